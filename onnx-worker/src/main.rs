@@ -1,11 +1,13 @@
 mod features;
 mod protocol;
 mod resample;
+mod runtime;
 mod server;
 mod smart_turn;
 mod vad;
 
 use anyhow::{anyhow, Result};
+use runtime::{ExecutionProvider, RuntimeConfig};
 use tokio::net::UnixListener;
 use tracing::{error, info};
 
@@ -42,7 +44,33 @@ async fn main() -> Result<()> {
     let socket_path = parse_arg(&args, "--socket")
         .ok_or_else(|| anyhow!("missing required argument: --socket"))?;
 
-    info!(vad_model = %vad_model, turn_model = %turn_model, "onnx-worker starting");
+    // Optional runtime tuning, passed by the Go supervisor from worker.Config
+    let intra_threads = match parse_arg(&args, "--intra-threads") {
+        Some(v) => v
+            .parse::<usize>()
+            .ok()
+            .filter(|n| *n > 0)
+            .ok_or_else(|| anyhow!("invalid --intra-threads value: {}", v))?,
+        None => 1,
+    };
+
+    let execution_provider = match parse_arg(&args, "--execution-provider") {
+        Some(v) => v.parse::<ExecutionProvider>()?,
+        None => ExecutionProvider::Cpu,
+    };
+
+    let runtime = RuntimeConfig {
+        intra_threads,
+        execution_provider,
+    };
+
+    info!(
+        vad_model = %vad_model,
+        turn_model = %turn_model,
+        intra_threads,
+        execution_provider = %execution_provider,
+        "onnx-worker starting"
+    );
 
     // Remove stale socket file so bind() doesn't fail
     let _ = std::fs::remove_file(&socket_path);
@@ -60,8 +88,13 @@ async fn main() -> Result<()> {
                 let vad_model_path = vad_model.clone();
                 let turn_model_path = turn_model.clone();
                 tokio::spawn(async move {
-                    if let Err(e) =
-                        server::handle_connection(stream, &vad_model_path, &turn_model_path).await
+                    if let Err(e) = server::handle_connection(
+                        stream,
+                        &vad_model_path,
+                        &turn_model_path,
+                        runtime,
+                    )
+                    .await
                     {
                         error!(error = %e, "handle_connection returned error");
                     }
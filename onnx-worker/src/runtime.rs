@@ -0,0 +1,105 @@
+use std::fmt;
+use std::str::FromStr;
+
+use anyhow::{anyhow, Result};
+use ort::{
+    execution_providers::{
+        CUDAExecutionProvider, CoreMLExecutionProvider, ExecutionProviderDispatch,
+    },
+    session::{builder::GraphOptimizationLevel, Session},
+};
+
+/// Hardware backend ONNX Runtime runs the models on.
+#[derive(Clone, Copy, Debug, PartialEq, Eq)]
+pub enum ExecutionProvider {
+    Cpu,
+    /// Needs a worker built with `--features cuda` and the CUDA libraries.
+    Cuda,
+    /// Enabled in macOS builds.
+    CoreML,
+}
+
+impl FromStr for ExecutionProvider {
+    type Err = anyhow::Error;
+
+    fn from_str(s: &str) -> Result<Self> {
+        match s {
+            "cpu" => Ok(Self::Cpu),
+            "cuda" => Ok(Self::Cuda),
+            "coreml" => Ok(Self::CoreML),
+            _ => Err(anyhow!("unknown execution provider: {}", s)),
+        }
+    }
+}
+
+impl fmt::Display for ExecutionProvider {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(match self {
+            Self::Cpu => "cpu",
+            Self::Cuda => "cuda",
+            Self::CoreML => "coreml",
+        })
+    }
+}
+
+/// ONNX Runtime settings shared by the VAD and Smart Turn sessions.
+#[derive(Clone, Copy, Debug)]
+pub struct RuntimeConfig {
+    pub intra_threads: usize,
+    pub execution_provider: ExecutionProvider,
+}
+
+impl Default for RuntimeConfig {
+    fn default() -> Self {
+        Self {
+            intra_threads: 1,
+            execution_provider: ExecutionProvider::Cpu,
+        }
+    }
+}
+
+impl RuntimeConfig {
+    /// Load the model at `model_path` into a session with these settings.
+    /// If the execution provider can't be registered (not compiled in, or no
+    /// device), ONNX Runtime logs a warning and the session runs on CPU.
+    pub fn session(&self, model_path: &str) -> Result<Session> {
+        let mut builder = Session::builder()
+            .map_err(|e| anyhow!("{}", e))?
+            .with_optimization_level(GraphOptimizationLevel::Level3)
+            .map_err(|e| anyhow!("{}", e))?
+            .with_intra_threads(self.intra_threads)
+            .map_err(|e| anyhow!("{}", e))?;
+
+        if let Some(provider) = self.dispatch() {
+            builder = builder
+                .with_execution_providers([provider])
+                .map_err(|e| anyhow!("{}", e))?;
+        }
+
+        builder
+            .commit_from_file(model_path)
+            .map_err(|e| anyhow!("{}", e))
+    }
+
+    fn dispatch(&self) -> Option<ExecutionProviderDispatch> {
+        match self.execution_provider {
+            ExecutionProvider::Cpu => None,
+            ExecutionProvider::Cuda => Some(CUDAExecutionProvider::default().build()),
+            ExecutionProvider::CoreML => Some(CoreMLExecutionProvider::default().build()),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn parses_supervisor_names() {
+        for name in ["cpu", "cuda", "coreml"] {
+            let provider: ExecutionProvider = name.parse().unwrap();
+            assert_eq!(provider.to_string(), name);
+        }
+        assert!("tpu".parse::<ExecutionProvider>().is_err());
+    }
+}
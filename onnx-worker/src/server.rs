@@ -5,6 +5,7 @@ use tokio::net::UnixStream;
 use tracing::{debug, error, info};
 
 use crate::protocol::{read_frame, write_response};
+use crate::runtime::RuntimeConfig;
 use crate::smart_turn::SmartTurnSession;
 use crate::vad::SileroSession;
 
@@ -80,16 +81,18 @@ fn parse_smart_turn_payload(payload: &[u8]) -> Result<(u32, u32, Vec<i16>)> {
 ///
 /// Each connection owns its own `SileroSession` so hidden state accumulates
 /// across VAD calls for the lifetime of the connection. A `SmartTurnSession`
-/// is also created per connection for turn-completion inference.
+/// is also created per connection for turn-completion inference. Both are
+/// built with the `runtime` settings.
 pub async fn handle_connection(
     mut stream: UnixStream,
     vad_model_path: &str,
     turn_model_path: &str,
+    runtime: RuntimeConfig,
 ) -> Result<()> {
     info!("client connected");
 
-    let mut vad_session = SileroSession::new(vad_model_path)?;
-    let mut smart_turn_session = SmartTurnSession::new(turn_model_path)?;
+    let mut vad_session = SileroSession::new(vad_model_path, &runtime)?;
+    let mut smart_turn_session = SmartTurnSession::new(turn_model_path, &runtime)?;
 
     loop {
         match read_frame(&mut stream).await {
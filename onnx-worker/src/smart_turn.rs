@@ -1,5 +1,7 @@
 use anyhow::Result;
-use ort::{inputs, session::Session, session::builder::GraphOptimizationLevel, value::TensorRef};
+use ort::{inputs, session::Session, value::TensorRef};
+
+use crate::runtime::RuntimeConfig;
 
 pub struct SmartTurnSession {
     session: Session,
@@ -10,15 +12,8 @@ pub struct SmartTurnSession {
 }
 
 impl SmartTurnSession {
-    pub fn new(model_path: &str) -> Result<Self> {
-        let session = Session::builder()
-            .map_err(|e| anyhow::anyhow!("{}", e))?
-            .with_optimization_level(GraphOptimizationLevel::Level3)
-            .map_err(|e| anyhow::anyhow!("{}", e))?
-            .with_intra_threads(1)
-            .map_err(|e| anyhow::anyhow!("{}", e))?
-            .commit_from_file(model_path)
-            .map_err(|e| anyhow::anyhow!("{}", e))?;
+    pub fn new(model_path: &str, runtime: &RuntimeConfig) -> Result<Self> {
+        let session = runtime.session(model_path)?;
 
         let feature_extractor = crate::features::WhisperFeatureExtractor::new();
 
@@ -1,9 +1,7 @@
 use anyhow::{anyhow, Result};
-use ort::{
-    inputs,
-    session::{builder::GraphOptimizationLevel, Session},
-    value::TensorRef,
-};
+use ort::{inputs, session::Session, value::TensorRef};
+
+use crate::runtime::RuntimeConfig;
 
 /// Per-connection Silero VAD session.
 /// Hidden state accumulates across calls for the lifetime of the connection.
@@ -18,17 +16,11 @@ pub struct SileroSession {
 }
 
 impl SileroSession {
-    /// Create a new session loading the model from `model_path`.
+    /// Create a new session loading the model from `model_path` with the
+    /// `runtime` settings.
     /// Hidden state is zeroed; context is empty (filled on first call).
-    pub fn new(model_path: &str) -> Result<Self> {
-        let session = Session::builder()
-            .map_err(|e| anyhow!("{}", e))?
-            .with_optimization_level(GraphOptimizationLevel::Level3)
-            .map_err(|e| anyhow!("{}", e))?
-            .with_intra_threads(1)
-            .map_err(|e| anyhow!("{}", e))?
-            .commit_from_file(model_path)
-            .map_err(|e| anyhow!("{}", e))?;
+    pub fn new(model_path: &str, runtime: &RuntimeConfig) -> Result<Self> {
+        let session = runtime.session(model_path)?;
 
         Ok(Self {
             session,
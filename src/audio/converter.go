@@ -18,14 +18,37 @@ type AudioConverterProcessor struct {
 	inputCodec       string
 	outputSampleRate int
 	outputCodec      string
+	highPass         *HighPassFilter
+	strictPCM        bool
+	pendingByte      []byte // trailing partial sample carried into the next linear16/float32 frame
+
+	passthrough       bool
+	passthroughActive bool // last flagged frame was forwarded unconverted (for logging transitions)
+	log               *logger.Logger
 }
 
 // AudioConverterConfig holds configuration for audio conversion
 type AudioConverterConfig struct {
 	InputSampleRate  int    // e.g., 8000, 16000, 24000
-	InputCodec       string // Supported: "mulaw"/"ulaw"/"PCMU", "alaw"/"PCMA", "linear16"/"pcm"
+	InputCodec       string // Supported: "mulaw"/"ulaw"/"PCMU", "alaw"/"PCMA", "linear16"/"pcm", "float32"/"pcm_f32le"
 	OutputSampleRate int    // e.g., 8000, 16000, 24000
-	OutputCodec      string // Supported: "mulaw"/"ulaw"/"PCMU", "alaw"/"PCMA", "linear16"/"pcm"
+	OutputCodec      string // Supported: "mulaw"/"ulaw"/"PCMU", "alaw"/"PCMA", "linear16"/"pcm", "float32"/"pcm_f32le"
+
+	// RemoveDC enables a single-pole high-pass stage on the decoded PCM to
+	// strip DC offset and low-frequency rumble (default cutoff: 80Hz)
+	RemoveDC   bool
+	HighPassHz float64 // High-pass cutoff in Hz; setting it also enables the filter
+
+	// StrictPCM makes linear16 or float32 input that ends mid-sample an
+	// error. By default the partial sample is buffered and prepended to the
+	// next frame.
+	StrictPCM bool
+
+	// Passthrough forwards frames flagged with the "passthrough" metadata
+	// (set by serializers that deliver native codec audio, e.g. Asterisk)
+	// unchanged when their codec and sample rate already match the output,
+	// avoiding a lossy decode/encode cycle. Ignored when RemoveDC is set.
+	Passthrough bool
 }
 
 // NewAudioConverterProcessor creates a new audio converter
@@ -35,19 +58,47 @@ func NewAudioConverterProcessor(config AudioConverterConfig) *AudioConverterProc
 		inputCodec:       config.InputCodec,
 		outputSampleRate: config.OutputSampleRate,
 		outputCodec:      config.OutputCodec,
+		strictPCM:        config.StrictPCM,
+		passthrough:      config.Passthrough,
+		log:              logger.WithPrefix("AudioConverter"),
+	}
+	if config.RemoveDC || config.HighPassHz > 0 {
+		cutoff := config.HighPassHz
+		if cutoff <= 0 {
+			cutoff = DefaultHighPassHz
+		}
+		ac.highPass = NewHighPassFilter(cutoff)
 	}
 	ac.BaseProcessor = processors.NewBaseProcessor("AudioConverter", ac)
 	return ac
 }
 
+// InputFormat implements processors.AudioFormatConsumer
+func (p *AudioConverterProcessor) InputFormat() processors.AudioFormat {
+	return processors.AudioFormat{SampleRate: p.inputSampleRate, Codec: NormalizeCodecName(p.inputCodec)}
+}
+
+// OutputFormat implements processors.AudioFormatProducer
+func (p *AudioConverterProcessor) OutputFormat() processors.AudioFormat {
+	return processors.AudioFormat{SampleRate: p.outputSampleRate, Codec: NormalizeCodecName(p.outputCodec)}
+}
+
 func (p *AudioConverterProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	// Convert audio frames
 	if audioFrame, ok := frame.(*frames.AudioFrame); ok {
+		if p.canPassthrough(audioFrame) {
+			return p.PushFrame(frame, direction)
+		}
+
 		convertedData, err := p.convertAudio(audioFrame.Data, audioFrame.SampleRate)
 		if err != nil {
-			logger.Error("Error converting audio: %v", err)
+			p.log.Error("Error converting audio: %v", err)
 			return p.PushFrame(frames.NewErrorFrame(err), frames.Upstream)
 		}
+		if len(convertedData) == 0 {
+			// Entire frame was buffered (partial sample)
+			return nil
+		}
 
 		// Create new frame with converted audio
 		newFrame := frames.NewAudioFrame(convertedData, p.outputSampleRate, audioFrame.Channels)
@@ -65,13 +116,50 @@ func (p *AudioConverterProcessor) HandleFrame(ctx context.Context, frame frames.
 	return p.PushFrame(frame, direction)
 }
 
+// canPassthrough reports whether a frame flagged as passthrough is already in
+// the output format and can skip conversion
+func (p *AudioConverterProcessor) canPassthrough(frame *frames.AudioFrame) bool {
+	if !p.passthrough || p.highPass != nil {
+		return false
+	}
+	if flagged, _ := frame.Metadata()["passthrough"].(bool); !flagged {
+		return false
+	}
+
+	codec, _ := frame.Metadata()["codec"].(string)
+	if codec == "" {
+		codec = p.inputCodec
+	}
+	match := NormalizeCodecName(codec) == NormalizeCodecName(p.outputCodec) &&
+		frame.SampleRate == p.outputSampleRate
+
+	if match != p.passthroughActive {
+		p.passthroughActive = match
+		if match {
+			p.log.Info("Passthrough active: %s %dHz matches output, skipping conversion", codec, frame.SampleRate)
+		} else {
+			p.log.Info("Passthrough inactive: %s %dHz differs from output %s %dHz, converting",
+				codec, frame.SampleRate, p.outputCodec, p.outputSampleRate)
+		}
+	}
+	return match
+}
+
+// Convert converts one buffer from the input format at inputRate to the
+// output format, outside of a pipeline. Like HandleFrame it keeps state
+// between calls (odd linear16 bytes, filter history), so use one converter
+// per stream.
+func (p *AudioConverterProcessor) Convert(data []byte, inputRate int) ([]byte, error) {
+	return p.convertAudio(data, inputRate)
+}
+
 func (p *AudioConverterProcessor) convertAudio(data []byte, inputRate int) ([]byte, error) {
 	// Step 1: Decode to PCM int16
 	var pcm []int16
 	var err error
 
 	// Normalize codec name
-	inputCodec := normalizeCodecName(p.inputCodec)
+	inputCodec := NormalizeCodecName(p.inputCodec)
 
 	switch inputCodec {
 	case "mulaw", "ulaw", "PCMU":
@@ -79,21 +167,37 @@ func (p *AudioConverterProcessor) convertAudio(data []byte, inputRate int) ([]by
 	case "alaw", "PCMA":
 		pcm = AlawToPCM(data)
 	case "linear16", "pcm":
+		if !p.strictPCM {
+			data = p.alignPCM(data, 2)
+		}
 		pcm, err = BytesToPCM(data)
 		if err != nil {
 			return nil, err
 		}
+	case "float32":
+		if !p.strictPCM {
+			data = p.alignPCM(data, 4)
+		}
+		pcm, err = Float32ToPCM16(data)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported input codec: %s", p.inputCodec)
 	}
 
-	// Step 2: Resample if needed
+	// Step 2: Remove DC offset / low-frequency rumble before resampling
+	if p.highPass != nil {
+		pcm = p.highPass.Process(pcm, inputRate)
+	}
+
+	// Step 3: Resample if needed
 	if inputRate != p.outputSampleRate {
 		pcm = Resample(pcm, inputRate, p.outputSampleRate)
 	}
 
-	// Step 3: Encode to output format
-	outputCodec := normalizeCodecName(p.outputCodec)
+	// Step 4: Encode to output format
+	outputCodec := NormalizeCodecName(p.outputCodec)
 
 	var output []byte
 	switch outputCodec {
@@ -103,6 +207,8 @@ func (p *AudioConverterProcessor) convertAudio(data []byte, inputRate int) ([]by
 		output = PCMToMulaw(pcm)
 	case "alaw", "PCMA":
 		output = PCMToAlaw(pcm)
+	case "float32":
+		output = PCM16ToFloat32(pcm)
 	default:
 		return nil, fmt.Errorf("unsupported output codec: %s", p.outputCodec)
 	}
@@ -110,8 +216,27 @@ func (p *AudioConverterProcessor) convertAudio(data []byte, inputRate int) ([]by
 	return output, nil
 }
 
-// normalizeCodecName converts codec name variations to a standard form
-func normalizeCodecName(codec string) string {
+// alignPCM prepends any bytes held from the previous frame and holds back a
+// trailing partial sample of width bytes, so samples split across frames are
+// reassembled in order.
+func (p *AudioConverterProcessor) alignPCM(data []byte, width int) []byte {
+	if len(p.pendingByte) > 0 {
+		joined := make([]byte, 0, len(p.pendingByte)+len(data))
+		joined = append(joined, p.pendingByte...)
+		data = append(joined, data...)
+		p.pendingByte = p.pendingByte[:0]
+	}
+	if partial := len(data) % width; partial != 0 {
+		p.pendingByte = append(p.pendingByte, data[len(data)-partial:]...)
+		data = data[:len(data)-partial]
+	}
+	return data
+}
+
+// NormalizeCodecName converts codec name variations to a standard form:
+// "mulaw", "alaw", "linear16" or "float32". Unknown names are returned
+// unchanged.
+func NormalizeCodecName(codec string) string {
 	// Convert to lowercase for comparison
 	switch codec {
 	case "mulaw", "ulaw", "PCMU":
@@ -120,11 +245,50 @@ func normalizeCodecName(codec string) string {
 		return "alaw"
 	case "linear16", "pcm", "PCM":
 		return "linear16"
+	case "float32", "pcm_f32le", "f32le":
+		return "float32"
 	default:
 		return codec
 	}
 }
 
+// frameCodec returns the normalized codec from audio frame metadata,
+// defaulting to linear16
+func frameCodec(meta map[string]interface{}) string {
+	if c, ok := meta["codec"].(string); ok {
+		return NormalizeCodecName(c)
+	}
+	return "linear16"
+}
+
+// decodePCM decodes mulaw, alaw, float32 or linear16 audio to PCM
+func decodePCM(data []byte, codec string) ([]int16, error) {
+	switch codec {
+	case "mulaw":
+		return MulawToPCM(data), nil
+	case "alaw":
+		return AlawToPCM(data), nil
+	case "float32":
+		return Float32ToPCM16(data)
+	default:
+		return BytesToPCM(data)
+	}
+}
+
+// encodePCM encodes PCM back to the codec it was decoded from
+func encodePCM(pcm []int16, codec string) []byte {
+	switch codec {
+	case "mulaw":
+		return PCMToMulaw(pcm)
+	case "alaw":
+		return PCMToAlaw(pcm)
+	case "float32":
+		return PCM16ToFloat32(pcm)
+	default:
+		return PCMToBytes(pcm)
+	}
+}
+
 // MulawToPCM converts mulaw audio to linear PCM int16
 func MulawToPCM(mulaw []byte) []int16 {
 	pcm := make([]int16, len(mulaw))
@@ -164,6 +328,34 @@ func PCMToBytes(pcm []int16) []byte {
 	return data
 }
 
+// Float32ToPCM16 converts 32-bit float PCM (little-endian, e.g. Cartesia's
+// pcm_f32le) to int16 PCM. Samples outside [-1, 1] are clipped.
+func Float32ToPCM16(data []byte) ([]int16, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid float32 PCM data length: %d", len(data))
+	}
+	pcm := make([]int16, len(data)/4)
+	for i := range pcm {
+		f := float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		if math.IsNaN(f) {
+			f = 0
+		}
+		f = math.Max(-1, math.Min(1, f))
+		pcm[i] = int16(math.Round(f * math.MaxInt16))
+	}
+	return pcm, nil
+}
+
+// PCM16ToFloat32 converts int16 PCM to 32-bit float PCM (little-endian) in
+// [-1, 1]
+func PCM16ToFloat32(pcm []int16) []byte {
+	data := make([]byte, len(pcm)*4)
+	for i, val := range pcm {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(float32(val)/math.MaxInt16))
+	}
+	return data
+}
+
 // Resample performs simple linear interpolation resampling
 // This is a basic implementation; for production, consider using a proper resampling library
 func Resample(input []int16, inputRate, outputRate int) []int16 {
@@ -310,10 +502,6 @@ func PCMToAlaw(pcm []int16) []byte {
 }
 
 // A-law encoding/decoding tables and functions
-const (
-	alawClip = 32767
-)
-
 var alawDecodeTable = [256]int16{
 	-5504, -5248, -6016, -5760, -4480, -4224, -4992, -4736,
 	-7552, -7296, -8064, -7808, -6528, -6272, -7040, -6784,
@@ -354,39 +542,48 @@ func alawDecode(alaw byte) int16 {
 }
 
 func alawEncode(pcm int16) byte {
-	// Get sign bit
-	sign := uint8(0)
-	if pcm < 0 {
-		sign = 0x80
-		pcm = -pcm
+	// Work on the 13-bit magnitude; the sign selects the XOR mask.
+	// Negative values use one's complement so -1 maps next to +0.
+	val := int32(pcm) >> 3
+	mask := uint8(0xD5)
+	if val < 0 {
+		mask = 0x55
+		val = -val - 1
 	}
 
-	// Clip the magnitude
-	if pcm > alawClip {
-		pcm = alawClip
-	}
-
-	// Find exponent and mantissa
+	// Find the segment (exponent) from the fixed segment end points
 	var exponent uint8
 	var mantissa uint8
 
-	if pcm >= 256 {
+	if val >= 0x800 {
 		exponent = 7
-		for pcm >= 256 && exponent > 0 {
-			pcm >>= 1
-			exponent--
-		}
-		mantissa = uint8((pcm >> 4) & 0x0F)
+		mantissa = uint8((val >> 7) & 0x0F)
+	} else if val >= 0x400 {
+		exponent = 6
+		mantissa = uint8((val >> 6) & 0x0F)
+	} else if val >= 0x200 {
+		exponent = 5
+		mantissa = uint8((val >> 5) & 0x0F)
+	} else if val >= 0x100 {
+		exponent = 4
+		mantissa = uint8((val >> 4) & 0x0F)
+	} else if val >= 0x80 {
+		exponent = 3
+		mantissa = uint8((val >> 3) & 0x0F)
+	} else if val >= 0x40 {
+		exponent = 2
+		mantissa = uint8((val >> 2) & 0x0F)
+	} else if val >= 0x20 {
+		exponent = 1
+		mantissa = uint8((val >> 1) & 0x0F)
 	} else {
+		// Segments 0 and 1 share the same step size
 		exponent = 0
-		mantissa = uint8((pcm >> 4) & 0x0F)
+		mantissa = uint8((val >> 1) & 0x0F)
 	}
 
-	// Combine sign, exponent, and mantissa
-	alaw := sign | (exponent << 4) | mantissa
-
-	// XOR with 0x55 for A-law
-	return alaw ^ 0x55
+	// Combine exponent and mantissa, then apply sign and even-bit inversion
+	return ((exponent << 4) | mantissa) ^ mask
 }
 
 // ClipAudio clips audio samples to prevent overflow
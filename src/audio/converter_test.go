@@ -0,0 +1,320 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func newPCMPassthroughConverter(strict bool) *AudioConverterProcessor {
+	return NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  16000,
+		InputCodec:       "linear16",
+		OutputSampleRate: 16000,
+		OutputCodec:      "linear16",
+		StrictPCM:        strict,
+	})
+}
+
+func TestAudioConverter_OddLengthFramesReassembled(t *testing.T) {
+	conv := newPCMPassthroughConverter(false)
+
+	samples := make([]int16, 100)
+	for i := range samples {
+		samples[i] = int16(i*300 - 15000)
+	}
+	data := PCMToBytes(samples)
+
+	// Split at odd offsets so every boundary falls mid-sample
+	splits := []int{1, 4, 7, 33, 64, 101, 150, 199, 200}
+	var output []int16
+	prev := 0
+	for _, end := range splits {
+		out, err := conv.convertAudio(data[prev:end], 16000)
+		if err != nil {
+			t.Fatalf("convertAudio(%d:%d) failed: %v", prev, end, err)
+		}
+		pcm, err := BytesToPCM(out)
+		if err != nil {
+			t.Fatalf("converter produced odd-length output for %d:%d: %v", prev, end, err)
+		}
+		output = append(output, pcm...)
+		prev = end
+	}
+
+	if len(output) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(output))
+	}
+	for i := range samples {
+		if output[i] != samples[i] {
+			t.Fatalf("Sample %d mismatch: got %d, want %d", i, output[i], samples[i])
+		}
+	}
+	if len(conv.pendingByte) != 0 {
+		t.Errorf("Expected no pending byte after even total, got %d", len(conv.pendingByte))
+	}
+}
+
+func TestAudioConverter_SingleByteFrameBuffered(t *testing.T) {
+	conv := newPCMPassthroughConverter(false)
+
+	out, err := conv.convertAudio([]byte{0x34}, 16000)
+	if err != nil {
+		t.Fatalf("convertAudio failed: %v", err)
+	}
+	if len(out) != 0 {
+		t.Fatalf("Expected single byte to be buffered, got %d bytes", len(out))
+	}
+
+	out, err = conv.convertAudio([]byte{0x12}, 16000)
+	if err != nil {
+		t.Fatalf("convertAudio failed: %v", err)
+	}
+	pcm, _ := BytesToPCM(out)
+	if len(pcm) != 1 || pcm[0] != 0x1234 {
+		t.Fatalf("Expected reassembled sample 0x1234, got %v", pcm)
+	}
+}
+
+func TestAudioConverter_StrictPCMRejectsOddLength(t *testing.T) {
+	conv := newPCMPassthroughConverter(true)
+
+	if _, err := conv.convertAudio([]byte{0x01, 0x02, 0x03}, 16000); err == nil {
+		t.Fatal("Expected error for odd-length input in strict mode")
+	}
+}
+
+// Reference G.711 A-law encodings
+var alawVectors = []struct {
+	pcm  int16
+	alaw byte
+}{
+	{0, 0xD5}, {8, 0xD5}, {15, 0xD5}, {16, 0xD4},
+	{-1, 0x55}, {-16, 0x55}, {-17, 0x54},
+	{100, 0xD3}, {-100, 0x53},
+	{255, 0xDA}, {256, 0xC5}, {-256, 0x5A},
+	{511, 0xCA}, {512, 0xF5},
+	{1000, 0xFA}, {-1000, 0x7A},
+	{2047, 0xEA}, {2048, 0x95},
+	{4095, 0x9A}, {4096, 0x85}, {-4096, 0x1A},
+	{8191, 0x8A}, {8192, 0xB5},
+	{12345, 0xBD}, {-12345, 0x3D},
+	{16384, 0xA5}, {-16384, 0x3A},
+	{32767, 0xAA}, {-32767, 0x2A}, {-32768, 0x2A},
+}
+
+func TestAlawEncode_ReferenceVectors(t *testing.T) {
+	for _, v := range alawVectors {
+		if got := alawEncode(v.pcm); got != v.alaw {
+			t.Errorf("alawEncode(%d) = %#x, want %#x", v.pcm, got, v.alaw)
+		}
+	}
+}
+
+func TestAlawEncode_InvertsDecodeTable(t *testing.T) {
+	for code := 0; code < 256; code++ {
+		if got := alawEncode(alawDecode(byte(code))); got != byte(code) {
+			t.Errorf("alawEncode(alawDecode(%#x)) = %#x", code, got)
+		}
+	}
+}
+
+func TestAlaw_RoundTripWithinQuantizationError(t *testing.T) {
+	pcm := make([]int16, 0, 65536)
+	for v := -32768; v <= 32767; v++ {
+		pcm = append(pcm, int16(v))
+	}
+	decoded := AlawToPCM(PCMToAlaw(pcm))
+
+	for i, v := range pcm {
+		// Step size doubles per segment above the first two
+		code := alawEncode(v) ^ 0x55
+		exponent := (code >> 4) & 0x07
+		step := 16
+		if exponent > 1 {
+			step = 8 << exponent
+		}
+
+		diff := int(decoded[i]) - int(v)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > step/2 {
+			t.Fatalf("sample %d decoded to %d, error %d exceeds half step %d", v, decoded[i], diff, step/2)
+		}
+	}
+}
+
+// nativeMulawFrame builds an 8kHz mulaw frame as the Asterisk serializer does
+func nativeMulawFrame(passthrough bool) *frames.AudioFrame {
+	frame := frames.NewAudioFrame(PCMToMulaw(sine(440, 8000, 0, 8000, 160)), 8000, 1)
+	frame.SetMetadata("codec", "ulaw")
+	if passthrough {
+		frame.SetMetadata("passthrough", true)
+	}
+	return frame
+}
+
+func TestAudioConverter_PassthroughSkipsMatchingCodec(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  8000,
+		InputCodec:       "mulaw",
+		OutputSampleRate: 8000,
+		OutputCodec:      "PCMU",
+		Passthrough:      true,
+	})
+	capture := &frameCapturer{}
+	conv.Link(capture)
+
+	in := nativeMulawFrame(true)
+	if err := conv.HandleFrame(context.Background(), in, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	if len(capture.frames) != 1 {
+		t.Fatalf("Expected 1 frame, got %d", len(capture.frames))
+	}
+	if capture.frames[0] != in {
+		t.Error("Expected the original frame to be forwarded unconverted")
+	}
+	if _, converted := in.Metadata()["original_codec"]; converted {
+		t.Error("Expected no conversion metadata on a passthrough frame")
+	}
+}
+
+func TestAudioConverter_PassthroughConvertsMismatch(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  8000,
+		InputCodec:       "mulaw",
+		OutputSampleRate: 16000,
+		OutputCodec:      "linear16",
+		Passthrough:      true,
+	})
+	capture := &frameCapturer{}
+	conv.Link(capture)
+
+	in := nativeMulawFrame(true)
+	if err := conv.HandleFrame(context.Background(), in, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	if len(capture.frames) != 1 {
+		t.Fatalf("Expected 1 frame, got %d", len(capture.frames))
+	}
+	out := capture.frames[0].(*frames.AudioFrame)
+	if out == in || out.SampleRate != 16000 || len(out.Data) != 2*len(in.Data)*2 {
+		t.Errorf("Expected 16kHz linear16 output, got %dHz with %d bytes", out.SampleRate, len(out.Data))
+	}
+	if out.Metadata()["codec"] != "linear16" {
+		t.Errorf("Expected codec metadata linear16, got %v", out.Metadata()["codec"])
+	}
+}
+
+func TestAudioConverter_PassthroughRequiresFlag(t *testing.T) {
+	config := AudioConverterConfig{
+		InputSampleRate:  8000,
+		InputCodec:       "mulaw",
+		OutputSampleRate: 8000,
+		OutputCodec:      "mulaw",
+	}
+
+	for _, tc := range []struct {
+		name        string
+		passthrough bool
+		flagged     bool
+	}{
+		{"option disabled", false, true},
+		{"frame not flagged", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Passthrough = tc.passthrough
+			conv := NewAudioConverterProcessor(config)
+			capture := &frameCapturer{}
+			conv.Link(capture)
+
+			in := nativeMulawFrame(tc.flagged)
+			if err := conv.HandleFrame(context.Background(), in, frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame failed: %v", err)
+			}
+			out := capture.frames[0].(*frames.AudioFrame)
+			if out == in {
+				t.Error("Expected the frame to go through conversion")
+			}
+			if !bytes.Equal(out.Data, PCMToMulaw(MulawToPCM(in.Data))) {
+				t.Error("Expected mulaw re-encoding of the input")
+			}
+		})
+	}
+}
+
+func TestFloat32_RoundTripWithinTolerance(t *testing.T) {
+	pcm := []int16{0, 1, -1, 1000, -1000, 16384, -16384, 32767, -32767, -32768}
+	back, err := Float32ToPCM16(PCM16ToFloat32(pcm))
+	if err != nil {
+		t.Fatalf("Float32ToPCM16 failed: %v", err)
+	}
+	for i, v := range pcm {
+		diff := int(back[i]) - int(v)
+		if diff < -1 || diff > 1 {
+			t.Errorf("sample %d round-tripped to %d", v, back[i])
+		}
+	}
+
+	// Out-of-range floats clip instead of wrapping
+	clipped, err := Float32ToPCM16(append(float32Bytes(1.5), float32Bytes(-2)...))
+	if err != nil {
+		t.Fatalf("Float32ToPCM16 failed: %v", err)
+	}
+	if clipped[0] != 32767 || clipped[1] != -32767 {
+		t.Errorf("Clipped samples = %v, want [32767 -32767]", clipped)
+	}
+
+	if _, err := Float32ToPCM16(make([]byte, 6)); err == nil {
+		t.Error("Expected an error for data that isn't whole float32 samples")
+	}
+}
+
+func TestAudioConverter_Float32ToMulaw(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  16000,
+		InputCodec:       "pcm_f32le",
+		OutputSampleRate: 8000,
+		OutputCodec:      "mulaw",
+	})
+
+	tone := sine(440, 8000, 0, 16000, 320)
+	src := PCM16ToFloat32(tone)
+
+	// Split mid-sample: the partial float is carried into the next frame
+	first, err := conv.Convert(src[:641], 16000)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	rest, err := conv.Convert(src[641:], 16000)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	got := append(first, rest...)
+
+	want := PCMToMulaw(Resample(tone, 16000, 8000))
+	if len(got) != len(want) {
+		t.Fatalf("Converted %d mulaw samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		diff := int(MulawToPCM(got[i : i+1])[0]) - int(MulawToPCM(want[i : i+1])[0])
+		if diff < -300 || diff > 300 {
+			t.Fatalf("Sample %d decodes %d away from the int16 conversion", i, diff)
+		}
+	}
+}
+
+// float32Bytes encodes one little-endian float32 sample
+func float32Bytes(f float32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(f))
+	return b
+}
@@ -0,0 +1,58 @@
+package audio
+
+import "time"
+
+// DefaultFadeDuration is long enough to avoid the click of a hard cut while
+// being inaudible as a fade
+const DefaultFadeDuration = 5 * time.Millisecond
+
+// FadeSamples returns how many samples d spans at sampleRate
+func FadeSamples(d time.Duration, sampleRate int) int {
+	if d <= 0 || sampleRate <= 0 {
+		return 0
+	}
+	return int(d * time.Duration(sampleRate) / time.Second)
+}
+
+// FadeIn ramps the first n samples of pcm linearly up from silence, in place
+func FadeIn(pcm []int16, n int) {
+	n = min(n, len(pcm))
+	for i := 0; i < n; i++ {
+		pcm[i] = int16(float64(pcm[i]) * float64(i) / float64(n))
+	}
+}
+
+// FadeOut ramps the last n samples of pcm linearly down to silence, in place
+func FadeOut(pcm []int16, n int) {
+	n = min(n, len(pcm))
+	start := len(pcm) - n
+	for i := 0; i < n; i++ {
+		pcm[start+i] = int16(float64(pcm[start+i]) * float64(n-1-i) / float64(n))
+	}
+}
+
+// FadeInAudio decodes mulaw, alaw, float32 or linear16 audio, fades its first
+// n samples in and re-encodes it in the same codec
+func FadeInAudio(data []byte, codec string, n int) ([]byte, error) {
+	codec = NormalizeCodecName(codec)
+	pcm, err := decodePCM(data, codec)
+	if err != nil {
+		return nil, err
+	}
+	FadeIn(pcm, n)
+	return encodePCM(pcm, codec), nil
+}
+
+// FadeOutAudio decodes audio like FadeInAudio, fades its first n samples out
+// and silences the rest, so it ends playback smoothly instead of with a cut
+func FadeOutAudio(data []byte, codec string, n int) ([]byte, error) {
+	codec = NormalizeCodecName(codec)
+	pcm, err := decodePCM(data, codec)
+	if err != nil {
+		return nil, err
+	}
+	n = min(n, len(pcm))
+	FadeOut(pcm[:n], n)
+	clear(pcm[n:])
+	return encodePCM(pcm, codec), nil
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func constantPCM(value int16, n int) []int16 {
+	pcm := make([]int16, n)
+	for i := range pcm {
+		pcm[i] = value
+	}
+	return pcm
+}
+
+func TestFadeSamples(t *testing.T) {
+	if n := FadeSamples(5*time.Millisecond, 8000); n != 40 {
+		t.Errorf("FadeSamples(5ms, 8kHz) = %d, want 40", n)
+	}
+	if n := FadeSamples(-time.Millisecond, 8000); n != 0 {
+		t.Errorf("FadeSamples(negative) = %d, want 0", n)
+	}
+}
+
+func TestFadeIn_RampsFromSilence(t *testing.T) {
+	pcm := constantPCM(10000, 100)
+	FadeIn(pcm, 40)
+
+	if pcm[0] != 0 {
+		t.Errorf("First sample = %d, want 0", pcm[0])
+	}
+	for i := 1; i < 40; i++ {
+		if pcm[i] <= pcm[i-1] {
+			t.Fatalf("Ramp not increasing at sample %d: %d after %d", i, pcm[i], pcm[i-1])
+		}
+	}
+	if pcm[20] != 5000 {
+		t.Errorf("Midpoint sample = %d, want 5000", pcm[20])
+	}
+	for i := 40; i < len(pcm); i++ {
+		if pcm[i] != 10000 {
+			t.Fatalf("Sample %d past the fade = %d, want it unchanged", i, pcm[i])
+		}
+	}
+}
+
+func TestFadeOut_RampsToSilence(t *testing.T) {
+	pcm := constantPCM(-10000, 100)
+	FadeOut(pcm, 40)
+
+	for i := 0; i < 60; i++ {
+		if pcm[i] != -10000 {
+			t.Fatalf("Sample %d before the fade = %d, want it unchanged", i, pcm[i])
+		}
+	}
+	for i := 61; i < len(pcm); i++ {
+		if pcm[i] <= pcm[i-1] {
+			t.Fatalf("Ramp not rising towards silence at sample %d: %d after %d", i, pcm[i], pcm[i-1])
+		}
+	}
+	if pcm[len(pcm)-1] != 0 {
+		t.Errorf("Last sample = %d, want 0", pcm[len(pcm)-1])
+	}
+}
+
+func TestFadeOutAudio_SilencesAfterRamp(t *testing.T) {
+	data := PCMToBytes(constantPCM(8000, 160))
+	faded, err := FadeOutAudio(data, "pcm", 40)
+	if err != nil {
+		t.Fatalf("FadeOutAudio failed: %v", err)
+	}
+	pcm, err := BytesToPCM(faded)
+	if err != nil {
+		t.Fatalf("BytesToPCM failed: %v", err)
+	}
+	if len(pcm) != 160 {
+		t.Fatalf("Faded chunk has %d samples, want 160", len(pcm))
+	}
+	if pcm[0] != 7800 {
+		t.Errorf("First sample = %d, want 7800", pcm[0])
+	}
+	for i := 39; i < len(pcm); i++ {
+		if pcm[i] != 0 {
+			t.Fatalf("Sample %d = %d, want silence after the fade", i, pcm[i])
+		}
+	}
+}
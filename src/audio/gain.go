@@ -0,0 +1,172 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	// DefaultDuckDB is the attenuation applied while ducked
+	DefaultDuckDB = -12.0
+	// DefaultGainRampTime is how long gain changes take, to avoid clicks
+	DefaultGainRampTime = 10 * time.Millisecond
+)
+
+// DBToGain converts a decibel value to a linear amplitude multiplier
+func DBToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// GainConfig holds configuration for the gain processor
+type GainConfig struct {
+	GainDB   float64       // Static gain applied to TTS audio (default: 0dB)
+	DuckDB   float64       // Attenuation while ducked by a DuckFrame (default: -12dB)
+	RampTime time.Duration // Transition time for gain changes (default: 10ms)
+}
+
+// GainProcessor applies a dB gain to TTSAudioFrames, with optional ducking
+// controlled by DuckFrames. Gain changes ramp over RampTime to avoid clicks.
+// Audio is decoded to PCM, scaled, clipped and re-encoded in its original codec.
+type GainProcessor struct {
+	*processors.BaseProcessor
+	gainDB   float64
+	duckDB   float64
+	rampTime time.Duration
+
+	// Gain state, shared by SetGainDB and the frame handler
+	mu       sync.Mutex
+	ducked   bool
+	current  float64 // Linear gain applied to the last sample
+	target   float64 // Linear gain being ramped towards
+	rampFrom float64 // Linear gain when the current ramp started
+
+	log *logger.Logger
+}
+
+// NewGainProcessor creates a new gain processor
+func NewGainProcessor(config GainConfig) *GainProcessor {
+	duckDB := config.DuckDB
+	if duckDB == 0 {
+		duckDB = DefaultDuckDB
+	}
+	rampTime := config.RampTime
+	if rampTime <= 0 {
+		rampTime = DefaultGainRampTime
+	}
+
+	gain := DBToGain(config.GainDB)
+	p := &GainProcessor{
+		gainDB:   config.GainDB,
+		duckDB:   duckDB,
+		rampTime: rampTime,
+		current:  gain,
+		target:   gain,
+		rampFrom: gain,
+		log:      logger.WithPrefix("Gain"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("Gain", p)
+	return p
+}
+
+// SetGainDB changes the static gain; the change is ramped
+func (p *GainProcessor) SetGainDB(db float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gainDB = db
+	p.updateTargetLocked()
+}
+
+func (p *GainProcessor) updateTargetLocked() {
+	db := p.gainDB
+	if p.ducked {
+		db += p.duckDB
+	}
+	p.rampFrom = p.current
+	p.target = DBToGain(db)
+}
+
+func (p *GainProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.DuckFrame:
+		p.mu.Lock()
+		p.ducked = f.Duck
+		if f.Duck && f.AttenuationDB != 0 {
+			p.duckDB = f.AttenuationDB
+		}
+		p.updateTargetLocked()
+		target := p.target
+		p.mu.Unlock()
+		p.log.Debug("Ducking %v (target gain %.1fdB)", f.Duck, 20*math.Log10(target))
+		return p.PushFrame(frame, direction)
+
+	case *frames.TTSAudioFrame:
+		p.mu.Lock()
+		data, ok := p.apply(f.Data, f.SampleRate, f.Metadata())
+		p.mu.Unlock()
+		if !ok {
+			return p.PushFrame(f, direction)
+		}
+
+		// Send the scaled audio in a new frame: the original may still be
+		// held upstream (e.g. by a recorder)
+		scaled := frames.NewTTSAudioFrame(data, f.SampleRate, f.Channels)
+		scaled.ContextID = f.ContextID
+		for k, v := range f.Metadata() {
+			scaled.SetMetadata(k, v)
+		}
+		return p.PushFrame(scaled, direction)
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// apply scales encoded audio by the current gain, ramping towards the target,
+// into a new slice. It reports false, leaving the audio as is, at unity gain
+// or when the audio can't be decoded. Called with mu held.
+func (p *GainProcessor) apply(data []byte, sampleRate int, meta map[string]interface{}) ([]byte, bool) {
+	if len(data) == 0 || (p.current == 1 && p.target == 1) {
+		return nil, false
+	}
+
+	codec := frameCodec(meta)
+	pcm, err := decodePCM(data, codec)
+	if err != nil {
+		p.log.Warn("Passing audio through unscaled: %v", err)
+		return nil, false
+	}
+
+	if sampleRate <= 0 {
+		p.current = p.target // Can't time a ramp without a sample rate
+	}
+	step := 0.0
+	if p.current != p.target {
+		rampSamples := p.rampTime.Seconds() * float64(sampleRate)
+		step = math.Abs(p.target-p.rampFrom) / math.Max(rampSamples, 1)
+	}
+
+	for i, val := range pcm {
+		if p.current < p.target {
+			p.current = math.Min(p.current+step, p.target)
+		} else if p.current > p.target {
+			p.current = math.Max(p.current-step, p.target)
+		}
+
+		// Clip symmetrically so boosted audio never wraps
+		scaled := float64(val) * p.current
+		if scaled > 32767 {
+			pcm[i] = 32767
+		} else if scaled < -32767 {
+			pcm[i] = -32767
+		} else {
+			pcm[i] = int16(math.Round(scaled))
+		}
+	}
+
+	return encodePCM(pcm, codec), true
+}
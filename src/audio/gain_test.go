@@ -0,0 +1,183 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// processTTS runs 20ms frames of pcm through the gain processor and returns
+// the output samples per frame
+func processTTS(t *testing.T, p *GainProcessor, pcm []int16) [][]int16 {
+	t.Helper()
+	capture := &frameCapturer{}
+	p.Link(capture)
+	var out [][]int16
+	for start := 0; start < len(pcm); start += 320 {
+		frame := frames.NewTTSAudioFrame(PCMToBytes(pcm[start:start+320]), 16000, 1)
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+		samples, err := BytesToPCM(lastTTSAudio(t, capture).Data)
+		if err != nil {
+			t.Fatalf("BytesToPCM failed: %v", err)
+		}
+		out = append(out, samples)
+	}
+	return out
+}
+
+// lastTTSAudio returns the audio frame the processor pushed last
+func lastTTSAudio(t *testing.T, capture *frameCapturer) *frames.TTSAudioFrame {
+	t.Helper()
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if len(capture.frames) == 0 {
+		t.Fatal("expected an audio frame to be pushed")
+	}
+	audioFrame, ok := capture.frames[len(capture.frames)-1].(*frames.TTSAudioFrame)
+	if !ok {
+		t.Fatalf("expected a TTSAudioFrame, got %T", capture.frames[len(capture.frames)-1])
+	}
+	return audioFrame
+}
+
+func TestGainProcessor_ScalesRMS(t *testing.T) {
+	input := sine(440, 4000, 0, 16000, 3200)
+	inputRMS := rms(input[len(input)-320:])
+
+	for _, db := range []float64{6, -6, -20} {
+		p := NewGainProcessor(GainConfig{GainDB: db})
+		out := processTTS(t, p, input)
+
+		want := inputRMS * DBToGain(db)
+		got := rms(out[len(out)-1])
+		if math.Abs(got-want)/want > 0.02 {
+			t.Errorf("%+.0fdB: expected RMS %.1f, got %.1f", db, want, got)
+		}
+	}
+}
+
+func TestGainProcessor_ClipsInsteadOfWrapping(t *testing.T) {
+	p := NewGainProcessor(GainConfig{GainDB: 12})
+	out := processTTS(t, p, sine(440, 20000, 0, 16000, 320))
+
+	for i, v := range out[0] {
+		if v == math.MinInt16 {
+			t.Fatalf("sample %d not clipped symmetrically: %d", i, v)
+		}
+	}
+	if peak := calculatePeak(PCMToBytes(out[0])); peak < 0.99 {
+		t.Errorf("expected boosted signal to hit the clip level, peak %.2f", peak)
+	}
+}
+
+func TestGainProcessor_DuckFrameWindow(t *testing.T) {
+	p := NewGainProcessor(GainConfig{DuckDB: -12})
+	input := sine(400, 8000, 0, 16000, 1600) // 100ms per burst, whole cycles per frame
+	baseRMS := rms(input[len(input)-320:])
+	ctx := context.Background()
+
+	before := processTTS(t, p, input)
+	if got := rms(before[len(before)-1]); math.Abs(got-baseRMS) > 1 {
+		t.Fatalf("expected unity gain before ducking, RMS %.1f vs %.1f", got, baseRMS)
+	}
+
+	if err := p.HandleFrame(ctx, frames.NewDuckFrame(true, 0), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(DuckFrame) failed: %v", err)
+	}
+	during := processTTS(t, p, input)
+
+	// The ramp starts from the previous gain rather than jumping
+	if first, last := during[0][0], input[0]; math.Abs(float64(first)-float64(last)) > 1 {
+		t.Errorf("expected ramp to start at previous gain, first sample %d vs %d", first, last)
+	}
+	want := baseRMS * DBToGain(-12)
+	if got := rms(during[len(during)-1]); math.Abs(got-want)/want > 0.02 {
+		t.Errorf("expected ducked RMS %.1f, got %.1f", want, got)
+	}
+
+	if err := p.HandleFrame(ctx, frames.NewDuckFrame(false, 0), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(DuckFrame) failed: %v", err)
+	}
+	after := processTTS(t, p, input)
+	if got := rms(after[len(after)-1]); math.Abs(got-baseRMS) > 1 {
+		t.Errorf("expected gain restored after ducking, RMS %.1f vs %.1f", got, baseRMS)
+	}
+}
+
+func TestGainProcessor_RampAvoidsSteps(t *testing.T) {
+	p := NewGainProcessor(GainConfig{})
+	ctx := context.Background()
+
+	// A DC signal makes the gain envelope directly visible
+	dc := make([]int16, 640)
+	for i := range dc {
+		dc[i] = 10000
+	}
+	if err := p.HandleFrame(ctx, frames.NewDuckFrame(true, -20), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(DuckFrame) failed: %v", err)
+	}
+	out := processTTS(t, p, dc)
+	samples := append(out[0], out[1]...)
+
+	// 10ms ramp at 16kHz spreads the 9000-step change over ~160 samples
+	for i := 1; i < len(samples); i++ {
+		if diff := math.Abs(float64(samples[i]) - float64(samples[i-1])); diff > 100 {
+			t.Fatalf("gain step of %.0f at sample %d", diff, i)
+		}
+	}
+	if last := samples[len(samples)-1]; last != 1000 {
+		t.Errorf("expected ramp to settle at -20dB (1000), got %d", last)
+	}
+}
+
+func TestGainProcessor_PreservesMulaw(t *testing.T) {
+	p := NewGainProcessor(GainConfig{GainDB: -6})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	input := PCMToMulaw(sine(440, 8000, 0, 8000, 160))
+	original := frames.NewTTSAudioFrame(append([]byte(nil), input...), 8000, 1)
+	original.SetMetadata("codec", "mulaw")
+	if err := p.HandleFrame(context.Background(), original, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+	if !bytes.Equal(original.Data, input) {
+		t.Error("expected the input frame to be left untouched")
+	}
+
+	frame := lastTTSAudio(t, capture)
+	if frame.Metadata()["codec"] != "mulaw" {
+		t.Errorf("expected the codec metadata to be kept, got %v", frame.Metadata()["codec"])
+	}
+
+	if len(frame.Data) != len(input) {
+		t.Fatalf("expected mulaw output of %d bytes, got %d", len(input), len(frame.Data))
+	}
+	gain := DBToGain(-6)
+	for i, b := range input {
+		want := mulawEncode(int16(math.Round(float64(mulawDecode(b)) * gain)))
+		if frame.Data[i] != want {
+			t.Fatalf("byte %d: expected %#x, got %#x", i, want, frame.Data[i])
+		}
+	}
+}
+
+func TestGainProcessor_SetGainDBWhileProcessing(t *testing.T) {
+	p := NewGainProcessor(GainConfig{})
+	input := sine(440, 4000, 0, 16000, 320*50)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			p.SetGainDB(float64(-i % 12))
+		}
+	}()
+	processTTS(t, p, input)
+	<-done
+}
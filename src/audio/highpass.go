@@ -0,0 +1,60 @@
+package audio
+
+import "math"
+
+// DefaultHighPassHz is the cutoff used when DC removal is enabled without an
+// explicit frequency. 80Hz clears DC offset and line rumble while leaving the
+// voice band (~100Hz+) intact.
+const DefaultHighPassHz = 80.0
+
+// HighPassFilter is a single-pole IIR high-pass filter for int16 PCM.
+// It keeps state across calls so consecutive frames are filtered as one
+// continuous stream.
+type HighPassFilter struct {
+	cutoffHz   float64
+	sampleRate int
+	alpha      float64
+	prevIn     float64
+	prevOut    float64
+}
+
+// NewHighPassFilter creates a high-pass filter with the given cutoff frequency
+func NewHighPassFilter(cutoffHz float64) *HighPassFilter {
+	return &HighPassFilter{cutoffHz: cutoffHz}
+}
+
+// Process filters pcm in place and returns it.
+// The filter coefficient is recomputed if the sample rate changes.
+func (f *HighPassFilter) Process(pcm []int16, sampleRate int) []int16 {
+	if sampleRate <= 0 || f.cutoffHz <= 0 {
+		return pcm
+	}
+	if sampleRate != f.sampleRate {
+		rc := 1.0 / (2 * math.Pi * f.cutoffHz)
+		dt := 1.0 / float64(sampleRate)
+		f.alpha = rc / (rc + dt)
+		f.sampleRate = sampleRate
+	}
+
+	for i, val := range pcm {
+		x := float64(val)
+		y := f.alpha * (f.prevOut + x - f.prevIn)
+		f.prevIn = x
+		f.prevOut = y
+
+		if y > 32767 {
+			pcm[i] = 32767
+		} else if y < -32768 {
+			pcm[i] = -32768
+		} else {
+			pcm[i] = int16(math.Round(y))
+		}
+	}
+	return pcm
+}
+
+// Reset clears the filter history
+func (f *HighPassFilter) Reset() {
+	f.prevIn = 0
+	f.prevOut = 0
+}
@@ -0,0 +1,124 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func sine(freq float64, amplitude float64, offset float64, sampleRate, n int) []int16 {
+	pcm := make([]int16, n)
+	for i := range pcm {
+		pcm[i] = int16(offset + amplitude*math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+	}
+	return pcm
+}
+
+func mean(pcm []int16) float64 {
+	var sum float64
+	for _, v := range pcm {
+		sum += float64(v)
+	}
+	return sum / float64(len(pcm))
+}
+
+func rms(pcm []int16) float64 {
+	var sum float64
+	for _, v := range pcm {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(pcm)))
+}
+
+func TestAudioConverter_RemoveDC(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  8000,
+		InputCodec:       "linear16",
+		OutputSampleRate: 8000,
+		OutputCodec:      "linear16",
+		RemoveDC:         true,
+	})
+
+	// 1s of a 440Hz tone riding on a 4000 DC offset, fed in 20ms frames
+	input := sine(440, 3000, 4000, 8000, 8000)
+	var output []int16
+	for start := 0; start < len(input); start += 160 {
+		out, err := conv.convertAudio(PCMToBytes(input[start:start+160]), 8000)
+		if err != nil {
+			t.Fatalf("convertAudio failed: %v", err)
+		}
+		pcm, err := BytesToPCM(out)
+		if err != nil {
+			t.Fatalf("BytesToPCM failed: %v", err)
+		}
+		output = append(output, pcm...)
+	}
+
+	if m := mean(input); m < 3900 {
+		t.Fatalf("Expected input mean near 4000, got %.1f", m)
+	}
+
+	// Skip the filter's settling time
+	settled := output[len(output)/2:]
+	if m := mean(settled); math.Abs(m) > 50 {
+		t.Errorf("Expected output mean near 0, got %.1f", m)
+	}
+
+	// Voice-band tone should pass mostly intact
+	if r := rms(settled); r < 0.9*3000/math.Sqrt2 {
+		t.Errorf("Expected 440Hz tone to be preserved, got RMS %.1f", r)
+	}
+}
+
+func TestAudioConverter_HighPassAttenuatesLowFrequency(t *testing.T) {
+	const sampleRate = 16000
+	rumble := sine(20, 8000, 0, sampleRate, sampleRate)
+	voice := sine(1000, 8000, 0, sampleRate, sampleRate)
+
+	rumbleOut := NewHighPassFilter(DefaultHighPassHz).Process(append([]int16(nil), rumble...), sampleRate)
+	voiceOut := NewHighPassFilter(DefaultHighPassHz).Process(append([]int16(nil), voice...), sampleRate)
+
+	rumbleGain := rms(rumbleOut[sampleRate/2:]) / rms(rumble[sampleRate/2:])
+	voiceGain := rms(voiceOut[sampleRate/2:]) / rms(voice[sampleRate/2:])
+
+	if rumbleGain > 0.4 {
+		t.Errorf("Expected 20Hz rumble to be attenuated, gain %.2f", rumbleGain)
+	}
+	if voiceGain < 0.95 {
+		t.Errorf("Expected 1kHz tone to pass, gain %.2f", voiceGain)
+	}
+}
+
+func TestAudioConverter_HighPassDisabledByDefault(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputSampleRate:  8000,
+		InputCodec:       "linear16",
+		OutputSampleRate: 8000,
+		OutputCodec:      "linear16",
+	})
+	if conv.highPass != nil {
+		t.Fatal("Expected high-pass filter to be disabled by default")
+	}
+
+	input := sine(440, 3000, 4000, 8000, 160)
+	out, err := conv.convertAudio(PCMToBytes(input), 8000)
+	if err != nil {
+		t.Fatalf("convertAudio failed: %v", err)
+	}
+	pcm, _ := BytesToPCM(out)
+	for i := range input {
+		if pcm[i] != input[i] {
+			t.Fatalf("Expected unmodified sample at %d: %d != %d", i, pcm[i], input[i])
+		}
+	}
+}
+
+func TestAudioConverter_HighPassHzEnablesFilter(t *testing.T) {
+	conv := NewAudioConverterProcessor(AudioConverterConfig{
+		InputCodec:  "linear16",
+		OutputCodec: "linear16",
+		HighPassHz:  120,
+	})
+	if conv.highPass == nil || conv.highPass.cutoffHz != 120 {
+		t.Fatalf("Expected high-pass filter at 120Hz, got %+v", conv.highPass)
+	}
+}
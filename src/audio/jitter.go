@@ -0,0 +1,418 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	// SequenceMetadataKey is the AudioFrame metadata key holding the
+	// transport's packet sequence number, used to reorder inbound audio
+	SequenceMetadataKey = "sequence"
+
+	// DefaultJitterPtime is the release cadence used until the first frame
+	// reveals the codec's packet time
+	DefaultJitterPtime = 20 * time.Millisecond
+	// DefaultJitterTargetDepth is the number of frames held before playout starts
+	DefaultJitterTargetDepth = 3
+	// DefaultJitterMaxDepth caps the buffer; older frames are dropped beyond it
+	DefaultJitterMaxDepth = 10
+
+	// jitterGain is the RFC 3550 interarrival jitter smoothing factor
+	jitterGain = 1.0 / 16
+)
+
+// JitterBufferConfig holds configuration for the jitter buffer
+type JitterBufferConfig struct {
+	Ptime       time.Duration // Release cadence (default: duration of the first frame)
+	TargetDepth int           // Initial frames held before playout (default: 3)
+	MinDepth    int           // Lower bound for the adaptive depth (default: 1)
+	MaxDepth    int           // Upper bound; oldest frames are dropped beyond it (default: 10)
+	Conceal     bool          // Emit silence for missing frames instead of skipping them
+}
+
+// JitterBufferStats is a snapshot of the jitter buffer's state
+type JitterBufferStats struct {
+	Depth         int           // Frames currently buffered
+	TargetDepth   int           // Current adaptive target depth
+	Jitter        time.Duration // Smoothed interarrival jitter
+	Released      int           // Frames released downstream
+	Concealed     int           // Silence frames emitted for missing audio
+	LateDrops     int           // Frames dropped for arriving after their slot
+	OverflowDrops int           // Frames dropped because the buffer was full
+	Underruns     int           // Ticks where the buffer ran dry
+}
+
+// jitterEntry is a buffered frame and its sequence number, if it has one
+type jitterEntry struct {
+	frame  *frames.AudioFrame
+	seq    int64
+	hasSeq bool
+}
+
+// JitterBufferProcessor smooths bursty inbound audio before VAD/STT.
+// Downstream AudioFrames are buffered, reordered by sequence number (when the
+// transport provides one) and released one per ptime. Playout starts once the
+// buffer reaches its target depth, which adapts to the observed interarrival
+// jitter. Missing frames are concealed with silence or skipped; on underrun
+// the buffer refills to the target depth before playout resumes.
+type JitterBufferProcessor struct {
+	*processors.BaseProcessor
+	mu sync.Mutex
+
+	ptime    time.Duration
+	minDepth int
+	maxDepth int
+	conceal  bool
+
+	buffer      []jitterEntry // Sorted by sequence; unsequenced frames keep arrival order
+	targetDepth int
+	playing     bool // False while filling to the target depth
+	nextSeq     int64
+	haveNextSeq bool
+	lastArrival time.Time
+	jitter      float64            // Seconds
+	last        *frames.AudioFrame // Template for concealment frames
+	stats       JitterBufferStats
+
+	stop chan struct{}
+	log  *logger.Logger
+}
+
+// NewJitterBufferProcessor creates a new jitter buffer
+func NewJitterBufferProcessor(config JitterBufferConfig) *JitterBufferProcessor {
+	minDepth := config.MinDepth
+	if minDepth <= 0 {
+		minDepth = 1
+	}
+	maxDepth := config.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultJitterMaxDepth
+	}
+	if maxDepth < minDepth {
+		maxDepth = minDepth
+	}
+	targetDepth := config.TargetDepth
+	if targetDepth <= 0 {
+		targetDepth = DefaultJitterTargetDepth
+	}
+	targetDepth = clampDepth(targetDepth, minDepth, maxDepth)
+
+	p := &JitterBufferProcessor{
+		ptime:       config.Ptime,
+		minDepth:    minDepth,
+		maxDepth:    maxDepth,
+		conceal:     config.Conceal,
+		targetDepth: targetDepth,
+		log:         logger.WithPrefix("JitterBuffer"),
+	}
+	if p.ptime > 0 {
+		p.seedJitter()
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("JitterBuffer", p)
+	return p
+}
+
+// Stats returns a snapshot of the buffer's counters
+func (p *JitterBufferProcessor) Stats() JitterBufferStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Depth = len(p.buffer)
+	stats.TargetDepth = p.targetDepth
+	stats.Jitter = time.Duration(p.jitter * float64(time.Second))
+	return stats
+}
+
+func (p *JitterBufferProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.StartFrame:
+		if direction == frames.Downstream {
+			p.startPlayout(ctx)
+		}
+
+	case *frames.AudioFrame:
+		if direction == frames.Downstream {
+			p.enqueue(f, time.Now())
+			return nil
+		}
+
+	case *frames.EndFrame:
+		// Release whatever is buffered so no trailing audio is lost
+		p.stopPlayout()
+		for _, buffered := range p.drain() {
+			if err := p.PushFrame(buffered, frames.Downstream); err != nil {
+				return err
+			}
+		}
+
+	case *frames.CancelFrame:
+		p.stopPlayout()
+		p.drain()
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// enqueue inserts a frame in sequence order, dropping late and duplicate
+// frames and trimming the oldest frames if the buffer overflows
+func (p *JitterBufferProcessor) enqueue(frame *frames.AudioFrame, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ptime <= 0 {
+		if p.ptime = frameDuration(frame); p.ptime <= 0 {
+			p.ptime = DefaultJitterPtime
+		}
+		p.seedJitter()
+	}
+	p.updateJitter(now)
+
+	entry := jitterEntry{frame: frame}
+	entry.seq, entry.hasSeq = frameSequence(frame.Metadata())
+
+	if entry.hasSeq {
+		if p.haveNextSeq && entry.seq < p.nextSeq {
+			p.stats.LateDrops++
+			return
+		}
+		i := sort.Search(len(p.buffer), func(i int) bool {
+			return p.buffer[i].hasSeq && p.buffer[i].seq >= entry.seq
+		})
+		if i < len(p.buffer) && p.buffer[i].seq == entry.seq {
+			return // Duplicate
+		}
+		p.buffer = append(p.buffer, jitterEntry{})
+		copy(p.buffer[i+1:], p.buffer[i:])
+		p.buffer[i] = entry
+	} else {
+		p.buffer = append(p.buffer, entry)
+	}
+
+	if overflow := len(p.buffer) - p.maxDepth; overflow > 0 {
+		p.stats.OverflowDrops += overflow
+		p.buffer = append(p.buffer[:0], p.buffer[overflow:]...)
+		if p.buffer[0].hasSeq {
+			p.nextSeq = p.buffer[0].seq
+		}
+	}
+}
+
+// seedJitter starts the jitter estimate at the value that yields the
+// configured target depth, so adaptation starts from the configured depth
+func (p *JitterBufferProcessor) seedJitter() {
+	p.jitter = float64(p.targetDepth-1) * p.ptime.Seconds() / 2
+}
+
+// updateJitter folds the deviation of this frame's arrival from the ideal
+// cadence into the smoothed jitter, and adapts the target depth to cover
+// twice the jitter
+func (p *JitterBufferProcessor) updateJitter(now time.Time) {
+	if !p.lastArrival.IsZero() {
+		deviation := math.Abs(now.Sub(p.lastArrival).Seconds() - p.ptime.Seconds())
+		p.jitter += (deviation - p.jitter) * jitterGain
+		depth := 1 + int(math.Ceil(2*p.jitter/p.ptime.Seconds()))
+		p.targetDepth = clampDepth(depth, p.minDepth, p.maxDepth)
+	}
+	p.lastArrival = now
+}
+
+// tick releases one frame: the next in sequence, a concealment frame if it
+// is missing, or nothing while the buffer is filling
+func (p *JitterBufferProcessor) tick() {
+	p.mu.Lock()
+	out := p.next()
+	p.mu.Unlock()
+
+	if out != nil {
+		if err := p.PushFrame(out, frames.Downstream); err != nil {
+			p.log.Warn("Error releasing audio: %v", err)
+		}
+	}
+}
+
+func (p *JitterBufferProcessor) next() *frames.AudioFrame {
+	if !p.playing {
+		if len(p.buffer) == 0 || len(p.buffer) < p.targetDepth {
+			return nil
+		}
+		p.playing = true
+	}
+
+	if len(p.buffer) == 0 {
+		p.stats.Underruns++
+		p.playing = false
+		p.log.Debug("Underrun, refilling to %d frames", p.targetDepth)
+		return p.concealment()
+	}
+
+	head := p.buffer[0]
+	if head.hasSeq && p.haveNextSeq && head.seq > p.nextSeq {
+		if head.seq-p.nextSeq > int64(p.maxDepth) {
+			// Too far apart to be loss (e.g., sequence reset): resync
+			p.nextSeq = head.seq
+		} else if p.conceal {
+			// The missing frame's slot has come; fill it
+			p.nextSeq++
+			return p.concealment()
+		}
+	}
+
+	p.buffer = append(p.buffer[:0], p.buffer[1:]...)
+	if head.hasSeq {
+		p.nextSeq = head.seq + 1
+		p.haveNextSeq = true
+	}
+	p.last = head.frame
+	p.stats.Released++
+	return head.frame
+}
+
+// concealment builds a silence frame shaped like the last released frame
+func (p *JitterBufferProcessor) concealment() *frames.AudioFrame {
+	if !p.conceal || p.last == nil {
+		return nil
+	}
+
+	codec := "linear16"
+	if c, ok := p.last.Metadata()["codec"].(string); ok {
+		codec = NormalizeCodecName(c)
+	}
+	silence := make([]byte, len(p.last.Data))
+	switch codec {
+	case "mulaw":
+		for i := range silence {
+			silence[i] = 0xFF
+		}
+	case "alaw":
+		for i := range silence {
+			silence[i] = 0xD5
+		}
+	}
+
+	frame := frames.NewAudioFrame(silence, p.last.SampleRate, p.last.Channels)
+	frame.SetMetadata("codec", codec)
+	frame.SetMetadata("concealed", true)
+	p.stats.Concealed++
+	return frame
+}
+
+// drain empties the buffer, returning the frames in order
+func (p *JitterBufferProcessor) drain() []*frames.AudioFrame {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]*frames.AudioFrame, len(p.buffer))
+	for i, entry := range p.buffer {
+		out[i] = entry.frame
+	}
+	p.stats.Released += len(out)
+	p.buffer = nil
+	p.playing = false
+	return out
+}
+
+func (p *JitterBufferProcessor) startPlayout(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	go p.playout(ctx, p.stop)
+}
+
+func (p *JitterBufferProcessor) stopPlayout() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// playout ticks once per ptime against an absolute schedule so the release
+// cadence does not drift with processing time
+func (p *JitterBufferProcessor) playout(ctx context.Context, stop chan struct{}) {
+	next := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for {
+		next = next.Add(p.cadence())
+		timer.Reset(time.Until(next))
+		select {
+		case <-timer.C:
+			p.tick()
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *JitterBufferProcessor) cadence() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ptime <= 0 {
+		return DefaultJitterPtime
+	}
+	return p.ptime
+}
+
+// frameDuration returns the playback duration of an audio frame
+func frameDuration(frame *frames.AudioFrame) time.Duration {
+	if frame.SampleRate <= 0 {
+		return 0
+	}
+	bytesPerSample := 2
+	if c, ok := frame.Metadata()["codec"].(string); ok {
+		if codec := NormalizeCodecName(c); codec == "mulaw" || codec == "alaw" {
+			bytesPerSample = 1
+		}
+	}
+	channels := frame.Channels
+	if channels <= 0 {
+		channels = 1
+	}
+	samples := len(frame.Data) / (bytesPerSample * channels)
+	return time.Duration(samples) * time.Second / time.Duration(frame.SampleRate)
+}
+
+// frameSequence reads the sequence number from frame metadata
+func frameSequence(meta map[string]interface{}) (int64, bool) {
+	switch v := meta[SequenceMetadataKey].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint32:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	case string:
+		seq, err := strconv.ParseInt(v, 10, 64)
+		return seq, err == nil
+	}
+	return 0, false
+}
+
+func clampDepth(depth, minDepth, maxDepth int) int {
+	if depth < minDepth {
+		return minDepth
+	}
+	if depth > maxDepth {
+		return maxDepth
+	}
+	return depth
+}
@@ -0,0 +1,214 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// seqFrame builds a 20ms 8kHz mulaw frame tagged with a sequence number,
+// with the sequence also written into the payload for identification
+func seqFrame(seq int) *frames.AudioFrame {
+	data := make([]byte, 160)
+	data[0] = byte(seq)
+	frame := frames.NewAudioFrame(data, 8000, 1)
+	frame.SetMetadata("codec", "mulaw")
+	frame.SetMetadata(SequenceMetadataKey, seq)
+	return frame
+}
+
+func newTestJitterBuffer(config JitterBufferConfig) (*JitterBufferProcessor, *frameCapturer) {
+	p := NewJitterBufferProcessor(config)
+	capturer := &frameCapturer{}
+	p.Link(capturer)
+	return p, capturer
+}
+
+// released returns the payload IDs of released audio, -1 for concealment
+func released(c *frameCapturer) []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []int
+	for _, f := range c.frames {
+		audio, ok := f.(*frames.AudioFrame)
+		if !ok {
+			continue
+		}
+		if concealed, _ := audio.Metadata()["concealed"].(bool); concealed {
+			ids = append(ids, -1)
+		} else {
+			ids = append(ids, int(audio.Data[0]))
+		}
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, got, want []int) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestJitterBuffer_ReordersBySequence(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 3, MinDepth: 3})
+	start := time.Now()
+
+	for i, seq := range []int{1, 0, 2, 4, 3, 5} {
+		p.enqueue(seqFrame(seq), start.Add(time.Duration(i)*20*time.Millisecond))
+	}
+	for i := 0; i < 6; i++ {
+		p.tick()
+	}
+
+	assertIDs(t, released(capturer), []int{0, 1, 2, 3, 4, 5})
+}
+
+func TestJitterBuffer_WaitsForTargetDepth(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 3, MinDepth: 3})
+	start := time.Now()
+
+	p.enqueue(seqFrame(0), start)
+	p.enqueue(seqFrame(1), start.Add(20*time.Millisecond))
+	p.tick()
+	if ids := released(capturer); len(ids) != 0 {
+		t.Fatalf("Expected nothing released below target depth, got %v", ids)
+	}
+
+	p.enqueue(seqFrame(2), start.Add(40*time.Millisecond))
+	p.tick()
+	assertIDs(t, released(capturer), []int{0})
+}
+
+func TestJitterBuffer_DropsLateFrames(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 2, MinDepth: 2, MaxDepth: 2})
+	start := time.Now()
+
+	p.enqueue(seqFrame(0), start)
+	p.enqueue(seqFrame(2), start.Add(20*time.Millisecond))
+	p.tick() // 0
+	p.tick() // 1 missing, no concealment: 2 is released in its place
+
+	p.enqueue(seqFrame(1), start.Add(60*time.Millisecond))
+	p.enqueue(seqFrame(2), start.Add(60*time.Millisecond))
+	assertIDs(t, released(capturer), []int{0, 2})
+	if stats := p.Stats(); stats.LateDrops != 2 || stats.Depth != 0 {
+		t.Errorf("Expected 2 late drops and an empty buffer, got %+v", stats)
+	}
+}
+
+func TestJitterBuffer_ConcealsMissingFrames(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 2, MinDepth: 2, MaxDepth: 2, Conceal: true})
+	start := time.Now()
+
+	for i, seq := range []int{0, 1, 3, 4} {
+		p.enqueue(seqFrame(seq), start.Add(time.Duration(i)*20*time.Millisecond))
+		p.tick()
+	}
+	p.tick()
+
+	assertIDs(t, released(capturer), []int{0, 1, -1, 3})
+
+	capturer.mu.Lock()
+	concealed := capturer.frames[2].(*frames.AudioFrame)
+	capturer.mu.Unlock()
+	if len(concealed.Data) != 160 || concealed.Data[0] != 0xFF {
+		t.Errorf("Expected 160 bytes of mulaw silence, got %d bytes starting %#x", len(concealed.Data), concealed.Data[0])
+	}
+}
+
+func TestJitterBuffer_UnderrunRefills(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 2, MinDepth: 2, MaxDepth: 2})
+	start := time.Now()
+
+	p.enqueue(seqFrame(0), start)
+	p.enqueue(seqFrame(1), start.Add(20*time.Millisecond))
+	p.tick()
+	p.tick()
+	p.tick() // Underrun
+
+	p.enqueue(seqFrame(2), start.Add(80*time.Millisecond))
+	p.tick() // Refilling: one frame is below target
+	p.enqueue(seqFrame(3), start.Add(100*time.Millisecond))
+	p.tick()
+
+	assertIDs(t, released(capturer), []int{0, 1, 2})
+	if stats := p.Stats(); stats.Underruns != 1 {
+		t.Errorf("Expected 1 underrun, got %+v", stats)
+	}
+}
+
+func TestJitterBuffer_AdaptsDepthToJitter(t *testing.T) {
+	p, _ := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 2})
+	start := time.Now()
+
+	// Frames arriving on time keep the depth low
+	for i := 0; i < 100; i++ {
+		p.enqueue(seqFrame(i%256), start.Add(time.Duration(i)*20*time.Millisecond))
+		p.drain()
+	}
+	steady := p.Stats().TargetDepth
+
+	// Bursts of four every 80ms push it up
+	at := start.Add(2 * time.Second)
+	for i := 0; i < 100; i++ {
+		if i%4 == 0 {
+			at = at.Add(80 * time.Millisecond)
+		}
+		p.enqueue(seqFrame(i%256), at)
+		p.drain()
+	}
+	bursty := p.Stats()
+
+	if steady > 2 {
+		t.Errorf("Expected low depth for steady arrivals, got %d", steady)
+	}
+	if bursty.TargetDepth <= steady || bursty.Jitter < 20*time.Millisecond {
+		t.Errorf("Expected depth to grow with jitter, steady %d, bursty %+v", steady, bursty)
+	}
+}
+
+func TestJitterBuffer_SteadyReleaseOfBurstyInput(t *testing.T) {
+	p, capturer := newTestJitterBuffer(JitterBufferConfig{TargetDepth: 3, MinDepth: 3, MaxDepth: 20})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := p.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) failed: %v", err)
+	}
+
+	// Two bursts of five out-of-order frames, 100ms apart
+	for _, burst := range [][]int{{1, 0, 3, 2, 4}, {6, 5, 8, 9, 7}} {
+		for _, seq := range burst {
+			if err := p.HandleFrame(ctx, seqFrame(seq), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame failed: %v", err)
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(released(capturer)) < 10 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := p.HandleFrame(ctx, frames.NewEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(EndFrame) failed: %v", err)
+	}
+
+	assertIDs(t, released(capturer), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	capturer.mu.Lock()
+	defer capturer.mu.Unlock()
+	for i := 1; i < 10; i++ {
+		if gap := capturer.times[i].Sub(capturer.times[i-1]); gap < 10*time.Millisecond {
+			t.Errorf("Frames %d and %d released %v apart, expected ~20ms cadence", i-1, i, gap)
+		}
+	}
+}
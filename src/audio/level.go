@@ -0,0 +1,192 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	// DefaultAudioLevelRate is how many AudioLevelFrames are emitted per second of audio
+	DefaultAudioLevelRate = 10.0
+	// DefaultAudioLevelSmoothing weights the previous RMS reading against the new one
+	DefaultAudioLevelSmoothing = 0.5
+)
+
+// CalculateVolume computes RMS volume from a little-endian int16 buffer,
+// normalized to [0, 1]
+func CalculateVolume(buffer []byte) float32 {
+	if len(buffer) < 2 {
+		return 0.0
+	}
+
+	// Convert bytes to int16 samples
+	numSamples := len(buffer) / 2
+	var sumSquares float64
+
+	for i := 0; i < numSamples; i++ {
+		// Read little-endian int16
+		sample := int16(buffer[i*2]) | int16(buffer[i*2+1])<<8
+		// Normalize to [-1.0, 1.0]
+		normalized := float64(sample) / 32768.0
+		sumSquares += normalized * normalized
+	}
+
+	// RMS (Root Mean Square)
+	rms := math.Sqrt(sumSquares / float64(numSamples))
+	return float32(rms)
+}
+
+// CodecVolume is CalculateVolume for audio in codec ("linear16", "mulaw",
+// "alaw" or "float32")
+func CodecVolume(data []byte, codec string) (float32, error) {
+	pcm, err := decodePCM(data, NormalizeCodecName(codec))
+	if err != nil {
+		return 0, err
+	}
+	return CalculateVolume(PCMToBytes(pcm)), nil
+}
+
+// calculatePeak returns the largest absolute sample, normalized to [0, 1]
+func calculatePeak(buffer []byte) float32 {
+	var peak int32
+	for i := 0; i+1 < len(buffer); i += 2 {
+		sample := int32(int16(buffer[i]) | int16(buffer[i+1])<<8)
+		if sample < 0 {
+			sample = -sample
+		}
+		if sample > peak {
+			peak = sample
+		}
+	}
+	return float32(peak) / 32768.0
+}
+
+// linearPCM returns a frame's audio as little-endian int16 PCM, decoding
+// G.711 audio tagged with a "codec" in its metadata
+func linearPCM(data []byte, meta map[string]interface{}) []byte {
+	if codec, ok := meta["codec"].(string); ok {
+		switch NormalizeCodecName(codec) {
+		case "mulaw":
+			return PCMToBytes(MulawToPCM(data))
+		case "alaw":
+			return PCMToBytes(AlawToPCM(data))
+		}
+	}
+	return data
+}
+
+// AudioLevelConfig holds configuration for the audio level meter
+type AudioLevelConfig struct {
+	Rate          float64 // Level updates per second of audio (default: 10)
+	Smoothing     float64 // 0-1, weight of the previous RMS reading (default: 0.5)
+	IncludeOutput bool    // Also meter outbound TTS audio (Source "bot")
+}
+
+// levelMeter accumulates one audio source over the current reporting window
+type levelMeter struct {
+	source     string
+	sumSquares float64
+	samples    int
+	peak       float32
+	elapsed    time.Duration
+	smoothed   float32
+}
+
+// AudioLevelProcessor emits AudioLevelFrames with smoothed RMS and peak
+// levels for VU meters. Audio frames are passed through untouched.
+type AudioLevelProcessor struct {
+	*processors.BaseProcessor
+	interval      time.Duration
+	smoothing     float32
+	includeOutput bool
+	input         *levelMeter
+	output        *levelMeter
+	log           *logger.Logger
+}
+
+// NewAudioLevelProcessor creates a new audio level processor
+func NewAudioLevelProcessor(config AudioLevelConfig) *AudioLevelProcessor {
+	rate := config.Rate
+	if rate <= 0 {
+		rate = DefaultAudioLevelRate
+	}
+	smoothing := config.Smoothing
+	if smoothing <= 0 || smoothing >= 1 {
+		smoothing = DefaultAudioLevelSmoothing
+	}
+
+	p := &AudioLevelProcessor{
+		interval:      time.Duration(float64(time.Second) / rate),
+		smoothing:     float32(smoothing),
+		includeOutput: config.IncludeOutput,
+		input:         &levelMeter{source: "user"},
+		output:        &levelMeter{source: "bot"},
+		log:           logger.WithPrefix("AudioLevel"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("AudioLevel", p)
+	return p
+}
+
+func (p *AudioLevelProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	var level *frames.AudioLevelFrame
+
+	switch f := frame.(type) {
+	case *frames.AudioFrame:
+		level = p.measure(p.input, f.Data, f.SampleRate, f.Channels, f.Metadata())
+	case *frames.TTSAudioFrame:
+		if p.includeOutput {
+			level = p.measure(p.output, f.Data, f.SampleRate, f.Channels, f.Metadata())
+		}
+	}
+
+	if err := p.PushFrame(frame, direction); err != nil {
+		return err
+	}
+	if level != nil {
+		return p.PushFrame(level, frames.Downstream)
+	}
+	return nil
+}
+
+// measure adds a frame to the meter and returns a level frame once a full
+// reporting interval of audio has been seen
+func (p *AudioLevelProcessor) measure(m *levelMeter, data []byte, sampleRate, channels int, meta map[string]interface{}) *frames.AudioLevelFrame {
+	if len(data) == 0 || sampleRate <= 0 {
+		return nil
+	}
+	if channels <= 0 {
+		channels = 1
+	}
+
+	pcm := linearPCM(data, meta)
+	n := len(pcm) / 2
+	if n == 0 {
+		return nil
+	}
+	rms := float64(CalculateVolume(pcm))
+	m.sumSquares += rms * rms * float64(n)
+	m.samples += n
+	if peak := calculatePeak(pcm); peak > m.peak {
+		m.peak = peak
+	}
+	m.elapsed += time.Duration(n/channels) * time.Second / time.Duration(sampleRate)
+
+	if m.elapsed < p.interval {
+		return nil
+	}
+
+	windowRMS := float32(math.Sqrt(m.sumSquares / float64(m.samples)))
+	m.smoothed = p.smoothing*m.smoothed + (1-p.smoothing)*windowRMS
+	level := frames.NewAudioLevelFrame(m.smoothed, m.peak, m.source)
+
+	m.sumSquares = 0
+	m.samples = 0
+	m.peak = 0
+	m.elapsed -= p.interval
+	return level
+}
@@ -0,0 +1,173 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// frameCapturer records frames queued to it by the processor under test
+type frameCapturer struct {
+	mu     sync.Mutex
+	frames []frames.Frame
+	times  []time.Time
+}
+
+func (c *frameCapturer) QueueFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frames = append(c.frames, frame)
+	c.times = append(c.times, time.Now())
+	return nil
+}
+
+func (c *frameCapturer) ProcessFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	return nil
+}
+
+func (c *frameCapturer) PushFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	return nil
+}
+
+func (c *frameCapturer) Link(next processors.FrameProcessor)    {}
+func (c *frameCapturer) SetPrev(prev processors.FrameProcessor) {}
+func (c *frameCapturer) Start(ctx context.Context) error        { return nil }
+func (c *frameCapturer) Stop() error                            { return nil }
+func (c *frameCapturer) Name() string                           { return "TestCapturer" }
+
+func (c *frameCapturer) levels() []*frames.AudioLevelFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []*frames.AudioLevelFrame
+	for _, f := range c.frames {
+		if level, ok := f.(*frames.AudioLevelFrame); ok {
+			result = append(result, level)
+		}
+	}
+	return result
+}
+
+// feedAudio sends 1s of 16kHz audio in 20ms frames and returns the frames sent
+func feedAudio(t *testing.T, p *AudioLevelProcessor, pcm []int16, tts bool) []frames.Frame {
+	t.Helper()
+	var sent []frames.Frame
+	for start := 0; start < len(pcm); start += 320 {
+		data := PCMToBytes(pcm[start : start+320])
+		var frame frames.Frame
+		if tts {
+			frame = frames.NewTTSAudioFrame(data, 16000, 1)
+		} else {
+			frame = frames.NewAudioFrame(data, 16000, 1)
+		}
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+		sent = append(sent, frame)
+	}
+	return sent
+}
+
+func TestAudioLevelProcessor_Cadence(t *testing.T) {
+	p := NewAudioLevelProcessor(AudioLevelConfig{Rate: 10})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	feedAudio(t, p, sine(440, 8000, 0, 16000, 16000), false)
+
+	if n := len(capture.levels()); n != 10 {
+		t.Errorf("Expected 10 level frames for 1s of audio at 10Hz, got %d", n)
+	}
+
+	p = NewAudioLevelProcessor(AudioLevelConfig{Rate: 25})
+	capture = &frameCapturer{}
+	p.Link(capture)
+
+	feedAudio(t, p, sine(440, 8000, 0, 16000, 16000), false)
+
+	if n := len(capture.levels()); n != 25 {
+		t.Errorf("Expected 25 level frames for 1s of audio at 25Hz, got %d", n)
+	}
+}
+
+func TestAudioLevelProcessor_SilenceVsTone(t *testing.T) {
+	p := NewAudioLevelProcessor(AudioLevelConfig{})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	feedAudio(t, p, make([]int16, 16000), false)
+	for _, level := range capture.levels() {
+		if level.RMS != 0 || level.Peak != 0 {
+			t.Fatalf("Expected zero level for silence, got RMS=%.3f Peak=%.3f", level.RMS, level.Peak)
+		}
+		if level.Source != "user" {
+			t.Errorf("Expected source user, got %s", level.Source)
+		}
+	}
+
+	capture.frames = nil
+	feedAudio(t, p, sine(440, 16000, 0, 16000, 16000), false)
+	levels := capture.levels()
+	last := levels[len(levels)-1]
+
+	// A 16000-amplitude sine peaks near 0.49 with RMS near 0.35
+	if last.Peak < 0.45 || last.Peak > 0.5 {
+		t.Errorf("Expected peak near 0.49, got %.3f", last.Peak)
+	}
+	if last.RMS < 0.3 || last.RMS > 0.37 {
+		t.Errorf("Expected smoothed RMS near 0.35, got %.3f", last.RMS)
+	}
+	if levels[0].RMS >= last.RMS {
+		t.Errorf("Expected RMS to rise smoothly from silence, first %.3f last %.3f", levels[0].RMS, last.RMS)
+	}
+}
+
+func TestAudioLevelProcessor_PassesAudioUnchanged(t *testing.T) {
+	p := NewAudioLevelProcessor(AudioLevelConfig{IncludeOutput: true})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	input := sine(440, 8000, 0, 16000, 3200)
+	sent := feedAudio(t, p, input, true)
+
+	var passed []frames.Frame
+	for _, f := range capture.frames {
+		if _, ok := f.(*frames.AudioLevelFrame); !ok {
+			passed = append(passed, f)
+		}
+	}
+	if len(passed) != len(sent) {
+		t.Fatalf("Expected %d audio frames passed through, got %d", len(sent), len(passed))
+	}
+	for i := range sent {
+		if passed[i] != sent[i] {
+			t.Fatalf("Audio frame %d was replaced", i)
+		}
+	}
+	pcm, _ := BytesToPCM(sent[0].(*frames.TTSAudioFrame).Data)
+	for i := range pcm {
+		if pcm[i] != input[i] {
+			t.Fatalf("Audio sample %d modified: %d != %d", i, pcm[i], input[i])
+		}
+	}
+
+	levels := capture.levels()
+	if len(levels) != 2 || levels[0].Source != "bot" {
+		t.Errorf("Expected 2 bot level frames, got %d", len(levels))
+	}
+}
+
+func TestAudioLevelProcessor_IgnoresOutputByDefault(t *testing.T) {
+	p := NewAudioLevelProcessor(AudioLevelConfig{})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	feedAudio(t, p, sine(440, 8000, 0, 16000, 16000), true)
+
+	if n := len(capture.levels()); n != 0 {
+		t.Errorf("Expected no level frames for TTS audio by default, got %d", n)
+	}
+}
@@ -0,0 +1,261 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	// DefaultLimiterThresholdDB is the output ceiling in dBFS
+	DefaultLimiterThresholdDB = -1.0
+	// DefaultLimiterKneeDB is the width of the soft knee below the ceiling
+	DefaultLimiterKneeDB = 6.0
+	// DefaultLimiterLookahead is how far ahead peaks are detected; it is
+	// also the latency the limiter adds
+	DefaultLimiterLookahead = 5 * time.Millisecond
+	// DefaultLimiterRelease is the time constant for gain recovery after a peak
+	DefaultLimiterRelease = 50 * time.Millisecond
+)
+
+// LimiterConfig holds configuration for the limiter processor
+type LimiterConfig struct {
+	ThresholdDB float64       // Output ceiling in dBFS (default: -1dB)
+	KneeDB      float64       // Soft knee width; gain reduction eases in this far below the ceiling (default: 6dB)
+	Lookahead   time.Duration // Peak detection window and added latency (default: 5ms)
+	Release     time.Duration // Gain recovery time constant (default: 50ms)
+}
+
+// LimiterProcessor is a soft-knee lookahead limiter for TTSAudioFrames.
+// Loud TTS, especially after a GainProcessor, is held under ThresholdDB
+// without the harmonics of hard clipping: audio is delayed by Lookahead so
+// the gain can ease down before a peak arrives, then recovers over Release.
+// Audio is decoded to PCM and re-encoded in its original codec.
+//
+// Place it after any gain stage and before the transport output:
+//
+//	pipeline.NewPipeline([]processors.FrameProcessor{
+//	    ...,
+//	    tts,
+//	    audio.NewGainProcessor(audio.GainConfig{GainDB: 6}),
+//	    audio.NewLimiterProcessor(audio.LimiterConfig{}),
+//	    transport.Output(),
+//	})
+type LimiterProcessor struct {
+	*processors.BaseProcessor
+	thresholdDB float64
+	kneeDB      float64
+	lookahead   time.Duration
+	release     time.Duration
+
+	// Per-stream state, rebuilt when the sample rate changes
+	sampleRate  int
+	releaseCoef float64
+	delay       []float64 // Input samples awaiting output
+	required    []float64 // Gain each delayed sample needs
+	smooth      []float64 // Released gains averaged into the applied gain
+	smoothSum   float64
+	envelope    float64 // Released gain
+	pos         int
+	pending     int // Samples in the delay line not yet output
+	lastMeta    map[string]interface{}
+	lastChans   int
+
+	log *logger.Logger
+}
+
+// NewLimiterProcessor creates a new limiter processor
+func NewLimiterProcessor(config LimiterConfig) *LimiterProcessor {
+	thresholdDB := config.ThresholdDB
+	if thresholdDB == 0 {
+		thresholdDB = DefaultLimiterThresholdDB
+	}
+	kneeDB := config.KneeDB
+	if kneeDB <= 0 {
+		kneeDB = DefaultLimiterKneeDB
+	}
+	lookahead := config.Lookahead
+	if lookahead <= 0 {
+		lookahead = DefaultLimiterLookahead
+	}
+	release := config.Release
+	if release <= 0 {
+		release = DefaultLimiterRelease
+	}
+
+	p := &LimiterProcessor{
+		thresholdDB: thresholdDB,
+		kneeDB:      kneeDB,
+		lookahead:   lookahead,
+		release:     release,
+		log:         logger.WithPrefix("Limiter"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("Limiter", p)
+	return p
+}
+
+func (p *LimiterProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.TTSAudioFrame:
+		f.Data = p.apply(f.Data, f.SampleRate, f.Metadata())
+		p.lastChans = f.Channels
+		return p.PushFrame(f, direction)
+
+	case *frames.TTSStoppedFrame:
+		// Release the delayed tail so it isn't prepended to the next response
+		if tail := p.flush(); tail != nil {
+			if err := p.PushFrame(tail, direction); err != nil {
+				return err
+			}
+		}
+		return p.PushFrame(frame, direction)
+
+	case *frames.InterruptionFrame:
+		// The delayed audio belongs to the interrupted response
+		p.reset(p.sampleRate)
+		return p.PushFrame(frame, direction)
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// reset rebuilds the delay line and gain state for sampleRate
+func (p *LimiterProcessor) reset(sampleRate int) {
+	p.sampleRate = sampleRate
+	if sampleRate <= 0 {
+		p.delay = nil
+		return
+	}
+
+	n := int(p.lookahead.Seconds() * float64(sampleRate))
+	if n < 1 {
+		n = 1
+	}
+	p.delay = make([]float64, n)
+	p.required = make([]float64, n)
+	p.smooth = make([]float64, n)
+	for i := range p.required {
+		p.required[i] = 1
+		p.smooth[i] = 1
+	}
+	p.smoothSum = float64(n)
+	p.envelope = 1
+	p.pos = 0
+	p.pending = 0
+	p.releaseCoef = math.Exp(-1 / (p.release.Seconds() * float64(sampleRate)))
+}
+
+// apply limits encoded audio. Output is delayed by the lookahead, so each
+// frame returns the same number of samples it was given.
+func (p *LimiterProcessor) apply(data []byte, sampleRate int, meta map[string]interface{}) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	codec := frameCodec(meta)
+	pcm, err := decodePCM(data, codec)
+	if err != nil {
+		p.log.Warn("Passing audio through unlimited: %v", err)
+		return data
+	}
+
+	if sampleRate != p.sampleRate || p.delay == nil {
+		p.reset(sampleRate)
+	}
+	if p.delay == nil {
+		return data // Can't time the lookahead without a sample rate
+	}
+	p.lastMeta = meta
+
+	for i, val := range pcm {
+		pcm[i] = p.process(float64(val))
+	}
+	return encodePCM(pcm, codec)
+}
+
+// flush drains the delay line into a frame, or returns nil if it is empty
+func (p *LimiterProcessor) flush() *frames.TTSAudioFrame {
+	if p.pending == 0 || p.delay == nil {
+		return nil
+	}
+
+	// Pushing a full delay line of silence through outputs the pending
+	// samples last
+	pending := p.pending
+	drained := make([]int16, len(p.delay)-1)
+	for i := range drained {
+		drained[i] = p.process(0)
+	}
+	tail := drained[len(drained)-pending:]
+	p.pending = 0
+
+	codec := frameCodec(p.lastMeta)
+	frame := frames.NewTTSAudioFrame(encodePCM(tail, codec), p.sampleRate, p.lastChans)
+	for key, value := range p.lastMeta {
+		frame.SetMetadata(key, value)
+	}
+	return frame
+}
+
+// process feeds one sample into the delay line and returns the limited
+// sample from one lookahead ago.
+//
+// Each delayed sample's required gain is min-held over the lookahead window,
+// released, then averaged over the same window. Every value in that average
+// already accounts for the sample leaving the delay line, so the applied gain
+// never exceeds what that sample needs while still changing smoothly.
+func (p *LimiterProcessor) process(x float64) int16 {
+	n := len(p.delay)
+	p.delay[p.pos] = x
+	p.required[p.pos] = p.gainFor(math.Abs(x))
+
+	held := 1.0
+	for _, g := range p.required {
+		held = math.Min(held, g)
+	}
+	if held < p.envelope {
+		p.envelope = held
+	} else {
+		p.envelope = held + (p.envelope-held)*p.releaseCoef
+	}
+
+	p.smoothSum += p.envelope - p.smooth[p.pos]
+	p.smooth[p.pos] = p.envelope
+	gain := p.smoothSum / float64(n)
+
+	p.pos = (p.pos + 1) % n
+	if p.pending < n-1 {
+		p.pending++
+	}
+
+	// The oldest sample in the ring is the one leaving the delay line
+	ceiling := 32767 * DBToGain(p.thresholdDB)
+	y := math.Max(-ceiling, math.Min(ceiling, p.delay[p.pos]*gain))
+	return int16(math.Round(y))
+}
+
+// gainFor returns the linear gain that keeps a sample of magnitude level
+// under the ceiling, easing in over the knee below it
+func (p *LimiterProcessor) gainFor(level float64) float64 {
+	if level <= 0 {
+		return 1
+	}
+	inDB := 20 * math.Log10(level/32767)
+	kneeStart := p.thresholdDB - p.kneeDB/2
+
+	var outDB float64
+	switch {
+	case inDB <= kneeStart:
+		return 1
+	case inDB < p.thresholdDB+p.kneeDB/2:
+		over := inDB - kneeStart
+		outDB = inDB - over*over/(2*p.kneeDB)
+	default:
+		outDB = p.thresholdDB
+	}
+	return DBToGain(outDB - inDB)
+}
@@ -0,0 +1,136 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// limit runs 20ms frames of pcm through the limiter and returns the output
+func limit(t *testing.T, p *LimiterProcessor, pcm []int16) []int16 {
+	t.Helper()
+	var out []int16
+	for start := 0; start < len(pcm); start += 320 {
+		frame := frames.NewTTSAudioFrame(PCMToBytes(pcm[start:start+320]), 16000, 1)
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+		samples, err := BytesToPCM(frame.Data)
+		if err != nil {
+			t.Fatalf("BytesToPCM failed: %v", err)
+		}
+		out = append(out, samples...)
+	}
+	return out
+}
+
+// harmonicRatio returns the fraction of spectral energy outside the
+// fundamental's bins, a rough measure of distortion
+func harmonicRatio(pcm []int16, freq float64, sampleRate int) float64 {
+	power := powerSpectrum(pcm)
+	binHz := float64(sampleRate) / float64(2*(len(power)-1))
+	fundamental := int(math.Round(freq / binHz))
+
+	var total, outside float64
+	for i, p := range power {
+		total += p
+		if i < fundamental-3 || i > fundamental+3 {
+			outside += p
+		}
+	}
+	return outside / total
+}
+
+func TestLimiter_HoldsPeaksUnderThreshold(t *testing.T) {
+	p := NewLimiterProcessor(LimiterConfig{ThresholdDB: -12})
+	ceiling := 32767 * DBToGain(-12)
+
+	// ~11dB over the ceiling
+	out := limit(t, p, sine(500, 30000, 0, 16000, 16000))
+	for i, v := range out {
+		if math.Abs(float64(v)) > math.Ceil(ceiling) {
+			t.Fatalf("sample %d = %d exceeds ceiling %.0f", i, v, ceiling)
+		}
+	}
+
+	// Loudness is kept close to the ceiling rather than over-attenuated
+	if peak := math.Abs(float64(peakSample(out[len(out)-1600:]))); peak < ceiling*0.9 {
+		t.Errorf("expected steady-state peak near ceiling %.0f, got %.0f", ceiling, peak)
+	}
+}
+
+func TestLimiter_LessDistortionThanHardClipping(t *testing.T) {
+	p := NewLimiterProcessor(LimiterConfig{ThresholdDB: -12})
+	ceiling := int16(32767 * DBToGain(-12))
+	input := sine(500, 30000, 0, 16000, 16000)
+
+	limited := limit(t, p, input)
+	clipped := ClipAudio(input, ceiling)
+
+	// Compare a steady-state window once the limiter has settled
+	window := 1024
+	limitedRatio := harmonicRatio(limited[len(limited)-window:], 500, 16000)
+	clippedRatio := harmonicRatio(clipped[len(clipped)-window:], 500, 16000)
+	if limitedRatio > clippedRatio/10 {
+		t.Errorf("expected limiter distortion well below hard clipping, got %.4f vs %.4f", limitedRatio, clippedRatio)
+	}
+}
+
+func TestLimiter_PassesQuietAudio(t *testing.T) {
+	p := NewLimiterProcessor(LimiterConfig{})
+	input := sine(500, 8000, 0, 16000, 3200)
+	out := limit(t, p, input)
+
+	// Output is the input delayed by the lookahead
+	delay := int(DefaultLimiterLookahead.Seconds()*16000) - 1
+	for i := delay; i < len(out); i++ {
+		if out[i] != input[i-delay] {
+			t.Fatalf("sample %d: expected %d, got %d", i, input[i-delay], out[i])
+		}
+	}
+}
+
+func TestLimiter_FlushesTailOnTTSStopped(t *testing.T) {
+	p := NewLimiterProcessor(LimiterConfig{})
+	capture := &frameCapturer{}
+	p.Link(capture)
+	input := sine(500, 8000, 0, 16000, 320)
+	out := limit(t, p, input)
+
+	if err := p.HandleFrame(context.Background(), frames.NewTTSStoppedFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSStoppedFrame) failed: %v", err)
+	}
+
+	// The tail is queued before the TTSStoppedFrame
+	var tail []int16
+	capture.mu.Lock()
+	for _, f := range capture.frames {
+		if audioFrame, ok := f.(*frames.TTSAudioFrame); ok && len(audioFrame.Data) < 640 {
+			tail, _ = BytesToPCM(audioFrame.Data)
+		}
+	}
+	capture.mu.Unlock()
+	all := append(out, tail...)
+	if len(all) < len(input) {
+		t.Fatalf("expected %d samples after flushing, got %d", len(input), len(all))
+	}
+	delay := len(all) - len(input)
+	for i, v := range input {
+		if all[i+delay] != v {
+			t.Fatalf("sample %d: expected %d, got %d", i, v, all[i+delay])
+		}
+	}
+}
+
+// peakSample returns the sample with the largest magnitude
+func peakSample(pcm []int16) int16 {
+	var peak int16
+	for _, v := range pcm {
+		if math.Abs(float64(v)) > math.Abs(float64(peak)) {
+			peak = v
+		}
+	}
+	return peak
+}
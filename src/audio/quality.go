@@ -0,0 +1,194 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	// DefaultQualityInterval is how much audio each AudioQualityFrame covers
+	DefaultQualityInterval = 5 * time.Second
+	// DefaultMaxClippingRatio warns when more than 1% of samples are clipped
+	DefaultMaxClippingRatio = 0.01
+	// DefaultMaxSilenceRatio warns when more than 95% of the audio is silent
+	DefaultMaxSilenceRatio = 0.95
+	// DefaultMaxDCOffset warns when the mean sample exceeds 5% of full scale
+	DefaultMaxDCOffset = 0.05
+	// DefaultMinQualityLevel warns when non-silent audio is quieter than -40 dBFS
+	DefaultMinQualityLevel = 0.01
+	// DefaultMaxQualityLevel warns when non-silent audio is louder than -6 dBFS
+	DefaultMaxQualityLevel = 0.5
+)
+
+// qualitySilenceLevel is the RMS (-60 dBFS) below which a frame counts as silent
+const qualitySilenceLevel = 0.001
+
+// AudioQualityConfig holds thresholds for the audio quality self-check.
+// Zero values use the defaults above.
+type AudioQualityConfig struct {
+	Interval         time.Duration // Audio per report (default: 5s)
+	MaxClippingRatio float64       // Fraction of samples at full scale (default: 0.01)
+	MaxSilenceRatio  float64       // Fraction of audio below -60 dBFS (default: 0.95)
+	MaxDCOffset      float64       // Absolute mean sample, normalized (default: 0.05)
+	MinLevel         float64       // RMS of non-silent audio, normalized (default: 0.01)
+	MaxLevel         float64       // RMS of non-silent audio, normalized (default: 0.5)
+	IncludeOutput    bool          // Also check outbound TTS audio (Source "bot")
+}
+
+// qualityMeter accumulates one audio source over the current report window
+type qualityMeter struct {
+	source        string
+	samples       int
+	clipped       int
+	sum           float64 // Normalized samples, for the DC offset
+	voicedSquares float64
+	voicedSamples int
+	silent        time.Duration
+	elapsed       time.Duration
+}
+
+// AudioQualityProcessor is a QA self-check: for every Interval of audio it
+// emits an AudioQualityFrame with the clipping ratio, silence ratio, DC
+// offset and level, and logs a warning for each threshold crossed. Audio
+// frames are passed through untouched.
+type AudioQualityProcessor struct {
+	*processors.BaseProcessor
+	config AudioQualityConfig
+	input  *qualityMeter
+	output *qualityMeter
+	log    *logger.Logger
+}
+
+// NewAudioQualityProcessor creates a new audio quality processor
+func NewAudioQualityProcessor(config AudioQualityConfig) *AudioQualityProcessor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultQualityInterval
+	}
+	if config.MaxClippingRatio <= 0 {
+		config.MaxClippingRatio = DefaultMaxClippingRatio
+	}
+	if config.MaxSilenceRatio <= 0 {
+		config.MaxSilenceRatio = DefaultMaxSilenceRatio
+	}
+	if config.MaxDCOffset <= 0 {
+		config.MaxDCOffset = DefaultMaxDCOffset
+	}
+	if config.MinLevel <= 0 {
+		config.MinLevel = DefaultMinQualityLevel
+	}
+	if config.MaxLevel <= 0 {
+		config.MaxLevel = DefaultMaxQualityLevel
+	}
+
+	p := &AudioQualityProcessor{
+		config: config,
+		input:  &qualityMeter{source: "user"},
+		output: &qualityMeter{source: "bot"},
+		log:    logger.WithPrefix("AudioQuality"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("AudioQuality", p)
+	return p
+}
+
+func (p *AudioQualityProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	var report *frames.AudioQualityFrame
+
+	switch f := frame.(type) {
+	case *frames.AudioFrame:
+		report = p.measure(p.input, f.Data, f.SampleRate, f.Channels, f.Metadata())
+	case *frames.TTSAudioFrame:
+		if p.config.IncludeOutput {
+			report = p.measure(p.output, f.Data, f.SampleRate, f.Channels, f.Metadata())
+		}
+	}
+
+	if err := p.PushFrame(frame, direction); err != nil {
+		return err
+	}
+	if report != nil {
+		return p.PushFrame(report, frames.Downstream)
+	}
+	return nil
+}
+
+// measure adds a frame to the meter and returns a report once a full
+// interval of audio has been seen
+func (p *AudioQualityProcessor) measure(m *qualityMeter, data []byte, sampleRate, channels int, meta map[string]interface{}) *frames.AudioQualityFrame {
+	if len(data) == 0 || sampleRate <= 0 {
+		return nil
+	}
+	if channels <= 0 {
+		channels = 1
+	}
+
+	pcm := linearPCM(data, meta)
+	n := len(pcm) / 2
+	if n == 0 {
+		return nil
+	}
+	duration := time.Duration(n/channels) * time.Second / time.Duration(sampleRate)
+
+	for i := 0; i+1 < len(pcm); i += 2 {
+		sample := int16(pcm[i]) | int16(pcm[i+1])<<8
+		if sample >= math.MaxInt16 || sample == math.MinInt16 {
+			m.clipped++
+		}
+		m.sum += float64(sample) / 32768.0
+	}
+	m.samples += n
+
+	rms := float64(CalculateVolume(pcm))
+	if rms < qualitySilenceLevel {
+		m.silent += duration
+	} else {
+		m.voicedSquares += rms * rms * float64(n)
+		m.voicedSamples += n
+	}
+	m.elapsed += duration
+
+	if m.elapsed < p.config.Interval {
+		return nil
+	}
+	report := p.report(m)
+	*m = qualityMeter{source: m.source}
+	return report
+}
+
+// report summarizes the meter's window and logs any thresholds crossed
+func (p *AudioQualityProcessor) report(m *qualityMeter) *frames.AudioQualityFrame {
+	report := frames.NewAudioQualityFrame(m.source)
+	report.Duration = m.elapsed
+	report.ClippingRatio = float64(m.clipped) / float64(m.samples)
+	report.SilenceRatio = float64(m.silent) / float64(m.elapsed)
+	report.DCOffset = m.sum / float64(m.samples)
+	if m.voicedSamples > 0 {
+		report.RMS = math.Sqrt(m.voicedSquares / float64(m.voicedSamples))
+	}
+
+	if report.ClippingRatio > p.config.MaxClippingRatio {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("clipping: %.1f%% of samples at full scale", report.ClippingRatio*100))
+	}
+	if report.SilenceRatio > p.config.MaxSilenceRatio {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("silence: %.0f%% of audio below -60 dBFS", report.SilenceRatio*100))
+	}
+	if math.Abs(report.DCOffset) > p.config.MaxDCOffset {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("DC offset: %.3f", report.DCOffset))
+	}
+	if m.voicedSamples > 0 && report.RMS < p.config.MinLevel {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("level too low: RMS %.4f", report.RMS))
+	} else if report.RMS > p.config.MaxLevel {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("level too high: RMS %.3f", report.RMS))
+	}
+
+	if len(report.Warnings) > 0 {
+		p.log.Warn("%s audio over %v: %s", m.source, m.elapsed, strings.Join(report.Warnings, "; "))
+	}
+	return report
+}
@@ -0,0 +1,108 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func (c *frameCapturer) qualityReports() []*frames.AudioQualityFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []*frames.AudioQualityFrame
+	for _, f := range c.frames {
+		if report, ok := f.(*frames.AudioQualityFrame); ok {
+			result = append(result, report)
+		}
+	}
+	return result
+}
+
+// clipped returns a loud 440Hz tone with every 50th sample at full scale
+func clipped() []int16 {
+	pcm := sine(440, 16000, 0, 16000, 16000)
+	for i := 0; i < len(pcm); i += 50 {
+		pcm[i] = 32767
+	}
+	return pcm
+}
+
+func TestAudioQualityProcessor_Warnings(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		pcm         []int16
+		wantWarning string // Prefix of the expected warning, "" for none
+	}{
+		{"clean tone", sine(440, 8000, 0, 16000, 16000), ""},
+		{"clipped", clipped(), "clipping"},
+		{"silent", make([]int16, 16000), "silence"},
+		{"dc offset", sine(440, 4000, 3000, 16000, 16000), "DC offset"},
+		{"too quiet", sine(440, 100, 0, 16000, 16000), "level too low"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewAudioQualityProcessor(AudioQualityConfig{Interval: time.Second})
+			capture := &frameCapturer{}
+			p.Link(capture)
+
+			// 1s of 16kHz audio in 20ms frames
+			for start := 0; start < len(tc.pcm); start += 320 {
+				frame := frames.NewAudioFrame(PCMToBytes(tc.pcm[start:start+320]), 16000, 1)
+				if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+					t.Fatalf("HandleFrame failed: %v", err)
+				}
+			}
+
+			reports := capture.qualityReports()
+			if len(reports) != 1 {
+				t.Fatalf("Expected 1 report for 1s of audio, got %d", len(reports))
+			}
+			report := reports[0]
+			if report.Source != "user" || report.Duration != time.Second {
+				t.Errorf("Report source %q duration %v, want user and 1s", report.Source, report.Duration)
+			}
+			if tc.wantWarning == "" {
+				if len(report.Warnings) != 0 {
+					t.Errorf("Expected no warnings, got %q", report.Warnings)
+				}
+				return
+			}
+			if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], tc.wantWarning) {
+				t.Errorf("Warnings = %q, want one %q warning", report.Warnings, tc.wantWarning)
+			}
+		})
+	}
+}
+
+func TestAudioQualityProcessor_Ratios(t *testing.T) {
+	p := NewAudioQualityProcessor(AudioQualityConfig{Interval: time.Second, IncludeOutput: true})
+	capture := &frameCapturer{}
+	p.Link(capture)
+
+	// Half a second of silence then half a second of clipped tone
+	pcm := append(make([]int16, 8000), clipped()[:8000]...)
+	for start := 0; start < len(pcm); start += 320 {
+		frame := frames.NewTTSAudioFrame(PCMToBytes(pcm[start:start+320]), 16000, 1)
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+	}
+
+	reports := capture.qualityReports()
+	if len(reports) != 1 || reports[0].Source != "bot" {
+		t.Fatalf("Expected 1 bot report, got %d", len(reports))
+	}
+	report := reports[0]
+	if report.SilenceRatio != 0.5 {
+		t.Errorf("SilenceRatio = %.3f, want 0.5", report.SilenceRatio)
+	}
+	if report.ClippingRatio != 0.01 {
+		t.Errorf("ClippingRatio = %.4f, want 0.01 (160 of 16000 samples)", report.ClippingRatio)
+	}
+	// The level ignores the silent half: a 16000-amplitude sine is near 0.35
+	if report.RMS < 0.33 || report.RMS > 0.37 {
+		t.Errorf("RMS = %.3f, want near 0.35", report.RMS)
+	}
+}
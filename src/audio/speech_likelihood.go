@@ -0,0 +1,291 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/turns/user_start"
+)
+
+const (
+	// DefaultSpeechLikelihoodThreshold is the mean likelihood over the window
+	// required to interrupt
+	DefaultSpeechLikelihoodThreshold = 0.6
+	// DefaultSpeechLikelihoodWindow is how long speech must be sustained
+	DefaultSpeechLikelihoodWindow = 300 * time.Millisecond
+	// DefaultSpeechLikelihoodMinRMS treats quieter frames as silence
+	DefaultSpeechLikelihoodMinRMS = 0.01
+
+	// speechAnalysisFrame is the hop between feature extractions
+	speechAnalysisFrame = 20 * time.Millisecond
+)
+
+// Feature bands: voiced speech concentrates its energy in the low band
+// (fundamental and first formant), while clicks, slams and hiss spread it
+// up to Nyquist.
+const (
+	speechBandLowHz  = 100.0
+	speechBandHighHz = 1000.0
+	noiseBandLowHz   = 2000.0
+)
+
+// SpeechFeatures are the spectral features of one analysis frame
+type SpeechFeatures struct {
+	RMS       float64 // Normalized RMS level, 0-1
+	Flatness  float64 // Spectral flatness over the voice band, 0 (tonal) to 1 (white noise)
+	BandRatio float64 // Energy in 100-1000Hz over energy above 2kHz
+}
+
+// Likelihood maps the features to a 0-1 speech likelihood. Speech is
+// harmonic (low flatness) and low-band heavy; impulsive noise is neither.
+func (f SpeechFeatures) Likelihood(minRMS float64) float64 {
+	if f.RMS < minRMS {
+		return 0
+	}
+	// Flatness <= 0.1 scores 1, >= 0.5 scores 0
+	tonal := clamp01((0.5 - f.Flatness) / 0.4)
+	// A low/high ratio >= 10 scores 1, <= 1 scores 0
+	lowBand := clamp01(math.Log10(math.Max(f.BandRatio, 1e-9)))
+	return math.Sqrt(tonal * lowBand)
+}
+
+// ExtractSpeechFeatures computes the spectral features of a PCM frame
+func ExtractSpeechFeatures(pcm []int16, sampleRate int) SpeechFeatures {
+	if len(pcm) == 0 || sampleRate <= 0 {
+		return SpeechFeatures{}
+	}
+
+	var sumSquares float64
+	for _, s := range pcm {
+		v := float64(s) / 32768.0
+		sumSquares += v * v
+	}
+	features := SpeechFeatures{RMS: math.Sqrt(sumSquares / float64(len(pcm)))}
+
+	power := powerSpectrum(pcm)
+	binHz := float64(sampleRate) / float64(2*(len(power)-1))
+	nyquist := float64(sampleRate) / 2
+
+	var logSum, linSum, low, high float64
+	var voiceBins int
+	for i, p := range power {
+		hz := float64(i) * binHz
+		if hz >= speechBandLowHz && hz <= math.Min(4000, nyquist) {
+			// Floor keeps log(0) out of silent bins
+			logSum += math.Log(p + 1e-12)
+			linSum += p + 1e-12
+			voiceBins++
+		}
+		if hz >= speechBandLowHz && hz <= speechBandHighHz {
+			low += p
+		}
+		if hz >= noiseBandLowHz {
+			high += p
+		}
+	}
+
+	if voiceBins > 0 {
+		features.Flatness = math.Exp(logSum/float64(voiceBins)) / (linSum / float64(voiceBins))
+	}
+	features.BandRatio = low / (high + 1e-12)
+	return features
+}
+
+// powerSpectrum returns the one-sided power spectrum of a Hann-windowed,
+// zero-padded frame
+func powerSpectrum(pcm []int16) []float64 {
+	n := 1
+	for n < len(pcm) {
+		n <<= 1
+	}
+
+	buf := make([]complex128, n)
+	for i, s := range pcm {
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(len(pcm)))
+		buf[i] = complex(float64(s)/32768.0*w, 0)
+	}
+	fft(buf)
+
+	power := make([]float64, n/2+1)
+	for i := range power {
+		m := cmplx.Abs(buf[i])
+		power[i] = m * m
+	}
+	return power
+}
+
+// fft is an in-place iterative radix-2 FFT; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				w *= step
+			}
+		}
+	}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// SpeechLikelihoodConfig configures a SpeechLikelihoodStrategy
+type SpeechLikelihoodConfig struct {
+	Threshold float64       // Mean likelihood over Window required to interrupt (default: 0.6)
+	Window    time.Duration // How long speech must be sustained (default: 300ms)
+	MinRMS    float64       // Normalized RMS below which a frame counts as silence (default: 0.01)
+}
+
+// SpeechLikelihoodStrategy is a user turn start strategy that only starts a
+// turn, and interrupts the bot, for sustained speech. Unlike a volume gate it
+// ignores door slams, keyboard clacks and other impulsive noise: each 20ms of
+// the user's audio is scored from its spectral flatness and low/high band
+// energy ratio, and the turn starts once the mean score over the window
+// reaches the threshold.
+//
+// Use it in place of the VAD start strategy, which would otherwise start the
+// turn (and interrupt) on any voice activity first:
+//
+//	turns.UserTurnStrategies{
+//	    StartStrategies: []user_start.UserTurnStartStrategy{
+//	        audio.NewSpeechLikelihoodStrategy(audio.SpeechLikelihoodConfig{}),
+//	    },
+//	    ...
+//	}
+type SpeechLikelihoodStrategy struct {
+	threshold float64
+	window    time.Duration
+	minRMS    float64
+
+	mu         sync.Mutex
+	pending    []int16
+	sampleRate int
+	scores     []float64 // Most recent frame likelihoods, at most one window long
+}
+
+var _ user_start.UserTurnStartStrategy = (*SpeechLikelihoodStrategy)(nil)
+
+// NewSpeechLikelihoodStrategy creates a speech likelihood interruption strategy
+func NewSpeechLikelihoodStrategy(config SpeechLikelihoodConfig) *SpeechLikelihoodStrategy {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultSpeechLikelihoodThreshold
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultSpeechLikelihoodWindow
+	}
+	if config.MinRMS <= 0 {
+		config.MinRMS = DefaultSpeechLikelihoodMinRMS
+	}
+	return &SpeechLikelihoodStrategy{
+		threshold: config.Threshold,
+		window:    config.Window,
+		minRMS:    config.MinRMS,
+	}
+}
+
+// AppendAudio scores linear16 audio in 20ms frames. A trailing partial
+// frame is kept for the next call.
+func (s *SpeechLikelihoodStrategy) AppendAudio(audio []byte, sampleRate int) error {
+	frameSize := int(speechAnalysisFrame.Seconds() * float64(sampleRate))
+	if frameSize <= 0 {
+		return nil
+	}
+	pcm, err := BytesToPCM(audio[:len(audio)&^1])
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sampleRate != s.sampleRate {
+		s.pending = s.pending[:0]
+		s.scores = s.scores[:0]
+		s.sampleRate = sampleRate
+	}
+	s.pending = append(s.pending, pcm...)
+
+	maxScores := s.windowFrames()
+	for len(s.pending) >= frameSize {
+		score := ExtractSpeechFeatures(s.pending[:frameSize], sampleRate).Likelihood(s.minRMS)
+		s.pending = s.pending[frameSize:]
+
+		s.scores = append(s.scores, score)
+		if len(s.scores) > maxScores {
+			s.scores = s.scores[len(s.scores)-maxScores:]
+		}
+	}
+	return nil
+}
+
+// ShouldStart scores the user's AudioFrames and reports whether the window
+// now holds sustained speech. Other frames are ignored; the decision is
+// purely acoustic.
+func (s *SpeechLikelihoodStrategy) ShouldStart(frame any) bool {
+	audioFrame, ok := frame.(*frames.AudioFrame)
+	if !ok {
+		return false
+	}
+	if err := s.AppendAudio(linearPCM(audioFrame.Data, audioFrame.Metadata()), audioFrame.SampleRate); err != nil {
+		return false
+	}
+	start, _ := s.ShouldInterrupt()
+	return start
+}
+
+// EnableInterruptions reports true: a turn started by sustained speech
+// interrupts the bot
+func (s *SpeechLikelihoodStrategy) EnableInterruptions() bool {
+	return true
+}
+
+// ShouldInterrupt reports whether a full window of audio has a mean speech
+// likelihood at or above the threshold
+func (s *SpeechLikelihoodStrategy) ShouldInterrupt() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.scores) < s.windowFrames() {
+		return false, nil
+	}
+	var sum float64
+	for _, score := range s.scores {
+		sum += score
+	}
+	return sum/float64(len(s.scores)) >= s.threshold, nil
+}
+
+// windowFrames is the number of analysis frames in one window
+func (s *SpeechLikelihoodStrategy) windowFrames() int {
+	if n := int(s.window / speechAnalysisFrame); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// Reset clears buffered audio and scores
+func (s *SpeechLikelihoodStrategy) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = nil
+	s.scores = nil
+}
@@ -0,0 +1,148 @@
+package audio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// voicedSpeech synthesizes a voiced vowel: a 140Hz glottal pulse train
+// with harmonics rolling off above ~1kHz, amplitude-modulated at a
+// syllable rate of 4Hz
+func voicedSpeech(sampleRate, n int) []int16 {
+	pcm := make([]int16, n)
+	for i := range pcm {
+		t := float64(i) / float64(sampleRate)
+		var v float64
+		for h := 1; float64(h)*140 < float64(sampleRate)/2; h++ {
+			f := float64(h) * 140
+			v += math.Sin(2*math.Pi*f*t) / (1 + math.Pow(f/700, 3))
+		}
+		envelope := 0.6 + 0.4*math.Sin(2*math.Pi*4*t)
+		pcm[i] = int16(4000 * envelope * v)
+	}
+	return pcm
+}
+
+// doorSlam is a 60ms broadband burst decaying into silence
+func doorSlam(sampleRate, n int) []int16 {
+	rng := rand.New(rand.NewSource(1))
+	pcm := make([]int16, n)
+	for i := range pcm {
+		t := float64(i) / float64(sampleRate)
+		if t < 0.06 {
+			pcm[i] = int16(20000 * math.Exp(-t/0.015) * (rng.Float64()*2 - 1))
+		}
+	}
+	return pcm
+}
+
+// keyboardClacks are 5ms clicks every 120ms
+func keyboardClacks(sampleRate, n int) []int16 {
+	rng := rand.New(rand.NewSource(2))
+	period := int(0.12 * float64(sampleRate))
+	click := int(0.005 * float64(sampleRate))
+	pcm := make([]int16, n)
+	for i := range pcm {
+		if i%period < click {
+			pcm[i] = int16(15000 * (rng.Float64()*2 - 1))
+		}
+	}
+	return pcm
+}
+
+func TestExtractSpeechFeatures(t *testing.T) {
+	speech := ExtractSpeechFeatures(voicedSpeech(16000, 320), 16000)
+	noise := ExtractSpeechFeatures(keyboardClacks(16000, 320), 16000)
+
+	if speech.Flatness >= noise.Flatness {
+		t.Errorf("Expected speech to be less flat than noise: %.3f vs %.3f", speech.Flatness, noise.Flatness)
+	}
+	if speech.BandRatio <= 10 || noise.BandRatio >= 1 {
+		t.Errorf("Expected low-band heavy speech and high-band heavy noise, got ratios %.2f and %.2f", speech.BandRatio, noise.BandRatio)
+	}
+	if l := speech.Likelihood(DefaultSpeechLikelihoodMinRMS); l < 0.8 {
+		t.Errorf("Expected high speech likelihood, got %.2f", l)
+	}
+	if l := noise.Likelihood(DefaultSpeechLikelihoodMinRMS); l > 0.2 {
+		t.Errorf("Expected low noise likelihood, got %.2f", l)
+	}
+	if l := ExtractSpeechFeatures(make([]int16, 320), 16000).Likelihood(DefaultSpeechLikelihoodMinRMS); l != 0 {
+		t.Errorf("Expected silence to score 0, got %.2f", l)
+	}
+}
+
+// feedStrategy appends pcm in 20ms frames and reports whether the strategy
+// asked to interrupt at any point
+func feedStrategy(t *testing.T, s *SpeechLikelihoodStrategy, pcm []int16, sampleRate int) bool {
+	t.Helper()
+	frame := sampleRate / 50
+	for start := 0; start+frame <= len(pcm); start += frame {
+		if err := s.AppendAudio(PCMToBytes(pcm[start:start+frame]), sampleRate); err != nil {
+			t.Fatalf("AppendAudio failed: %v", err)
+		}
+		interrupt, err := s.ShouldInterrupt()
+		if err != nil {
+			t.Fatalf("ShouldInterrupt failed: %v", err)
+		}
+		if interrupt {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSpeechLikelihoodStrategy_OnlySpeechInterrupts(t *testing.T) {
+	for _, rate := range []int{8000, 16000} {
+		cases := []struct {
+			name string
+			pcm  []int16
+			want bool
+		}{
+			{"speech", voicedSpeech(rate, rate), true},
+			{"door slam", doorSlam(rate, rate), false},
+			{"keyboard", keyboardClacks(rate, rate), false},
+			{"silence", make([]int16, rate), false},
+		}
+		for _, tc := range cases {
+			s := NewSpeechLikelihoodStrategy(SpeechLikelihoodConfig{})
+			if got := feedStrategy(t, s, tc.pcm, rate); got != tc.want {
+				t.Errorf("%s @ %dHz: interrupt = %v, want %v", tc.name, rate, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestSpeechLikelihoodStrategy_RequiresSustainedSpeech(t *testing.T) {
+	s := NewSpeechLikelihoodStrategy(SpeechLikelihoodConfig{})
+
+	// 200ms of speech is shorter than the 300ms window
+	if feedStrategy(t, s, voicedSpeech(16000, 3200), 16000) {
+		t.Fatal("Expected no interruption before the window is full")
+	}
+	if !feedStrategy(t, s, voicedSpeech(16000, 3200), 16000) {
+		t.Fatal("Expected interruption once speech is sustained")
+	}
+
+	s.Reset()
+	if interrupt, _ := s.ShouldInterrupt(); interrupt {
+		t.Error("Expected Reset to clear the window")
+	}
+}
+
+func TestSpeechLikelihoodStrategy_BuffersPartialFrames(t *testing.T) {
+	s := NewSpeechLikelihoodStrategy(SpeechLikelihoodConfig{Window: 100 * time.Millisecond})
+	data := PCMToBytes(voicedSpeech(16000, 1600))
+
+	// Writes that never align with the 20ms analysis frame
+	for start := 0; start < len(data); start += 250 {
+		end := min(start+250, len(data))
+		if err := s.AppendAudio(data[start:end], 16000); err != nil {
+			t.Fatalf("AppendAudio failed: %v", err)
+		}
+	}
+	if interrupt, _ := s.ShouldInterrupt(); !interrupt {
+		t.Error("Expected interruption for 100ms of speech in unaligned writes")
+	}
+}
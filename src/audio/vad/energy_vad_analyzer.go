@@ -0,0 +1,138 @@
+package vad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+)
+
+// EnergyVADConfig holds the thresholds for the energy-based VAD
+type EnergyVADConfig struct {
+	// EnergyThreshold is the RMS level (0.0 to 1.0) at which a frame counts
+	// as voice; louder frames score proportionally higher (default: 0.02)
+	EnergyThreshold float32
+
+	// MinZCR and MaxZCR bound the zero-crossing rate (fraction of adjacent
+	// samples that change sign) accepted as speech. Frames below MinZCR are
+	// hum or DC offset, frames above MaxZCR are broadband noise such as hiss
+	// (defaults: 0.005 and 0.4)
+	MinZCR float32
+	MaxZCR float32
+
+	// FrameDuration is the analysis window (default: 20ms)
+	FrameDuration time.Duration
+}
+
+// DefaultEnergyVADConfig returns the default energy VAD thresholds
+func DefaultEnergyVADConfig() EnergyVADConfig {
+	return EnergyVADConfig{
+		EnergyThreshold: 0.02,
+		MinZCR:          0.005,
+		MaxZCR:          0.4,
+		FrameDuration:   20 * time.Millisecond,
+	}
+}
+
+// EnergyVADAnalyzer detects voice from RMS energy and zero-crossing rate,
+// feeding the result through the shared VAD state machine. It needs no model
+// or external library, so it is a fallback when the onnx-worker isn't
+// available. It is far less robust to background noise than Silero.
+//
+// Any sample rate is accepted, and AnalyzeAudio accepts buffers of any size;
+// NumFramesRequired only sets the window the VADInputProcessor buffers.
+type EnergyVADAnalyzer struct {
+	*BaseVADAnalyzer
+	config EnergyVADConfig
+}
+
+// NewEnergyVADAnalyzer creates a new energy-based VAD analyzer. Zero fields
+// in config take their defaults.
+func NewEnergyVADAnalyzer(sampleRate int, params VADParams, config EnergyVADConfig) *EnergyVADAnalyzer {
+	defaults := DefaultEnergyVADConfig()
+	if config.EnergyThreshold <= 0 {
+		config.EnergyThreshold = defaults.EnergyThreshold
+	}
+	if config.MinZCR <= 0 {
+		config.MinZCR = defaults.MinZCR
+	}
+	if config.MaxZCR <= 0 {
+		config.MaxZCR = defaults.MaxZCR
+	}
+	if config.FrameDuration <= 0 {
+		config.FrameDuration = defaults.FrameDuration
+	}
+
+	return &EnergyVADAnalyzer{
+		BaseVADAnalyzer: NewBaseVADAnalyzer(sampleRate, params),
+		config:          config,
+	}
+}
+
+// SetSampleRate sets the audio sample rate; any positive rate is supported.
+func (v *EnergyVADAnalyzer) SetSampleRate(sampleRate int) error {
+	if sampleRate <= 0 {
+		return fmt.Errorf("energy VAD requires a positive sample rate (got %d)", sampleRate)
+	}
+	return v.BaseVADAnalyzer.SetSampleRate(sampleRate)
+}
+
+// NumFramesRequired returns the number of samples in one analysis window.
+func (v *EnergyVADAnalyzer) NumFramesRequired() int {
+	n := int(v.config.FrameDuration.Seconds() * float64(v.GetSampleRate()))
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// VoiceConfidence scores the buffer in [0.0, 1.0]. A frame at exactly
+// EnergyThreshold scores the configured Confidence, so EnergyThreshold is the
+// level that counts as voice; frames outside the ZCR range score 0.
+func (v *EnergyVADAnalyzer) VoiceConfidence(buffer []byte) float32 {
+	if len(buffer) < 4 {
+		return 0.0
+	}
+
+	zcr := zeroCrossingRate(buffer)
+	if zcr < v.config.MinZCR || zcr > v.config.MaxZCR {
+		return 0.0
+	}
+
+	rms := audio.CalculateVolume(buffer)
+	confidence := v.GetParams().Confidence * rms / v.config.EnergyThreshold
+	if confidence > 1.0 {
+		return 1.0
+	}
+	return confidence
+}
+
+// AnalyzeAudio processes audio and returns the current VAD state.
+func (v *EnergyVADAnalyzer) AnalyzeAudio(buffer []byte) (VADState, error) {
+	confidence := v.VoiceConfidence(buffer)
+
+	// Time the state machine by the buffer actually given, so any frame
+	// size works
+	numFrames := len(buffer) / 2
+	if numFrames == 0 {
+		return v.GetState(), nil
+	}
+	return v.ProcessAudio(buffer, confidence, numFrames)
+}
+
+// zeroCrossingRate returns the fraction of adjacent int16 samples whose sign
+// differs
+func zeroCrossingRate(buffer []byte) float32 {
+	numSamples := len(buffer) / 2
+	crossings := 0
+	prev := int16(binary.LittleEndian.Uint16(buffer))
+	for i := 1; i < numSamples; i++ {
+		sample := int16(binary.LittleEndian.Uint16(buffer[i*2:]))
+		if (sample >= 0) != (prev >= 0) {
+			crossings++
+		}
+		prev = sample
+	}
+	return float32(crossings) / float32(numSamples-1)
+}
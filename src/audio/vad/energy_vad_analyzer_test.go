@@ -0,0 +1,286 @@
+package vad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// voiceBuffer returns n samples of a voiced-speech-like signal: a 150Hz
+// fundamental with two harmonics at roughly -14dBFS RMS
+func voiceBuffer(sampleRate, n, offset int) []byte {
+	buf := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		t := float64(offset+i) / float64(sampleRate)
+		v := 0.2*math.Sin(2*math.Pi*150*t) +
+			0.1*math.Sin(2*math.Pi*300*t) +
+			0.05*math.Sin(2*math.Pi*450*t)
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(v*32767)))
+	}
+	return buf
+}
+
+// analyzeFor feeds chunks of frameSize samples for duration seconds and
+// returns the state after each chunk
+func analyzeFor(t *testing.T, v *EnergyVADAnalyzer, seconds float64, frameSize int, gen func(n, offset int) []byte) []VADState {
+	t.Helper()
+	var states []VADState
+	total := int(seconds * float64(v.GetSampleRate()))
+	for offset := 0; offset < total; offset += frameSize {
+		state, err := v.AnalyzeAudio(gen(frameSize, offset))
+		if err != nil {
+			t.Fatalf("AnalyzeAudio failed: %v", err)
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func TestEnergyVAD_SpeechThenSilence(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		sampleRate int
+		frameSize  int // Samples per AnalyzeAudio call
+	}{
+		{"8kHz 20ms", 8000, 160},
+		{"16kHz 32ms", 16000, 512},
+		{"24kHz 10ms", 24000, 240},
+		{"48kHz 30ms", 48000, 1440},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewEnergyVADAnalyzer(tc.sampleRate, DefaultVADParams(), EnergyVADConfig{})
+			speech := func(n, offset int) []byte { return voiceBuffer(tc.sampleRate, n, offset) }
+			silence := func(n, offset int) []byte { return make([]byte, n*2) }
+
+			states := analyzeFor(t, v, 0.6, tc.frameSize, speech)
+			if states[0] == VADStateSpeaking {
+				t.Error("expected speech to need StartSecs before SPEAKING")
+			}
+			if last := states[len(states)-1]; last != VADStateSpeaking {
+				t.Fatalf("expected SPEAKING after 0.6s of speech, got %s", last)
+			}
+
+			states = analyzeFor(t, v, 0.6, tc.frameSize, silence)
+			if states[0] != VADStateStopping {
+				t.Errorf("expected STOPPING on the first silent frame, got %s", states[0])
+			}
+			if last := states[len(states)-1]; last != VADStateQuiet {
+				t.Fatalf("expected QUIET after 0.6s of silence, got %s", last)
+			}
+		})
+	}
+}
+
+func TestEnergyVAD_SilenceStaysQuiet(t *testing.T) {
+	v := NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{})
+	silence := func(n, offset int) []byte { return make([]byte, n*2) }
+
+	for i, state := range analyzeFor(t, v, 1.0, v.NumFramesRequired(), silence) {
+		if state != VADStateQuiet {
+			t.Fatalf("frame %d: expected QUIET on silence, got %s", i, state)
+		}
+	}
+}
+
+func TestEnergyVAD_RejectsNoiseByZCR(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	noise := func(n, offset int) []byte {
+		buf := make([]byte, n*2)
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(rng.NormFloat64()*0.2*32767)))
+		}
+		return buf
+	}
+	dcOffset := func(n, offset int) []byte {
+		buf := make([]byte, n*2)
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(8000)))
+		}
+		return buf
+	}
+
+	for name, gen := range map[string]func(n, offset int) []byte{"white noise": noise, "dc offset": dcOffset} {
+		t.Run(name, func(t *testing.T) {
+			v := NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{})
+			if c := v.VoiceConfidence(gen(320, 0)); c != 0 {
+				t.Errorf("expected zero confidence, got %.3f", c)
+			}
+			for i, state := range analyzeFor(t, v, 0.6, 320, gen) {
+				if state != VADStateQuiet {
+					t.Fatalf("frame %d: expected QUIET, got %s", i, state)
+				}
+			}
+		})
+	}
+}
+
+func TestEnergyVAD_EnergyThreshold(t *testing.T) {
+	params := DefaultVADParams()
+	params.MinVolume = 0
+	quiet := voiceBuffer(16000, 320, 0) // ~0.16 RMS
+
+	loose := NewEnergyVADAnalyzer(16000, params, EnergyVADConfig{EnergyThreshold: 0.05})
+	if c := loose.VoiceConfidence(quiet); c < params.Confidence {
+		t.Errorf("expected voice above a 0.05 threshold, got confidence %.3f", c)
+	}
+	strict := NewEnergyVADAnalyzer(16000, params, EnergyVADConfig{EnergyThreshold: 0.5})
+	if c := strict.VoiceConfidence(quiet); c >= params.Confidence {
+		t.Errorf("expected no voice under a 0.5 threshold, got confidence %.3f", c)
+	}
+}
+
+func TestEnergyVAD_SampleRate(t *testing.T) {
+	v := NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{})
+	if got := v.NumFramesRequired(); got != 320 {
+		t.Errorf("expected 320 samples per 20ms window at 16kHz, got %d", got)
+	}
+	if err := v.SetSampleRate(44100); err != nil {
+		t.Fatalf("SetSampleRate(44100): %v", err)
+	}
+	if got := v.NumFramesRequired(); got != 882 {
+		t.Errorf("expected 882 samples per 20ms window at 44.1kHz, got %d", got)
+	}
+	if err := v.SetSampleRate(0); err == nil {
+		t.Error("expected an error for a zero sample rate")
+	}
+}
+
+// toneBuffer returns n samples of a sine at freq with peak amplitude amp
+// (0.0 to 1.0)
+func toneBuffer(sampleRate, n, offset int, freq, amp float64) []byte {
+	buf := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		t := float64(offset+i) / float64(sampleRate)
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(amp*math.Sin(2*math.Pi*freq*t)*32767)))
+	}
+	return buf
+}
+
+// scaled returns a generator producing voiceBuffer at gain times its level
+func scaled(sampleRate int, gain float64) func(n, offset int) []byte {
+	return func(n, offset int) []byte {
+		buf := voiceBuffer(sampleRate, n, offset)
+		for i := 0; i+1 < len(buf); i += 2 {
+			sample := float64(int16(binary.LittleEndian.Uint16(buf[i:]))) * gain
+			binary.LittleEndian.PutUint16(buf[i:], uint16(int16(sample)))
+		}
+		return buf
+	}
+}
+
+func TestEnergyVAD_AutoCalibrateNoisyLine(t *testing.T) {
+	const sampleRate = 16000
+	params := DefaultVADParams()
+	params.AutoCalibrate = true
+	v := NewEnergyVADAnalyzer(sampleRate, params, EnergyVADConfig{})
+
+	// A loud voice-band hum (RMS ~0.12) that the default thresholds take
+	// for speech
+	hum := func(n, offset int) []byte { return toneBuffer(sampleRate, n, offset, 200, 0.17) }
+	uncalibrated := NewEnergyVADAnalyzer(sampleRate, DefaultVADParams(), EnergyVADConfig{})
+	if states := analyzeFor(t, uncalibrated, 0.6, 320, hum); states[len(states)-1] != VADStateSpeaking {
+		t.Fatalf("expected the hum to pass as speech without calibration, got %s", states[len(states)-1])
+	}
+
+	for _, state := range analyzeFor(t, v, 1.5, 320, hum) {
+		if state != VADStateQuiet {
+			t.Fatalf("expected the hum to stay QUIET during and after calibration, got %s", state)
+		}
+	}
+
+	noise, ok := v.NoiseFloor()
+	if !ok || noise < 0.11 || noise > 0.13 {
+		t.Errorf("NoiseFloor = %.3f, %v; want ~0.12", noise, ok)
+	}
+	got := v.GetParams()
+	if got.MinVolume <= noise || got.MinVolume <= params.MinVolume {
+		t.Errorf("MinVolume = %.3f, want above the noise floor and the default", got.MinVolume)
+	}
+	if got.Confidence <= params.Confidence {
+		t.Errorf("Confidence = %.2f, want raised above %.2f", got.Confidence, params.Confidence)
+	}
+
+	// Speech well above the hum is still detected
+	states := analyzeFor(t, v, 0.6, 320, scaled(sampleRate, 2.5))
+	if last := states[len(states)-1]; last != VADStateSpeaking {
+		t.Errorf("expected speech over the noise to be SPEAKING, got %s", last)
+	}
+}
+
+func TestEnergyVAD_AutoCalibrateQuietLine(t *testing.T) {
+	const sampleRate = 16000
+	params := DefaultVADParams()
+	params.AutoCalibrate = true
+	params.CalibrationSecs = 0.5
+	v := NewEnergyVADAnalyzer(sampleRate, params, EnergyVADConfig{})
+
+	// A soft-spoken caller (RMS ~0.05) is below the default MinVolume
+	quietSpeech := scaled(sampleRate, 0.3)
+	uncalibrated := NewEnergyVADAnalyzer(sampleRate, DefaultVADParams(), EnergyVADConfig{})
+	if states := analyzeFor(t, uncalibrated, 0.6, 320, quietSpeech); states[len(states)-1] == VADStateSpeaking {
+		t.Fatal("expected quiet speech to be missed without calibration")
+	}
+
+	analyzeFor(t, v, 0.5, 320, func(n, offset int) []byte { return toneBuffer(sampleRate, n, offset, 100, 0.004) })
+	if _, ok := v.NoiseFloor(); !ok {
+		t.Fatal("expected calibration to complete after CalibrationSecs")
+	}
+	if got := v.GetParams().MinVolume; got >= params.MinVolume {
+		t.Errorf("MinVolume = %.3f, want lowered below %.3f on a quiet line", got, params.MinVolume)
+	}
+
+	states := analyzeFor(t, v, 0.6, 320, quietSpeech)
+	if last := states[len(states)-1]; last != VADStateSpeaking {
+		t.Errorf("expected quiet speech to be SPEAKING after calibration, got %s", last)
+	}
+
+	// Restart begins a new call: thresholds revert until recalibrated
+	v.Restart()
+	if _, ok := v.NoiseFloor(); ok {
+		t.Error("expected Restart to clear the calibration")
+	}
+	if got := v.GetParams(); got.MinVolume != params.MinVolume || got.Confidence != params.Confidence {
+		t.Errorf("expected Restart to restore the configured thresholds, got %+v", got)
+	}
+}
+
+func TestEnergyVAD_AutoCalibrateIgnoresEarlySpeech(t *testing.T) {
+	const sampleRate = 16000
+	params := DefaultVADParams()
+	params.AutoCalibrate = true
+	v := NewEnergyVADAnalyzer(sampleRate, params, EnergyVADConfig{})
+
+	// A quiet line, but the caller talks through the last 40% of the
+	// calibration window
+	analyzeFor(t, v, 0.6, 320, func(n, offset int) []byte { return toneBuffer(sampleRate, n, offset, 100, 0.004) })
+	analyzeFor(t, v, 0.42, 320, scaled(sampleRate, 1))
+	noise, ok := v.NoiseFloor()
+	if !ok {
+		t.Fatal("expected calibration to complete after CalibrationSecs")
+	}
+	if noise > 0.01 {
+		t.Errorf("NoiseFloor = %.3f, want the quiet line's level, not the speech's", noise)
+	}
+	if got := v.GetParams(); got.MinVolume >= params.MinVolume || got.Confidence != params.Confidence {
+		t.Errorf("expected thresholds set by the quiet line, got minVolume=%.3f confidence=%.2f", got.MinVolume, got.Confidence)
+	}
+}
+
+func TestEnergyVAD_AutoCalibrateCapsAdjustment(t *testing.T) {
+	const sampleRate = 16000
+	params := DefaultVADParams()
+	params.AutoCalibrate = true
+	params.MinVolume = 0.05
+	v := NewEnergyVADAnalyzer(sampleRate, params, EnergyVADConfig{})
+
+	// Loud hum throughout: the thresholds rise, but only so far
+	analyzeFor(t, v, 1.0, 320, func(n, offset int) []byte { return toneBuffer(sampleRate, n, offset, 200, 0.3) })
+	got := v.GetParams()
+	if got.MinVolume > params.MinVolume*maxVolumeAdjustment {
+		t.Errorf("MinVolume = %.3f, want at most %.3f", got.MinVolume, params.MinVolume*maxVolumeAdjustment)
+	}
+	if got.Confidence > params.Confidence+maxConfidenceRaise+1e-6 {
+		t.Errorf("Confidence = %.2f, want at most %.2f", got.Confidence, params.Confidence+maxConfidenceRaise)
+	}
+}
@@ -99,6 +99,17 @@ func (c *OnnxVADClient) VoiceConfidence(audio []byte, sampleRate int) (float32,
 	return 0.0, lastErr
 }
 
+// Reset drops the current connection so the next call starts a fresh worker
+// session, with new hidden state.
+func (c *OnnxVADClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
 // Close closes the underlying Unix socket connection.
 func (c *OnnxVADClient) Close() error {
 	c.mu.Lock()
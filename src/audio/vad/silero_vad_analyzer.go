@@ -3,10 +3,15 @@ package vad
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 )
 
+// defaultModelResetInterval is how often the Silero hidden state is reset,
+// keeping the recurrent state from drifting over long calls
+const defaultModelResetInterval = 5 * time.Second
+
 // SileroVADAnalyzer implements VAD using the Rust onnx-worker via Unix socket.
 // Each instance maintains a persistent connection to the worker; the worker
 // creates a new SileroSession (independent hidden state) per connection.
@@ -16,13 +21,20 @@ type SileroVADAnalyzer struct {
 	sockPath string
 	mu       sync.Mutex
 
+	// Model state resets, checked and done under mu with inference so a reset
+	// never lands between a request and its response
+	resetInterval time.Duration
+	lastResetTime time.Time
+
 	// Debug logging — log every N frames to avoid spam
 	frameCount      int
 	logEveryNFrames int
 }
 
 // NewSileroVADAnalyzer creates a new Silero VAD analyzer backed by the Rust
-// onnx-worker reachable at sockPath (Unix socket path).
+// onnx-worker reachable at sockPath (Unix socket path). ONNX Runtime settings
+// (threads, execution provider) belong to the worker; see
+// worker.StartWithConfig.
 func NewSileroVADAnalyzer(sampleRate int, params VADParams, sockPath string) (*SileroVADAnalyzer, error) {
 	client, err := NewOnnxVADClient(sockPath)
 	if err != nil {
@@ -35,10 +47,26 @@ func NewSileroVADAnalyzer(sampleRate int, params VADParams, sockPath string) (*S
 		BaseVADAnalyzer: base,
 		client:          client,
 		sockPath:        sockPath,
+		resetInterval:   defaultModelResetInterval,
+		lastResetTime:   time.Now(),
 		logEveryNFrames: 50,
 	}, nil
 }
 
+// SetModelResetInterval sets how often the model's hidden state is reset
+// (default 5s). Zero or negative disables periodic resets.
+func (v *SileroVADAnalyzer) SetModelResetInterval(interval time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.resetInterval = interval
+}
+
+// Stateful reports that the model keeps a hidden state across windows:
+// overlapping windows would feed it the same samples more than once.
+func (v *SileroVADAnalyzer) Stateful() bool {
+	return true
+}
+
 // SetSampleRate validates and sets the audio sample rate.
 func (v *SileroVADAnalyzer) SetSampleRate(sampleRate int) error {
 	if sampleRate != 8000 && sampleRate != 16000 {
@@ -61,6 +89,11 @@ func (v *SileroVADAnalyzer) VoiceConfidence(buffer []byte) float32 {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	if v.resetInterval > 0 && time.Since(v.lastResetTime) >= v.resetInterval {
+		v.client.Reset()
+		v.lastResetTime = time.Now()
+	}
+
 	confidence, err := v.client.VoiceConfidence(buffer, v.GetSampleRate())
 	if err != nil {
 		logger.Error("[SileroVAD] onnx-worker error: %v", err)
@@ -102,6 +135,7 @@ func (v *SileroVADAnalyzer) Restart() {
 	} else {
 		v.client = client
 	}
+	v.lastResetTime = time.Now()
 	v.mu.Unlock()
 
 	v.BaseVADAnalyzer.Restart()
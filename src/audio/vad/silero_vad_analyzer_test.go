@@ -0,0 +1,125 @@
+package vad
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startSessionCountingVADServer serves any number of connections, answering
+// every request with response, and counts the sessions opened
+func startSessionCountingVADServer(t *testing.T, response float32) (string, *atomic.Int32) {
+	t.Helper()
+
+	f, err := os.CreateTemp("", "mock-vad-sessions-*.sock")
+	if err != nil {
+		t.Fatalf("create temp file: %v", err)
+	}
+	sockPath := f.Name()
+	f.Close()
+	os.Remove(sockPath)
+
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	sessions := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			sessions.Add(1)
+			go func() {
+				defer conn.Close()
+				for {
+					var hdr [5]byte
+					if err := readFull(conn, hdr[:]); err != nil {
+						return
+					}
+					payload := make([]byte, binary.LittleEndian.Uint32(hdr[1:5]))
+					if err := readFull(conn, payload); err != nil {
+						return
+					}
+					var resp [4]byte
+					binary.LittleEndian.PutUint32(resp[:], math.Float32bits(response))
+					if err := writeFull(conn, resp[:]); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return sockPath, sessions
+}
+
+// TestSileroVADAnalyzer_ConcurrentModelReset runs VoiceConfidence from
+// several goroutines across reset boundaries; run with -race.
+func TestSileroVADAnalyzer_ConcurrentModelReset(t *testing.T) {
+	const want float32 = 0.8
+	sockPath, sessions := startSessionCountingVADServer(t, want)
+
+	analyzer, err := NewSileroVADAnalyzer(16000, VADParams{}, sockPath)
+	if err != nil {
+		t.Fatalf("NewSileroVADAnalyzer: %v", err)
+	}
+	defer analyzer.Cleanup()
+	analyzer.SetModelResetInterval(20 * time.Millisecond)
+
+	audio := make([]byte, 1024)
+	deadline := time.Now().Add(150 * time.Millisecond)
+	var wg sync.WaitGroup
+	var failures atomic.Int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				if got := analyzer.VoiceConfidence(audio); got != want {
+					failures.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := failures.Load(); n != 0 {
+		t.Fatalf("expected every call to reach the worker, %d failed", n)
+	}
+	// One session per elapsed interval at most, plus the initial connection
+	if n := sessions.Load(); n < 3 || n > 10 {
+		t.Fatalf("expected a fresh session roughly every 20ms over 150ms, got %d", n)
+	}
+}
+
+func TestSileroVADAnalyzer_ModelResetDisabled(t *testing.T) {
+	sockPath, sessions := startSessionCountingVADServer(t, 0.5)
+
+	analyzer, err := NewSileroVADAnalyzer(16000, VADParams{}, sockPath)
+	if err != nil {
+		t.Fatalf("NewSileroVADAnalyzer: %v", err)
+	}
+	defer analyzer.Cleanup()
+	analyzer.SetModelResetInterval(0)
+
+	audio := make([]byte, 1024)
+	for i := 0; i < 5; i++ {
+		analyzer.VoiceConfidence(audio)
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := sessions.Load(); n != 1 {
+		t.Fatalf("expected a single session with resets disabled, got %d", n)
+	}
+}
@@ -3,9 +3,10 @@ package vad
 import (
 	"context"
 	"fmt"
-	"math"
+	"slices"
 	"sync"
 
+	"github.com/square-key-labs/strawgo-ai/src/audio"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 )
 
@@ -51,8 +52,40 @@ type VADParams struct {
 	// MinVolume: Minimum audio volume threshold (0.0 to 1.0)
 	// Audio below this volume is ignored (default: 0.1)
 	MinVolume float32
+
+	// AutoCalibrate measures the line's noise floor over the first
+	// CalibrationSecs of each call, which never triggers SPEAKING, then sets
+	// MinVolume just above the noise level and raises Confidence above the
+	// noise's confidence. The quietest windows set the floor, so early
+	// speech or greeting echo doesn't skew it, and the thresholds only move
+	// a bounded amount from the configured ones. Quiet lines get a lower
+	// MinVolume so soft-spoken callers are heard; noisy lines get higher
+	// thresholds so the noise isn't (default: false)
+	AutoCalibrate bool
+
+	// CalibrationSecs is the length of the calibration window (default: 1.0)
+	CalibrationSecs float32
 }
 
+// Noise floor calibration bounds. The noise is measured as a low percentile
+// of the calibration windows, so a caller speaking early or the echo of a
+// greeting doesn't raise it. MinVolume is set to noiseFloorMargin times the
+// noise volume, kept within [minCalibratedVolume, maxCalibratedVolume] and
+// within a factor of maxVolumeAdjustment of the configured value; Confidence
+// is raised to noiseConfidenceMargin above the noise's confidence, by at most
+// maxConfidenceRaise and up to maxCalibratedConfidence.
+const (
+	defaultCalibrationSecs  float32 = 1.0
+	noiseFloorPercentile    float32 = 0.2
+	noiseFloorMargin        float32 = 2.0
+	minCalibratedVolume     float32 = 0.02
+	maxCalibratedVolume     float32 = 0.5
+	maxVolumeAdjustment     float32 = 4.0
+	noiseConfidenceMargin   float32 = 0.1
+	maxConfidenceRaise      float32 = 0.2
+	maxCalibratedConfidence float32 = 0.95
+)
+
 // DefaultVADParams returns the default VAD parameters
 func DefaultVADParams() VADParams {
 	return VADParams{
@@ -82,9 +115,34 @@ type VADAnalyzer interface {
 	Restart()
 }
 
+// ConfidenceReporter is implemented by analyzers that expose the confidence
+// of their most recent analysis window. Analyzers built on BaseVADAnalyzer
+// implement it.
+type ConfidenceReporter interface {
+	LastConfidence() float32
+}
+
+// HopSizeSetter is implemented by analyzers that can be fed overlapping
+// windows, keeping StartSecs and StopSecs in real time when consecutive
+// windows advance by fewer samples than their length. Analyzers built on
+// BaseVADAnalyzer implement it.
+type HopSizeSetter interface {
+	// SetHopSize sets how many new samples each window adds (0 = windows
+	// don't overlap)
+	SetHopSize(samples int)
+}
+
+// StatefulAnalyzer is implemented by analyzers that carry state from one
+// window to the next, such as a recurrent model, and so must see each sample
+// exactly once. Windows are never overlapped for them.
+type StatefulAnalyzer interface {
+	Stateful() bool
+}
+
 // BaseVADAnalyzer provides common functionality for VAD implementations
 type BaseVADAnalyzer struct {
 	params     VADParams
+	configured VADParams // params as given, restored by Restart
 	sampleRate int
 
 	// State machine
@@ -94,10 +152,23 @@ type BaseVADAnalyzer struct {
 	startThreshold  int
 	stopThreshold   int
 	prevSampleCount int
+	hopSamples      int // Samples between window starts (0 = non-overlapping)
 
 	// Volume tracking
 	smoothedVolume float32
 
+	// Raw confidence of the last window passed to ProcessAudio
+	lastConfidence float32
+
+	// Noise floor calibration (AutoCalibrate): seconds of audio measured so
+	// far, their per-window volume and confidence, and the result once the
+	// window completes
+	calibrated      bool
+	calibrationSecs float32
+	noiseVolumes    []float32
+	noiseConfs      []float32
+	noiseFloor      float32
+
 	// Thread safety
 	mu sync.RWMutex
 }
@@ -114,8 +185,12 @@ func NewBaseVADAnalyzer(sampleRate int, params VADParams) *BaseVADAnalyzer {
 			"Values != %.2fs may affect turn-detection latency or cause premature turn endings.",
 			params.StopSecs, recommendedStopSecs, recommendedStopSecs)
 	}
+	if params.AutoCalibrate && params.CalibrationSecs <= 0 {
+		params.CalibrationSecs = defaultCalibrationSecs
+	}
 	return &BaseVADAnalyzer{
 		params:         params,
+		configured:     params,
 		sampleRate:     sampleRate,
 		state:          VADStateQuiet,
 		smoothedVolume: 0.0,
@@ -131,6 +206,16 @@ func (v *BaseVADAnalyzer) SetSampleRate(sampleRate int) error {
 	return nil
 }
 
+// SetHopSize sets the number of samples between the starts of consecutive
+// analysis windows. With overlapping windows the start and stop thresholds
+// count hops, so a run of voiced windows still spans StartSecs of audio.
+func (v *BaseVADAnalyzer) SetHopSize(samples int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.hopSamples = samples
+	v.prevSampleCount = 0 // Recalculate thresholds on the next window
+}
+
 // GetSampleRate returns the current sample rate
 func (v *BaseVADAnalyzer) GetSampleRate() int {
 	v.mu.RLock()
@@ -138,7 +223,8 @@ func (v *BaseVADAnalyzer) GetSampleRate() int {
 	return v.sampleRate
 }
 
-// GetParams returns the current VAD parameters
+// GetParams returns the current VAD parameters, including any thresholds
+// adapted by AutoCalibrate
 func (v *BaseVADAnalyzer) GetParams() VADParams {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -152,7 +238,24 @@ func (v *BaseVADAnalyzer) GetState() VADState {
 	return v.state
 }
 
-// Restart resets the VAD analyzer state
+// LastConfidence returns the raw voice confidence of the last analyzed
+// window, before the volume gate
+func (v *BaseVADAnalyzer) LastConfidence() float32 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.lastConfidence
+}
+
+// NoiseFloor returns the noise volume measured by AutoCalibrate, and false
+// while calibration hasn't completed
+func (v *BaseVADAnalyzer) NoiseFloor() (float32, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.noiseFloor, v.calibrated
+}
+
+// Restart resets the VAD analyzer state. With AutoCalibrate the configured
+// thresholds are restored and the next call is calibrated afresh.
 func (v *BaseVADAnalyzer) Restart() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
@@ -162,6 +265,54 @@ func (v *BaseVADAnalyzer) Restart() {
 	v.startFrames = 0
 	v.stopFrames = 0
 	v.smoothedVolume = 0.0
+	v.lastConfidence = 0.0
+
+	v.params = v.configured
+	v.calibrated = false
+	v.calibrationSecs = 0
+	v.noiseVolumes = nil
+	v.noiseConfs = nil
+	v.noiseFloor = 0
+}
+
+// calibrate accumulates one window of the calibration period and, once
+// CalibrationSecs of audio have been seen, adapts MinVolume and Confidence
+// to the measured noise floor. Caller must hold mu.
+func (v *BaseVADAnalyzer) calibrate(volume, confidence, windowSecs float32) {
+	v.calibrationSecs += windowSecs
+	v.noiseVolumes = append(v.noiseVolumes, volume)
+	v.noiseConfs = append(v.noiseConfs, confidence)
+	if v.calibrationSecs < v.params.CalibrationSecs {
+		return
+	}
+
+	v.calibrated = true
+	v.noiseFloor = lowPercentile(v.noiseVolumes)
+	noiseConfidence := lowPercentile(v.noiseConfs)
+	v.noiseVolumes = nil
+	v.noiseConfs = nil
+
+	configured := v.configured
+	minVolume := v.noiseFloor * noiseFloorMargin
+	minVolume = min(max(minVolume, minCalibratedVolume, configured.MinVolume/maxVolumeAdjustment),
+		maxCalibratedVolume, configured.MinVolume*maxVolumeAdjustment)
+	v.params.MinVolume = minVolume
+
+	threshold := min(noiseConfidence+noiseConfidenceMargin,
+		configured.Confidence+maxConfidenceRaise, maxCalibratedConfidence)
+	if threshold > v.params.Confidence {
+		v.params.Confidence = threshold
+	}
+
+	logger.Info("[VADAnalyzer] Calibrated to noise floor %.3f (confidence %.2f): minVolume=%.3f, confidence=%.2f",
+		v.noiseFloor, noiseConfidence, v.params.MinVolume, v.params.Confidence)
+}
+
+// lowPercentile returns the noiseFloorPercentile value of windows
+func lowPercentile(windows []float32) float32 {
+	sorted := slices.Clone(windows)
+	slices.Sort(sorted)
+	return sorted[int(noiseFloorPercentile*float32(len(sorted)-1))]
 }
 
 // ProcessAudio implements the VAD state machine logic
@@ -170,6 +321,8 @@ func (v *BaseVADAnalyzer) ProcessAudio(buffer []byte, voiceConfidence float32, n
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	v.lastConfidence = voiceConfidence
+
 	// Calculate volume from audio buffer (int16 samples)
 	volume := v.calculateVolume(buffer)
 
@@ -182,12 +335,28 @@ func (v *BaseVADAnalyzer) ProcessAudio(buffer []byte, voiceConfidence float32, n
 	if sampleCount != v.prevSampleCount {
 		v.prevSampleCount = sampleCount
 		frameTime := float32(numFramesRequired) / float32(v.sampleRate)
-		v.startThreshold = int(v.params.StartSecs / frameTime)
-		v.stopThreshold = int(v.params.StopSecs / frameTime)
+		if v.hopSamples > 0 && v.hopSamples < numFramesRequired {
+			hopTime := float32(v.hopSamples) / float32(v.sampleRate)
+			v.startThreshold = overlappingWindows(v.params.StartSecs, frameTime, hopTime)
+			v.stopThreshold = overlappingWindows(v.params.StopSecs, frameTime, hopTime)
+		} else {
+			v.startThreshold = int(v.params.StartSecs / frameTime)
+			v.stopThreshold = int(v.params.StopSecs / frameTime)
+		}
 		logger.Debug("[VADAnalyzer] Thresholds updated: start=%d frames (%.2fs), stop=%d frames (%.2fs)",
 			v.startThreshold, v.params.StartSecs, v.stopThreshold, v.params.StopSecs)
 	}
 
+	// The calibration window is assumed to be non-speech
+	if v.params.AutoCalibrate && !v.calibrated {
+		windowSecs := float32(sampleCount) / float32(v.sampleRate)
+		if v.hopSamples > 0 && v.hopSamples < numFramesRequired {
+			windowSecs = float32(v.hopSamples) / float32(v.sampleRate)
+		}
+		v.calibrate(volume, voiceConfidence, windowSecs)
+		return v.state, nil
+	}
+
 	// Check if audio meets minimum volume threshold
 	if v.smoothedVolume < v.params.MinVolume {
 		// Log when voice is filtered due to low volume (helps diagnose VAD issues)
@@ -281,27 +450,20 @@ func (v *BaseVADAnalyzer) ProcessAudio(buffer []byte, voiceConfidence float32, n
 	return v.state, nil
 }
 
-// calculateVolume computes RMS volume from int16 audio buffer
-func (v *BaseVADAnalyzer) calculateVolume(buffer []byte) float32 {
-	if len(buffer) < 2 {
-		return 0.0
-	}
-
-	// Convert bytes to int16 samples
-	numSamples := len(buffer) / 2
-	var sumSquares float64
-
-	for i := 0; i < numSamples; i++ {
-		// Read little-endian int16
-		sample := int16(buffer[i*2]) | int16(buffer[i*2+1])<<8
-		// Normalize to [-1.0, 1.0]
-		normalized := float64(sample) / 32768.0
-		sumSquares += normalized * normalized
+// overlappingWindows returns how many consecutive windows of frameTime,
+// hopTime apart, span secs of audio: the first covers a whole window and each
+// further one adds a hop
+func overlappingWindows(secs, frameTime, hopTime float32) int {
+	if secs < frameTime {
+		return int(secs / frameTime)
 	}
+	// Nudge up so exact multiples don't truncate to one hop less
+	return int(float64(secs-frameTime)/float64(hopTime)+1e-6) + 1
+}
 
-	// RMS (Root Mean Square)
-	rms := math.Sqrt(sumSquares / float64(numSamples))
-	return float32(rms)
+// calculateVolume computes RMS volume from int16 audio buffer
+func (v *BaseVADAnalyzer) calculateVolume(buffer []byte) float32 {
+	return audio.CalculateVolume(buffer)
 }
 
 // VADProcessor is a frame processor that uses VAD to detect user speech
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/audio/turn"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -27,6 +28,10 @@ type VADInputProcessor struct {
 	audioBuffer []byte
 	bufferMu    sync.Mutex
 
+	// Overlapping windows (protected by bufferMu)
+	hopSize     int // Samples between window starts (0 = one window, non-overlapping)
+	analyzerHop int // Hop last passed to the analyzer
+
 	// VAD state tracking
 	currentState  VADState
 	previousState VADState
@@ -34,6 +39,22 @@ type VADInputProcessor struct {
 
 	// Current audio chunk for turn analyzer (16kHz resampled if needed)
 	currentAudioChunk []byte
+
+	// Speech segment tracking (protected by bufferMu)
+	emitSegments    bool
+	samplesAnalyzed int64         // Samples run through the analyzer since the stream began
+	voiceStart      time.Duration // Where the current voiced region began (onset included)
+	voiceEnd        time.Duration // Where trailing silence began while STOPPING
+	segmentSpeaking bool          // Current voiced region reached SPEAKING
+
+	// Pre-roll gating (protected by bufferMu)
+	preRoll     time.Duration        // Quiet audio retained for speech onset (0 = forward everything)
+	preRollBuf  []*frames.AudioFrame // Most recent quiet audio, oldest first
+	preRollSize time.Duration        // Duration held in preRollBuf
+
+	// Confidence stream (protected by bufferMu)
+	confidenceInterval time.Duration // Input audio between VADConfidenceFrames (0 = disabled)
+	confidenceElapsed  time.Duration // Audio analyzed since the last one
 }
 
 // NewVADInputProcessor creates a new VAD input processor
@@ -61,6 +82,62 @@ func NewVADInputProcessorWithTurn(analyzer VADAnalyzer, turnAnalyzer turn.TurnAn
 	return p
 }
 
+// SetEmitSpeechSegments enables SpeechSegmentFrames: one per contiguous
+// voiced region, pushed downstream once the region ends, for per-segment STT
+// commits or talk-time analytics. Disabled by default.
+func (p *VADInputProcessor) SetEmitSpeechSegments(enabled bool) {
+	p.bufferMu.Lock()
+	defer p.bufferMu.Unlock()
+	p.emitSegments = enabled
+}
+
+// SetEmitConfidence pushes a VADConfidenceFrame downstream for every interval
+// of input audio, carrying the confidence of the latest analysis window, for
+// external endpointing or analytics. Requires an analyzer that implements
+// ConfidenceReporter. 0 (the default) disables it.
+func (p *VADInputProcessor) SetEmitConfidence(interval time.Duration) {
+	if _, ok := p.analyzer.(ConfidenceReporter); !ok && interval > 0 {
+		logger.Warn("[VADInput] Analyzer %T does not report confidence, not emitting VADConfidenceFrames", p.analyzer)
+		return
+	}
+	p.bufferMu.Lock()
+	defer p.bufferMu.Unlock()
+	p.confidenceInterval = interval
+	p.confidenceElapsed = 0
+}
+
+// SetHopSize makes analysis windows overlap: each window starts samples after
+// the previous one and reuses the rest of it as context, so speech onsets
+// that straddle a window edge are caught up to a window earlier. Values of 0
+// (the default) or at least the analyzer's window size keep windows
+// back-to-back. Every overlapping window is a full analysis, so a hop of half
+// the window doubles inference cost. Stateful analyzers, such as Silero,
+// must see each sample once and don't support overlap.
+func (p *VADInputProcessor) SetHopSize(samples int) {
+	if s, ok := p.analyzer.(StatefulAnalyzer); ok && s.Stateful() && samples > 0 {
+		logger.Warn("[VADInput] Analyzer %T is stateful, not overlapping analysis windows", p.analyzer)
+		return
+	}
+	p.bufferMu.Lock()
+	defer p.bufferMu.Unlock()
+	p.hopSize = max(samples, 0)
+}
+
+// SetPreRoll holds audio back from STT while the user is quiet, keeping only
+// the most recent d. When VAD detects speech onset the held pre-roll is pushed
+// ahead of the live audio, so leading phonemes lost to VAD onset delay or a
+// lazily connecting STT service still reach it. 0 (the default) forwards all
+// audio downstream as it arrives.
+func (p *VADInputProcessor) SetPreRoll(d time.Duration) {
+	p.bufferMu.Lock()
+	defer p.bufferMu.Unlock()
+	p.preRoll = d
+	if d <= 0 {
+		p.preRollBuf = nil
+		p.preRollSize = 0
+	}
+}
+
 // HandleFrame processes frames from upstream (typically WebSocket input)
 func (p *VADInputProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	// Handle AudioFrame - accumulate and run VAD
@@ -78,6 +155,13 @@ func (p *VADInputProcessor) HandleFrame(ctx context.Context, frame frames.Frame,
 	// Handle EndFrame - reset VAD state
 	if _, ok := frame.(*frames.EndFrame); ok {
 		p.analyzer.Restart()
+		p.bufferMu.Lock()
+		p.samplesAnalyzed = 0
+		p.segmentSpeaking = false
+		p.preRollBuf = nil
+		p.preRollSize = 0
+		p.confidenceElapsed = 0
+		p.bufferMu.Unlock()
 		logger.Debug("[VADInput] EndFrame received, VAD state reset")
 	}
 
@@ -121,6 +205,19 @@ func (p *VADInputProcessor) handleAudioFrame(ctx context.Context, audioFrame *fr
 	numFramesRequired := p.analyzer.NumFramesRequired()
 	requiredBytes := numFramesRequired * 2 // int16 = 2 bytes per sample
 
+	// Each window advances by the hop, keeping the rest as context for the next
+	hopSamples := numFramesRequired
+	if p.hopSize > 0 && p.hopSize < numFramesRequired {
+		hopSamples = p.hopSize
+	}
+	hopBytes := hopSamples * 2
+	if hopSamples != p.analyzerHop {
+		if setter, ok := p.analyzer.(HopSizeSetter); ok {
+			setter.SetHopSize(hopSamples)
+		}
+		p.analyzerHop = hopSamples
+	}
+
 	// Process audio if we have enough samples
 	for len(p.audioBuffer) >= requiredBytes {
 		// Extract chunk for VAD analysis
@@ -141,14 +238,22 @@ func (p *VADInputProcessor) handleAudioFrame(ctx context.Context, audioFrame *fr
 		p.currentState = newState
 		p.stateMu.Unlock()
 
+		if p.emitSegments {
+			p.trackSpeechSegment(previousState, newState, hopSamples, audioFrame.SampleRate)
+		}
+		if p.confidenceInterval > 0 {
+			p.trackConfidence(hopSamples, audioFrame.SampleRate)
+		}
+
 		// Run turn analyzer if configured
 		if p.turnAnalyzer != nil {
 			isSpeech := newState == VADStateSpeaking || newState == VADStateStarting
 
-			// Feed audio to turn analyzer
+			// Feed audio to turn analyzer, each sample once: the hop leaving
+			// the buffer (the whole chunk when windows don't overlap)
 			// Note: If source is not 16kHz, audio should be resampled here
 			// For now, we pass the raw audio (works if source is 16kHz or close)
-			turnState := p.turnAnalyzer.AppendAudio(chunk, isSpeech)
+			turnState := p.turnAnalyzer.AppendAudio(chunk[:hopBytes], isSpeech)
 
 			// Emit UserStartedSpeakingFrame when VAD confirms speech (reaches SPEAKING state)
 			// We wait for SPEAKING (not STARTING) to avoid false triggers from brief voice blips
@@ -156,6 +261,7 @@ func (p *VADInputProcessor) handleAudioFrame(ctx context.Context, audioFrame *fr
 			if (previousState == VADStateQuiet || previousState == VADStateStarting) && newState == VADStateSpeaking {
 				logger.Info("[VADInput] 🎤 User started speaking (confirmed)")
 				userStartedFrame := frames.NewUserStartedSpeakingFrame()
+				userStartedFrame.Source = "vad"
 				if err := p.PushFrame(userStartedFrame, frames.Downstream); err != nil {
 					logger.Error("[VADInput] Failed to push UserStartedSpeakingFrame: %v", err)
 				}
@@ -183,16 +289,129 @@ func (p *VADInputProcessor) handleAudioFrame(ctx context.Context, audioFrame *fr
 			}
 		}
 
-		// Remove processed chunk from buffer
-		p.audioBuffer = p.audioBuffer[requiredBytes:]
+		// Remove the processed hop from the buffer
+		p.audioBuffer = p.audioBuffer[hopBytes:]
+	}
+
+	// With pre-roll, quiet audio is held rather than sent to STT
+	var preRoll []*frames.AudioFrame
+	if p.preRoll > 0 {
+		if p.GetCurrentState() == VADStateQuiet {
+			p.holdPreRoll(audioFrame)
+			p.bufferMu.Unlock()
+			return nil
+		}
+		preRoll = p.preRollBuf
+		p.preRollBuf = nil
+		p.preRollSize = 0
 	}
 
 	p.bufferMu.Unlock()
 
+	// Speech onset: release the pre-roll ahead of the audio that triggered it
+	if len(preRoll) > 0 {
+		logger.Debug("[VADInput] Speech onset, releasing %d pre-roll frames", len(preRoll))
+		for _, held := range preRoll {
+			if err := p.PushFrame(held, direction); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Always push audio frame downstream (STT needs all audio)
 	return p.PushFrame(audioFrame, direction)
 }
 
+// holdPreRoll adds a quiet frame to the pre-roll, dropping the oldest frames
+// once the rest still cover preRoll. Must be called with bufferMu held.
+func (p *VADInputProcessor) holdPreRoll(frame *frames.AudioFrame) {
+	p.preRollBuf = append(p.preRollBuf, frame)
+	p.preRollSize += audioFrameDuration(frame)
+	for len(p.preRollBuf) > 1 {
+		oldest := audioFrameDuration(p.preRollBuf[0])
+		if p.preRollSize-oldest < p.preRoll {
+			break
+		}
+		p.preRollBuf[0] = nil
+		p.preRollBuf = p.preRollBuf[1:]
+		p.preRollSize -= oldest
+	}
+}
+
+// audioFrameDuration returns the duration of a 16-bit PCM audio frame
+func audioFrameDuration(frame *frames.AudioFrame) time.Duration {
+	sampleRate := frame.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	channels := frame.Channels
+	if channels <= 0 {
+		channels = 1
+	}
+	return samplesToDuration(int64(len(frame.Data)/(2*channels)), sampleRate)
+}
+
+// trackSpeechSegment advances the segment clock by one analyzed chunk and
+// pushes a SpeechSegmentFrame when a voiced region that reached SPEAKING
+// returns to QUIET. The segment spans from the chunk where voice began
+// (including the STARTING onset) to the chunk where trailing silence began.
+// Must be called with bufferMu held.
+func (p *VADInputProcessor) trackSpeechSegment(prev, current VADState, samples, sampleRate int) {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	chunkStart := samplesToDuration(p.samplesAnalyzed, sampleRate)
+	p.samplesAnalyzed += int64(samples)
+
+	switch {
+	case prev == VADStateQuiet && (current == VADStateStarting || current == VADStateSpeaking):
+		p.voiceStart = chunkStart
+		p.segmentSpeaking = current == VADStateSpeaking
+	case current == VADStateSpeaking:
+		p.segmentSpeaking = true
+	case prev == VADStateSpeaking && (current == VADStateStopping || current == VADStateQuiet):
+		p.voiceEnd = chunkStart
+	}
+
+	if current != VADStateQuiet || !p.segmentSpeaking {
+		return
+	}
+	p.segmentSpeaking = false
+	segment := frames.NewSpeechSegmentFrame(p.voiceStart, p.voiceEnd)
+	logger.Debug("[VADInput] Speech segment %v-%v (%v)", segment.StartTime, segment.EndTime, segment.Duration())
+	if err := p.PushFrame(segment, frames.Downstream); err != nil {
+		logger.Error("[VADInput] Failed to push SpeechSegmentFrame: %v", err)
+	}
+}
+
+// trackConfidence advances the confidence clock by one analyzed chunk and
+// pushes a VADConfidenceFrame each time it passes confidenceInterval.
+// Must be called with bufferMu held.
+func (p *VADInputProcessor) trackConfidence(samples, sampleRate int) {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	p.confidenceElapsed += samplesToDuration(int64(samples), sampleRate)
+	if p.confidenceElapsed < p.confidenceInterval {
+		return
+	}
+	// At most one frame per window, even when windows outlast the interval
+	p.confidenceElapsed %= p.confidenceInterval
+
+	reporter, ok := p.analyzer.(ConfidenceReporter)
+	if !ok {
+		return
+	}
+	if err := p.PushFrame(frames.NewVADConfidenceFrame(reporter.LastConfidence()), frames.Downstream); err != nil {
+		logger.Error("[VADInput] Failed to push VADConfidenceFrame: %v", err)
+	}
+}
+
+// samplesToDuration converts a sample count at sampleRate to a duration
+func samplesToDuration(samples int64, sampleRate int) time.Duration {
+	return time.Duration(samples * int64(time.Second) / int64(sampleRate))
+}
+
 // runTurnAnalysis runs ML inference to determine if turn is complete
 func (p *VADInputProcessor) runTurnAnalysis() {
 	if p.turnAnalyzer == nil {
@@ -219,6 +438,7 @@ func (p *VADInputProcessor) runTurnAnalysis() {
 // emitUserStoppedSpeaking emits UserStoppedSpeakingFrame
 func (p *VADInputProcessor) emitUserStoppedSpeaking() {
 	userStoppedFrame := frames.NewUserStoppedSpeakingFrame()
+	userStoppedFrame.Source = "vad"
 	if err := p.PushFrame(userStoppedFrame, frames.Downstream); err != nil {
 		logger.Error("[VADInput] Failed to push UserStoppedSpeakingFrame: %v", err)
 	}
@@ -235,6 +455,7 @@ func (p *VADInputProcessor) emitStateTransitionFrames() error {
 	if (prev == VADStateQuiet || prev == VADStateStarting) && current == VADStateSpeaking {
 		logger.Info("[VADInput] 🎤 User started speaking")
 		userStartedFrame := frames.NewUserStartedSpeakingFrame()
+		userStartedFrame.Source = "vad"
 		if err := p.PushFrame(userStartedFrame, frames.Downstream); err != nil {
 			return fmt.Errorf("failed to push UserStartedSpeakingFrame: %w", err)
 		}
@@ -244,6 +465,7 @@ func (p *VADInputProcessor) emitStateTransitionFrames() error {
 	if (prev == VADStateSpeaking || prev == VADStateStopping) && current == VADStateQuiet {
 		logger.Info("[VADInput] 🔇 User stopped speaking")
 		userStoppedFrame := frames.NewUserStoppedSpeakingFrame()
+		userStoppedFrame.Source = "vad"
 		if err := p.PushFrame(userStoppedFrame, frames.Downstream); err != nil {
 			return fmt.Errorf("failed to push UserStoppedSpeakingFrame: %w", err)
 		}
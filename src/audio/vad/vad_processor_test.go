@@ -0,0 +1,341 @@
+package vad
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// captureProc records the frames pushed to it
+type captureProc struct {
+	mu     sync.Mutex
+	frames []frames.Frame
+}
+
+func (c *captureProc) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+func (c *captureProc) QueueFrame(f frames.Frame, _ frames.FrameDirection) error {
+	c.mu.Lock()
+	c.frames = append(c.frames, f)
+	c.mu.Unlock()
+	return nil
+}
+func (c *captureProc) PushFrame(frames.Frame, frames.FrameDirection) error { return nil }
+func (c *captureProc) Link(processors.FrameProcessor)                      {}
+func (c *captureProc) SetPrev(processors.FrameProcessor)                   {}
+func (c *captureProc) Start(context.Context) error                         { return nil }
+func (c *captureProc) Stop() error                                         { return nil }
+func (c *captureProc) Name() string                                        { return "capture" }
+
+func (c *captureProc) segments() []*frames.SpeechSegmentFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*frames.SpeechSegmentFrame
+	for _, f := range c.frames {
+		if segment, ok := f.(*frames.SpeechSegmentFrame); ok {
+			out = append(out, segment)
+		}
+	}
+	return out
+}
+
+// feedAudio sends seconds of audio from gen in 20ms frames, continuing at
+// sample offset, and returns the new offset
+func feedAudio(t *testing.T, p *VADInputProcessor, seconds float64, offset int, gen func(n, offset int) []byte) int {
+	t.Helper()
+	const sampleRate, frameSize = 16000, 320
+	total := int(seconds * sampleRate)
+	for i := 0; i < total; i += frameSize {
+		frame := frames.NewAudioFrame(gen(frameSize, offset+i), sampleRate, 1)
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+	}
+	return offset + total
+}
+
+func TestVADInputProcessor_EmitsSpeechSegments(t *testing.T) {
+	// No volume gate, so voice registers from its first window
+	params := DefaultVADParams()
+	params.MinVolume = 0
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, params, EnergyVADConfig{}))
+	p.SetEmitSpeechSegments(true)
+	capture := &captureProc{}
+	p.Link(capture)
+
+	speech := func(n, offset int) []byte { return voiceBuffer(16000, n, offset) }
+	silence := func(n, offset int) []byte { return make([]byte, n*2) }
+
+	offset := feedAudio(t, p, 0.6, 0, speech)
+	offset = feedAudio(t, p, 0.6, offset, silence)
+	offset = feedAudio(t, p, 0.4, offset, speech)
+	feedAudio(t, p, 0.6, offset, silence)
+
+	segments := capture.segments()
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 speech segments, got %d", len(segments))
+	}
+
+	const tolerance = 20 * time.Millisecond // One analysis window
+	for i, want := range []struct{ start, end time.Duration }{
+		{0, 600 * time.Millisecond},
+		{1200 * time.Millisecond, 1600 * time.Millisecond},
+	} {
+		got := segments[i]
+		if d := got.StartTime - want.start; d < -tolerance || d > tolerance {
+			t.Errorf("segment %d start = %v, want ~%v", i, got.StartTime, want.start)
+		}
+		if d := got.EndTime - want.end; d < -tolerance || d > tolerance {
+			t.Errorf("segment %d end = %v, want ~%v", i, got.EndTime, want.end)
+		}
+		if wantDur := want.end - want.start; got.Duration() < wantDur-tolerance || got.Duration() > wantDur+tolerance {
+			t.Errorf("segment %d duration = %v, want ~%v", i, got.Duration(), wantDur)
+		}
+	}
+}
+
+func TestVADInputProcessor_SpeechSegmentsDisabledByDefault(t *testing.T) {
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{}))
+	capture := &captureProc{}
+	p.Link(capture)
+
+	offset := feedAudio(t, p, 0.6, 0, func(n, offset int) []byte { return voiceBuffer(16000, n, offset) })
+	feedAudio(t, p, 0.6, offset, func(n, offset int) []byte { return make([]byte, n*2) })
+
+	if segments := capture.segments(); len(segments) != 0 {
+		t.Errorf("expected no speech segments when disabled, got %d", len(segments))
+	}
+}
+
+func (c *captureProc) confidences() []float32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []float32
+	for _, f := range c.frames {
+		if confidence, ok := f.(*frames.VADConfidenceFrame); ok {
+			out = append(out, confidence.Confidence)
+		}
+	}
+	return out
+}
+
+func TestVADInputProcessor_EmitsConfidenceAtCadence(t *testing.T) {
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{}))
+	p.SetEmitConfidence(100 * time.Millisecond)
+	capture := &captureProc{}
+	p.Link(capture)
+
+	offset := feedAudio(t, p, 0.5, 0, func(n, offset int) []byte { return voiceBuffer(16000, n, offset) })
+	speech := capture.confidences()
+	feedAudio(t, p, 0.5, offset, func(n, offset int) []byte { return make([]byte, n*2) })
+	all := capture.confidences()
+
+	// One frame per 100ms of audio, not one per 20ms analysis window
+	if len(speech) != 5 || len(all) != 10 {
+		t.Fatalf("expected 5 confidence frames per 500ms, got %d then %d", len(speech), len(all)-len(speech))
+	}
+	for i, c := range speech {
+		if c < 0.7 || c > 1 {
+			t.Errorf("speech frame %d: confidence %.3f, want >= 0.7", i, c)
+		}
+	}
+	for i, c := range all[len(speech):] {
+		if c != 0 {
+			t.Errorf("silence frame %d: confidence %.3f, want 0", i, c)
+		}
+	}
+}
+
+func TestVADInputProcessor_ConfidenceDisabledByDefault(t *testing.T) {
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{}))
+	capture := &captureProc{}
+	p.Link(capture)
+
+	feedAudio(t, p, 0.5, 0, func(n, offset int) []byte { return voiceBuffer(16000, n, offset) })
+	if n := len(capture.confidences()); n != 0 {
+		t.Errorf("expected no confidence frames when disabled, got %d", n)
+	}
+}
+
+// audioFrames returns the audio frames pushed downstream, in order
+func (c *captureProc) audioFrames() []*frames.AudioFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*frames.AudioFrame
+	for _, f := range c.frames {
+		if audioFrame, ok := f.(*frames.AudioFrame); ok {
+			out = append(out, audioFrame)
+		}
+	}
+	return out
+}
+
+func TestVADInputProcessor_PreRollPrecedesSpeech(t *testing.T) {
+	params := DefaultVADParams()
+	params.MinVolume = 0
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, params, EnergyVADConfig{}))
+	p.SetPreRoll(200 * time.Millisecond)
+	capture := &captureProc{}
+	p.Link(capture)
+
+	// 0.5s of silence then 0.4s of speech, in 20ms frames
+	var fed []*frames.AudioFrame
+	for i := 0; i < 45; i++ {
+		data := make([]byte, 640)
+		if i >= 25 {
+			data = voiceBuffer(16000, 320, i*320)
+		}
+		frame := frames.NewAudioFrame(data, 16000, 1)
+		fed = append(fed, frame)
+		if err := p.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+		if i == 24 {
+			if n := len(capture.audioFrames()); n != 0 {
+				t.Fatalf("expected quiet audio to be held, got %d frames downstream", n)
+			}
+		}
+	}
+
+	// The last 200ms of silence precedes the speech, with nothing dropped
+	// from the onset on
+	want := fed[15:]
+	got := capture.audioFrames()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d audio frames (10 pre-roll + 20 speech), got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("audio frame %d out of order", i)
+		}
+	}
+}
+
+func TestVADInputProcessor_NoPreRollForwardsAllAudio(t *testing.T) {
+	p := NewVADInputProcessor(NewEnergyVADAnalyzer(16000, DefaultVADParams(), EnergyVADConfig{}))
+	capture := &captureProc{}
+	p.Link(capture)
+
+	feedAudio(t, p, 0.4, 0, func(n, offset int) []byte { return make([]byte, n*2) })
+
+	if n := len(capture.audioFrames()); n != 20 {
+		t.Errorf("expected all 20 quiet frames forwarded without pre-roll, got %d", n)
+	}
+}
+
+// fractionVADAnalyzer scores a window by the fraction of its samples that
+// are non-zero, so onsets are exact to the sample
+type fractionVADAnalyzer struct {
+	*BaseVADAnalyzer
+}
+
+func (v *fractionVADAnalyzer) NumFramesRequired() int { return 512 }
+
+func (v *fractionVADAnalyzer) VoiceConfidence(buffer []byte) float32 {
+	voiced := 0
+	for i := 0; i+1 < len(buffer); i += 2 {
+		if buffer[i] != 0 || buffer[i+1] != 0 {
+			voiced++
+		}
+	}
+	return float32(voiced) / float32(len(buffer)/2)
+}
+
+func (v *fractionVADAnalyzer) AnalyzeAudio(buffer []byte) (VADState, error) {
+	return v.ProcessAudio(buffer, v.VoiceConfidence(buffer), v.NumFramesRequired())
+}
+
+// speakingOnsetDelay feeds silence then constant voice from onset in 4ms
+// frames and returns how many samples after the onset the processor
+// reported UserStartedSpeaking
+func speakingOnsetDelay(t *testing.T, hopSize, onset int) int {
+	t.Helper()
+	params := DefaultVADParams()
+	params.MinVolume = 0
+	params.StartSecs = 0.064 // Two 32ms windows
+	p := NewVADInputProcessor(&fractionVADAnalyzer{NewBaseVADAnalyzer(16000, params)})
+	p.SetHopSize(hopSize)
+	capture := &captureProc{}
+	p.Link(capture)
+
+	const frameSize = 64
+	for offset := 0; offset < 16000; offset += frameSize {
+		buf := make([]byte, frameSize*2)
+		for i := 0; i < frameSize; i++ {
+			if offset+i >= onset {
+				buf[i*2] = 0x10
+			}
+		}
+		if err := p.HandleFrame(context.Background(), frames.NewAudioFrame(buf, 16000, 1), frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+
+		capture.mu.Lock()
+		for _, f := range capture.frames {
+			if _, ok := f.(*frames.UserStartedSpeakingFrame); ok {
+				capture.mu.Unlock()
+				return offset + frameSize - onset
+			}
+		}
+		capture.mu.Unlock()
+	}
+	t.Fatal("never reported UserStartedSpeaking")
+	return 0
+}
+
+func TestVADInputProcessor_OverlappingWindowsDetectOnsetSooner(t *testing.T) {
+	// An onset well into a window leaves that window mostly quiet, so
+	// back-to-back windows only see it a whole window later
+	const onset = 700
+	nonOverlapping := speakingOnsetDelay(t, 0, onset)
+	overlapping := speakingOnsetDelay(t, 128, onset)
+
+	if overlapping >= nonOverlapping {
+		t.Fatalf("expected overlapping windows to report speech sooner, got %d samples vs %d non-overlapping",
+			overlapping, nonOverlapping)
+	}
+	// Still needs more than a window of voice: the onset isn't reported early
+	if overlapping < 512 {
+		t.Fatalf("overlapping windows reported speech after only %d samples of voice", overlapping)
+	}
+}
+
+func TestVADInputProcessor_HopSizeAtLeastWindowDoesNotOverlap(t *testing.T) {
+	const onset = 600
+	if a, b := speakingOnsetDelay(t, 0, onset), speakingOnsetDelay(t, 512, onset); a != b {
+		t.Fatalf("expected a hop of one window to match non-overlapping windows, got %d and %d", b, a)
+	}
+}
+
+// statefulVADAnalyzer stands in for a recurrent model such as Silero and
+// counts the windows it analyzes
+type statefulVADAnalyzer struct {
+	*fractionVADAnalyzer
+	analyzed int
+}
+
+func (v *statefulVADAnalyzer) Stateful() bool { return true }
+
+func (v *statefulVADAnalyzer) AnalyzeAudio(buffer []byte) (VADState, error) {
+	v.analyzed++
+	return v.fractionVADAnalyzer.AnalyzeAudio(buffer)
+}
+
+func TestVADInputProcessor_StatefulAnalyzerDoesNotOverlap(t *testing.T) {
+	analyzer := &statefulVADAnalyzer{fractionVADAnalyzer: &fractionVADAnalyzer{NewBaseVADAnalyzer(16000, DefaultVADParams())}}
+	p := NewVADInputProcessor(analyzer)
+	p.SetHopSize(128)
+
+	// Each sample is analyzed once: 4 windows of audio, 4 analyses
+	if err := p.HandleFrame(context.Background(), frames.NewAudioFrame(make([]byte, 4*512*2), 16000, 1), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+	if analyzer.analyzed != 4 {
+		t.Fatalf("expected 4 back-to-back windows for a stateful analyzer, got %d", analyzer.analyzed)
+	}
+}
@@ -76,6 +76,22 @@ func NewTTSStoppedFrame() *TTSStoppedFrame {
 	}
 }
 
+// TTSFlushFrame asks the TTS service to synthesize any buffered text and
+// have the provider generate the audio it is still holding for the current
+// context, without ending the response the way LLMFullResponseEndFrame does.
+// For apps that push text to TTS directly rather than from an LLM.
+type TTSFlushFrame struct {
+	*ControlFrame
+}
+
+func NewTTSFlushFrame() *TTSFlushFrame {
+	return &TTSFlushFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("TTSFlushFrame"),
+		},
+	}
+}
+
 // PlaybackCompleteFrame signals that the client has finished playing audio.
 // Emitted when the transport receives a client-side playback acknowledgement
 // (e.g., Twilio "mark" echo or Asterisk "QUEUE_DRAINED"), not on server buffer drain.
@@ -110,13 +126,14 @@ func NewHeartbeatFrame() *HeartbeatFrame {
 // Deprecated: Use BroadcastInterruption() on BaseProcessor instead. Will be removed in a future version.
 type InterruptionTaskFrame struct {
 	*ControlFrame
+	CorrelationID string // Carried over to the InterruptionFrame the task generates
 }
 
 func NewInterruptionTaskFrame() *InterruptionTaskFrame {
+	base := NewBaseFrame("InterruptionTaskFrame")
 	return &InterruptionTaskFrame{
-		ControlFrame: &ControlFrame{
-			BaseFrame: NewBaseFrame("InterruptionTaskFrame"),
-		},
+		ControlFrame:  &ControlFrame{BaseFrame: base},
+		CorrelationID: newInterruptionID(base),
 	}
 }
 
@@ -135,6 +152,25 @@ func NewLLMContextFrame(context interface{}) *LLMContextFrame {
 	}
 }
 
+// AssistantResponseCompleteFrame is pushed by the assistant aggregator once
+// per LLM response, after the response has been added to the context. It is
+// pushed even when the response had no content and nothing was stored.
+type AssistantResponseCompleteFrame struct {
+	*ControlFrame
+	Text      string    // Assistant message added to the context ("" if none)
+	Timestamp time.Time // When the response completed
+}
+
+func NewAssistantResponseCompleteFrame(text string) *AssistantResponseCompleteFrame {
+	return &AssistantResponseCompleteFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("AssistantResponseCompleteFrame"),
+		},
+		Text:      text,
+		Timestamp: time.Now(),
+	}
+}
+
 type LLMSummarizeContextFrame struct {
 	*ControlFrame
 }
@@ -164,11 +200,14 @@ func NewLLMMessagesAppendFrame(messages interface{}, runLLM bool) *LLMMessagesAp
 	}
 }
 
-// LLMMessagesUpdateFrame replaces all messages in the context
+// LLMMessagesUpdateFrame replaces all messages in the context.
+// If SystemPrompt is set it also replaces the active system prompt; a nil
+// Messages leaves the conversation history untouched.
 type LLMMessagesUpdateFrame struct {
 	*ControlFrame
-	Messages interface{} // []services.LLMMessage
-	RunLLM   bool
+	Messages     interface{} // []services.LLMMessage
+	SystemPrompt string
+	RunLLM       bool
 }
 
 func NewLLMMessagesUpdateFrame(messages interface{}, runLLM bool) *LLMMessagesUpdateFrame {
@@ -181,6 +220,40 @@ func NewLLMMessagesUpdateFrame(messages interface{}, runLLM bool) *LLMMessagesUp
 	}
 }
 
+// NewLLMSystemPromptUpdateFrame creates an update that only swaps the system
+// prompt, keeping the conversation history
+func NewLLMSystemPromptUpdateFrame(systemPrompt string) *LLMMessagesUpdateFrame {
+	return &LLMMessagesUpdateFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("LLMMessagesUpdateFrame"),
+		},
+		SystemPrompt: systemPrompt,
+	}
+}
+
+// LLMParamsFrame overrides sampling parameters for the LLM's next generation
+// only, e.g. a temperature of 0 while reading back a confirmation number.
+// The service's configured parameters apply again from the generation after.
+// Nil fields keep the configured value; several frames before a generation
+// combine, later ones winning.
+type LLMParamsFrame struct {
+	*ControlFrame
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   *int
+}
+
+func NewLLMParamsFrame(temperature, topP *float64, maxTokens *int) *LLMParamsFrame {
+	return &LLMParamsFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("LLMParamsFrame"),
+		},
+		Temperature: temperature,
+		TopP:        topP,
+		MaxTokens:   maxTokens,
+	}
+}
+
 // FunctionCallInfo describes a function call being initiated
 type FunctionCallInfo struct {
 	ToolCallID   string
@@ -202,6 +275,31 @@ func NewFunctionCallsStartedFrame(calls []FunctionCallInfo) *FunctionCallsStarte
 	}
 }
 
+// FunctionCallArgsDeltaFrame carries a fragment of a function call's
+// arguments as the LLM streams them, so UIs can preview the call live
+// ("get_weather(city: San..."). Arguments is the partial JSON received so
+// far. The function only runs once the complete FunctionCallInProgressFrame
+// follows.
+type FunctionCallArgsDeltaFrame struct {
+	*ControlFrame
+	ToolCallID   string
+	FunctionName string
+	Delta        string
+	Arguments    string
+}
+
+func NewFunctionCallArgsDeltaFrame(toolCallID, functionName, delta, arguments string) *FunctionCallArgsDeltaFrame {
+	return &FunctionCallArgsDeltaFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("FunctionCallArgsDeltaFrame"),
+		},
+		ToolCallID:   toolCallID,
+		FunctionName: functionName,
+		Delta:        delta,
+		Arguments:    arguments,
+	}
+}
+
 // FunctionCallInProgressFrame indicates a function is being executed
 type FunctionCallInProgressFrame struct {
 	*ControlFrame
@@ -290,3 +388,104 @@ func NewUserIdleTimeoutUpdateFrame(timeout time.Duration) *UserIdleTimeoutUpdate
 		Timeout: timeout,
 	}
 }
+
+// SetVoiceFrame switches the TTS voice at runtime (e.g., a different persona per topic).
+// TTS services finish the in-progress context with the old voice and apply the new
+// voice, model and settings to subsequent synthesis. Empty fields are left unchanged.
+// Settings keys are provider-specific (e.g., "speed", "volume", "stability").
+type SetVoiceFrame struct {
+	*ControlFrame
+	VoiceID  string
+	Model    string
+	Settings map[string]interface{}
+}
+
+func NewSetVoiceFrame(voiceID, model string, settings map[string]interface{}) *SetVoiceFrame {
+	return &SetVoiceFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("SetVoiceFrame"),
+		},
+		VoiceID:  voiceID,
+		Model:    model,
+		Settings: settings,
+	}
+}
+
+// TTSControlFrame adjusts TTS delivery mid-conversation (e.g., slow down when
+// the user can't follow). Zero values leave the current setting unchanged.
+type TTSControlFrame struct {
+	*ControlFrame
+	Volume  float64 // Volume multiplier
+	Speed   float64 // Speed multiplier
+	Emotion string  // Emotion guidance (e.g., "neutral", "calm")
+}
+
+func NewTTSControlFrame(volume, speed float64, emotion string) *TTSControlFrame {
+	return &TTSControlFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("TTSControlFrame"),
+		},
+		Volume:  volume,
+		Speed:   speed,
+		Emotion: emotion,
+	}
+}
+
+// DuckFrame starts or ends ducking of TTS audio (e.g., while a backchannel or
+// hold music plays over the bot). AttenuationDB overrides the processor's
+// configured duck level when non-zero.
+type DuckFrame struct {
+	*ControlFrame
+	Duck          bool
+	AttenuationDB float64
+}
+
+func NewDuckFrame(duck bool, attenuationDB float64) *DuckFrame {
+	return &DuckFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("DuckFrame"),
+		},
+		Duck:          duck,
+		AttenuationDB: attenuationDB,
+	}
+}
+
+// TransferFrame hands the call to another party (e.g. a human agent). The
+// output transport stops the bot's audio and runs its transfer handler (see
+// transports.WebSocketTransport.OnTransfer); transports without one ignore
+// it with a warning.
+type TransferFrame struct {
+	*ControlFrame
+	Destination string // Phone number, SIP URI or extension to transfer to
+}
+
+func NewTransferFrame(destination string) *TransferFrame {
+	return &TransferFrame{
+		ControlFrame: &ControlFrame{
+			BaseFrame: NewBaseFrame("TransferFrame"),
+		},
+		Destination: destination,
+	}
+}
+
+// FloatSetting returns a numeric setting, accepting any Go numeric type
+func (f *SetVoiceFrame) FloatSetting(key string) (float64, bool) {
+	switch v := f.Settings[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// StringSetting returns a string setting
+func (f *SetVoiceFrame) StringSetting(key string) (string, bool) {
+	v, ok := f.Settings[key].(string)
+	return v, ok
+}
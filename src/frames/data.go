@@ -18,6 +18,10 @@ type TextFrame struct {
 	// SkipTTS mirrors LLMTextFrame.SkipTTS — set by SentenceAggregator when
 	// the source LLMTextFrame had SkipTTS=true.
 	SkipTTS bool
+	// SkipContext, when true, keeps the assistant aggregator from storing
+	// the text in context while TTS still speaks it (e.g. function-call
+	// progress updates).
+	SkipContext bool
 }
 
 func NewTextFrame(text string) *TextFrame {
@@ -55,6 +59,15 @@ type TranscriptionFrame struct {
 	IsFinal   bool
 	Language  string
 	Timestamp time.Time
+
+	// AudioStart and AudioDuration place a final segment in the STT's audio
+	// stream, when the STT reports it (zero otherwise)
+	AudioStart    time.Duration
+	AudioDuration time.Duration
+
+	// SpeechFinal marks the last final segment of an utterance (Deepgram's
+	// speech_final); the next segment starts a new utterance
+	SpeechFinal bool
 }
 
 func NewTranscriptionFrame(text string, isFinal bool) *TranscriptionFrame {
@@ -114,6 +127,129 @@ func NewTTSAudioFrame(data []byte, sampleRate, channels int) *TTSAudioFrame {
 	}
 }
 
+// AudioLevelFrame carries a smoothed audio level reading for VU meters.
+// RMS and Peak are normalized to [0, 1].
+type AudioLevelFrame struct {
+	*DataFrame
+	RMS    float32
+	Peak   float32
+	Source string // "user" for inbound audio, "bot" for outbound TTS audio
+}
+
+func NewAudioLevelFrame(rms, peak float32, source string) *AudioLevelFrame {
+	return &AudioLevelFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("AudioLevelFrame"),
+		},
+		RMS:    rms,
+		Peak:   peak,
+		Source: source,
+	}
+}
+
+// AudioQualityFrame reports simple quality indicators for one window of
+// audio. Ratios are fractions in [0, 1]; DCOffset and RMS are normalized to
+// full scale. Warnings lists the thresholds crossed, empty when none were.
+type AudioQualityFrame struct {
+	*DataFrame
+	Source        string // "user" for inbound audio, "bot" for outbound TTS audio
+	Duration      time.Duration
+	ClippingRatio float64 // Samples at full scale
+	SilenceRatio  float64 // Audio below the silence level
+	DCOffset      float64 // Mean sample value
+	RMS           float64 // Level of the non-silent audio
+	Warnings      []string
+}
+
+func NewAudioQualityFrame(source string) *AudioQualityFrame {
+	return &AudioQualityFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("AudioQualityFrame"),
+		},
+		Source: source,
+	}
+}
+
+// AudioSendStatsFrame reports an output transport's outbound audio chunk
+// counters. It is pushed upstream whenever chunks never reached the client
+// (interruption, lateness, the paused buffer cap), found as a gap in the
+// sequence of chunks sent. Counts are totals for the connection.
+type AudioSendStatsFrame struct {
+	*DataFrame
+	Sent        int64  // Chunks written to the client
+	Dropped     int64  // Chunks skipped between sent ones
+	Gaps        int64  // Runs of consecutive skipped chunks
+	LateDropped int64  // Chunks skipped for exceeding MaxChunkAge
+	LastSeq     uint64 // Sequence number of the last chunk sent
+	Skipped     int64  // Chunks skipped in the gap that triggered this report
+}
+
+func NewAudioSendStatsFrame() *AudioSendStatsFrame {
+	return &AudioSendStatsFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("AudioSendStatsFrame"),
+		},
+	}
+}
+
+// SpeechSegmentFrame marks one contiguous voiced region detected by VAD.
+// Times are offsets into the input audio stream, measured in samples
+// analyzed, so they stay accurate however audio is batched or delayed.
+type SpeechSegmentFrame struct {
+	*DataFrame
+	StartTime time.Duration // First voiced audio of the segment
+	EndTime   time.Duration // First silent audio after the segment
+}
+
+func NewSpeechSegmentFrame(startTime, endTime time.Duration) *SpeechSegmentFrame {
+	return &SpeechSegmentFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("SpeechSegmentFrame"),
+		},
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// Duration returns the segment's talk time
+func (f *SpeechSegmentFrame) Duration() time.Duration {
+	return f.EndTime - f.StartTime
+}
+
+// VADConfidenceFrame carries the VAD's voice confidence for the most recent
+// analysis window, for external endpointing or analytics. Emitted at a fixed
+// cadence of input audio when enabled on the VADInputProcessor.
+type VADConfidenceFrame struct {
+	*DataFrame
+	Confidence float32 // Raw model confidence in [0.0, 1.0], before the volume gate
+}
+
+func NewVADConfidenceFrame(confidence float32) *VADConfidenceFrame {
+	return &VADConfidenceFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("VADConfidenceFrame"),
+		},
+		Confidence: confidence,
+	}
+}
+
+// ForceSpeakFrame makes the bot say Text even while the user is talking,
+// e.g. a time-critical alert. The user aggregator passes the text on to TTS
+// as a TextFrame and ignores barge-in until the bot has finished speaking it.
+type ForceSpeakFrame struct {
+	*DataFrame
+	Text string
+}
+
+func NewForceSpeakFrame(text string) *ForceSpeakFrame {
+	return &ForceSpeakFrame{
+		DataFrame: &DataFrame{
+			BaseFrame: NewBaseFrame("ForceSpeakFrame"),
+		},
+		Text: text,
+	}
+}
+
 // STTMetadataFrame carries STT service metadata for auto-tuning turn detection
 type STTMetadataFrame struct {
 	*DataFrame
@@ -1,6 +1,10 @@
 package frames
 
-import "github.com/square-key-labs/strawgo-ai/src/turns"
+import (
+	"strconv"
+
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
 
 // SystemFrame is the base for all system-level frames
 type SystemFrame struct {
@@ -11,11 +15,52 @@ func (f *SystemFrame) Category() FrameCategory {
 	return SystemCategory
 }
 
+// InterruptionLLMPolicy chooses what an interruption does to an LLM
+// response that is still being generated
+type InterruptionLLMPolicy int
+
+const (
+	// InterruptionLLMDefault leaves the policy unset: a StartFrame carrying
+	// it doesn't change the policy a processor adopted, and one that never
+	// adopted a policy aborts
+	InterruptionLLMDefault InterruptionLLMPolicy = iota
+	// InterruptionLLMAbort cancels the generation
+	InterruptionLLMAbort
+	// InterruptionLLMCompleteSilently lets the generation finish so the full
+	// response is recorded in the context, but nothing more of it is pushed
+	// to TTS or the client
+	InterruptionLLMCompleteSilently
+)
+
+// SilentResponseKey is the metadata key set to true on the
+// LLMFullResponseEndFrame of a response completed silently after an
+// interruption
+const SilentResponseKey = "silent_response"
+
 // StartFrame signals the beginning of pipeline execution
 type StartFrame struct {
 	*SystemFrame
 	AllowInterruptions bool
 	TurnStrategies     turns.UserTurnStrategies
+
+	// InterruptionLLMPolicy applies to LLM services; a StartFrame leaving it
+	// at InterruptionLLMDefault (such as a transport's per-call one) doesn't
+	// override a policy already adopted
+	InterruptionLLMPolicy InterruptionLLMPolicy
+
+	// Negotiated input media format and call identity, set by the transport
+	// or serializer on connect. Zero values mean unknown; arbitrary extras
+	// still travel in the metadata map.
+	SampleRate int    // Input sample rate in Hz
+	Codec      string // Input codec: "mulaw", "alaw" or "linear16"
+	Channels   int
+	CallID     string // Provider call identifier (e.g., Twilio CallSid)
+	Locale     string // Caller locale as a BCP-47 tag (e.g., "en-US")
+
+	// TemplateVars are variables for greeting, filler and fallback text
+	// templates (e.g., {{.CallerNumber}}), from the pipeline task config or
+	// the call's custom parameters
+	TemplateVars map[string]string
 }
 
 func NewStartFrame() *StartFrame {
@@ -39,6 +84,16 @@ func NewStartFrameWithConfig(allowInterruptions bool, strategies turns.UserTurnS
 	}
 }
 
+// MediaCodec returns the negotiated input codec, falling back to the "codec"
+// metadata key set by older serializers
+func (f *StartFrame) MediaCodec() string {
+	if f.Codec != "" {
+		return f.Codec
+	}
+	codec, _ := f.Metadata()["codec"].(string)
+	return codec
+}
+
 // EndFrame signals graceful shutdown after flushing all frames
 type EndFrame struct {
 	*SystemFrame
@@ -65,15 +120,94 @@ func NewCancelFrame() *CancelFrame {
 	}
 }
 
+// InterruptionCause identifies what triggered an interruption
+type InterruptionCause string
+
+const (
+	// InterruptionCauseUnspecified is an interruption from the application,
+	// a service or another non-speech source
+	InterruptionCauseUnspecified InterruptionCause = ""
+	// InterruptionCauseUserSpeech is a barge-in: the user started speaking
+	// over the bot and is still talking
+	InterruptionCauseUserSpeech InterruptionCause = "user_speech"
+)
+
+// InterruptionStage is a step in handling one interruption. Processors
+// report stages with the interruption's CorrelationID so its lifecycle can be
+// followed across the pipeline (see processors.BaseProcessor.TraceInterruption).
+type InterruptionStage string
+
+const (
+	// InterruptionStageDetected: a processor decided to interrupt
+	InterruptionStageDetected InterruptionStage = "detected"
+	// InterruptionStageTaskPushed: an InterruptionTaskFrame was pushed
+	// upstream for the task to convert
+	InterruptionStageTaskPushed InterruptionStage = "task_pushed"
+	// InterruptionStageFrameGenerated: the InterruptionFrame was created
+	InterruptionStageFrameGenerated InterruptionStage = "frame_generated"
+	// InterruptionStageTTSCancelled: a TTS service cancelled its synthesis
+	InterruptionStageTTSCancelled InterruptionStage = "tts_cancelled"
+	// InterruptionStageSTTFinalized: an STT service finalized or reset its
+	// stream
+	InterruptionStageSTTFinalized InterruptionStage = "stt_finalized"
+	// InterruptionStageOutputDrained: the output transport dropped its queued
+	// audio and told the client to flush
+	InterruptionStageOutputDrained InterruptionStage = "output_drained"
+	// InterruptionStageDrainConfirmed: the client confirmed its playout queue
+	// was flushed
+	InterruptionStageDrainConfirmed InterruptionStage = "drain_confirmed"
+)
+
 // InterruptionFrame signals user interrupted bot (e.g., started speaking)
 type InterruptionFrame struct {
 	*SystemFrame
+	Cause InterruptionCause // What triggered the interruption
+
+	// CorrelationID identifies the interruption across its lifecycle. Both
+	// frames of a broadcast pair, and a frame converted from an
+	// InterruptionTaskFrame, carry the ID of the original.
+	CorrelationID string
 }
 
 func NewInterruptionFrame() *InterruptionFrame {
+	return NewInterruptionFrameWithCause(InterruptionCauseUnspecified)
+}
+
+// NewInterruptionFrameWithCause creates an InterruptionFrame recording what
+// triggered it
+func NewInterruptionFrameWithCause(cause InterruptionCause) *InterruptionFrame {
+	base := NewBaseFrame("InterruptionFrame")
 	return &InterruptionFrame{
+		SystemFrame:   &SystemFrame{BaseFrame: base},
+		Cause:         cause,
+		CorrelationID: newInterruptionID(base),
+	}
+}
+
+// newInterruptionID derives a correlation ID from the frame that starts an
+// interruption
+func newInterruptionID(base *BaseFrame) string {
+	return "int-" + strconv.FormatUint(base.ID(), 10)
+}
+
+// IsUserSpeech reports whether the interruption was a user barge-in
+func (f *InterruptionFrame) IsUserSpeech() bool {
+	return f.Cause == InterruptionCauseUserSpeech
+}
+
+// LLMCancelGenerationFrame is pushed upstream to stop the LLM's in-flight
+// response without interrupting the bot. Unlike an InterruptionFrame it
+// leaves already generated text and audio to play out; use it when a
+// downstream processor has all of the response it will use. It is a system
+// frame so it reaches the LLM while it is still streaming.
+type LLMCancelGenerationFrame struct {
+	*SystemFrame
+}
+
+func NewLLMCancelGenerationFrame() *LLMCancelGenerationFrame {
+	return &LLMCancelGenerationFrame{
 		SystemFrame: &SystemFrame{
-			BaseFrame: NewBaseFrame("InterruptionFrame"),
+			BaseFrame: NewBaseFrame("LLMCancelGenerationFrame"),
 		},
 	}
 }
@@ -96,6 +230,7 @@ func NewErrorFrame(err error) *ErrorFrame {
 // UserStartedSpeakingFrame signals VAD detected user speech
 type UserStartedSpeakingFrame struct {
 	*SystemFrame
+	Source string // Detector that emitted it: "vad" or the STT service; empty if unknown
 }
 
 func NewUserStartedSpeakingFrame() *UserStartedSpeakingFrame {
@@ -109,6 +244,7 @@ func NewUserStartedSpeakingFrame() *UserStartedSpeakingFrame {
 // UserStoppedSpeakingFrame signals VAD detected end of user speech
 type UserStoppedSpeakingFrame struct {
 	*SystemFrame
+	Source string // Detector that emitted it: "vad" or the STT service; empty if unknown
 }
 
 func NewUserStoppedSpeakingFrame() *UserStoppedSpeakingFrame {
@@ -198,3 +334,32 @@ func NewBotConnectedFrame() *BotConnectedFrame {
 		},
 	}
 }
+
+// FlowControlPauseFrame asks the output transport to stop sending audio
+// because the client's playback buffer is full (e.g., Asterisk MEDIA_XOFF).
+// It is a system frame so it overtakes queued audio on its way to the output.
+type FlowControlPauseFrame struct {
+	*SystemFrame
+}
+
+func NewFlowControlPauseFrame() *FlowControlPauseFrame {
+	return &FlowControlPauseFrame{
+		SystemFrame: &SystemFrame{
+			BaseFrame: NewBaseFrame("FlowControlPauseFrame"),
+		},
+	}
+}
+
+// FlowControlResumeFrame tells the output transport the client can accept
+// audio again (e.g., Asterisk MEDIA_XON)
+type FlowControlResumeFrame struct {
+	*SystemFrame
+}
+
+func NewFlowControlResumeFrame() *FlowControlResumeFrame {
+	return &FlowControlResumeFrame{
+		SystemFrame: &SystemFrame{
+			BaseFrame: NewBaseFrame("FlowControlResumeFrame"),
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -51,6 +52,9 @@ type Logger struct {
 	prefix        string
 	stdLogger     *log.Logger
 	enabledLevels map[LogLevel]bool
+
+	ctxMu   sync.RWMutex // protects context, which is per logger (not shared)
+	context string       // Rendered "key=value" fields, e.g. call/stream SIDs
 }
 
 var (
@@ -142,6 +146,12 @@ func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
 	levelName := levelNames[level]
 
+	l.ctxMu.RLock()
+	if l.context != "" {
+		msg = fmt.Sprintf("[%s] %s", l.context, msg)
+	}
+	l.ctxMu.RUnlock()
+
 	var output string
 	if l.enableColors {
 		color := levelColors[level]
@@ -197,6 +207,34 @@ func (l *Logger) WithPrefix(prefix string) *Logger {
 	}
 }
 
+// SetContext attaches key=value fields (e.g., a call SID) that are included
+// in every line this logger writes. Unlike the level, context is per logger:
+// it is not shared with the parent or siblings. Pass nil to clear it.
+func (l *Logger) SetContext(fields map[string]string) {
+	keys := make([]string, 0, len(fields))
+	for key, value := range fields {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + fields[key]
+	}
+
+	l.ctxMu.Lock()
+	l.context = strings.Join(parts, " ")
+	l.ctxMu.Unlock()
+}
+
+// SetOutput redirects this logger, its parent and all sibling loggers to w
+func (l *Logger) SetOutput(w io.Writer) {
+	root := l.root()
+	root.stdLogger.SetOutput(w)
+}
+
 // Global convenience functions that use the default logger
 
 // GetDefault returns the default logger instance.
@@ -241,6 +279,11 @@ func Error(format string, args ...interface{}) {
 	GetDefault().log(ERROR, format, args...)
 }
 
+// SetOutput redirects the default logger (and all prefixed loggers) to w
+func SetOutput(w io.Writer) {
+	GetDefault().SetOutput(w)
+}
+
 // WithPrefix creates a new logger with a prefix from the default logger
 func WithPrefix(prefix string) *Logger {
 	return GetDefault().WithPrefix(prefix)
@@ -72,12 +72,14 @@ func EnsureModel(url, filename string) (string, error) {
 		return "", fmt.Errorf("failed to download %s: HTTP %d", filename, resp.StatusCode)
 	}
 
-	// Write to temp file, then atomic rename
-	tmpPath := modelPath + ".tmp"
-	f, err := os.Create(tmpPath)
+	// Write to a temp file unique to this call, then atomic rename. A fixed
+	// name would let concurrent processes sharing the cache clobber each
+	// other's partial download.
+	f, err := os.CreateTemp(cacheDir, filename+".*.tmp")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
+	tmpPath := f.Name()
 
 	written, err := io.Copy(f, resp.Body)
 	f.Close()
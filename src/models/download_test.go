@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// modelServer serves a distinct body for each requested file name
+func modelServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "model data for %s", r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestEnsureModel_LeavesForeignTempFile verifies that a download doesn't
+// write into a temp file another process may be filling.
+func TestEnsureModel_LeavesForeignTempFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := modelServer(t)
+
+	cacheDir := CacheDir()
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	foreign := filepath.Join(cacheDir, "vad.onnx.tmp")
+	if err := os.WriteFile(foreign, []byte("partial download"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := EnsureModel(srv.URL+"/vad", "vad.onnx")
+	if err != nil {
+		t.Fatalf("EnsureModel: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "model data for /vad" {
+		t.Errorf("unexpected model content %q", data)
+	}
+	if data, _ := os.ReadFile(foreign); string(data) != "partial download" {
+		t.Errorf("expected the other temp file to be untouched, got %q", data)
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(cacheDir, "vad.onnx.*.tmp"))
+	if len(leftovers) != 0 {
+		t.Errorf("expected no temp files left behind, got %v", leftovers)
+	}
+}
+
+// TestEnsureModel_Concurrent verifies that concurrent callers all get the
+// complete model.
+func TestEnsureModel_Concurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := modelServer(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("model-%d.onnx", i%2)
+			path, err := EnsureModel(srv.URL+"/"+name, name)
+			if err != nil {
+				t.Errorf("EnsureModel(%s): %v", name, err)
+				return
+			}
+			if data, _ := os.ReadFile(path); string(data) != "model data for /"+name {
+				t.Errorf("unexpected content for %s: %q", name, data)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// stageRecorder collects interruption stage events
+type stageRecorder struct {
+	mu     sync.Mutex
+	events []InterruptionStageEvent
+}
+
+func (r *stageRecorder) OnProcessFrame(ProcessFrameEvent) {}
+func (r *stageRecorder) OnPushFrame(PushFrameEvent)       {}
+func (r *stageRecorder) OnPipelineStarted()               {}
+func (r *stageRecorder) OnPipelineStopped()               {}
+
+func (r *stageRecorder) OnInterruptionStage(event InterruptionStageEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *stageRecorder) stages() map[frames.InterruptionStage]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[frames.InterruptionStage]string)
+	for _, e := range r.events {
+		out[e.Stage] = e.CorrelationID
+	}
+	return out
+}
+
+// interruptOnText pushes an InterruptionTaskFrame when it sees a TextFrame
+type interruptOnText struct {
+	*processors.BaseProcessor
+}
+
+func (p *interruptOnText) HandleFrame(_ context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.TextFrame); ok {
+		return p.PushInterruptionTaskFrame()
+	}
+	return p.PushFrame(frame, direction)
+}
+
+func TestInterruptionCorrelationIDThreadsThroughTask(t *testing.T) {
+	detector := &interruptOnText{}
+	detector.BaseProcessor = processors.NewBaseProcessor("Detector", detector)
+	tracker := newDirectionTrackingProcessor("interruption-tracker")
+	task := NewPipelineTask(NewPipeline([]processors.FrameProcessor{detector, tracker}))
+
+	recorder := &stageRecorder{}
+	taskObserver := NewTaskObserver()
+	taskObserver.AddObserver(recorder)
+	task.SetObserver(taskObserver)
+
+	done := runTask(task)
+	if err := queueWhenReady(task, frames.NewTextFrame("stop")); err != nil {
+		t.Fatalf("QueueFrame(TextFrame) failed: %v", err)
+	}
+
+	var interruption *frames.InterruptionFrame
+	deadline := time.Now().Add(2 * time.Second)
+	for interruption == nil && time.Now().Before(deadline) {
+		tracker.mu.Lock()
+		for _, tf := range tracker.frames {
+			if f, ok := tf.frame.(*frames.InterruptionFrame); ok {
+				interruption = f
+			}
+		}
+		tracker.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if interruption == nil {
+		t.Fatal("expected an InterruptionFrame downstream")
+	}
+
+	want := []frames.InterruptionStage{
+		frames.InterruptionStageDetected,
+		frames.InterruptionStageTaskPushed,
+		frames.InterruptionStageFrameGenerated,
+	}
+	var stages map[frames.InterruptionStage]string
+	for time.Now().Before(deadline) {
+		if stages = recorder.stages(); len(stages) == len(want) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, stage := range want {
+		if id, ok := stages[stage]; !ok || id != interruption.CorrelationID {
+			t.Errorf("stage %s: correlation ID %q, want %q", stage, id, interruption.CorrelationID)
+		}
+	}
+
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("QueueFrame(EndFrame) failed: %v", err)
+	}
+	if err := waitRunResult(t, done); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+}
@@ -30,6 +30,21 @@ type PushFrameEvent struct {
 	Timestamp     time.Time
 }
 
+// InterruptionStageEvent reports a processor reaching a stage of the
+// interruption identified by CorrelationID
+type InterruptionStageEvent struct {
+	ProcessorName string
+	CorrelationID string
+	Stage         frames.InterruptionStage
+	Timestamp     time.Time
+}
+
+// InterruptionObserver is implemented by Observers that also want
+// interruption lifecycle events
+type InterruptionObserver interface {
+	OnInterruptionStage(event InterruptionStageEvent)
+}
+
 type observerEventType int
 
 const (
@@ -37,12 +52,14 @@ const (
 	pushFrameObserverEvent
 	pipelineStartedObserverEvent
 	pipelineStoppedObserverEvent
+	interruptionStageObserverEvent
 )
 
 type observerEvent struct {
 	typeID       observerEventType
 	processEvent ProcessFrameEvent
 	pushEvent    PushFrameEvent
+	stageEvent   InterruptionStageEvent
 }
 
 type observerWorker struct {
@@ -130,6 +147,18 @@ func (o *TaskObserver) OnPushFrame(processorName string, frame frames.Frame, dir
 	})
 }
 
+func (o *TaskObserver) OnInterruptionStage(processorName, correlationID string, stage frames.InterruptionStage) {
+	o.broadcast(observerEvent{
+		typeID: interruptionStageObserverEvent,
+		stageEvent: InterruptionStageEvent{
+			ProcessorName: processorName,
+			CorrelationID: correlationID,
+			Stage:         stage,
+			Timestamp:     time.Now(),
+		},
+	})
+}
+
 func (o *TaskObserver) OnPipelineStarted() {
 	o.broadcast(observerEvent{typeID: pipelineStartedObserverEvent})
 }
@@ -172,5 +201,9 @@ func (o *TaskObserver) dispatchWithRecovery(observer Observer, event observerEve
 		observer.OnPipelineStarted()
 	case pipelineStoppedObserverEvent:
 		observer.OnPipelineStopped()
+	case interruptionStageObserverEvent:
+		if stageObserver, ok := observer.(InterruptionObserver); ok {
+			stageObserver.OnInterruptionStage(event.stageEvent)
+		}
 	}
 }
@@ -0,0 +1,239 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+type observedFrame struct {
+	processor string
+	text      string
+	direction frames.FrameDirection
+}
+
+func TestObserverFuncSeesFramesInOrder(t *testing.T) {
+	pipe := NewPipeline([]processors.FrameProcessor{
+		processors.NewPassthroughProcessor("first", false),
+		processors.NewPassthroughProcessor("second", false),
+	})
+	task := NewPipelineTask(pipe)
+
+	const count = 5
+
+	var mu sync.Mutex
+	var observed []observedFrame
+	sinkDone := make(chan struct{})
+	task.SetObserverFunc(func(processor string, frame frames.Frame, direction frames.FrameDirection) {
+		if text, ok := frame.(*frames.TextFrame); ok {
+			mu.Lock()
+			observed = append(observed, observedFrame{processor: processor, text: text.Text, direction: direction})
+			mu.Unlock()
+			if processor == "PipelineSink" && text.Text == fmt.Sprintf("frame-%d", count-1) {
+				close(sinkDone)
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(ctx)
+	}()
+
+	for i := 0; i < count; i++ {
+		if err := queueWhenReady(task, frames.NewTextFrame(fmt.Sprintf("frame-%d", i))); err != nil {
+			t.Fatalf("queue frame %d: %v", i, err)
+		}
+	}
+
+	// EndFrame is a system frame and would overtake queued data frames.
+	select {
+	case <-sinkDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for frames to reach the sink")
+	}
+
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("queue end frame: %v", err)
+	}
+	if err := waitRunResult(t, runDone); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	perProcessor := make(map[string][]string)
+	for _, o := range observed {
+		if o.direction != frames.Downstream {
+			t.Errorf("expected downstream direction for %s at %s, got %v", o.text, o.processor, o.direction)
+		}
+		perProcessor[o.processor] = append(perProcessor[o.processor], o.text)
+	}
+
+	for _, name := range []string{"PipelineSource", "first", "second", "PipelineSink"} {
+		seen := perProcessor[name]
+		if len(seen) != count {
+			t.Fatalf("expected %s to observe %d frames, got %d (%v)", name, count, len(seen), seen)
+		}
+		for i, text := range seen {
+			if want := fmt.Sprintf("frame-%d", i); text != want {
+				t.Errorf("%s observed %q at position %d, want %q", name, text, i, want)
+			}
+		}
+	}
+}
+
+func TestObserverFuncNilIsSafe(t *testing.T) {
+	base := processors.NewBaseProcessor("observer-func-nil", nil)
+	base.SetObserverFunc(nil)
+	base.Link(&queueOnlyProcessor{})
+
+	if err := base.ProcessFrame(context.Background(), frames.NewTextFrame("payload"), frames.Downstream); err != nil {
+		t.Fatalf("process frame: %v", err)
+	}
+}
+
+// processRecorder is a FrameObserver counting the frames it sees processed
+type processRecorder struct {
+	processed int
+}
+
+func (r *processRecorder) OnProcessFrame(string, frames.Frame, frames.FrameDirection) { r.processed++ }
+func (r *processRecorder) OnPushFrame(string, frames.Frame, frames.FrameDirection)    {}
+
+func TestObserverFuncPanicRecovery(t *testing.T) {
+	base := processors.NewBaseProcessor("observer-func-panic", nil)
+	base.SetObserverFunc(func(processor string, frame frames.Frame, direction frames.FrameDirection) {
+		panic("observer func panic")
+	})
+	recorder := &processRecorder{}
+	base.SetObserver(recorder)
+	base.Link(&queueOnlyProcessor{})
+
+	if err := base.ProcessFrame(context.Background(), frames.NewTextFrame("payload"), frames.Downstream); err != nil {
+		t.Fatalf("process frame: %v", err)
+	}
+	if recorder.processed != 1 {
+		t.Errorf("expected the observer to still see the frame, got %d", recorder.processed)
+	}
+}
+
+func TestPipelineTemplateContextReachesGreeting(t *testing.T) {
+	pipe := NewPipeline([]processors.FrameProcessor{
+		processors.NewGreetingProcessor(processors.GreetingConfig{Text: "Welcome to {{.CompanyName}}"}),
+		processors.NewPassthroughProcessor("tts", false),
+	})
+	config := DefaultPipelineTaskConfig()
+	config.TemplateContext = processors.TemplateContext{"CompanyName": "Acme"}
+	task := NewPipelineTaskWithConfig(pipe, config)
+
+	spoken := make(chan string, 1)
+	task.SetObserverFunc(func(processor string, frame frames.Frame, direction frames.FrameDirection) {
+		if text, ok := frame.(*frames.TextFrame); ok && processor == "tts" {
+			select {
+			case spoken <- text.Text:
+			default:
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(ctx)
+	}()
+
+	select {
+	case text := <-spoken:
+		if text != "Welcome to Acme" {
+			t.Errorf("Expected rendered greeting at TTS, got %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the greeting")
+	}
+
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("queue end frame: %v", err)
+	}
+	if err := waitRunResult(t, runDone); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+}
+
+func TestPipelineSeedsLLMContextFromSavedBlob(t *testing.T) {
+	saved := services.NewLLMContext("You are a receptionist.")
+	saved.AddUserMessage("Call me back later")
+	saved.AddAssistantMessage("Sure, talk soon.")
+	blob, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatalf("marshal context: %v", err)
+	}
+
+	llmCtx := services.NewLLMContext("")
+	pipe := NewPipeline([]processors.FrameProcessor{
+		processors.NewPassthroughProcessor("llm", false),
+	})
+	config := DefaultPipelineTaskConfig()
+	config.LLMContext = llmCtx
+	config.SavedContext = blob
+	task := NewPipelineTaskWithConfig(pipe, config)
+
+	// The context must already be restored when the StartFrame arrives
+	seeded := make(chan int, 1)
+	task.SetObserverFunc(func(processor string, frame frames.Frame, direction frames.FrameDirection) {
+		if _, ok := frame.(*frames.StartFrame); ok {
+			select {
+			case seeded <- len(llmCtx.Messages):
+			default:
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(ctx)
+	}()
+
+	select {
+	case n := <-seeded:
+		if n != 2 {
+			t.Errorf("Expected 2 restored messages at start, got %d", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the StartFrame")
+	}
+	if llmCtx.SystemPrompt != "You are a receptionist." {
+		t.Errorf("System prompt not restored, got %q", llmCtx.SystemPrompt)
+	}
+
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("queue end frame: %v", err)
+	}
+	if err := waitRunResult(t, runDone); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+}
+
+func TestPipelineSavedContextRequiresLLMContext(t *testing.T) {
+	config := DefaultPipelineTaskConfig()
+	config.SavedContext = []byte(`{"version":1,"messages":[]}`)
+	task := NewPipelineTaskWithConfig(NewPipeline(nil), config)
+
+	if err := task.Run(context.Background()); err == nil {
+		t.Fatal("Expected Run to fail without an LLMContext to restore into")
+	}
+}
@@ -0,0 +1,202 @@
+package observers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+)
+
+// LatencyStage names a stage of a turn that has a latency budget
+type LatencyStage string
+
+const (
+	// LatencyStageSTT runs from the user starting to speak to the first
+	// (partial or final) transcription
+	LatencyStageSTT LatencyStage = "stt"
+	// LatencyStageLLM runs from the LLM being asked for a response to its
+	// first token
+	LatencyStageLLM LatencyStage = "llm"
+	// LatencyStageTTS runs from the first LLM token to the first TTS audio
+	LatencyStageTTS LatencyStage = "tts"
+	// LatencyStageEndToEnd runs from the user stopping speaking to the first
+	// TTS audio
+	LatencyStageEndToEnd LatencyStage = "end_to_end"
+)
+
+// LatencyBudgets sets the latency allowed per stage; zero disables a stage
+type LatencyBudgets struct {
+	STT      time.Duration
+	LLM      time.Duration
+	TTS      time.Duration
+	EndToEnd time.Duration
+}
+
+func (b LatencyBudgets) budget(stage LatencyStage) time.Duration {
+	switch stage {
+	case LatencyStageSTT:
+		return b.STT
+	case LatencyStageLLM:
+		return b.LLM
+	case LatencyStageTTS:
+		return b.TTS
+	case LatencyStageEndToEnd:
+		return b.EndToEnd
+	}
+	return 0
+}
+
+// LatencyBudgetExceeded reports a stage of a turn that blew its budget
+type LatencyBudgetExceeded struct {
+	Turn    int // Turn number, counting user turns from 1
+	Stage   LatencyStage
+	Latency time.Duration
+	Budget  time.Duration
+
+	// StartFrameID and EndFrameID identify the frames the stage was measured
+	// between, for matching the alarm up with frame logs
+	StartFrameID uint64
+	EndFrameID   uint64
+}
+
+// LatencyBudgetObserver raises OnBudgetExceeded when a turn stage takes
+// longer than its budget. Each stage is measured once per turn, between the
+// first frames that start and end it, so a frame seen by several processors
+// only counts where it is first observed.
+type LatencyBudgetObserver struct {
+	mu sync.Mutex
+
+	OnBudgetExceeded func(exceeded LatencyBudgetExceeded)
+
+	budgets LatencyBudgets
+	turn    int
+
+	userSpeaking bool
+	marks        map[LatencyStage]latencyMark // Stage start, per stage
+	measured     map[LatencyStage]bool
+}
+
+type latencyMark struct {
+	at      time.Time
+	frameID uint64
+}
+
+// NewLatencyBudgetObserver creates an observer that checks turns against budgets
+func NewLatencyBudgetObserver(budgets LatencyBudgets) *LatencyBudgetObserver {
+	return &LatencyBudgetObserver{
+		budgets:  budgets,
+		marks:    make(map[LatencyStage]latencyMark),
+		measured: make(map[LatencyStage]bool),
+	}
+}
+
+func (o *LatencyBudgetObserver) OnProcessFrame(event pipeline.ProcessFrameEvent) {
+	o.handleFrame(event.Frame, event.Timestamp)
+}
+
+func (o *LatencyBudgetObserver) OnPushFrame(event pipeline.PushFrameEvent) {
+	o.handleFrame(event.Frame, event.Timestamp)
+}
+
+func (o *LatencyBudgetObserver) OnPipelineStarted() {
+	o.reset()
+}
+
+func (o *LatencyBudgetObserver) OnPipelineStopped() {
+	o.reset()
+}
+
+func (o *LatencyBudgetObserver) handleFrame(frame frames.Frame, now time.Time) {
+	var exceeded []LatencyBudgetExceeded
+	mark := latencyMark{at: now, frameID: frame.ID()}
+
+	o.mu.Lock()
+	switch f := frame.(type) {
+	case *frames.UserStartedSpeakingFrame:
+		if o.userSpeaking {
+			break
+		}
+		o.userSpeaking = true
+		o.turn++
+		o.marks = map[LatencyStage]latencyMark{LatencyStageSTT: mark}
+		o.measured = make(map[LatencyStage]bool)
+	case *frames.UserStoppedSpeakingFrame:
+		if !o.userSpeaking {
+			break
+		}
+		o.userSpeaking = false
+		o.marks[LatencyStageEndToEnd] = mark
+	case *frames.TranscriptionFrame:
+		exceeded = o.measure(exceeded, LatencyStageSTT, mark)
+		if f.IsFinal {
+			o.startOnce(LatencyStageLLM, mark)
+		}
+	case *frames.LLMContextFrame:
+		// The request itself is a better LLM start than the transcription,
+		// which also includes the wait for the end of the turn
+		if _, ok := o.marks[LatencyStageLLM]; ok && !o.measured[LatencyStageLLM] {
+			o.marks[LatencyStageLLM] = mark
+		}
+	case *frames.LLMTextFrame:
+		exceeded = o.measure(exceeded, LatencyStageLLM, mark)
+		o.startOnce(LatencyStageTTS, mark)
+	case *frames.TTSAudioFrame:
+		exceeded = o.measure(exceeded, LatencyStageTTS, mark)
+		exceeded = o.measure(exceeded, LatencyStageEndToEnd, mark)
+	}
+	cb := o.OnBudgetExceeded
+	o.mu.Unlock()
+
+	if cb == nil {
+		return
+	}
+	for _, e := range exceeded {
+		go cb(e)
+	}
+}
+
+// startOnce marks the start of stage unless it already started this turn.
+// Must be called with mu held.
+func (o *LatencyBudgetObserver) startOnce(stage LatencyStage, mark latencyMark) {
+	if o.turn == 0 {
+		return
+	}
+	if _, ok := o.marks[stage]; !ok {
+		o.marks[stage] = mark
+	}
+}
+
+// measure ends stage if it is running and appends it to exceeded when it
+// went over budget. Must be called with mu held.
+func (o *LatencyBudgetObserver) measure(exceeded []LatencyBudgetExceeded, stage LatencyStage, end latencyMark) []LatencyBudgetExceeded {
+	start, ok := o.marks[stage]
+	if !ok || o.measured[stage] {
+		return exceeded
+	}
+	o.measured[stage] = true
+
+	budget := o.budgets.budget(stage)
+	latency := end.at.Sub(start.at)
+	if budget <= 0 || latency <= budget {
+		return exceeded
+	}
+	return append(exceeded, LatencyBudgetExceeded{
+		Turn:         o.turn,
+		Stage:        stage,
+		Latency:      latency,
+		Budget:       budget,
+		StartFrameID: start.frameID,
+		EndFrameID:   end.frameID,
+	})
+}
+
+func (o *LatencyBudgetObserver) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.turn = 0
+	o.userSpeaking = false
+	o.marks = make(map[LatencyStage]latencyMark)
+	o.measured = make(map[LatencyStage]bool)
+}
@@ -0,0 +1,107 @@
+package observers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+)
+
+var testBudgets = LatencyBudgets{
+	STT:      300 * time.Millisecond,
+	LLM:      200 * time.Millisecond,
+	TTS:      200 * time.Millisecond,
+	EndToEnd: time.Second,
+}
+
+// playTurn feeds a turn to observer, delaying the LLM's first token by
+// llmDelay and the first audio by ttsDelay
+func playTurn(observer *LatencyBudgetObserver, base time.Time, llmDelay, ttsDelay time.Duration) {
+	push := func(frame frames.Frame, at time.Duration) {
+		observer.OnPushFrame(pipeline.PushFrameEvent{Frame: frame, Timestamp: base.Add(at)})
+	}
+	llmAt := 1150*time.Millisecond + llmDelay
+	push(frames.NewUserStartedSpeakingFrame(), 0)
+	push(frames.NewTranscriptionFrame("book a", false), 100*time.Millisecond)
+	push(frames.NewUserStoppedSpeakingFrame(), time.Second)
+	push(frames.NewTranscriptionFrame("book a table", true), 1100*time.Millisecond)
+	push(frames.NewLLMContextFrame(nil), 1150*time.Millisecond)
+	push(frames.NewLLMTextFrame("Sure"), llmAt)
+	push(frames.NewLLMTextFrame(", for when?"), llmAt+10*time.Millisecond)
+	push(frames.NewTTSAudioFrame([]byte{1}, 16000, 1), llmAt+ttsDelay)
+	push(frames.NewTTSAudioFrame([]byte{1}, 16000, 1), llmAt+ttsDelay+20*time.Millisecond)
+}
+
+func collectExceeded(t *testing.T, ch chan LatencyBudgetExceeded, n int) map[LatencyStage]LatencyBudgetExceeded {
+	t.Helper()
+	out := make(map[LatencyStage]LatencyBudgetExceeded)
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-ch:
+			out[e.Stage] = e
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for budget alarm %d of %d", i+1, n)
+		}
+	}
+	select {
+	case e := <-ch:
+		t.Fatalf("unexpected extra alarm for %s", e.Stage)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return out
+}
+
+func TestLatencyBudgetObserverFlagsSlowLLM(t *testing.T) {
+	observer := NewLatencyBudgetObserver(testBudgets)
+	ch := make(chan LatencyBudgetExceeded, 4)
+	observer.OnBudgetExceeded = func(e LatencyBudgetExceeded) { ch <- e }
+
+	playTurn(observer, time.Unix(1, 0), 500*time.Millisecond, 100*time.Millisecond)
+
+	alarms := collectExceeded(t, ch, 1)
+	llm, ok := alarms[LatencyStageLLM]
+	if !ok {
+		t.Fatalf("expected an LLM alarm, got %v", alarms)
+	}
+	if llm.Latency != 500*time.Millisecond || llm.Budget != 200*time.Millisecond {
+		t.Fatalf("unexpected LLM alarm: latency %v budget %v", llm.Latency, llm.Budget)
+	}
+	if llm.Turn != 1 {
+		t.Fatalf("expected turn 1, got %d", llm.Turn)
+	}
+	if llm.StartFrameID == 0 || llm.EndFrameID == 0 || llm.StartFrameID == llm.EndFrameID {
+		t.Fatalf("expected distinct start and end frame IDs, got %d and %d", llm.StartFrameID, llm.EndFrameID)
+	}
+}
+
+func TestLatencyBudgetObserverFlagsSlowTTSAndEndToEnd(t *testing.T) {
+	observer := NewLatencyBudgetObserver(testBudgets)
+	ch := make(chan LatencyBudgetExceeded, 4)
+	observer.OnBudgetExceeded = func(e LatencyBudgetExceeded) { ch <- e }
+
+	playTurn(observer, time.Unix(1, 0), 100*time.Millisecond, 900*time.Millisecond)
+
+	alarms := collectExceeded(t, ch, 2)
+	if tts := alarms[LatencyStageTTS]; tts.Latency != 900*time.Millisecond {
+		t.Fatalf("expected TTS alarm of 900ms, got %+v", tts)
+	}
+	if e2e := alarms[LatencyStageEndToEnd]; e2e.Latency != 1150*time.Millisecond {
+		t.Fatalf("expected end-to-end alarm of 1150ms, got %+v", e2e)
+	}
+}
+
+func TestLatencyBudgetObserverWithinBudgetTurnsAreSilent(t *testing.T) {
+	observer := NewLatencyBudgetObserver(testBudgets)
+	ch := make(chan LatencyBudgetExceeded, 4)
+	observer.OnBudgetExceeded = func(e LatencyBudgetExceeded) { ch <- e }
+
+	playTurn(observer, time.Unix(1, 0), 100*time.Millisecond, 100*time.Millisecond)
+	// A slow second turn is reported against its own turn number
+	playTurn(observer, time.Unix(10, 0), 100*time.Millisecond, 300*time.Millisecond)
+
+	alarms := collectExceeded(t, ch, 1)
+	if tts, ok := alarms[LatencyStageTTS]; !ok || tts.Turn != 2 {
+		t.Fatalf("expected a TTS alarm for turn 2, got %v", alarms)
+	}
+}
@@ -3,7 +3,11 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/square-key-labs/strawgo-ai/src/audio"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
@@ -63,23 +67,54 @@ func (p *PipelineSink) HandleFrame(ctx context.Context, frame frames.Frame, dire
 	return p.PushFrame(frame, direction)
 }
 
+// AudioFormatCheck selects what Initialize does when a processor's declared
+// input audio format (processors.AudioFormatConsumer) doesn't match the
+// nearest upstream declared output format (processors.AudioFormatProducer),
+// e.g. a 24kHz linear16 TTS feeding an 8kHz mulaw output with no converter
+type AudioFormatCheck int
+
+const (
+	// AudioFormatCheckWarn logs a warning for each mismatch (default)
+	AudioFormatCheckWarn AudioFormatCheck = iota
+	// AudioFormatCheckError fails Initialize on the first mismatch
+	AudioFormatCheckError
+	// AudioFormatCheckConvert inserts an audio.AudioConverterProcessor in
+	// front of each mismatched consumer
+	AudioFormatCheckConvert
+	// AudioFormatCheckOff skips the check
+	AudioFormatCheckOff
+)
+
 // Pipeline connects multiple processors in a linear chain
 type Pipeline struct {
-	processors []processors.FrameProcessor
-	source     *PipelineSource
-	sink       *PipelineSink
+	processors      []processors.FrameProcessor
+	source          *PipelineSource
+	sink            *PipelineSink
+	shutdownTimeout time.Duration
+
+	// cancel force-cancels every processor's context when Stop times out
+	cancel context.CancelFunc
 }
 
 // NewPipeline creates a new pipeline with the given processors
 func NewPipeline(procs []processors.FrameProcessor) *Pipeline {
 	p := &Pipeline{
-		processors: procs,
+		processors:      procs,
+		shutdownTimeout: DefaultShutdownTimeout,
 	}
 	return p
 }
 
 // Initialize sets up the pipeline with source and sink
 func (p *Pipeline) Initialize(task *PipelineTask) error {
+	formatCheck := AudioFormatCheckWarn
+	if task != nil {
+		p.shutdownTimeout = task.shutdownTimeout()
+		formatCheck = task.config.AudioFormatCheck
+	}
+	if err := p.checkAudioFormats(formatCheck); err != nil {
+		return err
+	}
 	p.source = newPipelineSource(task)
 	p.sink = newPipelineSink(task)
 
@@ -98,6 +133,76 @@ func (p *Pipeline) Initialize(task *PipelineTask) error {
 	return nil
 }
 
+// checkAudioFormats compares each processor that declares an input audio
+// format with the nearest upstream processor that declares an output format;
+// processors in between are assumed to pass audio through unchanged
+func (p *Pipeline) checkAudioFormats(mode AudioFormatCheck) error {
+	if mode == AudioFormatCheckOff {
+		return nil
+	}
+
+	checked := make([]processors.FrameProcessor, 0, len(p.processors))
+	var producer processors.FrameProcessor
+	var produced processors.AudioFormat
+	for _, proc := range p.processors {
+		if consumer, ok := proc.(processors.AudioFormatConsumer); ok && producer != nil {
+			expected := consumer.InputFormat()
+			if !audioFormatsMatch(produced, expected) {
+				mismatch := fmt.Sprintf("%s outputs %s but %s expects %s", producer.Name(), produced, proc.Name(), expected)
+				switch mode {
+				case AudioFormatCheckError:
+					return fmt.Errorf("audio format mismatch: %s; add an audio.AudioConverterProcessor between them", mismatch)
+				case AudioFormatCheckConvert:
+					logger.Info("[Pipeline] Audio format mismatch: %s; inserting an AudioConverter", mismatch)
+					checked = append(checked, newFormatConverter(produced, expected))
+				default:
+					logger.Warn("[Pipeline] Audio format mismatch: %s; audio will be garbled without an audio.AudioConverterProcessor between them", mismatch)
+				}
+			}
+		}
+
+		checked = append(checked, proc)
+		if out, ok := proc.(processors.AudioFormatProducer); ok {
+			producer = proc
+			produced = out.OutputFormat()
+		}
+	}
+	p.processors = checked
+	return nil
+}
+
+// audioFormatsMatch reports whether audio in format from is acceptable where
+// format to is expected. Unset fields match anything.
+func audioFormatsMatch(from, to processors.AudioFormat) bool {
+	if from.SampleRate > 0 && to.SampleRate > 0 && from.SampleRate != to.SampleRate {
+		return false
+	}
+	if from.Codec != "" && to.Codec != "" && audio.NormalizeCodecName(from.Codec) != audio.NormalizeCodecName(to.Codec) {
+		return false
+	}
+	return true
+}
+
+// newFormatConverter creates a converter from one declared format to
+// another, keeping whatever the target leaves unset
+func newFormatConverter(from, to processors.AudioFormat) *audio.AudioConverterProcessor {
+	if from.Codec == "" {
+		from.Codec = "linear16"
+	}
+	if to.Codec == "" {
+		to.Codec = from.Codec
+	}
+	if to.SampleRate <= 0 {
+		to.SampleRate = from.SampleRate
+	}
+	return audio.NewAudioConverterProcessor(audio.AudioConverterConfig{
+		InputSampleRate:  from.SampleRate,
+		InputCodec:       from.Codec,
+		OutputSampleRate: to.SampleRate,
+		OutputCodec:      to.Codec,
+	})
+}
+
 func (p *Pipeline) SetObserver(observer processors.FrameObserver) {
 	if p.source != nil {
 		if observerAware, ok := any(p.source).(processors.ObserverAwareProcessor); ok {
@@ -118,8 +223,28 @@ func (p *Pipeline) SetObserver(observer processors.FrameObserver) {
 	}
 }
 
+// SetObserverFunc installs a synchronous per-frame hook on the source, every
+// processor and the sink. Pass nil to remove it.
+func (p *Pipeline) SetObserverFunc(fn processors.ObserverFunc) {
+	if p.source != nil {
+		p.source.SetObserverFunc(fn)
+	}
+
+	for _, proc := range p.processors {
+		if observerAware, ok := proc.(processors.ObserverFuncAwareProcessor); ok {
+			observerAware.SetObserverFunc(fn)
+		}
+	}
+
+	if p.sink != nil {
+		p.sink.SetObserverFunc(fn)
+	}
+}
+
 // Start begins processing in all processors
 func (p *Pipeline) Start(ctx context.Context) error {
+	ctx, p.cancel = context.WithCancel(ctx)
+
 	// Start source
 	if err := p.source.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start source: %w", err)
@@ -142,25 +267,57 @@ func (p *Pipeline) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop gracefully stops all processors
+// Stop gracefully stops all processors. If they haven't all stopped within
+// the shutdown timeout, every processor's context is force-cancelled and an
+// error naming the ones still running is returned; a processor blocked
+// without watching its context is abandoned.
 func (p *Pipeline) Stop() error {
 	logger.Debug("[Pipeline] Beginning graceful shutdown")
 
 	// Stop in reverse order
-	if err := p.sink.Stop(); err != nil {
-		logger.Error("[Pipeline] Error stopping sink: %v", err)
+	order := []processors.FrameProcessor{p.sink}
+	for i := len(p.processors) - 1; i >= 0; i-- {
+		order = append(order, p.processors[i])
 	}
+	order = append(order, p.source)
 
-	for i := len(p.processors) - 1; i >= 0; i-- {
-		if err := p.processors[i].Stop(); err != nil {
-			logger.Error("[Pipeline] Error stopping processor %s: %v", p.processors[i].Name(), err)
+	var mu sync.Mutex
+	stopped := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, proc := range order {
+			if err := proc.Stop(); err != nil {
+				logger.Error("[Pipeline] Error stopping processor %s: %v", proc.Name(), err)
+			}
+			mu.Lock()
+			stopped++
+			mu.Unlock()
 		}
-	}
+	}()
 
-	if err := p.source.Stop(); err != nil {
-		logger.Error("[Pipeline] Error stopping source: %v", err)
+	timer := time.NewTimer(p.shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		if p.cancel != nil {
+			p.cancel()
+		}
+		mu.Lock()
+		var running []string
+		for _, proc := range order[stopped:] {
+			running = append(running, proc.Name())
+		}
+		mu.Unlock()
+		logger.Error("[Pipeline] Shutdown timed out after %v, force-cancelled; still stopping: %s",
+			p.shutdownTimeout, strings.Join(running, ", "))
+		return fmt.Errorf("processors did not stop within %v: %s", p.shutdownTimeout, strings.Join(running, ", "))
 	}
 
+	if p.cancel != nil {
+		p.cancel()
+	}
 	logger.Info("[Pipeline] Stopped all processors")
 	return nil
 }
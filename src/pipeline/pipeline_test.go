@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/services/deepgram"
+	"github.com/square-key-labs/strawgo-ai/src/transports"
+)
+
+// producerProcessor passes frames through and declares an output format
+type producerProcessor struct {
+	*processors.BaseProcessor
+	format processors.AudioFormat
+}
+
+func newProducer(name string, sampleRate int, codec string) *producerProcessor {
+	p := &producerProcessor{format: processors.AudioFormat{SampleRate: sampleRate, Codec: codec}}
+	p.BaseProcessor = processors.NewBaseProcessor(name, nil)
+	return p
+}
+
+func (p *producerProcessor) OutputFormat() processors.AudioFormat { return p.format }
+
+// consumerProcessor passes frames through and declares an input format
+type consumerProcessor struct {
+	*processors.BaseProcessor
+	format processors.AudioFormat
+}
+
+func newConsumer(name string, sampleRate int, codec string) *consumerProcessor {
+	p := &consumerProcessor{format: processors.AudioFormat{SampleRate: sampleRate, Codec: codec}}
+	p.BaseProcessor = processors.NewBaseProcessor(name, nil)
+	return p
+}
+
+func (p *consumerProcessor) InputFormat() processors.AudioFormat { return p.format }
+
+// ttsToTwilio is a 24kHz linear16 TTS feeding an 8kHz mulaw output through
+// a processor that doesn't touch audio
+func ttsToTwilio() []processors.FrameProcessor {
+	return []processors.FrameProcessor{
+		newProducer("TTS", 24000, "linear16"),
+		processors.NewPassthroughProcessor("Between", false),
+		newConsumer("TwilioOutput", 8000, "PCMU"),
+	}
+}
+
+func TestPipeline_AudioFormatMismatchError(t *testing.T) {
+	pipe := NewPipeline(ttsToTwilio())
+	task := NewPipelineTaskWithConfig(pipe, &PipelineTaskConfig{AudioFormatCheck: AudioFormatCheckError})
+
+	err := task.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected Run to fail on an audio format mismatch")
+	}
+	for _, want := range []string{"TTS", "linear16 24000Hz", "TwilioOutput", "PCMU 8000Hz"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestPipeline_AudioFormatMismatchConverts(t *testing.T) {
+	pipe := NewPipeline(ttsToTwilio())
+	if err := pipe.Initialize(&PipelineTask{config: &PipelineTaskConfig{AudioFormatCheck: AudioFormatCheckConvert}}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	if len(pipe.processors) != 4 {
+		t.Fatalf("expected a converter to be inserted, got %d processors", len(pipe.processors))
+	}
+	converter, ok := pipe.processors[2].(*audio.AudioConverterProcessor)
+	if !ok {
+		t.Fatalf("expected the converter right before the output, got %s", pipe.processors[2].Name())
+	}
+	if in := converter.InputFormat(); in.SampleRate != 24000 || in.Codec != "linear16" {
+		t.Errorf("converter input = %s, want linear16 24000Hz", in)
+	}
+	if out := converter.OutputFormat(); out.SampleRate != 8000 || out.Codec != "mulaw" {
+		t.Errorf("converter output = %s, want mulaw 8000Hz", out)
+	}
+
+	// The corrected chain passes a second check untouched
+	if err := pipe.checkAudioFormats(AudioFormatCheckError); err != nil {
+		t.Errorf("expected no mismatch after conversion, got %v", err)
+	}
+}
+
+// TestPipeline_AudioFormatCheckRealServices checks a real TTS service
+// against a WebSocket output speaking Twilio's 8kHz mulaw
+func TestPipeline_AudioFormatCheckRealServices(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   deepgram.TTSConfig
+		mismatch bool
+	}{
+		{"provider format", deepgram.TTSConfig{SampleRate: 24000}, true},
+		{"converted by the service", deepgram.TTSConfig{SampleRate: 24000, OutputSampleRate: 8000, OutputCodec: "mulaw"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.APIKey = "test-key"
+			transport := transports.NewWebSocketTransport(transports.WebSocketConfig{
+				Serializer: serializers.NewTwilioFrameSerializer("", ""),
+			})
+			pipe := NewPipeline([]processors.FrameProcessor{
+				deepgram.NewTTSService(tc.config),
+				transport.Output(),
+			})
+
+			err := pipe.checkAudioFormats(AudioFormatCheckError)
+			if tc.mismatch {
+				if err == nil || !strings.Contains(err.Error(), "linear16 24000Hz") || !strings.Contains(err.Error(), "mulaw 8000Hz") {
+					t.Errorf("expected a linear16 24000Hz to mulaw 8000Hz mismatch, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("expected matching formats, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPipeline_AudioFormatsMatch(t *testing.T) {
+	procs := []processors.FrameProcessor{
+		newProducer("TTS", 8000, "ulaw"),
+		newConsumer("Output", 8000, "mulaw"),
+		newConsumer("AnyRate", 0, "mulaw"),
+	}
+	pipe := NewPipeline(procs)
+	if err := pipe.checkAudioFormats(AudioFormatCheckError); err != nil {
+		t.Errorf("expected matching formats to pass, got %v", err)
+	}
+	if len(pipe.processors) != len(procs) {
+		t.Errorf("expected no converter, got %d processors", len(pipe.processors))
+	}
+}
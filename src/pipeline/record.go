@@ -0,0 +1,348 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// frameRecord is one recorded frame, written as a line of JSON.
+// Only the fields relevant to the frame type are set; audio is base64-encoded.
+type frameRecord struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Direction string    `json:"direction"`
+
+	Text               string                 `json:"text,omitempty"`
+	IsFinal            bool                   `json:"is_final,omitempty"`
+	Language           string                 `json:"language,omitempty"`
+	SkipTTS            bool                   `json:"skip_tts,omitempty"`
+	Audio              []byte                 `json:"audio,omitempty"`
+	SampleRate         int                    `json:"sample_rate,omitempty"`
+	Channels           int                    `json:"channels,omitempty"`
+	ContextID          string                 `json:"context_id,omitempty"`
+	Error              string                 `json:"error,omitempty"`
+	AllowInterruptions bool                   `json:"allow_interruptions,omitempty"`
+	LLMPolicy          int                    `json:"interruption_llm_policy,omitempty"`
+	Metadata           map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// encodeFrame converts a frame to a record. Returns false for frame types
+// that cannot be replayed (e.g., frames carrying live Go objects).
+func encodeFrame(frame frames.Frame) (frameRecord, bool) {
+	rec := frameRecord{Type: frame.Name()}
+	if len(frame.Metadata()) > 0 {
+		rec.Metadata = frame.Metadata()
+	}
+
+	switch f := frame.(type) {
+	case *frames.TextFrame:
+		rec.Text = f.Text
+		rec.SkipTTS = f.SkipTTS
+	case *frames.LLMTextFrame:
+		rec.Text = f.Text
+		rec.SkipTTS = f.SkipTTS
+	case *frames.TranscriptionFrame:
+		rec.Text = f.Text
+		rec.IsFinal = f.IsFinal
+		rec.Language = f.Language
+	case *frames.AudioFrame:
+		rec.Audio = f.Data
+		rec.SampleRate = f.SampleRate
+		rec.Channels = f.Channels
+	case *frames.TTSAudioFrame:
+		rec.Audio = f.Data
+		rec.SampleRate = f.SampleRate
+		rec.Channels = f.Channels
+		rec.ContextID = f.ContextID
+	case *frames.TTSStartedFrame:
+		rec.ContextID = f.ContextID
+	case *frames.TTSStoppedFrame:
+		rec.ContextID = f.ContextID
+	case *frames.ErrorFrame:
+		if f.Error != nil {
+			rec.Error = f.Error.Error()
+		}
+	case *frames.StartFrame:
+		rec.AllowInterruptions = f.AllowInterruptions
+		rec.LLMPolicy = int(f.InterruptionLLMPolicy)
+	case *frames.EndFrame, *frames.CancelFrame, *frames.InterruptionFrame,
+		*frames.UserStartedSpeakingFrame, *frames.UserStoppedSpeakingFrame,
+		*frames.BotStartedSpeakingFrame, *frames.BotStoppedSpeakingFrame,
+		*frames.LLMFullResponseStartFrame, *frames.LLMFullResponseEndFrame:
+		// No payload
+	default:
+		return frameRecord{}, false
+	}
+	return rec, true
+}
+
+// decodeFrame rebuilds a frame from a record
+func decodeFrame(rec frameRecord) (frames.Frame, error) {
+	var frame frames.Frame
+
+	switch rec.Type {
+	case "TextFrame":
+		f := frames.NewTextFrame(rec.Text)
+		f.SkipTTS = rec.SkipTTS
+		frame = f
+	case "LLMTextFrame":
+		f := frames.NewLLMTextFrame(rec.Text)
+		f.SkipTTS = rec.SkipTTS
+		frame = f
+	case "TranscriptionFrame":
+		f := frames.NewTranscriptionFrame(rec.Text, rec.IsFinal)
+		f.Language = rec.Language
+		frame = f
+	case "AudioFrame":
+		frame = frames.NewAudioFrame(rec.Audio, rec.SampleRate, rec.Channels)
+	case "TTSAudioFrame":
+		f := frames.NewTTSAudioFrame(rec.Audio, rec.SampleRate, rec.Channels)
+		f.ContextID = rec.ContextID
+		frame = f
+	case "TTSStartedFrame":
+		frame = frames.NewTTSStartedFrameWithContext(rec.ContextID)
+	case "TTSStoppedFrame":
+		f := frames.NewTTSStoppedFrame()
+		f.ContextID = rec.ContextID
+		frame = f
+	case "ErrorFrame":
+		frame = frames.NewErrorFrame(errors.New(rec.Error))
+	case "StartFrame":
+		f := frames.NewStartFrame()
+		f.AllowInterruptions = rec.AllowInterruptions
+		f.InterruptionLLMPolicy = frames.InterruptionLLMPolicy(rec.LLMPolicy)
+		frame = f
+	case "EndFrame":
+		frame = frames.NewEndFrame()
+	case "CancelFrame":
+		frame = frames.NewCancelFrame()
+	case "InterruptionFrame":
+		frame = frames.NewInterruptionFrame()
+	case "UserStartedSpeakingFrame":
+		frame = frames.NewUserStartedSpeakingFrame()
+	case "UserStoppedSpeakingFrame":
+		frame = frames.NewUserStoppedSpeakingFrame()
+	case "BotStartedSpeakingFrame":
+		frame = frames.NewBotStartedSpeakingFrame()
+	case "BotStoppedSpeakingFrame":
+		frame = frames.NewBotStoppedSpeakingFrame()
+	case "LLMFullResponseStartFrame":
+		frame = frames.NewLLMFullResponseStartFrame()
+	case "LLMFullResponseEndFrame":
+		frame = frames.NewLLMFullResponseEndFrame()
+	default:
+		return nil, fmt.Errorf("unsupported frame type %q", rec.Type)
+	}
+
+	for key, value := range rec.Metadata {
+		frame.SetMetadata(key, value)
+	}
+	return frame, nil
+}
+
+// Recorder is a pass-through processor that writes every frame it sees, in
+// both directions, as timestamped JSON lines. Place it where the stream of
+// interest flows (e.g., right after the transport input) and replay the
+// output with a Replayer.
+type Recorder struct {
+	*processors.BaseProcessor
+	mu      sync.Mutex
+	enc     *json.Encoder
+	buf     *bufio.Writer
+	closer  io.Closer
+	skipped int
+	log     *logger.Logger
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	buf := bufio.NewWriter(w)
+	r := &Recorder{
+		enc: json.NewEncoder(buf),
+		buf: buf,
+		log: logger.WithPrefix("Recorder"),
+	}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+	r.BaseProcessor = processors.NewBaseProcessor("Recorder", r)
+	return r
+}
+
+// NewFileRecorder creates a recorder writing to the file at path
+func NewFileRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	return NewRecorder(f), nil
+}
+
+func (r *Recorder) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	r.record(frame, direction)
+
+	// Flush on EndFrame so the recording is complete even without Close
+	if _, ok := frame.(*frames.EndFrame); ok {
+		if err := r.Flush(); err != nil {
+			r.log.Warn("Error flushing recording: %v", err)
+		}
+	}
+
+	return r.PushFrame(frame, direction)
+}
+
+func (r *Recorder) record(frame frames.Frame, direction frames.FrameDirection) {
+	rec, ok := encodeFrame(frame)
+	if !ok {
+		r.mu.Lock()
+		r.skipped++
+		r.mu.Unlock()
+		return
+	}
+	rec.Time = time.Now()
+	rec.Direction = direction.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(rec); err != nil {
+		r.log.Warn("Error recording %s: %v", frame.Name(), err)
+	}
+}
+
+// Flush writes any buffered records
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Flush()
+}
+
+// Close flushes the recording and closes the underlying writer if it is closable
+func (r *Recorder) Close() error {
+	if err := r.Flush(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.skipped > 0 {
+		r.log.Debug("Skipped %d frames that cannot be replayed", r.skipped)
+	}
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+// Replayer is a source processor that re-emits recorded downstream frames
+// with their original relative timing. Put it first in the pipeline under
+// test; replay starts when the pipeline's StartFrame arrives. Recorded
+// StartFrames and upstream frames are skipped since the pipeline generates
+// its own. A recorded EndFrame ends the pipeline as it did originally.
+type Replayer struct {
+	*processors.BaseProcessor
+	records []frameRecord
+	once    sync.Once
+	done    chan struct{}
+	log     *logger.Logger
+}
+
+// NewReplayer creates a replayer from a recording read from r
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	var records []frameRecord
+	dec := json.NewDecoder(r)
+	for {
+		var rec frameRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		records = append(records, rec)
+	}
+
+	rp := &Replayer{
+		records: records,
+		done:    make(chan struct{}),
+		log:     logger.WithPrefix("Replayer"),
+	}
+	rp.BaseProcessor = processors.NewBaseProcessor("Replayer", rp)
+	return rp, nil
+}
+
+// NewFileReplayer creates a replayer from the recording at path
+func NewFileReplayer(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	return NewReplayer(f)
+}
+
+// Done is closed once every recorded frame has been emitted
+func (r *Replayer) Done() <-chan struct{} {
+	return r.done
+}
+
+func (r *Replayer) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.StartFrame); ok && direction == frames.Downstream {
+		if err := r.PushFrame(frame, direction); err != nil {
+			return err
+		}
+		r.once.Do(func() {
+			go r.replay(ctx)
+		})
+		return nil
+	}
+	return r.PushFrame(frame, direction)
+}
+
+func (r *Replayer) replay(ctx context.Context) {
+	defer close(r.done)
+
+	if len(r.records) == 0 {
+		return
+	}
+
+	origin := r.records[0].Time
+	start := time.Now()
+	emitted := 0
+
+	for _, rec := range r.records {
+		if rec.Direction != frames.Downstream.String() || rec.Type == "StartFrame" {
+			continue
+		}
+
+		frame, err := decodeFrame(rec)
+		if err != nil {
+			r.log.Warn("Skipping recorded frame: %v", err)
+			continue
+		}
+
+		if wait := rec.Time.Sub(origin) - time.Since(start); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := r.PushFrame(frame, frames.Downstream); err != nil {
+			r.log.Warn("Error replaying %s: %v", rec.Type, err)
+		}
+		emitted++
+	}
+
+	r.log.Info("Replayed %d frames", emitted)
+}
@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// recordedSession is a short call: user speaks, bot answers, user barges in
+func recordedSession() []frames.Frame {
+	audio := frames.NewAudioFrame([]byte{0x00, 0x7F, 0xFF, 0x80}, 8000, 1)
+	audio.SetMetadata("codec", "mulaw")
+
+	ttsAudio := frames.NewTTSAudioFrame([]byte{0x01, 0x02, 0x03, 0x04}, 24000, 1)
+	ttsAudio.ContextID = "ctx-1"
+
+	return []frames.Frame{
+		frames.NewStartFrame(),
+		audio,
+		frames.NewUserStartedSpeakingFrame(),
+		frames.NewTranscriptionFrame("what's the weather", true),
+		frames.NewUserStoppedSpeakingFrame(),
+		frames.NewLLMFullResponseStartFrame(),
+		frames.NewLLMTextFrame("It is sunny."),
+		frames.NewTTSStartedFrameWithContext("ctx-1"),
+		ttsAudio,
+		frames.NewInterruptionFrame(),
+		frames.NewTextFrame("and tomorrow?"),
+		frames.NewEndFrame(),
+	}
+}
+
+// comparableRecord encodes a frame without timing or direction so frames
+// can be compared by content
+func comparableRecord(t *testing.T, frame frames.Frame) frameRecord {
+	t.Helper()
+	rec, ok := encodeFrame(frame)
+	if !ok {
+		t.Fatalf("frame %s cannot be recorded", frame.Name())
+	}
+	return rec
+}
+
+func TestRecorderReplayerRoundTrip(t *testing.T) {
+	const gap = 15 * time.Millisecond
+
+	var recording bytes.Buffer
+	recorder := NewRecorder(&recording)
+	ctx := context.Background()
+
+	session := recordedSession()
+	for _, frame := range session {
+		if err := recorder.HandleFrame(ctx, frame, frames.Downstream); err != nil {
+			t.Fatalf("record %s: %v", frame.Name(), err)
+		}
+		time.Sleep(gap)
+	}
+	// Upstream frames are recorded but not replayed by a source
+	if err := recorder.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Upstream); err != nil {
+		t.Fatalf("record upstream frame: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("close recorder: %v", err)
+	}
+
+	if lines := strings.Count(recording.String(), "\n"); lines != len(session)+1 {
+		t.Fatalf("expected %d recorded lines, got %d", len(session)+1, lines)
+	}
+
+	replayer, err := NewReplayer(&recording)
+	if err != nil {
+		t.Fatalf("NewReplayer: %v", err)
+	}
+	tracker := newDirectionTrackingProcessor("tracker")
+	task := NewPipelineTask(NewPipeline([]processors.FrameProcessor{replayer, tracker}))
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	if err := task.Run(runCtx); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	var observed []frames.Frame
+	for _, tf := range tracker.frames {
+		if _, ok := tf.frame.(*frames.StartFrame); ok {
+			continue // the pipeline's own StartFrame
+		}
+		if tf.direction == frames.Downstream {
+			observed = append(observed, tf.frame)
+		}
+	}
+
+	expected := session[1:]
+	if len(observed) != len(expected) {
+		names := make([]string, len(observed))
+		for i, f := range observed {
+			names[i] = f.Name()
+		}
+		t.Fatalf("expected %d replayed frames, got %d: %v", len(expected), len(observed), names)
+	}
+	for i := range expected {
+		want := comparableRecord(t, expected[i])
+		got := comparableRecord(t, observed[i])
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("frame %d mismatch:\n got  %+v\n want %+v", i, got, want)
+		}
+	}
+
+	// Original spacing is kept: the first replayed frame comes one gap after
+	// the recorded StartFrame and the last after len(session)-1 gaps
+	if minElapsed := time.Duration(len(session)-1) * gap; elapsed < minElapsed {
+		t.Errorf("expected replay to take at least %v, took %v", minElapsed, elapsed)
+	}
+
+	select {
+	case <-replayer.Done():
+	case <-time.After(time.Second):
+		t.Error("expected replayer to be done")
+	}
+}
+
+func TestReplayerRejectsMalformedRecording(t *testing.T) {
+	if _, err := NewReplayer(strings.NewReader("{not json}\n")); err == nil {
+		t.Fatal("expected error for malformed recording")
+	}
+}
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// TaskStats summarizes a call for analytics
+type TaskStats struct {
+	Turns         int           // User turns started
+	Interruptions int           // Interruptions, counting a broadcast pair once
+	UserTalkTime  time.Duration // Total time the user was speaking
+	BotTalkTime   time.Duration // Total time the bot was speaking
+}
+
+// recentFrames is how many tracked frames statsTracker remembers to count
+// each once, however many processors it passes through
+const recentFrames = 32
+
+// statsTracker builds TaskStats from the frames every processor handles, so
+// speaking frames count even if a processor (such as a transport output)
+// consumes them before they reach the ends of the pipeline
+type statsTracker struct {
+	mu    sync.Mutex
+	stats TaskStats
+
+	userSince time.Time // Zero when the user isn't speaking
+	botSince  time.Time // Zero when the bot isn't speaking
+
+	lastInterruptionSibling string
+
+	recent     [recentFrames]uint64 // IDs of the tracked frames seen last
+	recentNext int
+}
+
+func (s *statsTracker) observe(frame frames.Frame, now time.Time) {
+	switch frame.(type) {
+	case *frames.UserStartedSpeakingFrame, *frames.UserStoppedSpeakingFrame,
+		*frames.BotStartedSpeakingFrame, *frames.BotStoppedSpeakingFrame,
+		*frames.InterruptionFrame:
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seenLocked(frame.ID()) {
+		return
+	}
+
+	switch f := frame.(type) {
+	case *frames.UserStartedSpeakingFrame:
+		if s.userSince.IsZero() {
+			s.userSince = now
+			s.stats.Turns++
+		}
+	case *frames.UserStoppedSpeakingFrame:
+		if !s.userSince.IsZero() {
+			s.stats.UserTalkTime += now.Sub(s.userSince)
+			s.userSince = time.Time{}
+		}
+	case *frames.BotStartedSpeakingFrame:
+		if s.botSince.IsZero() {
+			s.botSince = now
+		}
+	case *frames.BotStoppedSpeakingFrame:
+		if !s.botSince.IsZero() {
+			s.stats.BotTalkTime += now.Sub(s.botSince)
+			s.botSince = time.Time{}
+		}
+	case *frames.InterruptionFrame:
+		// A broadcast interruption reaches both ends as a sibling pair
+		sibling := f.GetBroadcastSiblingID()
+		if sibling != "" && sibling == s.lastInterruptionSibling {
+			return
+		}
+		s.lastInterruptionSibling = sibling
+		s.stats.Interruptions++
+	}
+}
+
+// seenLocked reports whether the frame with id was already counted, and
+// remembers it if not. s.mu must be held.
+func (s *statsTracker) seenLocked(id uint64) bool {
+	for _, seen := range s.recent {
+		if seen == id {
+			return true
+		}
+	}
+	s.recent[s.recentNext] = id
+	s.recentNext = (s.recentNext + 1) % recentFrames
+	return false
+}
+
+// snapshot returns the stats so far, including speech still in progress
+func (s *statsTracker) snapshot(now time.Time) TaskStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	if !s.userSince.IsZero() {
+		stats.UserTalkTime += now.Sub(s.userSince)
+	}
+	if !s.botSince.IsZero() {
+		stats.BotTalkTime += now.Sub(s.botSince)
+	}
+	return stats
+}
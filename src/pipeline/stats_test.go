@@ -0,0 +1,131 @@
+package pipeline
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/transports"
+)
+
+func TestStatsTracker_ScriptedConversation(t *testing.T) {
+	var s statsTracker
+	base := time.Unix(100, 0)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	// Turn 1: the user asks, the bot answers in full
+	s.observe(frames.NewUserStartedSpeakingFrame(), at(0))
+	s.observe(frames.NewUserStoppedSpeakingFrame(), at(1500))
+	s.observe(frames.NewBotStartedSpeakingFrame(), at(2000))
+	s.observe(frames.NewBotStoppedSpeakingFrame(), at(5000))
+
+	// Turn 2: the user barges in; the broadcast pair reaches both ends
+	s.observe(frames.NewBotStartedSpeakingFrame(), at(6000))
+	s.observe(frames.NewUserStartedSpeakingFrame(), at(7000))
+	down, up := frames.NewInterruptionFrame(), frames.NewInterruptionFrame()
+	down.BroadcastSiblingID, up.BroadcastSiblingID = "pair-1", "pair-1"
+	s.observe(down, at(7300))
+	s.observe(up, at(7300))
+	s.observe(frames.NewBotStoppedSpeakingFrame(), at(7400))
+	s.observe(frames.NewUserStoppedSpeakingFrame(), at(9000))
+
+	// Turn 3 is still going
+	s.observe(frames.NewUserStartedSpeakingFrame(), at(10000))
+
+	got := s.snapshot(at(10500))
+	want := TaskStats{
+		Turns:         3,
+		Interruptions: 1,
+		UserTalkTime:  1500*time.Millisecond + 2000*time.Millisecond + 500*time.Millisecond,
+		BotTalkTime:   3000*time.Millisecond + 1400*time.Millisecond,
+	}
+	if got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestPipelineTask_StatsFromPipelineEnds(t *testing.T) {
+	pipe := NewPipeline([]processors.FrameProcessor{processors.NewPassthroughProcessor("Passthrough", false)})
+	task := NewPipelineTask(pipe)
+	done := runTask(task)
+
+	queue := func(frame frames.Frame, direction frames.FrameDirection) {
+		t.Helper()
+		if err := queueWhenReady(task, frame, direction); err != nil {
+			t.Fatalf("QueueFrame(%s) failed: %v", frame.Name(), err)
+		}
+	}
+	queue(frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	queue(frames.NewBotStartedSpeakingFrame(), frames.Upstream)
+	queue(frames.NewInterruptionFrame(), frames.Downstream)
+	queue(frames.NewBotStoppedSpeakingFrame(), frames.Upstream)
+	queue(frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stats := task.Stats(); stats.Turns == 1 && stats.Interruptions == 1 && stats.BotTalkTime > 0 && stats.UserTalkTime > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	stats := task.Stats()
+	if stats.Turns != 1 || stats.Interruptions != 1 {
+		t.Errorf("expected 1 turn and 1 interruption, got %+v", stats)
+	}
+	if stats.UserTalkTime <= 0 || stats.BotTalkTime <= 0 {
+		t.Errorf("expected user and bot talk time, got %+v", stats)
+	}
+
+	task.Cancel()
+	<-done
+}
+
+// TestPipelineTask_StatsPastWebSocketOutput counts user turns the WebSocket
+// output consumes before they reach the sink
+func TestPipelineTask_StatsPastWebSocketOutput(t *testing.T) {
+	transport := transports.NewWebSocketTransport(transports.WebSocketConfig{
+		Serializer: serializers.NewJSONFrameSerializer(serializers.JSONSerializerConfig{}),
+	})
+	pipe := NewPipeline([]processors.FrameProcessor{
+		processors.NewPassthroughProcessor("Passthrough", false),
+		transport.Output(),
+	})
+	task := NewPipelineTask(pipe)
+	done := runTask(task)
+
+	var observed int
+	var mu sync.Mutex
+	task.SetObserverFunc(func(processor string, frame frames.Frame, direction frames.FrameDirection) {
+		if _, ok := frame.(*frames.UserStartedSpeakingFrame); ok {
+			mu.Lock()
+			observed++
+			mu.Unlock()
+		}
+	})
+
+	for _, frame := range []frames.Frame{frames.NewUserStartedSpeakingFrame(), frames.NewUserStoppedSpeakingFrame()} {
+		if err := queueWhenReady(task, frame); err != nil {
+			t.Fatalf("QueueFrame(%s) failed: %v", frame.Name(), err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for task.Stats().UserTalkTime == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if stats := task.Stats(); stats.Turns != 1 || stats.UserTalkTime <= 0 {
+		t.Errorf("expected 1 turn with user talk time, got %+v", stats)
+	}
+	mu.Lock()
+	if observed < 2 {
+		t.Errorf("expected SetObserverFunc to still see the frame at each processor, saw it %d times", observed)
+	}
+	mu.Unlock()
+
+	task.Cancel()
+	<-done
+}
@@ -2,18 +2,61 @@ package pipeline
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
 	"github.com/square-key-labs/strawgo-ai/src/turns"
 )
 
+// DefaultShutdownTimeout is how long shutdown waits for processors to finish
+// before force-cancelling them
+const DefaultShutdownTimeout = 10 * time.Second
+
+// DefaultHangupTimeout is how long Hangup waits for the goodbye to finish
+// playing before ending the call anyway
+const DefaultHangupTimeout = 30 * time.Second
+
 // PipelineTaskConfig holds configuration for pipeline task
 type PipelineTaskConfig struct {
 	AllowInterruptions bool
 	TurnStrategies     turns.UserTurnStrategies
+
+	// InterruptionLLMPolicy decides whether an interruption cancels the LLM
+	// response in flight or lets it finish silently so the context keeps the
+	// whole reply (default: abort)
+	InterruptionLLMPolicy frames.InterruptionLLMPolicy
+
+	// ShutdownTimeout bounds each shutdown phase: after an EndFrame or
+	// CancelFrame is queued or pushed by a processor, the pipeline gets this
+	// long to drain before it is force-cancelled, and stopping the processors gets this long again
+	// (default: 10s)
+	ShutdownTimeout time.Duration
+
+	// HangupTimeout bounds how long Hangup waits for the goodbye to play out
+	// before ending the call (default: 30s)
+	HangupTimeout time.Duration
+
+	// TemplateContext holds pipeline-wide variables for greeting, filler and
+	// fallback templates. Per-call values from the transport take precedence.
+	TemplateContext processors.TemplateContext
+
+	// LLMContext is the conversation context shared by the aggregators and
+	// the LLM. When SavedContext (from LLMContext.MarshalJSON) is set it is
+	// restored into LLMContext before the StartFrame, resuming the conversation.
+	LLMContext   *services.LLMContext
+	SavedContext []byte
+
+	// AudioFormatCheck decides what happens when processors' declared audio
+	// formats don't line up (default: AudioFormatCheckWarn). With
+	// AudioFormatCheckError, Run returns the mismatch.
+	AudioFormatCheck AudioFormatCheck
 }
 
 // DefaultPipelineTaskConfig returns default configuration
@@ -34,7 +77,8 @@ type PipelineTask struct {
 	log      *logger.Logger
 
 	// Configuration
-	config *PipelineTaskConfig
+	config  *PipelineTaskConfig
+	initErr error // From Pipeline.Initialize, returned by Run
 
 	// Frame queuing
 	userFrameQueue chan userFrameQueueItem
@@ -44,6 +88,21 @@ type PipelineTask struct {
 	finished bool
 	mu       sync.RWMutex
 
+	// Force-cancels if a queued EndFrame doesn't finish the pipeline in time
+	shutdownTimer *time.Timer
+	shutdownMu    sync.Mutex
+
+	// Closed when the bot stops speaking during a Hangup, once it has
+	// started speaking since the goodbye was queued
+	hangupStopped chan struct{}
+	hangupStarted bool
+	hangupMu      sync.Mutex
+
+	stats statsTracker
+
+	// Hook from SetObserverFunc, called after the task's own stats hook
+	observerFunc atomic.Pointer[processors.ObserverFunc]
+
 	// Event handlers
 	onStarted  func()
 	onFinished func()
@@ -71,7 +130,8 @@ func NewPipelineTaskWithConfig(pipeline *Pipeline, config *PipelineTaskConfig) *
 	}
 
 	// Initialize the pipeline with this task
-	pipeline.Initialize(task)
+	task.initErr = pipeline.Initialize(task)
+	pipeline.SetObserverFunc(task.observeFrame)
 
 	return task
 }
@@ -91,6 +151,11 @@ func (t *PipelineTask) OnError(callback func(error)) {
 	t.onError = callback
 }
 
+// Stats returns the call's turn, interruption and talk-time counters so far
+func (t *PipelineTask) Stats() TaskStats {
+	return t.stats.snapshot(time.Now())
+}
+
 func (t *PipelineTask) SetObserver(observer *TaskObserver) {
 	t.mu.Lock()
 	t.observer = observer
@@ -99,6 +164,32 @@ func (t *PipelineTask) SetObserver(observer *TaskObserver) {
 	t.pipeline.SetObserver(observer)
 }
 
+// SetObserverFunc registers a hook that every processor in the pipeline calls
+// synchronously for each frame before handling it. Frames from a single
+// processor are observed in the order that processor handles them.
+func (t *PipelineTask) SetObserverFunc(fn processors.ObserverFunc) {
+	if fn == nil {
+		t.observerFunc.Store(nil)
+		return
+	}
+	t.observerFunc.Store(&fn)
+}
+
+// observeFrame is the hook every processor calls for each frame: it feeds
+// Stats, arms the shutdown deadline for an EndFrame or CancelFrame pushed
+// from inside the pipeline (e.g. by the transport when the client hangs up),
+// then calls the hook from SetObserverFunc
+func (t *PipelineTask) observeFrame(processor string, frame frames.Frame, direction frames.FrameDirection) {
+	t.stats.observe(frame, time.Now())
+	switch frame.(type) {
+	case *frames.EndFrame, *frames.CancelFrame:
+		t.armShutdownDeadline(frame.Name())
+	}
+	if fn := t.observerFunc.Load(); fn != nil {
+		(*fn)(processor, frame, direction)
+	}
+}
+
 // QueueFrame adds a frame to be processed by the pipeline
 // direction is optional; defaults to Downstream if not specified
 func (t *PipelineTask) QueueFrame(frame frames.Frame, direction ...frames.FrameDirection) error {
@@ -121,10 +212,134 @@ func (t *PipelineTask) QueueFrame(frame frames.Frame, direction ...frames.FrameD
 
 	select {
 	case t.userFrameQueue <- userFrameQueueItem{frame: frame, direction: dir}:
-		return nil
 	case <-t.ctx.Done():
 		return t.ctx.Err()
 	}
+
+	switch frame.(type) {
+	case *frames.EndFrame, *frames.CancelFrame:
+		t.armShutdownDeadline(frame.Name())
+	}
+	return nil
+}
+
+// shutdownTimeout returns the configured shutdown timeout or the default
+func (t *PipelineTask) shutdownTimeout() time.Duration {
+	if t.config.ShutdownTimeout > 0 {
+		return t.config.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
+}
+
+// armShutdownDeadline force-cancels the pipeline if reason (an EndFrame or
+// CancelFrame, queued or seen by any processor) hasn't reached the sink
+// within the shutdown timeout, e.g. because a processor is stuck. Only the
+// first request starts the clock.
+func (t *PipelineTask) armShutdownDeadline(reason string) {
+	t.shutdownMu.Lock()
+	defer t.shutdownMu.Unlock()
+	if t.shutdownTimer != nil {
+		return
+	}
+
+	timeout := t.shutdownTimeout()
+	t.shutdownTimer = time.AfterFunc(timeout, func() {
+		if t.ctx.Err() != nil {
+			return // Finished in time
+		}
+		t.log.Error("%s did not finish the pipeline within %v, force-cancelling", reason, timeout)
+		t.Cancel()
+	})
+}
+
+// UpdateSystemPrompt swaps the LLM system prompt without dropping the
+// conversation. The next generation uses the new prompt.
+func (t *PipelineTask) UpdateSystemPrompt(prompt string) error {
+	return t.QueueFrame(frames.NewLLMSystemPromptUpdateFrame(prompt))
+}
+
+// Hangup ends the call gracefully: goodbye is spoken in full, with barge-in
+// ignored, and the call ends (EndFrame, which closes the transport) once the
+// bot stops speaking, i.e. once the transport confirms playback. An empty
+// goodbye ends the call right away. Hangup returns as soon as the goodbye is
+// queued; if playback isn't confirmed within HangupTimeout the call is ended
+// anyway. The goodbye needs a user aggregator in the pipeline.
+func (t *PipelineTask) Hangup(goodbye string) error {
+	if goodbye == "" {
+		return t.QueueFrame(frames.NewEndFrame())
+	}
+
+	t.hangupMu.Lock()
+	if t.hangupStopped != nil {
+		t.hangupMu.Unlock()
+		return fmt.Errorf("hangup already in progress")
+	}
+	stopped := make(chan struct{})
+	t.hangupStopped = stopped
+	t.hangupStarted = false
+	t.hangupMu.Unlock()
+
+	// The end of "response" flushes the goodbye through TTS and lets the
+	// output report when it has finished playing
+	for _, frame := range []frames.Frame{frames.NewForceSpeakFrame(goodbye), frames.NewLLMFullResponseEndFrame()} {
+		if err := t.QueueFrame(frame); err != nil {
+			t.hangupMu.Lock()
+			t.hangupStopped = nil
+			t.hangupMu.Unlock()
+			return err
+		}
+	}
+	t.log.Info("Hanging up after goodbye: '%s'", goodbye)
+
+	timeout := t.config.HangupTimeout
+	if timeout <= 0 {
+		timeout = DefaultHangupTimeout
+	}
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-stopped:
+			t.log.Info("Goodbye played, ending call")
+		case <-timer.C:
+			t.log.Warn("Goodbye not played within %v, ending call", timeout)
+		case <-t.ctx.Done():
+			return
+		}
+		if err := t.QueueFrame(frames.NewEndFrame()); err != nil {
+			t.log.Warn("Error queuing EndFrame after goodbye: %v", err)
+		}
+	}()
+	return nil
+}
+
+// observeHangup completes a pending Hangup when the bot stops speaking. Only
+// a stop that follows a start seen after the goodbye was queued counts: the
+// stop of an utterance already playing at Hangup time says nothing about the
+// goodbye.
+func (t *PipelineTask) observeHangup(frame frames.Frame) {
+	switch frame.(type) {
+	case *frames.BotStartedSpeakingFrame, *frames.BotStoppedSpeakingFrame:
+	default:
+		return
+	}
+	t.hangupMu.Lock()
+	defer t.hangupMu.Unlock()
+	if t.hangupStopped == nil {
+		return
+	}
+	if _, ok := frame.(*frames.BotStartedSpeakingFrame); ok {
+		t.hangupStarted = true
+		return
+	}
+	if !t.hangupStarted {
+		return
+	}
+	select {
+	case <-t.hangupStopped:
+	default:
+		close(t.hangupStopped)
+	}
 }
 
 // Run starts the pipeline and runs until completion
@@ -140,6 +355,20 @@ func (t *PipelineTask) Run(ctx context.Context) error {
 
 	t.log.Info("Starting pipeline")
 
+	if t.initErr != nil {
+		return fmt.Errorf("failed to initialize pipeline: %w", t.initErr)
+	}
+
+	if len(t.config.SavedContext) > 0 {
+		if t.config.LLMContext == nil {
+			return fmt.Errorf("SavedContext requires LLMContext")
+		}
+		if err := json.Unmarshal(t.config.SavedContext, t.config.LLMContext); err != nil {
+			return fmt.Errorf("failed to restore LLM context: %w", err)
+		}
+		t.log.Info("Restored LLM context (%d messages)", len(t.config.LLMContext.Messages))
+	}
+
 	// Start the pipeline
 	if err := t.pipeline.Start(t.ctx); err != nil {
 		return fmt.Errorf("failed to start pipeline: %w", err)
@@ -162,6 +391,8 @@ func (t *PipelineTask) Run(ctx context.Context) error {
 		t.config.AllowInterruptions,
 		t.config.TurnStrategies,
 	)
+	startFrame.TemplateVars = t.config.TemplateContext
+	startFrame.InterruptionLLMPolicy = t.config.InterruptionLLMPolicy
 	if err := t.pipeline.QueueFrame(startFrame); err != nil {
 		return fmt.Errorf("failed to queue start frame: %w", err)
 	}
@@ -169,6 +400,12 @@ func (t *PipelineTask) Run(ctx context.Context) error {
 	// Wait for completion
 	t.wg.Wait()
 
+	t.shutdownMu.Lock()
+	if t.shutdownTimer != nil {
+		t.shutdownTimer.Stop()
+	}
+	t.shutdownMu.Unlock()
+
 	// Stop the pipeline
 	if err := t.pipeline.Stop(); err != nil {
 		t.log.Warn("Error stopping pipeline: %v", err)
@@ -225,6 +462,7 @@ func (t *PipelineTask) processUserFrames() {
 // handleDownstreamFrame handles frames that reach the sink
 func (t *PipelineTask) handleDownstreamFrame(frame frames.Frame) error {
 	t.log.Debug("Frame reached sink: %s", frame.Name())
+	t.observeHangup(frame)
 
 	// Handle lifecycle frames
 	switch frame.(type) {
@@ -258,13 +496,20 @@ func (t *PipelineTask) handleDownstreamFrame(frame frames.Frame) error {
 // handleUpstreamFrame handles frames going back up the pipeline
 func (t *PipelineTask) handleUpstreamFrame(frame frames.Frame) error {
 	t.log.Debug("Upstream frame from pipeline: %s", frame.Name())
+	t.observeHangup(frame)
 
 	// Handle InterruptionTaskFrame - convert to InterruptionFrame and send downstream
-	if _, ok := frame.(*frames.InterruptionTaskFrame); ok {
+	if taskFrame, ok := frame.(*frames.InterruptionTaskFrame); ok {
 		t.log.Warn("InterruptionTaskFrame is deprecated; use BaseProcessor.BroadcastInterruption() instead")
 		t.log.Warn("Received InterruptionTaskFrame, sending InterruptionFrame downstream")
-		// Send interruption frame downstream to all processors
-		if err := t.pipeline.QueueFrame(frames.NewInterruptionFrame()); err != nil {
+		// Send interruption frame downstream to all processors, keeping the
+		// task frame's correlation ID
+		interruption := frames.NewInterruptionFrame()
+		if taskFrame.CorrelationID != "" {
+			interruption.CorrelationID = taskFrame.CorrelationID
+		}
+		t.traceInterruption(interruption.CorrelationID, frames.InterruptionStageFrameGenerated)
+		if err := t.pipeline.QueueFrame(interruption); err != nil {
 			t.log.Error("Error queuing interruption frame: %v", err)
 			return err
 		}
@@ -281,6 +526,14 @@ func (t *PipelineTask) handleUpstreamFrame(frame frames.Frame) error {
 	return nil
 }
 
+// traceInterruption reports an interruption stage reached by the task itself
+func (t *PipelineTask) traceInterruption(correlationID string, stage frames.InterruptionStage) {
+	t.log.Debug("Interruption %s: %s", correlationID, stage)
+	if observer := t.getObserver(); observer != nil {
+		observer.OnInterruptionStage("PipelineTask", correlationID, stage)
+	}
+}
+
 func (t *PipelineTask) markFinished() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -267,3 +267,49 @@ func (p *directionTrackingProcessor) HandleFrame(ctx context.Context, frame fram
 	p.mu.Unlock()
 	return p.PushFrame(frame, direction)
 }
+
+func TestPipelineTaskUpdateSystemPrompt(t *testing.T) {
+	tracker := newDirectionTrackingProcessor("tracker")
+	task := NewPipelineTask(NewPipeline([]processors.FrameProcessor{tracker}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- task.Run(ctx)
+	}()
+
+	if err := queueWhenReady(task, frames.NewTextFrame("warmup")); err != nil {
+		t.Fatalf("queue warmup frame: %v", err)
+	}
+	if err := task.UpdateSystemPrompt("You are a support agent"); err != nil {
+		t.Fatalf("UpdateSystemPrompt: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var update *frames.LLMMessagesUpdateFrame
+	for update == nil && time.Now().Before(deadline) {
+		tracker.mu.Lock()
+		for _, tf := range tracker.frames {
+			if f, ok := tf.frame.(*frames.LLMMessagesUpdateFrame); ok {
+				update = f
+			}
+		}
+		tracker.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if update == nil {
+		t.Fatal("timed out waiting for system prompt update frame")
+	}
+	if update.SystemPrompt != "You are a support agent" || update.Messages != nil {
+		t.Errorf("expected prompt-only update, got prompt=%q messages=%v", update.SystemPrompt, update.Messages)
+	}
+
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("queue end frame: %v", err)
+	}
+	if err := waitRunResult(t, done); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// hangingProcessor blocks in HandleFrame, ignoring its context, on the first
+// frame of the given name until release is closed
+type hangingProcessor struct {
+	*processors.BaseProcessor
+	hangOn  string
+	release chan struct{}
+	hung    chan struct{}
+}
+
+func newHangingProcessor(t *testing.T, hangOn string) *hangingProcessor {
+	p := &hangingProcessor{
+		hangOn:  hangOn,
+		release: make(chan struct{}),
+		hung:    make(chan struct{}, 1),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("Hanging", p)
+	t.Cleanup(func() { close(p.release) })
+	return p
+}
+
+func (p *hangingProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if frame.Name() == p.hangOn {
+		p.hung <- struct{}{}
+		<-p.release
+	}
+	return p.PushFrame(frame, direction)
+}
+
+// runTask runs task in the background and returns when Run does
+func runTask(task *PipelineTask) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- task.Run(context.Background()) }()
+	return done
+}
+
+func TestPipelineTask_ShutdownTimeoutOnStuckEndFrame(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	hang := newHangingProcessor(t, "EndFrame")
+	pipe := NewPipeline([]processors.FrameProcessor{hang})
+	task := NewPipelineTaskWithConfig(pipe, &PipelineTaskConfig{ShutdownTimeout: timeout})
+
+	done := runTask(task)
+	if err := queueWhenReady(task, frames.NewEndFrame()); err != nil {
+		t.Fatalf("QueueFrame(EndFrame) failed: %v", err)
+	}
+	<-hang.hung
+	start := time.Now()
+
+	// One timeout waiting for the EndFrame, one for the processors to stop
+	select {
+	case <-done:
+	case <-time.After(4 * timeout):
+		t.Fatal("shutdown hung past the timeout")
+	}
+	if elapsed := time.Since(start); elapsed < timeout {
+		t.Errorf("expected shutdown to wait %v for graceful completion, force-cancelled after %v", timeout, elapsed)
+	}
+}
+
+// enderProcessor pushes an EndFrame in place of the first TextFrame, as a
+// transport does when the client hangs up
+type enderProcessor struct {
+	*processors.BaseProcessor
+}
+
+func (p *enderProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.TextFrame); ok {
+		return p.PushFrame(frames.NewEndFrame(), direction)
+	}
+	return p.PushFrame(frame, direction)
+}
+
+func TestPipelineTask_ShutdownTimeoutOnEndFrameFromProcessor(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	ender := &enderProcessor{}
+	ender.BaseProcessor = processors.NewBaseProcessor("Ender", ender)
+	hang := newHangingProcessor(t, "EndFrame")
+	pipe := NewPipeline([]processors.FrameProcessor{ender, hang})
+	task := NewPipelineTaskWithConfig(pipe, &PipelineTaskConfig{ShutdownTimeout: timeout})
+
+	done := runTask(task)
+	if err := queueWhenReady(task, frames.NewTextFrame("bye")); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	<-hang.hung
+
+	select {
+	case <-done:
+	case <-time.After(4 * timeout):
+		t.Fatal("EndFrame pushed by a processor did not arm the shutdown deadline")
+	}
+}
+
+func TestPipelineTask_CancelHonorsShutdownTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	hang := newHangingProcessor(t, "TextFrame")
+	pipe := NewPipeline([]processors.FrameProcessor{hang})
+	task := NewPipelineTaskWithConfig(pipe, &PipelineTaskConfig{ShutdownTimeout: timeout})
+
+	done := runTask(task)
+	if err := queueWhenReady(task, frames.NewTextFrame("stuck")); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	<-hang.hung
+
+	task.Cancel()
+	select {
+	case <-done:
+	case <-time.After(3 * timeout):
+		t.Fatal("Cancel did not finish the pipeline within the shutdown timeout")
+	}
+}
+
+func TestPipeline_StopReportsStuckProcessors(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	first := processors.NewPassthroughProcessor("First", false)
+	hang := newHangingProcessor(t, "TextFrame")
+	pipe := NewPipeline([]processors.FrameProcessor{first, hang})
+	pipe.Initialize(nil)
+	pipe.shutdownTimeout = timeout
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := pipe.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := pipe.QueueFrame(frames.NewTextFrame("stuck")); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	<-hang.hung
+
+	start := time.Now()
+	err := pipe.Stop()
+	if elapsed := time.Since(start); elapsed > 3*timeout {
+		t.Errorf("expected Stop to give up after %v, took %v", timeout, elapsed)
+	}
+	if err == nil {
+		t.Fatal("expected an error naming the stuck processors")
+	}
+	// Processors are stopped in reverse, so First is still queued behind Hanging
+	for _, name := range []string{"Hanging", "First", "PipelineSource"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error to name %s, got %v", name, err)
+		}
+	}
+	if strings.Contains(err.Error(), "PipelineSink") {
+		t.Errorf("expected the sink to have stopped, got %v", err)
+	}
+}
+
+func TestPipelineTask_HangupWaitsForGoodbyeSpeech(t *testing.T) {
+	pipe := NewPipeline([]processors.FrameProcessor{processors.NewPassthroughProcessor("Output", false)})
+	task := NewPipelineTaskWithConfig(pipe, &PipelineTaskConfig{HangupTimeout: 5 * time.Second})
+
+	done := runTask(task)
+	// An utterance is already playing when the call is hung up
+	if err := queueWhenReady(task, frames.NewBotStartedSpeakingFrame()); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := task.Hangup("Goodbye!"); err != nil {
+		t.Fatalf("Hangup failed: %v", err)
+	}
+
+	// The current utterance ending must not end the call
+	if err := task.QueueFrame(frames.NewBotStoppedSpeakingFrame()); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("call ended when the utterance playing at hangup stopped, before the goodbye")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// The goodbye plays and stops
+	for _, frame := range []frames.Frame{frames.NewBotStartedSpeakingFrame(), frames.NewBotStoppedSpeakingFrame()} {
+		if err := task.QueueFrame(frame); err != nil {
+			t.Fatalf("QueueFrame failed: %v", err)
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("call did not end after the goodbye stopped")
+	}
+}
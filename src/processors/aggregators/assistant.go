@@ -3,6 +3,8 @@ package aggregators
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -16,8 +18,20 @@ type AssistantAggregatorParams struct {
 	AutoSummarizationConfig        LLMAutoContextSummarizationConfig
 	SummaryLLM                     services.LLMService
 	MainLLM                        services.LLMService
+
+	// FunctionResultFormatter condenses a function result before it is
+	// stored as the tool message (e.g. extracting fields or summarizing).
+	// Nil stores the result JSON as-is.
+	FunctionResultFormatter FunctionResultFormatter
+	// FunctionResultMaxLength truncates stored function results longer than
+	// this many characters, after formatting (0 = unlimited)
+	FunctionResultMaxLength int
 }
 
+// FunctionResultFormatter turns a function's JSON result into the content
+// stored in context for the LLM
+type FunctionResultFormatter func(ctx context.Context, functionName, result string) string
+
 // DefaultAssistantAggregatorParams returns default parameters
 func DefaultAssistantAggregatorParams() *AssistantAggregatorParams {
 	return &AssistantAggregatorParams{}
@@ -71,8 +85,9 @@ func (a *LLMAssistantAggregator) HandleFrame(ctx context.Context, frame frames.F
 	if _, ok := frame.(*frames.InterruptionFrame); ok {
 		a.log.Info("Interruption received - clearing aggregation and resetting state")
 
-		// Push any accumulated aggregation before resetting
-		if len(a.aggregation) > 0 {
+		// Push any accumulated aggregation before resetting; an active
+		// response still gets its completion signal
+		if a.started > 0 || len(a.aggregation) > 0 {
 			if err := a.pushAggregation(ctx); err != nil {
 				a.log.Warn("Error pushing aggregation on interruption: %v", err)
 			}
@@ -120,7 +135,7 @@ func (a *LLMAssistantAggregator) HandleFrame(ctx context.Context, frame frames.F
 
 	// Handle TextFrame (from LLM) - accumulate if response is active
 	if textFrame, ok := frame.(*frames.TextFrame); ok {
-		if a.started > 0 {
+		if a.started > 0 && !textFrame.SkipContext {
 			a.log.Debug("Accumulating text: '%s'", textFrame.Text)
 			a.AppendToAggregation(textFrame.Text)
 			// Note: We don't set addSpaces here - keep default behavior
@@ -198,6 +213,10 @@ func (a *LLMAssistantAggregator) HandleFrame(ctx context.Context, frame frames.F
 			}
 		}
 
+		if resultFrame.Result != nil {
+			result = a.formatFunctionResult(ctx, resultFrame.FunctionName, result)
+		}
+
 		// Update the tool message in context
 		a.updateFunctionCallResult(resultFrame.FunctionName, resultFrame.ToolCallID, result)
 		a.maybeAutoSummarize(ctx)
@@ -244,37 +263,35 @@ func (a *LLMAssistantAggregator) HandleFrame(ctx context.Context, frame frames.F
 	return a.PushFrame(frame, direction)
 }
 
-// pushAggregation pushes the accumulated assistant response to context
+// pushAggregation adds the accumulated assistant response to the context
+// and pushes an AssistantResponseCompleteFrame. A response that is empty or
+// only whitespace (e.g. the LLM declining to speak after a tool call) is
+// never stored, but still gets the completion frame.
 func (a *LLMAssistantAggregator) pushAggregation(ctx context.Context) error {
-	if len(a.aggregation) == 0 {
-		a.log.Debug("No aggregation to push")
-		return nil
-	}
-
 	text := a.AggregationString()
-	a.log.Info("Pushing aggregation: '%s'", text)
 
 	// Reset aggregation
 	if err := a.Reset(); err != nil {
 		return err
 	}
 
-	// Add assistant message to context if we have content
-	if text != "" {
+	if strings.TrimSpace(text) == "" {
+		a.log.Debug("Empty assistant response, not adding to context")
+		text = ""
+	} else {
+		a.log.Info("Pushing aggregation: '%s'", text)
 		a.context.AddAssistantMessage(text)
 		a.maybeAutoSummarize(ctx)
-	}
 
-	// Push context frame downstream
-	if err := a.PushContextFrame(frames.Downstream); err != nil {
-		return err
+		// Push context frame downstream
+		if err := a.PushContextFrame(frames.Downstream); err != nil {
+			return err
+		}
 	}
 
-	// Push timestamp frame to mark completion
-	timestamp := time.Now().Format(time.RFC3339)
-	a.log.Info("Assistant response completed at %s", timestamp)
-
-	return nil
+	complete := frames.NewAssistantResponseCompleteFrame(text)
+	a.log.Info("Assistant response completed at %s", complete.Timestamp.Format(time.RFC3339))
+	return a.PushFrame(complete, frames.Downstream)
 }
 
 func (a *LLMAssistantAggregator) maybeAutoSummarize(ctx context.Context) {
@@ -297,6 +314,35 @@ func (a *LLMAssistantAggregator) forceSummarizeContext(ctx context.Context) {
 	a.summarizer.SummarizeContext(ctx, a.context, a.params.MainLLM)
 }
 
+// formatFunctionResult applies the configured formatter and length limit to
+// a function result before it enters the context
+func (a *LLMAssistantAggregator) formatFunctionResult(ctx context.Context, functionName, result string) string {
+	if a.params.FunctionResultFormatter != nil {
+		result = a.params.FunctionResultFormatter(ctx, functionName, result)
+	}
+	limit := a.params.FunctionResultMaxLength
+	if limit <= 0 {
+		return result
+	}
+	runes := []rune(result)
+	if len(runes) <= limit {
+		return result
+	}
+	a.log.Info("Truncating %s result from %d to %d characters", functionName, len(runes), limit)
+	return truncateFunctionResult(runes, limit)
+}
+
+// truncateFunctionResult cuts runes to at most limit characters, ending with
+// a marker so the LLM knows the result is incomplete
+func truncateFunctionResult(runes []rune, limit int) string {
+	marker := []rune(fmt.Sprintf("... [truncated, %d characters total]", len(runes)))
+	keep := limit - len(marker)
+	if keep <= 0 {
+		return string(runes[:limit])
+	}
+	return string(runes[:keep]) + string(marker)
+}
+
 // updateFunctionCallResult finds and updates a tool message in the context
 func (a *LLMAssistantAggregator) updateFunctionCallResult(functionName, toolCallID, result string) {
 	for i := range a.context.Messages {
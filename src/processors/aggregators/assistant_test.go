@@ -0,0 +1,198 @@
+package aggregators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// completionFrames returns the AssistantResponseCompleteFrames captured
+func completionFrames(c *captureProc) []*frames.AssistantResponseCompleteFrame {
+	var out []*frames.AssistantResponseCompleteFrame
+	for _, f := range c.get() {
+		if complete, ok := f.(*frames.AssistantResponseCompleteFrame); ok {
+			out = append(out, complete)
+		}
+	}
+	return out
+}
+
+// TestAssistantAggregator_EmptyResponses verifies only responses with real
+// content reach the context, while every response signals completion.
+func TestAssistantAggregator_EmptyResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string // Stored assistant message, "" for none
+	}{
+		{name: "no text", chunks: nil, want: ""},
+		{name: "empty text", chunks: []string{""}, want: ""},
+		{name: "whitespace only", chunks: []string{" ", "\n", "\t "}, want: ""},
+		{name: "normal response", chunks: []string{"Hello", "there."}, want: "Hello there."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+			aggregator := NewLLMAssistantAggregator(llmCtx, nil)
+			downstream := &captureProc{}
+			aggregator.Link(downstream)
+
+			aggregator.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+			for _, chunk := range tt.chunks {
+				aggregator.HandleFrame(ctx, frames.NewLLMTextFrame(chunk), frames.Downstream)
+			}
+			aggregator.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+			var stored []string
+			for _, msg := range llmCtx.Messages {
+				if msg.Role == "assistant" {
+					stored = append(stored, msg.Content)
+				}
+			}
+			if tt.want == "" && len(stored) != 0 {
+				t.Errorf("Expected no assistant message, got %q", stored)
+			}
+			if tt.want != "" && (len(stored) != 1 || stored[0] != tt.want) {
+				t.Errorf("Expected assistant message %q, got %q", tt.want, stored)
+			}
+
+			complete := completionFrames(downstream)
+			if len(complete) != 1 {
+				t.Fatalf("Expected 1 AssistantResponseCompleteFrame, got %d", len(complete))
+			}
+			if complete[0].Text != tt.want {
+				t.Errorf("Completion text = %q, want %q", complete[0].Text, tt.want)
+			}
+			if complete[0].Timestamp.IsZero() {
+				t.Error("Expected completion timestamp to be set")
+			}
+		})
+	}
+}
+
+// TestAssistantAggregator_InterruptedResponseCompletesOnce verifies an
+// interrupted response signals completion once, not again on its stale end.
+func TestAssistantAggregator_InterruptedResponseCompletesOnce(t *testing.T) {
+	ctx := context.Background()
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	aggregator := NewLLMAssistantAggregator(llmCtx, nil)
+	downstream := &captureProc{}
+	aggregator.Link(downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	if n := len(completionFrames(downstream)); n != 1 {
+		t.Errorf("Expected 1 AssistantResponseCompleteFrame, got %d", n)
+	}
+	if len(llmCtx.Messages) != 0 {
+		t.Errorf("Expected empty context, got %v", llmCtx.Messages)
+	}
+}
+
+// TestAssistantAggregator_SkipsProgressText verifies function-call progress
+// updates are forwarded for TTS but never stored in context.
+func TestAssistantAggregator_SkipsProgressText(t *testing.T) {
+	ctx := context.Background()
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	aggregator := NewLLMAssistantAggregator(llmCtx, nil)
+	downstream := &captureProc{}
+	aggregator.Link(downstream)
+
+	progress := frames.NewTextFrame("Still looking...")
+	progress.SkipContext = true
+
+	aggregator.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTextFrame("Let me check."), frames.Downstream)
+	aggregator.HandleFrame(ctx, progress, frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	for _, msg := range llmCtx.Messages {
+		if msg.Role == "assistant" && msg.Content != "Let me check." {
+			t.Errorf("Expected only the LLM text in context, got %q", msg.Content)
+		}
+	}
+
+	forwarded := false
+	for _, f := range downstream.get() {
+		if f == progress {
+			forwarded = true
+		}
+	}
+	if !forwarded {
+		t.Error("Expected the progress frame forwarded downstream")
+	}
+}
+
+// runFunctionCall sends one function call and its result through the
+// aggregator and returns the stored tool message content
+func runFunctionCall(t *testing.T, params *AssistantAggregatorParams, result interface{}) string {
+	t.Helper()
+	ctx := context.Background()
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	aggregator := NewLLMAssistantAggregator(llmCtx, params)
+	aggregator.Link(&captureProc{})
+
+	runLLM := false
+	aggregator.HandleFrame(ctx, frames.NewFunctionCallInProgressFrame("call-1", "search", nil, false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewFunctionCallResultFrame("call-1", "search", result, &runLLM), frames.Downstream)
+
+	for _, msg := range llmCtx.Messages {
+		if msg.Role == "tool" && msg.ToolCallID == "call-1" {
+			return msg.Content
+		}
+	}
+	t.Fatal("Expected a tool message in context")
+	return ""
+}
+
+// TestAssistantAggregator_TruncatesLargeFunctionResult verifies a huge tool
+// output is cut to FunctionResultMaxLength before entering context.
+func TestAssistantAggregator_TruncatesLargeFunctionResult(t *testing.T) {
+	large := map[string]interface{}{"rows": strings.Repeat("x", 50000)}
+
+	stored := runFunctionCall(t, &AssistantAggregatorParams{FunctionResultMaxLength: 200}, large)
+	if n := utf8.RuneCountInString(stored); n != 200 {
+		t.Errorf("Stored result is %d characters, want 200", n)
+	}
+	if !strings.HasPrefix(stored, `{"rows":"xxx`) {
+		t.Errorf("Expected the start of the result kept, got %q", stored[:20])
+	}
+	if !strings.Contains(stored, "truncated") {
+		t.Errorf("Expected a truncation marker, got %q", stored)
+	}
+
+	small := runFunctionCall(t, &AssistantAggregatorParams{FunctionResultMaxLength: 200}, map[string]interface{}{"ok": true})
+	if small != `{"ok":true}` {
+		t.Errorf("Small result = %q, want it stored unchanged", small)
+	}
+}
+
+// TestAssistantAggregator_FormatsFunctionResult verifies the formatter hook
+// condenses results before the length limit applies.
+func TestAssistantAggregator_FormatsFunctionResult(t *testing.T) {
+	var gotName string
+	params := &AssistantAggregatorParams{
+		FunctionResultFormatter: func(_ context.Context, functionName, result string) string {
+			gotName = functionName
+			return fmt.Sprintf("summary of %d characters", len(result))
+		},
+		FunctionResultMaxLength: 15,
+	}
+
+	stored := runFunctionCall(t, params, map[string]interface{}{"rows": strings.Repeat("x", 1000)})
+	if gotName != "search" {
+		t.Errorf("Formatter got function %q, want search", gotName)
+	}
+	if stored != "summary of 1011" {
+		t.Errorf("Stored result = %q, want the formatted summary cut to 15 characters", stored)
+	}
+}
@@ -0,0 +1,362 @@
+package aggregators
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+const defaultSlotExtractTimeout = 2 * time.Second
+
+// SlotExtractor pulls a slot's value out of a user utterance. Implementations
+// may be patterns, a parser or a small LLM call; they should respect ctx,
+// which is bounded by the processor's extract timeout. Return "" when the
+// utterance doesn't contain the slot.
+type SlotExtractor interface {
+	Extract(ctx context.Context, slot, text string) (string, error)
+}
+
+// SlotExtractorFunc adapts a function to SlotExtractor
+type SlotExtractorFunc func(ctx context.Context, slot, text string) (string, error)
+
+// Extract calls f(ctx, slot, text)
+func (f SlotExtractorFunc) Extract(ctx context.Context, slot, text string) (string, error) {
+	return f(ctx, slot, text)
+}
+
+// RegexSlotExtractor extracts a slot with a regular expression: the first
+// capture group if the pattern has one, otherwise the whole match. It panics
+// if pattern doesn't compile.
+func RegexSlotExtractor(pattern string) SlotExtractor {
+	re := regexp.MustCompile(pattern)
+	return SlotExtractorFunc(func(_ context.Context, _, text string) (string, error) {
+		match := re.FindStringSubmatch(text)
+		if match == nil {
+			return "", nil
+		}
+		if len(match) > 1 {
+			return strings.TrimSpace(match[1]), nil
+		}
+		return strings.TrimSpace(match[0]), nil
+	})
+}
+
+// DialogSlot is a value a dialog state collects from the user
+type DialogSlot struct {
+	Name      string
+	Extractor SlotExtractor            // Finds the value in an utterance (required)
+	Validate  func(value string) error // Optional; a rejected value is asked for again
+}
+
+// DialogState is one step of a slot-filling flow
+type DialogState struct {
+	Name string
+
+	// Prompt is spoken on entering the state. Like greetings it is a
+	// text/template, rendered against the call's TemplateContext plus the
+	// slots collected so far, e.g. "What time on {{.date}}?"
+	Prompt string
+
+	// Reprompt is spoken when a reply is missing a slot or fails validation
+	// (default: Prompt)
+	Reprompt string
+
+	Slots []DialogSlot
+
+	// Next picks the state to move to once every slot is filled; returning
+	// "" completes the dialog. Nil moves to the following state in
+	// DialogConfig.States, completing after the last.
+	Next func(slots map[string]string) string
+}
+
+// DialogConfig configures a DialogStateProcessor
+type DialogConfig struct {
+	// States is the flow; the first state is entered when the call starts
+	States []DialogState
+
+	// ExtractTimeout bounds each extractor call; on timeout or error the slot
+	// counts as missing. Default: 2s.
+	ExtractTimeout time.Duration
+
+	// HandOffMessage, when set, is appended to the LLM context as a system
+	// message once the dialog completes and the LLM is run, so it picks the
+	// conversation up. Rendered like prompts, e.g. "The caller booked
+	// {{.date}} at {{.time}}. Confirm and ask if they need anything else."
+	HandOffMessage string
+
+	// OnComplete is called with the collected slots when the dialog completes
+	OnComplete func(slots map[string]string)
+}
+
+// DialogStateProcessor runs a simple slot-filling flow, the kind of state
+// machine many IVR bots are, without an LLM round trip per turn.
+//
+// While the dialog is active it consumes the user's TranscriptionFrames:
+// each final transcription is run through the current state's extractors,
+// and once the state's slots are filled the next state's prompt is pushed
+// downstream as a TextFrame for TTS. Missing or invalid slots are asked for
+// again with the state's Reprompt. When the flow completes, transcriptions
+// pass through to the LLM path again, optionally with HandOffMessage
+// appended to its context.
+//
+// Insert between the STT service and the user aggregator:
+//
+//	pipeline.NewPipeline([]processors.FrameProcessor{
+//	    transport.Input(),
+//	    stt,
+//	    aggregators.NewDialogStateProcessor(aggregators.DialogConfig{...}),
+//	    userAgg,
+//	    llm,
+//	    tts,
+//	    ...,
+//	})
+type DialogStateProcessor struct {
+	*processors.BaseProcessor
+	config DialogConfig
+	states map[string]int // State index by name
+	log    *logger.Logger
+
+	mu        sync.Mutex
+	current   int // Index of the active state; -1 before start and after completion
+	completed bool
+	slots     map[string]string
+}
+
+// NewDialogStateProcessor creates a DialogStateProcessor for the flow in config
+func NewDialogStateProcessor(config DialogConfig) *DialogStateProcessor {
+	if config.ExtractTimeout <= 0 {
+		config.ExtractTimeout = defaultSlotExtractTimeout
+	}
+	states := make(map[string]int, len(config.States))
+	for i, state := range config.States {
+		states[state.Name] = i
+	}
+	p := &DialogStateProcessor{
+		config:  config,
+		states:  states,
+		log:     logger.WithPrefix("DialogState"),
+		current: -1,
+		slots:   make(map[string]string),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("DialogStateProcessor", p)
+	return p
+}
+
+// Slots returns a copy of the slots collected so far
+func (p *DialogStateProcessor) Slots() map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return copySlots(p.slots)
+}
+
+// State returns the name of the active state, or "" when the dialog hasn't
+// started or has completed
+func (p *DialogStateProcessor) State() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current < 0 {
+		return ""
+	}
+	return p.config.States[p.current].Name
+}
+
+// Completed reports whether the dialog has finished
+func (p *DialogStateProcessor) Completed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.completed
+}
+
+func (p *DialogStateProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if direction == frames.Upstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	switch f := frame.(type) {
+	case *frames.StartFrame:
+		if err := p.PushFrame(frame, direction); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		start := p.current < 0 && !p.completed && len(p.config.States) > 0
+		p.mu.Unlock()
+		if !start {
+			return nil
+		}
+		return p.enter(0)
+
+	case *frames.TranscriptionFrame:
+		p.mu.Lock()
+		active := p.current >= 0
+		p.mu.Unlock()
+		if !active {
+			return p.PushFrame(frame, direction)
+		}
+		// The dialog owns the turn; interims and finals stay out of the LLM path
+		if !f.IsFinal || strings.TrimSpace(f.Text) == "" {
+			return nil
+		}
+		return p.handleUtterance(ctx, f.Text)
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// handleUtterance fills the current state's slots from text and moves on
+// once they are all filled, or asks again
+func (p *DialogStateProcessor) handleUtterance(ctx context.Context, text string) error {
+	p.mu.Lock()
+	state := p.config.States[p.current]
+	p.mu.Unlock()
+
+	missing := false
+	for _, slot := range state.Slots {
+		if _, filled := p.slot(slot.Name); filled {
+			continue
+		}
+		value := p.extract(ctx, slot, text)
+		if value == "" {
+			missing = true
+			continue
+		}
+		p.mu.Lock()
+		p.slots[slot.Name] = value
+		p.mu.Unlock()
+		p.log.Info("State %q: filled %s = %q", state.Name, slot.Name, value)
+	}
+
+	if missing {
+		reprompt := state.Reprompt
+		if reprompt == "" {
+			reprompt = state.Prompt
+		}
+		return p.speak(reprompt)
+	}
+	return p.advance(state)
+}
+
+// extract runs a slot's extractor and validator, returning "" if the slot
+// wasn't found or its value was rejected
+func (p *DialogStateProcessor) extract(ctx context.Context, slot DialogSlot, text string) string {
+	if slot.Extractor == nil {
+		p.log.Warn("Slot %q has no extractor", slot.Name)
+		return ""
+	}
+
+	extractCtx, cancel := context.WithTimeout(ctx, p.config.ExtractTimeout)
+	value, err := slot.Extractor.Extract(extractCtx, slot.Name, text)
+	cancel()
+	if err != nil {
+		p.log.Warn("Extracting %s failed: %v", slot.Name, err)
+		return ""
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if slot.Validate != nil {
+		if err := slot.Validate(value); err != nil {
+			p.log.Info("Rejected %s = %q: %v", slot.Name, value, err)
+			return ""
+		}
+	}
+	return value
+}
+
+// advance moves from a completed state to the next one, or completes the dialog
+func (p *DialogStateProcessor) advance(state DialogState) error {
+	p.mu.Lock()
+	index := p.current
+	slots := copySlots(p.slots)
+	p.mu.Unlock()
+
+	next := ""
+	if state.Next != nil {
+		next = state.Next(slots)
+	} else if index+1 < len(p.config.States) {
+		next = p.config.States[index+1].Name
+	}
+	if next == "" {
+		return p.complete()
+	}
+
+	nextIndex, ok := p.states[next]
+	if !ok {
+		p.log.Error("State %q moved to unknown state %q, ending dialog", state.Name, next)
+		return p.complete()
+	}
+	p.log.Info("State %q -> %q", state.Name, next)
+	return p.enter(nextIndex)
+}
+
+// enter activates a state and speaks its prompt
+func (p *DialogStateProcessor) enter(index int) error {
+	p.mu.Lock()
+	p.current = index
+	p.mu.Unlock()
+	return p.speak(p.config.States[index].Prompt)
+}
+
+// complete ends the dialog and hands the conversation to the LLM path
+func (p *DialogStateProcessor) complete() error {
+	p.mu.Lock()
+	p.current = -1
+	p.completed = true
+	slots := copySlots(p.slots)
+	p.mu.Unlock()
+
+	p.log.Info("Dialog complete: %v", slots)
+	if p.config.OnComplete != nil {
+		p.config.OnComplete(slots)
+	}
+	if p.config.HandOffMessage == "" {
+		return nil
+	}
+	msgs := []services.LLMMessage{{Role: "system", Content: p.render(p.config.HandOffMessage)}}
+	return p.PushFrame(frames.NewLLMMessagesAppendFrame(msgs, true), frames.Downstream)
+}
+
+// speak renders text and pushes it downstream for TTS
+func (p *DialogStateProcessor) speak(text string) error {
+	text = p.render(text)
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	return p.PushFrame(frames.NewTextFrame(text), frames.Downstream)
+}
+
+// render executes text against the call's template context and the
+// collected slots
+func (p *DialogStateProcessor) render(text string) string {
+	vars := p.TemplateContext()
+	for name, value := range p.Slots() {
+		vars[name] = value
+	}
+	rendered, err := vars.Render(text)
+	if err != nil {
+		p.log.Warn("%v", err)
+	}
+	return rendered
+}
+
+func (p *DialogStateProcessor) slot(name string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	value, ok := p.slots[name]
+	return value, ok
+}
+
+func copySlots(slots map[string]string) map[string]string {
+	out := make(map[string]string, len(slots))
+	for name, value := range slots {
+		out[name] = value
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// bookingDialog collects a date, then a time between 9 and 17
+func bookingDialog(onComplete func(map[string]string)) DialogConfig {
+	return DialogConfig{
+		States: []DialogState{
+			{
+				Name:   "date",
+				Prompt: "What day would you like?",
+				Slots: []DialogSlot{{
+					Name:      "date",
+					Extractor: RegexSlotExtractor(`(?i)\b(monday|tuesday|wednesday|thursday|friday)\b`),
+				}},
+			},
+			{
+				Name:     "time",
+				Prompt:   "What time on {{.date}}?",
+				Reprompt: "We're open 9 to 5. What time?",
+				Slots: []DialogSlot{{
+					Name:      "time",
+					Extractor: RegexSlotExtractor(`\b(\d{1,2})\b`),
+					Validate: func(value string) error {
+						if hour, _ := strconv.Atoi(value); hour < 9 || hour > 17 {
+							return errors.New("outside opening hours")
+						}
+						return nil
+					},
+				}},
+			},
+		},
+		HandOffMessage: "Booked {{.date}} at {{.time}}.",
+		OnComplete:     onComplete,
+	}
+}
+
+// say feeds a final transcription to p
+func say(t *testing.T, p *DialogStateProcessor, text string) {
+	t.Helper()
+	if err := p.HandleFrame(context.Background(), frames.NewTranscriptionFrame(text, true), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+}
+
+// prompts returns the TextFrames pushed downstream
+func prompts(c *captureProc) []string {
+	var out []string
+	for _, f := range c.get() {
+		if textFrame, ok := f.(*frames.TextFrame); ok {
+			out = append(out, textFrame.Text)
+		}
+	}
+	return out
+}
+
+func TestDialogStateProcessor_TwoSlotFlow(t *testing.T) {
+	var completed map[string]string
+	p := NewDialogStateProcessor(bookingDialog(func(slots map[string]string) { completed = slots }))
+	downstream := &captureProc{}
+	p.Link(downstream)
+
+	if err := p.HandleFrame(context.Background(), frames.NewStartFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) failed: %v", err)
+	}
+	if p.State() != "date" {
+		t.Fatalf("expected to start in state date, got %q", p.State())
+	}
+
+	say(t, p, "um not sure")
+	if p.State() != "date" {
+		t.Fatalf("expected to stay in date without a date, got %q", p.State())
+	}
+	say(t, p, "Thursday please")
+	if p.State() != "time" {
+		t.Fatalf("expected date -> time, got %q", p.State())
+	}
+	say(t, p, "how about 7")
+	if p.State() != "time" {
+		t.Fatalf("expected an invalid time to be asked again, got %q", p.State())
+	}
+	say(t, p, "make it 10 then")
+
+	if !p.Completed() || p.State() != "" {
+		t.Fatalf("expected the dialog to complete, state %q", p.State())
+	}
+	want := map[string]string{"date": "Thursday", "time": "10"}
+	for name, value := range want {
+		if got := p.Slots()[name]; got != value {
+			t.Errorf("slot %s = %q, want %q", name, got, value)
+		}
+		if got := completed[name]; got != value {
+			t.Errorf("OnComplete slot %s = %q, want %q", name, got, value)
+		}
+	}
+
+	wantPrompts := []string{
+		"What day would you like?",
+		"What day would you like?",
+		"What time on Thursday?",
+		"We're open 9 to 5. What time?",
+	}
+	got := prompts(downstream)
+	if len(got) != len(wantPrompts) {
+		t.Fatalf("expected prompts %q, got %q", wantPrompts, got)
+	}
+	for i := range wantPrompts {
+		if got[i] != wantPrompts[i] {
+			t.Errorf("prompt %d = %q, want %q", i, got[i], wantPrompts[i])
+		}
+	}
+
+	// Transcriptions stayed out of the LLM path; the hand-off runs the LLM
+	var handOff *frames.LLMMessagesAppendFrame
+	for _, f := range downstream.get() {
+		switch f := f.(type) {
+		case *frames.TranscriptionFrame:
+			t.Errorf("expected the dialog to consume %q", f.Text)
+		case *frames.LLMMessagesAppendFrame:
+			handOff = f
+		}
+	}
+	if handOff == nil || !handOff.RunLLM {
+		t.Fatal("expected an LLMMessagesAppendFrame running the LLM on completion")
+	}
+	msgs := handOff.Messages.([]services.LLMMessage)
+	if msgs[0].Content != "Booked Thursday at 10." {
+		t.Errorf("hand-off message = %q", msgs[0].Content)
+	}
+}
+
+func TestDialogStateProcessor_PassesThroughAfterCompletion(t *testing.T) {
+	p := NewDialogStateProcessor(DialogConfig{States: []DialogState{{
+		Name:   "name",
+		Prompt: "What's your name?",
+		Slots:  []DialogSlot{{Name: "name", Extractor: RegexSlotExtractor(`(?i)i'm (\w+)`)}},
+	}}})
+	downstream := &captureProc{}
+	p.Link(downstream)
+
+	p.HandleFrame(context.Background(), frames.NewStartFrame(), frames.Downstream)
+	p.HandleFrame(context.Background(), frames.NewTranscriptionFrame("I'm", false), frames.Downstream)
+	say(t, p, "I'm Sam")
+	say(t, p, "what are your hours?")
+
+	var transcripts []string
+	for _, f := range downstream.get() {
+		if tf, ok := f.(*frames.TranscriptionFrame); ok {
+			transcripts = append(transcripts, tf.Text)
+		}
+	}
+	if len(transcripts) != 1 || transcripts[0] != "what are your hours?" {
+		t.Errorf("expected only the post-dialog transcription downstream, got %q", transcripts)
+	}
+	if p.Slots()["name"] != "Sam" {
+		t.Errorf("slot name = %q, want Sam", p.Slots()["name"])
+	}
+}
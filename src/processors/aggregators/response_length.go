@@ -0,0 +1,219 @@
+package aggregators
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// ResponseLengthConfig configures a ResponseLengthProcessor. Set MaxWords,
+// MaxChars or both; a response must fit every budget that is set.
+type ResponseLengthConfig struct {
+	MaxWords int // Words allowed per response (0 = no word budget)
+	MaxChars int // Characters allowed per response (0 = no character budget)
+
+	// BrevityReminder, when set, is appended to the context as a system
+	// message after a response is cut short, asking the LLM to keep later
+	// replies within budget (e.g. "Answer in one or two short sentences.")
+	BrevityReminder string
+}
+
+// ResponseLengthProcessor keeps spoken responses short by cutting an LLM
+// response off at the last sentence boundary within budget. Place it between
+// the LLM and the TTS service.
+//
+// The first sentence streams through token by token so first-token latency
+// is unchanged. Later sentences are held until complete and released only if
+// they fit. When one doesn't, the processor ends the response early with an
+// LLMFullResponseEndFrame, asks the LLM to stop generating with an upstream
+// LLMCancelGenerationFrame, and drops the rest of the response, including the
+// LLM's own end frame. A first sentence that alone runs past the budget is
+// cut at the token that exceeds it.
+type ResponseLengthProcessor struct {
+	*processors.BaseProcessor
+	config ResponseLengthConfig
+	log    *logger.Logger
+
+	streamed  strings.Builder // First sentence text already pushed
+	held      strings.Builder // Text after the first sentence awaiting a boundary
+	firstDone bool            // First sentence complete; later text is held
+	words     int             // Words pushed in the current response
+	chars     int             // Characters pushed in the current response
+	truncated bool            // Budget hit; dropping the rest of the response
+}
+
+// NewResponseLengthProcessor creates a new response length processor
+func NewResponseLengthProcessor(config ResponseLengthConfig) *ResponseLengthProcessor {
+	p := &ResponseLengthProcessor{
+		config: config,
+		log:    logger.WithPrefix("ResponseLength"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("ResponseLengthProcessor", p)
+	return p
+}
+
+func (p *ResponseLengthProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if direction == frames.Upstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	switch f := frame.(type) {
+	case *frames.LLMFullResponseStartFrame:
+		p.reset()
+		return p.PushFrame(frame, direction)
+
+	case *frames.LLMTextFrame:
+		if f.SkipTTS {
+			return p.PushFrame(frame, direction)
+		}
+		return p.processText(f.Text)
+
+	case *frames.TextFrame:
+		if f.SkipTTS || f.SkipContext {
+			return p.PushFrame(frame, direction)
+		}
+		return p.processText(f.Text)
+
+	case *frames.LLMFullResponseEndFrame:
+		if p.truncated {
+			// Already ended this response early
+			p.reset()
+			return nil
+		}
+		if err := p.releaseRemainder(); err != nil {
+			return err
+		}
+		p.reset()
+		return p.PushFrame(frame, direction)
+
+	case *frames.InterruptionFrame:
+		p.reset()
+		return p.PushFrame(frame, direction)
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// processText streams the first sentence and holds later text until it
+// reaches a sentence boundary
+func (p *ResponseLengthProcessor) processText(text string) error {
+	if p.truncated || text == "" {
+		return nil
+	}
+	if p.firstDone {
+		p.held.WriteString(text)
+		return p.releaseSentences()
+	}
+
+	if p.overBudget(0, 0) {
+		return p.truncate()
+	}
+
+	// Push only the part of this token that belongs to the first sentence
+	seen := p.streamed.Len()
+	p.streamed.WriteString(text)
+	current := p.streamed.String()
+	sentences, _ := extractSentences(current)
+	if len(sentences) == 0 {
+		return p.push(text)
+	}
+
+	firstEnd := len(sentences[0])
+	p.firstDone = true
+	p.held.WriteString(current[firstEnd:])
+	if firstEnd > seen {
+		if err := p.push(current[seen:firstEnd]); err != nil {
+			return err
+		}
+	}
+	return p.releaseSentences()
+}
+
+// releaseSentences pushes each complete held sentence that fits the budget
+func (p *ResponseLengthProcessor) releaseSentences() error {
+	sentences, remainder := extractSentences(p.held.String())
+	p.held.Reset()
+	p.held.WriteString(remainder)
+
+	for _, sentence := range sentences {
+		if p.overBudget(countWords(sentence), countChars(sentence)) {
+			return p.truncate()
+		}
+		if err := p.push(sentence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseRemainder pushes an unterminated final sentence if it fits; if it
+// doesn't, the response simply ends at the previous boundary
+func (p *ResponseLengthProcessor) releaseRemainder() error {
+	remainder := p.held.String()
+	p.held.Reset()
+	if strings.TrimSpace(remainder) == "" {
+		return nil
+	}
+	if p.overBudget(countWords(remainder), countChars(remainder)) {
+		p.log.Info("Dropped trailing fragment over budget (%d words)", countWords(remainder))
+		return nil
+	}
+	return p.push(remainder)
+}
+
+func (p *ResponseLengthProcessor) push(text string) error {
+	p.words += countWords(text)
+	p.chars += countChars(text)
+	return p.PushFrame(frames.NewLLMTextFrame(text), frames.Downstream)
+}
+
+// overBudget reports whether pushing words and chars more would exceed a
+// budget. With nothing more to push it reports whether a budget is used up.
+func (p *ResponseLengthProcessor) overBudget(words, chars int) bool {
+	if words == 0 && chars == 0 {
+		return (p.config.MaxWords > 0 && p.words >= p.config.MaxWords) ||
+			(p.config.MaxChars > 0 && p.chars >= p.config.MaxChars)
+	}
+	return (p.config.MaxWords > 0 && p.words+words > p.config.MaxWords) ||
+		(p.config.MaxChars > 0 && p.chars+chars > p.config.MaxChars)
+}
+
+// truncate ends the response early and stops the LLM generating the rest
+func (p *ResponseLengthProcessor) truncate() error {
+	p.truncated = true
+	p.held.Reset()
+	p.log.Info("Response over budget, cutting off after %d words / %d chars", p.words, p.chars)
+
+	if err := p.PushFrame(frames.NewLLMCancelGenerationFrame(), frames.Upstream); err != nil {
+		p.log.Warn("Failed to request generation cancel: %v", err)
+	}
+	if p.config.BrevityReminder != "" {
+		msgs := []services.LLMMessage{{Role: "system", Content: p.config.BrevityReminder}}
+		if err := p.PushFrame(frames.NewLLMMessagesAppendFrame(msgs, false), frames.Upstream); err != nil {
+			p.log.Warn("Failed to append brevity reminder: %v", err)
+		}
+	}
+	return p.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+}
+
+func (p *ResponseLengthProcessor) reset() {
+	p.streamed.Reset()
+	p.held.Reset()
+	p.firstDone = false
+	p.words = 0
+	p.chars = 0
+	p.truncated = false
+}
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func countChars(text string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(text))
+}
@@ -0,0 +1,172 @@
+package aggregators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func newTestResponseLength(config ResponseLengthConfig) (*ResponseLengthProcessor, *captureProc, *captureProc) {
+	p := NewResponseLengthProcessor(config)
+	upstream, downstream := &captureProc{}, &captureProc{}
+	p.SetPrev(upstream)
+	p.Link(downstream)
+	return p, upstream, downstream
+}
+
+// streamResponse sends tokens as one LLM response
+func streamResponse(t *testing.T, p *ResponseLengthProcessor, tokens []string) {
+	t.Helper()
+	ctx := context.Background()
+	p.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	for _, token := range tokens {
+		if err := p.HandleFrame(ctx, frames.NewLLMTextFrame(token), frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+	}
+	p.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+}
+
+// responseText returns the text pushed downstream and the number of
+// LLMFullResponseEndFrames
+func responseText(c *captureProc) (string, int) {
+	var text strings.Builder
+	ends := 0
+	for _, f := range c.get() {
+		switch f := f.(type) {
+		case *frames.LLMTextFrame:
+			text.WriteString(f.Text)
+		case *frames.LLMFullResponseEndFrame:
+			ends++
+		}
+	}
+	return text.String(), ends
+}
+
+func countFrames(c *captureProc, name string) int {
+	n := 0
+	for _, f := range c.get() {
+		if f.Name() == name {
+			n++
+		}
+	}
+	return n
+}
+
+func TestResponseLength_TruncatesAtSentenceBoundary(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ResponseLengthConfig
+		want   string
+	}{
+		{name: "word budget", config: ResponseLengthConfig{MaxWords: 12}, want: "Sure, I can help. The store opens at nine."},
+		{name: "char budget", config: ResponseLengthConfig{MaxChars: 30}, want: "Sure, I can help."},
+		{name: "both budgets", config: ResponseLengthConfig{MaxWords: 100, MaxChars: 50}, want: "Sure, I can help. The store opens at nine."},
+	}
+
+	tokens := []string{"Sure,", " I can", " help.", " The store", " opens at", " nine.", " It closes", " at six on", " weekdays.", " On weekends", " hours vary"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, upstream, downstream := newTestResponseLength(tt.config)
+			streamResponse(t, p, tokens)
+
+			text, ends := responseText(downstream)
+			if text != tt.want {
+				t.Errorf("Spoken text = %q, want %q", text, tt.want)
+			}
+			if ends != 1 {
+				t.Errorf("Expected exactly 1 LLMFullResponseEndFrame, got %d", ends)
+			}
+			if tt.config.MaxWords > 0 && countWords(text) > tt.config.MaxWords {
+				t.Errorf("Spoken %d words, over the %d word budget", countWords(text), tt.config.MaxWords)
+			}
+			if tt.config.MaxChars > 0 && len(text) > tt.config.MaxChars {
+				t.Errorf("Spoken %d chars, over the %d char budget", len(text), tt.config.MaxChars)
+			}
+			if got := countFrames(upstream, "LLMCancelGenerationFrame"); got != 1 {
+				t.Errorf("Expected 1 LLMCancelGenerationFrame upstream, got %d", got)
+			}
+		})
+	}
+}
+
+func TestResponseLength_EndsEarlyBeforeLLMFinishes(t *testing.T) {
+	p, _, downstream := newTestResponseLength(ResponseLengthConfig{MaxWords: 5})
+	ctx := context.Background()
+
+	p.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	for _, token := range []string{"Yes.", " That works", " for me.", " Anything", " else?"} {
+		p.HandleFrame(ctx, frames.NewLLMTextFrame(token), frames.Downstream)
+	}
+
+	// The response is ended as soon as the budget is hit, not when the LLM stops
+	if _, ends := responseText(downstream); ends != 1 {
+		t.Fatalf("Expected the response ended early, got %d end frames", ends)
+	}
+	p.HandleFrame(ctx, frames.NewLLMTextFrame(" More text."), frames.Downstream)
+	p.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	text, ends := responseText(downstream)
+	if text != "Yes. That works for me." {
+		t.Errorf("Spoken text = %q", text)
+	}
+	if ends != 1 {
+		t.Errorf("Expected the LLM's own end frame dropped, got %d end frames", ends)
+	}
+
+	// The next response starts with a fresh budget
+	streamResponse(t, p, []string{"Okay."})
+	if text, ends := responseText(downstream); !strings.HasSuffix(text, "Okay.") || ends != 2 {
+		t.Errorf("Expected the next response spoken in full, got %q with %d ends", text, ends)
+	}
+}
+
+func TestResponseLength_StreamsFirstSentenceImmediately(t *testing.T) {
+	p, _, downstream := newTestResponseLength(ResponseLengthConfig{MaxWords: 50})
+	ctx := context.Background()
+
+	p.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	p.HandleFrame(ctx, frames.NewLLMTextFrame("Hello"), frames.Downstream)
+	if text, _ := responseText(downstream); text != "Hello" {
+		t.Fatalf("Expected the first token pushed immediately, got %q", text)
+	}
+
+	// A token spanning the boundary releases only the first sentence's part
+	p.HandleFrame(ctx, frames.NewLLMTextFrame(" there. How"), frames.Downstream)
+	if text, _ := responseText(downstream); text != "Hello there." {
+		t.Fatalf("Expected the second sentence held, got %q", text)
+	}
+	p.HandleFrame(ctx, frames.NewLLMTextFrame(" are you?"), frames.Downstream)
+	if text, _ := responseText(downstream); text != "Hello there. How are you?" {
+		t.Errorf("Expected the complete second sentence released, got %q", text)
+	}
+}
+
+func TestResponseLength_ShortResponsesUntouched(t *testing.T) {
+	p, upstream, downstream := newTestResponseLength(ResponseLengthConfig{MaxWords: 20})
+	streamResponse(t, p, []string{"It is", " sunny.", " Enjoy your", " day"})
+
+	text, ends := responseText(downstream)
+	if text != "It is sunny. Enjoy your day" || ends != 1 {
+		t.Errorf("Expected the response unchanged, got %q with %d ends", text, ends)
+	}
+	if len(upstream.get()) != 0 {
+		t.Errorf("Expected nothing pushed upstream, got %d frames", len(upstream.get()))
+	}
+}
+
+func TestResponseLength_BrevityReminder(t *testing.T) {
+	p, upstream, _ := newTestResponseLength(ResponseLengthConfig{MaxWords: 3, BrevityReminder: "Keep it short."})
+	streamResponse(t, p, []string{"One two.", " Three four five."})
+
+	if got := countFrames(upstream, "LLMMessagesAppendFrame"); got != 1 {
+		t.Fatalf("Expected a brevity reminder appended, got %d", got)
+	}
+	for _, f := range upstream.get() {
+		if appendFrame, ok := f.(*frames.LLMMessagesAppendFrame); ok && appendFrame.RunLLM {
+			t.Error("Expected the reminder not to re-run the LLM")
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package aggregators
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+const (
+	defaultSemanticHoldTimeout       = 1500 * time.Millisecond
+	defaultSemanticClassifierTimeout = 500 * time.Millisecond
+)
+
+// EndpointClassifier decides whether a user utterance is a complete turn.
+// Implementations may be heuristics, a local model or a small LLM call; they
+// should respect ctx, which is bounded by the processor's classifier timeout.
+type EndpointClassifier interface {
+	IsComplete(ctx context.Context, text string) (bool, error)
+}
+
+// EndpointClassifierFunc adapts a function to EndpointClassifier
+type EndpointClassifierFunc func(ctx context.Context, text string) (bool, error)
+
+// IsComplete calls f(ctx, text)
+func (f EndpointClassifierFunc) IsComplete(ctx context.Context, text string) (bool, error) {
+	return f(ctx, text)
+}
+
+// defaultTrailingWords are words that rarely end a complete utterance:
+// conjunctions, fillers, articles, prepositions and possessives.
+var defaultTrailingWords = []string{
+	"and", "or", "but", "so", "because", "if", "then", "like",
+	"um", "uh", "er", "erm", "hmm",
+	"the", "a", "an", "my", "your", "our", "their", "his", "her",
+	"to", "of", "for", "with", "at", "in", "on", "from",
+	"is", "was", "are", "i'm",
+}
+
+var trailingWordPattern = regexp.MustCompile(`[\p{L}']+$`)
+
+// HeuristicEndpointClassifier treats an utterance as incomplete when it ends
+// in a trailing word such as "and"/"um"/"my", or with a comma or ellipsis
+type HeuristicEndpointClassifier struct {
+	trailing map[string]bool
+}
+
+// NewHeuristicEndpointClassifier creates a heuristic classifier. If no words
+// are given the built-in list of conjunctions, fillers and articles is used.
+func NewHeuristicEndpointClassifier(trailingWords ...string) *HeuristicEndpointClassifier {
+	if len(trailingWords) == 0 {
+		trailingWords = defaultTrailingWords
+	}
+	trailing := make(map[string]bool, len(trailingWords))
+	for _, word := range trailingWords {
+		trailing[strings.ToLower(word)] = true
+	}
+	return &HeuristicEndpointClassifier{trailing: trailing}
+}
+
+// IsComplete implements EndpointClassifier
+func (c *HeuristicEndpointClassifier) IsComplete(_ context.Context, text string) (bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return true, nil
+	}
+	if strings.HasSuffix(text, ",") || strings.HasSuffix(text, "...") || strings.HasSuffix(text, "…") || strings.HasSuffix(text, "-") {
+		return false, nil
+	}
+
+	// Terminal punctuation after a trailing word ("My number is...") is
+	// handled above; strip the rest so "and." is still caught
+	word := trailingWordPattern.FindString(strings.TrimRight(text, ".!?\"' "))
+	return !c.trailing[strings.ToLower(word)], nil
+}
+
+// SemanticEndpointConfig configures the SemanticEndpointProcessor
+type SemanticEndpointConfig struct {
+	// Classifier decides whether the utterance is complete.
+	// Default: HeuristicEndpointClassifier with the built-in word list.
+	Classifier EndpointClassifier
+
+	// HoldTimeout is how long an incomplete utterance is held waiting for the
+	// user to continue before it is released anyway. Default: 1.5s.
+	HoldTimeout time.Duration
+
+	// ClassifierTimeout bounds each classifier call; on timeout or error the
+	// utterance is treated as complete. Default: 500ms.
+	ClassifierTimeout time.Duration
+}
+
+// SemanticEndpointProcessor holds back end-of-turn when the user's utterance
+// sounds unfinished ("My number is... 555"), extending the aggregation window
+// instead of sending a half-thought to the LLM.
+//
+// Final transcriptions are buffered. When a turn end is proposed (a final
+// arrives while the user is silent, or the user stops speaking with text
+// buffered) the classifier is consulted: complete utterances are released
+// downstream as a single final TranscriptionFrame; incomplete ones are held
+// until the user continues or HoldTimeout expires.
+//
+// Insert between the STT service and the user aggregator:
+//
+//	pipeline.NewPipeline([]processors.FrameProcessor{
+//	    transport.Input(),
+//	    stt,
+//	    aggregators.NewSemanticEndpointProcessor(aggregators.SemanticEndpointConfig{}),
+//	    userAgg,
+//	    ...,
+//	})
+type SemanticEndpointProcessor struct {
+	*processors.BaseProcessor
+
+	cfg SemanticEndpointConfig
+
+	// Turn state — protected by mu; the hold timer releases from its own goroutine
+	mu           sync.Mutex
+	held         []*frames.TranscriptionFrame
+	userSpeaking bool
+	timer        *time.Timer
+	timerGen     uint64
+
+	log *logger.Logger
+}
+
+// NewSemanticEndpointProcessor creates a new SemanticEndpointProcessor
+func NewSemanticEndpointProcessor(cfg SemanticEndpointConfig) *SemanticEndpointProcessor {
+	if cfg.Classifier == nil {
+		cfg.Classifier = NewHeuristicEndpointClassifier()
+	}
+	if cfg.HoldTimeout <= 0 {
+		cfg.HoldTimeout = defaultSemanticHoldTimeout
+	}
+	if cfg.ClassifierTimeout <= 0 {
+		cfg.ClassifierTimeout = defaultSemanticClassifierTimeout
+	}
+
+	p := &SemanticEndpointProcessor{
+		cfg: cfg,
+		log: logger.WithPrefix("SemanticEndpoint"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("SemanticEndpointProcessor", p)
+	return p
+}
+
+// HandleFrame implements processors.ProcessHandler.
+func (p *SemanticEndpointProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if direction != frames.Downstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	switch f := frame.(type) {
+	case *frames.UserStartedSpeakingFrame:
+		// The user is continuing: keep holding, without a deadline
+		p.mu.Lock()
+		p.userSpeaking = true
+		p.mu.Unlock()
+		p.cancelTimer()
+		return p.PushFrame(frame, direction)
+
+	case *frames.UserStoppedSpeakingFrame:
+		p.mu.Lock()
+		p.userSpeaking = false
+		pending := len(p.held) > 0
+		p.mu.Unlock()
+		if err := p.PushFrame(frame, direction); err != nil {
+			return err
+		}
+		if pending {
+			return p.evaluate(ctx)
+		}
+		return nil
+
+	case *frames.TranscriptionFrame:
+		if !f.IsFinal || f.Text == "" {
+			return p.PushFrame(frame, direction)
+		}
+		p.mu.Lock()
+		p.held = append(p.held, f)
+		speaking := p.userSpeaking
+		p.mu.Unlock()
+		if !speaking {
+			return p.evaluate(ctx)
+		}
+		return nil
+
+	case *frames.EndFrame, *frames.CancelFrame:
+		// Don't lose the last words of the call
+		p.cancelTimer()
+		if err := p.release(); err != nil {
+			return err
+		}
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// evaluate runs the classifier on the buffered utterance and either releases
+// it or (re)starts the hold timer
+func (p *SemanticEndpointProcessor) evaluate(ctx context.Context) error {
+	text := p.heldText()
+	if text == "" {
+		return nil
+	}
+
+	classifyCtx, cancel := context.WithTimeout(ctx, p.cfg.ClassifierTimeout)
+	complete, err := p.cfg.Classifier.IsComplete(classifyCtx, text)
+	cancel()
+	if err != nil {
+		p.log.Warn("Classifier failed, releasing turn: %v", err)
+		complete = true
+	}
+
+	if complete {
+		p.cancelTimer()
+		return p.release()
+	}
+
+	p.log.Debug("Utterance %q sounds incomplete, holding up to %v", text, p.cfg.HoldTimeout)
+	p.startTimer()
+	return nil
+}
+
+// release pushes the buffered finals downstream as one final transcription
+func (p *SemanticEndpointProcessor) release() error {
+	p.mu.Lock()
+	held := p.held
+	p.held = nil
+	p.mu.Unlock()
+
+	if len(held) == 0 {
+		return nil
+	}
+	if len(held) == 1 {
+		return p.PushFrame(held[0], frames.Downstream)
+	}
+
+	last := held[len(held)-1]
+	merged := frames.NewTranscriptionFrame(joinTranscripts(held), true)
+	merged.Language = last.Language
+	merged.Timestamp = last.Timestamp
+	for key, value := range last.Metadata() {
+		merged.SetMetadata(key, value)
+	}
+	return p.PushFrame(merged, frames.Downstream)
+}
+
+func (p *SemanticEndpointProcessor) heldText() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return joinTranscripts(p.held)
+}
+
+// startTimer (re)starts the hold timer; when it fires the utterance is released
+func (p *SemanticEndpointProcessor) startTimer() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timerGen++
+	gen := p.timerGen
+	p.timer = time.AfterFunc(p.cfg.HoldTimeout, func() {
+		p.mu.Lock()
+		if gen != p.timerGen {
+			p.mu.Unlock()
+			return
+		}
+		p.timer = nil
+		p.mu.Unlock()
+
+		p.log.Debug("Hold timeout (%v), releasing incomplete turn", p.cfg.HoldTimeout)
+		if err := p.release(); err != nil {
+			p.log.Error("Failed to release held turn: %v", err)
+		}
+	})
+}
+
+// cancelTimer stops any running hold timer
+func (p *SemanticEndpointProcessor) cancelTimer() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timerGen++
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+func joinTranscripts(held []*frames.TranscriptionFrame) string {
+	parts := make([]string, 0, len(held))
+	for _, f := range held {
+		if text := strings.TrimSpace(f.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
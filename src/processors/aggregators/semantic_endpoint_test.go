@@ -0,0 +1,184 @@
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func setupSemanticEndpoint(cfg SemanticEndpointConfig) (*SemanticEndpointProcessor, *captureProc) {
+	p := NewSemanticEndpointProcessor(cfg)
+	downstream := &captureProc{}
+	p.Link(downstream)
+	return p, downstream
+}
+
+// transcripts returns the text of final transcriptions released downstream
+func transcripts(c *captureProc) []string {
+	var texts []string
+	for _, f := range c.get() {
+		if tf, ok := f.(*frames.TranscriptionFrame); ok && tf.IsFinal {
+			texts = append(texts, tf.Text)
+		}
+	}
+	return texts
+}
+
+func sendFinal(t *testing.T, p *SemanticEndpointProcessor, text string) {
+	t.Helper()
+	if err := p.HandleFrame(context.Background(), frames.NewTranscriptionFrame(text, true), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+}
+
+func TestHeuristicEndpointClassifier(t *testing.T) {
+	c := NewHeuristicEndpointClassifier()
+	cases := []struct {
+		text     string
+		complete bool
+	}{
+		{"What time is it?", true},
+		{"Yes.", true},
+		{"I'd like to book a table for two", true},
+		{"My number is...", false},
+		{"I went to the store and", false},
+		{"So um", false},
+		{"I want to order the", false},
+		{"Let me check, my account number is", false},
+		{"Send it to my", false},
+		{"Well, first,", false},
+		{"and.", false},
+	}
+	for _, tc := range cases {
+		got, err := c.IsComplete(context.Background(), tc.text)
+		if err != nil {
+			t.Fatalf("IsComplete(%q) failed: %v", tc.text, err)
+		}
+		if got != tc.complete {
+			t.Errorf("IsComplete(%q) = %v, want %v", tc.text, got, tc.complete)
+		}
+	}
+}
+
+func TestSemanticEndpoint_ReleasesCompleteTurn(t *testing.T) {
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{})
+
+	sendFinal(t, p, "What are your opening hours?")
+
+	if got := transcripts(downstream); len(got) != 1 || got[0] != "What are your opening hours?" {
+		t.Fatalf("Expected complete turn to be released immediately, got %v", got)
+	}
+}
+
+func TestSemanticEndpoint_HoldsIncompleteTurnUntilContinued(t *testing.T) {
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{HoldTimeout: time.Minute})
+	ctx := context.Background()
+
+	sendFinal(t, p, "My number is")
+	if got := transcripts(downstream); len(got) != 0 {
+		t.Fatalf("Expected trailing-incomplete turn to be held, got %v", got)
+	}
+
+	// User continues speaking; the rest of the utterance arrives
+	if err := p.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+	sendFinal(t, p, "555")
+	sendFinal(t, p, "1234.")
+	if got := transcripts(downstream); len(got) != 0 {
+		t.Fatalf("Expected nothing released while the user is speaking, got %v", got)
+	}
+	if err := p.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	got := transcripts(downstream)
+	if len(got) != 1 || got[0] != "My number is 555 1234." {
+		t.Fatalf("Expected one merged turn, got %v", got)
+	}
+}
+
+func TestSemanticEndpoint_ReleasesHeldTurnAfterTimeout(t *testing.T) {
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{HoldTimeout: 50 * time.Millisecond})
+
+	sendFinal(t, p, "I was thinking that maybe um")
+	if got := transcripts(downstream); len(got) != 0 {
+		t.Fatalf("Expected turn to be held, got %v", got)
+	}
+
+	downstream.waitFor(t, "TranscriptionFrame", time.Second)
+	if got := transcripts(downstream); len(got) != 1 || got[0] != "I was thinking that maybe um" {
+		t.Fatalf("Expected held turn released after timeout, got %v", got)
+	}
+}
+
+func TestSemanticEndpoint_UserSpeakingCancelsHoldTimeout(t *testing.T) {
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{HoldTimeout: 30 * time.Millisecond})
+
+	sendFinal(t, p, "Book it for")
+	if err := p.HandleFrame(context.Background(), frames.NewUserStartedSpeakingFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if got := transcripts(downstream); len(got) != 0 {
+		t.Fatalf("Expected turn held while the user keeps speaking, got %v", got)
+	}
+}
+
+func TestSemanticEndpoint_PluggableClassifier(t *testing.T) {
+	calls := 0
+	classifier := EndpointClassifierFunc(func(ctx context.Context, text string) (bool, error) {
+		calls++
+		// e.g. a slot-filling check: wait for the full 10-digit number
+		digits := 0
+		for _, r := range text {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		return digits >= 10, nil
+	})
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{Classifier: classifier, HoldTimeout: time.Minute})
+
+	sendFinal(t, p, "It's 555 123.")
+	if got := transcripts(downstream); len(got) != 0 {
+		t.Fatalf("Expected custom classifier to hold the turn, got %v", got)
+	}
+	sendFinal(t, p, "4567.")
+	if got := transcripts(downstream); len(got) != 1 || got[0] != "It's 555 123. 4567." {
+		t.Fatalf("Expected turn released once complete, got %v", got)
+	}
+	if calls != 2 {
+		t.Errorf("Expected classifier called per proposed end of turn, got %d", calls)
+	}
+}
+
+func TestSemanticEndpoint_ClassifierErrorReleasesTurn(t *testing.T) {
+	classifier := EndpointClassifierFunc(func(ctx context.Context, text string) (bool, error) {
+		return false, errors.New("model unavailable")
+	})
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{Classifier: classifier})
+
+	sendFinal(t, p, "Hello and")
+	if got := transcripts(downstream); len(got) != 1 {
+		t.Fatalf("Expected classifier error to fail open, got %v", got)
+	}
+}
+
+func TestSemanticEndpoint_EndFrameFlushesHeldTurn(t *testing.T) {
+	p, downstream := setupSemanticEndpoint(SemanticEndpointConfig{HoldTimeout: time.Minute})
+
+	sendFinal(t, p, "Goodbye and")
+	if err := p.HandleFrame(context.Background(), frames.NewEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	got := downstream.get()
+	if len(got) != 2 || got[0].Name() != "TranscriptionFrame" || got[1].Name() != "EndFrame" {
+		t.Fatalf("Expected held transcription before EndFrame, got %v", got)
+	}
+}
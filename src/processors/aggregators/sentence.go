@@ -8,6 +8,7 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
 // SentenceAggregator buffers incoming text frames and emits complete sentences.
@@ -23,8 +24,9 @@ import (
 //	LLMTextFrame(" world.") -> TextFrame("Hello, world.")
 type SentenceAggregator struct {
 	*processors.BaseProcessor
-	buffer strings.Builder
-	mode   TextAggregationMode
+	buffer   strings.Builder
+	mode     TextAggregationMode
+	language string
 }
 
 // NewSentenceAggregator creates a new sentence aggregator processor
@@ -42,6 +44,13 @@ func NewSentenceAggregator(mode ...TextAggregationMode) *SentenceAggregator {
 	return sa
 }
 
+// SetLanguage sets the language of the text (e.g. "zh", "ja-JP") so
+// sentences are split by its punctuation rules; see services.SentenceEnd.
+// Full-width punctuation ends a sentence in any language.
+func (s *SentenceAggregator) SetLanguage(language string) {
+	s.language = language
+}
+
 func (s *SentenceAggregator) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	// CRITICAL: Only process DOWNSTREAM frames (from LLM → TTS)
 	// Upstream frames (like word timestamps from TTS) must pass through unchanged
@@ -64,14 +73,17 @@ func (s *SentenceAggregator) HandleFrame(ctx context.Context, frame frames.Frame
 	// Handle TextFrame - only downstream (e.g., from user aggregator or other sources)
 	// Note: Upstream TextFrames (like TTS word timestamps) are passed through above
 	if textFrame, ok := frame.(*frames.TextFrame); ok {
-		if textFrame.SkipTTS {
+		// SkipContext frames (e.g. function-call progress) are already
+		// complete messages; keep them whole so the flag survives
+		if textFrame.SkipTTS || textFrame.SkipContext {
 			return s.PushFrame(frame, direction)
 		}
 		return s.processText(textFrame.Text)
 	}
 
-	// Handle LLMFullResponseEndFrame - flush any remaining buffer
-	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
+	// Handle LLMFullResponseEndFrame and TTSFlushFrame - flush any remaining buffer
+	switch frame.(type) {
+	case *frames.LLMFullResponseEndFrame, *frames.TTSFlushFrame:
 		if err := s.flushBuffer(); err != nil {
 			return err
 		}
@@ -116,7 +128,7 @@ func (s *SentenceAggregator) processText(text string) error {
 	s.buffer.WriteString(text)
 
 	// Extract complete sentences
-	sentences, remainder := extractSentences(s.buffer.String())
+	sentences, remainder := extractSentencesFor(s.buffer.String(), s.language)
 	s.buffer.Reset()
 	s.buffer.WriteString(remainder)
 
@@ -183,6 +195,12 @@ var abbreviations = map[string]bool{
 // Handles common sentence-ending punctuation while avoiding false positives
 // from abbreviations (Dr., Mr., etc.) and numbers ($29.95).
 func extractSentences(text string) ([]string, string) {
+	return extractSentencesFor(text, "")
+}
+
+// extractSentencesFor is extractSentences for text in language, which
+// decides whether punctuation needs a following space to end a sentence
+func extractSentencesFor(text, language string) ([]string, string) {
 	var sentences []string
 	var currentSentence strings.Builder
 
@@ -224,13 +242,11 @@ func extractSentences(text string) ([]string, string) {
 			}
 
 			if !isAbbreviation {
-				// Check if at end of text or followed by space/newline
-				if i == len(runes)-1 {
-					// End of text - this is a complete sentence
-					sentences = append(sentences, currentSentence.String())
-					currentSentence.Reset()
-				} else if i+1 < len(runes) && (unicode.IsSpace(runes[i+1]) || runes[i+1] == '\n') {
-					// Followed by space - likely end of sentence
+				// At the end of text, before a space or, for full-width
+				// punctuation, anywhere
+				if end, ok := services.SentenceEnd(runes, i, language); ok {
+					currentSentence.WriteString(string(runes[i+1 : end+1]))
+					i = end
 					sentences = append(sentences, currentSentence.String())
 					currentSentence.Reset()
 				}
@@ -2,6 +2,8 @@ package aggregators
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -162,3 +164,42 @@ func TestTextAggregationMode_SentenceMultipleSentences(t *testing.T) {
 		t.Errorf("Expected buffer to contain 'Third' (with or without leading space), got %q", buffered)
 	}
 }
+
+// TestSentenceAggregator_CJK verifies Chinese and Japanese replies are split
+// at full-width punctuation as they stream, without trailing spaces
+func TestSentenceAggregator_CJK(t *testing.T) {
+	for _, tc := range []struct {
+		language string
+		tokens   []string
+		want     []string
+	}{
+		{"zh", []string{"你好", "。今天", "天气很好！我们", "去公园吧?好的"}, []string{"你好。", "今天天气很好！", "我们去公园吧?", "好的"}},
+		{"ja", []string{"こんにちは。元気", "ですか？「はい", "。」と答えた"}, []string{"こんにちは。", "元気ですか？", "「はい。」", "と答えた"}},
+	} {
+		t.Run(tc.language, func(t *testing.T) {
+			aggregator := NewSentenceAggregator()
+			aggregator.SetLanguage(tc.language)
+			capture := &captureProc{}
+			aggregator.Link(capture)
+
+			for _, token := range tc.tokens {
+				if err := aggregator.HandleFrame(context.Background(), frames.NewLLMTextFrame(token), frames.Downstream); err != nil {
+					t.Fatalf("HandleFrame failed: %v", err)
+				}
+			}
+			if err := aggregator.HandleFrame(context.Background(), frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(LLMFullResponseEndFrame) failed: %v", err)
+			}
+
+			var got []string
+			for _, f := range capture.get() {
+				if text, ok := f.(*frames.TextFrame); ok {
+					got = append(got, strings.TrimSpace(text.Text))
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Sentences = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// LanguageMetadataKey is the text frame metadata key holding the language
+// the text is written in, set by the TranslationProcessor
+const LanguageMetadataKey = "language"
+
+// DefaultTranslationTimeout bounds a single translation call
+const DefaultTranslationTimeout = 5 * time.Second
+
+// Translator translates text into a target language (e.g. "es"). Wrap a
+// translation API or a one-shot LLM prompt.
+type Translator interface {
+	Translate(ctx context.Context, text, targetLanguage string) (string, error)
+}
+
+// TranslatorFunc adapts a function to the Translator interface
+type TranslatorFunc func(ctx context.Context, text, targetLanguage string) (string, error)
+
+// Translate calls f
+func (f TranslatorFunc) Translate(ctx context.Context, text, targetLanguage string) (string, error) {
+	return f(ctx, text, targetLanguage)
+}
+
+// TranslationConfig configures a TranslationProcessor
+type TranslationConfig struct {
+	Translator     Translator    // Translation backend (required)
+	TargetLanguage string        // Language the bot speaks, e.g. "es" (required)
+	Timeout        time.Duration // Per-sentence translation timeout (default: 5s)
+}
+
+// TranslationProcessor translates the bot's text into another language so
+// the user can speak one language and hear replies in another. Place it
+// between the LLM and the TTS service.
+//
+// LLM tokens are buffered into sentences and each sentence is translated
+// whole, so the output keeps the source's sentence boundaries. Translated
+// sentences are pushed as TextFrames tagged with LanguageMetadataKey, and a
+// SetVoiceFrame with the "language" setting follows the StartFrame so the
+// TTS voices the target language. If a translation fails the sentence is
+// spoken untranslated.
+type TranslationProcessor struct {
+	*processors.BaseProcessor
+	config TranslationConfig
+	buffer strings.Builder
+	log    *logger.Logger
+}
+
+// NewTranslationProcessor creates a new translation processor
+func NewTranslationProcessor(config TranslationConfig) *TranslationProcessor {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTranslationTimeout
+	}
+	p := &TranslationProcessor{
+		config: config,
+		log:    logger.WithPrefix("Translation"),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("TranslationProcessor", p)
+	return p
+}
+
+func (p *TranslationProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	// Upstream frames (e.g. TTS word timestamps) are already in the target language
+	if direction == frames.Upstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	switch f := frame.(type) {
+	case *frames.StartFrame:
+		if err := p.PushFrame(frame, direction); err != nil {
+			return err
+		}
+		settings := map[string]interface{}{"language": p.config.TargetLanguage}
+		return p.PushFrame(frames.NewSetVoiceFrame("", "", settings), frames.Downstream)
+
+	case *frames.LLMTextFrame:
+		if f.SkipTTS {
+			return p.PushFrame(frame, direction)
+		}
+		return p.processText(ctx, f.Text)
+
+	case *frames.TextFrame:
+		if f.SkipTTS {
+			return p.PushFrame(frame, direction)
+		}
+		return p.processText(ctx, f.Text)
+
+	case *frames.LLMFullResponseEndFrame, *frames.EndFrame:
+		if err := p.flushBuffer(ctx); err != nil {
+			return err
+		}
+		return p.PushFrame(frame, direction)
+
+	case *frames.InterruptionFrame:
+		if p.buffer.Len() > 0 {
+			p.log.Debug("Clearing buffer on interruption (%d bytes)", p.buffer.Len())
+			p.buffer.Reset()
+		}
+		return p.PushFrame(frame, direction)
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+// processText buffers text and translates each complete sentence
+func (p *TranslationProcessor) processText(ctx context.Context, text string) error {
+	p.buffer.WriteString(text)
+	sentences, remainder := extractSentences(p.buffer.String())
+	p.buffer.Reset()
+	p.buffer.WriteString(remainder)
+
+	for _, sentence := range sentences {
+		if err := p.pushTranslation(ctx, sentence, " "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushBuffer translates any incomplete sentence left at the end of a response
+func (p *TranslationProcessor) flushBuffer(ctx context.Context) error {
+	remainder := p.buffer.String()
+	p.buffer.Reset()
+	return p.pushTranslation(ctx, remainder, "")
+}
+
+// pushTranslation translates one sentence and pushes it with suffix appended
+func (p *TranslationProcessor) pushTranslation(ctx context.Context, sentence, suffix string) error {
+	sentence = strings.TrimSpace(sentence)
+	if sentence == "" {
+		return nil
+	}
+
+	translateCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	translated, err := p.config.Translator.Translate(translateCtx, sentence, p.config.TargetLanguage)
+	cancel()
+	translated = strings.TrimSpace(translated)
+	if err == nil && translated == "" {
+		err = errors.New("empty translation")
+	}
+	if err != nil {
+		p.log.Warn("Translation to %s failed, speaking original text: %v", p.config.TargetLanguage, err)
+		translated = sentence
+	} else {
+		p.log.Debug("Translated %q -> %q", sentence, translated)
+	}
+
+	textFrame := frames.NewTextFrame(translated + suffix)
+	textFrame.SetMetadata(LanguageMetadataKey, p.config.TargetLanguage)
+	return p.PushFrame(textFrame, frames.Downstream)
+}
@@ -0,0 +1,126 @@
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// mockTranslator translates from a fixed phrasebook and records its calls
+type mockTranslator struct {
+	phrases map[string]string
+	calls   []string
+}
+
+func (m *mockTranslator) Translate(ctx context.Context, text, targetLanguage string) (string, error) {
+	m.calls = append(m.calls, text)
+	translated, ok := m.phrases[text]
+	if !ok {
+		return "", errors.New("unknown phrase")
+	}
+	return translated, nil
+}
+
+func newTestTranslation(translator Translator) (*TranslationProcessor, *captureProc) {
+	p := NewTranslationProcessor(TranslationConfig{Translator: translator, TargetLanguage: "es"})
+	downstream := &captureProc{}
+	p.Link(downstream)
+	return p, downstream
+}
+
+// spokenText returns the text frames that reached downstream
+func spokenText(t *testing.T, c *captureProc) []string {
+	t.Helper()
+	var out []string
+	for _, f := range c.get() {
+		if textFrame, ok := f.(*frames.TextFrame); ok {
+			if lang := textFrame.Metadata()[LanguageMetadataKey]; lang != "es" {
+				t.Errorf("TextFrame %q language = %v, want es", textFrame.Text, lang)
+			}
+			out = append(out, textFrame.Text)
+		}
+	}
+	return out
+}
+
+func TestTranslationProcessor_TranslatesSentences(t *testing.T) {
+	translator := &mockTranslator{phrases: map[string]string{
+		"Hello there.": "Hola.",
+		"How are you?": "¿Cómo estás?",
+		"See you soon": "Hasta pronto",
+	}}
+	p, downstream := newTestTranslation(translator)
+	ctx := context.Background()
+
+	p.HandleFrame(ctx, frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	for _, token := range []string{"Hello", " there.", " How", " are you?", " See you", " soon"} {
+		if err := p.HandleFrame(ctx, frames.NewLLMTextFrame(token), frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+	}
+	p.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	want := []string{"Hola. ", "¿Cómo estás? ", "Hasta pronto"}
+	got := spokenText(t, downstream)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Sentence %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if len(translator.calls) != 3 {
+		t.Errorf("Expected one translation per sentence, got %q", translator.calls)
+	}
+
+	// The trailing sentence is flushed before the end of the response
+	all := downstream.get()
+	if _, ok := all[len(all)-1].(*frames.LLMFullResponseEndFrame); !ok {
+		t.Errorf("Expected LLMFullResponseEndFrame last, got %s", all[len(all)-1].Name())
+	}
+}
+
+func TestTranslationProcessor_SetsTTSLanguageOnStart(t *testing.T) {
+	p, downstream := newTestTranslation(&mockTranslator{})
+	p.HandleFrame(context.Background(), frames.NewStartFrame(), frames.Downstream)
+
+	all := downstream.get()
+	if len(all) != 2 {
+		t.Fatalf("Expected StartFrame and SetVoiceFrame, got %d frames", len(all))
+	}
+	setVoice, ok := all[1].(*frames.SetVoiceFrame)
+	if !ok {
+		t.Fatalf("Expected SetVoiceFrame after StartFrame, got %s", all[1].Name())
+	}
+	if language, _ := setVoice.StringSetting("language"); language != "es" {
+		t.Errorf("SetVoiceFrame language = %q, want es", language)
+	}
+}
+
+func TestTranslationProcessor_FallsBackToSourceText(t *testing.T) {
+	p, downstream := newTestTranslation(&mockTranslator{})
+	ctx := context.Background()
+
+	p.HandleFrame(ctx, frames.NewTextFrame("Untranslatable."), frames.Downstream)
+	if got := spokenText(t, downstream); len(got) != 1 || got[0] != "Untranslatable. " {
+		t.Errorf("Expected the original sentence on failure, got %q", got)
+	}
+}
+
+func TestTranslationProcessor_InterruptionDropsPartialSentence(t *testing.T) {
+	translator := &mockTranslator{phrases: map[string]string{"Goodbye.": "Adiós."}}
+	p, downstream := newTestTranslation(translator)
+	ctx := context.Background()
+
+	p.HandleFrame(ctx, frames.NewLLMTextFrame("I was saying"), frames.Downstream)
+	p.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	p.HandleFrame(ctx, frames.NewLLMTextFrame("Goodbye."), frames.Downstream)
+	p.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	if got := spokenText(t, downstream); len(got) != 1 || got[0] != "Adiós. " {
+		t.Errorf("Expected only the post-interruption sentence, got %q", got)
+	}
+}
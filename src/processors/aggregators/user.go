@@ -2,8 +2,10 @@ package aggregators
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
@@ -13,6 +15,11 @@ import (
 
 const defaultUserAggregationTimeout = 500 * time.Millisecond
 
+// defaultInterruptionFinalizeTimeout bounds how long the aggregator waits for
+// the STT final that follows an interruption before falling back to the last
+// interim transcript.
+const defaultInterruptionFinalizeTimeout = 1 * time.Second
+
 type LLMUserAggregator struct {
 	*LLMContextAggregator
 
@@ -20,12 +27,62 @@ type LLMUserAggregator struct {
 
 	userSpeaking          bool
 	botSpeaking           bool
+	llmGenerating         bool
 	userTurnActive        bool
 	seenInterimResults    bool
 	waitingForAggregation bool
 	interruptionSent      bool
 	mutedState            bool
 
+	// lastInterim is the latest interim transcript of the current utterance.
+	// On interruption it becomes pendingInterim until the STT final arrives.
+	lastInterim         string
+	pendingInterim      string
+	pendingInterimSince time.Time
+
+	// Interruption confirmation: a candidate interruption waits this long and
+	// is dropped if the user stops speaking first (protected by stateMu)
+	confirmWindow time.Duration
+	confirmTimer  *time.Timer
+	confirmGen    uint64
+
+	// Barge-in is ignored until the bot has spoken for minBotSpeech,
+	// measured from botSpeechStart (protected by stateMu)
+	minBotSpeech   time.Duration
+	botSpeechStart time.Time
+
+	// A ForceSpeakFrame utterance is queued or playing; barge-in is ignored
+	// until the bot stops speaking after forcedStarted (protected by stateMu)
+	forcedSpeech  bool
+	forcedStarted bool
+
+	// Barge-in while the LLM is generating but TTS hasn't started yet also
+	// interrupts, cancelling the in-flight response (protected by stateMu)
+	interruptGeneration bool
+
+	// Interim debounce: interims within interimDebounce of the last handled
+	// one skip turn handling; the newest is re-queued when the window ends
+	// (protected by stateMu)
+	interimDebounce    time.Duration
+	lastInterimHandled time.Time
+	debouncedInterim   *frames.TranscriptionFrame
+	requeuedInterim    *frames.TranscriptionFrame
+	interimSeq         uint64
+	requeuedSeq        uint64
+
+	// Final segment reconciliation: the words and audio end of the last
+	// final since an utterance boundary, used to trim re-segmented overlap
+	// (protected by stateMu)
+	lastFinalWords []string
+	lastFinalEnd   time.Duration
+
+	// Max utterance: interims that run past maxUtterance without a final
+	// are flushed to the LLM; flushedWords are then trimmed from the interims
+	// and final that continue the utterance (protected by stateMu)
+	maxUtterance   time.Duration
+	utteranceStart time.Time
+	flushedWords   []string
+
 	stateMu sync.Mutex
 
 	aggregationCtx    context.Context
@@ -35,14 +92,74 @@ type LLMUserAggregator struct {
 
 func NewLLMUserAggregator(context *services.LLMContext, strategies turns.UserTurnStrategies) *LLMUserAggregator {
 	u := &LLMUserAggregator{
-		turnStrategies:   strategies,
-		aggregationEvent: make(chan struct{}, 1),
+		turnStrategies:      strategies,
+		aggregationEvent:    make(chan struct{}, 1),
+		interruptGeneration: true,
 	}
 
 	u.LLMContextAggregator = NewLLMContextAggregator("LLMUserAggregator", context, "user", u)
 	return u
 }
 
+// SetInterruptionConfirmation requires the user to keep speaking for window
+// before a barge-in interrupts the bot. On noisy lines a burst can trip the
+// start strategy and then subside; with a window the candidate interruption
+// is cancelled when the user stops speaking before it elapses, so the bot
+// keeps talking. Zero (the default) interrupts immediately.
+func (u *LLMUserAggregator) SetInterruptionConfirmation(window time.Duration) {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	u.confirmWindow = window
+}
+
+// SetMinBotSpeechBeforeInterrupt ignores barge-in until the bot has been
+// speaking for window, measured from TTSStartedFrame. The start of bot
+// speech is when echo is most likely to leak back into the input; a user
+// turn that starts within the window neither interrupts nor begins a turn,
+// so the strategies re-evaluate once it has passed. Unlike the output grace
+// period this drops the interruption decision itself. Zero (the default)
+// allows barge-in immediately.
+func (u *LLMUserAggregator) SetMinBotSpeechBeforeInterrupt(window time.Duration) {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	u.minBotSpeech = window
+}
+
+// SetInterruptDuringGeneration controls whether barge-in interrupts while the
+// LLM is generating a response that TTS hasn't started speaking yet. The bot
+// only counts as speaking from TTSStartedFrame, which lags the context push
+// by the LLM's time to first token; with this enabled (the default) a user
+// who starts talking in that gap cancels the response instead of having it
+// play over them.
+func (u *LLMUserAggregator) SetInterruptDuringGeneration(enabled bool) {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	u.interruptGeneration = enabled
+}
+
+// SetInterimDebounce handles interim transcripts at most once per window.
+// STTs like Deepgram send interims many times a second; with a window, turn
+// strategies see the first interim and then the newest one at the end of
+// each window, skipping the rest. Finals are never debounced. Zero (the
+// default) handles every interim.
+func (u *LLMUserAggregator) SetInterimDebounce(window time.Duration) {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	u.interimDebounce = window
+}
+
+// SetMaxUtteranceDuration flushes the latest interim transcript once an
+// utterance has produced interims for d without a final. In noisy
+// environments an STT like Deepgram may never mark the utterance final, so
+// without a ceiling the turn is never aggregated and the bot never responds.
+// Words already flushed are dropped from the rest of the utterance. Zero (the
+// default) waits for the final indefinitely.
+func (u *LLMUserAggregator) SetMaxUtteranceDuration(d time.Duration) {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	u.maxUtterance = d
+}
+
 func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	if startFrame, ok := frame.(*frames.StartFrame); ok {
 		u.HandleStartFrame(startFrame)
@@ -60,19 +177,33 @@ func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame,
 		if u.aggregationCancel != nil {
 			u.aggregationCancel()
 		}
+		u.stateMu.Lock()
+		u.cancelConfirmationLocked()
+		u.stateMu.Unlock()
 		return u.PushFrame(frame, direction)
 	}
 
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if forceFrame, ok := frame.(*frames.ForceSpeakFrame); ok {
+		return u.handleForceSpeak(forceFrame)
+	}
+
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
+		u.stateMu.Lock()
+		u.forcedSpeech = false
+		u.stateMu.Unlock()
 		u.HandleInterruptionFrame()
-		if err := u.Reset(); err != nil {
-			logger.Error("[%s] reset failed on interruption: %v", u.Name(), err)
-		}
+		u.handleInterruption(interruption)
 		return u.PushFrame(frame, direction)
 	}
 
 	u.updateBotSpeakingState(frame)
 
+	// The LLM reports the end of every response upstream, spoken or not; it
+	// goes no further than here
+	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok && direction == frames.Upstream {
+		return nil
+	}
+
 	if err := u.updateMuteState(frame); err != nil {
 		logger.Error("[%s] failed to push mute state frame: %v", u.Name(), err)
 	}
@@ -81,6 +212,10 @@ func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame,
 		return nil
 	}
 
+	if tf, ok := frame.(*frames.TranscriptionFrame); ok && !tf.IsFinal && tf.Text != "" && u.debounceInterim(tf) {
+		return nil
+	}
+
 	u.updateUserSpeakingState(frame)
 	u.handleTurnStart(ctx, frame)
 	u.handleTurnStop(frame)
@@ -92,13 +227,28 @@ func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame,
 
 		u.stateMu.Lock()
 		if transcriptionFrame.IsFinal {
-			u.AppendToAggregation(transcriptionFrame.Text)
+			if text := u.trimFlushedLocked(u.reconcileFinalLocked(transcriptionFrame)); text != "" {
+				u.AppendToAggregation(text)
+			}
 			u.seenInterimResults = false
+			u.lastInterim = ""
+			u.utteranceStart = time.Time{}
+			u.flushedWords = nil
+			u.dropDebouncedInterimLocked()
+			// The final supersedes any interim held across an interruption
+			if u.pendingInterim != "" {
+				u.pendingInterim = ""
+				u.waitingForAggregation = false
+			}
 		} else {
-			u.seenInterimResults = true
+			u.noteInterimLocked(transcriptionFrame.Text)
 		}
 		u.stateMu.Unlock()
 
+		if !transcriptionFrame.IsFinal {
+			u.flushLongUtterance()
+		}
+
 		if transcriptionFrame.IsFinal {
 			select {
 			case u.aggregationEvent <- struct{}{}:
@@ -130,11 +280,18 @@ func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame,
 	}
 
 	if updateFrame, ok := frame.(*frames.LLMMessagesUpdateFrame); ok {
+		if updateFrame.SystemPrompt != "" {
+			u.context.SystemPrompt = updateFrame.SystemPrompt
+			// Let the LLM service pick up the new prompt as well
+			if err := u.PushFrame(frame, frames.Downstream); err != nil {
+				return err
+			}
+		}
 		if messages, ok := updateFrame.Messages.([]services.LLMMessage); ok {
 			u.context.Messages = messages
-			if updateFrame.RunLLM {
-				return u.PushContextFrame(frames.Downstream)
-			}
+		}
+		if updateFrame.RunLLM {
+			return u.PushContextFrame(frames.Downstream)
 		}
 		return nil
 	}
@@ -142,8 +299,229 @@ func (u *LLMUserAggregator) HandleFrame(ctx context.Context, frame frames.Frame,
 	return u.PushFrame(frame, direction)
 }
 
+// debounceInterim reports whether an interim should skip turn handling
+// because another was handled less than interimDebounce ago. A skipped
+// interim still updates lastInterim, and the newest one is re-queued when
+// the window ends so strategies see the latest text.
+func (u *LLMUserAggregator) debounceInterim(frame *frames.TranscriptionFrame) bool {
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+	if u.interimDebounce <= 0 {
+		return false
+	}
+
+	if frame == u.requeuedInterim {
+		u.requeuedInterim = nil
+		if u.requeuedSeq != u.interimSeq {
+			return true // A newer interim or a final was handled meanwhile
+		}
+		u.lastInterimHandled = time.Now()
+		return false
+	}
+
+	u.interimSeq++
+	wait := u.interimDebounce - time.Since(u.lastInterimHandled)
+	if wait <= 0 {
+		u.debouncedInterim = nil
+		u.lastInterimHandled = time.Now()
+		return false
+	}
+
+	u.noteInterimLocked(frame.Text)
+	if u.debouncedInterim == nil {
+		time.AfterFunc(wait, u.requeueDebouncedInterim)
+	}
+	u.debouncedInterim = frame
+	return true
+}
+
+// requeueDebouncedInterim queues the newest skipped interim for handling
+// once its debounce window has ended
+func (u *LLMUserAggregator) requeueDebouncedInterim() {
+	u.stateMu.Lock()
+	frame := u.debouncedInterim
+	u.debouncedInterim = nil
+	u.requeuedInterim = frame
+	u.requeuedSeq = u.interimSeq
+	u.stateMu.Unlock()
+
+	if frame != nil {
+		if err := u.QueueFrame(frame, frames.Downstream); err != nil {
+			logger.Debug("[%s] dropping debounced interim: %v", u.Name(), err)
+		}
+	}
+}
+
+// dropDebouncedInterimLocked discards any interim still waiting out its
+// debounce window. Caller must hold stateMu.
+func (u *LLMUserAggregator) dropDebouncedInterimLocked() {
+	u.debouncedInterim = nil
+	u.interimSeq++
+}
+
+// noteInterimLocked records the latest interim of the current utterance.
+// Caller must hold stateMu.
+func (u *LLMUserAggregator) noteInterimLocked(text string) {
+	u.seenInterimResults = true
+	u.lastInterim = u.trimFlushedLocked(text)
+	if u.utteranceStart.IsZero() {
+		u.utteranceStart = time.Now()
+	}
+}
+
+// flushLongUtterance aggregates the latest interim and pushes it to the LLM
+// once the utterance has gone maxUtterance without a final
+func (u *LLMUserAggregator) flushLongUtterance() {
+	u.stateMu.Lock()
+	if u.maxUtterance <= 0 || u.utteranceStart.IsZero() || u.lastInterim == "" ||
+		time.Since(u.utteranceStart) < u.maxUtterance {
+		u.stateMu.Unlock()
+		return
+	}
+	logger.Warn("[%s] No final after %v, flushing interim: %s", u.Name(), u.maxUtterance, u.lastInterim)
+	u.AppendToAggregation(u.lastInterim)
+	u.flushedWords = append(u.flushedWords, strings.Fields(u.lastInterim)...)
+	u.lastInterim = ""
+	u.utteranceStart = time.Now() // The rest of the utterance gets its own ceiling
+	u.dropDebouncedInterimLocked()
+	u.stateMu.Unlock()
+
+	if err := u.processAggregation(); err != nil {
+		logger.Error("[%s] failed to push aggregation on max utterance: %v", u.Name(), err)
+	}
+}
+
+// trimFlushedLocked drops the leading words of text that were already
+// flushed by the max utterance ceiling. Caller must hold stateMu.
+func (u *LLMUserAggregator) trimFlushedLocked(text string) string {
+	if len(u.flushedWords) == 0 {
+		return text
+	}
+	words := strings.Fields(text)
+	n := 0
+	for n < len(words) && n < len(u.flushedWords) && normalizeWord(words[n]) == normalizeWord(u.flushedWords[n]) {
+		n++
+	}
+	return strings.Join(words[n:], " ")
+}
+
+// reconcileFinalLocked returns the text of a final segment minus any words
+// already aggregated from the previous one. STTs like Deepgram can
+// re-segment audio so consecutive finals overlap; when the segment starts
+// before the previous one ended, the longest run of the previous segment's
+// trailing words that begins the new one is dropped. Segments without audio
+// timing, or after a speech_final boundary, are taken as-is, so genuine
+// repetition ("no, no") is kept. Caller must hold stateMu.
+func (u *LLMUserAggregator) reconcileFinalLocked(frame *frames.TranscriptionFrame) string {
+	words := strings.Fields(frame.Text)
+	end := frame.AudioStart + frame.AudioDuration
+
+	if frame.AudioDuration > 0 && len(u.lastFinalWords) > 0 && frame.AudioStart < u.lastFinalEnd {
+		if overlap := wordOverlap(u.lastFinalWords, words); overlap > 0 {
+			logger.Debug("[%s] Dropping %d words overlapping the previous final: %q",
+				u.Name(), overlap, strings.Join(words[:overlap], " "))
+			words = words[overlap:]
+		}
+	}
+
+	if frame.SpeechFinal || frame.AudioDuration <= 0 {
+		u.lastFinalWords = nil
+		u.lastFinalEnd = 0
+	} else {
+		u.lastFinalWords = strings.Fields(frame.Text)
+		if end > u.lastFinalEnd {
+			u.lastFinalEnd = end
+		}
+	}
+
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+// wordOverlap returns the length of the longest suffix of prev that is a
+// prefix of next, comparing words case- and punctuation-insensitively
+func wordOverlap(prev, next []string) int {
+	for n := min(len(prev), len(next)); n > 0; n-- {
+		match := true
+		for i := 0; i < n; i++ {
+			if normalizeWord(prev[len(prev)-n+i]) != normalizeWord(next[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return n
+		}
+	}
+	return 0
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r)
+	}))
+}
+
+// handleInterruption resets turn state while keeping the words of the
+// interrupting utterance. Text already aggregated is carried over, and the
+// latest interim is held until the STT final (triggered by the upstream
+// finalize) replaces it, or promoted if no final arrives in time. A user
+// barge-in doesn't finalize the STT stream, so its interim isn't held: the
+// utterance's own final carries those words, and promoting the interim
+// first would duplicate them.
+func (u *LLMUserAggregator) handleInterruption(interruption *frames.InterruptionFrame) {
+	u.stateMu.Lock()
+	carried := append([]string(nil), u.aggregation...)
+	lastFinalWords, lastFinalEnd := u.lastFinalWords, u.lastFinalEnd
+	flushedWords := u.flushedWords
+	interim := u.lastInterim
+	userSpeaking := u.userSpeaking
+	u.stateMu.Unlock()
+
+	if err := u.Reset(); err != nil {
+		logger.Error("[%s] reset failed on interruption: %v", u.Name(), err)
+	}
+
+	u.stateMu.Lock()
+	defer u.stateMu.Unlock()
+
+	u.userSpeaking = userSpeaking
+	for _, text := range carried {
+		u.AppendToAggregation(text)
+	}
+	u.lastFinalWords, u.lastFinalEnd = lastFinalWords, lastFinalEnd
+	u.flushedWords = flushedWords
+	if interim != "" && !interruption.IsUserSpeech() {
+		u.pendingInterim = interim
+		u.pendingInterimSince = time.Now()
+		u.waitingForAggregation = true
+		logger.Debug("[%s] Holding interim %q until STT finalize", u.Name(), interim)
+	}
+}
+
+// promoteStalePendingInterim appends the held interim to the aggregation if
+// the STT final did not arrive within the finalize timeout.
+// Caller must hold stateMu.
+func (u *LLMUserAggregator) promoteStalePendingInterim() {
+	if u.pendingInterim == "" || time.Since(u.pendingInterimSince) < defaultInterruptionFinalizeTimeout {
+		return
+	}
+	logger.Debug("[%s] No final after interruption, using interim: %s", u.Name(), u.pendingInterim)
+	u.AppendToAggregation(u.pendingInterim)
+	u.pendingInterim = ""
+	u.waitingForAggregation = false
+}
+
 func (u *LLMUserAggregator) pushAggregation() error {
 	u.stateMu.Lock()
+	if u.waitingForAggregation {
+		// Still waiting on the final for an interrupted utterance; the
+		// aggregation task pushes once it arrives or times out.
+		u.stateMu.Unlock()
+		return nil
+	}
 	if len(u.aggregation) == 0 {
 		u.stateMu.Unlock()
 		return nil
@@ -172,6 +550,15 @@ func (u *LLMUserAggregator) processAggregation() error {
 	return u.PushContextFrame(frames.Downstream)
 }
 
+// PushContextFrame pushes the context to the LLM and marks a response as
+// generating until TTS starts speaking it or the LLM reports its end upstream
+func (u *LLMUserAggregator) PushContextFrame(direction frames.FrameDirection) error {
+	u.stateMu.Lock()
+	u.llmGenerating = true
+	u.stateMu.Unlock()
+	return u.LLMContextAggregator.PushContextFrame(direction)
+}
+
 func (u *LLMUserAggregator) aggregationTaskHandler() {
 	ticker := time.NewTicker(defaultUserAggregationTimeout / 2)
 	defer ticker.Stop()
@@ -183,9 +570,11 @@ func (u *LLMUserAggregator) aggregationTaskHandler() {
 
 		case <-ticker.C:
 			u.handleTurnStop(nil)
+			u.flushLongUtterance()
 
 			u.stateMu.Lock()
-			shouldPush := !u.userSpeaking && len(u.aggregation) > 0
+			u.promoteStalePendingInterim()
+			shouldPush := !u.userSpeaking && !u.waitingForAggregation && len(u.aggregation) > 0
 			u.stateMu.Unlock()
 
 			if shouldPush {
@@ -206,11 +595,21 @@ func (u *LLMUserAggregator) Reset() error {
 
 	u.userSpeaking = false
 	u.botSpeaking = false
+	u.botSpeechStart = time.Time{}
+	u.llmGenerating = false
 	u.userTurnActive = false
 	u.seenInterimResults = false
 	u.waitingForAggregation = false
 	u.interruptionSent = false
 	u.mutedState = false
+	u.lastInterim = ""
+	u.pendingInterim = ""
+	u.lastFinalWords = nil
+	u.lastFinalEnd = 0
+	u.utteranceStart = time.Time{}
+	u.flushedWords = nil
+	u.cancelConfirmationLocked()
+	u.dropDebouncedInterimLocked()
 
 	for _, strategy := range u.turnStrategies.StartStrategies {
 		strategy.Reset()
@@ -225,15 +624,48 @@ func (u *LLMUserAggregator) Reset() error {
 	return u.LLMContextAggregator.Reset()
 }
 
+// handleForceSpeak sends a forced utterance to TTS and ignores barge-in
+// until the bot has finished speaking
+func (u *LLMUserAggregator) handleForceSpeak(frame *frames.ForceSpeakFrame) error {
+	u.stateMu.Lock()
+	u.forcedSpeech = true
+	// Already talking: the forced text plays as part of the current speech
+	u.forcedStarted = u.botSpeaking
+	if u.cancelConfirmationLocked() {
+		logger.Debug("[%s] Dropping pending interruption for forced speech", u.Name())
+	}
+	u.stateMu.Unlock()
+
+	logger.Info("[%s] Forced speech, ignoring barge-in until the bot stops speaking", u.Name())
+	return u.PushFrame(frames.NewTextFrame(frame.Text), frames.Downstream)
+}
+
 func (u *LLMUserAggregator) updateBotSpeakingState(frame frames.Frame) {
 	switch frame.(type) {
 	case *frames.BotStartedSpeakingFrame, *frames.TTSStartedFrame:
 		u.stateMu.Lock()
+		if !u.botSpeaking || u.botSpeechStart.IsZero() {
+			u.botSpeechStart = time.Now()
+		}
 		u.botSpeaking = true
+		u.llmGenerating = false
+		if u.forcedSpeech {
+			u.forcedStarted = true
+		}
+		u.stateMu.Unlock()
+	case *frames.LLMFullResponseEndFrame:
+		u.stateMu.Lock()
+		u.llmGenerating = false
 		u.stateMu.Unlock()
 	case *frames.BotStoppedSpeakingFrame:
 		u.stateMu.Lock()
 		u.botSpeaking = false
+		u.botSpeechStart = time.Time{}
+		u.llmGenerating = false
+		if u.forcedSpeech && u.forcedStarted {
+			u.forcedSpeech = false
+			logger.Debug("[%s] Forced speech finished, barge-in enabled", u.Name())
+		}
 		u.stateMu.Unlock()
 	}
 }
@@ -248,8 +680,45 @@ func (u *LLMUserAggregator) updateUserSpeakingState(frame frames.Frame) {
 		u.stateMu.Lock()
 		u.userSpeaking = false
 		u.interruptionSent = false
+		if u.cancelConfirmationLocked() {
+			logger.Debug("[%s] User went quiet within %v, interruption not confirmed", u.Name(), u.confirmWindow)
+		}
+		u.stateMu.Unlock()
+	}
+}
+
+// confirmInterruptionLocked broadcasts the interruption once the user has kept
+// speaking for the confirmation window. Caller must hold stateMu.
+func (u *LLMUserAggregator) confirmInterruptionLocked(ctx context.Context) {
+	u.cancelConfirmationLocked()
+	u.confirmGen++
+	gen := u.confirmGen
+	u.confirmTimer = time.AfterFunc(u.confirmWindow, func() {
+		u.stateMu.Lock()
+		if gen != u.confirmGen {
+			u.stateMu.Unlock()
+			return
+		}
+		u.confirmTimer = nil
 		u.stateMu.Unlock()
+
+		logger.Debug("[%s] Sustained speech for %v, interrupting", u.Name(), u.confirmWindow)
+		if err := u.BroadcastInterruptionWithCause(ctx, frames.InterruptionCauseUserSpeech); err != nil {
+			logger.Error("[%s] failed to broadcast interruption: %v", u.Name(), err)
+		}
+	})
+}
+
+// cancelConfirmationLocked drops a pending interruption, reporting whether
+// one was pending. Caller must hold stateMu.
+func (u *LLMUserAggregator) cancelConfirmationLocked() bool {
+	u.confirmGen++
+	if u.confirmTimer == nil {
+		return false
 	}
+	u.confirmTimer.Stop()
+	u.confirmTimer = nil
+	return true
 }
 
 func (u *LLMUserAggregator) handleTurnStart(ctx context.Context, frame frames.Frame) {
@@ -271,15 +740,41 @@ func (u *LLMUserAggregator) handleTurnStart(ctx context.Context, frame frames.Fr
 			return
 		}
 
+		botResponding := u.botSpeaking || (u.interruptGeneration && u.llmGenerating)
+		shouldInterrupt := u.InterruptionsAllowed() && botResponding && strategy.EnableInterruptions() && !u.interruptionSent
+		if shouldInterrupt && u.forcedSpeech {
+			u.stateMu.Unlock()
+			logger.Debug("[%s] Ignoring barge-in during forced speech", u.Name())
+			for _, startStrategy := range u.turnStrategies.StartStrategies {
+				startStrategy.Reset()
+			}
+			return
+		}
+		if shouldInterrupt && u.botSpeaking && u.minBotSpeech > 0 {
+			if spoken := time.Since(u.botSpeechStart); spoken < u.minBotSpeech {
+				u.stateMu.Unlock()
+				logger.Debug("[%s] Ignoring barge-in %v into bot speech (minimum %v)", u.Name(), spoken.Round(time.Millisecond), u.minBotSpeech)
+				for _, startStrategy := range u.turnStrategies.StartStrategies {
+					startStrategy.Reset()
+				}
+				return
+			}
+		}
+
 		u.userTurnActive = true
-		shouldInterrupt := u.InterruptionsAllowed() && u.botSpeaking && strategy.EnableInterruptions() && !u.interruptionSent
 		if shouldInterrupt {
 			u.interruptionSent = true
+			if u.confirmWindow > 0 {
+				u.confirmInterruptionLocked(ctx)
+				shouldInterrupt = false
+			}
 		}
 		u.stateMu.Unlock()
 
 		if shouldInterrupt {
-			if err := u.BroadcastInterruption(ctx); err != nil {
+			// Start strategies all detect the user speaking, so this is a
+			// barge-in: STT should keep transcribing rather than finalize
+			if err := u.BroadcastInterruptionWithCause(ctx, frames.InterruptionCauseUserSpeech); err != nil {
 				logger.Error("[%s] failed to broadcast interruption: %v", u.Name(), err)
 			}
 		}
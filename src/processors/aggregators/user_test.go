@@ -2,9 +2,14 @@ package aggregators
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/square-key-labs/strawgo-ai/src/audio"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/services"
 	"github.com/square-key-labs/strawgo-ai/src/turns"
@@ -211,3 +216,724 @@ func TestUserAggregator_InterimReturnsNil(t *testing.T) {
 		t.Errorf("Expected nil error for final transcription, got %v", err)
 	}
 }
+
+// waitForUserMessages polls the LLMContextFrames pushed to capture until the
+// latest holds n user messages or the timeout expires. The context is only
+// read once its frame has been received, never while the aggregator writes it.
+func waitForUserMessages(capture *captureProc, n int, timeout time.Duration) []string {
+	deadline := time.Now().Add(timeout)
+	for {
+		var contents []string
+		var llmCtx *services.LLMContext
+		for _, f := range capture.get() {
+			if ctxFrame, ok := f.(*frames.LLMContextFrame); ok {
+				llmCtx = ctxFrame.Context.(*services.LLMContext)
+			}
+		}
+		if llmCtx != nil {
+			for _, msg := range llmCtx.Messages {
+				if msg.Role == "user" {
+					contents = append(contents, msg.Content)
+				}
+			}
+		}
+		if len(contents) >= n || time.Now().After(deadline) {
+			return contents
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestUserAggregator_InterruptionKeepsInterruptingUtterance simulates
+// interim -> interruption -> final and verifies the final interrupting
+// utterance is fully captured in context.
+func TestUserAggregator_InterruptionKeepsInterruptingUtterance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	llmCtx := &services.LLMContext{
+		Messages: []services.LLMMessage{},
+	}
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewTranscriptionUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	capture := &captureProc{}
+	aggregator.Link(capture)
+	aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("wait I", false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+
+	aggregator.stateMu.Lock()
+	if aggregator.pendingInterim != "wait I" {
+		t.Errorf("Expected pending interim 'wait I' after interruption, got %q", aggregator.pendingInterim)
+	}
+	aggregator.stateMu.Unlock()
+
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("wait I have a question", true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	messages := waitForUserMessages(capture, 1, 2*time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 user message, got %d: %v", len(messages), messages)
+	}
+	if messages[0] != "wait I have a question" {
+		t.Errorf("Expected 'wait I have a question', got %q", messages[0])
+	}
+}
+
+// TestUserAggregator_UserSpeechInterruptionDoesNotHoldInterim simulates
+// interim -> barge-in -> final arriving after the finalize timeout. No
+// finalize is sent for a barge-in, so the interim must not be promoted ahead
+// of the utterance's own final and duplicate its words.
+func TestUserAggregator_UserSpeechInterruptionDoesNotHoldInterim(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	llmCtx := &services.LLMContext{
+		Messages: []services.LLMMessage{},
+	}
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewTranscriptionUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	capture := &captureProc{}
+	aggregator.Link(capture)
+	aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("wait I", false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewInterruptionFrameWithCause(frames.InterruptionCauseUserSpeech), frames.Downstream)
+
+	// The user keeps talking past the finalize timeout
+	time.Sleep(defaultInterruptionFinalizeTimeout + 200*time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("wait I have a question", true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	messages := waitForUserMessages(capture, 1, 2*time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 user message, got %d: %v", len(messages), messages)
+	}
+	if messages[0] != "wait I have a question" {
+		t.Errorf("Expected 'wait I have a question', got %q", messages[0])
+	}
+}
+
+// TestUserAggregator_InterruptionCarriesFinalText verifies that final text
+// aggregated before an interruption arrives is not wiped by the reset.
+func TestUserAggregator_InterruptionCarriesFinalText(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	llmCtx := &services.LLMContext{
+		Messages: []services.LLMMessage{},
+	}
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewTranscriptionUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	capture := &captureProc{}
+	aggregator.Link(capture)
+	aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("no", true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("that's wrong", true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	messages := waitForUserMessages(capture, 1, 2*time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 user message, got %d: %v", len(messages), messages)
+	}
+	if messages[0] != "no that's wrong" {
+		t.Errorf("Expected 'no that's wrong', got %q", messages[0])
+	}
+}
+
+// TestUserAggregator_InterruptionFallsBackToInterim verifies the held interim
+// is used when no final arrives after the interruption.
+func TestUserAggregator_InterruptionFallsBackToInterim(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	llmCtx := &services.LLMContext{
+		Messages: []services.LLMMessage{},
+	}
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewTranscriptionUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	capture := &captureProc{}
+	aggregator.Link(capture)
+	aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("hold on", false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	messages := waitForUserMessages(capture, 1, defaultInterruptionFinalizeTimeout+2*time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 user message, got %d: %v", len(messages), messages)
+	}
+	if messages[0] != "hold on" {
+		t.Errorf("Expected 'hold on', got %q", messages[0])
+	}
+}
+
+// TestUserAggregator_SystemPromptUpdateKeepsHistory verifies that a
+// system-prompt-only update swaps the prompt without clearing messages.
+func TestUserAggregator_SystemPromptUpdateKeepsHistory(t *testing.T) {
+	llmCtx := services.NewLLMContext("You are a sales agent")
+	llmCtx.AddUserMessage("hi")
+	llmCtx.AddAssistantMessage("hello")
+
+	aggregator := NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{})
+
+	update := frames.NewLLMSystemPromptUpdateFrame("You are a support agent")
+	if err := aggregator.HandleFrame(context.Background(), update, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMMessagesUpdateFrame) failed: %v", err)
+	}
+
+	if llmCtx.SystemPrompt != "You are a support agent" {
+		t.Errorf("Expected updated system prompt, got %q", llmCtx.SystemPrompt)
+	}
+	if len(llmCtx.Messages) != 2 {
+		t.Errorf("Expected conversation history to be kept, got %d messages", len(llmCtx.Messages))
+	}
+}
+
+func newConfirmingAggregator(t *testing.T, window time.Duration) (*LLMUserAggregator, *captureProc) {
+	t.Helper()
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewVADUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+	aggregator := NewLLMUserAggregator(&services.LLMContext{Messages: []services.LLMMessage{}}, strategies)
+	aggregator.SetInterruptionConfirmation(window)
+	downstream := &captureProc{}
+	aggregator.Link(downstream)
+	aggregator.HandleFrame(context.Background(), frames.NewStartFrameWithConfig(true, strategies), frames.Downstream)
+	return aggregator, downstream
+}
+
+func countInterruptions(c *captureProc) int {
+	n := 0
+	for _, f := range c.get() {
+		if _, ok := f.(*frames.InterruptionFrame); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// TestUserAggregator_NoiseBurstNotConfirmed verifies a barge-in that stops
+// within the confirmation window does not interrupt the bot.
+func TestUserAggregator_NoiseBurstNotConfirmed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 100*time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	time.Sleep(20 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+	time.Sleep(200 * time.Millisecond)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected no interruption for a noise burst, got %d", n)
+	}
+}
+
+// TestUserAggregator_SustainedSpeechInterrupts verifies a barge-in that lasts
+// past the confirmation window interrupts the bot, but not before it.
+func TestUserAggregator_SustainedSpeechInterrupts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 100*time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected interruption to wait for confirmation, got %d", n)
+	}
+
+	downstream.waitFor(t, "InterruptionFrame", time.Second)
+	if n := countInterruptions(downstream); n != 1 {
+		t.Errorf("Expected exactly 1 interruption, got %d", n)
+	}
+	for _, f := range downstream.get() {
+		if interruption, ok := f.(*frames.InterruptionFrame); ok && !interruption.IsUserSpeech() {
+			t.Errorf("Expected barge-in cause %q, got %q", frames.InterruptionCauseUserSpeech, interruption.Cause)
+		}
+	}
+}
+
+// interimRecorder is a start strategy that records the interim text it sees
+type interimRecorder struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (r *interimRecorder) ShouldStart(frame any) bool {
+	if tf, ok := frame.(*frames.TranscriptionFrame); ok && !tf.IsFinal {
+		r.mu.Lock()
+		r.texts = append(r.texts, tf.Text)
+		r.mu.Unlock()
+	}
+	return false
+}
+func (r *interimRecorder) EnableInterruptions() bool { return true }
+func (r *interimRecorder) Reset()                    {}
+
+func (r *interimRecorder) seen() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.texts...)
+}
+
+// TestUserAggregator_InterimDebounce verifies rapid interims are throttled,
+// the newest still reaches the strategies, and the final is honored.
+func TestUserAggregator_InterimDebounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := &interimRecorder{}
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{recorder},
+	}
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	aggregator.SetInterimDebounce(100 * time.Millisecond)
+	capture := &captureProc{}
+	aggregator.Link(capture)
+	if err := aggregator.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer aggregator.Stop()
+	aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+	// 20 interims over ~200ms
+	for i := 1; i <= 20; i++ {
+		aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame(fmt.Sprintf("word %d", i), false), frames.Downstream)
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Let the window of the re-queued newest interim close too
+	time.Sleep(250 * time.Millisecond)
+
+	seen := recorder.seen()
+	if len(seen) < 2 || len(seen) > 5 {
+		t.Fatalf("Expected interims throttled to ~1 per 100ms, strategies saw %d: %v", len(seen), seen)
+	}
+	if seen[0] != "word 1" || seen[len(seen)-1] != "word 20" {
+		t.Errorf("Expected the first and newest interims to be handled, got %v", seen)
+	}
+
+	// An interim still inside its window is dropped once the final arrives
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("word 21", false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("word 22", false), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("the final words", true), frames.Downstream)
+
+	messages := waitForUserMessages(capture, 1, time.Second)
+	if len(messages) != 1 || messages[0] != "the final words" {
+		t.Fatalf("Expected the final to be aggregated, got %v", messages)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if after := recorder.seen(); len(after) != len(seen)+1 {
+		t.Errorf("Expected only word 21 handled around the final, strategies saw %v", after[len(seen):])
+	}
+}
+
+// finalSegment builds a final transcription placed in the STT audio stream
+func finalSegment(text string, start, duration time.Duration, speechFinal bool) *frames.TranscriptionFrame {
+	frame := frames.NewTranscriptionFrame(text, true)
+	frame.AudioStart = start
+	frame.AudioDuration = duration
+	frame.SpeechFinal = speechFinal
+	return frame
+}
+
+// TestUserAggregator_ReconcilesOverlappingFinals verifies re-segmented finals
+// that overlap the previous one don't duplicate words, while repetition in
+// separate audio or across an utterance boundary is kept.
+func TestUserAggregator_ReconcilesOverlappingFinals(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		segments []*frames.TranscriptionFrame
+		want     string
+	}{
+		{
+			name: "overlapping words trimmed",
+			segments: []*frames.TranscriptionFrame{
+				finalSegment("I would like to", 0, 1500*ms, false),
+				finalSegment("like to book a table.", 1000*ms, 1500*ms, true),
+			},
+			want: "I would like to book a table.",
+		},
+		{
+			name: "overlap ignores case and punctuation",
+			segments: []*frames.TranscriptionFrame{
+				finalSegment("Send it to Paris,", 0, 1200*ms, false),
+				finalSegment("paris tomorrow", 900*ms, 800*ms, true),
+			},
+			want: "Send it to Paris, tomorrow",
+		},
+		{
+			name: "repeated segment dropped",
+			segments: []*frames.TranscriptionFrame{
+				finalSegment("hello there", 0, 1000*ms, false),
+				finalSegment("hello there", 200*ms, 800*ms, true),
+			},
+			want: "hello there",
+		},
+		{
+			name: "repetition in separate audio kept",
+			segments: []*frames.TranscriptionFrame{
+				finalSegment("no", 0, 500*ms, false),
+				finalSegment("no", 600*ms, 400*ms, true),
+			},
+			want: "no no",
+		},
+		{
+			name: "speech_final boundary resets",
+			segments: []*frames.TranscriptionFrame{
+				finalSegment("yes", 0, 500*ms, true),
+				finalSegment("yes please", 300*ms, 700*ms, true),
+			},
+			want: "yes yes please",
+		},
+		{
+			name: "untimed finals kept",
+			segments: []*frames.TranscriptionFrame{
+				frames.NewTranscriptionFrame("go go", true),
+				frames.NewTranscriptionFrame("go", true),
+			},
+			want: "go go go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+			strategies := turns.UserTurnStrategies{
+				StartStrategies: []user_start.UserTurnStartStrategy{
+					user_start.NewTranscriptionUserTurnStartStrategy(true),
+				},
+				StopStrategies: []user_stop.UserTurnStopStrategy{
+					user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+				},
+			}
+			aggregator := NewLLMUserAggregator(llmCtx, strategies)
+			capture := &captureProc{}
+			aggregator.Link(capture)
+			aggregator.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream)
+
+			aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+			for _, segment := range tt.segments {
+				aggregator.HandleFrame(ctx, segment, frames.Downstream)
+			}
+			aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+
+			messages := waitForUserMessages(capture, 1, 2*time.Second)
+			if len(messages) != 1 {
+				t.Fatalf("Expected 1 user message, got %d: %v", len(messages), messages)
+			}
+			if messages[0] != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, messages[0])
+			}
+		})
+	}
+}
+
+// TestUserAggregator_MinBotSpeechBeforeInterrupt verifies barge-in early in
+// bot speech is ignored, and honored once the bot has spoken long enough.
+func TestUserAggregator_MinBotSpeechBeforeInterrupt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 0)
+	aggregator.SetMinBotSpeechBeforeInterrupt(100 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewTTSStartedFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+
+	// Echo right as the bot starts talking
+	time.Sleep(20 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+	time.Sleep(20 * time.Millisecond)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected barge-in within the window to be ignored, got %d interruptions", n)
+	}
+
+	// BotStartedSpeakingFrame after TTSStartedFrame doesn't restart the window
+	time.Sleep(100 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 1 {
+		t.Fatalf("Expected barge-in after the window to interrupt, got %d interruptions", n)
+	}
+}
+
+// TestUserAggregator_MinBotSpeechRestartsPerUtterance verifies the window
+// is measured from the start of each bot utterance.
+func TestUserAggregator_MinBotSpeechRestartsPerUtterance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 0)
+	aggregator.SetMinBotSpeechBeforeInterrupt(100 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewTTSStartedFrame(), frames.Downstream)
+	time.Sleep(150 * time.Millisecond)
+	aggregator.HandleFrame(ctx, frames.NewBotStoppedSpeakingFrame(), frames.Downstream)
+
+	aggregator.HandleFrame(ctx, frames.NewTTSStartedFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected barge-in at the start of a new utterance to be ignored, got %d interruptions", n)
+	}
+}
+
+// TestUserAggregator_ForceSpeakIgnoresBargeIn verifies a forced utterance
+// goes to TTS and can't be interrupted, and barge-in works again after it.
+func TestUserAggregator_ForceSpeakIgnoresBargeIn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 0)
+	aggregator.HandleFrame(ctx, frames.NewForceSpeakFrame("Your flight is boarding now."), frames.Downstream)
+
+	var spoken string
+	for _, f := range downstream.get() {
+		if textFrame, ok := f.(*frames.TextFrame); ok {
+			spoken = textFrame.Text
+		}
+	}
+	if spoken != "Your flight is boarding now." {
+		t.Fatalf("Expected the forced text to be pushed to TTS, got %q", spoken)
+	}
+
+	aggregator.HandleFrame(ctx, frames.NewTTSStartedFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected barge-in during forced speech to be ignored, got %d interruptions", n)
+	}
+
+	// The next utterance is interruptible again
+	aggregator.HandleFrame(ctx, frames.NewBotStoppedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 1 {
+		t.Fatalf("Expected barge-in after forced speech to interrupt, got %d interruptions", n)
+	}
+}
+
+func countContextFrames(c *captureProc) int {
+	n := 0
+	for _, f := range c.get() {
+		if _, ok := f.(*frames.LLMContextFrame); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// TestUserAggregator_MaxUtteranceFlushesWithoutFinal verifies an utterance
+// that only ever produces interims is flushed once it passes the ceiling, and
+// the flushed words are not repeated when the utterance continues.
+func TestUserAggregator_MaxUtteranceFlushesWithoutFinal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			user_start.NewVADUserTurnStartStrategy(true),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	aggregator := NewLLMUserAggregator(llmCtx, strategies)
+	aggregator.SetMaxUtteranceDuration(200 * time.Millisecond)
+	downstream := &captureProc{}
+	aggregator.Link(downstream)
+	aggregator.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, strategies), frames.Downstream)
+	defer aggregator.HandleFrame(ctx, frames.NewEndFrame(), frames.Downstream)
+
+	// Background noise keeps VAD and the STT busy; no final ever arrives
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	interims := []string{"book", "book a", "book a table", "book a table for two"}
+	for _, text := range interims {
+		aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame(text, false), frames.Downstream)
+		if n := countContextFrames(downstream); n != 0 {
+			t.Fatalf("Expected no flush before the ceiling, got %d context frames", n)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	downstream.waitFor(t, "LLMContextFrame", time.Second)
+	first := llmCtx.Messages[len(llmCtx.Messages)-1].Content
+	if first != "book a table for two" {
+		t.Fatalf("Expected the latest interim flushed, got %q", first)
+	}
+
+	// The utterance carries on; only the new words are flushed next
+	aggregator.HandleFrame(ctx, frames.NewTranscriptionFrame("book a table for two at eight", false), frames.Downstream)
+	deadline := time.Now().Add(time.Second)
+	for countContextFrames(downstream) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := countContextFrames(downstream); n != 2 {
+		t.Fatalf("Expected a second flush, got %d context frames", n)
+	}
+	second := llmCtx.Messages[len(llmCtx.Messages)-1].Content
+	if second != "at eight" {
+		t.Fatalf("Expected only the new words flushed, got %q", second)
+	}
+}
+
+// TestUserAggregator_BargeInDuringGenerationInterrupts verifies a user who
+// starts speaking after the context was pushed but before TTS started still
+// cancels the in-flight response.
+func TestUserAggregator_BargeInDuringGenerationInterrupts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator, downstream := newConfirmingAggregator(t, 0)
+	aggregator.HandleFrame(ctx, frames.NewLLMMessagesAppendFrame([]services.LLMMessage{{Role: "user", Content: "What's the weather?"}}, true), frames.Downstream)
+	if n := countContextFrames(downstream); n != 1 {
+		t.Fatalf("Expected the context to be pushed, got %d context frames", n)
+	}
+
+	// LLM is still producing tokens; no TTSStartedFrame yet
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 1 {
+		t.Fatalf("Expected barge-in during generation to interrupt, got %d interruptions", n)
+	}
+}
+
+// TestUserAggregator_GenerationInterruptEndsWithResponse verifies barge-in
+// after the response finished speaking doesn't interrupt, and that the
+// pre-TTS interruption can be disabled.
+func TestUserAggregator_GenerationInterruptEndsWithResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages := []services.LLMMessage{{Role: "user", Content: "Hi"}}
+
+	aggregator, downstream := newConfirmingAggregator(t, 0)
+	aggregator.HandleFrame(ctx, frames.NewLLMMessagesAppendFrame(messages, true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewTTSStartedFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewBotStoppedSpeakingFrame(), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected no interruption once the response was spoken, got %d", n)
+	}
+
+	aggregator, downstream = newConfirmingAggregator(t, 0)
+	aggregator.SetInterruptDuringGeneration(false)
+	aggregator.HandleFrame(ctx, frames.NewLLMMessagesAppendFrame(messages, true), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected no interruption during generation when disabled, got %d", n)
+	}
+}
+
+// feedAudio pushes pcm through the aggregator as 20ms 16kHz AudioFrames
+func feedAudio(ctx context.Context, aggregator *LLMUserAggregator, pcm []int16) {
+	for start := 0; start+320 <= len(pcm); start += 320 {
+		aggregator.HandleFrame(ctx, frames.NewAudioFrame(audio.PCMToBytes(pcm[start:start+320]), 16000, 1), frames.Downstream)
+	}
+}
+
+// TestUserAggregator_SpeechLikelihoodGatesInterruption verifies the speech
+// likelihood start strategy ignores keyboard clicks during bot speech and
+// interrupts once the user speaks.
+func TestUserAggregator_SpeechLikelihoodGatesInterruption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	strategies := turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{
+			audio.NewSpeechLikelihoodStrategy(audio.SpeechLikelihoodConfig{}),
+		},
+		StopStrategies: []user_stop.UserTurnStopStrategy{
+			user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true),
+		},
+	}
+	aggregator := NewLLMUserAggregator(&services.LLMContext{Messages: []services.LLMMessage{}}, strategies)
+	downstream := &captureProc{}
+	aggregator.Link(downstream)
+	aggregator.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, strategies), frames.Downstream)
+	aggregator.HandleFrame(ctx, frames.NewBotStartedSpeakingFrame(), frames.Downstream)
+
+	// Half a second of 5ms keyboard clicks every 120ms
+	rng := rand.New(rand.NewSource(1))
+	clicks := make([]int16, 8000)
+	for i := range clicks {
+		if i%1920 < 80 {
+			clicks[i] = int16(15000 * (rng.Float64()*2 - 1))
+		}
+	}
+	feedAudio(ctx, aggregator, clicks)
+	if n := countInterruptions(downstream); n != 0 {
+		t.Fatalf("Expected keyboard clicks not to interrupt, got %d interruptions", n)
+	}
+
+	// Half a second of a voiced vowel: 140Hz harmonics rolling off above 700Hz
+	speech := make([]int16, 8000)
+	for i := range speech {
+		t := float64(i) / 16000
+		var v float64
+		for f := 140.0; f < 8000; f += 140 {
+			v += math.Sin(2*math.Pi*f*t) / (1 + math.Pow(f/700, 3))
+		}
+		speech[i] = int16(4000 * v)
+	}
+	feedAudio(ctx, aggregator, speech)
+	if n := countInterruptions(downstream); n != 1 {
+		t.Fatalf("Expected sustained speech to interrupt once, got %d interruptions", n)
+	}
+}
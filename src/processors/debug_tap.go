@@ -0,0 +1,295 @@
+package processors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+)
+
+// DefaultDebugTapPath is where inspectors connect when DebugTapConfig.Path
+// is unset
+const DefaultDebugTapPath = "/debug/frames"
+
+// DebugTapConfig configures a DebugTapProcessor
+type DebugTapConfig struct {
+	// Name distinguishes several taps in one pipeline (default: "DebugTap")
+	Name string
+
+	// Addr is the listen address of the inspector endpoint (e.g. ":9090").
+	// Empty means the tap doesn't listen; mount Handler() on your own server.
+	Addr string
+
+	// Path of the inspector WebSocket endpoint (default: DefaultDebugTapPath)
+	Path string
+
+	// IgnoredFrameTypes are frame types not published (e.g. audio frames)
+	IgnoredFrameTypes []frames.Frame
+
+	// InspectorBuffer is how many summaries are queued per inspector before
+	// newer ones are dropped for it (default: 256)
+	InspectorBuffer int
+}
+
+// FrameSummary is the JSON message sent to inspectors for each frame
+type FrameSummary struct {
+	Tap       string                 `json:"tap"`
+	ID        uint64                 `json:"id"`
+	Type      string                 `json:"type"`
+	Direction string                 `json:"direction"`
+	Size      int                    `json:"size"` // Bytes of audio/image data, or characters of text
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Time      time.Time              `json:"time"`
+}
+
+// DebugTapProcessor passes every frame through unchanged and publishes a
+// summary of it to inspectors connected over WebSocket, for visualizing a
+// live pipeline during development. Publishing never blocks the pipeline:
+// with no inspector connected frames aren't summarized at all, and a slow
+// inspector misses summaries rather than holding frames up.
+type DebugTapProcessor struct {
+	*BaseProcessor
+	log               *logger.Logger
+	name              string
+	addr              string
+	path              string
+	ignoredFrameTypes map[reflect.Type]bool
+	inspectorBuffer   int
+	upgrader          websocket.Upgrader
+
+	mu         sync.RWMutex
+	inspectors map[*debugInspector]struct{}
+	server     *http.Server
+}
+
+type debugInspector struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewDebugTapProcessor creates a debug tap
+func NewDebugTapProcessor(config DebugTapConfig) *DebugTapProcessor {
+	if config.Name == "" {
+		config.Name = "DebugTap"
+	}
+	if config.Path == "" {
+		config.Path = DefaultDebugTapPath
+	}
+	if config.InspectorBuffer <= 0 {
+		config.InspectorBuffer = 256
+	}
+
+	d := &DebugTapProcessor{
+		log:               logger.WithPrefix(config.Name),
+		name:              config.Name,
+		addr:              config.Addr,
+		path:              config.Path,
+		ignoredFrameTypes: make(map[reflect.Type]bool),
+		inspectorBuffer:   config.InspectorBuffer,
+		upgrader: websocket.Upgrader{
+			// Inspectors are development tools served from anywhere
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		inspectors: make(map[*debugInspector]struct{}),
+	}
+	for _, frameType := range config.IgnoredFrameTypes {
+		d.ignoredFrameTypes[reflect.TypeOf(frameType)] = true
+	}
+	d.BaseProcessor = NewBaseProcessor(config.Name, d)
+	return d
+}
+
+// Handler returns the inspector WebSocket endpoint, for mounting on an
+// existing HTTP server
+func (d *DebugTapProcessor) Handler() http.Handler {
+	return http.HandlerFunc(d.handleInspector)
+}
+
+// Start starts the processor and, when Addr is set, the inspector endpoint
+func (d *DebugTapProcessor) Start(ctx context.Context) error {
+	if err := d.BaseProcessor.Start(ctx); err != nil {
+		return err
+	}
+	if d.addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(d.path, d.Handler())
+	server := &http.Server{Addr: d.addr, Handler: mux}
+	d.mu.Lock()
+	d.server = server
+	d.mu.Unlock()
+
+	go func() {
+		d.log.Info("Inspector endpoint listening on %s%s", d.addr, d.path)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.log.Error("Inspector endpoint failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop disconnects inspectors, closes the endpoint and stops the processor
+func (d *DebugTapProcessor) Stop() error {
+	d.mu.Lock()
+	server := d.server
+	d.server = nil
+	inspectors := d.inspectors
+	d.inspectors = make(map[*debugInspector]struct{})
+	d.mu.Unlock()
+
+	if server != nil {
+		if err := server.Close(); err != nil {
+			d.log.Warn("Inspector endpoint close error: %v", err)
+		}
+	}
+	for inspector := range inspectors {
+		inspector.close()
+	}
+	return d.BaseProcessor.Stop()
+}
+
+// HandleFrame publishes a summary of frame and passes it through
+func (d *DebugTapProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if frame != nil && !d.ignoredFrameTypes[reflect.TypeOf(frame)] {
+		d.publish(frame, direction)
+	}
+	return d.PushFrame(frame, direction)
+}
+
+// InspectorCount returns the number of connected inspectors
+func (d *DebugTapProcessor) InspectorCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.inspectors)
+}
+
+func (d *DebugTapProcessor) publish(frame frames.Frame, direction frames.FrameDirection) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.inspectors) == 0 {
+		return
+	}
+
+	msg, err := json.Marshal(d.summarize(frame, direction))
+	if err != nil {
+		d.log.Debug("Could not summarize %s: %v", frame.Name(), err)
+		return
+	}
+	for inspector := range d.inspectors {
+		select {
+		case inspector.send <- msg:
+		default: // Inspector can't keep up; drop rather than stall the pipeline
+		}
+	}
+}
+
+// summarize builds the inspector message for frame
+func (d *DebugTapProcessor) summarize(frame frames.Frame, direction frames.FrameDirection) FrameSummary {
+	summary := FrameSummary{
+		Tap:       d.name,
+		ID:        frame.ID(),
+		Type:      frame.Name(),
+		Direction: "downstream",
+		Size:      frameSize(frame),
+		Time:      time.Now(),
+	}
+	if direction == frames.Upstream {
+		summary.Direction = "upstream"
+	}
+	for key, value := range frame.Metadata() {
+		switch value.(type) {
+		case string, bool, int, int64, uint64, float32, float64:
+			if summary.Metadata == nil {
+				summary.Metadata = make(map[string]interface{})
+			}
+			summary.Metadata[key] = value
+		}
+	}
+	return summary
+}
+
+// frameSize returns the length of a frame's Data bytes or, failing that,
+// its Text
+func frameSize(frame frames.Frame) int {
+	v := reflect.ValueOf(frame)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return 0
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+	if data := v.FieldByName("Data"); data.IsValid() && data.Kind() == reflect.Slice {
+		return data.Len()
+	}
+	if text := v.FieldByName("Text"); text.IsValid() && text.Kind() == reflect.String {
+		return text.Len()
+	}
+	return 0
+}
+
+func (d *DebugTapProcessor) handleInspector(w http.ResponseWriter, r *http.Request) {
+	conn, err := d.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		d.log.Warn("Inspector upgrade failed: %v", err)
+		return
+	}
+	inspector := &debugInspector{
+		conn: conn,
+		send: make(chan []byte, d.inspectorBuffer),
+		done: make(chan struct{}),
+	}
+
+	d.mu.Lock()
+	d.inspectors[inspector] = struct{}{}
+	d.mu.Unlock()
+	d.log.Info("Inspector connected from %s", r.RemoteAddr)
+
+	go inspector.writeLoop()
+
+	// Inspectors only listen; reading detects when they go away
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	d.mu.Lock()
+	delete(d.inspectors, inspector)
+	d.mu.Unlock()
+	inspector.close()
+	d.log.Info("Inspector disconnected from %s", r.RemoteAddr)
+}
+
+func (i *debugInspector) writeLoop() {
+	for {
+		select {
+		case <-i.done:
+			return
+		case msg := <-i.send:
+			i.conn.SetWriteDeadline(time.Now().Add(time.Second))
+			if err := i.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				i.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (i *debugInspector) close() {
+	i.closeOnce.Do(func() {
+		close(i.done)
+		i.conn.Close()
+	})
+}
@@ -0,0 +1,161 @@
+package processors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func startDebugTap(t *testing.T, ctx context.Context, config DebugTapConfig) (*DebugTapProcessor, *interruptionCaptureProcessor) {
+	t.Helper()
+	tap := NewDebugTapProcessor(config)
+	sink := newInterruptionCaptureProcessor("tap-sink")
+	tap.Link(sink)
+
+	if err := sink.Start(ctx); err != nil {
+		t.Fatalf("start sink: %v", err)
+	}
+	if err := tap.Start(ctx); err != nil {
+		t.Fatalf("start tap: %v", err)
+	}
+	t.Cleanup(func() {
+		tap.Stop()
+		sink.Stop()
+	})
+	return tap, sink
+}
+
+func connectInspector(t *testing.T, tap *DebugTapProcessor) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(tap.Handler())
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial inspector: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for tap.InspectorCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("inspector never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return conn
+}
+
+func readFrameSummary(t *testing.T, conn *websocket.Conn) FrameSummary {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	var summary FrameSummary
+	if err := json.Unmarshal(msg, &summary); err != nil {
+		t.Fatalf("decode summary %q: %v", msg, err)
+	}
+	return summary
+}
+
+func TestDebugTap_PublishesSummariesToInspector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tap, sink := startDebugTap(t, ctx, DebugTapConfig{})
+	conn := connectInspector(t, tap)
+
+	text := frames.NewTextFrame("hello")
+	text.SetMetadata("context_id", "ctx-1")
+	if err := tap.HandleFrame(ctx, text, frames.Downstream); err != nil {
+		t.Fatalf("handle text: %v", err)
+	}
+	audio := frames.NewTTSAudioFrame(make([]byte, 320), 16000, 1)
+	if err := tap.HandleFrame(ctx, audio, frames.Upstream); err != nil {
+		t.Fatalf("handle audio: %v", err)
+	}
+
+	got := readFrameSummary(t, conn)
+	if got.ID != text.ID() || got.Type != text.Name() || got.Direction != "downstream" || got.Size != 5 {
+		t.Fatalf("unexpected text summary: %+v", got)
+	}
+	if got.Metadata["context_id"] != "ctx-1" {
+		t.Fatalf("expected context_id metadata, got %v", got.Metadata)
+	}
+
+	got = readFrameSummary(t, conn)
+	if got.ID != audio.ID() || got.Type != audio.Name() || got.Direction != "upstream" || got.Size != 320 {
+		t.Fatalf("unexpected audio summary: %+v", got)
+	}
+
+	// Downstream frames still reach the next processor unchanged
+	if captured := waitForCapturedFrame(t, sink); captured != text {
+		t.Fatalf("expected text frame to pass through, got %v", captured)
+	}
+}
+
+func TestDebugTap_IgnoredFrameTypesNotPublished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tap, _ := startDebugTap(t, ctx, DebugTapConfig{
+		IgnoredFrameTypes: []frames.Frame{&frames.TTSAudioFrame{}},
+	})
+	conn := connectInspector(t, tap)
+
+	tap.HandleFrame(ctx, frames.NewTTSAudioFrame(make([]byte, 320), 16000, 1), frames.Downstream)
+	text := frames.NewTextFrame("after audio")
+	tap.HandleFrame(ctx, text, frames.Downstream)
+
+	if got := readFrameSummary(t, conn); got.ID != text.ID() {
+		t.Fatalf("expected only the text frame to be published, got %+v", got)
+	}
+}
+
+func TestDebugTap_PassesThroughWithoutInspector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tap, sink := startDebugTap(t, ctx, DebugTapConfig{})
+
+	text := frames.NewTextFrame("nobody watching")
+	if err := tap.HandleFrame(ctx, text, frames.Downstream); err != nil {
+		t.Fatalf("handle frame: %v", err)
+	}
+	if captured := waitForCapturedFrame(t, sink); captured != text {
+		t.Fatalf("expected text frame to pass through, got %v", captured)
+	}
+}
+
+func TestDebugTap_StopWhileInspectorDisconnects(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tap, _ := startDebugTap(t, ctx, DebugTapConfig{})
+	conn := connectInspector(t, tap)
+
+	// Stop and the handler both close the inspector; neither may panic
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.Close()
+	}()
+	tap.Stop()
+	<-done
+
+	deadline := time.Now().Add(2 * time.Second)
+	for tap.InspectorCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("inspector still registered after Stop")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,173 @@
+package processors
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+)
+
+// FunctionCallParams is passed to a FunctionHandler
+type FunctionCallParams struct {
+	ToolCallID   string
+	FunctionName string
+	Arguments    map[string]interface{}
+
+	// Progress speaks an interim update ("Still looking...") while the
+	// function runs. The text goes to TTS but is not stored in the LLM
+	// context. Calls after the handler returns are ignored.
+	Progress func(text string)
+}
+
+// FunctionHandler executes a registered function. The returned value becomes
+// the FunctionCallResultFrame's Result; an error is reported to the LLM as
+// {"error": "..."}. ctx is cancelled if the call is cancelled or, for calls
+// with CancelOnInterruption, when the user interrupts.
+type FunctionHandler func(ctx context.Context, params FunctionCallParams) (interface{}, error)
+
+// FunctionCallProcessor runs registered function handlers for the
+// FunctionCallInProgressFrames an LLM service emits and pushes their
+// FunctionCallResultFrames downstream for the assistant aggregator.
+//
+// Place it after the LLM and before TTS so progress updates are spoken:
+//
+//	llm → functionCalls → tts → transport.Output() → assistantAggregator
+//
+// Calls to unregistered functions pass through untouched, so an application
+// can still answer those itself.
+type FunctionCallProcessor struct {
+	*BaseProcessor
+
+	mu       sync.Mutex
+	handlers map[string]FunctionHandler
+	running  map[string]*runningFunctionCall // keyed by tool call ID
+}
+
+type runningFunctionCall struct {
+	cancel               context.CancelFunc
+	cancelOnInterruption bool
+}
+
+// NewFunctionCallProcessor creates a FunctionCallProcessor with no handlers
+func NewFunctionCallProcessor() *FunctionCallProcessor {
+	p := &FunctionCallProcessor{
+		handlers: make(map[string]FunctionHandler),
+		running:  make(map[string]*runningFunctionCall),
+	}
+	p.BaseProcessor = NewBaseProcessor("FunctionCallProcessor", p)
+	return p
+}
+
+// RegisterFunction registers handler for calls to the named function,
+// replacing any previous handler
+func (p *FunctionCallProcessor) RegisterFunction(name string, handler FunctionHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[name] = handler
+}
+
+// HandleFrame starts handlers for function calls and cancels them on
+// interruption or FunctionCallCancelFrame
+func (p *FunctionCallProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.FunctionCallInProgressFrame:
+		// Forward first so the assistant aggregator records the call before
+		// any progress or result arrives
+		if err := p.PushFrame(frame, direction); err != nil {
+			return err
+		}
+		p.startCall(f)
+		return nil
+
+	case *frames.FunctionCallCancelFrame:
+		p.cancelCalls(func(id string, _ *runningFunctionCall) bool { return id == f.ToolCallID })
+
+	case *frames.InterruptionFrame:
+		p.cancelCalls(func(_ string, call *runningFunctionCall) bool { return call.cancelOnInterruption })
+
+	case *frames.EndFrame, *frames.CancelFrame:
+		p.cancelCalls(func(string, *runningFunctionCall) bool { return true })
+	}
+
+	return p.PushFrame(frame, direction)
+}
+
+func (p *FunctionCallProcessor) startCall(frame *frames.FunctionCallInProgressFrame) {
+	p.mu.Lock()
+	handler, ok := p.handlers[frame.FunctionName]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	callCtx, cancel := context.WithCancel(context.Background())
+	p.running[frame.ToolCallID] = &runningFunctionCall{
+		cancel:               cancel,
+		cancelOnInterruption: frame.CancelOnInterruption,
+	}
+	p.mu.Unlock()
+
+	var doneMu sync.Mutex
+	done := false
+	params := FunctionCallParams{
+		ToolCallID:   frame.ToolCallID,
+		FunctionName: frame.FunctionName,
+		Arguments:    frame.Arguments,
+		Progress: func(text string) {
+			doneMu.Lock()
+			defer doneMu.Unlock()
+			if done || callCtx.Err() != nil || strings.TrimSpace(text) == "" {
+				return
+			}
+			progress := frames.NewTextFrame(text)
+			progress.SkipContext = true
+			if err := p.PushFrame(progress, frames.Downstream); err != nil {
+				logger.Error("[%s] Failed to push progress for %s: %v", p.Name(), frame.FunctionName, err)
+			}
+		},
+	}
+
+	logger.Info("[%s] Running %s (id: %s)", p.Name(), frame.FunctionName, frame.ToolCallID)
+	go func() {
+		defer cancel()
+
+		result, err := handler(callCtx, params)
+
+		doneMu.Lock()
+		done = true
+		doneMu.Unlock()
+
+		p.mu.Lock()
+		delete(p.running, frame.ToolCallID)
+		p.mu.Unlock()
+
+		// A cancelled call's result is stale; the LLM has moved on
+		if callCtx.Err() != nil {
+			logger.Info("[%s] %s (id: %s) cancelled", p.Name(), frame.FunctionName, frame.ToolCallID)
+			return
+		}
+		if err != nil {
+			logger.Warn("[%s] %s (id: %s) failed: %v", p.Name(), frame.FunctionName, frame.ToolCallID, err)
+			result = map[string]interface{}{"error": err.Error()}
+		}
+		if result == nil {
+			result = "COMPLETED"
+		}
+		if pushErr := p.PushFrame(frames.NewFunctionCallResultFrame(frame.ToolCallID, frame.FunctionName, result, nil), frames.Downstream); pushErr != nil {
+			logger.Error("[%s] Failed to push result for %s: %v", p.Name(), frame.FunctionName, pushErr)
+		}
+	}()
+}
+
+// cancelCalls cancels the running calls matching match
+func (p *FunctionCallProcessor) cancelCalls(match func(id string, call *runningFunctionCall) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, call := range p.running {
+		if match(id, call) {
+			call.cancel()
+			delete(p.running, id)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package processors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestFunctionCallProcessor_StreamsProgressThenResult(t *testing.T) {
+	p := NewFunctionCallProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	release := make(chan struct{})
+	p.RegisterFunction("book_table", func(ctx context.Context, params FunctionCallParams) (interface{}, error) {
+		params.Progress("Checking availability...")
+		params.Progress("Still looking, one moment.")
+		<-release
+		return map[string]interface{}{"booked": true}, nil
+	})
+
+	call := frames.NewFunctionCallInProgressFrame("call-1", "book_table", map[string]interface{}{"party": 2}, true)
+	if err := p.HandleFrame(context.Background(), call, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+
+	// Both updates are spoken while the tool is still running
+	deadline := time.Now().Add(time.Second)
+	var progress []*frames.TextFrame
+	for time.Now().Before(deadline) && len(progress) < 2 {
+		progress = progress[:0]
+		for _, f := range capture.capturedFrames() {
+			if text, ok := f.(*frames.TextFrame); ok {
+				progress = append(progress, text)
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(progress) != 2 {
+		t.Fatalf("expected 2 progress frames, got %d", len(progress))
+	}
+	for i, want := range []string{"Checking availability...", "Still looking, one moment."} {
+		if progress[i].Text != want {
+			t.Errorf("progress %d = %q, want %q", i, progress[i].Text, want)
+		}
+		if !progress[i].SkipContext {
+			t.Errorf("progress %d should be marked SkipContext", i)
+		}
+	}
+	if capture.hasFrameOfType("FunctionCallResultFrame") {
+		t.Fatal("result pushed before the tool finished")
+	}
+
+	close(release)
+	capture.waitForFrame(t, "FunctionCallResultFrame", time.Second)
+
+	captured := capture.capturedFrames()
+	if _, ok := captured[0].(*frames.FunctionCallInProgressFrame); !ok {
+		t.Errorf("expected the in-progress frame forwarded first, got %s", captured[0].Name())
+	}
+	result := captured[len(captured)-1].(*frames.FunctionCallResultFrame)
+	if result.ToolCallID != "call-1" || result.FunctionName != "book_table" {
+		t.Errorf("unexpected result identity %s/%s", result.ToolCallID, result.FunctionName)
+	}
+	if booked, _ := result.Result.(map[string]interface{})["booked"].(bool); !booked {
+		t.Errorf("unexpected result %v", result.Result)
+	}
+}
+
+func TestFunctionCallProcessor_ReportsHandlerError(t *testing.T) {
+	p := NewFunctionCallProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+	p.RegisterFunction("lookup", func(ctx context.Context, params FunctionCallParams) (interface{}, error) {
+		return nil, errors.New("backend unavailable")
+	})
+
+	p.HandleFrame(context.Background(), frames.NewFunctionCallInProgressFrame("call-1", "lookup", nil, true), frames.Downstream)
+	capture.waitForFrame(t, "FunctionCallResultFrame", time.Second)
+
+	captured := capture.capturedFrames()
+	result := captured[len(captured)-1].(*frames.FunctionCallResultFrame)
+	if msg, _ := result.Result.(map[string]interface{})["error"].(string); msg != "backend unavailable" {
+		t.Errorf("expected the error reported as the result, got %v", result.Result)
+	}
+}
+
+func TestFunctionCallProcessor_CancelledOnInterruption(t *testing.T) {
+	p := NewFunctionCallProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	started := make(chan struct{})
+	cancelled := make(chan FunctionCallParams, 1)
+	p.RegisterFunction("lookup", func(ctx context.Context, params FunctionCallParams) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		cancelled <- params
+		return "stale", nil
+	})
+
+	ctx := context.Background()
+	p.HandleFrame(ctx, frames.NewFunctionCallInProgressFrame("call-1", "lookup", nil, true), frames.Downstream)
+	<-started
+	p.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+
+	select {
+	case params := <-cancelled:
+		// Progress after cancellation is dropped
+		params.Progress("Still looking...")
+	case <-time.After(time.Second):
+		t.Fatal("handler was not cancelled by the interruption")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	for _, f := range capture.capturedFrames() {
+		switch f.(type) {
+		case *frames.FunctionCallResultFrame, *frames.TextFrame:
+			t.Errorf("unexpected %s after cancellation", f.Name())
+		}
+	}
+}
+
+func TestFunctionCallProcessor_PassesUnregisteredCalls(t *testing.T) {
+	p := NewFunctionCallProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	p.HandleFrame(context.Background(), frames.NewFunctionCallInProgressFrame("call-1", "unknown", nil, true), frames.Downstream)
+	time.Sleep(50 * time.Millisecond)
+
+	captured := capture.capturedFrames()
+	if len(captured) != 1 || captured[0].Name() != "FunctionCallInProgressFrame" {
+		t.Errorf("expected only the forwarded call, got %d frames", len(captured))
+	}
+}
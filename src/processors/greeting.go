@@ -0,0 +1,62 @@
+package processors
+
+import (
+	"context"
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// GreetingConfig configures a GreetingProcessor
+type GreetingConfig struct {
+	// Text is spoken once when the call starts. It is a text/template
+	// rendered against the call's TemplateContext, e.g.
+	// "Thanks for calling {{.CompanyName}}. Is this {{.CallerNumber}}?"
+	Text string
+
+	// WaitForCall delays the greeting until a StartFrame with a CallID
+	// arrives (set by telephony serializers on connect), so per-call
+	// variables are available. Otherwise the first StartFrame triggers it.
+	WaitForCall bool
+}
+
+// GreetingProcessor speaks a templated greeting at the start of a call.
+// Place it before the TTS service; the greeting is pushed downstream as a
+// TextFrame right after the triggering StartFrame.
+type GreetingProcessor struct {
+	*BaseProcessor
+	config GreetingConfig
+	once   sync.Once
+}
+
+// NewGreetingProcessor creates a new GreetingProcessor
+func NewGreetingProcessor(config GreetingConfig) *GreetingProcessor {
+	p := &GreetingProcessor{config: config}
+	p.BaseProcessor = NewBaseProcessor("Greeting", p)
+	return p
+}
+
+func (p *GreetingProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	startFrame, ok := frame.(*frames.StartFrame)
+	if !ok || direction != frames.Downstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	if err := p.PushFrame(frame, direction); err != nil {
+		return err
+	}
+	if p.config.WaitForCall && startFrame.CallID == "" {
+		return nil
+	}
+
+	var err error
+	p.once.Do(func() {
+		text := p.RenderTemplate(p.config.Text)
+		if text == "" {
+			return
+		}
+		p.log.Info("Greeting: %s", text)
+		err = p.PushFrame(frames.NewTextFrame(text), frames.Downstream)
+	})
+	return err
+}
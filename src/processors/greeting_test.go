@@ -0,0 +1,74 @@
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func spokenText(captured []frames.Frame) []string {
+	var texts []string
+	for _, f := range captured {
+		if text, ok := f.(*frames.TextFrame); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return texts
+}
+
+func TestGreetingProcessorRendersCallMetadata(t *testing.T) {
+	greeting := NewGreetingProcessor(GreetingConfig{
+		Text: "Thanks for calling {{.CompanyName}}. Am I speaking with {{.CallerNumber}}?",
+	})
+	tts := &frameCaptureProcessor{}
+	greeting.Link(tts)
+
+	start := frames.NewStartFrame()
+	start.TemplateVars = map[string]string{"CompanyName": "Acme", "CallerNumber": "+15551234567"}
+	if err := greeting.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame(StartFrame) failed: %v", err)
+	}
+
+	captured := tts.capturedFrames()
+	if len(captured) != 2 || captured[0] != start {
+		t.Fatalf("Expected StartFrame followed by the greeting, got %d frames", len(captured))
+	}
+	texts := spokenText(captured)
+	if want := "Thanks for calling Acme. Am I speaking with +15551234567?"; len(texts) != 1 || texts[0] != want {
+		t.Errorf("Expected greeting %q, got %v", want, texts)
+	}
+
+	// A second StartFrame doesn't greet again
+	greeting.ProcessFrame(context.Background(), frames.NewStartFrame(), frames.Downstream)
+	if texts := spokenText(tts.capturedFrames()); len(texts) != 1 {
+		t.Errorf("Expected a single greeting, got %v", texts)
+	}
+}
+
+func TestGreetingProcessorWaitsForCall(t *testing.T) {
+	greeting := NewGreetingProcessor(GreetingConfig{
+		Text:        "Hi {{.CallerName}}, this is {{.CompanyName}}.",
+		WaitForCall: true,
+	})
+	tts := &frameCaptureProcessor{}
+	greeting.Link(tts)
+	ctx := context.Background()
+
+	pipelineStart := frames.NewStartFrame()
+	pipelineStart.TemplateVars = map[string]string{"CompanyName": "Acme"}
+	greeting.ProcessFrame(ctx, pipelineStart, frames.Downstream)
+	if texts := spokenText(tts.capturedFrames()); len(texts) != 0 {
+		t.Fatalf("Expected no greeting before the call starts, got %v", texts)
+	}
+
+	callStart := frames.NewStartFrame()
+	callStart.CallID = "CA123"
+	greeting.ProcessFrame(ctx, callStart, frames.Downstream)
+
+	// CallerName is missing and renders empty
+	texts := spokenText(tts.capturedFrames())
+	if want := "Hi , this is Acme."; len(texts) != 1 || texts[0] != want {
+		t.Errorf("Expected greeting %q, got %v", want, texts)
+	}
+}
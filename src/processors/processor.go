@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"sync"
 
@@ -26,6 +27,64 @@ type ObserverAwareProcessor interface {
 	SetObserver(observer FrameObserver)
 }
 
+// ObserverFunc is a lightweight synchronous hook invoked by ProcessFrame for
+// every frame before the processor handles it. Useful for frame-level logging,
+// test assertions and golden-trace capture without editing each processor.
+type ObserverFunc func(processor string, frame frames.Frame, direction frames.FrameDirection)
+
+// ObserverFuncAwareProcessor is implemented by processors that accept an
+// ObserverFunc hook
+type ObserverFuncAwareProcessor interface {
+	SetObserverFunc(fn ObserverFunc)
+}
+
+// InterruptionStageObserver is implemented by FrameObservers that also
+// follow interruptions through their lifecycle stages
+type InterruptionStageObserver interface {
+	OnInterruptionStage(processorName, correlationID string, stage frames.InterruptionStage)
+}
+
+// LogContextAwareProcessor is implemented by processors whose log lines can
+// carry per-call context (e.g., call and stream SIDs)
+type LogContextAwareProcessor interface {
+	SetLogContext(fields map[string]string)
+}
+
+// AudioFormat describes the audio a processor consumes or produces. Zero
+// fields mean any rate or codec.
+type AudioFormat struct {
+	SampleRate int    // Hz, e.g. 8000, 16000, 24000
+	Codec      string // "linear16", "mulaw" or "alaw"
+}
+
+func (f AudioFormat) String() string {
+	codec := f.Codec
+	if codec == "" {
+		codec = "any codec"
+	}
+	if f.SampleRate <= 0 {
+		return codec + " at any rate"
+	}
+	return fmt.Sprintf("%s %dHz", codec, f.SampleRate)
+}
+
+// AudioFormatConsumer is implemented by processors that need audio in a
+// particular format, e.g. a transport output writing 8kHz mulaw. The
+// pipeline checks it against the nearest upstream AudioFormatProducer.
+type AudioFormatConsumer interface {
+	InputFormat() AudioFormat
+}
+
+// AudioFormatProducer is implemented by processors that emit audio in a
+// known format, e.g. a TTS service producing 24kHz linear16
+type AudioFormatProducer interface {
+	OutputFormat() AudioFormat
+}
+
+// LogContextMetadataKeys are the frame metadata keys adopted as log context
+// when a StartFrame carrying them passes through a processor
+var LogContextMetadataKeys = []string{"callSid", "streamSid", "channelID"}
+
 // FrameProcessor is the interface that all processors must implement
 type FrameProcessor interface {
 	// ProcessFrame processes a single frame
@@ -59,7 +118,8 @@ type BaseProcessor struct {
 	next FrameProcessor
 	prev FrameProcessor
 
-	observer FrameObserver
+	observer     FrameObserver
+	observerFunc ObserverFunc
 
 	// Separate channels for system (high priority) and other frames
 	systemChan chan frameWithDirection
@@ -74,6 +134,7 @@ type BaseProcessor struct {
 	// Interruption support
 	allowInterruptions bool
 	turnStrategies     turns.UserTurnStrategies
+	llmPolicy          frames.InterruptionLLMPolicy
 
 	// Handler for subclasses
 	handler ProcessHandler
@@ -81,6 +142,20 @@ type BaseProcessor struct {
 	// Error handling callback
 	// Called when push_error is invoked or an unexpected exception occurs
 	onError ErrorHandler
+
+	// propagatePanics lets a panic in HandleFrame crash the process instead
+	// of being recovered into an ErrorFrame
+	propagatePanics bool
+
+	// Per-call logging: log carries the processor name as prefix, and every
+	// attached logger receives the same context fields
+	log        *logger.Logger
+	logFields  map[string]string
+	logTargets []*logger.Logger
+
+	// templateCtx accumulates template variables from every StartFrame seen
+	templateMu  sync.RWMutex
+	templateCtx TemplateContext
 }
 
 type frameWithDirection struct {
@@ -102,11 +177,14 @@ type ProcessHandler interface {
 
 // NewBaseProcessor creates a new BaseProcessor
 func NewBaseProcessor(name string, handler ProcessHandler) *BaseProcessor {
+	log := logger.WithPrefix(name)
 	return &BaseProcessor{
 		name:       name,
 		systemChan: make(chan frameWithDirection, 100),
 		dataChan:   make(chan frameWithDirection, 1000),
 		handler:    handler,
+		log:        log,
+		logTargets: []*logger.Logger{log},
 	}
 }
 
@@ -135,6 +213,116 @@ func (p *BaseProcessor) SetObserver(observer FrameObserver) {
 	p.observer = observer
 }
 
+// SetObserverFunc registers a hook called synchronously for every frame
+// before it is handled. Pass nil to remove it.
+func (p *BaseProcessor) SetObserverFunc(fn ObserverFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.observerFunc = fn
+}
+
+// Logger returns the processor's logger, which includes the log context
+func (p *BaseProcessor) Logger() *logger.Logger {
+	return p.log
+}
+
+// AttachLogger makes l receive this processor's log context. Services with
+// their own prefixed logger attach it so their lines carry the call SID too.
+func (p *BaseProcessor) AttachLogger(l *logger.Logger) {
+	if l == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logTargets = append(p.logTargets, l)
+	l.SetContext(p.logFields)
+}
+
+// SetLogContext sets the fields (e.g., callSid, streamSid) included in every
+// log line of this processor and its attached loggers, replacing any previous
+// context. Transports set this on connect; other processors adopt it from the
+// StartFrame metadata.
+func (p *BaseProcessor) SetLogContext(fields map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logFields = make(map[string]string, len(fields))
+	for key, value := range fields {
+		p.logFields[key] = value
+	}
+	for _, l := range p.logTargets {
+		l.SetContext(p.logFields)
+	}
+}
+
+// LogContext returns a copy of the current log context fields
+func (p *BaseProcessor) LogContext() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	fields := make(map[string]string, len(p.logFields))
+	for key, value := range p.logFields {
+		fields[key] = value
+	}
+	return fields
+}
+
+// adoptLogContext merges call identifiers from frame metadata into the log context
+func (p *BaseProcessor) adoptLogContext(metadata map[string]interface{}) {
+	fields := p.LogContext()
+	changed := false
+	for _, key := range LogContextMetadataKeys {
+		if value, ok := metadata[key].(string); ok && value != "" && fields[key] != value {
+			fields[key] = value
+			changed = true
+		}
+	}
+	if changed {
+		p.SetLogContext(fields)
+	}
+}
+
+// adoptTemplateContext merges a StartFrame's variables into the template
+// context. The pipeline's StartFrame arrives first and the transport's per-call
+// one later, so call values override pipeline-wide defaults.
+func (p *BaseProcessor) adoptTemplateContext(frame *frames.StartFrame) {
+	vars := templateContextFromStartFrame(frame)
+	if len(vars) == 0 {
+		return
+	}
+
+	p.templateMu.Lock()
+	defer p.templateMu.Unlock()
+	if p.templateCtx == nil {
+		p.templateCtx = TemplateContext{}
+	}
+	for key, value := range vars {
+		p.templateCtx[key] = value
+	}
+}
+
+// TemplateContext returns a copy of the variables adopted from StartFrames
+func (p *BaseProcessor) TemplateContext() TemplateContext {
+	p.templateMu.RLock()
+	defer p.templateMu.RUnlock()
+	ctx := make(TemplateContext, len(p.templateCtx))
+	for key, value := range p.templateCtx {
+		ctx[key] = value
+	}
+	return ctx
+}
+
+// RenderTemplate renders text against the processor's template context. On a
+// malformed template it logs a warning and returns text unrendered, so a typo
+// never silences the bot.
+func (p *BaseProcessor) RenderTemplate(text string) string {
+	rendered, err := p.TemplateContext().Render(text)
+	if err != nil {
+		p.log.Warn("%v", err)
+	}
+	return rendered
+}
+
 func (p *BaseProcessor) Start(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -153,8 +341,8 @@ func (p *BaseProcessor) Start(ctx context.Context) error {
 	p.wg.Add(1)
 	go p.dataFrameHandler()
 
-	logger.Info("[%s] Started", p.name)
-	logger.Debug("[%s] Processor initialized with system and data channels", p.name)
+	p.log.Info("Started")
+	p.log.Debug("Processor initialized with system and data channels")
 	return nil
 }
 
@@ -167,8 +355,8 @@ func (p *BaseProcessor) Stop() error {
 
 	p.wg.Wait()
 
-	logger.Info("[%s] Stopped", p.name)
-	logger.Debug("[%s] All goroutines terminated", p.name)
+	p.log.Info("Stopped")
+	p.log.Debug("All goroutines terminated")
 	return nil
 }
 
@@ -221,36 +409,112 @@ func (p *BaseProcessor) PushFrame(frame frames.Frame, direction frames.FrameDire
 }
 
 func (p *BaseProcessor) ProcessFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if startFrame, ok := frame.(*frames.StartFrame); ok {
+		p.adoptLogContext(frame.Metadata())
+		p.adoptTemplateContext(startFrame)
+		if startFrame.InterruptionLLMPolicy != frames.InterruptionLLMDefault {
+			p.mu.Lock()
+			p.llmPolicy = startFrame.InterruptionLLMPolicy
+			p.mu.Unlock()
+		}
+	}
+
 	p.notifyProcessFrame(frame, direction)
 
 	if p.handler != nil {
-		return p.handler.HandleFrame(ctx, frame, direction)
+		return p.handleFrame(ctx, frame, direction)
 	}
 	// Default: pass through
 	return p.PushFrame(frame, direction)
 }
 
+// SetPanicRecovery chooses what happens when HandleFrame panics. Enabled
+// (the default), the panic is logged with the processor and frame, an
+// ErrorFrame is pushed upstream and the processor keeps handling frames; the
+// frame being handled is dropped. Disabled, the panic propagates and crashes
+// the process, e.g. to get a core dump while debugging.
+func (p *BaseProcessor) SetPanicRecovery(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.propagatePanics = !enabled
+}
+
+// handleFrame calls the handler, recovering a panic unless panics propagate
+func (p *BaseProcessor) handleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) (err error) {
+	p.mu.RLock()
+	propagate := p.propagatePanics
+	p.mu.RUnlock()
+
+	if !propagate {
+		defer func() {
+			if r := recover(); r != nil {
+				err = p.recoverPanic(r, frame)
+			}
+		}()
+	}
+	return p.handler.HandleFrame(ctx, frame, direction)
+}
+
+// recoverPanic reports a panic recovered while handling frame: it is logged
+// with the stack, passed to the error handler and pushed upstream as an
+// ErrorFrame
+func (p *BaseProcessor) recoverPanic(r interface{}, frame frames.Frame) error {
+	panicErr := fmt.Errorf("panic in %s handling %s: %v", p.name, frame.Name(), r)
+	p.log.Error("Recovered from panic handling %s: %v\n%s", frame.Name(), r, debug.Stack())
+
+	p.mu.RLock()
+	handler := p.onError
+	p.mu.RUnlock()
+	if handler != nil {
+		handler(p, panicErr, "", 0)
+	}
+
+	errorFrame := frames.NewErrorFrame(panicErr)
+	errorFrame.SetMetadata("processor", p.name)
+	errorFrame.SetMetadata("frame", frame.Name())
+	errorFrame.SetMetadata("panic", true)
+	if err := p.PushFrame(errorFrame, frames.Upstream); err != nil {
+		p.log.Warn("Error pushing ErrorFrame for panic: %v", err)
+	}
+	return panicErr
+}
+
 func (p *BaseProcessor) notifyProcessFrame(frame frames.Frame, direction frames.FrameDirection) {
 	defer func() {
 		if r := recover(); r != nil {
-			logger.Error("[%s] Recovered from observer panic in process notification: %v", p.name, r)
+			p.log.Error("Recovered from observer panic in process notification: %v", r)
 		}
 	}()
 
 	p.mu.RLock()
 	observer := p.observer
+	observerFunc := p.observerFunc
 	name := p.name
 	p.mu.RUnlock()
 
+	if observerFunc != nil {
+		p.callObserverFunc(observerFunc, name, frame, direction)
+	}
 	if observer != nil {
 		observer.OnProcessFrame(name, frame, direction)
 	}
 }
 
+// callObserverFunc runs the hook, recovering on its own so a panicking hook
+// doesn't keep the FrameObserver from seeing the frame
+func (p *BaseProcessor) callObserverFunc(fn ObserverFunc, name string, frame frames.Frame, direction frames.FrameDirection) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Error("Recovered from observer func panic in process notification: %v", r)
+		}
+	}()
+	fn(name, frame, direction)
+}
+
 func (p *BaseProcessor) notifyPushFrame(frame frames.Frame, direction frames.FrameDirection) {
 	defer func() {
 		if r := recover(); r != nil {
-			logger.Error("[%s] Recovered from observer panic in push notification: %v", p.name, r)
+			p.log.Error("Recovered from observer panic in push notification: %v", r)
 		}
 	}()
 
@@ -271,12 +535,12 @@ func (p *BaseProcessor) systemFrameHandler() {
 	for {
 		select {
 		case <-p.ctx.Done():
-			logger.Debug("[%s] System frame handler shutting down", p.name)
+			p.log.Debug("System frame handler shutting down")
 			return
 		case fwd := <-p.systemChan:
-			logger.Debug("[%s] Processing system frame: %s", p.name, fwd.frame.Name())
+			p.log.Debug("Processing system frame: %s", fwd.frame.Name())
 			if err := p.ProcessFrame(p.ctx, fwd.frame, fwd.direction); err != nil {
-				logger.Error("[%s] Error processing system frame %s: %v", p.name, fwd.frame.Name(), err)
+				p.log.Error("Error processing system frame %s: %v", fwd.frame.Name(), err)
 			}
 		}
 	}
@@ -289,15 +553,15 @@ func (p *BaseProcessor) dataFrameHandler() {
 	for {
 		select {
 		case <-p.ctx.Done():
-			logger.Debug("[%s] Data frame handler shutting down", p.name)
+			p.log.Debug("Data frame handler shutting down")
 			return
 		case fwd := <-p.dataChan:
 			// Only log non-AudioFrame processing to reduce noise
 			if fwd.frame.Name() != "AudioFrame" && fwd.frame.Name() != "TTSAudioFrame" {
-				logger.Debug("[%s] Processing data frame: %s", p.name, fwd.frame.Name())
+				p.log.Debug("Processing data frame: %s", fwd.frame.Name())
 			}
 			if err := p.ProcessFrame(p.ctx, fwd.frame, fwd.direction); err != nil {
-				logger.Error("[%s] Error processing data frame %s: %v", p.name, fwd.frame.Name(), err)
+				p.log.Error("Error processing data frame %s: %v", fwd.frame.Name(), err)
 			}
 		}
 	}
@@ -313,7 +577,7 @@ func (p *BaseProcessor) HandleStartFrame(frame *frames.StartFrame) {
 	p.turnStrategies = frame.TurnStrategies
 
 	totalStrategies := len(p.turnStrategies.StartStrategies) + len(p.turnStrategies.StopStrategies) + len(p.turnStrategies.MuteStrategies)
-	logger.Debug("[%s] Interruptions configured: allowed=%v, turn_strategies=%d", p.name, p.allowInterruptions, totalStrategies)
+	p.log.Debug("Interruptions configured: allowed=%v, turn_strategies=%d", p.allowInterruptions, totalStrategies)
 }
 
 // InterruptionsAllowed returns whether interruptions are enabled
@@ -323,6 +587,18 @@ func (p *BaseProcessor) InterruptionsAllowed() bool {
 	return p.allowInterruptions
 }
 
+// InterruptionLLMPolicy returns the policy adopted from the StartFrame for
+// LLM responses in flight when an interruption arrives, InterruptionLLMAbort
+// if none was
+func (p *BaseProcessor) InterruptionLLMPolicy() frames.InterruptionLLMPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.llmPolicy == frames.InterruptionLLMDefault {
+		return frames.InterruptionLLMAbort
+	}
+	return p.llmPolicy
+}
+
 func (p *BaseProcessor) TurnStrategies() turns.UserTurnStrategies {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -336,8 +612,27 @@ func (p *BaseProcessor) InterruptionStrategies() []InterruptionStrategy {
 // PushInterruptionTaskFrame pushes an InterruptionTaskFrame upstream
 // This is a helper method for processors that need to trigger an interruption
 func (p *BaseProcessor) PushInterruptionTaskFrame() error {
-	logger.Debug("[%s] Pushing InterruptionTaskFrame upstream", p.name)
-	return p.PushFrame(frames.NewInterruptionTaskFrame(), frames.Upstream)
+	taskFrame := frames.NewInterruptionTaskFrame()
+	p.TraceInterruption(taskFrame.CorrelationID, frames.InterruptionStageDetected)
+	p.log.Debug("Pushing InterruptionTaskFrame upstream")
+	p.TraceInterruption(taskFrame.CorrelationID, frames.InterruptionStageTaskPushed)
+	return p.PushFrame(taskFrame, frames.Upstream)
+}
+
+// TraceInterruption records that this processor reached stage of the
+// interruption identified by correlationID: it is logged and reported to the
+// processor's observer when that observer follows interruption stages
+func (p *BaseProcessor) TraceInterruption(correlationID string, stage frames.InterruptionStage) {
+	p.log.Debug("Interruption %s: %s", correlationID, stage)
+
+	p.mu.RLock()
+	observer := p.observer
+	name := p.name
+	p.mu.RUnlock()
+
+	if stageObserver, ok := observer.(InterruptionStageObserver); ok {
+		stageObserver.OnInterruptionStage(name, correlationID, stage)
+	}
 }
 
 func (p *BaseProcessor) BroadcastFrame(ctx context.Context, frameConstructor func() frames.Frame) error {
@@ -385,9 +680,28 @@ func (p *BaseProcessor) BroadcastFrame(ctx context.Context, frameConstructor fun
 }
 
 func (p *BaseProcessor) BroadcastInterruption(ctx context.Context) error {
-	logger.Debug("[%s] Broadcasting paired InterruptionFrame in both directions", p.name)
+	return p.BroadcastInterruptionWithCause(ctx, frames.InterruptionCauseUnspecified)
+}
+
+// BroadcastInterruptionWithCause broadcasts paired InterruptionFrames that
+// record what triggered them, so processors can react differently to a user
+// barge-in than to other interruptions
+func (p *BaseProcessor) BroadcastInterruptionWithCause(ctx context.Context, cause frames.InterruptionCause) error {
+	// Both frames of the pair share the first one's correlation ID
+	first := frames.NewInterruptionFrameWithCause(cause)
+	p.TraceInterruption(first.CorrelationID, frames.InterruptionStageDetected)
+	p.log.Debug("Broadcasting paired InterruptionFrame in both directions (cause %q)", cause)
+	p.TraceInterruption(first.CorrelationID, frames.InterruptionStageFrameGenerated)
+
+	created := false
 	return p.BroadcastFrame(ctx, func() frames.Frame {
-		return frames.NewInterruptionFrame()
+		if !created {
+			created = true
+			return first
+		}
+		frame := frames.NewInterruptionFrameWithCause(cause)
+		frame.CorrelationID = first.CorrelationID
+		return frame
 	})
 }
 
@@ -441,7 +755,7 @@ func setBroadcastSiblingIDOnValue(value reflect.Value, siblingID string) bool {
 // HandleInterruptionFrame processes an InterruptionFrame
 // This should be called by processors when they receive an InterruptionFrame
 func (p *BaseProcessor) HandleInterruptionFrame() {
-	logger.Debug("[%s] Handling interruption - clearing queues", p.name)
+	p.log.Debug("Handling interruption - clearing queues")
 
 	// Drain the data channel to clear any pending frames
 	p.mu.Lock()
@@ -490,9 +804,9 @@ func (p *BaseProcessor) PushError(errorMsg string, err error, fatal bool) error
 
 	// Log with file/line information
 	if fatal {
-		logger.Error("[%s] FATAL ERROR at %s:%d - %v", p.name, file, line, fullErr)
+		p.log.Error("FATAL ERROR at %s:%d - %v", file, line, fullErr)
 	} else {
-		logger.Error("[%s] Error at %s:%d - %v", p.name, file, line, fullErr)
+		p.log.Error("Error at %s:%d - %v", file, line, fullErr)
 	}
 
 	// Call the on_error callback if set
@@ -527,7 +841,7 @@ func (p *BaseProcessor) PushErrorFrame(errorFrame *frames.ErrorFrame) error {
 	}
 
 	// Log with file/line information
-	logger.Error("[%s] Error at %s:%d - %v", p.name, file, line, errorFrame.Error)
+	p.log.Error("Error at %s:%d - %v", file, line, errorFrame.Error)
 
 	// Call the on_error callback if set
 	p.mu.RLock()
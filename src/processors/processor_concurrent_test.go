@@ -233,4 +233,10 @@ func TestBroadcastInterruption(t *testing.T) {
 	if downstreamFrame.GetBroadcastSiblingID() != strconv.FormatUint(upstreamFrame.ID(), 10) {
 		t.Fatalf("downstream sibling mismatch: got %s expected %d", downstreamFrame.GetBroadcastSiblingID(), upstreamFrame.ID())
 	}
+
+	upstreamID := upstreamFrame.(*frames.InterruptionFrame).CorrelationID
+	downstreamID := downstreamFrame.(*frames.InterruptionFrame).CorrelationID
+	if upstreamID == "" || upstreamID != downstreamID {
+		t.Fatalf("expected both frames to share a correlation ID, got %q and %q", upstreamID, downstreamID)
+	}
 }
@@ -0,0 +1,115 @@
+package processors
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines(substr string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var matched []string
+	for _, line := range strings.Split(b.buf.String(), "\n") {
+		if strings.Contains(line, substr) {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+func captureLogs(t *testing.T) *syncBuffer {
+	t.Helper()
+	buf := &syncBuffer{}
+	logger.SetOutput(buf)
+	t.Cleanup(func() { logger.SetOutput(os.Stdout) })
+	return buf
+}
+
+func TestBaseProcessor_AdoptsLogContextFromStartFrame(t *testing.T) {
+	logs := captureLogs(t)
+	p := NewBaseProcessor("CallLogTest", nil)
+
+	start := frames.NewStartFrame()
+	start.SetMetadata("callSid", "CA123")
+	start.SetMetadata("streamSid", "MZ456")
+	start.SetMetadata("accountSid", "AC789") // Not a log context key
+	if err := p.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame failed: %v", err)
+	}
+
+	if err := p.PushError("synthesis failed", nil, false); err != nil {
+		t.Fatalf("PushError failed: %v", err)
+	}
+
+	lines := logs.lines("synthesis failed")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one error line, got %v", lines)
+	}
+	if !strings.Contains(lines[0], "[CallLogTest] [callSid=CA123 streamSid=MZ456]") {
+		t.Errorf("Expected call prefix in log line, got %q", lines[0])
+	}
+	if strings.Contains(lines[0], "AC789") {
+		t.Errorf("Expected only log context keys to be adopted, got %q", lines[0])
+	}
+}
+
+func TestBaseProcessor_SetLogContextAppliesToAttachedLoggers(t *testing.T) {
+	logs := captureLogs(t)
+	p := NewBaseProcessor("CallLogService", nil)
+	serviceLog := logger.WithPrefix("CallLogServiceImpl")
+	p.AttachLogger(serviceLog)
+
+	p.SetLogContext(map[string]string{"conn": "ws-1"})
+	serviceLog.Error("websocket closed")
+
+	lines := logs.lines("websocket closed")
+	if len(lines) != 1 || !strings.Contains(lines[0], "[CallLogServiceImpl] [conn=ws-1]") {
+		t.Fatalf("Expected attached logger to carry the context, got %v", lines)
+	}
+
+	// Frame metadata merges into the context set by the transport
+	start := frames.NewStartFrame()
+	start.SetMetadata("callSid", "CA1")
+	if err := p.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame failed: %v", err)
+	}
+	if got := p.LogContext(); got["conn"] != "ws-1" || got["callSid"] != "CA1" {
+		t.Errorf("Expected merged context, got %v", got)
+	}
+}
+
+func TestBaseProcessor_LogContextIsPerProcessor(t *testing.T) {
+	logs := captureLogs(t)
+	a := NewBaseProcessor("CallLogA", nil)
+	b := NewBaseProcessor("CallLogB", nil)
+	a.SetLogContext(map[string]string{"callSid": "CA-A"})
+	b.SetLogContext(map[string]string{"callSid": "CA-B"})
+
+	a.Logger().Error("from a")
+	b.Logger().Error("from b")
+
+	if lines := logs.lines("from a"); len(lines) != 1 || !strings.Contains(lines[0], "callSid=CA-A") {
+		t.Errorf("Expected call A prefix, got %v", lines)
+	}
+	if lines := logs.lines("from b"); len(lines) != 1 || !strings.Contains(lines[0], "callSid=CA-B") {
+		t.Errorf("Expected call B prefix, got %v", lines)
+	}
+}
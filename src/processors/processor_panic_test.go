@@ -0,0 +1,105 @@
+package processors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// panickingHandler panics on TextFrames carrying "boom"
+type panickingHandler struct {
+	*BaseProcessor
+}
+
+func newPanickingProcessor() *panickingHandler {
+	h := &panickingHandler{}
+	h.BaseProcessor = NewBaseProcessor("Panicky", h)
+	return h
+}
+
+func (h *panickingHandler) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if text, ok := frame.(*frames.TextFrame); ok && text.Text == "boom" {
+		panic("handler exploded")
+	}
+	return h.PushFrame(frame, direction)
+}
+
+// recordingProcessor delivers every queued frame on a channel
+type recordingProcessor struct {
+	countingProcessor
+	frames chan frames.Frame
+}
+
+func newRecordingProcessor() *recordingProcessor {
+	return &recordingProcessor{frames: make(chan frames.Frame, 10)}
+}
+
+func (r *recordingProcessor) QueueFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	r.frames <- frame
+	return nil
+}
+
+func (r *recordingProcessor) next(t *testing.T) frames.Frame {
+	t.Helper()
+	select {
+	case frame := <-r.frames:
+		return frame
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a frame")
+		return nil
+	}
+}
+
+func TestBaseProcessor_RecoversHandlerPanic(t *testing.T) {
+	p := newPanickingProcessor()
+	upstream := newRecordingProcessor()
+	downstream := newRecordingProcessor()
+	p.SetPrev(upstream)
+	p.Link(downstream)
+
+	var reported error
+	p.SetOnError(func(_ FrameProcessor, err error, _ string, _ int) { reported = err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("start processor: %v", err)
+	}
+	defer p.Stop()
+
+	p.QueueFrame(frames.NewTextFrame("boom"), frames.Downstream)
+	p.QueueFrame(frames.NewTextFrame("still alive"), frames.Downstream)
+
+	errorFrame, ok := upstream.next(t).(*frames.ErrorFrame)
+	if !ok {
+		t.Fatal("Expected an ErrorFrame pushed upstream")
+	}
+	if errorFrame.Metadata()["processor"] != "Panicky" || errorFrame.Metadata()["frame"] != "TextFrame" {
+		t.Errorf("ErrorFrame metadata = %v, want processor Panicky and frame TextFrame", errorFrame.Metadata())
+	}
+	if panicked, _ := errorFrame.Metadata()["panic"].(bool); !panicked {
+		t.Error("Expected the ErrorFrame flagged as a panic")
+	}
+
+	// The processor keeps handling frames after the panic
+	if text, ok := downstream.next(t).(*frames.TextFrame); !ok || text.Text != "still alive" {
+		t.Fatalf("Expected the next frame forwarded downstream, got %v", text)
+	}
+	if reported == nil {
+		t.Error("Expected the error handler called with the panic")
+	}
+}
+
+func TestBaseProcessor_PropagatesPanicWhenRecoveryDisabled(t *testing.T) {
+	p := newPanickingProcessor()
+	p.SetPanicRecovery(false)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected the panic to propagate")
+		}
+	}()
+	p.ProcessFrame(context.Background(), frames.NewTextFrame("boom"), frames.Downstream)
+}
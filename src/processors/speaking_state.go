@@ -0,0 +1,87 @@
+package processors
+
+import (
+	"context"
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+)
+
+// UserSpeakingStateProcessor is the single source of truth for whether the
+// user is speaking. Local VAD and STT endpointing (Sarvam, OpenAI Realtime)
+// can both emit UserStartedSpeakingFrame/UserStoppedSpeakingFrame for the same
+// turn; this processor forwards the first Started of a turn and drops the
+// rest, and forwards a Stopped only once every source that started has
+// stopped, so the aggregators and interruption logic see exactly one pair per
+// turn and a turn doesn't end while STT still hears speech. Sources are told
+// apart by the frames' Source.
+//
+// Place it after the STT service and before the user aggregator:
+//
+//	transport.Input() → vad → stt → processors.NewUserSpeakingStateProcessor() → userAgg → ...
+type UserSpeakingStateProcessor struct {
+	*BaseProcessor
+
+	mu      sync.Mutex
+	sources map[string]bool // Sources that started speaking and haven't stopped
+}
+
+// NewUserSpeakingStateProcessor creates a UserSpeakingStateProcessor
+func NewUserSpeakingStateProcessor() *UserSpeakingStateProcessor {
+	p := &UserSpeakingStateProcessor{sources: make(map[string]bool)}
+	p.BaseProcessor = NewBaseProcessor("UserSpeakingState", p)
+	return p
+}
+
+// Speaking reports whether the user is currently speaking
+func (p *UserSpeakingStateProcessor) Speaking() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sources) > 0
+}
+
+func (p *UserSpeakingStateProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if direction != frames.Downstream {
+		return p.PushFrame(frame, direction)
+	}
+
+	switch f := frame.(type) {
+	case *frames.UserStartedSpeakingFrame:
+		if !p.started(f.Source) {
+			logger.Debug("[%s] Dropping duplicate UserStartedSpeakingFrame from %q", p.Name(), f.Source)
+			return nil
+		}
+	case *frames.UserStoppedSpeakingFrame:
+		if !p.stopped(f.Source) {
+			logger.Debug("[%s] Dropping UserStoppedSpeakingFrame from %q, user still speaking", p.Name(), f.Source)
+			return nil
+		}
+	case *frames.EndFrame, *frames.CancelFrame:
+		p.mu.Lock()
+		clear(p.sources)
+		p.mu.Unlock()
+	}
+	return p.PushFrame(frame, direction)
+}
+
+// started records source as speaking, reporting whether the turn began
+func (p *UserSpeakingStateProcessor) started(source string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	began := len(p.sources) == 0
+	p.sources[source] = true
+	return began
+}
+
+// stopped records source as quiet, reporting whether the turn ended: it was
+// the last source still speaking
+func (p *UserSpeakingStateProcessor) stopped(source string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.sources[source] {
+		return false
+	}
+	delete(p.sources, source)
+	return len(p.sources) == 0
+}
@@ -0,0 +1,102 @@
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func userStarted(source string) frames.Frame {
+	f := frames.NewUserStartedSpeakingFrame()
+	f.Source = source
+	return f
+}
+
+func userStopped(source string) frames.Frame {
+	f := frames.NewUserStoppedSpeakingFrame()
+	f.Source = source
+	return f
+}
+
+// TestUserSpeakingStateProcessor_DedupsVADAndSTT feeds the speaking events
+// a local VAD and STT endpointing both emit for two turns and checks the
+// aggregators would see one Started/Stopped pair per turn.
+func TestUserSpeakingStateProcessor_DedupsVADAndSTT(t *testing.T) {
+	p := NewUserSpeakingStateProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	ctx := context.Background()
+	events := []frames.Frame{
+		// Turn 1: VAD hears speech first, STT endpoints first
+		userStarted("vad"),
+		userStarted("stt"),
+		frames.NewTranscriptionFrame("book a table", true),
+		userStopped("stt"),
+		userStopped("vad"),
+		// Turn 2: STT hears speech first, VAD stops first
+		userStarted("stt"),
+		userStarted("vad"),
+		userStopped("vad"),
+		userStopped("stt"),
+	}
+	for _, f := range events {
+		if err := p.HandleFrame(ctx, f, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame(%s) failed: %v", f.Name(), err)
+		}
+	}
+
+	var got []string
+	for _, f := range capture.capturedFrames() {
+		got = append(got, f.Name())
+	}
+	want := []string{
+		"UserStartedSpeakingFrame", "TranscriptionFrame", "UserStoppedSpeakingFrame",
+		"UserStartedSpeakingFrame", "UserStoppedSpeakingFrame",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if p.Speaking() {
+		t.Error("expected the user to be quiet after the last turn")
+	}
+}
+
+func TestUserSpeakingStateProcessor_DropsStopWithoutStart(t *testing.T) {
+	p := NewUserSpeakingStateProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	p.HandleFrame(context.Background(), frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+	if n := len(capture.capturedFrames()); n != 0 {
+		t.Fatalf("expected a stray UserStoppedSpeakingFrame to be dropped, got %d frames", n)
+	}
+}
+
+// TestUserSpeakingStateProcessor_StopsWhenAllSourcesStop checks the turn
+// doesn't end while a source that started is still hearing speech.
+func TestUserSpeakingStateProcessor_StopsWhenAllSourcesStop(t *testing.T) {
+	p := NewUserSpeakingStateProcessor()
+	capture := &frameCaptureProcessor{}
+	p.Link(capture)
+
+	ctx := context.Background()
+	for _, f := range []frames.Frame{userStarted("vad"), userStarted("stt"), userStopped("vad")} {
+		p.HandleFrame(ctx, f, frames.Downstream)
+	}
+	if got := capture.capturedFrames(); len(got) != 1 || !p.Speaking() {
+		t.Fatalf("expected only the Started forwarded while STT still hears speech, got %d frames", len(got))
+	}
+
+	p.HandleFrame(ctx, userStopped("stt"), frames.Downstream)
+	got := capture.capturedFrames()
+	if len(got) != 2 || got[1].Name() != "UserStoppedSpeakingFrame" || p.Speaking() {
+		t.Fatalf("expected the Stopped forwarded once STT stopped, got %d frames", len(got))
+	}
+}
@@ -0,0 +1,54 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// TemplateContext holds the variables greeting, filler and fallback text is
+// rendered against with text/template, e.g. "Hi, calling from {{.CallerNumber}}?"
+type TemplateContext map[string]string
+
+// Render executes text as a template against the context. Missing variables
+// render as empty strings; text without actions is returned as is.
+func (c TemplateContext) Render(text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("text").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return text, fmt.Errorf("invalid template %q: %w", text, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, map[string]string(c)); err != nil {
+		return text, fmt.Errorf("failed to render template %q: %w", text, err)
+	}
+	return out.String(), nil
+}
+
+// templateContextFromStartFrame collects the template variables a StartFrame
+// carries: string metadata (callSid, streamSid, ...), the typed call fields
+// and the explicit TemplateVars, which win on conflicts
+func templateContextFromStartFrame(frame *frames.StartFrame) TemplateContext {
+	ctx := TemplateContext{}
+	for key, value := range frame.Metadata() {
+		if s, ok := value.(string); ok && s != "" {
+			ctx[key] = s
+		}
+	}
+	if frame.CallID != "" {
+		ctx["CallID"] = frame.CallID
+	}
+	if frame.Locale != "" {
+		ctx["Locale"] = frame.Locale
+	}
+	for key, value := range frame.TemplateVars {
+		ctx[key] = value
+	}
+	return ctx
+}
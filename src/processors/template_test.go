@@ -0,0 +1,66 @@
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestTemplateContextRender(t *testing.T) {
+	ctx := TemplateContext{"CallerNumber": "+15551234567", "CompanyName": "Acme"}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"plain text", "Hello there", "Hello there", false},
+		{"variables", "Thanks for calling {{.CompanyName}}. Is this {{.CallerNumber}}?", "Thanks for calling Acme. Is this +15551234567?", false},
+		{"missing variable", "Hi {{.CallerName}}!", "Hi !", false},
+		{"conditional default", "Hi{{with .CallerName}} {{.}}{{end}}!", "Hi!", false},
+		{"malformed", "Hi {{.CallerName", "Hi {{.CallerName", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ctx.Render(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, _ := TemplateContext(nil).Render("Hi {{.CallerName}}"); got != "Hi " {
+		t.Errorf("Render() on nil context = %q, want %q", got, "Hi ")
+	}
+}
+
+func TestBaseProcessorAdoptsTemplateContext(t *testing.T) {
+	p := NewPassthroughProcessor("templated", false)
+	ctx := context.Background()
+
+	// Pipeline-wide defaults arrive first
+	pipelineStart := frames.NewStartFrame()
+	pipelineStart.TemplateVars = map[string]string{"CompanyName": "Acme", "CallerName": "there"}
+	p.ProcessFrame(ctx, pipelineStart, frames.Downstream)
+
+	// The transport's per-call StartFrame adds and overrides
+	callStart := frames.NewStartFrame()
+	callStart.CallID = "CA123"
+	callStart.SetMetadata("streamSid", "MZ456")
+	callStart.TemplateVars = map[string]string{"CallerName": "Sam"}
+	p.ProcessFrame(ctx, callStart, frames.Downstream)
+
+	got := p.RenderTemplate("{{.CompanyName}}/{{.CallerName}}/{{.CallID}}/{{.streamSid}}")
+	if want := "Acme/Sam/CA123/MZ456"; got != want {
+		t.Errorf("RenderTemplate() = %q, want %q", got, want)
+	}
+
+	if got := p.RenderTemplate("Hi {{.CallerName"); got != "Hi {{.CallerName" {
+		t.Errorf("Expected malformed template to be returned as is, got %q", got)
+	}
+}
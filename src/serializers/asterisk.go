@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 )
@@ -13,8 +14,10 @@ import (
 // Codec is auto-detected from MEDIA_START message for true passthrough
 type AsteriskFrameSerializer struct {
 	channelID  string
-	codec      string // Auto-detected from MEDIA_START, or fallback: "mulaw", "alaw", etc.
-	sampleRate int    // Auto-detected from codec, or fallback: 8000
+	formatMu   sync.RWMutex // Guards codec/sampleRate, read by the output side
+	codec      string       // Auto-detected from MEDIA_START, or fallback: "mulaw", "alaw", etc.
+	sampleRate int          // Auto-detected from codec, or fallback: 8000
+	frameSize  int          // optimal_frame_size from MEDIA_START, or fallback: 0 (transport default)
 }
 
 // Asterisk control message structure
@@ -33,6 +36,25 @@ type AsteriskSerializerConfig struct {
 	ChannelID  string
 	Codec      string // Optional fallback codec if MEDIA_START not received: "mulaw"/"ulaw", "alaw" (default: "alaw")
 	SampleRate int    // Optional fallback sample rate (default: 8000)
+
+	// OptimalFrameSize is the outbound audio chunk size in bytes used until
+	// MEDIA_START reports one (e.g. 240 for 30ms of 8kHz G.711). Default: 0,
+	// the transport's per-codec default (20ms).
+	OptimalFrameSize int
+}
+
+// asteriskFormatRates maps the Asterisk formats passed through without
+// transcoding to their sample rate
+var asteriskFormatRates = map[string]int{
+	"ulaw":   8000,
+	"alaw":   8000,
+	"slin":   8000,
+	"slin12": 12000,
+	"slin16": 16000,
+	"slin24": 24000,
+	"slin32": 32000,
+	"slin44": 44100,
+	"slin48": 48000,
 }
 
 // NewAsteriskFrameSerializer creates a new Asterisk serializer with codec auto-detection
@@ -52,6 +74,7 @@ func NewAsteriskFrameSerializer(config AsteriskSerializerConfig) *AsteriskFrameS
 		channelID:  config.ChannelID,
 		codec:      normalizeAsteriskCodec(codec),
 		sampleRate: sampleRate,
+		frameSize:  config.OptimalFrameSize,
 	}
 }
 
@@ -62,11 +85,10 @@ func normalizeAsteriskCodec(codec string) string {
 		return "mulaw"
 	case "PCMA":
 		return "alaw"
-	case "slin":
-		return "linear16"
-	case "slin16":
-		return "linear16"
 	default:
+		if strings.HasPrefix(codec, "slin") {
+			return "linear16"
+		}
 		return codec
 	}
 }
@@ -116,6 +138,12 @@ func (s *AsteriskFrameSerializer) Type() SerializerType {
 	return SerializerTypeBinary
 }
 
+// PreferredMessageType keeps the WebSocket frame type: Asterisk sends audio
+// in BINARY frames and control messages in TEXT frames
+func (s *AsteriskFrameSerializer) PreferredMessageType() MessageType {
+	return MessageTypeAuto
+}
+
 // Setup initializes the serializer
 func (s *AsteriskFrameSerializer) Setup(frame frames.Frame) error {
 	// Can extract channelID from StartFrame metadata if needed
@@ -168,6 +196,14 @@ func (s *AsteriskFrameSerializer) SerializePlaybackDoneAck(correlationID string)
 	return fmt.Sprintf("MARK_MEDIA correlation_id:%s", correlationID), nil
 }
 
+// SerializeTransfer flushes queued bot audio. chan_websocket has no transfer
+// command; the ARI or dialplan application controlling the channel performs
+// the transfer from the transport's TransferHandler (see
+// WebSocketTransport.OnTransfer), which gets the channel ID.
+func (s *AsteriskFrameSerializer) SerializeTransfer(destination string) (interface{}, error) {
+	return "FLUSH_MEDIA", nil
+}
+
 // Deserialize converts Asterisk data to frames
 // TEXT frames: Control messages (MEDIA_START, HANGUP, etc.)
 // BINARY frames: Raw audio in native codec (passthrough to STT)
@@ -184,21 +220,27 @@ func (s *AsteriskFrameSerializer) Deserialize(data interface{}) (frames.Frame, e
 		switch msg.Type {
 		case "MEDIA_START":
 			// Extract codec and channel from MEDIA_START message
-			if msg.Format != "" {
+			s.formatMu.Lock()
+			// MEDIA_START reports the format the channel negotiated. Asterisk
+			// has no way to be told of a different choice, so a list of
+			// formats is not one we can pick from and the fallback is kept
+			if strings.Contains(msg.Format, ",") {
+				fmt.Printf("[AsteriskSerializer] ⚠️ MEDIA_START reported several formats (%s), keeping codec=%s\n", msg.Format, s.codec)
+			} else if msg.Format != "" {
 				s.codec = normalizeAsteriskCodec(msg.Format)
+				if rate, ok := asteriskFormatRates[msg.Format]; ok {
+					s.sampleRate = rate
+				}
 			}
 			if msg.Channel != "" {
 				s.channelID = msg.Channel
 			}
-			// Update sample rate based on codec
-			switch s.codec {
-			case "mulaw", "alaw":
-				s.sampleRate = 8000
-			case "linear16":
-				s.sampleRate = 16000
+			if msg.OptimalFrameSize > 0 {
+				s.frameSize = msg.OptimalFrameSize
 			}
+			s.formatMu.Unlock()
 
-			fmt.Printf("[AsteriskSerializer] ✅ MEDIA_START: codec=%s, channel=%s, rate=%d\n", s.codec, s.channelID, s.sampleRate)
+			fmt.Printf("[AsteriskSerializer] ✅ MEDIA_START: codec=%s, channel=%s, rate=%d, frame_size=%d\n", s.codec, s.channelID, s.sampleRate, msg.OptimalFrameSize)
 
 			// DON'T create a new StartFrame - it would overwrite interruption settings from pipeline
 			// MEDIA_START just updates our internal state for codec detection
@@ -213,13 +255,13 @@ func (s *AsteriskFrameSerializer) Deserialize(data interface{}) (frames.Frame, e
 
 		case "MEDIA_XON":
 			fmt.Printf("[AsteriskSerializer] ✅ MEDIA_XON: Resume sending (buffer below threshold)\n")
-			// Flow control: Resume sending
-			return nil, nil
+			// Flow control: the output processor resumes sending
+			return frames.NewFlowControlResumeFrame(), nil
 
 		case "MEDIA_XOFF":
 			fmt.Printf("[AsteriskSerializer] ⚠️  MEDIA_XOFF: Pause sending (buffer full ~900 frames)\n")
-			// Flow control: Pause sending
-			return nil, nil
+			// Flow control: the output processor pauses sending and buffers
+			return frames.NewFlowControlPauseFrame(), nil
 
 		case "MEDIA_BUFFERING_COMPLETED":
 			fmt.Printf("[AsteriskSerializer] ✅ MEDIA_BUFFERING_COMPLETED\n")
@@ -279,10 +321,36 @@ func (s *AsteriskFrameSerializer) GetChannelID() string {
 
 // GetCodec returns the configured codec
 func (s *AsteriskFrameSerializer) GetCodec() string {
+	s.formatMu.RLock()
+	defer s.formatMu.RUnlock()
 	return s.codec
 }
 
 // GetSampleRate returns the configured sample rate
 func (s *AsteriskFrameSerializer) GetSampleRate() int {
+	s.formatMu.RLock()
+	defer s.formatMu.RUnlock()
 	return s.sampleRate
 }
+
+// SupportsCoalescedAudio reports true: Asterisk plays binary media of any
+// length, so several ptimes can go in one message
+func (s *AsteriskFrameSerializer) SupportsCoalescedAudio() bool {
+	return true
+}
+
+// OutputAudioFormat returns the codec and sample rate from MEDIA_START, or
+// the configured fallback
+func (s *AsteriskFrameSerializer) OutputAudioFormat() (string, int) {
+	s.formatMu.RLock()
+	defer s.formatMu.RUnlock()
+	return s.codec, s.sampleRate
+}
+
+// OptimalFrameSize returns the chunk size requested by MEDIA_START, or the
+// configured fallback
+func (s *AsteriskFrameSerializer) OptimalFrameSize() int {
+	s.formatMu.RLock()
+	defer s.formatMu.RUnlock()
+	return s.frameSize
+}
@@ -0,0 +1,31 @@
+package serializers
+
+import "testing"
+
+func TestAsteriskMediaStartUsesReportedFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		reported string
+		codec    string
+		rate     int
+	}{
+		{"ulaw", "ulaw", "mulaw", 8000},
+		{"alaw", "alaw", "alaw", 8000},
+		{"slin16", "slin16", "linear16", 16000},
+		{"slin", "slin", "linear16", 8000},
+		// Asterisk can't be told which of several formats was picked
+		{"several formats keep the fallback", "slin16,ulaw", "alaw", 8000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serializer := NewAsteriskFrameSerializer(AsteriskSerializerConfig{})
+			if _, err := serializer.Deserialize("MEDIA_START connection_id:abc channel:PJSIP/100 format:" + tt.reported); err != nil {
+				t.Fatalf("Deserialize(MEDIA_START) error: %v", err)
+			}
+			codec, rate := serializer.OutputAudioFormat()
+			if codec != tt.codec || rate != tt.rate {
+				t.Errorf("reported %s: got %s/%d, want %s/%d", tt.reported, codec, rate, tt.codec, tt.rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package serializers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestTwilioSerializeInterruptionEmitsClear(t *testing.T) {
+	serializer := NewTwilioFrameSerializer("", "")
+
+	if _, err := serializer.Deserialize(`{"event":"start","start":{"streamSid":"MZ123","callSid":"CA456"}}`); err != nil {
+		t.Fatalf("Deserialize(start) error = %v", err)
+	}
+
+	data, err := serializer.Serialize(frames.NewInterruptionFrame())
+	if err != nil {
+		t.Fatalf("Serialize(InterruptionFrame) error = %v", err)
+	}
+
+	msg, ok := data.(string)
+	if !ok {
+		t.Fatalf("Serialize(InterruptionFrame) type = %T, want string", data)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(msg), &decoded); err != nil {
+		t.Fatalf("clear message is not valid JSON: %v", err)
+	}
+	want := map[string]interface{}{"event": "clear", "streamSid": "MZ123"}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("clear message = %v, want %v", decoded, want)
+	}
+}
+
+func TestTwilioSetupReadsStreamSidFromStartFrame(t *testing.T) {
+	serializer := NewTwilioFrameSerializer("", "")
+
+	startFrame := frames.NewStartFrame()
+	startFrame.SetMetadata("streamSid", "MZ789")
+	startFrame.SetMetadata("callSid", "CA000")
+	if err := serializer.Setup(startFrame); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	data, err := serializer.Serialize(frames.NewInterruptionFrame())
+	if err != nil {
+		t.Fatalf("Serialize(InterruptionFrame) error = %v", err)
+	}
+	if got, want := data, `{"event":"clear","streamSid":"MZ789"}`; got != want {
+		t.Fatalf("clear message = %v, want %s", got, want)
+	}
+}
+
+func TestTwilioDeserializeStartSetsMediaFormat(t *testing.T) {
+	serializer := NewTwilioFrameSerializer("", "")
+
+	frame, err := serializer.Deserialize(`{"event":"start","start":{"streamSid":"MZ1","callSid":"CA2","mediaFormat":{"encoding":"audio/x-mulaw","sampleRate":8000,"channels":1}}}`)
+	if err != nil {
+		t.Fatalf("Deserialize(start) error = %v", err)
+	}
+	startFrame, ok := frame.(*frames.StartFrame)
+	if !ok {
+		t.Fatalf("Deserialize(start) frame = %T, want *frames.StartFrame", frame)
+	}
+	if startFrame.Codec != "mulaw" || startFrame.SampleRate != 8000 || startFrame.Channels != 1 {
+		t.Errorf("unexpected media format: %s %dHz x%d", startFrame.Codec, startFrame.SampleRate, startFrame.Channels)
+	}
+	if startFrame.CallID != "CA2" {
+		t.Errorf("CallID = %q, want CA2", startFrame.CallID)
+	}
+	if startFrame.Metadata()["callSid"] != "CA2" {
+		t.Error("expected callSid metadata to be kept")
+	}
+}
+
+func TestAsteriskSerializeInterruptionEmitsFlushCommands(t *testing.T) {
+	serializer := NewAsteriskFrameSerializer(AsteriskSerializerConfig{ChannelID: "chan-1"})
+
+	data, err := serializer.Serialize(frames.NewInterruptionFrame())
+	if err != nil {
+		t.Fatalf("Serialize(InterruptionFrame) error = %v", err)
+	}
+
+	commands, ok := data.([]string)
+	if !ok {
+		t.Fatalf("Serialize(InterruptionFrame) type = %T, want []string", data)
+	}
+	if want := []string{"REPORT_QUEUE_DRAINED", "FLUSH_MEDIA"}; !reflect.DeepEqual(commands, want) {
+		t.Fatalf("interruption commands = %v, want %v", commands, want)
+	}
+}
+
+func TestJSONSerializeInterruption(t *testing.T) {
+	serializer := NewJSONFrameSerializer(JSONSerializerConfig{})
+
+	data, err := serializer.Serialize(frames.NewInterruptionFrame())
+	if err != nil {
+		t.Fatalf("Serialize(InterruptionFrame) error = %v", err)
+	}
+	if got, want := data, `{"type":"interruption"}`; got != want {
+		t.Fatalf("interruption message = %v, want %s", got, want)
+	}
+}
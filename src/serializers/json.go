@@ -0,0 +1,149 @@
+package serializers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// JSONFrameSerializer implements a simple JSON protocol for browser clients.
+// Every message is a TEXT frame with a "type" field; audio is base64-encoded
+// linear16 PCM.
+type JSONFrameSerializer struct {
+	sampleRate int
+}
+
+// JSONSerializerConfig holds configuration for the JSON serializer
+type JSONSerializerConfig struct {
+	SampleRate int // Sample rate of inbound client audio (default: 16000)
+}
+
+// JSON message structure
+type jsonMessage struct {
+	Type       string `json:"type"`
+	Audio      string `json:"audio,omitempty"` // base64-encoded linear16 PCM
+	SampleRate int    `json:"sample_rate,omitempty"`
+}
+
+// Audio level message; levels are always sent, silence included
+type jsonAudioLevelMessage struct {
+	Type   string  `json:"type"`
+	RMS    float32 `json:"rms"`
+	Peak   float32 `json:"peak"`
+	Source string  `json:"source,omitempty"`
+}
+
+// NewJSONFrameSerializer creates a new JSON serializer
+func NewJSONFrameSerializer(config JSONSerializerConfig) *JSONFrameSerializer {
+	sampleRate := config.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 16000
+	}
+	return &JSONFrameSerializer{sampleRate: sampleRate}
+}
+
+// Type returns the serialization type (JSON/text)
+func (s *JSONFrameSerializer) Type() SerializerType {
+	return SerializerTypeText
+}
+
+// Setup initializes the serializer (no-op for JSON)
+func (s *JSONFrameSerializer) Setup(frame frames.Frame) error {
+	return nil
+}
+
+// Serialize converts a frame to a JSON message
+func (s *JSONFrameSerializer) Serialize(frame frames.Frame) (interface{}, error) {
+	var msg interface{}
+	var msgType string
+
+	switch f := frame.(type) {
+	case *frames.TTSAudioFrame:
+		msgType = "audio"
+		msg = jsonMessage{
+			Type:       msgType,
+			Audio:      base64.StdEncoding.EncodeToString(f.Data),
+			SampleRate: f.SampleRate,
+		}
+
+	case *frames.InterruptionFrame:
+		// Tell the client to drop any audio it has queued
+		msgType = "interruption"
+		msg = jsonMessage{Type: msgType}
+
+	case *frames.AudioLevelFrame:
+		msgType = "audio_level"
+		msg = jsonAudioLevelMessage{
+			Type:   msgType,
+			RMS:    f.RMS,
+			Peak:   f.Peak,
+			Source: f.Source,
+		}
+
+	default:
+		// Ignore other frame types
+		return nil, nil
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON %s message: %w", msgType, err)
+	}
+	return string(data), nil
+}
+
+// Deserialize converts a JSON client message to a frame
+func (s *JSONFrameSerializer) Deserialize(data interface{}) (frames.Frame, error) {
+	jsonData, ok := data.(string)
+	if !ok {
+		if bytes, ok := data.([]byte); ok {
+			jsonData = string(bytes)
+		} else {
+			return nil, fmt.Errorf("expected string or []byte, got %T", data)
+		}
+	}
+
+	var msg jsonMessage
+	if err := json.Unmarshal([]byte(jsonData), &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON message: %w", err)
+	}
+
+	switch msg.Type {
+	case "start":
+		startFrame := frames.NewStartFrame()
+		startFrame.Codec = "linear16"
+		startFrame.SampleRate = s.sampleRate
+		if msg.SampleRate > 0 {
+			startFrame.SampleRate = msg.SampleRate
+		}
+		startFrame.Channels = 1
+		return startFrame, nil
+
+	case "audio":
+		audioData, err := base64.StdEncoding.DecodeString(msg.Audio)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode audio payload: %w", err)
+		}
+		sampleRate := msg.SampleRate
+		if sampleRate == 0 {
+			sampleRate = s.sampleRate
+		}
+		audioFrame := frames.NewAudioFrame(audioData, sampleRate, 1)
+		audioFrame.SetMetadata("codec", "linear16")
+		return audioFrame, nil
+
+	case "stop":
+		return frames.NewEndFrame(), nil
+
+	default:
+		// Unknown message type, ignore
+		return nil, nil
+	}
+}
+
+// Cleanup releases any resources (none for JSON serializer)
+func (s *JSONFrameSerializer) Cleanup() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package serializers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestJSONSerializeAudioLevel(t *testing.T) {
+	serializer := NewJSONFrameSerializer(JSONSerializerConfig{})
+
+	data, err := serializer.Serialize(frames.NewAudioLevelFrame(0.25, 0.5, "user"))
+	if err != nil {
+		t.Fatalf("Serialize(AudioLevelFrame) error = %v", err)
+	}
+	msg, ok := data.(string)
+	if !ok {
+		t.Fatalf("Serialize(AudioLevelFrame) type = %T, want string", data)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(msg), &decoded); err != nil {
+		t.Fatalf("audio level message is not valid JSON: %v", err)
+	}
+	if decoded["type"] != "audio_level" || decoded["rms"] != 0.25 || decoded["peak"] != 0.5 || decoded["source"] != "user" {
+		t.Errorf("unexpected audio level message %s", msg)
+	}
+}
+
+func TestJSONDeserializeAudio(t *testing.T) {
+	serializer := NewJSONFrameSerializer(JSONSerializerConfig{})
+
+	frame, err := serializer.Deserialize(`{"type":"audio","audio":"AAEC"}`)
+	if err != nil {
+		t.Fatalf("Deserialize(audio) error = %v", err)
+	}
+	audioFrame, ok := frame.(*frames.AudioFrame)
+	if !ok {
+		t.Fatalf("Deserialize(audio) frame = %T, want *frames.AudioFrame", frame)
+	}
+	if len(audioFrame.Data) != 3 || audioFrame.SampleRate != 16000 {
+		t.Errorf("unexpected audio frame: %d bytes @ %dHz", len(audioFrame.Data), audioFrame.SampleRate)
+	}
+	if codec, _ := audioFrame.Metadata()["codec"].(string); codec != "linear16" {
+		t.Errorf("expected linear16 codec metadata, got %q", codec)
+	}
+}
+
+func TestJSONDeserializeStartSetsMediaFormat(t *testing.T) {
+	serializer := NewJSONFrameSerializer(JSONSerializerConfig{SampleRate: 24000})
+
+	frame, err := serializer.Deserialize(`{"type":"start"}`)
+	if err != nil {
+		t.Fatalf("Deserialize(start) error = %v", err)
+	}
+	startFrame, ok := frame.(*frames.StartFrame)
+	if !ok {
+		t.Fatalf("Deserialize(start) frame = %T, want *frames.StartFrame", frame)
+	}
+	if startFrame.Codec != "linear16" || startFrame.SampleRate != 24000 || startFrame.Channels != 1 {
+		t.Errorf("unexpected media format: %s %dHz x%d", startFrame.Codec, startFrame.SampleRate, startFrame.Channels)
+	}
+
+	frame, _ = serializer.Deserialize(`{"type":"start","sample_rate":48000}`)
+	if got := frame.(*frames.StartFrame).SampleRate; got != 48000 {
+		t.Errorf("expected client sample rate 48000, got %d", got)
+	}
+}
+
+func TestJSONSerializeSilentAudioLevel(t *testing.T) {
+	serializer := NewJSONFrameSerializer(JSONSerializerConfig{})
+
+	data, err := serializer.Serialize(frames.NewAudioLevelFrame(0, 0, "user"))
+	if err != nil {
+		t.Fatalf("Serialize(AudioLevelFrame) error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(data.(string)), &decoded); err != nil {
+		t.Fatalf("audio level message is not valid JSON: %v", err)
+	}
+	// Silence must still carry its levels, or clients can't tell it apart
+	// from a missing field
+	if decoded["rms"] != 0.0 || decoded["peak"] != 0.0 {
+		t.Errorf("expected zero levels in silent audio level message, got %s", data)
+	}
+}
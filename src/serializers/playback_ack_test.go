@@ -95,3 +95,23 @@ func TestAsteriskDeserializeMediaMarkProcessedReturnsPlaybackCompleteFrame(t *te
 		t.Fatalf("Deserialize(MEDIA_MARK_PROCESSED) correlation_id = %v, want playback-789", got)
 	}
 }
+
+func TestAsteriskDeserializeFlowControl(t *testing.T) {
+	serializer := NewAsteriskFrameSerializer(AsteriskSerializerConfig{})
+
+	frame, err := serializer.Deserialize("MEDIA_XOFF")
+	if err != nil {
+		t.Fatalf("Deserialize(MEDIA_XOFF) error = %v", err)
+	}
+	if _, ok := frame.(*frames.FlowControlPauseFrame); !ok {
+		t.Fatalf("Deserialize(MEDIA_XOFF) frame = %T, want *frames.FlowControlPauseFrame", frame)
+	}
+
+	frame, err = serializer.Deserialize("MEDIA_XON")
+	if err != nil {
+		t.Fatalf("Deserialize(MEDIA_XON) error = %v", err)
+	}
+	if _, ok := frame.(*frames.FlowControlResumeFrame); !ok {
+		t.Fatalf("Deserialize(MEDIA_XON) frame = %T, want *frames.FlowControlResumeFrame", frame)
+	}
+}
@@ -12,6 +12,30 @@ const (
 	SerializerTypeText   SerializerType = "text"
 )
 
+// MessageType says how the transport passes inbound WebSocket messages to
+// Deserialize
+type MessageType int
+
+const (
+	// MessageTypeAuto passes BINARY frames as []byte and TEXT frames as
+	// string, for protocols that mix the two (e.g. Asterisk: binary audio,
+	// text control). This is the default.
+	MessageTypeAuto MessageType = iota
+	// MessageTypeText passes every message as a string, for text protocols
+	// whose clients may still send them in BINARY frames and whose
+	// Deserialize only accepts strings
+	MessageTypeText
+	// MessageTypeBinary passes every message as []byte
+	MessageTypeBinary
+)
+
+// MessageTypeSerializer is implemented by serializers that declare how
+// inbound messages should be interpreted. Serializers without it get
+// MessageTypeAuto.
+type MessageTypeSerializer interface {
+	PreferredMessageType() MessageType
+}
+
 // FrameSerializer is the interface for serializing and deserializing frames
 // to/from protocol-specific formats (e.g., Twilio, Asterisk, Telnyx)
 type FrameSerializer interface {
@@ -22,7 +46,10 @@ type FrameSerializer interface {
 	Setup(frame frames.Frame) error
 
 	// Serialize converts a frame to its serialized representation
-	// Returns the serialized data (string or bytes) and any error
+	// Returns the serialized data (string or bytes) and any error. A frame
+	// that needs several messages may return a batch: []string, [][]byte or
+	// []interface{} mixing the two (e.g. a control JSON then binary audio),
+	// sent in order.
 	Serialize(frame frames.Frame) (interface{}, error)
 
 	// Deserialize converts serialized data back to a frame
@@ -33,6 +60,35 @@ type FrameSerializer interface {
 	Cleanup() error
 }
 
+// AudioFormatSerializer is implemented by serializers whose connection
+// carries audio in a fixed codec and sample rate. The transport converts
+// outbound audio in any other format before serializing it.
+type AudioFormatSerializer interface {
+	// OutputAudioFormat returns the connection's negotiated codec ("mulaw",
+	// "alaw" or "linear16") and sample rate
+	OutputAudioFormat() (codec string, sampleRate int)
+}
+
+// CoalescingAudioSerializer is implemented by serializers whose protocol
+// accepts several ptimes of audio in one message, letting the transport
+// coalesce paced chunks into fewer WebSocket writes (see
+// WebSocketConfig.FramesPerWrite).
+type CoalescingAudioSerializer interface {
+	// SupportsCoalescedAudio reports whether one audio message may carry
+	// more than one chunk
+	SupportsCoalescedAudio() bool
+}
+
+// FrameSizeSerializer is implemented by serializers whose peer asks for
+// audio in chunks of a particular size (e.g. Asterisk's optimal_frame_size).
+// The transport chunks outbound audio to that size instead of its per-codec
+// default.
+type FrameSizeSerializer interface {
+	// OptimalFrameSize returns the preferred audio chunk size in bytes, or 0
+	// to use the transport's default
+	OptimalFrameSize() int
+}
+
 // PlaybackAckSerializer is implemented by serializers that support client-side
 // playback acknowledgement. When the server signals playback-done (e.g., a Twilio
 // mark message), the client echoes it back, allowing the transport to emit
@@ -42,3 +98,13 @@ type PlaybackAckSerializer interface {
 	// request a playback-done acknowledgement (e.g., a Twilio mark event).
 	SerializePlaybackDoneAck(correlationID string) (interface{}, error)
 }
+
+// TransferSerializer is implemented by serializers whose protocol needs
+// in-band messages before a call transfer, e.g. to drop bot audio the client
+// has buffered. The transport sends them when it receives a TransferFrame,
+// then hands the transfer itself to its TransferHandler.
+type TransferSerializer interface {
+	// SerializeTransfer returns the message(s) preparing the connection for a
+	// transfer to destination
+	SerializeTransfer(destination string) (interface{}, error)
+}
@@ -0,0 +1,152 @@
+package serializers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// TextFrameSerializer implements a text-only JSON protocol for chat widgets,
+// so the same LLM/aggregator pipeline can serve voice and chat. Audio is never
+// sent; there is no STT or TTS in the loop.
+//
+// Client → server:
+//
+//	{"type":"start"}                     starts the session
+//	{"type":"message","text":"Hi there"} a user turn (final TranscriptionFrame)
+//	{"type":"stop"}                      ends the session
+//
+// Server → client:
+//
+//	{"type":"text","text":"Hel"}                          streamed reply chunk
+//	{"type":"message","role":"assistant","text":"Hello"} complete assistant turn
+//	{"type":"interruption"}                               reply was cut short
+type TextFrameSerializer struct {
+	mu    sync.Mutex
+	reply strings.Builder // Assistant text of the turn in progress
+}
+
+// textMessage is the wire format of the text protocol
+type textMessage struct {
+	Type string `json:"type"`
+	Role string `json:"role,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// NewTextFrameSerializer creates a new text chat serializer
+func NewTextFrameSerializer() *TextFrameSerializer {
+	return &TextFrameSerializer{}
+}
+
+// Type returns the serialization type (JSON/text)
+func (s *TextFrameSerializer) Type() SerializerType {
+	return SerializerTypeText
+}
+
+// Setup initializes the serializer (no-op for text)
+func (s *TextFrameSerializer) Setup(frame frames.Frame) error {
+	return nil
+}
+
+// Serialize converts LLM output to chat messages; audio and other frames are ignored
+func (s *TextFrameSerializer) Serialize(frame frames.Frame) (interface{}, error) {
+	var msg textMessage
+
+	switch f := frame.(type) {
+	case *frames.LLMFullResponseStartFrame:
+		s.mu.Lock()
+		s.reply.Reset()
+		s.mu.Unlock()
+		return nil, nil
+
+	case *frames.TextFrame:
+		if f.Text == "" {
+			return nil, nil
+		}
+		s.appendReply(f.Text)
+		msg = textMessage{Type: "text", Text: f.Text}
+
+	case *frames.LLMTextFrame:
+		if f.Text == "" {
+			return nil, nil
+		}
+		s.appendReply(f.Text)
+		msg = textMessage{Type: "text", Text: f.Text}
+
+	case *frames.LLMFullResponseEndFrame:
+		s.mu.Lock()
+		text := strings.TrimSpace(s.reply.String())
+		s.reply.Reset()
+		s.mu.Unlock()
+		if text == "" {
+			return nil, nil
+		}
+		msg = textMessage{Type: "message", Role: "assistant", Text: text}
+
+	case *frames.InterruptionFrame:
+		s.mu.Lock()
+		s.reply.Reset()
+		s.mu.Unlock()
+		msg = textMessage{Type: "interruption"}
+
+	default:
+		// Ignore other frame types, including audio
+		return nil, nil
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal text %s message: %w", msg.Type, err)
+	}
+	return string(data), nil
+}
+
+func (s *TextFrameSerializer) appendReply(text string) {
+	s.mu.Lock()
+	s.reply.WriteString(text)
+	s.mu.Unlock()
+}
+
+// Deserialize converts a chat client message to a frame
+func (s *TextFrameSerializer) Deserialize(data interface{}) (frames.Frame, error) {
+	jsonData, ok := data.(string)
+	if !ok {
+		if bytes, ok := data.([]byte); ok {
+			jsonData = string(bytes)
+		} else {
+			return nil, fmt.Errorf("expected string or []byte, got %T", data)
+		}
+	}
+
+	var msg textMessage
+	if err := json.Unmarshal([]byte(jsonData), &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal text message: %w", err)
+	}
+
+	switch msg.Type {
+	case "start":
+		return frames.NewStartFrame(), nil
+
+	case "message":
+		text := strings.TrimSpace(msg.Text)
+		if text == "" {
+			return nil, nil
+		}
+		return frames.NewTranscriptionFrame(text, true), nil
+
+	case "stop":
+		return frames.NewEndFrame(), nil
+
+	default:
+		// Unknown message type, ignore
+		return nil, nil
+	}
+}
+
+// Cleanup releases any resources (none for text serializer)
+func (s *TextFrameSerializer) Cleanup() error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package serializers
+
+import (
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestTextDeserializeMessageReturnsFinalTranscription(t *testing.T) {
+	serializer := NewTextFrameSerializer()
+
+	frame, err := serializer.Deserialize(`{"type":"message","text":"  What are your hours?  "}`)
+	if err != nil {
+		t.Fatalf("Deserialize(message) error = %v", err)
+	}
+	transcription, ok := frame.(*frames.TranscriptionFrame)
+	if !ok {
+		t.Fatalf("Deserialize(message) frame = %T, want *frames.TranscriptionFrame", frame)
+	}
+	if !transcription.IsFinal || transcription.Text != "What are your hours?" {
+		t.Errorf("Deserialize(message) = %q (final=%v), want final %q", transcription.Text, transcription.IsFinal, "What are your hours?")
+	}
+
+	if frame, _ := serializer.Deserialize(`{"type":"message","text":"   "}`); frame != nil {
+		t.Errorf("Deserialize(blank message) frame = %T, want nil", frame)
+	}
+}
+
+func TestTextSerializeAssistantTurn(t *testing.T) {
+	serializer := NewTextFrameSerializer()
+
+	var got []string
+	for _, frame := range []frames.Frame{
+		frames.NewLLMFullResponseStartFrame(),
+		frames.NewLLMTextFrame("We open"),
+		frames.NewTTSAudioFrame([]byte{0x00, 0x01}, 16000, 1),
+		frames.NewLLMTextFrame(" at 9."),
+		frames.NewLLMFullResponseEndFrame(),
+	} {
+		data, err := serializer.Serialize(frame)
+		if err != nil {
+			t.Fatalf("Serialize(%s) error = %v", frame.Name(), err)
+		}
+		if data != nil {
+			got = append(got, data.(string))
+		}
+	}
+
+	want := []string{
+		`{"type":"text","text":"We open"}`,
+		`{"type":"text","text":" at 9."}`,
+		`{"type":"message","role":"assistant","text":"We open at 9."}`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Serialize() messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTextSerializeInterruptionDiscardsPartialTurn(t *testing.T) {
+	serializer := NewTextFrameSerializer()
+
+	serializer.Serialize(frames.NewLLMTextFrame("Let me"))
+	data, err := serializer.Serialize(frames.NewInterruptionFrame())
+	if err != nil || data != `{"type":"interruption"}` {
+		t.Fatalf("Serialize(InterruptionFrame) = %v, %v", data, err)
+	}
+	if data, _ := serializer.Serialize(frames.NewLLMFullResponseEndFrame()); data != nil {
+		t.Errorf("Serialize(LLMFullResponseEndFrame) after interruption = %v, want nil", data)
+	}
+}
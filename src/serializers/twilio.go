@@ -4,24 +4,41 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 )
 
 // TwilioFrameSerializer handles Twilio Media Streams WebSocket protocol
 type TwilioFrameSerializer struct {
-	streamSid string
-	callSid   string
+	streamSid  string
+	callSid    string
+	accountSid string
+
+	// Negotiated media format from the start event (default: 8kHz mulaw)
+	formatMu   sync.RWMutex
+	codec      string
+	sampleRate int
+
+	// Inbound media ordering: Twilio numbers media chunks from 1 and stamps
+	// them with milliseconds since the stream started (protected by orderMu)
+	orderMu         sync.Mutex
+	lastChunk       int
+	lastTimestampMs int
+	outOfOrder      int64
 }
 
 // Twilio message structures
 type twilioMessage struct {
-	Event     string                 `json:"event"`
-	StreamSid string                 `json:"streamSid,omitempty"`
-	Media     *twilioMedia           `json:"media,omitempty"`
-	Start     *twilioStart           `json:"start,omitempty"`
-	Mark      *twilioMark            `json:"mark,omitempty"`
-	Stop      map[string]interface{} `json:"stop,omitempty"`
+	Event          string                 `json:"event"`
+	SequenceNumber string                 `json:"sequenceNumber,omitempty"`
+	StreamSid      string                 `json:"streamSid,omitempty"`
+	Media          *twilioMedia           `json:"media,omitempty"`
+	Start          *twilioStart           `json:"start,omitempty"`
+	Mark           *twilioMark            `json:"mark,omitempty"`
+	Stop           map[string]interface{} `json:"stop,omitempty"`
 }
 
 type twilioMedia struct {
@@ -47,8 +64,10 @@ type twilioMark struct {
 // NewTwilioFrameSerializer creates a new Twilio serializer
 func NewTwilioFrameSerializer(streamSid, callSid string) *TwilioFrameSerializer {
 	return &TwilioFrameSerializer{
-		streamSid: streamSid,
-		callSid:   callSid,
+		streamSid:  streamSid,
+		callSid:    callSid,
+		codec:      "mulaw",
+		sampleRate: 8000,
 	}
 }
 
@@ -58,8 +77,22 @@ func (s *TwilioFrameSerializer) Type() SerializerType {
 }
 
 // Setup initializes the serializer with startup configuration
+// Picks up streamSid/callSid from StartFrame metadata so outgoing media and
+// clear events are keyed to the right stream.
 func (s *TwilioFrameSerializer) Setup(frame frames.Frame) error {
-	// Can extract streamSid/callSid from StartFrame metadata if needed
+	if frame != nil {
+		if meta := frame.Metadata(); meta != nil {
+			if streamSid, ok := meta["streamSid"].(string); ok && streamSid != "" {
+				s.streamSid = streamSid
+			}
+			if callSid, ok := meta["callSid"].(string); ok && callSid != "" {
+				s.callSid = callSid
+			}
+			if accountSid, ok := meta["accountSid"].(string); ok && accountSid != "" {
+				s.accountSid = accountSid
+			}
+		}
+	}
 	return nil
 }
 
@@ -67,25 +100,13 @@ func (s *TwilioFrameSerializer) Setup(frame frames.Frame) error {
 func (s *TwilioFrameSerializer) Serialize(frame frames.Frame) (interface{}, error) {
 	switch f := frame.(type) {
 	case *frames.AudioFrame:
-		// Encode audio data (mulaw) to base64
-		payload := base64.StdEncoding.EncodeToString(f.Data)
+		return s.serializeMedia(f.Data)
 
-		msg := twilioMessage{
-			Event:     "media",
-			StreamSid: s.streamSid,
-			Media: &twilioMedia{
-				Payload: payload,
-			},
-		}
-
-		data, err := json.Marshal(msg)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal Twilio media message: %w", err)
-		}
-		return string(data), nil
+	case *frames.TTSAudioFrame:
+		return s.serializeMedia(f.Data)
 
 	case *frames.InterruptionFrame:
-		// Send clear event to stop audio playback
+		// Send clear event so Twilio drops any audio it has buffered for this stream
 		msg := twilioMessage{
 			Event:     "clear",
 			StreamSid: s.streamSid,
@@ -107,6 +128,23 @@ func (s *TwilioFrameSerializer) Serialize(frame frames.Frame) (interface{}, erro
 	}
 }
 
+// serializeMedia wraps audio already in the stream's codec in a media event
+func (s *TwilioFrameSerializer) serializeMedia(audio []byte) (interface{}, error) {
+	msg := twilioMessage{
+		Event:     "media",
+		StreamSid: s.streamSid,
+		Media: &twilioMedia{
+			Payload: base64.StdEncoding.EncodeToString(audio),
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Twilio media message: %w", err)
+	}
+	return string(data), nil
+}
+
 // Deserialize converts Twilio WebSocket JSON data to frames
 func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, error) {
 	jsonData, ok := data.(string)
@@ -127,17 +165,34 @@ func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, err
 	switch msg.Event {
 	case "start":
 		// Update streamSid and callSid from start message
+		s.orderMu.Lock()
+		s.lastChunk, s.lastTimestampMs = 0, 0
+		s.orderMu.Unlock()
 		if msg.Start != nil {
 			s.streamSid = msg.Start.StreamSid
 			s.callSid = msg.Start.CallSid
+			s.accountSid = msg.Start.AccountSid
 		}
 
 		// Create StartFrame with metadata
 		startFrame := frames.NewStartFrame()
 		startFrame.SetMetadata("streamSid", s.streamSid)
 		startFrame.SetMetadata("callSid", s.callSid)
+		startFrame.CallID = s.callSid
+		// Twilio Media Streams are always 8kHz mono mulaw
+		startFrame.Codec = "mulaw"
+		startFrame.SampleRate = 8000
+		startFrame.Channels = 1
 		if msg.Start != nil {
 			startFrame.SetMetadata("accountSid", msg.Start.AccountSid)
+			applyTwilioMediaFormat(startFrame, msg.Start.MediaFormat)
+			s.formatMu.Lock()
+			s.codec, s.sampleRate = startFrame.Codec, startFrame.SampleRate
+			s.formatMu.Unlock()
+			startFrame.Locale = msg.Start.CustomParameters["locale"]
+			if len(msg.Start.CustomParameters) > 0 {
+				startFrame.TemplateVars = msg.Start.CustomParameters
+			}
 		}
 		return startFrame, nil
 
@@ -146,6 +201,14 @@ func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, err
 			return nil, fmt.Errorf("media event missing media data")
 		}
 
+		// Audio arriving behind what STT has already heard would be spliced
+		// in out of order; drop it
+		chunk, _ := strconv.Atoi(msg.Media.Chunk)
+		timestamp, _ := strconv.Atoi(msg.Media.Timestamp)
+		if !s.acceptChunk(chunk, timestamp) {
+			return nil, nil
+		}
+
 		// Decode base64 mulaw audio
 		audioData, err := base64.StdEncoding.DecodeString(msg.Media.Payload)
 		if err != nil {
@@ -157,6 +220,13 @@ func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, err
 		audioFrame := frames.NewAudioFrame(audioData, 8000, 1)
 		audioFrame.SetMetadata("codec", "mulaw")
 		audioFrame.SetMetadata("streamSid", s.streamSid)
+		if chunk > 0 {
+			audioFrame.SetMetadata("chunk", chunk)
+			audioFrame.SetMetadata("timestamp_ms", timestamp)
+		}
+		if seq, err := strconv.Atoi(msg.SequenceNumber); err == nil {
+			audioFrame.SetMetadata("sequence_number", seq)
+		}
 		return audioFrame, nil
 
 	case "stop":
@@ -173,6 +243,9 @@ func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, err
 		if msg.Mark != nil {
 			playbackComplete.SetMetadata("correlation_id", msg.Mark.Name)
 		}
+		// Twilio echoes the mark in order with the inbound media, so the
+		// stream clock at that point is when playback reached it
+		playbackComplete.SetMetadata("stream_position_ms", int(s.StreamPosition()/time.Millisecond))
 		return playbackComplete, nil
 
 	default:
@@ -181,6 +254,58 @@ func (s *TwilioFrameSerializer) Deserialize(data interface{}) (frames.Frame, err
 	}
 }
 
+// acceptChunk records an inbound media chunk, reporting false for one at or
+// behind the last chunk received. Chunks without a number are accepted.
+func (s *TwilioFrameSerializer) acceptChunk(chunk, timestampMs int) bool {
+	if chunk <= 0 {
+		return true
+	}
+	s.orderMu.Lock()
+	defer s.orderMu.Unlock()
+	if chunk <= s.lastChunk {
+		s.outOfOrder++
+		return false
+	}
+	s.lastChunk = chunk
+	s.lastTimestampMs = max(s.lastTimestampMs, timestampMs)
+	return true
+}
+
+// OutOfOrderChunks returns how many inbound media chunks were dropped for
+// arriving at or behind an already received chunk
+func (s *TwilioFrameSerializer) OutOfOrderChunks() int64 {
+	s.orderMu.Lock()
+	defer s.orderMu.Unlock()
+	return s.outOfOrder
+}
+
+// StreamPosition returns the media timestamp of the latest inbound chunk:
+// how far into the stream Twilio's clock is
+func (s *TwilioFrameSerializer) StreamPosition() time.Duration {
+	s.orderMu.Lock()
+	defer s.orderMu.Unlock()
+	return time.Duration(s.lastTimestampMs) * time.Millisecond
+}
+
+// applyTwilioMediaFormat copies the start event's mediaFormat, e.g.
+// {"encoding": "audio/x-mulaw", "sampleRate": 8000, "channels": 1}
+func applyTwilioMediaFormat(startFrame *frames.StartFrame, format map[string]interface{}) {
+	if encoding, ok := format["encoding"].(string); ok {
+		switch encoding {
+		case "audio/x-mulaw":
+			startFrame.Codec = "mulaw"
+		case "audio/x-alaw":
+			startFrame.Codec = "alaw"
+		}
+	}
+	if rate, ok := format["sampleRate"].(float64); ok && rate > 0 {
+		startFrame.SampleRate = int(rate)
+	}
+	if channels, ok := format["channels"].(float64); ok && channels > 0 {
+		startFrame.Channels = int(channels)
+	}
+}
+
 // SerializePlaybackDoneAck sends a Twilio mark message. Twilio echoes it back
 // after the client has finished playing all audio sent before the mark, which we
 // map to PlaybackCompleteFrame in Deserialize.
@@ -197,11 +322,37 @@ func (s *TwilioFrameSerializer) SerializePlaybackDoneAck(correlationID string) (
 	return string(data), nil
 }
 
+// SerializeTransfer drops the bot audio Twilio still has buffered. Media
+// Streams has no in-band transfer; the call is redirected by the transport's
+// TransferHandler (see transports.NewTwilioTransferHandler), which ends the
+// stream.
+func (s *TwilioFrameSerializer) SerializeTransfer(destination string) (interface{}, error) {
+	clear, err := json.Marshal(twilioMessage{Event: "clear", StreamSid: s.streamSid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Twilio clear message: %w", err)
+	}
+	return string(clear), nil
+}
+
 // Cleanup releases any resources (none for Twilio serializer)
 func (s *TwilioFrameSerializer) Cleanup() error {
 	return nil
 }
 
+// SupportsCoalescedAudio reports true: a Twilio media payload may carry any
+// number of 20ms frames
+func (s *TwilioFrameSerializer) SupportsCoalescedAudio() bool {
+	return true
+}
+
+// OutputAudioFormat returns the codec and sample rate negotiated in the
+// start event
+func (s *TwilioFrameSerializer) OutputAudioFormat() (string, int) {
+	s.formatMu.RLock()
+	defer s.formatMu.RUnlock()
+	return s.codec, s.sampleRate
+}
+
 // GetStreamSid returns the current stream SID
 func (s *TwilioFrameSerializer) GetStreamSid() string {
 	return s.streamSid
@@ -211,3 +362,8 @@ func (s *TwilioFrameSerializer) GetStreamSid() string {
 func (s *TwilioFrameSerializer) GetCallSid() string {
 	return s.callSid
 }
+
+// GetAccountSid returns the account SID from the start event
+func (s *TwilioFrameSerializer) GetAccountSid() string {
+	return s.accountSid
+}
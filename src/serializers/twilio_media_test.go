@@ -0,0 +1,77 @@
+package serializers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// twilioMediaMessage builds an inbound media event for chunk at timestampMs
+func twilioMediaMessage(seq, chunk, timestampMs int) string {
+	payload := base64.StdEncoding.EncodeToString([]byte{0xFF, 0xFF})
+	return fmt.Sprintf(`{"event":"media","sequenceNumber":"%d","streamSid":"MZ123","media":{"track":"inbound","chunk":"%d","timestamp":"%d","payload":"%s"}}`,
+		seq, chunk, timestampMs, payload)
+}
+
+func TestTwilioDeserializeMediaSetsChunkMetadata(t *testing.T) {
+	serializer := NewTwilioFrameSerializer("MZ123", "CA456")
+
+	frame, err := serializer.Deserialize(twilioMediaMessage(3, 2, 20))
+	if err != nil {
+		t.Fatalf("Deserialize(media) error = %v", err)
+	}
+	audioFrame, ok := frame.(*frames.AudioFrame)
+	if !ok {
+		t.Fatalf("Deserialize(media) frame = %T, want *frames.AudioFrame", frame)
+	}
+
+	meta := audioFrame.Metadata()
+	if meta["chunk"] != 2 || meta["timestamp_ms"] != 20 || meta["sequence_number"] != 3 {
+		t.Errorf("Metadata chunk=%v timestamp_ms=%v sequence_number=%v, want 2, 20 and 3",
+			meta["chunk"], meta["timestamp_ms"], meta["sequence_number"])
+	}
+	if pos := serializer.StreamPosition().Milliseconds(); pos != 20 {
+		t.Errorf("StreamPosition = %dms, want 20ms", pos)
+	}
+}
+
+func TestTwilioDeserializeMediaDropsOutOfOrderChunks(t *testing.T) {
+	serializer := NewTwilioFrameSerializer("MZ123", "CA456")
+
+	var accepted []int
+	for i, chunk := range []int{1, 2, 4, 3, 4, 5} {
+		frame, err := serializer.Deserialize(twilioMediaMessage(i+2, chunk, (chunk-1)*20))
+		if err != nil {
+			t.Fatalf("Deserialize(chunk %d) error = %v", chunk, err)
+		}
+		if frame != nil {
+			accepted = append(accepted, frame.Metadata()["chunk"].(int))
+		}
+	}
+
+	if fmt.Sprint(accepted) != "[1 2 4 5]" {
+		t.Errorf("Accepted chunks %v, want [1 2 4 5]", accepted)
+	}
+	if dropped := serializer.OutOfOrderChunks(); dropped != 2 {
+		t.Errorf("OutOfOrderChunks = %d, want 2", dropped)
+	}
+
+	// The mark echo reports how far the stream had played
+	frame, err := serializer.Deserialize(`{"event":"mark","sequenceNumber":"8","streamSid":"MZ123","mark":{"name":"playback-done"}}`)
+	if err != nil {
+		t.Fatalf("Deserialize(mark) error = %v", err)
+	}
+	if pos := frame.Metadata()["stream_position_ms"]; pos != 80 {
+		t.Errorf("stream_position_ms = %v, want 80", pos)
+	}
+
+	// A new stream starts numbering again
+	if _, err := serializer.Deserialize(`{"event":"start","start":{"streamSid":"MZ789","callSid":"CA456"}}`); err != nil {
+		t.Fatalf("Deserialize(start) error = %v", err)
+	}
+	if frame, _ := serializer.Deserialize(twilioMediaMessage(2, 1, 0)); frame == nil {
+		t.Error("Expected chunk 1 of a new stream to be accepted")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -42,12 +41,11 @@ type LLMService struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 
-	// Request-scoped context for cancellable streaming (protected by streamMu)
-	requestCtx    context.Context
-	requestCancel context.CancelFunc
-	isGenerating  bool
-	lastContextAt time.Time  // When we last received a new context (for interruption filtering)
-	streamMu      sync.Mutex // Protects requestCancel, isGenerating, and lastContextAt
+	// The response being streamed, stopped by interruptions
+	stream *services.StreamGuard
+
+	// Sampling overrides from an LLMParamsFrame for the next generation only
+	nextParams services.NextLLMParams
 }
 
 // LLMConfig holds configuration for Anthropic Claude
@@ -87,6 +85,8 @@ func NewLLMService(config LLMConfig) *LLMService {
 		log:         logger.WithPrefix("AnthropicLLM"),
 	}
 	s.BaseProcessor = processors.NewBaseProcessor("Anthropic", s)
+	s.AttachLogger(s.log)
+	s.stream = services.NewStreamGuard(s.BaseProcessor, s.log)
 	return s
 }
 
@@ -127,30 +127,15 @@ func (s *LLMService) Cleanup() error {
 }
 
 func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// Handle InterruptionFrame - CRITICAL: Stop streaming immediately
-	// BUT: If we just received a new context (within 100ms), this interruption is for
-	// the OLD response, not the new one. Don't cancel in that case.
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
-		s.streamMu.Lock()
-		timeSinceContext := time.Since(s.lastContextAt)
-		isNewContext := timeSinceContext < 100*time.Millisecond
-
-		s.log.Warn("Interruption received (isGenerating=%v, timeSinceContext=%v)", s.isGenerating, timeSinceContext)
-
-		if isNewContext {
-			// This interruption is for the OLD response, not the new one we just started
-			s.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
-			s.streamMu.Unlock()
-			return s.PushFrame(frame, direction)
-		}
+	// Handle LLMParamsFrame - override sampling for the next generation
+	if params, ok := frame.(*frames.LLMParamsFrame); ok {
+		s.nextParams.Set(params)
+		return nil
+	}
 
-		if s.isGenerating && s.requestCancel != nil {
-			s.log.Warn("Cancelling ongoing stream")
-			s.requestCancel()
-			s.isGenerating = false
-		}
-		s.streamMu.Unlock()
-		return s.PushFrame(frame, direction)
+	// Handle LLMCancelGenerationFrame and InterruptionFrame - stop streaming
+	if handled, err := s.stream.HandleFrame(frame, direction); handled {
+		return err
 	}
 
 	// Handle LLMContextFrame (from aggregators)
@@ -159,9 +144,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.log.Debug("Received LLMContextFrame with %d messages", len(llmContext.Messages))
 
 			// Record when we received this context (for interruption filtering)
-			s.streamMu.Lock()
-			s.lastContextAt = time.Now()
-			s.streamMu.Unlock()
+			s.stream.ContextReceived()
 
 			// Update our context reference
 			s.context = llmContext
@@ -170,9 +153,10 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewLLMFullResponseStartFrame(), frames.Downstream)
 
 			// Generate response using the provided context
-			if err := s.generateResponseFromContext(llmContext); err != nil {
+			gen := s.stream.Begin(s.ctx, llmContext)
+			if err := gen.Run(func() error { return s.generateResponseFromContext(gen, llmContext) }); err != nil {
 				// Only log error if not cancelled
-				if s.requestCtx != nil && s.requestCtx.Err() == context.Canceled {
+				if gen.Cancelled() {
 					s.log.Debug("Stream cancelled by interruption")
 				} else {
 					s.log.Error("Error generating response: %v", err)
@@ -181,7 +165,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			}
 
 			// Send LLM response end marker
-			s.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+			gen.PushResponseEnd()
 		}
 		return nil
 	}
@@ -192,30 +176,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 
 // generateResponseFromContext generates a response using the Anthropic Messages API
 // Supports streaming via SSE, tool calling, and interruption cancellation
-func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) error {
-	// Create cancellable context for this request
-	// Use background context if s.ctx is nil (Initialize not called yet)
-	parentCtx := s.ctx
-	if parentCtx == nil {
-		parentCtx = context.Background()
-	}
-
-	// Lock to safely set stream state (read by InterruptionFrame handler)
-	s.streamMu.Lock()
-	s.requestCtx, s.requestCancel = context.WithCancel(parentCtx)
-	s.isGenerating = true
-	s.streamMu.Unlock()
-
-	defer func() {
-		s.streamMu.Lock()
-		s.isGenerating = false
-		if s.requestCancel != nil {
-			s.requestCancel()
-		}
-		s.requestCancel = nil
-		s.streamMu.Unlock()
-	}()
-
+func (s *LLMService) generateResponseFromContext(gen *services.Generation, llmCtx *services.LLMContext) error {
 	// Build Anthropic-format messages
 	// Anthropic differs from OpenAI:
 	// - System prompt is a top-level field, not a message
@@ -304,6 +265,7 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 	if s.temperature > 0 {
 		requestBody["temperature"] = s.temperature
 	}
+	s.nextParams.Take().ApplyTo(requestBody)
 
 	// Add tools if present in context
 	if len(llmCtx.Tools) > 0 {
@@ -347,8 +309,18 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 		return err
 	}
 
+	// Hold a stream slot until the response has been read
+	release, err := services.RateLimiterFor("anthropic", s.apiKey).Acquire(gen.Context())
+	if err != nil {
+		if gen.Context().Err() == context.Canceled {
+			return nil // Interrupted while queued
+		}
+		return fmt.Errorf("Anthropic: %w", err)
+	}
+	defer release()
+
 	// Use cancellable context so interruption can stop the request
-	req, err := http.NewRequestWithContext(s.requestCtx, "POST", s.baseURL+"/messages", bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(gen.Context(), "POST", s.baseURL+"/messages", bytes.NewReader(bodyBytes))
 	if err != nil {
 		return err
 	}
@@ -362,7 +334,7 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 	resp, err := client.Do(req)
 	if err != nil {
 		// Check if cancelled by interruption
-		if s.requestCtx.Err() == context.Canceled {
+		if gen.Context().Err() == context.Canceled {
 			return nil // Not an error, just interrupted
 		}
 		return err
@@ -393,7 +365,7 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 	for scanner.Scan() {
 		// Check if interrupted
 		select {
-		case <-s.requestCtx.Done():
+		case <-gen.Context().Done():
 			s.log.Debug("Stream interrupted, stopping generation")
 			return nil
 		default:
@@ -451,10 +423,11 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 				fullResponse.WriteString(event.Delta.Text)
 				// Emit raw LLMTextFrame - sentence splitting handled by SentenceAggregator
 				textFrame := frames.NewLLMTextFrame(event.Delta.Text)
-				s.PushFrame(textFrame, frames.Downstream)
+				gen.Push(textFrame)
 			} else if event.Delta.Type == "input_json_delta" {
-				if tu, ok := activeToolUses[event.Index]; ok {
+				if tu, ok := activeToolUses[event.Index]; ok && event.Delta.PartialJSON != "" {
 					tu.inputJSON.WriteString(event.Delta.PartialJSON)
+					gen.Push(frames.NewFunctionCallArgsDeltaFrame(tu.id, tu.name, event.Delta.PartialJSON, tu.inputJSON.String()))
 				}
 			}
 
@@ -485,7 +458,11 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 					args,
 					true, // cancelOnInterruption
 				)
-				s.PushFrame(funcFrame, frames.Downstream)
+				if !gen.Push(funcFrame) {
+					// A silenced response's tool calls are not run
+					delete(activeToolUses, event.Index)
+					continue
+				}
 
 				// Track for context update
 				completedToolCalls = append(completedToolCalls, services.ToolCall{
@@ -507,7 +484,7 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 
 	// Check if scanner error was due to cancellation
 	if err := scanner.Err(); err != nil {
-		if s.requestCtx.Err() == context.Canceled {
+		if gen.Context().Err() == context.Canceled {
 			return nil // Not an error, just interrupted
 		}
 		return err
@@ -522,10 +499,10 @@ func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) er
 			Content:   response,
 			ToolCalls: completedToolCalls,
 		}
-		llmCtx.Messages = append(llmCtx.Messages, msg)
+		gen.AddReply(msg)
 		s.log.Info("Assistant response with %d tool calls", len(completedToolCalls))
 	} else if response != "" {
-		llmCtx.AddAssistantMessage(response)
+		gen.AddReply(services.LLMMessage{Role: "assistant", Content: response})
 		s.log.Debug("Assistant: %s", response)
 	}
 
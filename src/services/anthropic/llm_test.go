@@ -254,10 +254,7 @@ func TestLLMServiceInterruptionHandling(t *testing.T) {
 	defer service.Cleanup()
 
 	// Simulate active generation
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx, service.requestCancel = context.WithCancel(ctx)
-	service.streamMu.Unlock()
+	service.stream.Begin(ctx, services.NewLLMContext(""))
 
 	// Send interruption
 	interruptFrame := frames.NewInterruptionFrame()
@@ -267,9 +264,7 @@ func TestLLMServiceInterruptionHandling(t *testing.T) {
 	}
 
 	// Verify generation was stopped
-	service.streamMu.Lock()
-	wasGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	wasGenerating := service.stream.Generating()
 
 	if wasGenerating {
 		t.Error("Expected isGenerating to be false after interruption")
@@ -286,11 +281,8 @@ func TestLLMServiceInterruptionIgnoredForNewContext(t *testing.T) {
 	defer service.Cleanup()
 
 	// Simulate active generation with very recent context
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx, service.requestCancel = context.WithCancel(ctx)
-	service.lastContextAt = time.Now() // Just received context
-	service.streamMu.Unlock()
+	service.stream.ContextReceived() // Just received context
+	gen := service.stream.Begin(ctx, services.NewLLMContext(""))
 
 	// Send interruption immediately (within 100ms window)
 	interruptFrame := frames.NewInterruptionFrame()
@@ -300,20 +292,14 @@ func TestLLMServiceInterruptionIgnoredForNewContext(t *testing.T) {
 	}
 
 	// Verify generation was NOT stopped (interruption was for old response)
-	service.streamMu.Lock()
-	stillGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	stillGenerating := service.stream.Generating()
 
 	if !stillGenerating {
 		t.Error("Expected isGenerating to remain true when interruption occurs within new context window")
 	}
 
 	// Clean up
-	service.streamMu.Lock()
-	if service.requestCancel != nil {
-		service.requestCancel()
-	}
-	service.streamMu.Unlock()
+	gen.End()
 }
 
 // --- SSE Parsing Tests ---
@@ -579,9 +565,10 @@ func TestLLMServiceToolCalling(t *testing.T) {
 	}
 
 	captured := capturer.getFrames()
-	// Should have: LLMFullResponseStartFrame, FunctionCallInProgressFrame, LLMFullResponseEndFrame
-	if len(captured) != 3 {
-		t.Fatalf("Expected 3 frames (start, func_call, end), got %d", len(captured))
+	// Should have: LLMFullResponseStartFrame, two FunctionCallArgsDeltaFrames,
+	// FunctionCallInProgressFrame, LLMFullResponseEndFrame
+	if len(captured) != 5 {
+		t.Fatalf("Expected 5 frames (start, 2 arg deltas, func_call, end), got %d", len(captured))
 	}
 
 	// Frame 0: LLMFullResponseStartFrame
@@ -589,10 +576,21 @@ func TestLLMServiceToolCalling(t *testing.T) {
 		t.Errorf("Frame 0: expected LLMFullResponseStartFrame, got %T", captured[0])
 	}
 
-	// Frame 1: FunctionCallInProgressFrame
-	fcf, ok := captured[1].(*frames.FunctionCallInProgressFrame)
+	// Frames 1-2: argument deltas, accumulating the partial JSON
+	for i, wantArgs := range []string{`{"location": `, `{"location": "San Francisco"}`} {
+		delta, ok := captured[1+i].(*frames.FunctionCallArgsDeltaFrame)
+		if !ok {
+			t.Fatalf("Frame %d: expected FunctionCallArgsDeltaFrame, got %T", 1+i, captured[1+i])
+		}
+		if delta.ToolCallID != "toolu_test123" || delta.FunctionName != "get_weather" || delta.Arguments != wantArgs {
+			t.Errorf("Frame %d: unexpected delta %+v, want arguments %q", 1+i, delta, wantArgs)
+		}
+	}
+
+	// Frame 3: FunctionCallInProgressFrame
+	fcf, ok := captured[3].(*frames.FunctionCallInProgressFrame)
 	if !ok {
-		t.Fatalf("Frame 1: expected FunctionCallInProgressFrame, got %T", captured[1])
+		t.Fatalf("Frame 3: expected FunctionCallInProgressFrame, got %T", captured[3])
 	}
 	if fcf.ToolCallID != "toolu_test123" {
 		t.Errorf("Expected tool call ID 'toolu_test123', got %s", fcf.ToolCallID)
@@ -607,9 +605,9 @@ func TestLLMServiceToolCalling(t *testing.T) {
 		t.Error("Expected CancelOnInterruption to be true")
 	}
 
-	// Frame 2: LLMFullResponseEndFrame
-	if _, ok := captured[2].(*frames.LLMFullResponseEndFrame); !ok {
-		t.Errorf("Frame 2: expected LLMFullResponseEndFrame, got %T", captured[2])
+	// Frame 4: LLMFullResponseEndFrame
+	if _, ok := captured[4].(*frames.LLMFullResponseEndFrame); !ok {
+		t.Errorf("Frame 4: expected LLMFullResponseEndFrame, got %T", captured[4])
 	}
 
 	// Verify tool call was added to context
@@ -705,9 +703,9 @@ func TestLLMServiceToolCallingWithTextAndToolUse(t *testing.T) {
 	service.HandleFrame(ctx, contextFrame, frames.Downstream)
 
 	captured := capturer.getFrames()
-	// start, text("Let me check."), func_call, end
-	if len(captured) != 4 {
-		t.Fatalf("Expected 4 frames, got %d", len(captured))
+	// start, text("Let me check."), arg delta, func_call, end
+	if len(captured) != 5 {
+		t.Fatalf("Expected 5 frames, got %d", len(captured))
 	}
 
 	if tf, ok := captured[1].(*frames.LLMTextFrame); ok {
@@ -718,12 +716,16 @@ func TestLLMServiceToolCallingWithTextAndToolUse(t *testing.T) {
 		t.Errorf("Frame 1: expected LLMTextFrame, got %T", captured[1])
 	}
 
-	if fcf, ok := captured[2].(*frames.FunctionCallInProgressFrame); ok {
+	if _, ok := captured[2].(*frames.FunctionCallArgsDeltaFrame); !ok {
+		t.Errorf("Frame 2: expected FunctionCallArgsDeltaFrame, got %T", captured[2])
+	}
+
+	if fcf, ok := captured[3].(*frames.FunctionCallInProgressFrame); ok {
 		if fcf.FunctionName != "lookup" {
 			t.Errorf("Expected function name 'lookup', got %s", fcf.FunctionName)
 		}
 	} else {
-		t.Errorf("Frame 2: expected FunctionCallInProgressFrame, got %T", captured[2])
+		t.Errorf("Frame 3: expected FunctionCallInProgressFrame, got %T", captured[3])
 	}
 
 	// Context should have assistant message with both text and tool calls
@@ -805,9 +807,7 @@ func TestLLMServiceInterruptionCancelsRequest(t *testing.T) {
 	}
 
 	// Verify isGenerating is false
-	service.streamMu.Lock()
-	stillGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	stillGenerating := service.stream.Generating()
 	if stillGenerating {
 		t.Error("Expected isGenerating to be false after interruption")
 	}
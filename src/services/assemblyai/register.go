@@ -0,0 +1,24 @@
+package assemblyai
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("assemblyai", func(config services.ServiceConfig) (services.STTService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		return NewSTTService(STTConfig{
+			APIKey:                       config.String(services.ConfigAPIKey),
+			Language:                     config.String(services.ConfigLanguage),
+			Model:                        config.String(services.ConfigModel),
+			Domain:                       config.String("domain"),
+			SampleRate:                   config.Int(services.ConfigSampleRate),
+			EndUtteranceSilenceThreshold: config.Int("end_utterance_silence_threshold"),
+			BaseURL:                      config.String(services.ConfigBaseURL),
+		}), nil
+	})
+}
@@ -117,6 +117,7 @@ func NewSTTService(config STTConfig) *STTService {
 		log:                          logger.WithPrefix("AssemblyAISTT"),
 	}
 	s.BaseProcessor = processors.NewBaseProcessor("AssemblyAISTT", s)
+	s.AttachLogger(s.log)
 	return s
 }
 
@@ -206,7 +207,7 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	}
 
 	// Handle InterruptionFrame - send force end utterance to reset stream
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
 		s.log.Info("Received InterruptionFrame, sending force end utterance")
 		if s.conn != nil {
 			forceEnd := map[string]bool{"force_end_utterance": true}
@@ -220,6 +221,7 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 				s.log.Debug("Sent force end utterance to reset STT stream")
 			}
 		}
+		s.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageSTTFinalized)
 		return s.PushFrame(frame, direction)
 	}
 
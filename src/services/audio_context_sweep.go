@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DefaultAudioContextTTL is how long a TTS service keeps an audio context the
+// provider never reported done before evicting it
+const DefaultAudioContextTTL = 2 * time.Minute
+
+// DefaultMaxAudioContexts caps how many audio contexts a TTS service tracks
+// at once; the oldest are evicted beyond it
+const DefaultMaxAudioContexts = 16
+
+// AudioContextBounds applies the defaults to a configured audio context TTL
+// and cap; negative values stay, disabling that bound
+func AudioContextBounds(ttl time.Duration, maxContexts int) (time.Duration, int) {
+	if ttl == 0 {
+		ttl = DefaultAudioContextTTL
+	}
+	if maxContexts == 0 {
+		maxContexts = DefaultMaxAudioContexts
+	}
+	return ttl, maxContexts
+}
+
+// SweepAudioContexts evicts from contexts those started more than ttl before
+// now and then, while more than maxContexts remain, the oldest ones. Contexts
+// named in keep (e.g. the active one) are never evicted. A ttl or
+// maxContexts of zero or less disables that bound. Returns the evicted IDs,
+// oldest first. The caller must hold the lock guarding contexts.
+func SweepAudioContexts[C any](contexts map[string]C, started func(C) time.Time, now time.Time, ttl time.Duration, maxContexts int, keep ...string) []string {
+	type entry struct {
+		id      string
+		started time.Time
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, id := range keep {
+		kept[id] = true
+	}
+	candidates := make([]entry, 0, len(contexts))
+	for id, c := range contexts {
+		if !kept[id] {
+			candidates = append(candidates, entry{id, started(c)})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].started.Before(candidates[j].started) })
+
+	var evicted []string
+	for _, c := range candidates {
+		expired := ttl > 0 && now.Sub(c.started) > ttl
+		overCap := maxContexts > 0 && len(contexts) > maxContexts
+		if !expired && !overCap {
+			break // Sorted oldest first, so the rest are newer and within the cap
+		}
+		delete(contexts, c.id)
+		evicted = append(evicted, c.id)
+	}
+	return evicted
+}
+
+// RunAudioContextSweeper calls sweep every quarter of ttl until ctx is done,
+// so a context outlives ttl by at most a quarter of it. It returns at once if
+// ttl is not positive.
+func RunAudioContextSweeper(ctx context.Context, ttl time.Duration, sweep func()) {
+	if ttl <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(ttl/4, 10*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
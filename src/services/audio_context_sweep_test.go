@@ -0,0 +1,37 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSweepAudioContextsEvictsExpiredAndOverCap(t *testing.T) {
+	now := time.Now()
+	contexts := map[string]time.Time{
+		"expired": now.Add(-2 * time.Minute),
+		"old":     now.Add(-30 * time.Second),
+		"older":   now.Add(-40 * time.Second),
+		"new":     now.Add(-time.Second),
+		"active":  now.Add(-5 * time.Minute),
+	}
+	started := func(t time.Time) time.Time { return t }
+
+	evicted := SweepAudioContexts(contexts, started, now, time.Minute, 3, "active")
+	if want := []string{"expired", "older"}; !slices.Equal(evicted, want) {
+		t.Fatalf("evicted %v, want %v", evicted, want)
+	}
+	for _, id := range []string{"active", "old", "new"} {
+		if _, ok := contexts[id]; !ok {
+			t.Errorf("expected %s to be kept", id)
+		}
+	}
+}
+
+func TestSweepAudioContextsDisabledBounds(t *testing.T) {
+	now := time.Now()
+	contexts := map[string]time.Time{"a": now.Add(-time.Hour), "b": now.Add(-time.Hour)}
+	if evicted := SweepAudioContexts(contexts, func(t time.Time) time.Time { return t }, now, -1, -1); len(evicted) != 0 {
+		t.Fatalf("expected nothing evicted with both bounds disabled, got %v", evicted)
+	}
+}
@@ -0,0 +1,36 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("azure", func(config services.ServiceConfig) (services.STTService, error) {
+		if config.String(services.ConfigAPIKey) == "" || config.String(services.ConfigRegion) == "" {
+			return nil, fmt.Errorf("%s and %s are required", services.ConfigAPIKey, services.ConfigRegion)
+		}
+		return NewSTTService(STTConfig{
+			SubscriptionKey:   config.String(services.ConfigAPIKey),
+			Region:            config.String(services.ConfigRegion),
+			Language:          config.String(services.ConfigLanguage),
+			Encoding:          config.String(services.ConfigEncoding),
+			SampleRate:        config.Int(services.ConfigSampleRate),
+			KeepaliveInterval: config.Duration("keepalive_interval"),
+			KeepaliveTimeout:  config.Duration("keepalive_timeout"),
+		}), nil
+	})
+
+	services.RegisterTTS("azure", func(config services.ServiceConfig) (services.TTSService, error) {
+		if config.String(services.ConfigAPIKey) == "" || config.String(services.ConfigRegion) == "" {
+			return nil, fmt.Errorf("%s and %s are required", services.ConfigAPIKey, services.ConfigRegion)
+		}
+		return NewTTSService(TTSConfig{
+			SubscriptionKey: config.String(services.ConfigAPIKey),
+			Region:          config.String(services.ConfigRegion),
+			Voice:           config.String(services.ConfigVoice),
+			OutputFormat:    config.String(services.ConfigOutputFormat),
+		}), nil
+	})
+}
@@ -31,6 +31,9 @@ type TTSService struct {
 	httpClient      *http.Client
 
 	started bool
+
+	// Converts provider audio to the configured output format (nil = none)
+	outputConverter *services.TTSOutputConverter
 }
 
 // TTSConfig holds configuration for Azure TTS
@@ -39,6 +42,13 @@ type TTSConfig struct {
 	Region          string
 	Voice           string
 	OutputFormat    string
+
+	// OutputSampleRate and OutputCodec ("mulaw", "alaw", "linear16") convert
+	// the provider's audio to the transport's format inside the service, e.g.
+	// to 8kHz mulaw for telephony. Either may be left unset to keep the
+	// provider's. Default: no conversion.
+	OutputSampleRate int
+	OutputCodec      string
 }
 
 // NewTTSService creates a new Azure TTS service
@@ -64,6 +74,8 @@ func NewTTSService(config TTSConfig) *TTSService {
 		voice:           voice,
 		outputFormat:    outputFormat,
 		httpClient:      &http.Client{},
+
+		outputConverter: services.NewTTSOutputConverter(config.OutputSampleRate, config.OutputCodec),
 	}
 
 	service.BaseProcessor = processors.NewBaseProcessor("AzureTTS", service)
@@ -89,6 +101,12 @@ func (s *TTSService) SetVoice(voiceID string) {
 func (s *TTSService) SetModel(model string) {
 }
 
+// OutputFormat implements processors.AudioFormatProducer
+func (s *TTSService) OutputFormat() processors.AudioFormat {
+	sampleRate, _ := s.parseOutputFormat()
+	return s.outputConverter.Format(processors.AudioFormat{SampleRate: sampleRate, Codec: s.getCodec()})
+}
+
 func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	switch f := frame.(type) {
 	case *frames.StartFrame:
@@ -102,6 +120,12 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	case *frames.InterruptionFrame:
 		return s.PushFrame(frame, direction)
 
+	case *frames.SetVoiceFrame:
+		if f.VoiceID != "" {
+			s.SetVoice(f.VoiceID)
+		}
+		return s.PushFrame(frame, direction)
+
 	case *frames.TextFrame:
 		if f.SkipTTS {
 			return s.PushFrame(frame, direction)
@@ -181,6 +205,9 @@ return errors.New(errMsg)
 	audioFrame := frames.NewTTSAudioFrame(audioData, sampleRate, channels)
 	audioFrame.SetMetadata("codec", s.getCodec())
 	audioFrame.SetMetadata("context_id", contextID)
+	if err := s.outputConverter.Convert(audioFrame); err != nil {
+		logger.Warn("[AzureTTS] Output conversion failed: %v", err)
+	}
 	if err := s.PushFrame(audioFrame, frames.Downstream); err != nil {
 		return err
 	}
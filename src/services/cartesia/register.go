@@ -0,0 +1,34 @@
+package cartesia
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterTTS("cartesia", func(config services.ServiceConfig) (services.TTSService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		if _, err := Regions.Resolve(config.String(services.ConfigRegion), config.String(services.ConfigEndpoint), DefaultHost); err != nil {
+			return nil, err
+		}
+		aggregate := true
+		if config.Has("aggregate_sentences") {
+			aggregate = config.Bool("aggregate_sentences")
+		}
+		return NewTTSService(TTSConfig{
+			APIKey:              config.String(services.ConfigAPIKey),
+			VoiceID:             config.String(services.ConfigVoice),
+			Model:               config.String(services.ConfigModel),
+			Language:            config.String(services.ConfigLanguage),
+			SampleRate:          config.Int(services.ConfigSampleRate),
+			Encoding:            config.String(services.ConfigEncoding),
+			AggregateSentences:  aggregate,
+			PronunciationDictID: config.String("pronunciation_dict_id"),
+			Region:              config.String(services.ConfigRegion),
+			Endpoint:            config.String(services.ConfigEndpoint),
+		}), nil
+	})
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-	"unicode"
 
 	"github.com/gorilla/websocket"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -18,6 +17,22 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
+// Valid ranges for Sonic-3 generation parameters
+const (
+	MinVolume = 0.5
+	MaxVolume = 2.0
+	MinSpeed  = 0.6
+	MaxSpeed  = 1.5
+)
+
+// DefaultHost is the Cartesia API host used when no Region or Endpoint is set
+const DefaultHost = "api.cartesia.ai"
+
+// Regions are the Cartesia API hosts selectable with Region
+var Regions = services.RegionalHosts{
+	"us": "api.cartesia.ai",
+}
+
 // GenerationConfig holds Cartesia Sonic-3 generation parameters
 type GenerationConfig struct {
 	Volume  float64 `json:"volume,omitempty"`  // Volume multiplier [0.5, 2.0], default 1.0
@@ -54,6 +69,7 @@ type TTSService struct {
 	*processors.BaseProcessor
 	*services.AudioContextManager
 	apiKey              string
+	host                string // API host, from Region or Endpoint
 	voiceID             string
 	model               string
 	cartesiaVersion     string
@@ -73,9 +89,12 @@ type TTSService struct {
 	// Sentence aggregation
 	textBuffer strings.Builder
 
-	// Audio context management
+	// Audio context management. Contexts the provider never finishes are
+	// evicted after contextTTL, and at most maxContexts are kept.
 	audioContexts map[string]*AudioContext
 	contextMu     sync.RWMutex
+	contextTTL    time.Duration
+	maxContexts   int
 
 	// Metrics tracking
 	ttfbStart    time.Time
@@ -98,6 +117,24 @@ type TTSService struct {
 	// Rate-limiting for "IGNORING old context" logs
 	ignoredAudioCount    int    // Count of ignored audio messages for current old context
 	lastIgnoredContextID string // The context ID we're currently ignoring
+
+	// Stall detection - text sent since the last audio chunk, and contexts
+	// finalized with continue=false that haven't reported done yet
+	// (protected by mu)
+	stallTimeout time.Duration
+	watchdog     *services.StallWatchdog
+	unanswered   []sentText
+	finishing    map[string]bool
+	stallRetried bool // Resynthesis already attempted for the current stall
+
+	// Converts provider audio to the configured output format (nil = none)
+	outputConverter *services.TTSOutputConverter
+}
+
+// sentText is a text message awaiting audio, kept for resynthesis on stall
+type sentText struct {
+	contextID string
+	text      string
 }
 
 // TTSConfig holds configuration for Cartesia TTS
@@ -113,6 +150,23 @@ type TTSConfig struct {
 	GenerationConfig    *GenerationConfig // Optional: volume, speed, emotion for Sonic-3
 	AggregateSentences  bool              // Wait for complete sentences before TTS (default: true)
 	PronunciationDictID string            // Optional: UUID of a pre-created pronunciation dictionary (Sonic-3)
+	StallTimeout        time.Duration     // No audio this long after sending text triggers reconnect + resynthesis (default: 5s, negative disables)
+	Region              string            // API region from Regions (default: DefaultHost)
+	Endpoint            string            // API host override, e.g. a proxy; takes precedence over Region
+
+	// OutputSampleRate and OutputCodec ("mulaw", "alaw", "linear16") convert
+	// the provider's audio to the transport's format inside the service, e.g.
+	// to 8kHz mulaw for telephony. Either may be left unset to keep the
+	// provider's. Default: no conversion.
+	OutputSampleRate int
+	OutputCodec      string
+
+	// AudioContextTTL and MaxAudioContexts bound the audio contexts tracked
+	// when the provider never sends done for some, evicting the oldest.
+	// Defaults: services.DefaultAudioContextTTL and
+	// services.DefaultMaxAudioContexts; negative disables either bound.
+	AudioContextTTL  time.Duration
+	MaxAudioContexts int
 }
 
 // NewTTSService creates a new Cartesia TTS service
@@ -157,8 +211,16 @@ func NewTTSService(config TTSConfig) *TTSService {
 		aggregateSentences = config.AggregateSentences
 	}
 
+	log := logger.WithPrefix("CartesiaTTS")
+	host, err := Regions.Resolve(config.Region, config.Endpoint, DefaultHost)
+	if err != nil {
+		log.Warn("%v, using %s", err, DefaultHost)
+		host = DefaultHost
+	}
+
 	cs := &TTSService{
 		apiKey:              config.APIKey,
+		host:                host,
 		voiceID:             config.VoiceID,
 		model:               model,
 		cartesiaVersion:     cartesiaVersion,
@@ -169,12 +231,20 @@ func NewTTSService(config TTSConfig) *TTSService {
 		generationConfig:    config.GenerationConfig,
 		aggregateSentences:  aggregateSentences,
 		codecDetected:       codecDetected,
-		log:                 logger.WithPrefix("CartesiaTTS"),
+		log:                 log,
 		pronunciationDictID: config.PronunciationDictID,
 		audioContexts:       make(map[string]*AudioContext),
 		AudioContextManager: services.NewAudioContextManager(),
+		outputConverter:     services.NewTTSOutputConverter(config.OutputSampleRate, config.OutputCodec),
+	}
+	cs.stallTimeout = config.StallTimeout
+	if cs.stallTimeout == 0 {
+		cs.stallTimeout = services.DefaultTTSStallTimeout
 	}
+	cs.watchdog = services.NewStallWatchdog(cs.stallTimeout, cs.handleStall)
+	cs.contextTTL, cs.maxContexts = services.AudioContextBounds(config.AudioContextTTL, config.MaxAudioContexts)
 	cs.BaseProcessor = processors.NewBaseProcessor("CartesiaTTS", cs)
+	cs.AttachLogger(cs.log)
 	return cs
 }
 
@@ -186,6 +256,17 @@ func (s *TTSService) SetModel(model string) {
 	s.model = model
 }
 
+// OutputFormat implements processors.AudioFormatProducer. Without a
+// configured SampleRate the format follows the call's codec, known only from
+// the StartFrame.
+func (s *TTSService) OutputFormat() processors.AudioFormat {
+	var native processors.AudioFormat
+	if s.codecDetected {
+		native = processors.AudioFormat{SampleRate: s.sampleRate, Codec: s.encodingToCodec()}
+	}
+	return s.outputConverter.Format(native)
+}
+
 func (s *TTSService) SetLanguage(language string) {
 	s.language = language
 }
@@ -211,6 +292,9 @@ func (s *TTSService) Initialize(ctx context.Context) error {
 	// Start receiving audio
 	go s.receiveAudio()
 
+	// Evict audio contexts the provider never finishes
+	go services.RunAudioContextSweeper(s.ctx, s.contextTTL, s.sweepAudioContexts)
+
 	s.log.Info("Streaming mode connected (context: %s)", s.GetActiveAudioContextID())
 
 	return nil
@@ -222,6 +306,8 @@ func (s *TTSService) Cleanup() error {
 		s.cancel()
 	}
 
+	s.watchdog.Stop()
+
 	// Give goroutines a moment to see the context cancellation
 	time.Sleep(50 * time.Millisecond)
 
@@ -254,26 +340,27 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	if startFrame, ok := frame.(*frames.StartFrame); ok {
 		// Auto-detect output format from incoming codec (only if user didn't set SampleRate)
 		if !s.codecDetected {
-			if meta := startFrame.Metadata(); meta != nil {
-				if codec, ok := meta["codec"].(string); ok {
-					s.log.Info("Detected incoming codec: %s", codec)
-					// Match Cartesia output to incoming codec for compatibility
-					switch codec {
-					case "mulaw":
-						s.sampleRate = 8000
-						s.encoding = "pcm_mulaw"
-						s.log.Info("Auto-configured output format: pcm_mulaw @ 8000Hz")
-					case "alaw":
-						s.sampleRate = 8000
-						s.encoding = "pcm_alaw"
-						s.log.Info("Auto-configured output format: pcm_alaw @ 8000Hz")
-					case "linear16":
-						s.sampleRate = 16000
-						s.encoding = "pcm_s16le"
-						s.log.Info("Auto-configured output format: pcm_s16le @ 16000Hz")
+			if codec := startFrame.MediaCodec(); codec != "" {
+				s.log.Info("Detected incoming codec: %s", codec)
+				// Match Cartesia output to incoming codec for compatibility
+				switch codec {
+				case "mulaw":
+					s.sampleRate = 8000
+					s.encoding = "pcm_mulaw"
+					s.log.Info("Auto-configured output format: pcm_mulaw @ 8000Hz")
+				case "alaw":
+					s.sampleRate = 8000
+					s.encoding = "pcm_alaw"
+					s.log.Info("Auto-configured output format: pcm_alaw @ 8000Hz")
+				case "linear16":
+					s.sampleRate = 16000
+					if startFrame.SampleRate > 0 {
+						s.sampleRate = startFrame.SampleRate
 					}
-					s.codecDetected = true
+					s.encoding = "pcm_s16le"
+					s.log.Info("Auto-configured output format: pcm_s16le @ %dHz", s.sampleRate)
 				}
+				s.codecDetected = true
 			}
 		}
 
@@ -307,7 +394,7 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	}
 
 	// Handle InterruptionFrame - stop synthesis and reset state
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
 		s.log.Info("============================================")
 		s.log.Info("INTERRUPTION RECEIVED")
 		s.log.Info("============================================")
@@ -329,7 +416,12 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.ignoredAudioCount = 0
 			s.lastIgnoredContextID = ""
 		}
+		// Interrupted text is no longer expected to produce audio
+		s.unanswered = nil
+		s.finishing = nil
+		s.stallRetried = false
 		s.mu.Unlock()
+		s.watchdog.Stop()
 		// Reset context IDs via AudioContextManager
 		s.ResetActiveAudioContext()
 
@@ -382,11 +474,24 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewTTSStoppedFrame(), frames.Upstream)
 		}
 
+		s.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageTTSCancelled)
 		s.log.Debug("Interruption complete, passing frame downstream")
 
 		return s.PushFrame(frame, direction)
 	}
 
+	// Handle SetVoiceFrame - finish current context, then switch voice without reconnecting
+	if setVoice, ok := frame.(*frames.SetVoiceFrame); ok {
+		s.handleSetVoice(setVoice)
+		return s.PushFrame(frame, direction)
+	}
+
+	// Handle TTSControlFrame - adjust volume/speed/emotion for subsequent messages
+	if control, ok := frame.(*frames.TTSControlFrame); ok {
+		s.handleTTSControl(control)
+		return s.PushFrame(frame, direction)
+	}
+
 	// Process text frames (LLM output)
 	if textFrame, ok := frame.(*frames.TextFrame); ok {
 		if textFrame.SkipTTS {
@@ -418,24 +523,29 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 		return s.processTextInput(llmFrame.Text)
 	}
 
-	// Handle LLM response end to flush TTS
-	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
-		// Flush any remaining text in buffer (protected by mutex)
-		s.mu.Lock()
-		hasRemainingText := s.textBuffer.Len() > 0
-		var remainingText string
-		if hasRemainingText {
-			remainingText = s.textBuffer.String()
-			s.textBuffer.Reset()
+	// Handle TTSFlushFrame - synthesize buffered text and flush the context,
+	// keeping it open for more text
+	if _, ok := frame.(*frames.TTSFlushFrame); ok {
+		if err := s.flushTextBuffer(); err != nil {
+			s.log.Warn("Error synthesizing remaining text: %v", err)
 		}
-		s.mu.Unlock()
-
-		if hasRemainingText {
-			s.log.Debug("Flushing remaining text: %s", remainingText)
-			if err := s.synthesizeText(remainingText); err != nil {
-				s.log.Warn("Error synthesizing remaining text: %v", err)
+		if ctxID := s.GetActiveAudioContextID(); s.isConnected() && ctxID != "" {
+			s.log.Debug("Flushing context %s", ctxID)
+			flushMsg := s.buildMessageWithContextID("", true, ctxID)
+			flushMsg["flush"] = true
+			if err := s.writeJSON(flushMsg); err != nil {
+				s.log.Warn("Error sending flush: %v", err)
 			}
 		}
+		return s.PushFrame(frame, direction)
+	}
+
+	// Handle LLM response end to flush TTS
+	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
+		// Flush any remaining text in buffer before the terminal continue=false
+		if err := s.flushTextBuffer(); err != nil {
+			s.log.Warn("Error synthesizing remaining text: %v", err)
+		}
 
 		currentContextID := s.GetActiveAudioContextID()
 		turnContextID := s.GetTurnContextID() // capture before ResetActiveAudioContext clears it
@@ -462,6 +572,9 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 		s.ttfbRecorded = false
 		s.mu.Unlock()
 		s.ResetActiveAudioContext()
+		if hasValidContext && wasSpeaking {
+			s.awaitDone(currentContextID)
+		}
 
 		s.log.Info("Closing context %s on normal completion (was_speaking=%v)", logContextID, wasSpeaking)
 		if currentContextID != "" {
@@ -488,6 +601,100 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	return s.PushFrame(frame, direction)
 }
 
+// handleSetVoice flushes buffered text and finalizes the active context with the
+// current voice, then applies the new voice parameters. Cartesia takes the voice
+// per message, so the WebSocket stays open; the next synthesis opens a fresh
+// context (and TTSStartedFrame) with the new voice.
+func (s *TTSService) handleSetVoice(frame *frames.SetVoiceFrame) {
+	if err := s.flushTextBuffer(); err != nil {
+		s.log.Warn("Error synthesizing remaining text before voice change: %v", err)
+	}
+
+	currentContextID := s.GetActiveAudioContextID()
+	if currentContextID != "" && s.isConnected() {
+		flushMsg := s.buildMessageWithContextID("", false, currentContextID)
+		if err := s.writeJSON(flushMsg); err != nil {
+			s.log.Warn("Error flushing context before voice change: %v", err)
+		} else if s.speaking() {
+			s.awaitDone(currentContextID)
+		}
+	}
+
+	s.mu.Lock()
+	s.isSpeaking = false
+	s.ttfbRecorded = false
+	s.mu.Unlock()
+	s.ResetActiveAudioContext()
+
+	if frame.VoiceID != "" {
+		s.voiceID = frame.VoiceID
+	}
+	if frame.Model != "" {
+		s.model = frame.Model
+	}
+	if language, ok := frame.StringSetting("language"); ok {
+		s.language = language
+	}
+
+	genConfig := GenerationConfig{}
+	if s.generationConfig != nil {
+		genConfig = *s.generationConfig
+	}
+	speed, _ := frame.FloatSetting("speed")
+	volume, _ := frame.FloatSetting("volume")
+	changed := s.applySpeedAndVolume(&genConfig, speed, volume)
+	if emotion, ok := frame.StringSetting("emotion"); ok {
+		genConfig.Emotion = emotion
+		changed = true
+	}
+	if changed {
+		s.generationConfig = &genConfig
+	}
+
+	s.log.Info("Voice changed (voice=%s, model=%s, previous context=%s)", s.voiceID, s.model, currentContextID)
+}
+
+// handleTTSControl applies in-range generation settings from a TTSControlFrame.
+// Out-of-range values are rejected and the previous setting is kept.
+func (s *TTSService) handleTTSControl(frame *frames.TTSControlFrame) {
+	genConfig := GenerationConfig{}
+	if s.generationConfig != nil {
+		genConfig = *s.generationConfig
+	}
+
+	s.applySpeedAndVolume(&genConfig, frame.Speed, frame.Volume)
+	if frame.Emotion != "" {
+		genConfig.Emotion = frame.Emotion
+	}
+
+	s.generationConfig = &genConfig
+	s.log.Info("Generation config updated (volume=%.2f, speed=%.2f, emotion=%s)", genConfig.Volume, genConfig.Speed, genConfig.Emotion)
+}
+
+// applySpeedAndVolume copies in-range speed and volume into genConfig. Zero
+// leaves a setting unchanged; out-of-range values are logged and the previous
+// setting is kept. Reports whether anything changed.
+func (s *TTSService) applySpeedAndVolume(genConfig *GenerationConfig, speed, volume float64) bool {
+	changed := false
+	if volume != 0 {
+		if volume < MinVolume || volume > MaxVolume {
+			s.log.Warn("Ignoring volume %.2f outside [%.1f, %.1f]", volume, MinVolume, MaxVolume)
+		} else {
+			genConfig.Volume = volume
+			changed = true
+		}
+	}
+	if speed != 0 {
+		if speed < MinSpeed || speed > MaxSpeed {
+			s.log.Warn("Ignoring speed %.2f outside [%.1f, %.1f]", speed, MinSpeed, MaxSpeed)
+		} else {
+			genConfig.Speed = speed
+			changed = true
+		}
+	}
+	return changed
+}
+
 // processTextInput handles incoming text with optional sentence aggregation
 func (s *TTSService) processTextInput(text string) error {
 	if text == "" {
@@ -506,7 +713,7 @@ func (s *TTSService) processTextInput(text string) error {
 	s.mu.Unlock()
 
 	// Extract complete sentences (doesn't need lock - working on local copy)
-	sentences, remainder := s.extractSentences(bufferedText)
+	sentences, remainder := services.SplitSentences(bufferedText, s.language)
 
 	// Update buffer with remainder (protected by mutex)
 	s.mu.Lock()
@@ -528,40 +735,31 @@ func (s *TTSService) processTextInput(text string) error {
 	return nil
 }
 
-// extractSentences splits text into complete sentences and remainder
-func (s *TTSService) extractSentences(text string) ([]string, string) {
-	var sentences []string
-	var currentSentence strings.Builder
-
-	sentenceEnders := map[rune]bool{
-		'.': true,
-		'!': true,
-		'?': true,
-		';': true,
-	}
-
-	runes := []rune(text)
-	for i := 0; i < len(runes); i++ {
-		r := runes[i]
-		currentSentence.WriteRune(r)
-
-		if sentenceEnders[r] {
-			// Check if this is really end of sentence (not abbreviation like "Dr.")
-			// Simple heuristic: if followed by space and uppercase or end of text
-			if i == len(runes)-1 {
-				// End of text
-				sentences = append(sentences, currentSentence.String())
-				currentSentence.Reset()
-			} else if i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
-				// Followed by space - likely end of sentence
-				sentences = append(sentences, currentSentence.String())
-				currentSentence.Reset()
-			}
-		}
+// flushTextBuffer synthesizes the buffered text one sentence at a time, so a
+// multi-sentence remainder keeps its sentence-boundary prosody and timing.
+// Any trailing partial sentence is sent last.
+func (s *TTSService) flushTextBuffer() error {
+	s.mu.Lock()
+	remainingText := s.textBuffer.String()
+	s.textBuffer.Reset()
+	s.mu.Unlock()
+
+	if remainingText == "" {
+		return nil
 	}
+	s.log.Debug("Flushing remaining text: %s", remainingText)
 
-	// Return sentences and any remaining incomplete text
-	return sentences, currentSentence.String()
+	sentences, remainder := services.SplitSentences(remainingText, s.language)
+	for _, sentence := range append(sentences, remainder) {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" {
+			continue
+		}
+		if err := s.synthesizeText(sentence); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *TTSService) synthesizeText(text string) error {
@@ -569,6 +767,18 @@ func (s *TTSService) synthesizeText(text string) error {
 		return nil
 	}
 
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := services.RateLimiterFor("cartesia", s.apiKey).Wait(ctx); err != nil {
+		if errors.Is(err, services.ErrRateLimited) {
+			s.log.Warn("Dropping text, %v", err)
+			return s.PushFrame(frames.NewErrorFrame(fmt.Errorf("Cartesia: %w", err)), frames.Upstream)
+		}
+		return err
+	}
+
 	// Use AudioContextManager to get or create context ID
 	// Reuses turn context ID if available, otherwise generates new one
 	ctxID := s.GetOrCreateContextID()
@@ -602,7 +812,101 @@ func (s *TTSService) synthesizeText(text string) error {
 
 	// Send text chunk via WebSocket (writeJSON handles nil conn check)
 	msg := s.buildMessageWithContextID(text, true, ctxID)
-	return s.writeJSON(msg)
+	if err := s.writeJSON(msg); err != nil {
+		return err
+	}
+	s.awaitAudio(ctxID, text)
+	return nil
+}
+
+// awaitAudio records text sent to Cartesia and arms the stall watchdog
+func (s *TTSService) awaitAudio(contextID, text string) {
+	if s.watchdog == nil {
+		return
+	}
+	s.mu.Lock()
+	s.unanswered = append(s.unanswered, sentText{contextID: contextID, text: text})
+	s.mu.Unlock()
+	s.watchdog.Arm()
+}
+
+// awaitDone keeps the watchdog running for a context finalized with
+// continue=false until Cartesia reports it done, so a stall partway through
+// its last sentence is caught
+func (s *TTSService) awaitDone(contextID string) {
+	if s.watchdog == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.finishing == nil {
+		s.finishing = make(map[string]bool)
+	}
+	s.finishing[contextID] = true
+	s.mu.Unlock()
+	s.watchdog.Arm()
+}
+
+// speaking reports whether text was sent in the active context
+func (s *TTSService) speaking() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isSpeaking
+}
+
+// handleStall runs when text was sent but no audio came back within the stall
+// timeout. It reports the stall upstream, then reconnects and resends the
+// text sent since the last audio once; a second stall gives up on it. Audio
+// cut short in a finalized context can't be resumed and is only reported.
+func (s *TTSService) handleStall() {
+	s.mu.Lock()
+	pending := s.unanswered
+	finishing := len(s.finishing)
+	retried := s.stallRetried
+	s.unanswered = nil
+	s.finishing = nil
+	s.stallRetried = true
+	s.mu.Unlock()
+
+	if (len(pending) == 0 && finishing == 0) || (s.ctx != nil && s.ctx.Err() != nil) {
+		return
+	}
+
+	s.log.Warn("No audio for %v (%d messages unanswered, %d contexts unfinished)", s.stallTimeout, len(pending), finishing)
+	s.PushFrame(frames.NewErrorFrame(fmt.Errorf("Cartesia stalled: no audio within %v", s.stallTimeout)), frames.Upstream)
+
+	if retried {
+		s.log.Error("Cartesia still stalled after resynthesis, dropping %d messages", len(pending))
+		return
+	}
+
+	if err := s.reconnect(); err != nil {
+		s.log.Error("Reconnect after stall failed: %v", err)
+		s.PushFrame(frames.NewErrorFrame(fmt.Errorf("Cartesia reconnect after stall failed: %w", err)), frames.Upstream)
+		return
+	}
+
+	s.log.Info("Resynthesizing %d messages after stall", len(pending))
+	activeCtxID := s.GetActiveAudioContextID()
+	flushed := make(map[string]bool)
+	for _, sent := range pending {
+		if err := s.writeJSON(s.buildMessageWithContextID(sent.text, true, sent.contextID)); err != nil {
+			s.log.Error("Resynthesis failed: %v", err)
+			return
+		}
+		s.awaitAudio(sent.contextID, sent.text)
+	}
+	// Contexts whose turn already ended had been flushed on the old connection
+	for _, sent := range pending {
+		if sent.contextID == activeCtxID || flushed[sent.contextID] {
+			continue
+		}
+		flushed[sent.contextID] = true
+		if err := s.writeJSON(s.buildMessageWithContextID("", false, sent.contextID)); err != nil {
+			s.log.Warn("Error flushing resynthesized context %s: %v", sent.contextID, err)
+			continue
+		}
+		s.awaitDone(sent.contextID)
+	}
 }
 
 // writeJSON safely writes JSON to the WebSocket with mutex protection.
@@ -723,6 +1027,30 @@ func (s *TTSService) createAudioContext(contextID string) {
 		StartTime:      time.Now(),
 	}
 	s.log.Info("Created audio context: %s", contextID)
+	s.evictAudioContextsLocked(contextID)
+}
+
+// sweepAudioContexts evicts audio contexts past their TTL, keeping the
+// active one
+func (s *TTSService) sweepAudioContexts() {
+	s.contextMu.Lock()
+	defer s.contextMu.Unlock()
+	s.evictAudioContextsLocked()
+}
+
+// evictAudioContextsLocked removes contexts older than contextTTL and the
+// oldest beyond maxContexts, never the active one or keep. Audio still
+// arriving for an evicted context is dropped as stale. Must be called with
+// contextMu held.
+func (s *TTSService) evictAudioContextsLocked(keep ...string) {
+	keep = append(keep, s.GetActiveAudioContextID())
+	evicted := services.SweepAudioContexts(s.audioContexts,
+		func(c *AudioContext) time.Time { return c.StartTime },
+		time.Now(), s.contextTTL, s.maxContexts, keep...)
+	if len(evicted) > 0 {
+		s.log.Warn("Evicted %d audio contexts never finished by the provider: %v (%d remain)",
+			len(evicted), evicted, len(s.audioContexts))
+	}
 }
 
 func (s *TTSService) removeAudioContext(contextID string) {
@@ -874,7 +1202,17 @@ func (s *TTSService) receiveAudio() {
 					s.ttfbRecorded = true
 					s.log.Info("TTFB (Time to First Byte): %v", ttfb)
 				}
+				// Audio is flowing - everything sent so far is being answered;
+				// finalized contexts still owe the rest of their audio
+				s.unanswered = nil
+				s.stallRetried = false
+				finishing := len(s.finishing) > 0
 				s.mu.Unlock()
+				if finishing {
+					s.watchdog.Feed()
+				} else {
+					s.watchdog.Stop()
+				}
 
 				// Audio chunk - decode base64 audio
 				if audioB64, ok := response["data"].(string); ok && audioB64 != "" {
@@ -888,6 +1226,9 @@ func (s *TTSService) receiveAudio() {
 					audioFrame := frames.NewTTSAudioFrame(audioData, s.sampleRate, 1)
 					audioFrame.SetMetadata("codec", codec)
 					audioFrame.SetMetadata("context_id", receivedCtxID)
+					if err := s.outputConverter.Convert(audioFrame); err != nil {
+						s.log.Warn("Output conversion failed: %v", err)
+					}
 
 					// Add to audio context for tracking
 					if hasCtxID {
@@ -938,13 +1279,19 @@ func (s *TTSService) receiveAudio() {
 
 				// Remove audio context
 				s.removeAudioContext(receivedCtxID)
+				s.CompleteAudioContext(receivedCtxID)
 
 				s.mu.Lock()
 				if s.isSpeaking {
 					s.isSpeaking = false
 					s.log.Info("Synthesis completed (WebSocketOutput will emit TTSStoppedFrame after playback)")
 				}
+				delete(s.finishing, receivedCtxID)
+				idle := len(s.finishing) == 0 && len(s.unanswered) == 0
 				s.mu.Unlock()
+				if idle {
+					s.watchdog.Stop()
+				}
 
 			case "error":
 				// Error message
@@ -962,6 +1309,12 @@ func (s *TTSService) receiveAudio() {
 	}
 }
 
+// wsURL builds the TTS WebSocket URL
+func (s *TTSService) wsURL() string {
+	return services.EndpointURL("wss", s.host, "/tts/websocket") +
+		fmt.Sprintf("?api_key=%s&cartesia_version=%s", s.apiKey, s.cartesiaVersion)
+}
+
 // dialWebSocket creates a new WebSocket connection to Cartesia.
 // Does NOT hold any locks — safe to call from any goroutine.
 func (s *TTSService) dialWebSocket() (*websocket.Conn, error) {
@@ -969,10 +1322,7 @@ func (s *TTSService) dialWebSocket() (*websocket.Conn, error) {
 		return s.dialFunc()
 	}
 
-	wsURL := fmt.Sprintf("wss://api.cartesia.ai/tts/websocket?api_key=%s&cartesia_version=%s",
-		s.apiKey, s.cartesiaVersion)
-
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	conn, _, err := websocket.DefaultDialer.Dial(s.wsURL(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Cartesia: %w", err)
 	}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
 	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
@@ -503,3 +504,643 @@ func TestReconnectLockedConcurrentDial(t *testing.T) {
 		}
 	}
 }
+
+func TestSetVoiceFrameAppliesToNextUtterance(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice-a", Model: "sonic-3"})
+	s.dialFunc = testDialWebSocket(wsURL)
+	defer closeTestService(s)
+
+	ctx := context.Background()
+	nextMsg := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	first := nextMsg()
+	firstCtx := first["context_id"]
+	if voice := first["voice"].(map[string]interface{}); voice["id"] != "voice-a" {
+		t.Fatalf("expected first utterance with voice-a, got %v", voice["id"])
+	}
+
+	// Partial sentence is buffered, then flushed with the old voice on voice change
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("And more"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	setVoice := frames.NewSetVoiceFrame("voice-b", "sonic-2", map[string]interface{}{"speed": 1.2, "emotion": "excited"})
+	if err := s.HandleFrame(ctx, setVoice, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(SetVoiceFrame) failed: %v", err)
+	}
+
+	flushedText := nextMsg()
+	if flushedText["transcript"] != "And more" || flushedText["context_id"] != firstCtx {
+		t.Fatalf("expected buffered text flushed on old context, got %#v", flushedText)
+	}
+	if voice := flushedText["voice"].(map[string]interface{}); voice["id"] != "voice-a" {
+		t.Fatalf("expected buffered text to use old voice, got %v", voice["id"])
+	}
+	final := nextMsg()
+	if final["continue"] != false || final["context_id"] != firstCtx {
+		t.Fatalf("expected continue=false on old context, got %#v", final)
+	}
+
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("New persona here. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	next := nextMsg()
+	if next["context_id"] == firstCtx {
+		t.Error("expected a new context for the new voice")
+	}
+	if voice := next["voice"].(map[string]interface{}); voice["id"] != "voice-b" {
+		t.Errorf("expected voice-b, got %v", voice["id"])
+	}
+	if next["model_id"] != "sonic-2" {
+		t.Errorf("expected model sonic-2, got %v", next["model_id"])
+	}
+	genConfig, ok := next["generation_config"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected generation_config, got %#v", next)
+	}
+	if genConfig["speed"] != 1.2 || genConfig["emotion"] != "excited" {
+		t.Errorf("unexpected generation_config: %#v", genConfig)
+	}
+}
+
+func TestTTSControlFrameUpdatesGenerationConfig(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	s := NewTTSService(TTSConfig{
+		APIKey:           "test-key",
+		VoiceID:          "voice-a",
+		Model:            "sonic-3",
+		GenerationConfig: &GenerationConfig{Volume: 1.2},
+	})
+	s.dialFunc = testDialWebSocket(wsURL)
+	defer closeTestService(s)
+
+	ctx := context.Background()
+	nextMsg := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	first := nextMsg()
+	genConfig := first["generation_config"].(map[string]interface{})
+	if genConfig["volume"] != 1.2 || genConfig["speed"] != nil {
+		t.Fatalf("unexpected initial generation_config: %#v", genConfig)
+	}
+
+	// Volume is out of range and must be rejected; speed and emotion apply
+	control := frames.NewTTSControlFrame(3.0, 0.8, "calm")
+	if err := s.HandleFrame(ctx, control, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSControlFrame) failed: %v", err)
+	}
+
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Let me say that again. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	next := nextMsg()
+	if next["context_id"] != first["context_id"] {
+		t.Errorf("expected control change to keep the current context")
+	}
+	genConfig, ok := next["generation_config"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected generation_config, got %#v", next)
+	}
+	if genConfig["volume"] != 1.2 {
+		t.Errorf("expected out-of-range volume to be ignored, got %v", genConfig["volume"])
+	}
+	if genConfig["speed"] != 0.8 || genConfig["emotion"] != "calm" {
+		t.Errorf("unexpected generation_config: %#v", genConfig)
+	}
+}
+
+func TestTTSControlFrameRejectsOutOfRangeSpeed(t *testing.T) {
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3"})
+
+	for _, speed := range []float64{0.5, 1.6} {
+		if err := s.HandleFrame(context.Background(), frames.NewTTSControlFrame(0, speed, ""), frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame(TTSControlFrame) failed: %v", err)
+		}
+		if s.generationConfig.Speed != 0 {
+			t.Errorf("expected speed %.1f to be rejected, got %.1f", speed, s.generationConfig.Speed)
+		}
+	}
+
+	if err := s.HandleFrame(context.Background(), frames.NewTTSControlFrame(MaxVolume, MinSpeed, ""), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSControlFrame) failed: %v", err)
+	}
+	if s.generationConfig.Volume != MaxVolume || s.generationConfig.Speed != MinSpeed {
+		t.Errorf("expected boundary values to be accepted, got %+v", s.generationConfig)
+	}
+}
+
+// upstreamCapture collects frames a service pushes upstream
+type upstreamCapture struct {
+	*processors.BaseProcessor
+	ch chan frames.Frame
+}
+
+func newUpstreamCapture() *upstreamCapture {
+	c := &upstreamCapture{ch: make(chan frames.Frame, 32)}
+	c.BaseProcessor = processors.NewBaseProcessor("UpstreamCapture", nil)
+	return c
+}
+
+func (c *upstreamCapture) QueueFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	select {
+	case c.ch <- frame:
+	default:
+	}
+	return nil
+}
+
+func TestStallWatchdogReconnectsAndResynthesizes(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	type received struct {
+		conn int
+		msg  map[string]interface{}
+	}
+	messages := make(chan received, 16)
+	var connMu sync.Mutex
+	conns := 0
+	// Accepts text but never returns audio
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connMu.Lock()
+		conns++
+		id := conns
+		connMu.Unlock()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			messages <- received{conn: id, msg: msg}
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3", StallTimeout: 100 * time.Millisecond})
+	s.dialFunc = testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer closeTestService(s)
+	capture := newUpstreamCapture()
+	s.SetPrev(capture)
+
+	if err := s.HandleFrame(context.Background(), frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+
+	nextMsg := func() received {
+		t.Helper()
+		select {
+		case r := <-messages:
+			return r
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return received{}
+		}
+	}
+
+	first := nextMsg()
+	if first.conn != 1 || !strings.HasPrefix(first.msg["transcript"].(string), "Hello there.") {
+		t.Fatalf("unexpected first message: %+v", first)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for gotError := false; !gotError; {
+		select {
+		case frame := <-capture.ch:
+			_, gotError = frame.(*frames.ErrorFrame)
+		case <-deadline:
+			t.Fatal("expected an ErrorFrame upstream when no audio arrives")
+		}
+	}
+
+	// Recovery resends the unanswered text on a fresh connection
+	resent := nextMsg()
+	if resent.conn != 2 {
+		t.Fatalf("expected resynthesis on a new connection, got connection %d", resent.conn)
+	}
+	if resent.msg["transcript"] != first.msg["transcript"] || resent.msg["context_id"] != first.msg["context_id"] {
+		t.Errorf("expected the unanswered text to be resent in its context, got %#v", resent.msg)
+	}
+}
+
+func TestStallWatchdogFedByAudio(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	dials := 0
+	var dialMu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg["transcript"] == "" {
+				continue
+			}
+			conn.WriteJSON(map[string]interface{}{
+				"type":       "chunk",
+				"context_id": msg["context_id"],
+				"data":       "AAAA",
+			})
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3", StallTimeout: 100 * time.Millisecond})
+	dial := testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	s.dialFunc = func() (*websocket.Conn, error) {
+		dialMu.Lock()
+		dials++
+		dialMu.Unlock()
+		return dial()
+	}
+	defer closeTestService(s)
+	capture := newUpstreamCapture()
+	s.SetPrev(capture)
+
+	if err := s.HandleFrame(context.Background(), frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+
+	timeout := time.After(300 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case frame := <-capture.ch:
+			if _, ok := frame.(*frames.ErrorFrame); ok {
+				t.Fatalf("unexpected ErrorFrame while audio is flowing: %v", frame.(*frames.ErrorFrame).Error)
+			}
+		case <-timeout:
+			done = true
+		}
+	}
+	dialMu.Lock()
+	defer dialMu.Unlock()
+	if dials != 1 {
+		t.Errorf("expected no reconnect while audio is flowing, dials=%d", dials)
+	}
+}
+
+func TestStallWatchdogWaitsForFinalizedContext(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		sendDone  bool
+		wantStall bool
+	}{
+		{"stalls after first chunk", false, true},
+		{"done ends the wait", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			upgrader := websocket.Upgrader{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				for {
+					var msg map[string]interface{}
+					if err := conn.ReadJSON(&msg); err != nil {
+						return
+					}
+					switch {
+					case msg["transcript"] != nil && msg["transcript"] != "":
+						// Only the start of the sentence is ever generated
+						conn.WriteJSON(map[string]interface{}{"type": "chunk", "context_id": msg["context_id"], "data": "AAAA"})
+					case msg["continue"] == false && tc.sendDone:
+						conn.WriteJSON(map[string]interface{}{"type": "done", "context_id": msg["context_id"]})
+					}
+				}
+			}))
+			defer server.Close()
+
+			s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3", StallTimeout: 100 * time.Millisecond})
+			s.dialFunc = testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+			defer closeTestService(s)
+			capture := newUpstreamCapture()
+			s.SetPrev(capture)
+
+			ctx := context.Background()
+			if err := s.HandleFrame(ctx, frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+			}
+			if err := s.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(LLMFullResponseEndFrame) failed: %v", err)
+			}
+
+			stalled := false
+			timeout := time.After(400 * time.Millisecond)
+			for waiting := true; waiting && !stalled; {
+				select {
+				case frame := <-capture.ch:
+					_, stalled = frame.(*frames.ErrorFrame)
+				case <-timeout:
+					waiting = false
+				}
+			}
+			if stalled != tc.wantStall {
+				t.Errorf("expected stall=%v, got %v", tc.wantStall, stalled)
+			}
+		})
+	}
+}
+
+func TestResponseEndFlushesRemainderPerSentence(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3"})
+	s.dialFunc = testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer closeTestService(s)
+
+	ctx := context.Background()
+	nextMsg := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Intro. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	nextMsg()
+
+	// A remainder that still holds several sentences at response end
+	s.mu.Lock()
+	s.textBuffer.WriteString("First sentence. Second sentence! And a tail")
+	s.mu.Unlock()
+	if err := s.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMFullResponseEndFrame) failed: %v", err)
+	}
+
+	for _, want := range []string{"First sentence.", "Second sentence!", "And a tail"} {
+		msg := nextMsg()
+		if msg["continue"] == false {
+			t.Fatalf("expected %q to be synthesized before the final flush, got %#v", want, msg)
+		}
+		if got, _ := msg["transcript"].(string); strings.TrimSpace(got) != want {
+			t.Fatalf("expected sentence %q synthesized separately, got %q", want, got)
+		}
+	}
+	if final := nextMsg(); final["continue"] != false {
+		t.Fatalf("expected continue=false after the sentences, got %#v", final)
+	}
+}
+
+func TestAudioContextsSweptAfterTTL(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	send := make(chan map[string]interface{}, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for msg := range send {
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	defer close(send)
+
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3", AudioContextTTL: 40 * time.Millisecond})
+	s.dialFunc = testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	if err := s.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer closeTestService(s)
+	downstream := newUpstreamCapture()
+	s.Link(downstream)
+
+	// The provider never reports these done
+	s.createAudioContext("never-done-1")
+	s.createAudioContext("never-done-2")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.audioContextAvailable("never-done-1") || s.audioContextAvailable("never-done-2") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected contexts without completion to be swept after the TTL")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	active := s.GetActiveAudioContextID()
+	if active == "" {
+		t.Fatal("expected the active context to survive the sweep")
+	}
+
+	// Late audio for a swept context is still filtered as stale
+	send <- map[string]interface{}{"type": "chunk", "context_id": "never-done-1", "data": "AQEB"}
+	send <- map[string]interface{}{"type": "chunk", "context_id": active, "data": "AgIC"}
+	select {
+	case frame := <-downstream.ch:
+		audio, ok := frame.(*frames.TTSAudioFrame)
+		if !ok || string(audio.Data) != "\x02\x02\x02" {
+			t.Fatalf("expected only the active context's audio, got %T %v", frame, frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected audio for the active context")
+	}
+}
+
+func TestAudioContextsCappedOldestFirst(t *testing.T) {
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3", MaxAudioContexts: 2})
+	for _, id := range []string{"first", "second", "third"} {
+		s.createAudioContext(id)
+		time.Sleep(time.Millisecond) // Distinct start times
+	}
+	if s.audioContextAvailable("first") {
+		t.Error("expected the oldest context to be evicted beyond the cap")
+	}
+	if !s.audioContextAvailable("second") || !s.audioContextAvailable("third") {
+		t.Error("expected the newest contexts to be kept")
+	}
+}
+
+func TestTTSFlushFrameFlushesWithoutClosingContext(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3"})
+	s.dialFunc = testDialWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer closeTestService(s)
+
+	ctx := context.Background()
+	nextMsg := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	// Text without a sentence boundary waits in the buffer
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Partial text"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	if err := s.HandleFrame(ctx, frames.NewTTSFlushFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSFlushFrame) failed: %v", err)
+	}
+
+	text := nextMsg()
+	if got, _ := text["transcript"].(string); strings.TrimSpace(got) != "Partial text" {
+		t.Fatalf("expected the buffered text synthesized, got %#v", text)
+	}
+	flush := nextMsg()
+	if flush["flush"] != true || flush["continue"] != true || flush["context_id"] != text["context_id"] {
+		t.Fatalf("expected a continuing flush for context %v, got %#v", text["context_id"], flush)
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("expected the context left open, got %#v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if s.GetActiveAudioContextID() != text["context_id"] {
+		t.Errorf("expected context %v still active, got %q", text["context_id"], s.GetActiveAudioContextID())
+	}
+}
+
+func TestCartesiaTTSEndpointUsedInDialedURL(t *testing.T) {
+	if s := NewTTSService(TTSConfig{APIKey: "test-key"}); !strings.HasPrefix(s.wsURL(), "wss://api.cartesia.ai/tts/websocket?") {
+		t.Errorf("default wsURL = %q", s.wsURL())
+	}
+
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path + "?" + r.URL.RawQuery
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+	s := NewTTSService(TTSConfig{APIKey: "test-key", Endpoint: endpoint, CartesiaVersion: "2025-04-16"})
+	conn, err := s.dialWebSocket()
+	if err != nil {
+		t.Fatalf("dialWebSocket failed: %v", err)
+	}
+	conn.Close()
+
+	if got := <-paths; got != "/tts/websocket?api_key=test-key&cartesia_version=2025-04-16" {
+		t.Errorf("Dialed %q", got)
+	}
+}
+
+func TestSetVoiceFrameRejectsOutOfRangeSettings(t *testing.T) {
+	s := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "test-voice", Model: "sonic-3"})
+
+	setVoice := frames.NewSetVoiceFrame("voice-b", "", map[string]interface{}{"speed": 3.0, "volume": 1.5})
+	if err := s.HandleFrame(context.Background(), setVoice, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(SetVoiceFrame) failed: %v", err)
+	}
+	if s.generationConfig == nil || s.generationConfig.Speed != 0 || s.generationConfig.Volume != 1.5 {
+		t.Errorf("expected speed 3.0 to be rejected and volume 1.5 applied, got %+v", s.generationConfig)
+	}
+}
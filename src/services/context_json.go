@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// llmContextVersion is the current saved context format version
+const llmContextVersion = 1
+
+// savedLLMContext is the persisted form of an LLMContext
+type savedLLMContext struct {
+	Version      int            `json:"version"`
+	SystemPrompt string         `json:"system_prompt,omitempty"`
+	Model        string         `json:"model,omitempty"`
+	Temperature  float64        `json:"temperature"`
+	Messages     []savedMessage `json:"messages"`
+	Tools        []Tool         `json:"tools,omitempty"`
+	ToolChoice   interface{}    `json:"tool_choice,omitempty"`
+}
+
+// savedMessage is the persisted form of an LLMMessage
+type savedMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// MarshalJSON saves the conversation - messages with their tool calls and
+// results, the system prompt, model settings and tools - so it can be
+// restored for a callback or a later session
+func (c *LLMContext) MarshalJSON() ([]byte, error) {
+	saved := savedLLMContext{
+		Version:      llmContextVersion,
+		SystemPrompt: c.SystemPrompt,
+		Model:        c.Model,
+		Temperature:  c.Temperature,
+		Messages:     make([]savedMessage, len(c.Messages)),
+		Tools:        c.Tools,
+		ToolChoice:   c.ToolChoice,
+	}
+	for i, m := range c.Messages {
+		saved.Messages[i] = savedMessage(m)
+	}
+	return json.Marshal(saved)
+}
+
+// UnmarshalJSON restores a context saved with MarshalJSON, replacing the
+// current conversation. Tool calls and results that lost their counterpart
+// (e.g. the context was saved while a function was running) are dropped, as
+// providers reject a tool call without a result and vice versa.
+func (c *LLMContext) UnmarshalJSON(data []byte) error {
+	var saved savedLLMContext
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	if saved.Version > llmContextVersion {
+		return fmt.Errorf("unsupported LLM context version %d", saved.Version)
+	}
+
+	messages := make([]LLMMessage, len(saved.Messages))
+	for i, m := range saved.Messages {
+		messages[i] = LLMMessage(m)
+	}
+
+	c.SystemPrompt = saved.SystemPrompt
+	c.Model = saved.Model
+	c.Temperature = saved.Temperature
+	c.Messages = repairToolPairs(messages)
+	c.Tools = saved.Tools
+	c.ToolChoice = saved.ToolChoice
+	return nil
+}
+
+// repairToolPairs drops tool results with no matching assistant tool call and
+// tool calls that never got a result. An assistant message left with neither
+// content nor tool calls is removed.
+func repairToolPairs(messages []LLMMessage) []LLMMessage {
+	issued := make(map[string]bool)
+	answered := make(map[string]bool)
+	for _, m := range messages {
+		switch {
+		case m.Role == "assistant":
+			for _, call := range m.ToolCalls {
+				issued[call.ID] = true
+			}
+		case m.Role == "tool" && issued[m.ToolCallID]:
+			answered[m.ToolCallID] = true
+		}
+	}
+
+	repaired := make([]LLMMessage, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case "assistant":
+			if len(m.ToolCalls) == 0 {
+				break
+			}
+			calls := make([]ToolCall, 0, len(m.ToolCalls))
+			for _, call := range m.ToolCalls {
+				if answered[call.ID] {
+					calls = append(calls, call)
+				}
+			}
+			if len(calls) == 0 && m.Content == "" {
+				continue
+			}
+			m.ToolCalls = calls
+		case "tool":
+			if !answered[m.ToolCallID] {
+				continue
+			}
+		}
+		repaired = append(repaired, m)
+	}
+	return repaired
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLLMContextJSONRoundTrip(t *testing.T) {
+	ctx := NewLLMContext("You are a booking assistant.")
+	ctx.Model = "gpt-4o"
+	ctx.Temperature = 0.3
+	ctx.SetTools([]Tool{{
+		Type: "function",
+		Function: ToolFunction{
+			Name:        "book_table",
+			Description: "Book a table",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"guests": map[string]interface{}{"type": "integer"}},
+			},
+		},
+	}})
+	ctx.SetToolChoice("auto")
+	ctx.AddUserMessage("Table for two at 7pm")
+	ctx.AddMessageWithToolCalls([]ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: FunctionCall{Name: "book_table", Arguments: `{"guests":2}`},
+	}})
+	ctx.AddToolMessage("call_1", `{"confirmed":true}`)
+	ctx.AddAssistantMessage("You're booked for 7pm.")
+
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	restored := NewLLMContext("")
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if restored.SystemPrompt != ctx.SystemPrompt || restored.Model != ctx.Model || restored.Temperature != ctx.Temperature {
+		t.Errorf("Settings not restored: %+v", restored)
+	}
+	if !reflect.DeepEqual(restored.Messages, ctx.Messages) {
+		t.Errorf("Messages not restored:\n got  %+v\n want %+v", restored.Messages, ctx.Messages)
+	}
+	if len(restored.Tools) != 1 || restored.Tools[0].Function.Name != "book_table" {
+		t.Fatalf("Tools not restored: %+v", restored.Tools)
+	}
+	params, ok := restored.Tools[0].Function.Parameters.(map[string]interface{})
+	if !ok || params["type"] != "object" {
+		t.Errorf("Tool parameters not restored: %#v", restored.Tools[0].Function.Parameters)
+	}
+	if restored.ToolChoice != "auto" {
+		t.Errorf("ToolChoice = %v, want auto", restored.ToolChoice)
+	}
+}
+
+func TestLLMContextUnmarshalDropsUnpairedToolMessages(t *testing.T) {
+	ctx := NewLLMContext("")
+	ctx.AddUserMessage("What's the weather and the time?")
+	// Saved while get_time was still running
+	ctx.AddMessageWithToolCalls([]ToolCall{
+		{ID: "call_weather", Type: "function", Function: FunctionCall{Name: "get_weather"}},
+		{ID: "call_time", Type: "function", Function: FunctionCall{Name: "get_time"}},
+	})
+	ctx.AddToolMessage("call_weather", "sunny")
+	ctx.AddToolMessage("call_orphan", "stale")
+	ctx.AddMessageWithToolCalls([]ToolCall{
+		{ID: "call_pending", Type: "function", Function: FunctionCall{Name: "get_news"}},
+	})
+
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	restored := NewLLMContext("")
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(restored.Messages) != 3 {
+		t.Fatalf("Expected user, assistant and one tool result, got %+v", restored.Messages)
+	}
+	calls := restored.Messages[1].ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_weather" {
+		t.Errorf("Expected only the answered tool call to remain, got %+v", calls)
+	}
+	if restored.Messages[2].ToolCallID != "call_weather" {
+		t.Errorf("Expected the matching tool result, got %+v", restored.Messages[2])
+	}
+}
+
+func TestLLMContextUnmarshalRejectsNewerVersion(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"version":99,"messages":[]}`), NewLLMContext(""))
+	if err == nil {
+		t.Fatal("Expected an error for an unsupported version")
+	}
+}
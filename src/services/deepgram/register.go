@@ -0,0 +1,52 @@
+package deepgram
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("deepgram", func(config services.ServiceConfig) (services.STTService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		if _, err := Regions.Resolve(config.String(services.ConfigRegion), config.String(services.ConfigEndpoint), DefaultHost); err != nil {
+			return nil, err
+		}
+		keywords := config.StringSlice("keywords")
+		if err := ValidateKeywords(keywords); err != nil {
+			return nil, err
+		}
+		return NewSTTService(STTConfig{
+			APIKey:            config.String(services.ConfigAPIKey),
+			Language:          config.String(services.ConfigLanguage),
+			Model:             config.String(services.ConfigModel),
+			Encoding:          config.String(services.ConfigEncoding),
+			BaseURL:           config.String(services.ConfigBaseURL),
+			Region:            config.String(services.ConfigRegion),
+			Endpoint:          config.String(services.ConfigEndpoint),
+			KeepaliveInterval: config.Duration("keepalive_interval"),
+			KeepaliveTimeout:  config.Duration("keepalive_timeout"),
+			EagerInit:         config.Bool("eager_init"),
+			Keywords:          keywords,
+		}), nil
+	})
+
+	services.RegisterTTS("deepgram", func(config services.ServiceConfig) (services.TTSService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		if _, err := Regions.Resolve(config.String(services.ConfigRegion), config.String(services.ConfigEndpoint), DefaultHost); err != nil {
+			return nil, err
+		}
+		return NewTTSService(TTSConfig{
+			APIKey:     config.String(services.ConfigAPIKey),
+			Model:      config.String(services.ConfigModel),
+			Encoding:   config.String(services.ConfigEncoding),
+			SampleRate: config.Int(services.ConfigSampleRate),
+			Region:     config.String(services.ConfigRegion),
+			Endpoint:   config.String(services.ConfigEndpoint),
+		}), nil
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,8 +17,26 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
+// DefaultBaseURL is the Deepgram streaming transcription endpoint
+const DefaultBaseURL = "wss://api.deepgram.com/v1/listen"
+
+// DefaultHost is the Deepgram API host used when no Region or Endpoint is set
+const DefaultHost = "api.deepgram.com"
+
+// Regions are the Deepgram API hosts selectable with Region; pass them to
+// services.NearestRegion to pick the closest
+var Regions = services.RegionalHosts{
+	"us": "api.deepgram.com",
+	"eu": "api.eu.deepgram.com",
+}
+
+// DetectedLanguageKey is the TranscriptionFrame metadata key holding the
+// language Deepgram detected when STTConfig.DetectLanguage is set
+const DetectedLanguageKey = "detected_language"
+
 // STTService provides speech-to-text using Deepgram
 type STTService struct {
 	*processors.BaseProcessor
@@ -25,13 +46,29 @@ type STTService struct {
 	encoding          string
 	keepaliveInterval time.Duration
 	keepaliveTimeout  time.Duration
+	closeTimeout      time.Duration
+	baseURL           string
+	modelFallbacks    []string
+	detectLanguage    bool
+	keywords          []string
+	eagerInit         bool
+	encodingSet       bool // Encoding was set explicitly; don't override from StartFrame codec
 	conn              *websocket.Conn
+	releaseStream     func() // Frees the rate limiter stream slot held by conn
 	ctx               context.Context
 	cancel            context.CancelFunc
 	connMu            sync.Mutex // Protects concurrent WebSocket writes
 	readWG            sync.WaitGroup
-	connDropped       atomic.Bool // set on write failure; frames silently dropped until reconnect
+	connDropped       atomic.Bool   // set on write failure; frames silently dropped until reconnect
+	closing           atomic.Bool   // CloseStream sent; Deepgram closing the socket is expected
+	receiveDone       chan struct{} // Closed when receiveTranscriptions returns
 	log               *logger.Logger
+
+	// lastInterim holds the most recent interim transcript of the in-progress
+	// utterance. If a Finalize (sent on interruption) returns no final text,
+	// it is promoted to a final so the interrupting words are not lost.
+	lastInterim string
+	interimMu   sync.Mutex
 }
 
 // STTConfig holds configuration for Deepgram
@@ -42,8 +79,20 @@ type STTConfig struct {
 	Encoding          string        // Supported: "mulaw"/"ulaw", "alaw", "linear16" (default: "linear16")
 	KeepaliveInterval time.Duration // Interval for sending keepalive pings (default: 5s)
 	KeepaliveTimeout  time.Duration // Timeout for keepalive (default: 30s)
+	CloseTimeout      time.Duration // How long EndFrame waits for final results after CloseStream (default: 2s)
+	BaseURL           string        // WebSocket URL override (for testing); takes precedence over Region and Endpoint
+	Region            string        // API region from Regions, e.g. "eu" (default: "us")
+	Endpoint          string        // API host override, e.g. a self-hosted "deepgram.internal:8080"; takes precedence over Region
+	EagerInit         bool          // Connect on StartFrame instead of the first AudioFrame (default: false)
+	ModelFallbacks    []string      // Models to try in order if Deepgram rejects Model on connect (e.g., "nova-2", "base")
+	DetectLanguage    bool          // Transcribe multilingual speech (language=multi, nova-2/nova-3 only) instead of Language; the language is reported on each TranscriptionFrame
+	Keywords          []string      // Vocabulary to boost, as "word" or "word:boost" (e.g., "StrawGo:2"); sent as keyterms to nova-3, which ignores boosts
 }
 
+// eagerSilenceDuration is how much silence is sent right after an eager
+// connect so Deepgram's stream is running before the user starts speaking.
+const eagerSilenceDuration = 100 * time.Millisecond
+
 // NewSTTService creates a new Deepgram STT service
 func NewSTTService(config STTConfig) *STTService {
 	encoding := config.Encoding
@@ -64,6 +113,22 @@ func NewSTTService(config STTConfig) *STTService {
 		keepaliveTimeout = 30 * time.Second
 	}
 
+	closeTimeout := config.CloseTimeout
+	if closeTimeout == 0 {
+		closeTimeout = 2 * time.Second
+	}
+
+	log := logger.WithPrefix("DeepgramSTT")
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		host, err := Regions.Resolve(config.Region, config.Endpoint, DefaultHost)
+		if err != nil {
+			log.Warn("%v, using %s", err, DefaultHost)
+			host = DefaultHost
+		}
+		baseURL = services.EndpointURL("wss", host, "/v1/listen")
+	}
+
 	ds := &STTService{
 		apiKey:            config.APIKey,
 		language:          config.Language,
@@ -71,9 +136,20 @@ func NewSTTService(config STTConfig) *STTService {
 		encoding:          encoding,
 		keepaliveInterval: keepaliveInterval,
 		keepaliveTimeout:  keepaliveTimeout,
-		log:               logger.WithPrefix("DeepgramSTT"),
+		closeTimeout:      closeTimeout,
+		baseURL:           baseURL,
+		modelFallbacks:    config.ModelFallbacks,
+		detectLanguage:    config.DetectLanguage,
+		keywords:          config.Keywords,
+		eagerInit:         config.EagerInit,
+		encodingSet:       config.Encoding != "",
+		log:               log,
 	}
 	ds.BaseProcessor = processors.NewBaseProcessor("DeepgramSTT", ds)
+	ds.AttachLogger(ds.log)
+	if ds.detectLanguage && !supportsMultilingual(ds.model) {
+		ds.log.Warn("Model %q may not support multilingual streaming; use nova-2 or nova-3", ds.model)
+	}
 	return ds
 }
 
@@ -99,50 +175,174 @@ func (s *STTService) SetModel(model string) {
 	s.model = model
 }
 
-func (s *STTService) Initialize(ctx context.Context) error {
-	s.ctx, s.cancel = context.WithCancel(ctx)
-
-	// Determine sample rate based on encoding
-	sampleRate := "16000" // Default for linear16
+// sampleRate returns the input sample rate implied by the encoding
+func (s *STTService) sampleRate() int {
 	if s.encoding == "mulaw" || s.encoding == "ulaw" || s.encoding == "alaw" {
-		sampleRate = "8000" // Telephony codecs (mulaw/alaw) are typically 8kHz
+		return 8000 // Telephony codecs (mulaw/alaw) are typically 8kHz
 	}
+	return 16000 // Default for linear16
+}
 
-	// Build WebSocket URL
-	params := url.Values{}
-	params.Set("language", s.language)
-	params.Set("model", s.model)
-	params.Set("encoding", s.encoding)
-	params.Set("sample_rate", sampleRate)
-	params.Set("channels", "1")
-	params.Set("interim_results", "true")
+// silence returns d worth of silent audio in the configured encoding
+func (s *STTService) silence(d time.Duration) []byte {
+	if s.encoding == "linear16" {
+		return make([]byte, int(d.Seconds()*float64(s.sampleRate()))*2)
+	}
 
-	wsURL := fmt.Sprintf("wss://api.deepgram.com/v1/listen?%s", params.Encode())
+	fill := byte(0xFF) // mulaw silence
+	if s.encoding == "alaw" {
+		fill = 0xD5
+	}
+	data := make([]byte, int(d.Seconds()*float64(s.sampleRate())))
+	for i := range data {
+		data[i] = fill
+	}
+	return data
+}
 
-	// Connect to Deepgram
-	header := map[string][]string{
-		"Authorization": {fmt.Sprintf("Token %s", s.apiKey)},
+func (s *STTService) Initialize(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	if err := ValidateKeywords(s.keywords); err != nil {
+		return err
 	}
 
-	var err error
-	s.conn, _, err = websocket.DefaultDialer.Dial(wsURL, header)
+	// Hold a stream slot for the life of the connection
+	release, err := services.RateLimiterFor("deepgram", s.apiKey).Acquire(s.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Deepgram: %w", err)
 	}
 
+	// Walk the fallback chain while Deepgram rejects the model
+	models := append([]string{s.model}, s.modelFallbacks...)
+	var conn *websocket.Conn
+	for i, model := range models {
+		var resp *http.Response
+		conn, resp, err = s.dial(model)
+		if err == nil {
+			if model != s.model {
+				s.log.Info("Selected fallback model %q (requested %q)", model, s.model)
+				s.model = model
+			}
+			break
+		}
+		if i == len(models)-1 || !modelRejected(resp) {
+			release()
+			return fmt.Errorf("failed to connect to Deepgram: %w", err)
+		}
+		s.log.Warn("Deepgram rejected model %q, trying %q", model, models[i+1])
+	}
+
+	s.connMu.Lock()
+	s.conn = conn
+	s.releaseStream = release
+	done := make(chan struct{})
+	s.receiveDone = done
+	s.connMu.Unlock()
+
 	// Start receiving transcriptions
 	s.connDropped.Store(false)
-	conn := s.conn
+	s.closing.Store(false)
 	s.readWG.Add(2)
-	go s.receiveTranscriptions(conn)
+	go s.receiveTranscriptions(conn, done)
 
 	// Start keepalive task to prevent timeout
 	go s.keepaliveTask(conn)
 
-	s.log.Info("Connected and initialized")
+	s.log.Info("Connected and initialized (model %q)", s.model)
 	return nil
 }
 
+// dial opens a streaming connection with the given model
+func (s *STTService) dial(model string) (*websocket.Conn, *http.Response, error) {
+	params := url.Values{}
+	if s.detectLanguage {
+		// Streaming has no detect_language; multilingual transcription
+		// reports the languages spoken with each result instead
+		params.Set("language", "multi")
+	} else {
+		params.Set("language", s.language)
+	}
+	params.Set("model", model)
+	params.Set("encoding", s.encoding)
+	params.Set("sample_rate", fmt.Sprintf("%d", s.sampleRate()))
+	params.Set("channels", "1")
+	params.Set("interim_results", "true")
+	s.addKeywords(params, model)
+
+	wsURL := fmt.Sprintf("%s?%s", s.baseURL, params.Encode())
+	header := map[string][]string{
+		"Authorization": {fmt.Sprintf("Token %s", s.apiKey)},
+	}
+	return websocket.DefaultDialer.Dial(wsURL, header)
+}
+
+// supportsMultilingual reports whether model streams with language=multi
+func supportsMultilingual(model string) bool {
+	return strings.HasPrefix(model, "nova-2") || strings.HasPrefix(model, "nova-3")
+}
+
+// ValidateKeywords checks each keyword is a non-empty word with an optional
+// numeric boost, as in "word" or "word:boost"
+func ValidateKeywords(keywords []string) error {
+	for _, keyword := range keywords {
+		word, boost, hasBoost := splitKeyword(keyword)
+		if word == "" {
+			return fmt.Errorf("invalid keyword %q: empty word", keyword)
+		}
+		if hasBoost {
+			if _, err := strconv.ParseFloat(boost, 64); err != nil {
+				return fmt.Errorf("invalid keyword %q: boost must be a number", keyword)
+			}
+		}
+	}
+	return nil
+}
+
+// splitKeyword splits "word:boost" at its last colon
+func splitKeyword(keyword string) (word, boost string, hasBoost bool) {
+	i := strings.LastIndex(keyword, ":")
+	if i < 0 {
+		return strings.TrimSpace(keyword), "", false
+	}
+	return strings.TrimSpace(keyword[:i]), strings.TrimSpace(keyword[i+1:]), true
+}
+
+// addKeywords adds the boosted vocabulary in the form model supports: nova-3
+// only takes unweighted keyterm values, older models take keywords with
+// optional boosts
+func (s *STTService) addKeywords(params url.Values, model string) {
+	if len(s.keywords) == 0 {
+		return
+	}
+	if !strings.HasPrefix(model, "nova-3") {
+		params["keywords"] = s.keywords
+		return
+	}
+	for _, keyword := range s.keywords {
+		word, _, hasBoost := splitKeyword(keyword)
+		if hasBoost {
+			s.log.Warn("Model %q does not support keyword boosts, sending %q as a keyterm", model, word)
+		}
+		params.Add("keyterm", word)
+	}
+}
+
+// modelRejected reports whether a failed handshake was Deepgram refusing the
+// model (HTTP 400 mentioning the model, e.g. "No such model/language/tier
+// combination found"), as opposed to an auth or network failure
+func modelRejected(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	reason := resp.Header.Get("dg-error")
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		reason += string(body)
+	}
+	return strings.Contains(strings.ToLower(reason), "model")
+}
+
 func (s *STTService) Cleanup() error {
 	if s.cancel != nil {
 		s.cancel()
@@ -156,6 +356,8 @@ func (s *STTService) disconnect() {
 	s.connMu.Lock()
 	conn := s.conn
 	s.conn = nil
+	release := s.releaseStream
+	s.releaseStream = nil
 	s.connMu.Unlock()
 
 	if conn != nil {
@@ -163,19 +365,56 @@ func (s *STTService) disconnect() {
 	}
 
 	s.readWG.Wait()
+	if release != nil {
+		release()
+	}
+}
+
+// connected reports whether a WebSocket to Deepgram is open
+func (s *STTService) connected() bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	return s.conn != nil
 }
 
 func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// Pass StartFrame through without initializing (lazy initialization on first audio)
-	if _, ok := frame.(*frames.StartFrame); ok {
+	// Handle StartFrame - lazy initialization on first audio unless EagerInit is set.
+	// Without an explicit Encoding, wait for the StartFrame that carries the
+	// call's codec: the pipeline task's own StartFrame has none, and dialing
+	// on it would open the stream with the wrong encoding and sample rate.
+	if startFrame, ok := frame.(*frames.StartFrame); ok {
+		codec := startFrame.MediaCodec()
+		if s.eagerInit && !s.connected() && (s.encodingSet || codec != "") {
+			// Match the incoming codec if the user didn't pick an encoding
+			if !s.encodingSet {
+				s.encoding = normalizeDeepgramEncoding(codec)
+				s.log.Info("Detected incoming codec: %s", s.encoding)
+			}
+
+			s.log.Info("Eager initializing WebSocket on StartFrame")
+			if err := s.Initialize(ctx); err != nil {
+				s.log.Error("Failed to initialize: %v", err)
+				s.PushFrame(frames.NewErrorFrame(err), frames.Upstream)
+			} else {
+				// Prime the stream so the first spoken word isn't clipped
+				s.connMu.Lock()
+				err := s.conn.WriteMessage(websocket.BinaryMessage, s.silence(eagerSilenceDuration))
+				s.connMu.Unlock()
+				if err != nil {
+					s.log.Debug("Error sending priming silence: %v", err)
+				}
+			}
+		}
+
 		// Emit STT metadata for auto-tuning turn detection
 		s.PushFrame(frames.NewSTTMetadataFrame("deepgram", 300*time.Millisecond), frames.Downstream)
 		return s.PushFrame(frame, direction)
 	}
 
-	// Handle EndFrame - cleanup and close connection
+	// Handle EndFrame - flush the last utterance, then cleanup and close connection
 	if _, ok := frame.(*frames.EndFrame); ok {
 		s.log.Info("Received EndFrame, cleaning up")
+		s.closeStream()
 		if err := s.Cleanup(); err != nil {
 			s.log.Warn("Error during cleanup: %v", err)
 		}
@@ -183,8 +422,14 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	}
 
 	// Handle InterruptionFrame - send finalize to reset Deepgram stream
-	// This prevents old transcription fragments from arriving after interruption
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	// This prevents old transcription fragments from arriving after interruption.
+	// A user barge-in is the exception: the user is mid-utterance, so the stream
+	// stays live and keeps transcribing their new speech.
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
+		if interruption.IsUserSpeech() {
+			s.log.Debug("Interrupted by user speech, keeping stream live")
+			return s.PushFrame(frame, direction)
+		}
 		s.log.Info("Received InterruptionFrame, sending finalize to reset stream")
 		if s.conn != nil {
 			// Send finalize message to tell Deepgram to flush current utterance
@@ -202,6 +447,7 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 				s.log.Debug("Sent finalize message to reset STT stream")
 			}
 		}
+		s.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageSTTFinalized)
 		// Pass the interruption frame downstream
 		return s.PushFrame(frame, direction)
 	}
@@ -209,7 +455,7 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	// Process audio frames
 	if audioFrame, ok := frame.(*frames.AudioFrame); ok {
 		// Lazy initialization on first audio frame
-		if s.conn == nil {
+		if !s.connected() {
 			s.log.Info("Lazy initializing on first AudioFrame")
 			if err := s.Initialize(ctx); err != nil {
 				s.log.Error("Failed to initialize: %v", err)
@@ -248,8 +494,41 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	return s.PushFrame(frame, direction)
 }
 
-func (s *STTService) receiveTranscriptions(conn *websocket.Conn) {
+// closeStream sends Deepgram's CloseStream so it finalizes any audio still in
+// flight, then waits up to closeTimeout for the final results and the
+// server's close. Deepgram closes the socket once everything is sent, which
+// ends receiveTranscriptions.
+func (s *STTService) closeStream() {
+	s.connMu.Lock()
+	conn := s.conn
+	done := s.receiveDone
+	if conn == nil || s.connDropped.Load() {
+		s.connMu.Unlock()
+		return
+	}
+	// Stop audio and keepalives; Deepgram rejects anything after CloseStream
+	s.connDropped.Store(true)
+	s.closing.Store(true)
+	err := conn.WriteJSON(map[string]string{"type": "CloseStream"})
+	s.connMu.Unlock()
+
+	if err != nil {
+		s.log.Debug("Error sending CloseStream: %v", err)
+		return
+	}
+	s.log.Debug("Sent CloseStream, waiting for final results")
+
+	select {
+	case <-done:
+		s.log.Debug("Deepgram closed the stream")
+	case <-time.After(s.closeTimeout):
+		s.log.Warn("Timed out after %v waiting for Deepgram to close the stream", s.closeTimeout)
+	}
+}
+
+func (s *STTService) receiveTranscriptions(conn *websocket.Conn, done chan struct{}) {
 	defer s.readWG.Done()
+	defer close(done)
 
 	for {
 		select {
@@ -260,7 +539,8 @@ func (s *STTService) receiveTranscriptions(conn *websocket.Conn) {
 			_, message, err := conn.ReadMessage()
 			if err != nil {
 				// Check if this is a normal closure during shutdown
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) ||
+				if s.closing.Load() ||
+					websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) ||
 					strings.Contains(err.Error(), "use of closed network connection") {
 					s.log.Debug("Connection closed normally")
 					return
@@ -272,11 +552,19 @@ func (s *STTService) receiveTranscriptions(conn *websocket.Conn) {
 
 			// Parse Deepgram response
 			var response struct {
-				IsFinal bool `json:"is_final"`
-				Channel struct {
+				IsFinal      bool    `json:"is_final"`
+				SpeechFinal  bool    `json:"speech_final"`
+				FromFinalize bool    `json:"from_finalize"`
+				Start        float64 `json:"start"`
+				Duration     float64 `json:"duration"`
+				Channel      struct {
 					Alternatives []struct {
-						Transcript string  `json:"transcript"`
-						Confidence float64 `json:"confidence"`
+						Transcript string   `json:"transcript"`
+						Confidence float64  `json:"confidence"`
+						Languages  []string `json:"languages"` // Multilingual only, most words first
+						Words      []struct {
+							Language string `json:"language"`
+						} `json:"words"`
 					} `json:"alternatives"`
 				} `json:"channel"`
 			}
@@ -286,19 +574,54 @@ func (s *STTService) receiveTranscriptions(conn *websocket.Conn) {
 				continue
 			}
 
-			// Extract transcript
+			// Extract transcript and, with multilingual on, its main language
+			transcript := ""
+			language := ""
 			if len(response.Channel.Alternatives) > 0 {
-				transcript := response.Channel.Alternatives[0].Transcript
-				if transcript != "" {
-					transcriptionFrame := frames.NewTranscriptionFrame(transcript, response.IsFinal)
-					s.log.Debug("Transcription (final=%v): %s", response.IsFinal, transcript)
-					s.PushFrame(transcriptionFrame, frames.Downstream)
+				alternative := response.Channel.Alternatives[0]
+				transcript = alternative.Transcript
+				if len(alternative.Languages) > 0 {
+					language = alternative.Languages[0]
+				} else if len(alternative.Words) > 0 {
+					language = alternative.Words[0].Language
 				}
 			}
+
+			s.interimMu.Lock()
+			if response.IsFinal {
+				// A finalize response with no text would discard the in-progress
+				// utterance; fall back to the last interim we saw for it.
+				if transcript == "" && response.FromFinalize && s.lastInterim != "" {
+					s.log.Debug("Finalize returned no text, promoting last interim: %s", s.lastInterim)
+					transcript = s.lastInterim
+				}
+				s.lastInterim = ""
+			} else if transcript != "" {
+				s.lastInterim = transcript
+			}
+			s.interimMu.Unlock()
+
+			if transcript != "" {
+				transcriptionFrame := frames.NewTranscriptionFrame(transcript, response.IsFinal)
+				transcriptionFrame.AudioStart = secondsToDuration(response.Start)
+				transcriptionFrame.AudioDuration = secondsToDuration(response.Duration)
+				transcriptionFrame.SpeechFinal = response.SpeechFinal
+				if s.detectLanguage && language != "" {
+					transcriptionFrame.Language = language
+					transcriptionFrame.SetMetadata(DetectedLanguageKey, language)
+				}
+				s.log.Debug("Transcription (final=%v, lang=%s): %s", response.IsFinal, language, transcript)
+				s.PushFrame(transcriptionFrame, frames.Downstream)
+			}
 		}
 	}
 }
 
+// secondsToDuration converts Deepgram's fractional-second offsets
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
 func (s *STTService) keepaliveTask(conn *websocket.Conn) {
 	defer s.readWG.Done()
 
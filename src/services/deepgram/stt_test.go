@@ -2,8 +2,21 @@ package deepgram
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
 func TestNewDeepgramSTTService(t *testing.T) {
@@ -160,3 +173,873 @@ func TestDeepgramSTT_ErrorPropagation(t *testing.T) {
 		t.Error("Expected Initialize to return an error for invalid API key")
 	}
 }
+
+// mockCollector captures frames pushed by the service for test assertions
+type mockCollector struct {
+	*processors.BaseProcessor
+	mu     sync.Mutex
+	frames []frames.Frame
+}
+
+func newMockCollector() *mockCollector {
+	c := &mockCollector{
+		frames: make([]frames.Frame, 0),
+	}
+	c.BaseProcessor = processors.NewBaseProcessor("MockCollector", c)
+	return c
+}
+
+func (c *mockCollector) HandleFrame(_ context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	c.mu.Lock()
+	c.frames = append(c.frames, frame)
+	c.mu.Unlock()
+	return c.PushFrame(frame, direction)
+}
+
+func (c *mockCollector) transcriptions() []*frames.TranscriptionFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []*frames.TranscriptionFrame
+	for _, f := range c.frames {
+		if tf, ok := f.(*frames.TranscriptionFrame); ok {
+			result = append(result, tf)
+		}
+	}
+	return result
+}
+
+// startMockWSServer creates a mock WebSocket server for testing.
+// handler receives the upgraded connection for custom behavior.
+func startMockWSServer(t *testing.T, handler func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("Mock server upgrade error: %v", err)
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	return server
+}
+
+// wsURL converts an HTTP test server URL to a WebSocket URL
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// deepgramResult builds a Deepgram streaming result message
+func deepgramResult(transcript string, isFinal, fromFinalize bool) map[string]interface{} {
+	return map[string]interface{}{
+		"type":          "Results",
+		"is_final":      isFinal,
+		"from_finalize": fromFinalize,
+		"channel": map[string]interface{}{
+			"alternatives": []map[string]interface{}{
+				{"transcript": transcript, "confidence": 0.9},
+			},
+		},
+	}
+}
+
+// waitForFinal polls the collector for a final transcription
+func waitForFinal(t *testing.T, collector *mockCollector) *frames.TranscriptionFrame {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, tf := range collector.transcriptions() {
+			if tf.IsFinal {
+				return tf
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for final transcription")
+	return nil
+}
+
+// runFinalizeScenario sends audio, waits for the interim, interrupts, and
+// answers the Finalize with finalizeReply.
+func runFinalizeScenario(t *testing.T, finalizeReply map[string]interface{}) *frames.TranscriptionFrame {
+	t.Helper()
+
+	server := startMockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				conn.WriteJSON(deepgramResult("wait I", false, false))
+				continue
+			}
+			var msg map[string]interface{}
+			if json.Unmarshal(data, &msg) == nil && msg["type"] == "Finalize" {
+				conn.WriteJSON(finalizeReply)
+			}
+		}
+	})
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:  "test-key",
+		BaseURL: wsURL(server),
+	})
+	collector := newMockCollector()
+	service.Link(collector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("Failed to start service: %v", err)
+	}
+	if err := collector.Start(ctx); err != nil {
+		t.Fatalf("Failed to start collector: %v", err)
+	}
+	defer service.Cleanup()
+
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(collector.transcriptions()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for interim transcription")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	service.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	return waitForFinal(t, collector)
+}
+
+func TestDeepgramSTT_FinalizeReturnsFinal(t *testing.T) {
+	final := runFinalizeScenario(t, deepgramResult("wait I have a question", true, true))
+	if final.Text != "wait I have a question" {
+		t.Errorf("Expected final 'wait I have a question', got %q", final.Text)
+	}
+}
+
+func TestDeepgramSTT_EmptyFinalizePromotesInterim(t *testing.T) {
+	final := runFinalizeScenario(t, deepgramResult("", true, true))
+	if final.Text != "wait I" {
+		t.Errorf("Expected last interim 'wait I' to be promoted, got %q", final.Text)
+	}
+}
+
+func TestDeepgramSTT_UserSpeechInterruptionSkipsFinalize(t *testing.T) {
+	finalizes := make(chan struct{}, 4)
+	audio := make(chan struct{}, 16)
+	server := startMockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				audio <- struct{}{}
+				continue
+			}
+			var msg map[string]interface{}
+			if json.Unmarshal(data, &msg) == nil && msg["type"] == "Finalize" {
+				finalizes <- struct{}{}
+			}
+		}
+	})
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{APIKey: "test-key", BaseURL: wsURL(server)})
+	collector := newMockCollector()
+	service.Link(collector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := collector.Start(ctx); err != nil {
+		t.Fatalf("Failed to start collector: %v", err)
+	}
+	if err := service.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer service.Cleanup()
+
+	// Barge-in: the user keeps talking, so the stream must stay live
+	service.HandleFrame(ctx, frames.NewInterruptionFrameWithCause(frames.InterruptionCauseUserSpeech), frames.Downstream)
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+	select {
+	case <-audio:
+	case <-time.After(time.Second):
+		t.Fatal("Expected audio after a user-speech interruption to reach Deepgram")
+	}
+	select {
+	case <-finalizes:
+		t.Fatal("Expected no Finalize for a user-speech interruption")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Other interruptions still finalize
+	service.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+	select {
+	case <-finalizes:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Finalize for a non-speech interruption")
+	}
+}
+
+// startSlowHandshakeServer records binary payloads received and counts
+// connections. Each handshake is delayed to make dial latency observable.
+func startSlowHandshakeServer(t *testing.T, delay time.Duration, dials *atomic.Int32, received chan<- []byte) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dials.Add(1)
+		time.Sleep(delay)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("Mock server upgrade error: %v", err)
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				received <- data
+			}
+		}
+	}))
+}
+
+func TestDeepgramSTT_EagerInitDialsOnStartFrame(t *testing.T) {
+	const handshakeDelay = 200 * time.Millisecond
+
+	var dials atomic.Int32
+	received := make(chan []byte, 16)
+	server := startSlowHandshakeServer(t, handshakeDelay, &dials, received)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:    "test-key",
+		BaseURL:   wsURL(server),
+		EagerInit: true,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	defer service.Cleanup()
+
+	startFrame := frames.NewStartFrame()
+	startFrame.SetMetadata("codec", "mulaw")
+	if err := service.HandleFrame(ctx, startFrame, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) failed: %v", err)
+	}
+
+	if dials.Load() != 1 {
+		t.Fatalf("Expected WebSocket to be dialed on StartFrame, got %d dials", dials.Load())
+	}
+	if service.encoding != "mulaw" {
+		t.Errorf("Expected encoding adopted from StartFrame codec, got %s", service.encoding)
+	}
+
+	select {
+	case silence := <-received:
+		if len(silence) != 800 || silence[0] != 0xFF {
+			t.Errorf("Expected 100ms of mulaw silence, got %d bytes starting 0x%X", len(silence), silence[0])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for priming silence")
+	}
+
+	// The first real audio must go straight out on the warm connection
+	audio := []byte{0x10, 0x20, 0x30, 0x40}
+	start := time.Now()
+	if err := service.HandleFrame(ctx, frames.NewAudioFrame(audio, 8000, 1), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(AudioFrame) failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= handshakeDelay {
+		t.Errorf("Expected first audio to skip connection setup, took %v", elapsed)
+	}
+
+	select {
+	case data := <-received:
+		if string(data) != string(audio) {
+			t.Errorf("Expected first audio payload %v, got %v", audio, data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for first audio")
+	}
+	if dials.Load() != 1 {
+		t.Errorf("Expected a single connection, got %d dials", dials.Load())
+	}
+}
+
+func TestDeepgramSTT_LazyInitByDefault(t *testing.T) {
+	var dials atomic.Int32
+	received := make(chan []byte, 16)
+	server := startSlowHandshakeServer(t, 0, &dials, received)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:  "test-key",
+		BaseURL: wsURL(server),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	defer service.Cleanup()
+
+	if err := service.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) failed: %v", err)
+	}
+	if dials.Load() != 0 || service.conn != nil {
+		t.Fatalf("Expected no connection before first audio, got %d dials", dials.Load())
+	}
+
+	if err := service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(AudioFrame) failed: %v", err)
+	}
+	if dials.Load() != 1 {
+		t.Errorf("Expected connection on first audio, got %d dials", dials.Load())
+	}
+}
+
+func TestDeepgramSTT_RegisteredByName(t *testing.T) {
+	service, err := services.BuildSTT("deepgram", services.ServiceConfig{
+		services.ConfigAPIKey:   "test-key",
+		services.ConfigModel:    "nova-3",
+		services.ConfigEncoding: "mulaw",
+	})
+	if err != nil {
+		t.Fatalf("BuildSTT failed: %v", err)
+	}
+	stt, ok := service.(*STTService)
+	if !ok {
+		t.Fatalf("Expected *STTService, got %T", service)
+	}
+	if stt.model != "nova-3" || stt.encoding != "mulaw" {
+		t.Errorf("Config not applied: model=%q encoding=%q", stt.model, stt.encoding)
+	}
+
+	if _, err := services.BuildSTT("deepgram", nil); err == nil {
+		t.Error("Expected error without an API key")
+	}
+}
+
+// startModelCheckServer rejects handshakes for any model but accepted, the way
+// Deepgram does, recording each requested model
+func startModelCheckServer(t *testing.T, accepted string, requested chan<- string) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		model := r.URL.Query().Get("model")
+		requested <- model
+		if model != accepted {
+			w.Header().Set("dg-error", "No such model/language/tier combination found")
+			http.Error(w, `{"err_code":"Bad Request","err_msg":"No such model/language/tier combination found."}`, http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestDeepgramSTT_ModelFallback(t *testing.T) {
+	requested := make(chan string, 4)
+	server := startModelCheckServer(t, "nova-2", requested)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:         "test-key",
+		Model:          "nova-3-preview",
+		ModelFallbacks: []string{"nova-2", "base"},
+		BaseURL:        wsURL(server),
+	})
+	defer service.Cleanup()
+
+	if err := service.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed, expected fallback to connect: %v", err)
+	}
+	if service.conn == nil {
+		t.Fatal("Expected a connection on the fallback model")
+	}
+	if service.model != "nova-2" {
+		t.Errorf("Expected fallback model nova-2 to be selected, got %q", service.model)
+	}
+
+	close(requested)
+	var got []string
+	for model := range requested {
+		got = append(got, model)
+	}
+	if len(got) != 2 || got[0] != "nova-3-preview" || got[1] != "nova-2" {
+		t.Errorf("Expected nova-3-preview then nova-2, got %v", got)
+	}
+}
+
+func TestDeepgramSTT_ModelFallbackExhausted(t *testing.T) {
+	requested := make(chan string, 4)
+	server := startModelCheckServer(t, "nova-2", requested)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:         "test-key",
+		Model:          "bogus",
+		ModelFallbacks: []string{"also-bogus"},
+		BaseURL:        wsURL(server),
+	})
+	defer service.Cleanup()
+
+	if err := service.Initialize(context.Background()); err == nil {
+		t.Fatal("Expected Initialize to fail when every model is rejected")
+	}
+	if len(requested) != 2 {
+		t.Errorf("Expected both models to be tried, got %d dials", len(requested))
+	}
+}
+
+func TestDeepgramSTT_NoFallbackOnAuthFailure(t *testing.T) {
+	var dials atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dials.Add(1)
+		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:         "bad-key",
+		Model:          "nova-3",
+		ModelFallbacks: []string{"nova-2"},
+		BaseURL:        wsURL(server),
+	})
+	defer service.Cleanup()
+
+	if err := service.Initialize(context.Background()); err == nil {
+		t.Fatal("Expected Initialize to fail on auth error")
+	}
+	if dials.Load() != 1 {
+		t.Errorf("Expected no fallback attempt on auth failure, got %d dials", dials.Load())
+	}
+}
+
+func TestDeepgramSTT_StreamCapBlocksExtraStreams(t *testing.T) {
+	services.SetRateLimit("deepgram", services.RateLimitConfig{MaxConcurrent: 1, MaxWait: 50 * time.Millisecond})
+	t.Cleanup(func() { services.SetRateLimit("deepgram", services.RateLimitConfig{}) })
+
+	server := startMockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	newService := func() *STTService {
+		return NewSTTService(STTConfig{APIKey: "capped-key", BaseURL: wsURL(server)})
+	}
+
+	first := newService()
+	if err := first.Initialize(context.Background()); err != nil {
+		t.Fatalf("first Initialize failed: %v", err)
+	}
+
+	second := newService()
+	defer second.Cleanup()
+	if err := second.Initialize(context.Background()); !errors.Is(err, services.ErrRateLimited) {
+		t.Fatalf("Expected second stream to be rate limited, got %v", err)
+	}
+
+	// Closing the first stream frees its slot
+	first.Cleanup()
+	if err := second.Initialize(context.Background()); err != nil {
+		t.Fatalf("Expected stream after release to connect, got %v", err)
+	}
+}
+
+// startQueryCaptureServer records each connection's query string, then
+// replies to the first audio message with reply
+func startQueryCaptureServer(t *testing.T, queries chan<- url.Values, reply map[string]interface{}) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage && reply != nil {
+				conn.WriteJSON(reply)
+			}
+		}
+	}))
+}
+
+func TestDeepgramSTT_LanguageDetectionQuery(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   STTConfig
+		language string
+	}{
+		{"disabled", STTConfig{Language: "en-US", Model: "nova-3"}, "en-US"},
+		{"multilingual", STTConfig{Language: "en-US", Model: "nova-3", DetectLanguage: true}, "multi"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			queries := make(chan url.Values, 1)
+			server := startQueryCaptureServer(t, queries, nil)
+			defer server.Close()
+
+			tc.config.APIKey = "test-key"
+			tc.config.BaseURL = wsURL(server)
+			service := NewSTTService(tc.config)
+			defer service.Cleanup()
+			if err := service.Initialize(context.Background()); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			query := <-queries
+			if got := query.Get("language"); got != tc.language {
+				t.Errorf("Expected language %q, got %q", tc.language, got)
+			}
+			// Streaming rejects detect_language
+			if query.Has("detect_language") {
+				t.Errorf("Expected no detect_language param, got query %v", query)
+			}
+		})
+	}
+}
+
+func TestDeepgramSTT_KeywordsQuery(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		model    string
+		keywords []string
+		param    string
+		want     []string
+	}{
+		{"boosted keywords", "nova-2", []string{"StrawGo:2", "Twilio", "acme corp:-1.5"}, "keywords", []string{"StrawGo:2", "Twilio", "acme corp:-1.5"}},
+		{"nova-3 keyterms", "nova-3", []string{"StrawGo:2", "Twilio"}, "keyterm", []string{"StrawGo", "Twilio"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			queries := make(chan url.Values, 1)
+			server := startQueryCaptureServer(t, queries, nil)
+			defer server.Close()
+
+			service := NewSTTService(STTConfig{
+				APIKey:   "test-key",
+				Model:    tc.model,
+				Keywords: tc.keywords,
+				BaseURL:  wsURL(server),
+			})
+			defer service.Cleanup()
+			if err := service.Initialize(context.Background()); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			query := <-queries
+			if got := query[tc.param]; strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("Expected %s %v, got %v", tc.param, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDeepgramSTT_InvalidKeywordBoost(t *testing.T) {
+	service := NewSTTService(STTConfig{APIKey: "test-key", Keywords: []string{"StrawGo:high"}})
+	defer service.Cleanup()
+	if err := service.Initialize(context.Background()); err == nil || !strings.Contains(err.Error(), "StrawGo:high") {
+		t.Errorf("Expected an invalid keyword error, got %v", err)
+	}
+
+	_, err := services.BuildSTT("deepgram", services.ServiceConfig{
+		services.ConfigAPIKey: "test-key",
+		"keywords":            ":3",
+	})
+	if err == nil {
+		t.Error("Expected the registry to reject a keyword without a word")
+	}
+}
+
+func TestDeepgramSTT_DetectedLanguageMetadata(t *testing.T) {
+	reply := deepgramResult("hola, necesito ayuda", true, false)
+	reply["channel"].(map[string]interface{})["alternatives"].([]map[string]interface{})[0]["languages"] = []string{"es"}
+
+	queries := make(chan url.Values, 1)
+	server := startQueryCaptureServer(t, queries, reply)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:         "test-key",
+		BaseURL:        wsURL(server),
+		DetectLanguage: true,
+	})
+	collector := newMockCollector()
+	service.Link(collector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("Failed to start service: %v", err)
+	}
+	if err := collector.Start(ctx); err != nil {
+		t.Fatalf("Failed to start collector: %v", err)
+	}
+	defer service.Cleanup()
+
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+	final := waitForFinal(t, collector)
+
+	if final.Language != "es" {
+		t.Errorf("Expected Language 'es', got %q", final.Language)
+	}
+	if got := final.Metadata()[DetectedLanguageKey]; got != "es" {
+		t.Errorf("Expected %s metadata 'es', got %v", DetectedLanguageKey, got)
+	}
+}
+
+func TestDeepgramSTT_SegmentTiming(t *testing.T) {
+	reply := deepgramResult("book a table", true, false)
+	reply["speech_final"] = true
+	reply["start"] = 1.25
+	reply["duration"] = 0.5
+
+	queries := make(chan url.Values, 1)
+	server := startQueryCaptureServer(t, queries, reply)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{APIKey: "test-key", BaseURL: wsURL(server)})
+	collector := newMockCollector()
+	service.Link(collector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("Failed to start service: %v", err)
+	}
+	if err := collector.Start(ctx); err != nil {
+		t.Fatalf("Failed to start collector: %v", err)
+	}
+	defer service.Cleanup()
+
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+	final := waitForFinal(t, collector)
+
+	if final.AudioStart != 1250*time.Millisecond || final.AudioDuration != 500*time.Millisecond {
+		t.Errorf("Expected segment at 1.25s for 0.5s, got %v for %v", final.AudioStart, final.AudioDuration)
+	}
+	if !final.SpeechFinal {
+		t.Error("Expected SpeechFinal to be set")
+	}
+}
+
+// startCloseStreamServer answers audio with an interim and, when reply is set,
+// answers CloseStream with reply before closing the socket. Received control
+// message types are sent on msgs.
+func startCloseStreamServer(t *testing.T, msgs chan<- string, reply map[string]interface{}) *httptest.Server {
+	return startMockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msgType == websocket.BinaryMessage {
+				conn.WriteJSON(deepgramResult("see you", false, false))
+				continue
+			}
+			var msg map[string]interface{}
+			if json.Unmarshal(data, &msg) != nil {
+				continue
+			}
+			msgs <- msg["type"].(string)
+			if msg["type"] == "CloseStream" && reply != nil {
+				conn.WriteJSON(reply)
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+		}
+	})
+}
+
+// pushRecorder records the frames a processor pushes, in push order
+type pushRecorder struct {
+	mu     sync.Mutex
+	pushed []frames.Frame
+}
+
+func (r *pushRecorder) OnProcessFrame(string, frames.Frame, frames.FrameDirection) {}
+
+func (r *pushRecorder) OnPushFrame(_ string, frame frames.Frame, _ frames.FrameDirection) {
+	r.mu.Lock()
+	r.pushed = append(r.pushed, frame)
+	r.mu.Unlock()
+}
+
+func TestDeepgramSTT_EndFrameSendsCloseStream(t *testing.T) {
+	msgs := make(chan string, 8)
+	server := startCloseStreamServer(t, msgs, deepgramResult("see you tomorrow", true, false))
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{APIKey: "test-key", BaseURL: wsURL(server)})
+	collector := newMockCollector()
+	service.Link(collector)
+	upstream := newMockCollector()
+	service.SetPrev(upstream)
+	recorder := &pushRecorder{}
+	service.SetObserver(recorder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	for _, p := range []processors.FrameProcessor{service, collector, upstream} {
+		if err := p.Start(ctx); err != nil {
+			t.Fatalf("Failed to start %s: %v", p.Name(), err)
+		}
+	}
+	defer service.Cleanup()
+
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+	if err := service.HandleFrame(ctx, frames.NewEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(EndFrame) failed: %v", err)
+	}
+
+	select {
+	case msgType := <-msgs:
+		if msgType != "CloseStream" {
+			t.Fatalf("Expected CloseStream, got %q", msgType)
+		}
+	default:
+		t.Fatal("Expected CloseStream to be sent before EndFrame returned")
+	}
+
+	// The final transcript is pushed before the EndFrame, and Deepgram
+	// closing the socket afterwards is not an error
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	finalAt, endAt := -1, -1
+	for i, f := range recorder.pushed {
+		switch f := f.(type) {
+		case *frames.TranscriptionFrame:
+			if f.IsFinal && f.Text == "see you tomorrow" {
+				finalAt = i
+			}
+		case *frames.EndFrame:
+			endAt = i
+		case *frames.ErrorFrame:
+			t.Errorf("Expected no ErrorFrame after CloseStream, got %v", f.Error)
+		}
+	}
+	if finalAt < 0 || endAt < 0 || finalAt > endAt {
+		t.Errorf("Expected the final transcript before the EndFrame (final at %d, end at %d)", finalAt, endAt)
+	}
+}
+
+func TestDeepgramSTT_CloseStreamTimeout(t *testing.T) {
+	msgs := make(chan string, 8)
+	server := startCloseStreamServer(t, msgs, nil)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:       "test-key",
+		BaseURL:      wsURL(server),
+		CloseTimeout: 100 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("Failed to start service: %v", err)
+	}
+	defer service.Cleanup()
+
+	service.HandleFrame(ctx, frames.NewAudioFrame([]byte{0x00, 0x01}, 16000, 1), frames.Downstream)
+
+	start := time.Now()
+	if err := service.HandleFrame(ctx, frames.NewEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(EndFrame) failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected EndFrame to give up after CloseTimeout, took %v", elapsed)
+	}
+	if msgType := <-msgs; msgType != "CloseStream" {
+		t.Errorf("Expected CloseStream, got %q", msgType)
+	}
+}
+
+func TestDeepgramSTT_RegionAndEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  STTConfig
+		want string
+	}{
+		{"default", STTConfig{}, DefaultBaseURL},
+		{"eu region", STTConfig{Region: "eu"}, "wss://api.eu.deepgram.com/v1/listen"},
+		{"endpoint", STTConfig{Region: "eu", Endpoint: "deepgram.internal:8080"}, "wss://deepgram.internal:8080/v1/listen"},
+		{"unknown region falls back", STTConfig{Region: "mars"}, DefaultBaseURL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.APIKey = "test-key"
+			if s := NewSTTService(tc.cfg); s.baseURL != tc.want {
+				t.Errorf("baseURL = %q, want %q", s.baseURL, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeepgramSTT_EagerInitWaitsForCodecStartFrame(t *testing.T) {
+	queries := make(chan url.Values, 2)
+	server := startQueryCaptureServer(t, queries, nil)
+	defer server.Close()
+
+	service := NewSTTService(STTConfig{
+		APIKey:    "test-key",
+		BaseURL:   wsURL(server),
+		EagerInit: true,
+	})
+	defer service.Cleanup()
+	ctx := context.Background()
+
+	// The pipeline task's StartFrame carries no codec and must not dial
+	if err := service.HandleFrame(ctx, frames.NewStartFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(task StartFrame) failed: %v", err)
+	}
+	select {
+	case query := <-queries:
+		t.Fatalf("Expected no dial before the codec is known, dialed with %v", query)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The transport's per-call StartFrame does
+	callStart := frames.NewStartFrame()
+	callStart.SetMetadata("codec", "mulaw")
+	if err := service.HandleFrame(ctx, callStart, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(call StartFrame) failed: %v", err)
+	}
+	select {
+	case query := <-queries:
+		if query.Get("encoding") != "mulaw" || query.Get("sample_rate") != "8000" {
+			t.Errorf("Expected a mulaw/8000 stream, dialed with encoding=%s sample_rate=%s",
+				query.Get("encoding"), query.Get("sample_rate"))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a dial on the StartFrame carrying the codec")
+	}
+}
@@ -3,6 +3,7 @@ package deepgram
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -46,6 +47,7 @@ type TTSService struct {
 	model      string
 	encoding   string
 	sampleRate int
+	speakURL   string
 
 	// WebSocket connection
 	conn   *websocket.Conn
@@ -68,6 +70,9 @@ type TTSService struct {
 	ttfbStart    time.Time
 	ttfbRecorded bool
 	log          *logger.Logger
+
+	// Converts provider audio to the configured output format (nil = none)
+	outputConverter *services.TTSOutputConverter
 }
 
 // TTSConfig holds configuration for Deepgram TTS
@@ -76,6 +81,15 @@ type TTSConfig struct {
 	Model      string // e.g., "aura-asteria-en", "aura-luna-en", "aura-stella-en"
 	Encoding   string // e.g., "linear16", "mulaw", "alaw" (default: "linear16")
 	SampleRate int    // e.g., 8000, 16000, 24000, 48000 (default: 16000)
+	Region     string // API region from Regions, e.g. "eu" (default: "us")
+	Endpoint   string // API host override; takes precedence over Region
+
+	// OutputSampleRate and OutputCodec ("mulaw", "alaw", "linear16") convert
+	// the provider's audio to the transport's format inside the service, e.g.
+	// to 8kHz mulaw for telephony. Either may be left unset to keep the
+	// provider's. Default: no conversion.
+	OutputSampleRate int
+	OutputCodec      string
 }
 
 // NewTTSService creates a new Deepgram TTS service
@@ -96,14 +110,25 @@ func NewTTSService(config TTSConfig) *TTSService {
 		sampleRate = DefaultTTSSampleRate
 	}
 
+	log := logger.WithPrefix("DeepgramTTS")
+	host, err := Regions.Resolve(config.Region, config.Endpoint, DefaultHost)
+	if err != nil {
+		log.Warn("%v, using %s", err, DefaultHost)
+		host = DefaultHost
+	}
+
 	ds := &TTSService{
 		apiKey:     config.APIKey,
 		model:      model,
 		encoding:   encoding,
 		sampleRate: sampleRate,
-		log:        logger.WithPrefix("DeepgramTTS"),
+		speakURL:   services.EndpointURL("wss", host, "/v1/speak"),
+		log:        log,
+
+		outputConverter: services.NewTTSOutputConverter(config.OutputSampleRate, config.OutputCodec),
 	}
 	ds.BaseProcessor = processors.NewBaseProcessor("DeepgramTTS", ds)
+	ds.AttachLogger(ds.log)
 	return ds
 }
 
@@ -116,11 +141,16 @@ func (s *TTSService) SetModel(model string) {
 	s.model = model
 }
 
+// OutputFormat implements processors.AudioFormatProducer
+func (s *TTSService) OutputFormat() processors.AudioFormat {
+	return s.outputConverter.Format(processors.AudioFormat{SampleRate: s.sampleRate, Codec: s.encodingToCodec()})
+}
+
 func (s *TTSService) Initialize(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
 	// Build WebSocket URL with query parameters
-	u, err := url.Parse(DeepgramTTSURL)
+	u, err := url.Parse(s.speakURL)
 	if err != nil {
 		return fmt.Errorf("failed to parse URL: %w", err)
 	}
@@ -208,7 +238,7 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	}
 
 	// Handle InterruptionFrame - stop synthesis and reset state
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
 		s.log.Info("============================================")
 		s.log.Info("INTERRUPTION RECEIVED")
 		s.log.Info("============================================")
@@ -243,11 +273,37 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewTTSStoppedFrame(), frames.Upstream)
 		}
 
+		s.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageTTSCancelled)
 		s.log.Debug("Interruption complete, passing frame downstream")
 
 		return s.PushFrame(frame, direction)
 	}
 
+	// Handle SetVoiceFrame - Deepgram selects the voice via the model in the
+	// connection URL, so reconnect lazily on the next text when it changes
+	if setVoice, ok := frame.(*frames.SetVoiceFrame); ok {
+		newModel := setVoice.Model
+		if setVoice.VoiceID != "" {
+			newModel = setVoice.VoiceID
+		}
+		if newModel != "" && newModel != s.model {
+			s.mu.Lock()
+			s.isSpeaking = false
+			s.contextID = ""
+			s.currentTurnContextID = ""
+			s.mu.Unlock()
+			if s.conn != nil {
+				if err := s.Cleanup(); err != nil {
+					s.log.Warn("Error closing connection for voice change: %v", err)
+				}
+				s.ctx = nil
+			}
+			s.model = newModel
+			s.log.Info("Voice changed (model=%s)", s.model)
+		}
+		return s.PushFrame(frame, direction)
+	}
+
 	// Process text frames (LLM output)
 	if textFrame, ok := frame.(*frames.TextFrame); ok {
 		if textFrame.SkipTTS {
@@ -326,6 +382,18 @@ func (s *TTSService) synthesizeText(text string) error {
 		return nil
 	}
 
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := services.RateLimiterFor("deepgram", s.apiKey).Wait(ctx); err != nil {
+		if errors.Is(err, services.ErrRateLimited) {
+			s.log.Warn("Dropping text, %v", err)
+			return s.PushFrame(frames.NewErrorFrame(fmt.Errorf("Deepgram: %w", err)), frames.Upstream)
+		}
+		return err
+	}
+
 	// Use current turn context ID if available, otherwise generate new one
 	s.mu.Lock()
 	if s.contextID == "" {
@@ -429,6 +497,9 @@ func (s *TTSService) receiveAudio() {
 				audioFrame := frames.NewTTSAudioFrame(message, s.sampleRate, 1)
 				audioFrame.SetMetadata("codec", codec)
 				audioFrame.SetMetadata("context_id", contextID)
+				if err := s.outputConverter.Convert(audioFrame); err != nil {
+					s.log.Warn("Output conversion failed: %v", err)
+				}
 
 				s.PushFrame(audioFrame, frames.Downstream)
 			} else if messageType == websocket.TextMessage {
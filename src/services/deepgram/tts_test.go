@@ -378,3 +378,15 @@ func TestDeepgramTTSContextIDReuse(t *testing.T) {
 	service.mu.Unlock()
 }
 
+
+func TestTTSServiceRegionAndEndpoint(t *testing.T) {
+	if s := NewTTSService(TTSConfig{APIKey: "test-key"}); s.speakURL != "wss://api.deepgram.com/v1/speak" {
+		t.Errorf("default speakURL = %q", s.speakURL)
+	}
+	if s := NewTTSService(TTSConfig{APIKey: "test-key", Region: "eu"}); s.speakURL != "wss://api.eu.deepgram.com/v1/speak" {
+		t.Errorf("eu speakURL = %q", s.speakURL)
+	}
+	if s := NewTTSService(TTSConfig{APIKey: "test-key", Endpoint: "ws://127.0.0.1:9000"}); s.speakURL != "ws://127.0.0.1:9000/v1/speak" {
+		t.Errorf("endpoint speakURL = %q", s.speakURL)
+	}
+}
@@ -0,0 +1,36 @@
+package elevenlabs
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterTTS("elevenlabs", func(config services.ServiceConfig) (services.TTSService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		if err := ValidateModel(config.String(services.ConfigModel)); err != nil {
+			return nil, err
+		}
+		if _, err := Regions.Resolve(config.String(services.ConfigRegion), config.String(services.ConfigEndpoint), DefaultHost); err != nil {
+			return nil, err
+		}
+		aggregate := true
+		if config.Has("aggregate_sentences") {
+			aggregate = config.Bool("aggregate_sentences")
+		}
+		return NewTTSService(TTSConfig{
+			APIKey:             config.String(services.ConfigAPIKey),
+			VoiceID:            config.String(services.ConfigVoice),
+			Model:              config.String(services.ConfigModel),
+			OutputFormat:       config.String(services.ConfigOutputFormat),
+			UseStreaming:       config.Bool("streaming"),
+			Language:           config.String(services.ConfigLanguage),
+			AggregateSentences: aggregate,
+			Region:             config.String(services.ConfigRegion),
+			Endpoint:           config.String(services.ConfigEndpoint),
+		}), nil
+	})
+}
@@ -5,13 +5,15 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
-	"unicode"
 
 	"github.com/gorilla/websocket"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
@@ -20,6 +22,17 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
+// DefaultHost is the ElevenLabs API host used when no Region or Endpoint is set
+const DefaultHost = "api.elevenlabs.io"
+
+// Regions are the ElevenLabs API hosts selectable with Region; the eu and in
+// hosts are data residency deployments that need an account provisioned there
+var Regions = services.RegionalHosts{
+	"us": "api.us.elevenlabs.io",
+	"eu": "api.eu.residency.elevenlabs.io",
+	"in": "api.in.residency.elevenlabs.io",
+}
+
 // VoiceSettings holds configurable voice parameters
 type VoiceSettings struct {
 	Stability       float64 `json:"stability,omitempty"`        // 0.0 to 1.0
@@ -58,6 +71,7 @@ type TTSService struct {
 	*processors.BaseProcessor
 	*services.AudioContextManager
 	apiKey             string
+	host               string // API host, from Region or Endpoint
 	voiceID            string
 	model              string
 	outputFormat       string
@@ -79,9 +93,12 @@ type TTSService struct {
 	partialWord          string  // Partial word across chunks
 	partialWordStartTime float64
 
-	// Audio context management
+	// Audio context management. Contexts the provider never finishes are
+	// evicted after contextTTL, and at most maxContexts are kept.
 	audioContexts map[string]*AudioContext
 	contextMu     sync.RWMutex
+	contextTTL    time.Duration
+	maxContexts   int
 
 	// Metrics tracking
 	ttfbStart    time.Time
@@ -90,24 +107,121 @@ type TTSService struct {
 	// Speaking state tracking
 	isSpeaking bool       // Track if we've emitted TTSStartedFrame
 	mu         sync.Mutex // Protect concurrent access to isSpeaking and service-specific state
+
+	// Converts provider audio to the configured output format (nil = none)
+	outputConverter *services.TTSOutputConverter
+
+	// WebSocket write mutex - gorilla/websocket is NOT safe for concurrent
+	// writes. Also protects conn and the unflushed text.
+	wsMu     sync.Mutex
+	dialFunc func() (*websocket.Conn, error)
+
+	// Reconnection: after an unexpected close the stream is redialed up to
+	// reconnectAttempts times, and the text sent for unflushedCtx since its
+	// last flush is re-sent so synthesis resumes (protected by wsMu)
+	reconnectAttempts int
+	unflushed         strings.Builder
+	unflushedCtx      string
+
+	// Connections detached by a voice change, kept open until the context
+	// they were finishing completes (protected by wsMu)
+	retiring map[*websocket.Conn]string
+
+	// Stall detection - text sent since the last audio, and closed contexts
+	// that haven't sent isFinal yet (protected by mu)
+	stallTimeout time.Duration
+	watchdog     *services.StallWatchdog
+	unanswered   int
+	finishing    map[string]bool
+	stallRetried bool // Reconnect already attempted for the current stall
 }
 
+// defaultReconnectAttempts is how many times a dropped stream is redialed
+// when TTSConfig.ReconnectAttempts is unset
+const defaultReconnectAttempts = 3
+
+// reconnectBackoff is the delay before the first redial, doubled per attempt
+const reconnectBackoff = 100 * time.Millisecond
+
+// retireTimeout bounds how long a connection detached by a voice change waits
+// for its context's final message before it is closed anyway
+const retireTimeout = 10 * time.Second
+
 // TTSConfig holds configuration for ElevenLabs
 type TTSConfig struct {
 	APIKey             string
 	VoiceID            string         // e.g., "21m00Tcm4TlvDq8ikWAM" (Rachel)
-	Model              string         // e.g., "eleven_turbo_v2_5", "eleven_flash_v2_5"
+	Model              string         // e.g., "eleven_turbo_v2_5", "eleven_flash_v2_5", "eleven_v3"; see ValidateModel
 	OutputFormat       string         // Supported: "ulaw_8000", "alaw_8000", "pcm_16000", "pcm_22050", "pcm_24000", "pcm_44100" (default: "pcm_24000")
 	UseStreaming       bool           // Use WebSocket streaming for lower latency
 	VoiceSettings      *VoiceSettings // Optional: stability, similarity_boost, style, speed
 	Language           string         // Language code for multilingual models (e.g., "en", "es", "fr")
 	AggregateSentences bool           // Wait for complete sentences before TTS (default: true)
+	Region             string         // API region from Regions, e.g. "eu" (default: DefaultHost)
+	Endpoint           string         // API host override; takes precedence over Region
+
+	// OutputSampleRate and OutputCodec ("mulaw", "alaw", "linear16") convert
+	// the provider's audio to the transport's format inside the service, e.g.
+	// to 8kHz mulaw for telephony. Either may be left unset to keep the
+	// provider's. Default: no conversion.
+	OutputSampleRate int
+	OutputCodec      string
+
+	// ReconnectAttempts is how many times a streaming connection that drops
+	// mid-turn is redialed before giving up with an ErrorFrame. The current
+	// context's unflushed text is re-sent on the new connection. Default: 3,
+	// negative disables.
+	ReconnectAttempts int
+
+	// StallTimeout is how long a streaming connection may go without audio
+	// while text or a closed context is still waiting for it. A stall is
+	// reported upstream and the connection is redialed once, re-sending the
+	// unflushed text. Default: services.DefaultTTSStallTimeout, negative
+	// disables.
+	StallTimeout time.Duration
+
+	// AudioContextTTL and MaxAudioContexts bound the audio contexts tracked
+	// when the provider never sends isFinal for some, evicting the oldest.
+	// Defaults: services.DefaultAudioContextTTL and
+	// services.DefaultMaxAudioContexts; negative disables either bound.
+	AudioContextTTL  time.Duration
+	MaxAudioContexts int
 }
 
-// Multilingual models that support language codes
-var multilingualModels = map[string]bool{
-	"eleven_flash_v2_5": true,
-	"eleven_turbo_v2_5": true,
+// knownModels maps each supported model to whether it accepts a
+// language_code; the others infer the language from the text or are
+// English-only
+var knownModels = map[string]bool{
+	"eleven_v3":              true,
+	"eleven_flash_v2_5":      true,
+	"eleven_turbo_v2_5":      true,
+	"eleven_multilingual_v2": true,
+	"eleven_flash_v2":        false,
+	"eleven_turbo_v2":        false,
+	"eleven_multilingual_v1": false,
+	"eleven_monolingual_v1":  false,
+}
+
+// ValidateModel returns an error if model is not a known ElevenLabs model.
+// An empty model is valid and uses the account default.
+func ValidateModel(model string) error {
+	if model == "" {
+		return nil
+	}
+	if _, ok := knownModels[model]; !ok {
+		names := make([]string, 0, len(knownModels))
+		for name := range knownModels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown ElevenLabs model %q (supported: %s)", model, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// supportsLanguageCode reports whether model accepts a language_code
+func supportsLanguageCode(model string) bool {
+	return knownModels[model]
 }
 
 // NewTTSService creates a new ElevenLabs TTS service
@@ -136,8 +250,16 @@ func NewTTSService(config TTSConfig) *TTSService {
 		aggregateSentences = config.AggregateSentences
 	}
 
+	log := logger.WithPrefix("ElevenLabsTTS")
+	host, err := Regions.Resolve(config.Region, config.Endpoint, DefaultHost)
+	if err != nil {
+		log.Warn("%v, using %s", err, DefaultHost)
+		host = DefaultHost
+	}
+
 	es := &TTSService{
 		apiKey:              config.APIKey,
+		host:                host,
 		voiceID:             config.VoiceID,
 		model:               config.Model,
 		outputFormat:        outputFormat,
@@ -146,20 +268,57 @@ func NewTTSService(config TTSConfig) *TTSService {
 		language:            config.Language,
 		aggregateSentences:  aggregateSentences,
 		codecDetected:       codecDetected,
-		log:                 logger.WithPrefix("ElevenLabsTTS"),
+		log:                 log,
 		audioContexts:       make(map[string]*AudioContext),
 		AudioContextManager: services.NewAudioContextManager(),
+		outputConverter:     services.NewTTSOutputConverter(config.OutputSampleRate, config.OutputCodec),
 	}
+	es.reconnectAttempts = config.ReconnectAttempts
+	if es.reconnectAttempts == 0 {
+		es.reconnectAttempts = defaultReconnectAttempts
+	}
+	es.contextTTL, es.maxContexts = services.AudioContextBounds(config.AudioContextTTL, config.MaxAudioContexts)
+	es.stallTimeout = config.StallTimeout
+	if es.stallTimeout == 0 {
+		es.stallTimeout = services.DefaultTTSStallTimeout
+	}
+	es.watchdog = services.NewStallWatchdog(es.stallTimeout, es.handleStall)
 	es.BaseProcessor = processors.NewBaseProcessor("ElevenLabsTTS", es)
+	es.AttachLogger(es.log)
+	if err := ValidateModel(es.model); err != nil {
+		es.log.Error("%v", err)
+	}
+	es.warnUnsupportedLanguage()
 	return es
 }
 
+// warnUnsupportedLanguage logs when a language is set that the model won't
+// apply, since ElevenLabs would otherwise ignore it silently
+func (s *TTSService) warnUnsupportedLanguage() {
+	if s.language != "" && s.model != "" && !supportsLanguageCode(s.model) {
+		s.log.Warn("Model %s does not support language codes, ignoring language %q", s.model, s.language)
+	}
+}
+
 func (s *TTSService) SetVoice(voiceID string) {
 	s.voiceID = voiceID
 }
 
 func (s *TTSService) SetModel(model string) {
 	s.model = model
+	s.warnUnsupportedLanguage()
+}
+
+// OutputFormat implements processors.AudioFormatProducer. Without a
+// configured OutputFormat the format follows the call's codec, known only
+// from the StartFrame.
+func (s *TTSService) OutputFormat() processors.AudioFormat {
+	var native processors.AudioFormat
+	if s.codecDetected {
+		sampleRate, codec := s.parseOutputFormat()
+		native = processors.AudioFormat{SampleRate: sampleRate, Codec: codec}
+	}
+	return s.outputConverter.Format(native)
 }
 
 func (s *TTSService) SetVoiceSettings(settings *VoiceSettings) {
@@ -168,74 +327,61 @@ func (s *TTSService) SetVoiceSettings(settings *VoiceSettings) {
 
 func (s *TTSService) SetLanguage(language string) {
 	s.language = language
+	s.warnUnsupportedLanguage()
+}
+
+// languageCode returns the language to send, or "" if the model can't use it
+func (s *TTSService) languageCode() string {
+	if s.language == "" || !supportsLanguageCode(s.model) {
+		return ""
+	}
+	return s.language
+}
+
+// streamURL builds the multi-stream-input WebSocket URL
+func (s *TTSService) streamURL() string {
+	wsURL := services.EndpointURL("wss", s.host, fmt.Sprintf("/v1/text-to-speech/%s/multi-stream-input?model_id=%s&output_format=%s&auto_mode=true",
+		s.voiceID, s.model, s.outputFormat))
+
+	// Add language code for multilingual models
+	if language := s.languageCode(); language != "" {
+		wsURL += fmt.Sprintf("&language_code=%s", language)
+	}
+	return wsURL
 }
 
 func (s *TTSService) Initialize(ctx context.Context) error {
+	if err := ValidateModel(s.model); err != nil {
+		return err
+	}
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
 	if s.useStreaming {
 		// Generate context ID for multi-stream mode
 		s.SetActiveAudioContextID(services.GenerateContextID())
 
-		// Build WebSocket URL with multi-stream-input endpoint and output_format
-		wsURL := fmt.Sprintf("wss://api.elevenlabs.io/v1/text-to-speech/%s/multi-stream-input?model_id=%s&output_format=%s&auto_mode=true",
-			s.voiceID, s.model, s.outputFormat)
-
-		// Add language code for multilingual models
-		if s.language != "" && multilingualModels[s.model] {
-			wsURL += fmt.Sprintf("&language_code=%s", s.language)
-			s.log.Info("Using language code: %s", s.language)
-		}
-
-		header := http.Header{}
-		header.Set("xi-api-key", s.apiKey)
-
-		var err error
-		s.conn, _, err = websocket.DefaultDialer.Dial(wsURL, header)
-		if err != nil {
-			return fmt.Errorf("failed to connect to ElevenLabs: %w", err)
+		if language := s.languageCode(); language != "" {
+			s.log.Info("Using language code: %s", language)
 		}
 
-		// Send initial config with context_id and voice settings
 		ctxID := s.GetActiveAudioContextID()
-		config := map[string]interface{}{
-			"text":       " ",
-			"context_id": ctxID,
-		}
-
-		// Add voice settings
-		if s.voiceSettings != nil {
-			voiceSettingsMap := map[string]interface{}{}
-			if s.voiceSettings.Stability != 0 {
-				voiceSettingsMap["stability"] = s.voiceSettings.Stability
-			}
-			if s.voiceSettings.SimilarityBoost != 0 {
-				voiceSettingsMap["similarity_boost"] = s.voiceSettings.SimilarityBoost
-			}
-			if s.voiceSettings.Style != 0 {
-				voiceSettingsMap["style"] = s.voiceSettings.Style
-			}
-			if s.voiceSettings.UseSpeakerBoost {
-				voiceSettingsMap["use_speaker_boost"] = s.voiceSettings.UseSpeakerBoost
-			}
-			if s.voiceSettings.Speed != 0 {
-				voiceSettingsMap["speed"] = s.voiceSettings.Speed
-			}
-			if len(voiceSettingsMap) > 0 {
-				config["voice_settings"] = voiceSettingsMap
-			}
-		}
-
-		if err := s.conn.WriteJSON(config); err != nil {
-			return fmt.Errorf("failed to send config: %w", err)
+		conn, err := s.connect(ctxID)
+		if err != nil {
+			return err
 		}
+		s.wsMu.Lock()
+		s.conn = conn
+		s.wsMu.Unlock()
 
 		// Start receiving audio
-		go s.receiveAudio()
+		go s.receiveAudio(conn)
 
 		// Start keepalive to prevent timeout
 		go s.keepaliveLoop()
 
+		// Evict audio contexts the provider never finishes
+		go services.RunAudioContextSweeper(s.ctx, s.contextTTL, s.sweepAudioContexts)
+
 		s.log.Info("Streaming mode connected (context: %s)", ctxID)
 	} else {
 		s.log.Info("Non-streaming mode initialized")
@@ -244,16 +390,237 @@ func (s *TTSService) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// dialWebSocket opens the multi-stream-input WebSocket. Does not hold any
+// locks.
+func (s *TTSService) dialWebSocket() (*websocket.Conn, error) {
+	if s.dialFunc != nil {
+		return s.dialFunc()
+	}
+
+	header := http.Header{}
+	header.Set("xi-api-key", s.apiKey)
+
+	conn, _, err := websocket.DefaultDialer.Dial(s.streamURL(), header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ElevenLabs: %w", err)
+	}
+	return conn, nil
+}
+
+// connect dials the stream and sends the initial config for ctxID, or for
+// a fresh context when none is active
+func (s *TTSService) connect(ctxID string) (*websocket.Conn, error) {
+	if ctxID == "" {
+		ctxID = services.GenerateContextID()
+	}
+	conn, err := s.dialWebSocket()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteJSON(s.initMessage(ctxID)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send config: %w", err)
+	}
+	return conn, nil
+}
+
+// initMessage builds the initial config message with context_id and voice
+// settings
+func (s *TTSService) initMessage(ctxID string) map[string]interface{} {
+	config := map[string]interface{}{
+		"text":       " ",
+		"context_id": ctxID,
+	}
+
+	// Add voice settings
+	if s.voiceSettings != nil {
+		voiceSettingsMap := map[string]interface{}{}
+		if s.voiceSettings.Stability != 0 {
+			voiceSettingsMap["stability"] = s.voiceSettings.Stability
+		}
+		if s.voiceSettings.SimilarityBoost != 0 {
+			voiceSettingsMap["similarity_boost"] = s.voiceSettings.SimilarityBoost
+		}
+		if s.voiceSettings.Style != 0 {
+			voiceSettingsMap["style"] = s.voiceSettings.Style
+		}
+		if s.voiceSettings.UseSpeakerBoost {
+			voiceSettingsMap["use_speaker_boost"] = s.voiceSettings.UseSpeakerBoost
+		}
+		if s.voiceSettings.Speed != 0 {
+			voiceSettingsMap["speed"] = s.voiceSettings.Speed
+		}
+		if len(voiceSettingsMap) > 0 {
+			config["voice_settings"] = voiceSettingsMap
+		}
+	}
+	return config
+}
+
+// isConnected reports whether the WebSocket is currently established
+func (s *TTSService) isConnected() bool {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	return s.conn != nil
+}
+
+// writeJSON writes a message to the stream. If the server closed the
+// connection while idle it is redialed first, re-sending any unflushed text.
+func (s *TTSService) writeJSON(v interface{}) error {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	return s.writeJSONLocked(v)
+}
+
+// writeJSONLocked is writeJSON for callers holding wsMu
+func (s *TTSService) writeJSONLocked(v interface{}) error {
+	if s.conn == nil {
+		if s.ctx == nil || s.ctx.Err() != nil {
+			return fmt.Errorf("WebSocket connection closed (shutting down)")
+		}
+		s.log.Warn("Connection nil on write, reconnecting...")
+		if err := s.reconnectLocked(); err != nil {
+			return fmt.Errorf("WebSocket reconnection failed: %w", err)
+		}
+	}
+	return s.conn.WriteJSON(v)
+}
+
+// writeJSONBestEffort writes a message without reconnecting, for close and
+// keepalive messages that are pointless on a new connection
+func (s *TTSService) writeJSONBestEffort(v interface{}) error {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.conn == nil {
+		return fmt.Errorf("WebSocket connection not established")
+	}
+	return s.conn.WriteJSON(v)
+}
+
+// writeText sends text for ctxID and records it as unflushed. It is
+// recorded after the write so a reconnect on this write doesn't replay it.
+func (s *TTSService) writeText(ctxID, text string) error {
+	msg := map[string]interface{}{
+		"text":                   text,
+		"context_id":             ctxID,
+		"try_trigger_generation": true,
+	}
+
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if s.unflushedCtx != ctxID {
+		s.unflushed.Reset()
+		s.unflushedCtx = ctxID
+	}
+	if err := s.writeJSONLocked(msg); err != nil {
+		return err
+	}
+	s.unflushed.WriteString(text)
+	return nil
+}
+
+// clearUnflushed forgets the unflushed text once it is flushed or cancelled
+func (s *TTSService) clearUnflushed() {
+	s.wsMu.Lock()
+	s.unflushed.Reset()
+	s.unflushedCtx = ""
+	s.wsMu.Unlock()
+}
+
+// reconnectLocked dials a new stream, re-sends the config and the active
+// context's unflushed text, and starts a receiver for it. Caller must hold
+// wsMu; it is released while dialing so writers aren't blocked on the
+// network.
+func (s *TTSService) reconnectLocked() error {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	ctxID := s.GetActiveAudioContextID()
+	s.wsMu.Unlock()
+	conn, err := s.connect(ctxID)
+	s.wsMu.Lock()
+	if err != nil {
+		return err
+	}
+
+	// Shutdown occurred while we were dialing
+	if s.ctx == nil || s.ctx.Err() != nil {
+		conn.Close()
+		return fmt.Errorf("shutting down, discarding new connection")
+	}
+	// Another goroutine reconnected while we were dialing
+	if s.conn != nil {
+		conn.Close()
+		return nil
+	}
+
+	if s.unflushedCtx != "" && s.unflushedCtx == ctxID && s.unflushed.Len() > 0 {
+		replay := map[string]interface{}{
+			"text":                   s.unflushed.String(),
+			"context_id":             ctxID,
+			"try_trigger_generation": true,
+		}
+		if err := conn.WriteJSON(replay); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to replay unflushed text: %w", err)
+		}
+		s.log.Info("Replayed %d characters of unflushed text for context %s", s.unflushed.Len(), ctxID)
+	}
+
+	s.conn = conn
+	go s.receiveAudio(conn)
+	s.log.Info("WebSocket reconnected (context: %s)", ctxID)
+	return nil
+}
+
+// reconnectAfterDrop redials a stream that closed unexpectedly, backing off
+// between attempts. It reports whether the stream was restored.
+func (s *TTSService) reconnectAfterDrop(dropped *websocket.Conn) bool {
+	delay := reconnectBackoff
+	for attempt := 1; attempt <= s.reconnectAttempts; attempt++ {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		s.wsMu.Lock()
+		if s.conn != dropped {
+			// Cleanup or a writer already replaced the connection
+			restored := s.conn != nil
+			s.wsMu.Unlock()
+			return restored
+		}
+		err := s.reconnectLocked()
+		if err != nil {
+			// reconnectLocked cleared conn; keep comparing against nil
+			dropped = nil
+		}
+		s.wsMu.Unlock()
+
+		if err == nil {
+			return true
+		}
+		s.log.Warn("Reconnect attempt %d/%d failed: %v", attempt, s.reconnectAttempts, err)
+	}
+	return false
+}
+
 func (s *TTSService) Cleanup() error {
 	// Cancel context first to signal goroutines to stop
 	if s.cancel != nil {
 		s.cancel()
 	}
+	s.watchdog.Stop()
 
 	// Give goroutines a moment to see the context cancellation
 	time.Sleep(50 * time.Millisecond)
 
-	// Now close the connection
+	// Now close the connection under lock (writeJSON may be in flight)
+	s.wsMu.Lock()
 	if s.conn != nil {
 		// Send close message before closing socket (for ElevenLabs)
 		if s.HasActiveAudioContext() {
@@ -265,6 +632,13 @@ func (s *TTSService) Cleanup() error {
 		s.conn.Close()
 		s.conn = nil
 	}
+	for conn := range s.retiring {
+		conn.Close()
+	}
+	s.retiring = nil
+	s.unflushed.Reset()
+	s.unflushedCtx = ""
+	s.wsMu.Unlock()
 
 	// Clear audio contexts
 	s.contextMu.Lock()
@@ -284,14 +658,14 @@ func (s *TTSService) keepaliveLoop() {
 			return
 		case <-ticker.C:
 			ctxID := s.GetActiveAudioContextID()
-			if s.conn != nil && ctxID != "" {
+			if ctxID != "" {
 				keepaliveMsg := map[string]interface{}{
 					"text":       "",
 					"context_id": ctxID,
 				}
-				if err := s.conn.WriteJSON(keepaliveMsg); err != nil {
-					s.log.Warn("Keepalive error: %v", err)
-					return
+				// The connection may be down while reconnecting; keep ticking
+				if err := s.writeJSONBestEffort(keepaliveMsg); err != nil {
+					s.log.Debug("Keepalive error: %v", err)
 				}
 			}
 		}
@@ -303,23 +677,21 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	if startFrame, ok := frame.(*frames.StartFrame); ok {
 		// Auto-detect output format from incoming codec (only if user didn't set OutputFormat)
 		if !s.codecDetected {
-			if meta := startFrame.Metadata(); meta != nil {
-				if codec, ok := meta["codec"].(string); ok {
-					s.log.Info("Detected incoming codec: %s", codec)
-					// Match ElevenLabs output to incoming codec for compatibility
-					switch codec {
-					case "mulaw":
-						s.outputFormat = "ulaw_8000"
-						s.log.Info("Auto-configured output format: ulaw_8000")
-					case "alaw":
-						s.outputFormat = "alaw_8000"
-						s.log.Info("Auto-configured output format: alaw_8000")
-					case "linear16":
-						s.outputFormat = "pcm_16000"
-						s.log.Info("Auto-configured output format: pcm_16000")
-					}
-					s.codecDetected = true
+			if codec := startFrame.MediaCodec(); codec != "" {
+				s.log.Info("Detected incoming codec: %s", codec)
+				// Match ElevenLabs output to incoming codec for compatibility
+				switch codec {
+				case "mulaw":
+					s.outputFormat = "ulaw_8000"
+					s.log.Info("Auto-configured output format: ulaw_8000")
+				case "alaw":
+					s.outputFormat = "alaw_8000"
+					s.log.Info("Auto-configured output format: alaw_8000")
+				case "linear16":
+					s.outputFormat = "pcm_16000"
+					s.log.Info("Auto-configured output format: pcm_16000")
 				}
+				s.codecDetected = true
 			}
 		}
 
@@ -353,7 +725,7 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	}
 
 	// Handle InterruptionFrame - stop synthesis and reset state
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
 		s.log.Info("INTERRUPTION RECEIVED - Stopping TTS synthesis")
 		oldContextID := s.GetActiveAudioContextID()
 		s.mu.Lock()
@@ -367,19 +739,25 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 		s.partialWordStartTime = 0.0
 		s.cumulativeTime = 0
 		s.ttfbRecorded = false
+		// Interrupted text is no longer expected to produce audio
+		s.unanswered = 0
+		s.finishing = nil
+		s.stallRetried = false
 		s.mu.Unlock()
+		s.watchdog.Stop()
 		// Reset context IDs via AudioContextManager
 		s.ResetActiveAudioContext()
 
 		// CRITICAL: Always close the context if it exists, regardless of wasSpeaking
 		// This prevents context accumulation on ElevenLabs
-		if s.useStreaming && s.conn != nil && oldContextID != "" {
+		if s.useStreaming && oldContextID != "" {
+			s.clearUnflushed()
 			s.log.Debug("Closing context %s on ElevenLabs (was_speaking=%v)", oldContextID, wasSpeaking)
 			closeMsg := map[string]interface{}{
 				"context_id":    oldContextID,
 				"close_context": true,
 			}
-			if err := s.conn.WriteJSON(closeMsg); err != nil {
+			if err := s.writeJSONBestEffort(closeMsg); err != nil {
 				s.log.Debug("Error closing context: %v", err)
 			}
 
@@ -392,11 +770,18 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewTTSStoppedFrame(), frames.Upstream)
 		}
 
+		s.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageTTSCancelled)
 		s.log.Debug("Interruption handled (was_speaking=%v, closed_context=%s)", wasSpeaking, oldContextID)
 
 		return s.PushFrame(frame, direction)
 	}
 
+	// Handle SetVoiceFrame - finish current context, then switch voice
+	if setVoice, ok := frame.(*frames.SetVoiceFrame); ok {
+		s.handleSetVoice(setVoice)
+		return s.PushFrame(frame, direction)
+	}
+
 	// Process text frames (LLM output)
 	if textFrame, ok := frame.(*frames.TextFrame); ok {
 		if textFrame.SkipTTS {
@@ -428,30 +813,25 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 		return s.processTextInput(llmFrame.Text)
 	}
 
+	// Handle TTSFlushFrame - synthesize buffered text and flush the context,
+	// keeping it open for more text
+	if _, ok := frame.(*frames.TTSFlushFrame); ok {
+		s.flushTextBuffer()
+		if ctxID := s.GetActiveAudioContextID(); s.useStreaming && s.ctx != nil && ctxID != "" {
+			s.log.Debug("Flushing context %s", ctxID)
+			s.sendFlush(ctxID)
+		}
+		return s.PushFrame(frame, direction)
+	}
+
 	// Handle LLM response end to flush TTS
 	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
-		// Flush any remaining text in buffer
-		if s.textBuffer.Len() > 0 {
-			remainingText := s.textBuffer.String()
-			s.textBuffer.Reset()
-			s.log.Debug("Flushing remaining text: %s", remainingText)
-			if err := s.synthesizeText(remainingText); err != nil {
-				s.log.Warn("Error synthesizing remaining text: %v", err)
-			}
-		}
+		s.flushTextBuffer()
 
 		ctxID := s.GetActiveAudioContextID()
-		if s.useStreaming && s.conn != nil && ctxID != "" {
+		if s.useStreaming && s.ctx != nil && ctxID != "" {
 			s.log.Info("LLM response ended, sending flush to generate final audio")
-			// Send flush message with context_id
-			flushMsg := map[string]interface{}{
-				"text":       "",
-				"context_id": ctxID,
-				"flush":      true,
-			}
-			if err := s.conn.WriteJSON(flushMsg); err != nil {
-				s.log.Warn("Error sending flush: %v", err)
-			}
+			s.sendFlush(ctxID)
 
 			// CRITICAL: Close context after normal completion (not just on interruption)
 			// This prevents context accumulation on ElevenLabs
@@ -470,8 +850,10 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 				"context_id":    ctxID,
 				"close_context": true,
 			}
-			if err := s.conn.WriteJSON(closeMsg); err != nil {
+			if err := s.writeJSONBestEffort(closeMsg); err != nil {
 				s.log.Debug("Error closing context: %v", err)
+			} else if wasSpeaking {
+				s.awaitFinal(ctxID)
 			}
 
 			// Remove audio context
@@ -503,6 +885,253 @@ func (s *TTSService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	return s.PushFrame(frame, direction)
 }
 
+// handleSetVoice flushes buffered text and finishes the active context with
+// the current voice, then applies the new voice parameters. In streaming mode
+// the voice and model are part of the WebSocket URL and voice settings are
+// sent on connect, so the socket is detached: it keeps delivering the old
+// context's audio until ElevenLabs marks it final, and the next text dials a
+// new connection.
+func (s *TTSService) handleSetVoice(frame *frames.SetVoiceFrame) {
+	if s.textBuffer.Len() > 0 {
+		remainingText := s.textBuffer.String()
+		s.textBuffer.Reset()
+		if err := s.synthesizeText(remainingText); err != nil {
+			s.log.Warn("Error synthesizing remaining text before voice change: %v", err)
+		}
+	}
+
+	ctxID := s.GetActiveAudioContextID()
+	if s.useStreaming && s.isConnected() && ctxID != "" {
+		flushMsg := map[string]interface{}{
+			"text":       "",
+			"context_id": ctxID,
+			"flush":      true,
+		}
+		closeMsg := map[string]interface{}{
+			"context_id":    ctxID,
+			"close_context": true,
+		}
+		if err := s.writeJSON(flushMsg); err != nil {
+			s.log.Warn("Error sending flush before voice change: %v", err)
+		} else if err := s.writeJSONBestEffort(closeMsg); err != nil {
+			s.log.Debug("Error closing context before voice change: %v", err)
+		} else if s.speaking() {
+			s.awaitFinal(ctxID)
+		}
+	}
+	s.clearUnflushed()
+
+	s.mu.Lock()
+	s.isSpeaking = false
+	s.cumulativeTime = 0
+	s.partialWord = ""
+	s.partialWordStartTime = 0.0
+	s.ttfbRecorded = false
+	s.mu.Unlock()
+	s.ResetActiveAudioContext()
+
+	if frame.VoiceID != "" {
+		s.voiceID = frame.VoiceID
+	}
+	if frame.Model != "" {
+		if err := ValidateModel(frame.Model); err != nil {
+			s.log.Warn("Keeping model %s: %v", s.model, err)
+		} else {
+			s.model = frame.Model
+		}
+	}
+	if language, ok := frame.StringSetting("language"); ok {
+		s.language = language
+	}
+	s.warnUnsupportedLanguage()
+
+	settings := VoiceSettings{}
+	if s.voiceSettings != nil {
+		settings = *s.voiceSettings
+	}
+	if v, ok := frame.FloatSetting("stability"); ok {
+		settings.Stability = v
+	}
+	if v, ok := frame.FloatSetting("similarity_boost"); ok {
+		settings.SimilarityBoost = v
+	}
+	if v, ok := frame.FloatSetting("style"); ok {
+		settings.Style = v
+	}
+	if v, ok := frame.FloatSetting("speed"); ok {
+		settings.Speed = v
+	}
+	if v, ok := frame.Settings["use_speaker_boost"].(bool); ok {
+		settings.UseSpeakerBoost = v
+	}
+	s.voiceSettings = &settings
+
+	// Reconnect lazily so the new URL and voice settings take effect
+	if s.useStreaming && s.ctx != nil {
+		s.wsMu.Lock()
+		s.retireConnLocked(ctxID)
+		s.wsMu.Unlock()
+	}
+
+	s.log.Info("Voice changed (voice=%s, model=%s, previous context=%s)", s.voiceID, s.model, ctxID)
+}
+
+// retireConnLocked detaches the active connection so the next write dials a
+// new one. It stays open until ctxID's final message arrives (or
+// retireTimeout passes) so the flushed audio isn't cut. Caller must hold wsMu.
+func (s *TTSService) retireConnLocked(ctxID string) {
+	conn := s.conn
+	s.conn = nil
+	if conn == nil {
+		return
+	}
+	if ctxID == "" {
+		conn.Close()
+		return
+	}
+	if s.retiring == nil {
+		s.retiring = make(map[*websocket.Conn]string)
+	}
+	s.retiring[conn] = ctxID
+	time.AfterFunc(retireTimeout, func() { s.closeRetired(conn) })
+}
+
+// closeRetired closes a connection detached by retireConnLocked, once
+func (s *TTSService) closeRetired(conn *websocket.Conn) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	if _, ok := s.retiring[conn]; !ok {
+		return
+	}
+	delete(s.retiring, conn)
+	conn.Close()
+}
+
+// awaitAudio records text sent to ElevenLabs and arms the stall watchdog
+func (s *TTSService) awaitAudio() {
+	if s.watchdog == nil {
+		return
+	}
+	s.mu.Lock()
+	s.unanswered++
+	s.mu.Unlock()
+	s.watchdog.Arm()
+}
+
+// awaitFinal keeps the watchdog running for a closed context until its
+// isFinal arrives, so a stall partway through its last sentence is caught
+func (s *TTSService) awaitFinal(ctxID string) {
+	if s.watchdog == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.finishing == nil {
+		s.finishing = make(map[string]bool)
+	}
+	s.finishing[ctxID] = true
+	s.mu.Unlock()
+	s.watchdog.Arm()
+}
+
+// audioReceived notes audio from ElevenLabs: text sent so far is being
+// answered, and only closed contexts still owe the rest of theirs
+func (s *TTSService) audioReceived() {
+	s.mu.Lock()
+	s.unanswered = 0
+	s.stallRetried = false
+	finishing := len(s.finishing) > 0
+	s.mu.Unlock()
+	if finishing {
+		s.watchdog.Feed()
+	} else {
+		s.watchdog.Stop()
+	}
+}
+
+// contextFinished notes ctxID's isFinal, disarming the watchdog once
+// nothing else is outstanding
+func (s *TTSService) contextFinished(ctxID string) {
+	s.mu.Lock()
+	delete(s.finishing, ctxID)
+	idle := len(s.finishing) == 0 && s.unanswered == 0
+	s.mu.Unlock()
+	if idle {
+		s.watchdog.Stop()
+	}
+}
+
+// speaking reports whether text was sent in the active context
+func (s *TTSService) speaking() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isSpeaking
+}
+
+// handleStall runs when text or a closed context got no audio within the
+// stall timeout. It reports the stall upstream and redials once, re-sending
+// the active context's unflushed text; a second stall gives up.
+func (s *TTSService) handleStall() {
+	s.mu.Lock()
+	unanswered := s.unanswered
+	finishing := len(s.finishing)
+	retried := s.stallRetried
+	s.unanswered = 0
+	s.finishing = nil
+	s.stallRetried = true
+	s.mu.Unlock()
+
+	if (unanswered == 0 && finishing == 0) || s.ctx == nil || s.ctx.Err() != nil {
+		return
+	}
+
+	s.log.Warn("No audio for %v (%d messages unanswered, %d contexts unfinished)", s.stallTimeout, unanswered, finishing)
+	s.PushFrame(frames.NewErrorFrame(fmt.Errorf("ElevenLabs stalled: no audio within %v", s.stallTimeout)), frames.Upstream)
+
+	if retried {
+		s.log.Error("ElevenLabs still stalled after reconnecting, giving up on the pending audio")
+		return
+	}
+
+	s.wsMu.Lock()
+	err := s.reconnectLocked()
+	replayed := s.unflushedCtx != "" && s.unflushedCtx == s.GetActiveAudioContextID() && s.unflushed.Len() > 0
+	s.wsMu.Unlock()
+	if err != nil {
+		s.log.Error("Reconnect after stall failed: %v", err)
+		s.PushFrame(frames.NewErrorFrame(fmt.Errorf("ElevenLabs reconnect after stall failed: %w", err)), frames.Upstream)
+		return
+	}
+	if replayed {
+		s.awaitAudio()
+	}
+}
+
+// flushTextBuffer synthesizes any text still waiting for a sentence boundary
+func (s *TTSService) flushTextBuffer() {
+	if s.textBuffer.Len() == 0 {
+		return
+	}
+	remainingText := s.textBuffer.String()
+	s.textBuffer.Reset()
+	s.log.Debug("Flushing remaining text: %s", remainingText)
+	if err := s.synthesizeText(remainingText); err != nil {
+		s.log.Warn("Error synthesizing remaining text: %v", err)
+	}
+}
+
+// sendFlush asks ElevenLabs to generate the audio for all text sent to ctxID
+func (s *TTSService) sendFlush(ctxID string) {
+	flushMsg := map[string]interface{}{
+		"text":       "",
+		"context_id": ctxID,
+		"flush":      true,
+	}
+	if err := s.writeJSON(flushMsg); err != nil {
+		s.log.Warn("Error sending flush: %v", err)
+	}
+	s.clearUnflushed()
+}
+
 // processTextInput handles incoming text with optional sentence aggregation
 func (s *TTSService) processTextInput(text string) error {
 	if text == "" {
@@ -519,7 +1148,7 @@ func (s *TTSService) processTextInput(text string) error {
 	bufferedText := s.textBuffer.String()
 
 	// Extract complete sentences
-	sentences, remainder := s.extractSentences(bufferedText)
+	sentences, remainder := services.SplitSentences(bufferedText, s.language)
 
 	// Update buffer with remainder
 	s.textBuffer.Reset()
@@ -539,44 +1168,23 @@ func (s *TTSService) processTextInput(text string) error {
 	return nil
 }
 
-// extractSentences splits text into complete sentences and remainder
-func (s *TTSService) extractSentences(text string) ([]string, string) {
-	var sentences []string
-	var currentSentence strings.Builder
-
-	sentenceEnders := map[rune]bool{
-		'.': true,
-		'!': true,
-		'?': true,
-		';': true,
-	}
-
-	runes := []rune(text)
-	for i := 0; i < len(runes); i++ {
-		r := runes[i]
-		currentSentence.WriteRune(r)
-
-		if sentenceEnders[r] {
-			if i == len(runes)-1 {
-				// End of text
-				sentences = append(sentences, currentSentence.String())
-				currentSentence.Reset()
-			} else if i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
-				// Followed by space - likely end of sentence
-				sentences = append(sentences, currentSentence.String())
-				currentSentence.Reset()
-			}
-		}
-	}
-
-	return sentences, currentSentence.String()
-}
-
 func (s *TTSService) synthesizeText(text string) error {
 	if text == "" {
 		return nil
 	}
 
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := services.RateLimiterFor("elevenlabs", s.apiKey).Wait(ctx); err != nil {
+		if errors.Is(err, services.ErrRateLimited) {
+			s.log.Warn("Dropping text, %v", err)
+			return s.PushFrame(frames.NewErrorFrame(fmt.Errorf("ElevenLabs: %w", err)), frames.Upstream)
+		}
+		return err
+	}
+
 	// Use AudioContextManager to get or create context ID
 	// Reuses turn context ID if available, otherwise generates new one
 	ctxID := s.GetOrCreateContextID()
@@ -613,14 +1221,13 @@ func (s *TTSService) synthesizeText(text string) error {
 		s.log.Info("FIRST TOKEN -> Starting audio generation (parallel LLM+TTS)")
 	}
 
-	if s.useStreaming && s.conn != nil {
+	if s.useStreaming && s.ctx != nil {
 		// Send text chunk via WebSocket with context_id
-		msg := map[string]interface{}{
-			"text":                   text,
-			"context_id":             ctxID,
-			"try_trigger_generation": true,
+		if err := s.writeText(ctxID, text); err != nil {
+			return err
 		}
-		return s.conn.WriteJSON(msg)
+		s.awaitAudio()
+		return nil
 	} else {
 		// Use HTTP API for non-streaming
 		return s.synthesizeHTTP(text)
@@ -629,13 +1236,16 @@ func (s *TTSService) synthesizeText(text string) error {
 
 func (s *TTSService) synthesizeHTTP(text string) error {
 	// Add output_format parameter to URL
-	url := fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s?output_format=%s",
-		s.voiceID, s.outputFormat)
+	url := services.EndpointURL("https", s.host, fmt.Sprintf("/v1/text-to-speech/%s?output_format=%s",
+		s.voiceID, s.outputFormat))
 
 	requestBody := map[string]interface{}{
 		"text":     text,
 		"model_id": s.model,
 	}
+	if language := s.languageCode(); language != "" {
+		requestBody["language_code"] = language
+	}
 
 	// Add voice settings
 	if s.voiceSettings != nil {
@@ -697,6 +1307,7 @@ func (s *TTSService) synthesizeHTTP(text string) error {
 	// Create TTS audio frame with codec metadata
 	audioFrame := frames.NewTTSAudioFrame(audioData, sampleRate, 1)
 	audioFrame.SetMetadata("codec", codec)
+	s.convertOutput(audioFrame)
 	if err := s.PushFrame(audioFrame, frames.Downstream); err != nil {
 		return err
 	}
@@ -722,6 +1333,30 @@ func (s *TTSService) createAudioContext(contextID string) {
 		StartTime:      time.Now(),
 	}
 	s.log.Info("Created audio context: %s", contextID)
+	s.evictAudioContextsLocked(contextID)
+}
+
+// sweepAudioContexts evicts audio contexts past their TTL, keeping the
+// active one
+func (s *TTSService) sweepAudioContexts() {
+	s.contextMu.Lock()
+	defer s.contextMu.Unlock()
+	s.evictAudioContextsLocked()
+}
+
+// evictAudioContextsLocked removes contexts older than contextTTL and the
+// oldest beyond maxContexts, never the active one or keep. Audio still
+// arriving for an evicted context is dropped as stale. Must be called with
+// contextMu held.
+func (s *TTSService) evictAudioContextsLocked(keep ...string) {
+	keep = append(keep, s.GetActiveAudioContextID())
+	evicted := services.SweepAudioContexts(s.audioContexts,
+		func(c *AudioContext) time.Time { return c.StartTime },
+		time.Now(), s.contextTTL, s.maxContexts, keep...)
+	if len(evicted) > 0 {
+		s.log.Warn("Evicted %d audio contexts never finished by the provider: %v (%d remain)",
+			len(evicted), evicted, len(s.audioContexts))
+	}
 }
 
 func (s *TTSService) removeAudioContext(contextID string) {
@@ -830,28 +1465,19 @@ func (s *TTSService) calculateWordTimes(alignment map[string]interface{}) []Word
 	return timestamps
 }
 
-func (s *TTSService) receiveAudio() {
+// receiveAudio reads myConn until it closes. The connection is passed in
+// rather than read from s.conn so a receiver for a replaced or retired
+// connection never touches the new one.
+func (s *TTSService) receiveAudio(myConn *websocket.Conn) {
 	for {
 		select {
 		case <-s.ctx.Done():
 			s.log.Debug("Context cancelled, stopping audio receiver")
 			return
 		default:
-			if s.conn == nil {
-				s.log.Debug("Connection is nil, stopping receiver")
-				return
-			}
-
-			messageType, message, err := s.conn.ReadMessage()
+			messageType, message, err := myConn.ReadMessage()
 			if err != nil {
-				// Check if this is a normal closure during shutdown
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) ||
-					strings.Contains(err.Error(), "use of closed network connection") {
-					s.log.Debug("Connection closed normally")
-					return
-				}
-				s.log.Error("Error reading message: %v", err)
-				s.PushFrame(frames.NewErrorFrame(err), frames.Upstream)
+				s.handleReadError(myConn, err)
 				return
 			}
 
@@ -861,6 +1487,7 @@ func (s *TTSService) receiveAudio() {
 				sampleRate, codec := s.parseOutputFormat()
 				audioFrame := frames.NewTTSAudioFrame(message, sampleRate, 1)
 				audioFrame.SetMetadata("codec", codec)
+				s.convertOutput(audioFrame)
 				s.PushFrame(audioFrame, frames.Downstream)
 			} else {
 				// JSON message (contains base64-encoded audio + metadata)
@@ -888,6 +1515,21 @@ func (s *TTSService) receiveAudio() {
 						s.contextMu.RUnlock()
 
 						s.removeAudioContext(receivedCtxID)
+						s.contextFinished(receivedCtxID)
+						s.CompleteAudioContext(receivedCtxID)
+					}
+
+					// A connection detached by a voice change is done once its
+					// context completes; speaking state belongs to the new one
+					s.wsMu.Lock()
+					retiredCtx, retired := s.retiring[myConn]
+					s.wsMu.Unlock()
+					if retired {
+						if receivedCtxID == retiredCtx {
+							s.log.Debug("Closing connection retired by voice change (context %s done)", receivedCtxID)
+							s.closeRetired(myConn)
+						}
+						continue
 					}
 
 					s.mu.Lock()
@@ -899,6 +1541,12 @@ func (s *TTSService) receiveAudio() {
 					continue
 				}
 
+				// Any audio shows the stream is alive, including the tail of
+				// a context already closed on our side
+				if audioB64, ok := response["audio"].(string); ok && audioB64 != "" {
+					s.audioReceived()
+				}
+
 				// Validate context ID to avoid processing old/stale messages
 				if hasCtxID {
 					currentCtxID := s.GetActiveAudioContextID()
@@ -931,6 +1579,7 @@ func (s *TTSService) receiveAudio() {
 					audioFrame := frames.NewTTSAudioFrame(audioData, sampleRate, 1)
 					audioFrame.SetMetadata("codec", codec)
 					audioFrame.SetMetadata("context_id", receivedCtxID)
+					s.convertOutput(audioFrame)
 
 					// Add to audio context for tracking
 					if hasCtxID {
@@ -953,6 +1602,40 @@ func (s *TTSService) receiveAudio() {
 	}
 }
 
+// handleReadError handles the end of conn's read loop. Our own shutdown and
+// a normal close from the server (e.g. idle timeout) leave reconnection to
+// the next write; any other error means the stream dropped, so it is
+// redialed at once with the unflushed text replayed.
+func (s *TTSService) handleReadError(conn *websocket.Conn, err error) {
+	if s.ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+		s.log.Debug("Connection closed (shutdown)")
+		return
+	}
+
+	s.wsMu.Lock()
+	current := s.conn == conn
+	if current && websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		s.conn = nil
+	}
+	s.wsMu.Unlock()
+	if !current {
+		s.log.Debug("Stale receiver saw close (connection already replaced): %v", err)
+		return
+	}
+
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		s.log.Debug("Server closed connection normally, reconnecting on next write")
+		return
+	}
+
+	s.log.Warn("Connection dropped: %v, reconnecting", err)
+	if s.reconnectAfterDrop(conn) {
+		return
+	}
+	s.log.Error("Error reading message: %v", err)
+	s.PushFrame(frames.NewErrorFrame(err), frames.Upstream)
+}
+
 // parseOutputFormat extracts sample rate and codec from output format string
 func (s *TTSService) parseOutputFormat() (int, string) {
 	switch s.outputFormat {
@@ -972,3 +1655,10 @@ func (s *TTSService) parseOutputFormat() (int, string) {
 		return 24000, "linear16"
 	}
 }
+
+// convertOutput converts an audio frame to the configured output format
+func (s *TTSService) convertOutput(frame *frames.TTSAudioFrame) {
+	if err := s.outputConverter.Convert(frame); err != nil {
+		s.log.Warn("Output conversion failed: %v", err)
+	}
+}
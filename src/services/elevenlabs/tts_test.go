@@ -1,11 +1,21 @@
 package elevenlabs
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
 	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
@@ -192,3 +202,665 @@ func TestElevenLabsTTSContextIDReuse(t *testing.T) {
 		t.Errorf("Expected currentTurnContextID to be reset after LLMFullResponseEndFrame, got: %s", service.GetTurnContextID())
 	}
 }
+
+func TestElevenLabsTTSSetVoiceFrame(t *testing.T) {
+	service := NewTTSService(TTSConfig{
+		APIKey:       "test-key",
+		VoiceID:      "voice-a",
+		Model:        "eleven_turbo_v2_5",
+		UseStreaming: false,
+		VoiceSettings: &VoiceSettings{
+			Stability:       0.4,
+			SimilarityBoost: 0.7,
+		},
+	})
+	service.SetActiveAudioContextID("old-context")
+	service.isSpeaking = true
+
+	setVoice := frames.NewSetVoiceFrame("voice-b", "eleven_flash_v2_5", map[string]interface{}{
+		"stability": 0.9,
+		"speed":     1.1,
+	})
+	if err := service.HandleFrame(context.Background(), setVoice, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(SetVoiceFrame) failed: %v", err)
+	}
+
+	if service.voiceID != "voice-b" {
+		t.Errorf("Expected voice-b, got %s", service.voiceID)
+	}
+	if service.model != "eleven_flash_v2_5" {
+		t.Errorf("Expected eleven_flash_v2_5, got %s", service.model)
+	}
+	if service.voiceSettings.Stability != 0.9 || service.voiceSettings.Speed != 1.1 {
+		t.Errorf("Expected updated settings, got %+v", service.voiceSettings)
+	}
+	if service.voiceSettings.SimilarityBoost != 0.7 {
+		t.Errorf("Expected untouched settings to be kept, got %+v", service.voiceSettings)
+	}
+	if service.HasActiveAudioContext() || service.isSpeaking {
+		t.Error("Expected current context to be finished on voice change")
+	}
+}
+
+func TestElevenLabsTTSOutputFormatFromStartFrame(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		start func() *frames.StartFrame
+		want  string
+	}{
+		{"typed codec", func() *frames.StartFrame {
+			f := frames.NewStartFrame()
+			f.Codec = "alaw"
+			return f
+		}, "alaw_8000"},
+		{"legacy metadata", func() *frames.StartFrame {
+			f := frames.NewStartFrame()
+			f.SetMetadata("codec", "mulaw")
+			return f
+		}, "ulaw_8000"},
+		{"unknown codec", frames.NewStartFrame, "pcm_24000"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewTTSService(TTSConfig{
+				APIKey:       "test-key",
+				VoiceID:      "test-voice",
+				UseStreaming: false,
+			})
+			if err := service.HandleFrame(context.Background(), tc.start(), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(StartFrame) failed: %v", err)
+			}
+			if service.outputFormat != tc.want {
+				t.Errorf("Expected output format %s, got %s", tc.want, service.outputFormat)
+			}
+		})
+	}
+}
+
+func TestElevenLabsTTSLanguageForMultilingualModels(t *testing.T) {
+	for _, model := range []string{"eleven_v3", "eleven_flash_v2_5", "eleven_turbo_v2_5", "eleven_multilingual_v2"} {
+		service := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice", Model: model, Language: "es"})
+		if url := service.streamURL(); !strings.Contains(url, "language_code=es") {
+			t.Errorf("%s: expected language_code in %s", model, url)
+		}
+	}
+}
+
+func TestElevenLabsTTSLanguageWarnsForUnsupportedModel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger.SetOutput(buf)
+	t.Cleanup(func() { logger.SetOutput(os.Stdout) })
+
+	service := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice", Model: "eleven_flash_v2", Language: "es"})
+	if url := service.streamURL(); strings.Contains(url, "language_code") {
+		t.Errorf("Expected no language_code for an English-only model, got %s", url)
+	}
+	if !strings.Contains(buf.String(), `does not support language codes, ignoring language "es"`) {
+		t.Errorf("Expected a warning about the ignored language, got log %q", buf.String())
+	}
+
+	// Switching to a multilingual model applies it
+	buf.Reset()
+	service.SetModel("eleven_turbo_v2_5")
+	if url := service.streamURL(); !strings.Contains(url, "language_code=es") {
+		t.Errorf("Expected language_code after switching models, got %s", url)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning for a multilingual model, got %q", buf.String())
+	}
+}
+
+func TestElevenLabsTTSModelValidation(t *testing.T) {
+	if err := ValidateModel("eleven_v3"); err != nil {
+		t.Errorf("Expected eleven_v3 to be valid, got %v", err)
+	}
+	if err := ValidateModel(""); err != nil {
+		t.Errorf("Expected an empty model to use the default, got %v", err)
+	}
+	err := ValidateModel("eleven_turbo_v9")
+	if err == nil || !strings.Contains(err.Error(), `unknown ElevenLabs model "eleven_turbo_v9"`) {
+		t.Fatalf("Expected an unknown model error, got %v", err)
+	}
+
+	service := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice", Model: "eleven_turbo_v9"})
+	if err := service.Initialize(context.Background()); err == nil {
+		t.Error("Expected Initialize to reject an unknown model")
+	}
+
+	_, err = services.BuildTTS("elevenlabs", services.ServiceConfig{
+		services.ConfigAPIKey: "test-key",
+		services.ConfigModel:  "eleven_turbo_v9",
+	})
+	if err == nil {
+		t.Error("Expected the registry to reject an unknown model")
+	}
+
+	// A voice change to an unknown model keeps the current one
+	service = NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice", Model: "eleven_turbo_v2_5"})
+	service.HandleFrame(context.Background(), frames.NewSetVoiceFrame("", "eleven_turbo_v9", nil), frames.Downstream)
+	if service.model != "eleven_turbo_v2_5" {
+		t.Errorf("Expected unknown model to be rejected, got %s", service.model)
+	}
+}
+
+func TestElevenLabsTTSOutputConversion(t *testing.T) {
+	service := NewTTSService(TTSConfig{
+		APIKey:           "test-key",
+		VoiceID:          "test-voice",
+		OutputFormat:     "pcm_24000",
+		OutputSampleRate: 8000,
+		OutputCodec:      "mulaw",
+	})
+
+	sampleRate, codec := service.parseOutputFormat()
+	frame := frames.NewTTSAudioFrame(make([]byte, 960), sampleRate, 1)
+	frame.SetMetadata("codec", codec)
+	service.convertOutput(frame)
+
+	if frame.SampleRate != 8000 || frame.Metadata()["codec"] != "mulaw" || len(frame.Data) != 160 {
+		t.Errorf("Expected 160 bytes of 8kHz mulaw, got %d bytes of %v at %dHz",
+			len(frame.Data), frame.Metadata()["codec"], frame.SampleRate)
+	}
+}
+
+// frameCapture collects frames a service pushes to it
+type frameCapture struct {
+	*processors.BaseProcessor
+	ch chan frames.Frame
+}
+
+func newFrameCapture() *frameCapture {
+	c := &frameCapture{ch: make(chan frames.Frame, 32)}
+	c.BaseProcessor = processors.NewBaseProcessor("FrameCapture", nil)
+	return c
+}
+
+func (c *frameCapture) QueueFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	select {
+	case c.ch <- frame:
+	default:
+	}
+	return nil
+}
+
+func TestElevenLabsTTSReconnectsAndReplaysAfterDrop(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	type received struct {
+		conn int
+		msg  map[string]interface{}
+	}
+	messages := make(chan received, 16)
+	var connMu sync.Mutex
+	conns := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connMu.Lock()
+		conns++
+		id := conns
+		connMu.Unlock()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			messages <- received{conn: id, msg: msg}
+			text, _ := msg["text"].(string)
+			if strings.TrimSpace(text) == "" {
+				continue // Config, keepalive or flush
+			}
+			audio := map[string]interface{}{
+				"audio":     base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4}),
+				"contextId": msg["context_id"],
+			}
+			if err := conn.WriteJSON(audio); err != nil {
+				return
+			}
+			if id == 1 {
+				// Drop mid-synthesis without a close handshake
+				conn.UnderlyingConn().Close()
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{
+		APIKey:       "test-key",
+		VoiceID:      "test-voice",
+		Model:        "eleven_flash_v2_5",
+		OutputFormat: "pcm_16000",
+		UseStreaming: true,
+	})
+	s.dialFunc = func() (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		return conn, err
+	}
+	upstream := newFrameCapture()
+	downstream := newFrameCapture()
+	s.SetPrev(upstream)
+	s.Link(downstream)
+	defer s.Cleanup()
+
+	if err := s.HandleFrame(context.Background(), frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+
+	nextMsg := func() received {
+		t.Helper()
+		select {
+		case r := <-messages:
+			return r
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return received{}
+		}
+	}
+
+	config := nextMsg()
+	text := nextMsg()
+	if config.conn != 1 || text.conn != 1 || text.msg["text"] != "Hello there. " {
+		t.Fatalf("unexpected first connection messages: %+v, %+v", config, text)
+	}
+
+	// The dropped stream is redialed with the config and the unflushed text
+	reconfig := nextMsg()
+	replay := nextMsg()
+	if reconfig.conn != 2 || reconfig.msg["text"] != " " || reconfig.msg["context_id"] != text.msg["context_id"] {
+		t.Fatalf("expected the config re-sent on a new connection, got %+v", reconfig)
+	}
+	if replay.conn != 2 || replay.msg["text"] != "Hello there. " || replay.msg["context_id"] != text.msg["context_id"] {
+		t.Fatalf("expected the unflushed text replayed in its context, got %+v", replay)
+	}
+
+	// Audio arrives from both connections, and the drop isn't reported
+	audioFrames := 0
+	deadline := time.After(2 * time.Second)
+	for audioFrames < 2 {
+		select {
+		case frame := <-downstream.ch:
+			if _, ok := frame.(*frames.TTSAudioFrame); ok {
+				audioFrames++
+			}
+		case <-deadline:
+			t.Fatalf("expected audio to resume after reconnect, got %d audio frames", audioFrames)
+		}
+	}
+	for len(upstream.ch) > 0 {
+		if frame := <-upstream.ch; frame != nil {
+			if _, ok := frame.(*frames.ErrorFrame); ok {
+				t.Fatalf("expected no ErrorFrame after a successful reconnect, got %v", frame)
+			}
+		}
+	}
+}
+
+func TestElevenLabsTTSNoReconnectWhenDisabled(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var connMu sync.Mutex
+	conns := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		connMu.Lock()
+		conns++
+		connMu.Unlock()
+		conn.ReadJSON(&map[string]interface{}{})
+		conn.UnderlyingConn().Close()
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{
+		APIKey:            "test-key",
+		VoiceID:           "test-voice",
+		Model:             "eleven_flash_v2_5",
+		UseStreaming:      true,
+		ReconnectAttempts: -1,
+	})
+	s.dialFunc = func() (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		return conn, err
+	}
+	upstream := newFrameCapture()
+	s.SetPrev(upstream)
+	defer s.Cleanup()
+
+	if err := s.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	select {
+	case frame := <-upstream.ch:
+		if _, ok := frame.(*frames.ErrorFrame); !ok {
+			t.Fatalf("expected an ErrorFrame for the dropped stream, got %v", frame)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an ErrorFrame when reconnecting is disabled")
+	}
+
+	connMu.Lock()
+	defer connMu.Unlock()
+	if conns != 1 {
+		t.Fatalf("expected no redial when reconnecting is disabled, got %d connections", conns)
+	}
+}
+
+func TestElevenLabsTTSSweepsUnfinishedAudioContexts(t *testing.T) {
+	service := NewTTSService(TTSConfig{
+		APIKey:           "test-key",
+		VoiceID:          "test-voice",
+		AudioContextTTL:  40 * time.Millisecond,
+		MaxAudioContexts: 2,
+	})
+	service.SetActiveAudioContextID("active")
+	service.createAudioContext("active")
+
+	// isFinal never arrives for these; the cap evicts the older one at once
+	service.createAudioContext("never-final-1")
+	time.Sleep(time.Millisecond)
+	service.createAudioContext("never-final-2")
+	if service.audioContextAvailable("never-final-1") {
+		t.Error("expected the oldest inactive context to be evicted beyond the cap")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	service.sweepAudioContexts()
+	if service.audioContextAvailable("never-final-2") {
+		t.Error("expected the unfinished context to be swept after the TTL")
+	}
+	if !service.audioContextAvailable("active") {
+		t.Error("expected the active context to survive the sweep")
+	}
+}
+
+func TestElevenLabsTTSFlushFrameFlushesWithoutClosingContext(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	messages := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			messages <- msg
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{
+		APIKey:       "test-key",
+		VoiceID:      "test-voice",
+		Model:        "eleven_flash_v2_5",
+		OutputFormat: "pcm_16000",
+		UseStreaming: true,
+	})
+	s.dialFunc = func() (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		return conn, err
+	}
+	downstream := newFrameCapture()
+	s.SetPrev(newFrameCapture())
+	s.Link(downstream)
+	defer s.Cleanup()
+
+	nextMsg := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case msg := <-messages:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	// Text without a sentence boundary waits in the buffer
+	ctx := context.Background()
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Partial text"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	if err := s.HandleFrame(ctx, frames.NewTTSFlushFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSFlushFrame) failed: %v", err)
+	}
+
+	nextMsg() // Config
+	text := nextMsg()
+	if got, _ := text["text"].(string); strings.TrimSpace(got) != "Partial text" {
+		t.Fatalf("expected the buffered text synthesized, got %#v", text)
+	}
+	flush := nextMsg()
+	if flush["flush"] != true || flush["context_id"] != text["context_id"] {
+		t.Fatalf("expected a flush for context %v, got %#v", text["context_id"], flush)
+	}
+
+	select {
+	case msg := <-messages:
+		t.Fatalf("expected the context left open, got %#v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if s.GetActiveAudioContextID() != text["context_id"] {
+		t.Errorf("expected context %v still active, got %q", text["context_id"], s.GetActiveAudioContextID())
+	}
+	for {
+		select {
+		case f := <-downstream.ch:
+			if _, ok := f.(*frames.TTSFlushFrame); ok {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the TTSFlushFrame passed downstream")
+		}
+	}
+}
+
+func TestElevenLabsTTSRegionAndEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		region, endpoint, wantPrefix string
+	}{
+		{"", "", "wss://api.elevenlabs.io/v1/text-to-speech/voice/"},
+		{"eu", "", "wss://api.eu.residency.elevenlabs.io/v1/text-to-speech/voice/"},
+		{"eu", "ws://127.0.0.1:9000", "ws://127.0.0.1:9000/v1/text-to-speech/voice/"},
+	} {
+		service := NewTTSService(TTSConfig{APIKey: "test-key", VoiceID: "voice", Region: tc.region, Endpoint: tc.endpoint})
+		if url := service.streamURL(); !strings.HasPrefix(url, tc.wantPrefix) {
+			t.Errorf("Region %q endpoint %q: streamURL = %s, want prefix %s", tc.region, tc.endpoint, url, tc.wantPrefix)
+		}
+	}
+}
+
+func TestElevenLabsTTSSetVoiceKeepsOldContextAudio(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var connMu sync.Mutex
+	conns := 0
+	closed := make(chan int, 2)
+	newVoiceTexts := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connMu.Lock()
+		conns++
+		id := conns
+		connMu.Unlock()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				closed <- id
+				return
+			}
+			if text, _ := msg["text"].(string); id == 2 && strings.TrimSpace(text) != "" {
+				newVoiceTexts <- text
+			}
+			if flush, _ := msg["flush"].(bool); !flush {
+				continue
+			}
+			// The flushed sentence is generated after the voice change
+			time.Sleep(50 * time.Millisecond)
+			conn.WriteJSON(map[string]interface{}{
+				"audio":     base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4}),
+				"contextId": msg["context_id"],
+			})
+			conn.WriteJSON(map[string]interface{}{"isFinal": true, "contextId": msg["context_id"]})
+		}
+	}))
+	defer server.Close()
+
+	s := NewTTSService(TTSConfig{
+		APIKey:       "test-key",
+		VoiceID:      "voice-a",
+		Model:        "eleven_flash_v2_5",
+		OutputFormat: "pcm_16000",
+		UseStreaming: true,
+	})
+	s.dialFunc = func() (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		return conn, err
+	}
+	downstream := newFrameCapture()
+	s.SetPrev(newFrameCapture())
+	s.Link(downstream)
+	defer s.Cleanup()
+
+	ctx := context.Background()
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Last sentence in the old voice. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+	oldCtxID := s.GetActiveAudioContextID()
+	if err := s.HandleFrame(ctx, frames.NewSetVoiceFrame("voice-b", "", nil), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(SetVoiceFrame) failed: %v", err)
+	}
+	if err := s.HandleFrame(ctx, frames.NewTextFrame("Hello in the new voice. "), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for gotOldAudio := false; !gotOldAudio; {
+		select {
+		case frame := <-downstream.ch:
+			if audio, ok := frame.(*frames.TTSAudioFrame); ok && audio.Metadata()["context_id"] == oldCtxID {
+				gotOldAudio = true
+			}
+		case <-deadline:
+			t.Fatal("expected the old context's audio after the voice change")
+		}
+	}
+
+	// The old connection closes once its context is final; the new one stays
+	select {
+	case id := <-closed:
+		if id != 1 {
+			t.Errorf("expected the old connection to close, got connection %d", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the old connection to close after its final message")
+	}
+	if !s.isConnected() {
+		t.Error("expected the new connection to stay open")
+	}
+	if len(newVoiceTexts) != 1 {
+		t.Errorf("expected the new text sent once on the new connection, got %d", len(newVoiceTexts))
+	}
+}
+
+func TestElevenLabsTTSStallWatchdogWaitsForFinal(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		sendFinal bool
+		wantStall bool
+	}{
+		{"stalls after first chunk", false, true},
+		{"isFinal ends the wait", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			upgrader := websocket.Upgrader{}
+			var connMu sync.Mutex
+			conns := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				connMu.Lock()
+				conns++
+				connMu.Unlock()
+				for {
+					var msg map[string]interface{}
+					if err := conn.ReadJSON(&msg); err != nil {
+						return
+					}
+					text, _ := msg["text"].(string)
+					switch {
+					case strings.TrimSpace(text) != "":
+						// Only the start of the sentence is ever generated
+						conn.WriteJSON(map[string]interface{}{
+							"audio":     base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4}),
+							"contextId": msg["context_id"],
+						})
+					case msg["close_context"] == true && tc.sendFinal:
+						conn.WriteJSON(map[string]interface{}{"isFinal": true, "contextId": msg["context_id"]})
+					}
+				}
+			}))
+			defer server.Close()
+
+			s := NewTTSService(TTSConfig{
+				APIKey:       "test-key",
+				VoiceID:      "test-voice",
+				Model:        "eleven_flash_v2_5",
+				OutputFormat: "pcm_16000",
+				UseStreaming: true,
+				StallTimeout: 100 * time.Millisecond,
+			})
+			s.dialFunc = func() (*websocket.Conn, error) {
+				conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+				return conn, err
+			}
+			upstream := newFrameCapture()
+			s.SetPrev(upstream)
+			s.Link(newFrameCapture())
+			defer s.Cleanup()
+
+			ctx := context.Background()
+			if err := s.HandleFrame(ctx, frames.NewTextFrame("Hello there. "), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(TextFrame) failed: %v", err)
+			}
+			if err := s.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(LLMFullResponseEndFrame) failed: %v", err)
+			}
+
+			stalled := false
+			timeout := time.After(400 * time.Millisecond)
+			for waiting := true; waiting && !stalled; {
+				select {
+				case frame := <-upstream.ch:
+					_, stalled = frame.(*frames.ErrorFrame)
+				case <-timeout:
+					waiting = false
+				}
+			}
+			if stalled != tc.wantStall {
+				t.Errorf("expected stall=%v, got %v", tc.wantStall, stalled)
+			}
+			if tc.wantStall {
+				time.Sleep(50 * time.Millisecond)
+				connMu.Lock()
+				defer connMu.Unlock()
+				if conns != 2 {
+					t.Errorf("expected a reconnect after the stall, got %d connections", conns)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultProbeTimeout bounds each connection made by NearestRegion
+const DefaultProbeTimeout = 2 * time.Second
+
+// RegionalHosts maps a provider's region names (e.g. "us", "eu") to the API
+// host serving that region
+type RegionalHosts map[string]string
+
+// Resolve returns the API host to use: endpoint when set, otherwise the host
+// for region, otherwise defaultHost. An unknown region is an error.
+func (h RegionalHosts) Resolve(region, endpoint, defaultHost string) (string, error) {
+	if endpoint != "" {
+		return endpoint, nil
+	}
+	if region == "" {
+		return defaultHost, nil
+	}
+	host, ok := h[strings.ToLower(region)]
+	if !ok {
+		return "", fmt.Errorf("unknown region %q (supported: %s)", region, strings.Join(h.Regions(), ", "))
+	}
+	return host, nil
+}
+
+// Regions returns the region names in sorted order
+func (h RegionalHosts) Regions() []string {
+	regions := make([]string, 0, len(h))
+	for region := range h {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// EndpointURL joins host and path into a URL with scheme. A host that
+// already carries a scheme (e.g. "ws://localhost:8080" in tests) keeps it.
+func EndpointURL(scheme, host, path string) string {
+	if strings.Contains(host, "://") {
+		return strings.TrimRight(host, "/") + path
+	}
+	return scheme + "://" + host + path
+}
+
+// NearestRegion picks the region with the lowest latency by opening a TCP
+// connection to every region's host (port 443 unless the host names one)
+// at once and returning the first to connect. Each probe is bounded by
+// timeout (default: DefaultProbeTimeout). Call it once at startup and pass
+// the result as the service's Region.
+func NearestRegion(ctx context.Context, hosts RegionalHosts, timeout time.Duration) (string, error) {
+	if len(hosts) == 0 {
+		return "", fmt.Errorf("no regions to probe")
+	}
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type probe struct {
+		region string
+		err    error
+	}
+	results := make(chan probe, len(hosts))
+	for region, host := range hosts {
+		go func() {
+			addr := host
+			if i := strings.Index(addr, "://"); i >= 0 {
+				addr = addr[i+3:]
+			}
+			addr, _, _ = strings.Cut(addr, "/")
+			if _, _, err := net.SplitHostPort(addr); err != nil {
+				addr = net.JoinHostPort(addr, "443")
+			}
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				conn.Close()
+			}
+			results <- probe{region: region, err: err}
+		}()
+	}
+
+	var errs []string
+	for range hosts {
+		r := <-results
+		if r.err == nil {
+			return r.region, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", r.region, r.err))
+	}
+	sort.Strings(errs)
+	return "", fmt.Errorf("no region reachable: %s", strings.Join(errs, "; "))
+}
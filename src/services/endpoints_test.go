@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegionalHostsResolve(t *testing.T) {
+	hosts := RegionalHosts{"us": "api.example.com", "eu": "api.eu.example.com"}
+	for _, tc := range []struct {
+		name, region, endpoint, want string
+	}{
+		{"default", "", "", "default.example.com"},
+		{"region", "eu", "", "api.eu.example.com"},
+		{"region is case insensitive", "EU", "", "api.eu.example.com"},
+		{"endpoint wins over region", "eu", "proxy.internal:8443", "proxy.internal:8443"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			host, err := hosts.Resolve(tc.region, tc.endpoint, "default.example.com")
+			if err != nil || host != tc.want {
+				t.Errorf("Resolve(%q, %q) = %q, %v; want %q", tc.region, tc.endpoint, host, err, tc.want)
+			}
+		})
+	}
+
+	_, err := hosts.Resolve("ap", "", "default.example.com")
+	if err == nil || !strings.Contains(err.Error(), "eu, us") {
+		t.Errorf("Expected unknown region error listing eu, us, got %v", err)
+	}
+}
+
+func TestEndpointURL(t *testing.T) {
+	if got := EndpointURL("wss", "api.eu.example.com", "/v1/listen"); got != "wss://api.eu.example.com/v1/listen" {
+		t.Errorf("EndpointURL = %q", got)
+	}
+	if got := EndpointURL("wss", "ws://127.0.0.1:8080/", "/v1/listen"); got != "ws://127.0.0.1:8080/v1/listen" {
+		t.Errorf("EndpointURL kept scheme = %q", got)
+	}
+}
+
+func TestNearestRegion(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	// Grab a free port and close it so dials to it are refused
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	hosts := RegionalHosts{"up": listener.Addr().String(), "down": "ws://" + closedAddr}
+	region, err := NearestRegion(context.Background(), hosts, time.Second)
+	if err != nil || region != "up" {
+		t.Errorf("NearestRegion = %q, %v; want up", region, err)
+	}
+
+	if _, err := NearestRegion(context.Background(), RegionalHosts{"down": closedAddr}, time.Second); err == nil {
+		t.Error("Expected an error when no region is reachable")
+	}
+}
@@ -0,0 +1,467 @@
+package fallback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// Config controls failover between a primary and a secondary TTS service
+type Config struct {
+	// StallTimeout is how long the primary may go without audio after being
+	// sent text before failing over. Default: services.DefaultTTSStallTimeout;
+	// negative disables stall detection (errors still fail over).
+	StallTimeout time.Duration
+
+	// SampleRate and Codec are the audio format the rest of the pipeline
+	// expects. Secondary audio in another format is converted to it. When
+	// unset, the format of the primary's audio is used once seen.
+	SampleRate int
+	Codec      string
+
+	// StayOnSecondary keeps using the secondary after a failover. By default
+	// the primary is tried again from the next LLM response.
+	StayOnSecondary bool
+}
+
+// TTS routes text to a primary TTS service and, when it reports an error or
+// stalls, replays the unanswered text on a secondary and uses it for the rest
+// of the utterance. Both services are started so failover doesn't wait for a
+// connection; only the active one's output reaches the pipeline.
+//
+// Voice and model settings apply to the primary; configure the secondary's
+// voice when creating it, since voice IDs differ between providers.
+type TTS struct {
+	*processors.BaseProcessor
+
+	primary   services.TTSService
+	secondary services.TTSService
+	config    Config
+	log       *logger.Logger
+	watchdog  *services.StallWatchdog
+
+	ctxMu sync.RWMutex
+	ctx   context.Context
+
+	mu           sync.Mutex
+	useSecondary bool
+	// Text sent to the primary and not yet known to be voiced. A primary
+	// that reports finished contexts keeps its text until the context
+	// completes; otherwise the primary's audio answers everything sent.
+	pending     []pendingText
+	reportsDone bool
+	primaryCtx  string          // Context new text goes to, from the primary's TTSStartedFrame
+	ended       map[string]bool // Contexts whose response ended, awaiting completion
+	unanswered  int             // Text frames sent since the primary's last audio
+
+	format         processors.AudioFormat
+	converter      *audio.AudioConverterProcessor
+	converterInput processors.AudioFormat
+}
+
+// pendingText is a text frame sent to the primary, with the context the
+// primary synthesizes it in ("" until its TTSStartedFrame)
+type pendingText struct {
+	frame     frames.Frame
+	contextID string
+}
+
+// audioContextReporter is a primary that reports when the provider finished
+// a context's audio (services.AudioContextManager)
+type audioContextReporter interface {
+	SetOnAudioContextCompleted(fn func(contextID string))
+}
+
+type frameBridge struct {
+	owner     *TTS
+	secondary bool
+	direction frames.FrameDirection
+	name      string
+}
+
+// WrapTTS wraps primary with failover to secondary
+func WrapTTS(primary, secondary services.TTSService, config Config) *TTS {
+	if config.StallTimeout == 0 {
+		config.StallTimeout = services.DefaultTTSStallTimeout
+	}
+	t := &TTS{
+		primary:   primary,
+		secondary: secondary,
+		config:    config,
+		log:       logger.WithPrefix("FallbackTTS"),
+		format:    processors.AudioFormat{SampleRate: config.SampleRate, Codec: audio.NormalizeCodecName(config.Codec)},
+	}
+	t.watchdog = services.NewStallWatchdog(config.StallTimeout, t.handleStall)
+	t.BaseProcessor = processors.NewBaseProcessor("FallbackTTS", t)
+	if reporter, ok := primary.(audioContextReporter); ok {
+		t.reportsDone = true
+		reporter.SetOnAudioContextCompleted(t.primaryContextCompleted)
+	}
+
+	for _, inner := range []struct {
+		service   services.TTSService
+		secondary bool
+	}{{primary, false}, {secondary, true}} {
+		inner.service.SetPrev(&frameBridge{owner: t, secondary: inner.secondary, direction: frames.Upstream, name: "FallbackTTSUpstreamBridge"})
+		inner.service.Link(&frameBridge{owner: t, secondary: inner.secondary, direction: frames.Downstream, name: "FallbackTTSDownstreamBridge"})
+	}
+	return t
+}
+
+// OutputFormat implements processors.AudioFormatProducer: the configured
+// format, which secondary audio is converted to, or else the primary's
+func (t *TTS) OutputFormat() processors.AudioFormat {
+	if t.config.SampleRate > 0 {
+		return processors.AudioFormat{SampleRate: t.config.SampleRate, Codec: audio.NormalizeCodecName(t.config.Codec)}
+	}
+	if producer, ok := t.primary.(processors.AudioFormatProducer); ok {
+		return producer.OutputFormat()
+	}
+	return processors.AudioFormat{}
+}
+
+// UsingSecondary reports whether text is currently routed to the secondary
+func (t *TTS) UsingSecondary() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.useSecondary
+}
+
+func (t *TTS) SetVoice(voiceID string) {
+	t.primary.SetVoice(voiceID)
+}
+
+func (t *TTS) SetModel(model string) {
+	t.primary.SetModel(model)
+}
+
+func (t *TTS) Initialize(ctx context.Context) error {
+	t.setContext(ctx)
+	if err := t.primary.Initialize(ctx); err != nil {
+		t.log.Warn("Primary failed to initialize, using secondary: %v", err)
+		t.mu.Lock()
+		t.useSecondary = true
+		t.mu.Unlock()
+	}
+	return t.secondary.Initialize(ctx)
+}
+
+func (t *TTS) Cleanup() error {
+	t.watchdog.Stop()
+	primaryErr := t.primary.Cleanup()
+	if err := t.secondary.Cleanup(); err != nil {
+		return err
+	}
+	return primaryErr
+}
+
+func (t *TTS) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	t.setContext(ctx)
+
+	// Lifecycle and interruptions go to both so each sets up, cancels and
+	// shuts down its own connection
+	if c, ok := frame.(frames.Categorizable); ok && c.Category() == frames.SystemCategory {
+		if _, ok := frame.(*frames.InterruptionFrame); ok {
+			t.watchdog.Stop()
+			t.mu.Lock()
+			t.clearPendingLocked()
+			t.mu.Unlock()
+		}
+		primaryErr := t.primary.ProcessFrame(ctx, frame, direction)
+		if err := t.secondary.ProcessFrame(ctx, frame, direction); err != nil {
+			return err
+		}
+		return primaryErr
+	}
+
+	if direction == frames.Downstream {
+		if _, ok := frame.(*frames.LLMFullResponseStartFrame); ok && !t.config.StayOnSecondary {
+			t.mu.Lock()
+			if t.useSecondary {
+				t.log.Info("New response, trying the primary again")
+				t.useSecondary = false
+			}
+			t.mu.Unlock()
+		}
+	}
+
+	t.mu.Lock()
+	useSecondary := t.useSecondary
+	if !useSecondary && direction == frames.Downstream && isSpeakable(frame) {
+		t.pending = append(t.pending, pendingText{frame: frame, contextID: t.primaryCtx})
+		t.unanswered++
+		t.mu.Unlock()
+		t.watchdog.Arm()
+	} else {
+		t.mu.Unlock()
+	}
+
+	if useSecondary {
+		return t.secondary.ProcessFrame(ctx, frame, direction)
+	}
+	err := t.primary.ProcessFrame(ctx, frame, direction)
+	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok && direction == frames.Downstream {
+		t.endPrimaryContext()
+	}
+	return err
+}
+
+// endPrimaryContext runs once the primary has the whole response: text
+// still pending in its context now waits for the context to complete
+func (t *TTS) endPrimaryContext() {
+	t.mu.Lock()
+	ctxID := t.primaryCtx
+	t.primaryCtx = ""
+	waiting := false
+	for _, p := range t.pending {
+		if ctxID != "" && p.contextID == ctxID {
+			waiting = true
+			break
+		}
+	}
+	if waiting {
+		if t.ended == nil {
+			t.ended = make(map[string]bool)
+		}
+		t.ended[ctxID] = true
+	}
+	t.mu.Unlock()
+	if waiting {
+		t.watchdog.Arm()
+	}
+}
+
+// primaryContextCompleted drops the text of a context the primary's
+// provider finished, disarming the watchdog once nothing is outstanding
+func (t *TTS) primaryContextCompleted(contextID string) {
+	t.mu.Lock()
+	var kept []pendingText
+	for _, p := range t.pending {
+		if p.contextID != contextID {
+			kept = append(kept, p)
+		}
+	}
+	t.pending = kept
+	delete(t.ended, contextID)
+	idle := t.unanswered == 0 && len(t.ended) == 0
+	t.mu.Unlock()
+	if idle {
+		t.watchdog.Stop()
+	}
+}
+
+// clearPendingLocked forgets all text sent to the primary. Caller must
+// hold mu.
+func (t *TTS) clearPendingLocked() {
+	t.pending = nil
+	t.primaryCtx = ""
+	t.ended = nil
+	t.unanswered = 0
+}
+
+// handleInnerFrame forwards output from the active service and fails over on
+// a primary error
+func (t *TTS) handleInnerFrame(frame frames.Frame, direction frames.FrameDirection, fromSecondary bool) error {
+	if errFrame, ok := frame.(*frames.ErrorFrame); ok && direction == frames.Upstream && !fromSecondary {
+		t.failover(fmt.Sprintf("primary error: %v", errFrame.Error))
+		return nil
+	}
+
+	t.mu.Lock()
+	active := t.useSecondary == fromSecondary
+	t.mu.Unlock()
+	if !active {
+		return nil
+	}
+
+	if started, ok := frame.(*frames.TTSStartedFrame); ok && !fromSecondary && direction == frames.Downstream {
+		t.mu.Lock()
+		if t.reportsDone && started.ContextID != "" {
+			t.primaryCtx = started.ContextID
+			for i := range t.pending {
+				if t.pending[i].contextID == "" {
+					t.pending[i].contextID = started.ContextID
+				}
+			}
+		}
+		t.mu.Unlock()
+	}
+
+	if audioFrame, ok := frame.(*frames.TTSAudioFrame); ok {
+		if fromSecondary {
+			return t.PushFrame(t.convertSecondary(audioFrame), direction)
+		}
+		t.mu.Lock()
+		t.unanswered = 0
+		// Audio outside a reported context (e.g. HTTP synthesis of each
+		// sentence) answers everything sent so far
+		if !t.reportsDone || audioContextID(audioFrame) == "" {
+			t.clearPendingLocked()
+		}
+		waiting := len(t.ended) > 0
+		if t.format.SampleRate == 0 {
+			t.format = processors.AudioFormat{SampleRate: audioFrame.SampleRate, Codec: ttsFrameCodec(audioFrame)}
+		}
+		t.mu.Unlock()
+		// Ended contexts still owe the rest of their audio
+		if waiting {
+			t.watchdog.Feed()
+		} else {
+			t.watchdog.Stop()
+		}
+	}
+	return t.PushFrame(frame, direction)
+}
+
+func (t *TTS) handleStall() {
+	t.failover(fmt.Sprintf("no audio within %v", t.config.StallTimeout))
+}
+
+// failover switches to the secondary and replays the text the primary left
+// unanswered
+func (t *TTS) failover(reason string) {
+	t.watchdog.Stop()
+	t.mu.Lock()
+	if t.useSecondary {
+		t.mu.Unlock()
+		return
+	}
+	t.useSecondary = true
+	pending := t.pending
+	ended := len(t.ended) > 0
+	t.clearPendingLocked()
+	t.mu.Unlock()
+
+	t.log.Warn("Failing over to secondary (%s), replaying %d text frames", reason, len(pending))
+	ctx := t.context()
+	for _, p := range pending {
+		if err := t.secondary.ProcessFrame(ctx, p.frame, frames.Downstream); err != nil {
+			t.log.Error("Secondary failed to take over: %v", err)
+			t.PushFrame(frames.NewErrorFrame(fmt.Errorf("fallback TTS: %w", err)), frames.Upstream)
+			return
+		}
+	}
+	// The primary already had the end of the response, so the secondary
+	// needs it too to flush the replayed text
+	if ended {
+		if err := t.secondary.ProcessFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+			t.log.Error("Secondary failed to end the response: %v", err)
+			t.PushFrame(frames.NewErrorFrame(fmt.Errorf("fallback TTS: %w", err)), frames.Upstream)
+		}
+	}
+}
+
+// convertSecondary converts secondary audio to the pipeline's format
+func (t *TTS) convertSecondary(frame *frames.TTSAudioFrame) *frames.TTSAudioFrame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	in := processors.AudioFormat{SampleRate: frame.SampleRate, Codec: ttsFrameCodec(frame)}
+	if t.format.SampleRate == 0 || in == t.format {
+		return frame
+	}
+	if t.converter == nil || t.converterInput != in {
+		t.log.Info("Converting secondary audio from %s to %s", in, t.format)
+		t.converter = audio.NewAudioConverterProcessor(audio.AudioConverterConfig{
+			InputSampleRate:  in.SampleRate,
+			InputCodec:       in.Codec,
+			OutputSampleRate: t.format.SampleRate,
+			OutputCodec:      t.format.Codec,
+		})
+		t.converterInput = in
+	}
+
+	data, err := t.converter.Convert(frame.Data, frame.SampleRate)
+	if err != nil {
+		t.log.Error("Converting secondary audio failed: %v", err)
+		return frame
+	}
+	converted := frames.NewTTSAudioFrame(data, t.format.SampleRate, frame.Channels)
+	converted.ContextID = frame.ContextID
+	for k, v := range frame.Metadata() {
+		converted.SetMetadata(k, v)
+	}
+	converted.SetMetadata("codec", t.format.Codec)
+	return converted
+}
+
+// isSpeakable reports whether frame carries text the TTS will synthesize
+func isSpeakable(frame frames.Frame) bool {
+	switch f := frame.(type) {
+	case *frames.TextFrame:
+		return !f.SkipTTS && strings.TrimSpace(f.Text) != ""
+	case *frames.LLMTextFrame:
+		return !f.SkipTTS && strings.TrimSpace(f.Text) != ""
+	}
+	return false
+}
+
+// audioContextID returns the provider context an audio frame belongs to
+func audioContextID(frame *frames.TTSAudioFrame) string {
+	if frame.ContextID != "" {
+		return frame.ContextID
+	}
+	contextID, _ := frame.Metadata()["context_id"].(string)
+	return contextID
+}
+
+func ttsFrameCodec(frame *frames.TTSAudioFrame) string {
+	if codec, ok := frame.Metadata()["codec"].(string); ok {
+		return audio.NormalizeCodecName(codec)
+	}
+	return "linear16"
+}
+
+func (t *TTS) context() context.Context {
+	t.ctxMu.RLock()
+	defer t.ctxMu.RUnlock()
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
+func (t *TTS) setContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	t.ctxMu.Lock()
+	t.ctx = ctx
+	t.ctxMu.Unlock()
+}
+
+func (b *frameBridge) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+
+func (b *frameBridge) QueueFrame(frame frames.Frame, _ frames.FrameDirection) error {
+	return b.owner.handleInnerFrame(frame, b.direction, b.secondary)
+}
+
+func (b *frameBridge) PushFrame(frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+
+func (b *frameBridge) Link(processors.FrameProcessor) {}
+
+func (b *frameBridge) SetPrev(processors.FrameProcessor) {}
+
+func (b *frameBridge) Start(context.Context) error {
+	return nil
+}
+
+func (b *frameBridge) Stop() error {
+	return nil
+}
+
+func (b *frameBridge) Name() string {
+	return b.name
+}
@@ -0,0 +1,304 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// mockTTS answers each text frame with one 20ms audio frame, an error, or
+// (when stalled) nothing
+type mockTTS struct {
+	*processors.BaseProcessor
+	sampleRate int
+	codec      string
+	fail       bool
+	stall      bool
+
+	mu    sync.Mutex
+	texts []string
+	ends  int // LLMFullResponseEndFrames received
+}
+
+func newMockTTS(name string, sampleRate int, codec string) *mockTTS {
+	m := &mockTTS{sampleRate: sampleRate, codec: codec}
+	m.BaseProcessor = processors.NewBaseProcessor(name, m)
+	return m
+}
+
+func (m *mockTTS) SetVoice(string)                  {}
+func (m *mockTTS) SetModel(string)                  {}
+func (m *mockTTS) Initialize(context.Context) error { return nil }
+func (m *mockTTS) Cleanup() error                   { return nil }
+
+func (m *mockTTS) HandleFrame(_ context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
+		m.mu.Lock()
+		m.ends++
+		m.mu.Unlock()
+	}
+	textFrame, ok := frame.(*frames.TextFrame)
+	if !ok {
+		return m.PushFrame(frame, direction)
+	}
+	m.mu.Lock()
+	m.texts = append(m.texts, textFrame.Text)
+	m.mu.Unlock()
+
+	switch {
+	case m.fail:
+		return m.PushFrame(frames.NewErrorFrame(errors.New("provider unavailable")), frames.Upstream)
+	case m.stall:
+		return nil
+	}
+	bytesPerSample := 2
+	if m.codec == "mulaw" {
+		bytesPerSample = 1
+	}
+	audioFrame := frames.NewTTSAudioFrame(make([]byte, m.sampleRate/50*bytesPerSample), m.sampleRate, 1)
+	audioFrame.SetMetadata("codec", m.codec)
+	return m.PushFrame(audioFrame, frames.Downstream)
+}
+
+func (m *mockTTS) spoken() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.texts...)
+}
+
+// streamingTTS is a primary that synthesizes in one provider context per
+// response, like the WebSocket services: audio carries the context ID, only
+// the first answerFirst texts get audio, and the test completes contexts
+type streamingTTS struct {
+	*processors.BaseProcessor
+	*services.AudioContextManager
+	answerFirst int
+
+	mu    sync.Mutex
+	texts int
+}
+
+func newStreamingTTS(answerFirst int) *streamingTTS {
+	m := &streamingTTS{AudioContextManager: services.NewAudioContextManager(), answerFirst: answerFirst}
+	m.BaseProcessor = processors.NewBaseProcessor("StreamingPrimary", m)
+	return m
+}
+
+func (m *streamingTTS) SetVoice(string)                  {}
+func (m *streamingTTS) SetModel(string)                  {}
+func (m *streamingTTS) Initialize(context.Context) error { return nil }
+func (m *streamingTTS) Cleanup() error                   { return nil }
+
+func (m *streamingTTS) HandleFrame(_ context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
+		m.ResetActiveAudioContext()
+		return m.PushFrame(frame, direction)
+	}
+	if _, ok := frame.(*frames.TextFrame); !ok {
+		return m.PushFrame(frame, direction)
+	}
+	if !m.HasActiveAudioContext() {
+		m.PushFrame(frames.NewTTSStartedFrameWithContext(m.GetOrCreateContextID()), frames.Downstream)
+	}
+	m.mu.Lock()
+	m.texts++
+	answer := m.texts <= m.answerFirst
+	m.mu.Unlock()
+	if !answer {
+		return nil
+	}
+	audioFrame := frames.NewTTSAudioFrame(make([]byte, 640), 16000, 1)
+	audioFrame.SetMetadata("codec", "linear16")
+	audioFrame.SetMetadata("context_id", m.GetActiveAudioContextID())
+	return m.PushFrame(audioFrame, frames.Downstream)
+}
+
+type captureProc struct {
+	mu     sync.Mutex
+	frames []frames.Frame
+}
+
+func (c *captureProc) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+func (c *captureProc) QueueFrame(f frames.Frame, _ frames.FrameDirection) error {
+	c.mu.Lock()
+	c.frames = append(c.frames, f)
+	c.mu.Unlock()
+	return nil
+}
+func (c *captureProc) PushFrame(frames.Frame, frames.FrameDirection) error { return nil }
+func (c *captureProc) Link(processors.FrameProcessor)                      {}
+func (c *captureProc) SetPrev(processors.FrameProcessor)                   {}
+func (c *captureProc) Start(context.Context) error                         { return nil }
+func (c *captureProc) Stop() error                                         { return nil }
+func (c *captureProc) Name() string                                        { return "capture" }
+
+func (c *captureProc) audio() []*frames.TTSAudioFrame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*frames.TTSAudioFrame
+	for _, f := range c.frames {
+		if audioFrame, ok := f.(*frames.TTSAudioFrame); ok {
+			out = append(out, audioFrame)
+		}
+	}
+	return out
+}
+
+func (c *captureProc) errors() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, f := range c.frames {
+		if _, ok := f.(*frames.ErrorFrame); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func wrapWithCapture(primary, secondary *mockTTS, config Config) (*TTS, *captureProc, *captureProc) {
+	tts := WrapTTS(primary, secondary, config)
+	downstream, upstream := &captureProc{}, &captureProc{}
+	tts.Link(downstream)
+	tts.SetPrev(upstream)
+	return tts, downstream, upstream
+}
+
+func say(t *testing.T, tts *TTS, text string) {
+	t.Helper()
+	if err := tts.HandleFrame(context.Background(), frames.NewTextFrame(text), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame failed: %v", err)
+	}
+}
+
+func TestFallbackTTS_FailsOverOnErrorWithPipelineCodec(t *testing.T) {
+	primary := newMockTTS("Primary", 8000, "mulaw")
+	primary.fail = true
+	secondary := newMockTTS("Secondary", 16000, "linear16")
+	tts, downstream, upstream := wrapWithCapture(primary, secondary, Config{SampleRate: 8000, Codec: "PCMU"})
+
+	say(t, tts, "Your order has shipped.")
+	say(t, tts, "It arrives Friday.")
+
+	if !tts.UsingSecondary() {
+		t.Fatal("expected a failover to the secondary")
+	}
+	want := []string{"Your order has shipped.", "It arrives Friday."}
+	if got := secondary.spoken(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("secondary spoke %q, want %q", got, want)
+	}
+	if n := upstream.errors(); n != 0 {
+		t.Errorf("expected the primary's error to be absorbed, %d reached the pipeline", n)
+	}
+
+	audioFrames := downstream.audio()
+	if len(audioFrames) != 2 {
+		t.Fatalf("expected 2 audio frames, got %d", len(audioFrames))
+	}
+	for _, f := range audioFrames {
+		if f.SampleRate != 8000 || f.Metadata()["codec"] != "mulaw" {
+			t.Errorf("audio is %v %dHz, want mulaw 8000Hz", f.Metadata()["codec"], f.SampleRate)
+		}
+		if len(f.Data) != 160 {
+			t.Errorf("expected 20ms of 8kHz mulaw (160 bytes), got %d", len(f.Data))
+		}
+	}
+}
+
+func TestFallbackTTS_FailsOverOnStall(t *testing.T) {
+	primary := newMockTTS("Primary", 16000, "linear16")
+	primary.stall = true
+	secondary := newMockTTS("Secondary", 16000, "linear16")
+	tts, downstream, _ := wrapWithCapture(primary, secondary, Config{StallTimeout: 50 * time.Millisecond})
+
+	say(t, tts, "Still there?")
+
+	deadline := time.Now().Add(time.Second)
+	for len(downstream.audio()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := secondary.spoken(); len(got) != 1 || got[0] != "Still there?" {
+		t.Fatalf("expected the stalled text replayed on the secondary, got %q", got)
+	}
+	if len(downstream.audio()) != 1 {
+		t.Fatalf("expected the secondary's audio downstream, got %d frames", len(downstream.audio()))
+	}
+}
+
+func TestFallbackTTS_RetriesPrimaryNextResponse(t *testing.T) {
+	primary := newMockTTS("Primary", 16000, "linear16")
+	primary.fail = true
+	secondary := newMockTTS("Secondary", 16000, "linear16")
+	tts, _, _ := wrapWithCapture(primary, secondary, Config{})
+
+	say(t, tts, "First answer.")
+	if !tts.UsingSecondary() {
+		t.Fatal("expected a failover to the secondary")
+	}
+
+	primary.fail = false
+	tts.HandleFrame(context.Background(), frames.NewLLMFullResponseStartFrame(), frames.Downstream)
+	say(t, tts, "Second answer.")
+	if tts.UsingSecondary() {
+		t.Fatal("expected the primary to be used again for a new response")
+	}
+	if got := primary.spoken(); len(got) != 2 || got[1] != "Second answer." {
+		t.Fatalf("primary spoke %q", got)
+	}
+}
+
+func TestFallbackTTS_KeepsContextTextUntilCompleted(t *testing.T) {
+	// Audio for the first sentence arrives, then the provider stalls
+	// partway through the response
+	primary := newStreamingTTS(1)
+	secondary := newMockTTS("Secondary", 16000, "linear16")
+	tts := WrapTTS(primary, secondary, Config{StallTimeout: 50 * time.Millisecond})
+	tts.Link(&captureProc{})
+	tts.SetPrev(&captureProc{})
+
+	say(t, tts, "Your order has shipped.")
+	say(t, tts, "It arrives Friday.")
+	tts.HandleFrame(context.Background(), frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+
+	deadline := time.Now().Add(time.Second)
+	for !tts.UsingSecondary() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	want := []string{"Your order has shipped.", "It arrives Friday."}
+	if got := secondary.spoken(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("expected the unfinished context replayed on the secondary, got %q", got)
+	}
+	secondary.mu.Lock()
+	ends := secondary.ends
+	secondary.mu.Unlock()
+	if ends != 1 {
+		t.Errorf("expected the response end replayed after the text, got %d", ends)
+	}
+}
+
+func TestFallbackTTS_CompletedContextDisarms(t *testing.T) {
+	primary := newStreamingTTS(2)
+	secondary := newMockTTS("Secondary", 16000, "linear16")
+	tts := WrapTTS(primary, secondary, Config{StallTimeout: 50 * time.Millisecond})
+	tts.Link(&captureProc{})
+	tts.SetPrev(&captureProc{})
+
+	say(t, tts, "Your order has shipped.")
+	contextID := primary.GetActiveAudioContextID()
+	say(t, tts, "It arrives Friday.")
+	tts.HandleFrame(context.Background(), frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+	primary.CompleteAudioContext(contextID)
+
+	time.Sleep(150 * time.Millisecond)
+	if tts.UsingSecondary() || len(secondary.spoken()) != 0 {
+		t.Fatalf("expected no failover once the context completed, secondary spoke %q", secondary.spoken())
+	}
+}
@@ -5,12 +5,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
@@ -18,23 +17,34 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
+// DefaultBaseURL is the default Gemini API endpoint
+const DefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
 // LLMService provides language model capabilities using Google Gemini
 type LLMService struct {
 	*processors.BaseProcessor
 	apiKey      string
+	baseURL     string
 	model       string
 	temperature float64
 	context     *services.LLMContext
 	ctx         context.Context
 	cancel      context.CancelFunc
 
-	// Request-scoped context for cancellable streaming (protected by streamMu)
-	requestCtx    context.Context
-	requestCancel context.CancelFunc
-	isGenerating  bool
-	lastContextAt time.Time  // When we last received a new context (for interruption filtering)
-	streamMu      sync.Mutex // Protects requestCancel, isGenerating, and lastContextAt
-	log           *logger.Logger
+	// The response being streamed, stopped by interruptions
+	stream *services.StreamGuard
+	log    *logger.Logger
+
+	// systemPrompt, once set by an LLMMessagesUpdateFrame or SetSystemPrompt,
+	// overrides the prompt of every request. The context itself is shared
+	// with the aggregators and is never rewritten.
+	systemPrompt string
+
+	safetyFallback       string
+	nonStreamingFallback bool
+
+	// Sampling overrides from an LLMParamsFrame for the next generation only
+	nextParams services.NextLLMParams
 }
 
 // LLMConfig holds configuration for Gemini
@@ -43,18 +53,94 @@ type LLMConfig struct {
 	Model        string // e.g., "gemini-1.5-pro", "gemini-1.5-flash"
 	SystemPrompt string
 	Temperature  float64
+	BaseURL      string // Optional: override default API URL
+
+	// SafetyFallback is spoken when Gemini blocks a response (SAFETY,
+	// RECITATION, a blocked prompt, ...) before producing any text. If empty
+	// a BlockedError is pushed upstream as an ErrorFrame instead. It is a
+	// text/template rendered against the call's TemplateContext.
+	SafetyFallback string
+
+	// NonStreamingFallback retries with the non-streaming generateContent
+	// endpoint when the SSE request fails before any text is produced
+	NonStreamingFallback bool
+}
+
+// blockedFinishReasons are the finish reasons where Gemini withheld content
+var blockedFinishReasons = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+}
+
+// BlockedError is returned when Gemini blocks a response without producing any text
+type BlockedError struct {
+	Reason string // blockReason or finishReason, e.g. "SAFETY"
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("gemini response blocked: %s", e.Reason)
+}
+
+// generateContentResponse is a generateContent response or streamed chunk
+type generateContentResponse struct {
+	Candidates     []responseCandidate `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+}
+
+type responseCandidate struct {
+	Index   int `json:"index"`
+	Content struct {
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"content"`
+	FinishReason string `json:"finishReason"`
+}
+
+// primaryCandidate returns the candidate with index 0, or nil if there is none
+func (r *generateContentResponse) primaryCandidate() *responseCandidate {
+	for i := range r.Candidates {
+		if r.Candidates[i].Index == 0 {
+			return &r.Candidates[i]
+		}
+	}
+	return nil
+}
+
+// responseState accumulates a response across streamed chunks
+type responseState struct {
+	gen          *services.Generation
+	text         strings.Builder
+	finishReason string
+	blockReason  string
 }
 
 // NewLLMService creates a new Gemini LLM service
 func NewLLMService(config LLMConfig) *LLMService {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	gs := &LLMService{
 		apiKey:      config.APIKey,
+		baseURL:     baseURL,
 		model:       config.Model,
 		temperature: config.Temperature,
 		context:     services.NewLLMContext(config.SystemPrompt),
 		log:         logger.WithPrefix("GeminiLLM"),
+
+		safetyFallback:       config.SafetyFallback,
+		nonStreamingFallback: config.NonStreamingFallback,
 	}
 	gs.BaseProcessor = processors.NewBaseProcessor("Gemini", gs)
+	gs.AttachLogger(gs.log)
+	gs.stream = services.NewStreamGuard(gs.BaseProcessor, gs.log)
 	return gs
 }
 
@@ -62,8 +148,10 @@ func (s *LLMService) SetModel(model string) {
 	s.model = model
 }
 
+// SetSystemPrompt replaces the system prompt sent with every request. An
+// empty prompt clears the override and falls back to the context's own.
 func (s *LLMService) SetSystemPrompt(prompt string) {
-	s.context.SystemPrompt = prompt
+	s.systemPrompt = prompt
 }
 
 func (s *LLMService) SetTemperature(temp float64) {
@@ -95,34 +183,23 @@ func (s *LLMService) Cleanup() error {
 }
 
 func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// Handle InterruptionFrame - CRITICAL: Stop streaming immediately
-	// BUT: If we just received a new context (within 100ms), this interruption is for
-	// the OLD response, not the new one. Don't cancel in that case.
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
-		s.streamMu.Lock()
-		isGen := s.isGenerating
-		hasCancel := s.requestCancel != nil
-		timeSinceContext := time.Since(s.lastContextAt)
-		isNewContext := timeSinceContext < 100*time.Millisecond
-
-		s.log.Debug("Interruption received (isGenerating=%v, hasCancel=%v, timeSinceContext=%v)", isGen, hasCancel, timeSinceContext)
-
-		if isNewContext {
-			// This interruption is for the OLD response, not the new one we just started
-			s.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
-			s.streamMu.Unlock()
-			return s.PushFrame(frame, direction)
-		}
+	// Handle LLMParamsFrame - override sampling for the next generation
+	if params, ok := frame.(*frames.LLMParamsFrame); ok {
+		s.nextParams.Set(params)
+		return nil
+	}
 
-		if isGen && hasCancel {
-			s.log.Info("Cancelling ongoing stream")
-			s.requestCancel()
-			s.isGenerating = false
-			s.log.Info("Stream cancelled, isGenerating=false")
-		} else {
-			s.log.Debug("No active stream to cancel")
+	// Handle LLMCancelGenerationFrame and InterruptionFrame - stop streaming
+	if handled, err := s.stream.HandleFrame(frame, direction); handled {
+		return err
+	}
+
+	// Handle LLMMessagesUpdateFrame - swap the system prompt for later turns
+	if updateFrame, ok := frame.(*frames.LLMMessagesUpdateFrame); ok {
+		if updateFrame.SystemPrompt != "" {
+			s.log.Info("Updating system prompt")
+			s.systemPrompt = updateFrame.SystemPrompt
 		}
-		s.streamMu.Unlock()
 		return s.PushFrame(frame, direction)
 	}
 
@@ -133,9 +210,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.log.Info("Received LLMContextFrame with %d messages", len(llmContext.Messages))
 
 			// Record when we received this context (for interruption filtering)
-			s.streamMu.Lock()
-			s.lastContextAt = time.Now()
-			s.streamMu.Unlock()
+			s.stream.ContextReceived()
 
 			// Update our context reference
 			s.context = llmContext
@@ -144,9 +219,10 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewLLMFullResponseStartFrame(), frames.Downstream)
 
 			// Generate response using the provided context
-			if err := s.generateResponse(); err != nil {
+			gen := s.stream.Begin(s.ctx, llmContext)
+			if err := gen.Run(func() error { return s.generateResponse(gen) }); err != nil {
 				// Only log error if not cancelled
-				if s.requestCtx != nil && s.requestCtx.Err() == context.Canceled {
+				if gen.Cancelled() || errors.Is(err, context.Canceled) {
 					s.log.Info("Stream cancelled by interruption")
 				} else {
 					s.log.Error("Error generating response: %v", err)
@@ -155,7 +231,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			}
 
 			// Send LLM response end marker
-			s.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+			gen.PushResponseEnd()
 		}
 		return nil
 	}
@@ -164,72 +240,61 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	return s.PushFrame(frame, direction)
 }
 
-func (s *LLMService) generateResponse() error {
-	// Create cancellable context for this request
-	// Use background context if s.ctx is nil (Initialize not called yet)
-	parentCtx := s.ctx
-	if parentCtx == nil {
-		parentCtx = context.Background()
-	}
-
-	// Lock to safely set stream state (read by InterruptionFrame handler)
-	s.streamMu.Lock()
-	s.requestCtx, s.requestCancel = context.WithCancel(parentCtx)
-	s.isGenerating = true
-	s.streamMu.Unlock()
-
-	s.log.Info("Starting stream generation (isGenerating=true)")
-	defer func() {
-		s.streamMu.Lock()
-		wasGenerating := s.isGenerating
-		s.isGenerating = false
-		if s.requestCancel != nil {
-			s.requestCancel()
-		}
-		s.requestCancel = nil
-		s.streamMu.Unlock()
-		s.log.Info("Stream generation ended (wasGenerating=%v)", wasGenerating)
-	}()
-
+func (s *LLMService) generateResponse(gen *services.Generation) error {
 	// Build contents array (Gemini format)
 	contents := []map[string]interface{}{}
 
-	// Add system instruction in first user message if available
-	if s.context.SystemPrompt != "" && len(s.context.Messages) == 1 {
+	for _, msg := range s.context.Messages {
+		role := msg.Role
+		if role == "developer" {
+			role = "user" // Gemini does not support the "developer" role
+		}
+		if role == "assistant" {
+			role = "model" // Gemini uses "model" instead of "assistant"
+		}
+		if role == "system" {
+			continue // Skip system messages (handled differently)
+		}
+
 		contents = append(contents, map[string]interface{}{
-			"role": "user",
+			"role": role,
 			"parts": []map[string]string{
-				{"text": s.context.SystemPrompt + "\n\n" + s.context.Messages[0].Content},
+				{"text": msg.Content},
 			},
 		})
-	} else {
-		for _, msg := range s.context.Messages {
-			role := msg.Role
-			if role == "developer" {
-				role = "user" // Gemini does not support the "developer" role
-			}
-			if role == "assistant" {
-				role = "model" // Gemini uses "model" instead of "assistant"
-			}
-			if role == "system" {
-				continue // Skip system messages (handled differently)
-			}
-
-			contents = append(contents, map[string]interface{}{
-				"role": role,
-				"parts": []map[string]string{
-					{"text": msg.Content},
-				},
-			})
-		}
 	}
 
 	// Prepare request
+	generationConfig := map[string]interface{}{
+		"temperature": s.temperature,
+	}
+	params := s.nextParams.Take()
+	if params.Temperature != nil {
+		generationConfig["temperature"] = *params.Temperature
+	}
+	if params.TopP != nil {
+		generationConfig["topP"] = *params.TopP
+	}
+	if params.MaxTokens != nil {
+		generationConfig["maxOutputTokens"] = *params.MaxTokens
+	}
 	requestBody := map[string]interface{}{
-		"contents": contents,
-		"generationConfig": map[string]interface{}{
-			"temperature": s.temperature,
-		},
+		"contents":         contents,
+		"generationConfig": generationConfig,
+	}
+
+	// Send the system prompt as a system instruction so it applies on every
+	// turn, including after it is swapped mid-conversation
+	systemPrompt := s.context.SystemPrompt
+	if s.systemPrompt != "" {
+		systemPrompt = s.systemPrompt
+	}
+	if systemPrompt != "" {
+		requestBody["systemInstruction"] = map[string]interface{}{
+			"parts": []map[string]string{
+				{"text": systemPrompt},
+			},
+		}
 	}
 
 	bodyBytes, err := json.Marshal(requestBody)
@@ -237,42 +302,72 @@ func (s *LLMService) generateResponse() error {
 		return err
 	}
 
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?key=%s&alt=sse",
-		s.model, s.apiKey)
+	// Hold a stream slot until the response has been read, including the
+	// non-streaming retry
+	release, err := services.RateLimiterFor("gemini", s.apiKey).Acquire(gen.Context())
+	if err != nil {
+		if gen.Context().Err() == context.Canceled {
+			return nil // Interrupted while queued
+		}
+		return fmt.Errorf("Gemini: %w", err)
+	}
+	defer release()
 
-	// Use cancellable context so interruption can stop the request
-	req, err := http.NewRequestWithContext(s.requestCtx, "POST", url, bytes.NewReader(bodyBytes))
+	state := &responseState{gen: gen}
+	err = s.streamContent(bodyBytes, state)
+	if gen.Context().Err() == context.Canceled {
+		return nil // Not an error, just interrupted
+	}
 	if err != nil {
-		return err
+		// Only fall back if nothing was spoken yet, otherwise we'd repeat it
+		if !s.nonStreamingFallback || state.text.Len() > 0 {
+			return err
+		}
+		s.log.Warn("Streaming request failed, retrying without streaming: %v", err)
+		if err := s.generateContent(bodyBytes, state); err != nil {
+			if gen.Context().Err() == context.Canceled {
+				return nil
+			}
+			return err
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	response := state.text.String()
+	if response == "" && state.blockReason != "" {
+		return s.handleBlocked(state)
+	}
+	if state.blockReason != "" {
+		s.log.Warn("Response cut off (%s) after %d chars", state.blockReason, len(response))
+	} else if state.finishReason == "MAX_TOKENS" {
+		s.log.Warn("Response truncated at max output tokens")
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Add assistant response to context
+	gen.AddReply(services.LLMMessage{Role: "assistant", Content: response})
+	s.log.Debug("Assistant response length: %d", len(response))
+
+	return nil
+}
+
+// streamContent calls streamGenerateContent and consumes the SSE stream
+func (s *LLMService) streamContent(bodyBytes []byte, state *responseState) error {
+	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?key=%s&alt=sse",
+		s.baseURL, s.model, s.apiKey)
+
+	resp, err := s.post(state.gen.Context(), url, bodyBytes)
 	if err != nil {
-		// Check if cancelled by interruption
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
-		}
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gemini API error: %s", string(body))
-	}
-
 	// Stream response (SSE format)
-	var fullResponse strings.Builder
 	scanner := bufio.NewScanner(resp.Body)
 
 	for scanner.Scan() {
 		// Check if interrupted
 		select {
-		case <-s.requestCtx.Done():
-			s.log.Info("Stream interrupted mid-generation, stopping immediately (tokens so far: %d chars)", fullResponse.Len())
+		case <-state.gen.Context().Done():
+			s.log.Info("Stream interrupted mid-generation, stopping immediately (tokens so far: %d chars)", state.text.Len())
 			return nil
 		default:
 		}
@@ -284,43 +379,101 @@ func (s *LLMService) generateResponse() error {
 
 		data := strings.TrimPrefix(line, "data: ")
 
-		var streamResp struct {
-			Candidates []struct {
-				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				} `json:"content"`
-			} `json:"candidates"`
-		}
-
-		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
+		var chunk generateContentResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 			continue
 		}
+		s.consume(&chunk, state)
+	}
 
-		if len(streamResp.Candidates) > 0 && len(streamResp.Candidates[0].Content.Parts) > 0 {
-			content := streamResp.Candidates[0].Content.Parts[0].Text
-			if content != "" {
-				fullResponse.WriteString(content)
-				// Send token as LLM text frame
-				textFrame := frames.NewLLMTextFrame(content)
-				s.PushFrame(textFrame, frames.Downstream)
-			}
+	return scanner.Err()
+}
+
+// generateContent calls the non-streaming generateContent endpoint, used
+// when the SSE stream fails
+func (s *LLMService) generateContent(bodyBytes []byte, state *responseState) error {
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s",
+		s.baseURL, s.model, s.apiKey)
+
+	resp, err := s.post(state.gen.Context(), url, bodyBytes)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result generateContentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode gemini response: %w", err)
+	}
+	s.consume(&result, state)
+	return nil
+}
+
+// post sends a request bound to the generation's context and checks the status
+func (s *LLMService) post(ctx context.Context, url string, bodyBytes []byte) (*http.Response, error) {
+	// Use cancellable context so interruption can stop the request
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("gemini API error: %s", string(body))
+	}
+	return resp, nil
+}
+
+// consume pushes the text of one response chunk downstream and records why
+// generation finished. Only the first candidate is spoken; the others are
+// alternatives, not continuations.
+func (s *LLMService) consume(chunk *generateContentResponse, state *responseState) {
+	if chunk.PromptFeedback != nil && chunk.PromptFeedback.BlockReason != "" {
+		state.blockReason = chunk.PromptFeedback.BlockReason
+	}
+
+	cand := chunk.primaryCandidate()
+	if cand == nil {
+		return
+	}
+
+	for _, part := range cand.Content.Parts {
+		if part.Text == "" {
+			continue
 		}
+		state.text.WriteString(part.Text)
+		// Send token as LLM text frame
+		state.gen.Push(frames.NewLLMTextFrame(part.Text))
 	}
 
-	// Check if scanner error was due to cancellation
-	if err := scanner.Err(); err != nil {
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
+	if cand.FinishReason != "" {
+		state.finishReason = cand.FinishReason
+		if blockedFinishReasons[cand.FinishReason] {
+			state.blockReason = cand.FinishReason
 		}
-		return err
 	}
+}
 
-	// Add assistant response to context
-	response := fullResponse.String()
-	s.context.AddAssistantMessage(response)
-	s.log.Debug("Assistant response length: %d", len(response))
+// handleBlocked speaks the configured fallback for a response that was
+// blocked before producing any text, or reports it as an error
+func (s *LLMService) handleBlocked(state *responseState) error {
+	reason := state.blockReason
+	if s.safetyFallback == "" {
+		return &BlockedError{Reason: reason}
+	}
 
+	fallback := s.RenderTemplate(s.safetyFallback)
+	s.log.Warn("Response blocked (%s), speaking fallback", reason)
+	state.gen.Push(frames.NewLLMTextFrame(fallback))
+	state.gen.AddReply(services.LLMMessage{Role: "assistant", Content: fallback})
 	return nil
 }
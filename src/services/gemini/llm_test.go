@@ -0,0 +1,227 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func TestLLMServiceSystemPromptUpdate(t *testing.T) {
+	var mu sync.Mutex
+	var systemPrompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.0-flash:streamGenerateContent" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		var body struct {
+			SystemInstruction struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"systemInstruction"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		system := ""
+		if len(body.SystemInstruction.Parts) > 0 {
+			system = body.SystemInstruction.Parts[0].Text
+		}
+		mu.Lock()
+		systemPrompts = append(systemPrompts, system)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"ok\"}]}}]}\n\n")
+	}))
+	defer server.Close()
+
+	service := NewLLMService(LLMConfig{
+		APIKey:  "test-key",
+		Model:   "gemini-2.0-flash",
+		BaseURL: server.URL,
+	})
+	ctx := context.Background()
+
+	llmCtx := services.NewLLMContext("You are a sales agent")
+	llmCtx.AddUserMessage("hi")
+	if err := service.HandleFrame(ctx, frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	if err := service.HandleFrame(ctx, frames.NewLLMSystemPromptUpdateFrame("You are a support agent"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMMessagesUpdateFrame) failed: %v", err)
+	}
+
+	llmCtx.AddUserMessage("I need help")
+	if err := service.HandleFrame(ctx, frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(systemPrompts) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(systemPrompts))
+	}
+	if systemPrompts[0] != "You are a sales agent" {
+		t.Errorf("Expected original prompt on first request, got %q", systemPrompts[0])
+	}
+	if systemPrompts[1] != "You are a support agent" {
+		t.Errorf("Expected updated prompt on next request, got %q", systemPrompts[1])
+	}
+	if llmCtx.SystemPrompt != "You are a sales agent" {
+		t.Errorf("Expected the shared context to keep its prompt, got %q", llmCtx.SystemPrompt)
+	}
+}
+
+// runGeminiTurn sends one user turn to a service backed by handler and
+// returns the text spoken downstream and any errors pushed upstream
+func runGeminiTurn(t *testing.T, cfg LLMConfig, handler http.HandlerFunc) (*LLMService, string, []error) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	cfg.APIKey = "test-key"
+	cfg.Model = "gemini-2.0-flash"
+	cfg.BaseURL = server.URL
+	service := NewLLMService(cfg)
+	up := newFrameCollector("upstream")
+	down := newFrameCollector("downstream")
+	service.Link(down)
+	service.SetPrev(up)
+
+	llmCtx := services.NewLLMContext("")
+	llmCtx.AddUserMessage("hi")
+	if err := service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	var text strings.Builder
+	for len(down.ch) > 0 {
+		if f, ok := (<-down.ch).(*frames.LLMTextFrame); ok {
+			text.WriteString(f.Text)
+		}
+	}
+	var errs []error
+	for len(up.ch) > 0 {
+		if f, ok := (<-up.ch).(*frames.ErrorFrame); ok {
+			errs = append(errs, f.Error)
+		}
+	}
+	return service, text.String(), errs
+}
+
+func sseHandler(chunks ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+	}
+}
+
+const safetyBlockedChunk = `{"candidates":[{"index":0,"finishReason":"SAFETY","safetyRatings":[{"category":"HARM_CATEGORY_HARASSMENT","probability":"HIGH","blocked":true}]}]}`
+
+func TestLLMServiceSafetyBlockedPushesError(t *testing.T) {
+	service, text, errs := runGeminiTurn(t, LLMConfig{}, sseHandler(safetyBlockedChunk))
+
+	if text != "" {
+		t.Errorf("Expected no text, got %q", text)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 ErrorFrame, got %d", len(errs))
+	}
+	var blocked *BlockedError
+	if !errors.As(errs[0], &blocked) || blocked.Reason != "SAFETY" {
+		t.Errorf("Expected BlockedError(SAFETY), got %v", errs[0])
+	}
+	if n := len(service.context.Messages); n != 1 {
+		t.Errorf("Expected no assistant message for a blocked response, got %d messages", n)
+	}
+}
+
+func TestLLMServiceSafetyBlockedSpeaksFallback(t *testing.T) {
+	fallback := "Sorry, I can't help with that."
+	service, text, errs := runGeminiTurn(t, LLMConfig{SafetyFallback: fallback}, sseHandler(safetyBlockedChunk))
+
+	if text != fallback {
+		t.Errorf("Expected fallback %q, got %q", fallback, text)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors with a fallback, got %v", errs)
+	}
+	msgs := service.context.Messages
+	if last := msgs[len(msgs)-1]; last.Role != "assistant" || last.Content != fallback {
+		t.Errorf("Expected fallback in context, got %+v", last)
+	}
+}
+
+func TestLLMServicePromptBlocked(t *testing.T) {
+	_, _, errs := runGeminiTurn(t, LLMConfig{}, sseHandler(`{"promptFeedback":{"blockReason":"PROHIBITED_CONTENT"}}`))
+
+	var blocked *BlockedError
+	if len(errs) != 1 || !errors.As(errs[0], &blocked) || blocked.Reason != "PROHIBITED_CONTENT" {
+		t.Errorf("Expected BlockedError(PROHIBITED_CONTENT), got %v", errs)
+	}
+}
+
+func TestLLMServiceSpeaksOnlyPrimaryCandidate(t *testing.T) {
+	service, text, errs := runGeminiTurn(t, LLMConfig{}, sseHandler(
+		`{"candidates":[{"index":1,"content":{"parts":[{"text":"Other "}]}},{"index":0,"content":{"parts":[{"text":"Hello "},{"text":"there"}]}}]}`,
+		`{"candidates":[{"index":0,"content":{"parts":[{"text":"!"}]},"finishReason":"STOP"}]}`,
+	))
+
+	if text != "Hello there!" {
+		t.Errorf("Expected primary candidate text, got %q", text)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	if last := service.context.Messages[len(service.context.Messages)-1]; last.Content != "Hello there!" {
+		t.Errorf("Expected full response in context, got %q", last.Content)
+	}
+}
+
+func TestLLMServiceNonStreamingFallback(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, ":streamGenerateContent") {
+			http.Error(w, "stream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates":[{"index":0,"content":{"parts":[{"text":"Recovered"}]},"finishReason":"STOP"}]}`)
+	}
+
+	_, text, errs := runGeminiTurn(t, LLMConfig{NonStreamingFallback: true}, handler)
+	if text != "Recovered" {
+		t.Errorf("Expected non-streaming response, got %q", text)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	mu.Lock()
+	if len(paths) != 2 || !strings.HasSuffix(paths[1], ":generateContent") {
+		t.Errorf("Expected streaming then non-streaming request, got %v", paths)
+	}
+	mu.Unlock()
+
+	// Without the option the streaming error is reported
+	_, _, errs = runGeminiTurn(t, LLMConfig{}, handler)
+	if len(errs) != 1 {
+		t.Errorf("Expected streaming error without fallback, got %v", errs)
+	}
+}
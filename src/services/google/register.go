@@ -0,0 +1,24 @@
+package google
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterTTS("google", func(config services.ServiceConfig) (services.TTSService, error) {
+		if config.String(services.ConfigAPIKey) == "" && config.String("service_account") == "" {
+			return nil, fmt.Errorf("%s or service_account is required", services.ConfigAPIKey)
+		}
+		return NewGoogleTTSService(TTSConfig{
+			APIKey:         config.String(services.ConfigAPIKey),
+			ServiceAccount: config.String("service_account"),
+			LanguageCode:   config.String(services.ConfigLanguage),
+			VoiceName:      config.String(services.ConfigVoice),
+			Gender:         VoiceGender(config.String("gender")),
+			Encoding:       AudioEncoding(config.String(services.ConfigEncoding)),
+			SampleRate:     config.Int(services.ConfigSampleRate),
+		}), nil
+	})
+}
@@ -75,6 +75,9 @@ type GoogleTTSService struct {
 
 	// Lifecycle
 	started bool
+
+	// Converts provider audio to the configured output format (nil = none)
+	outputConverter *services.TTSOutputConverter
 }
 
 // TTSConfig holds configuration for Google TTS
@@ -86,6 +89,13 @@ type TTSConfig struct {
 	Gender         VoiceGender   // MALE, FEMALE, NEUTRAL
 	Encoding       AudioEncoding // LINEAR16, MP3, OGG_OPUS, MULAW, ALAW
 	SampleRate     int           // Sample rate in Hz (e.g., 16000, 24000)
+
+	// OutputSampleRate and OutputCodec ("mulaw", "alaw", "linear16") convert
+	// the provider's audio to the transport's format inside the service, e.g.
+	// to 8kHz mulaw for telephony. Either may be left unset to keep the
+	// provider's. Default: no conversion.
+	OutputSampleRate int
+	OutputCodec      string
 }
 
 // NewGoogleTTSService creates a new Google TTS service
@@ -125,6 +135,8 @@ func NewGoogleTTSService(config TTSConfig) *GoogleTTSService {
 		encoding:       encoding,
 		sampleRate:     sampleRate,
 		httpClient:     &http.Client{},
+
+		outputConverter: services.NewTTSOutputConverter(config.OutputSampleRate, config.OutputCodec),
 	}
 
 	service.BaseProcessor = processors.NewBaseProcessor("GoogleTTS", service)
@@ -154,6 +166,11 @@ func (s *GoogleTTSService) SetModel(model string) {
 	// Voice name determines the model
 }
 
+// OutputFormat implements processors.AudioFormatProducer
+func (s *GoogleTTSService) OutputFormat() processors.AudioFormat {
+	return s.outputConverter.Format(processors.AudioFormat{SampleRate: s.sampleRate, Codec: s.getCodec()})
+}
+
 // HandleFrame processes frames
 func (s *GoogleTTSService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
 	switch f := frame.(type) {
@@ -168,6 +185,13 @@ func (s *GoogleTTSService) HandleFrame(ctx context.Context, frame frames.Frame,
 	case *frames.InterruptionFrame:
 		// Reset context ID on interruption
 		s.contextID = ""
+		s.TraceInterruption(f.CorrelationID, frames.InterruptionStageTTSCancelled)
+		return s.PushFrame(frame, direction)
+
+	case *frames.SetVoiceFrame:
+		if f.VoiceID != "" {
+			s.SetVoice(f.VoiceID)
+		}
 		return s.PushFrame(frame, direction)
 
 	case *frames.TextFrame:
@@ -277,6 +301,9 @@ func (s *GoogleTTSService) synthesize(ctx context.Context, text string) error {
 	audioFrame := frames.NewTTSAudioFrame(audioData, s.sampleRate, 1)
 	audioFrame.SetMetadata("codec", codec)
 	audioFrame.SetMetadata("context_id", contextID)
+	if err := s.outputConverter.Convert(audioFrame); err != nil {
+		logger.Warn("[GoogleTTS] Output conversion failed: %v", err)
+	}
 	if err := s.PushFrame(audioFrame, frames.Downstream); err != nil {
 		return err
 	}
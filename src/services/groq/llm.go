@@ -1,21 +1,13 @@
 package groq
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
-	"sync"
-	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
 	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/services/openaicompat"
 )
 
 // GroqLLMService provides language model capabilities using Groq's OpenAI-compatible API
@@ -31,12 +23,11 @@ type GroqLLMService struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 
-	// Request-scoped context for cancellable streaming (protected by streamMu)
-	requestCtx    context.Context
-	requestCancel context.CancelFunc
-	isGenerating  bool
-	lastContextAt time.Time  // When we last received a new context (for interruption filtering)
-	streamMu      sync.Mutex // Protects requestCancel, isGenerating, and lastContextAt
+	// The response being streamed, stopped by interruptions
+	stream *services.StreamGuard
+
+	// Sampling overrides from an LLMParamsFrame for the next generation only
+	nextParams services.NextLLMParams
 }
 
 // GroqLLMConfig holds configuration for Groq
@@ -76,6 +67,8 @@ func NewGroqLLMService(config GroqLLMConfig) *GroqLLMService {
 		log:         logger.WithPrefix("GroqLLM"),
 	}
 	gs.BaseProcessor = processors.NewBaseProcessor("Groq", gs)
+	gs.AttachLogger(gs.log)
+	gs.stream = services.NewStreamGuard(gs.BaseProcessor, gs.log)
 	return gs
 }
 
@@ -116,30 +109,15 @@ func (s *GroqLLMService) Cleanup() error {
 }
 
 func (s *GroqLLMService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// Handle InterruptionFrame - CRITICAL: Stop streaming immediately
-	// BUT: If we just received a new context (within 100ms), this interruption is for
-	// the OLD response, not the new one. Don't cancel in that case.
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
-		s.streamMu.Lock()
-		timeSinceContext := time.Since(s.lastContextAt)
-		isNewContext := timeSinceContext < 100*time.Millisecond
-
-		s.log.Warn("Interruption received (isGenerating=%v, timeSinceContext=%v)", s.isGenerating, timeSinceContext)
-
-		if isNewContext {
-			// This interruption is for the OLD response, not the new one we just started
-			s.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
-			s.streamMu.Unlock()
-			return s.PushFrame(frame, direction)
-		}
+	// Handle LLMParamsFrame - override sampling for the next generation
+	if params, ok := frame.(*frames.LLMParamsFrame); ok {
+		s.nextParams.Set(params)
+		return nil
+	}
 
-		if s.isGenerating && s.requestCancel != nil {
-			s.log.Warn("Cancelling ongoing stream")
-			s.requestCancel()
-			s.isGenerating = false
-		}
-		s.streamMu.Unlock()
-		return s.PushFrame(frame, direction)
+	// Handle LLMCancelGenerationFrame and InterruptionFrame - stop streaming
+	if handled, err := s.stream.HandleFrame(frame, direction); handled {
+		return err
 	}
 
 	// Handle LLMContextFrame (from aggregators)
@@ -149,9 +127,7 @@ func (s *GroqLLMService) HandleFrame(ctx context.Context, frame frames.Frame, di
 			s.log.Debug("Received LLMContextFrame with %d messages", len(llmContext.Messages))
 
 			// Record when we received this context (for interruption filtering)
-			s.streamMu.Lock()
-			s.lastContextAt = time.Now()
-			s.streamMu.Unlock()
+			s.stream.ContextReceived()
 
 			// Update our context reference
 			s.context = llmContext
@@ -160,9 +136,10 @@ func (s *GroqLLMService) HandleFrame(ctx context.Context, frame frames.Frame, di
 			s.PushFrame(frames.NewLLMFullResponseStartFrame(), frames.Downstream)
 
 			// Generate response using the provided context
-			if err := s.generateResponseFromContext(llmContext); err != nil {
+			gen := s.stream.Begin(s.ctx, llmContext)
+			if err := gen.Run(func() error { return s.generateResponseFromContext(gen, llmContext) }); err != nil {
 				// Only log error if not cancelled
-				if s.requestCtx != nil && s.requestCtx.Err() == context.Canceled {
+				if gen.Cancelled() {
 					s.log.Debug("Stream cancelled by interruption")
 				} else {
 					s.log.Error("Error generating response: %v", err)
@@ -171,7 +148,7 @@ func (s *GroqLLMService) HandleFrame(ctx context.Context, frame frames.Frame, di
 			}
 
 			// Send LLM response end marker
-			s.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+			gen.PushResponseEnd()
 		}
 		return nil
 	}
@@ -182,215 +159,24 @@ func (s *GroqLLMService) HandleFrame(ctx context.Context, frame frames.Frame, di
 
 // generateResponseFromContext generates a response using the provided context
 // Supports full message format including tool calls
-func (s *GroqLLMService) generateResponseFromContext(llmCtx *services.LLMContext) error {
-	// Create cancellable context for this request
-	// Use background context if s.ctx is nil (Initialize not called yet)
-	parentCtx := s.ctx
-	if parentCtx == nil {
-		parentCtx = context.Background()
-	}
-
-	// Lock to safely set stream state (read by InterruptionFrame handler)
-	s.streamMu.Lock()
-	s.requestCtx, s.requestCancel = context.WithCancel(parentCtx)
-	s.isGenerating = true
-	s.streamMu.Unlock()
-
-	defer func() {
-		s.streamMu.Lock()
-		s.isGenerating = false
-		if s.requestCancel != nil {
-			s.requestCancel()
-		}
-		s.requestCancel = nil
-		s.streamMu.Unlock()
-	}()
-
-	// Build messages array from context
-	messages := []map[string]interface{}{}
-
-	// Add system prompt if present
-	if llmCtx.SystemPrompt != "" {
-		messages = append(messages, map[string]interface{}{
-			"role":    "system",
-			"content": llmCtx.SystemPrompt,
-		})
-	}
-
-	// Add all messages from context
-	for _, msg := range llmCtx.Messages {
-		// "developer" is an OpenAI-specific role; Groq uses the OpenAI wire format
-		// but does not accept "developer" — map it to "user".
-		role := msg.Role
-		if role == "developer" {
-			role = "user"
-		}
-		message := map[string]interface{}{
-			"role": role,
-		}
-
-		// Add content if present
-		if msg.Content != "" {
-			message["content"] = msg.Content
-		}
-
-		// Add tool calls if present (assistant messages)
-		if len(msg.ToolCalls) > 0 {
-			toolCalls := []map[string]interface{}{}
-			for _, tc := range msg.ToolCalls {
-				toolCalls = append(toolCalls, map[string]interface{}{
-					"id":   tc.ID,
-					"type": tc.Type,
-					"function": map[string]interface{}{
-						"name":      tc.Function.Name,
-						"arguments": tc.Function.Arguments,
-					},
-				})
-			}
-			message["tool_calls"] = toolCalls
-		}
-
-		// Add tool_call_id if present (tool messages)
-		if msg.ToolCallID != "" {
-			message["tool_call_id"] = msg.ToolCallID
-		}
-
-		messages = append(messages, message)
-	}
-
-	// Prepare request
-	requestBody := map[string]interface{}{
-		"model":       s.model,
-		"messages":    messages,
-		"temperature": s.temperature,
-		"stream":      true,
-	}
-
-	// Add tools if present in context
-	if len(llmCtx.Tools) > 0 {
-		tools := []map[string]interface{}{}
-		for _, tool := range llmCtx.Tools {
-			tools = append(tools, map[string]interface{}{
-				"type": tool.Type,
-				"function": map[string]interface{}{
-					"name":        tool.Function.Name,
-					"description": tool.Function.Description,
-					"parameters":  tool.Function.Parameters,
-				},
-			})
-		}
-		requestBody["tools"] = tools
-
-		// Add tool_choice if specified
-		if llmCtx.ToolChoice != nil {
-			requestBody["tool_choice"] = llmCtx.ToolChoice
-		}
-	}
-
-	bodyBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return err
-	}
+func (s *GroqLLMService) generateResponseFromContext(gen *services.Generation, llmCtx *services.LLMContext) error {
+	// "developer" is an OpenAI-specific role; Groq uses the OpenAI wire format
+	// but does not accept it
+	requestBody := openaicompat.BuildRequest(s.model, s.temperature, llmCtx, openaicompat.DeveloperAsUser)
+	s.nextParams.Take().ApplyTo(requestBody)
 
 	// Use cancellable context so interruption can stop the request
 	// Use Groq API endpoint (OpenAI-compatible)
-	req, err := http.NewRequestWithContext(s.requestCtx, "POST", s.baseURL+"/chat/completions", bytes.NewReader(bodyBytes))
+	endpoint := openaicompat.BearerEndpoint("Groq", s.baseURL, s.apiKey)
+	result, err := endpoint.Complete(gen.Context(), requestBody, gen.AddReply, gen.Push)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		// Check if cancelled by interruption
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
-		}
-		return err
+	if len(result.ToolCalls) > 0 {
+		s.log.Debug("Emitted %d tool call(s)", len(result.ToolCalls))
+	} else if result.Text != "" {
+		s.log.Debug("Assistant: %s", result.Text)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Groq API error: %s", string(body))
-	}
-
-	// Stream response
-	var fullResponse strings.Builder
-	scanner := bufio.NewScanner(resp.Body)
-
-	for scanner.Scan() {
-		// Check if interrupted
-		select {
-		case <-s.requestCtx.Done():
-			s.log.Debug("Stream interrupted, stopping generation")
-			return nil
-		default:
-		}
-
-		line := scanner.Text()
-		if !strings.HasPrefix(line, "data: ") {
-			continue
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-		if data == "[DONE]" {
-			break
-		}
-
-		var streamResp struct {
-			Choices []struct {
-				Delta struct {
-					Content   string `json:"content"`
-					ToolCalls []struct {
-						Index    int    `json:"index"`
-						ID       string `json:"id"`
-						Type     string `json:"type"`
-						Function struct {
-							Name      string `json:"name"`
-							Arguments string `json:"arguments"`
-						} `json:"function"`
-					} `json:"tool_calls"`
-				} `json:"delta"`
-			} `json:"choices"`
-		}
-
-		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
-			continue
-		}
-
-		if len(streamResp.Choices) > 0 {
-			content := streamResp.Choices[0].Delta.Content
-			if content != "" {
-				fullResponse.WriteString(content)
-				// Emit raw LLMTextFrame - sentence splitting handled by SentenceAggregator
-				textFrame := frames.NewLLMTextFrame(content)
-				s.PushFrame(textFrame, frames.Downstream)
-			}
-
-			// Handle tool calls (function calling)
-			// Note: Full function call support would require accumulating tool_calls
-			// and emitting FunctionCallInProgressFrame, but that's beyond current scope
-		}
-	}
-
-	// Check if scanner error was due to cancellation
-	if err := scanner.Err(); err != nil {
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
-		}
-		return err
-	}
-
-	// Add assistant response to context
-	response := fullResponse.String()
-	if response != "" {
-		llmCtx.AddAssistantMessage(response)
-		s.log.Debug("Assistant: %s", response)
-	}
-
 	return nil
 }
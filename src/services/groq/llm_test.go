@@ -172,10 +172,7 @@ func TestGroqLLMServiceInterruptionHandling(t *testing.T) {
 	service.Initialize(ctx)
 	defer service.Cleanup()
 
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx, service.requestCancel = context.WithCancel(ctx)
-	service.streamMu.Unlock()
+	service.stream.Begin(ctx, services.NewLLMContext(""))
 
 	interruptFrame := frames.NewInterruptionFrame()
 	err := service.HandleFrame(ctx, interruptFrame, frames.Downstream)
@@ -183,9 +180,7 @@ func TestGroqLLMServiceInterruptionHandling(t *testing.T) {
 		t.Errorf("InterruptionFrame handling failed: %v", err)
 	}
 
-	service.streamMu.Lock()
-	wasGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	wasGenerating := service.stream.Generating()
 
 	if wasGenerating {
 		t.Error("Expected isGenerating to be false after interruption")
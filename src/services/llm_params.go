@@ -0,0 +1,61 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// LLMParams are sampling overrides for one generation. Nil fields keep the
+// service's configured value.
+type LLMParams struct {
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   *int
+}
+
+// ApplyTo sets the overridden temperature, top_p and max_tokens in an
+// OpenAI- or Anthropic-style request body
+func (p LLMParams) ApplyTo(requestBody map[string]interface{}) {
+	if p.Temperature != nil {
+		requestBody["temperature"] = *p.Temperature
+	}
+	if p.TopP != nil {
+		requestBody["top_p"] = *p.TopP
+	}
+	if p.MaxTokens != nil {
+		requestBody["max_tokens"] = *p.MaxTokens
+	}
+}
+
+// NextLLMParams holds the overrides of LLMParamsFrames until the next
+// generation takes them. The zero value is ready to use.
+type NextLLMParams struct {
+	mu     sync.Mutex
+	params LLMParams
+}
+
+// Set merges the frame's overrides into the pending ones
+func (n *NextLLMParams) Set(frame *frames.LLMParamsFrame) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if frame.Temperature != nil {
+		n.params.Temperature = frame.Temperature
+	}
+	if frame.TopP != nil {
+		n.params.TopP = frame.TopP
+	}
+	if frame.MaxTokens != nil {
+		n.params.MaxTokens = frame.MaxTokens
+	}
+}
+
+// Take returns the pending overrides and clears them, so they apply to a
+// single generation
+func (n *NextLLMParams) Take() LLMParams {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	params := n.params
+	n.params = LLMParams{}
+	return params
+}
@@ -0,0 +1,111 @@
+// Package mock provides deterministic STT and TTS services for testing
+// pipelines without real providers. MockTTS encodes text as PCM audio that
+// MockSTT decodes back to the same text, so a scripted conversation can run
+// end to end through a LoopbackTransport.
+package mock
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// DefaultSampleRate is the sample rate of mock audio (16-bit mono PCM)
+	DefaultSampleRate = 16000
+
+	// samplesPerByte is how many samples encode one byte of text
+	samplesPerByte = 80
+
+	// sampleScale spaces byte values apart so silence (0) never decodes
+	sampleScale = 64
+)
+
+// EncodeText returns 16-bit little-endian PCM encoding text. Each byte is a
+// short constant-level run; decoding it with DecodeAudio or MockSTT yields
+// text again. The audio contains no silence.
+func EncodeText(text string) []byte {
+	pcm := make([]byte, 0, len(text)*samplesPerByte*2)
+	for i := 0; i < len(text); i++ {
+		sample := uint16((int(text[i]) + 1) * sampleScale)
+		for j := 0; j < samplesPerByte; j++ {
+			pcm = binary.LittleEndian.AppendUint16(pcm, sample)
+		}
+	}
+	return pcm
+}
+
+// Silence returns d of 16-bit PCM silence at sampleRate
+func Silence(d time.Duration, sampleRate int) []byte {
+	samples := int(d.Seconds() * float64(sampleRate))
+	return make([]byte, samples*2)
+}
+
+// Speech returns text encoded as an utterance: EncodeText followed by enough
+// silence for MockSTT to finalize it at sampleRate
+func Speech(text string, sampleRate int) []byte {
+	return append(EncodeText(text), Silence(2*DefaultEndOfUtterance, sampleRate)...)
+}
+
+// DecodeAudio returns all text encoded in pcm, ignoring silence
+func DecodeAudio(pcm []byte) string {
+	var d decoder
+	d.feed(pcm, 0)
+	return string(d.text)
+}
+
+// decoder turns mock PCM back into text across arbitrarily split frames
+type decoder struct {
+	leftover []byte // Odd byte carried to the next frame
+	value    int    // Byte value of the current run
+	run      int    // Samples seen in the current run
+	silence  int    // Consecutive silent samples
+	text     []byte // Decoded text of the current utterance
+}
+
+// feed decodes pcm and returns utterances ended by at least endSilence
+// silent samples. With endSilence zero, utterances are never ended and all
+// text accumulates.
+func (d *decoder) feed(pcm []byte, endSilence int) []string {
+	if len(d.leftover) > 0 {
+		pcm = append(d.leftover, pcm...)
+		d.leftover = nil
+	}
+	if len(pcm)%2 == 1 {
+		d.leftover = []byte{pcm[len(pcm)-1]}
+		pcm = pcm[:len(pcm)-1]
+	}
+
+	var utterances []string
+	for i := 0; i+1 < len(pcm); i += 2 {
+		sample := int(int16(binary.LittleEndian.Uint16(pcm[i:])))
+		if sample <= 0 {
+			d.run = 0
+			d.silence++
+			if endSilence > 0 && d.silence >= endSilence && len(d.text) > 0 {
+				utterances = append(utterances, string(d.text))
+				d.text = nil
+			}
+			continue
+		}
+
+		d.silence = 0
+		value := sample/sampleScale - 1
+		if value != d.value || d.run == samplesPerByte {
+			d.value = value
+			d.run = 0
+		}
+		d.run++
+		if d.run == samplesPerByte && value >= 0 && value < 256 {
+			d.text = append(d.text, byte(value))
+		}
+	}
+	return utterances
+}
+
+// flush returns the text of an utterance in progress and resets it
+func (d *decoder) flush() string {
+	text := string(d.text)
+	d.text = nil
+	d.run = 0
+	return text
+}
@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// capture records frames pushed to it
+type capture struct {
+	*processors.BaseProcessor
+	frames []frames.Frame
+}
+
+func newCapture() *capture {
+	c := &capture{}
+	c.BaseProcessor = processors.NewBaseProcessor("Capture", nil)
+	return c
+}
+
+func (c *capture) QueueFrame(frame frames.Frame, direction frames.FrameDirection) error {
+	c.frames = append(c.frames, frame)
+	return nil
+}
+
+func TestMockTTSToMockSTT(t *testing.T) {
+	ctx := context.Background()
+	tts := NewMockTTS(TTSConfig{})
+	spoken := newCapture()
+	tts.Link(spoken)
+
+	for _, frame := range []frames.Frame{
+		frames.NewLLMFullResponseStartFrame(),
+		frames.NewLLMTextFrame("Hi "),
+		frames.NewLLMTextFrame("there"),
+		frames.NewLLMFullResponseEndFrame(),
+	} {
+		if err := tts.HandleFrame(ctx, frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame failed: %v", err)
+		}
+	}
+
+	stt := NewMockSTT(STTConfig{})
+	heard := newCapture()
+	stt.Link(heard)
+	var started, stopped int
+	for _, frame := range spoken.frames {
+		switch f := frame.(type) {
+		case *frames.TTSStartedFrame:
+			started++
+		case *frames.TTSStoppedFrame:
+			stopped++
+		case *frames.TTSAudioFrame:
+			stt.HandleFrame(ctx, frames.NewAudioFrame(f.Data, f.SampleRate, f.Channels), frames.Downstream)
+		}
+	}
+	if started != 1 || stopped != 1 {
+		t.Fatalf("Expected one TTSStartedFrame and one TTSStoppedFrame, got %d and %d", started, stopped)
+	}
+
+	// No trailing silence: the user stopping finalizes the utterance
+	stt.HandleFrame(ctx, frames.NewUserStoppedSpeakingFrame(), frames.Downstream)
+	var transcripts []string
+	for _, frame := range heard.frames {
+		if tf, ok := frame.(*frames.TranscriptionFrame); ok && tf.IsFinal {
+			transcripts = append(transcripts, tf.Text)
+		}
+	}
+	if len(transcripts) != 1 || transcripts[0] != "Hi there" {
+		t.Fatalf("Expected transcript %q, got %q", "Hi there", transcripts)
+	}
+}
+
+func TestDecoderAcrossOddFrameSplits(t *testing.T) {
+	pcm := Speech("split me", DefaultSampleRate)
+	endSilence := int(DefaultEndOfUtterance.Seconds() * DefaultSampleRate)
+
+	var d decoder
+	var got []string
+	for start := 0; start < len(pcm); start += 333 {
+		got = append(got, d.feed(pcm[start:min(start+333, len(pcm))], endSilence)...)
+	}
+	if len(got) != 1 || got[0] != "split me" {
+		t.Fatalf("Expected one utterance %q, got %q", "split me", got)
+	}
+}
+
+func TestDecoderSeparatesUtterances(t *testing.T) {
+	endSilence := int(DefaultEndOfUtterance.Seconds() * DefaultSampleRate)
+	pcm := append(Speech("one", DefaultSampleRate), Speech("two", DefaultSampleRate)...)
+
+	var d decoder
+	got := d.feed(pcm, endSilence)
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Fatalf("Expected utterances [one two], got %q", got)
+	}
+
+	// Without enough silence the utterance stays open until flushed
+	got = d.feed(append(EncodeText("three"), Silence(10*time.Millisecond, DefaultSampleRate)...), endSilence)
+	if len(got) != 0 {
+		t.Fatalf("Expected no utterance before the silence, got %q", got)
+	}
+	if text := d.flush(); text != "three" {
+		t.Fatalf("Expected flush to return %q, got %q", "three", text)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	text := "Hello, wooorld!"
+	if got := DecodeAudio(EncodeText(text)); got != text {
+		t.Fatalf("Expected %q, got %q", text, got)
+	}
+}
@@ -0,0 +1,21 @@
+package mock
+
+import (
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("mock", func(config services.ServiceConfig) (services.STTService, error) {
+		stt := NewMockSTT(STTConfig{
+			SampleRate:     config.Int(services.ConfigSampleRate),
+			EndOfUtterance: config.Duration("end_of_utterance"),
+		})
+		stt.SetLanguage(config.String(services.ConfigLanguage))
+		return stt, nil
+	})
+	services.RegisterTTS("mock", func(config services.ServiceConfig) (services.TTSService, error) {
+		return NewMockTTS(TTSConfig{
+			SampleRate: config.Int(services.ConfigSampleRate),
+		}), nil
+	})
+}
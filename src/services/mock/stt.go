@@ -0,0 +1,94 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// DefaultEndOfUtterance is the silence after which MockSTT finalizes an
+// utterance when STTConfig.EndOfUtterance is unset
+const DefaultEndOfUtterance = 100 * time.Millisecond
+
+// STTConfig holds configuration for MockSTT
+type STTConfig struct {
+	// SampleRate of the expected input audio (default: DefaultSampleRate)
+	SampleRate int
+
+	// EndOfUtterance is the silence that finalizes an utterance
+	// (default: DefaultEndOfUtterance)
+	EndOfUtterance time.Duration
+}
+
+// MockSTT transcribes audio produced by EncodeText or MockTTS. An utterance
+// becomes a final TranscriptionFrame after EndOfUtterance of silence, or
+// when the user stops speaking. Audio frames pass through unchanged.
+type MockSTT struct {
+	*processors.BaseProcessor
+	endSilence int
+	language   string
+	model      string
+	decoder    decoder
+	log        *logger.Logger
+}
+
+// NewMockSTT creates a mock STT service
+func NewMockSTT(config STTConfig) *MockSTT {
+	if config.SampleRate <= 0 {
+		config.SampleRate = DefaultSampleRate
+	}
+	if config.EndOfUtterance <= 0 {
+		config.EndOfUtterance = DefaultEndOfUtterance
+	}
+
+	s := &MockSTT{
+		endSilence: int(config.EndOfUtterance.Seconds() * float64(config.SampleRate)),
+		log:        logger.WithPrefix("MockSTT"),
+	}
+	s.BaseProcessor = processors.NewBaseProcessor("MockSTT", s)
+	return s
+}
+
+func (s *MockSTT) SetLanguage(lang string) {
+	s.language = lang
+}
+
+func (s *MockSTT) SetModel(model string) {
+	s.model = model
+}
+
+func (s *MockSTT) Initialize(ctx context.Context) error {
+	return nil
+}
+
+func (s *MockSTT) Cleanup() error {
+	return nil
+}
+
+func (s *MockSTT) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.AudioFrame:
+		for _, text := range s.decoder.feed(f.Data, s.endSilence) {
+			if err := s.pushTranscription(text); err != nil {
+				return err
+			}
+		}
+	case *frames.UserStoppedSpeakingFrame:
+		if text := s.decoder.flush(); text != "" {
+			if err := s.pushTranscription(text); err != nil {
+				return err
+			}
+		}
+	}
+	return s.PushFrame(frame, direction)
+}
+
+func (s *MockSTT) pushTranscription(text string) error {
+	s.log.Debug("Transcribed %q", text)
+	transcription := frames.NewTranscriptionFrame(text, true)
+	transcription.Language = s.language
+	return s.PushFrame(transcription, frames.Downstream)
+}
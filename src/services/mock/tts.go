@@ -0,0 +1,114 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// TTSConfig holds configuration for MockTTS
+type TTSConfig struct {
+	// SampleRate of the produced audio (default: DefaultSampleRate)
+	SampleRate int
+}
+
+// MockTTS synthesizes text as EncodeText audio, so DecodeAudio (or MockSTT)
+// on the output recovers exactly what was spoken. Each text frame becomes one
+// TTSAudioFrame, bracketed per response by TTSStartedFrame and
+// TTSStoppedFrame. Text frames pass through after their audio.
+type MockTTS struct {
+	*processors.BaseProcessor
+	sampleRate int
+	voice      string
+	model      string
+	speaking   bool
+	log        *logger.Logger
+}
+
+// NewMockTTS creates a mock TTS service
+func NewMockTTS(config TTSConfig) *MockTTS {
+	if config.SampleRate <= 0 {
+		config.SampleRate = DefaultSampleRate
+	}
+
+	s := &MockTTS{
+		sampleRate: config.SampleRate,
+		log:        logger.WithPrefix("MockTTS"),
+	}
+	s.BaseProcessor = processors.NewBaseProcessor("MockTTS", s)
+	return s
+}
+
+func (s *MockTTS) SetVoice(voiceID string) {
+	s.voice = voiceID
+}
+
+func (s *MockTTS) SetModel(model string) {
+	s.model = model
+}
+
+// OutputFormat implements processors.AudioFormatProducer
+func (s *MockTTS) OutputFormat() processors.AudioFormat {
+	return processors.AudioFormat{SampleRate: s.sampleRate, Codec: "linear16"}
+}
+
+func (s *MockTTS) Initialize(ctx context.Context) error {
+	return nil
+}
+
+func (s *MockTTS) Cleanup() error {
+	return nil
+}
+
+func (s *MockTTS) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.TextFrame:
+		if !f.SkipTTS {
+			if err := s.synthesize(f.Text); err != nil {
+				return err
+			}
+		}
+	case *frames.LLMTextFrame:
+		if !f.SkipTTS {
+			if err := s.synthesize(f.Text); err != nil {
+				return err
+			}
+		}
+	case *frames.LLMFullResponseEndFrame:
+		if err := s.stopSpeaking(); err != nil {
+			return err
+		}
+	case *frames.InterruptionFrame:
+		s.speaking = false
+	}
+	return s.PushFrame(frame, direction)
+}
+
+func (s *MockTTS) synthesize(text string) error {
+	if text == "" {
+		return nil
+	}
+	if !s.speaking {
+		s.speaking = true
+		// Upstream for the user aggregator, downstream for the output transport
+		s.PushFrame(frames.NewTTSStartedFrame(), frames.Upstream)
+		if err := s.PushFrame(frames.NewTTSStartedFrame(), frames.Downstream); err != nil {
+			return err
+		}
+	}
+
+	s.log.Debug("Synthesizing %q", text)
+	audio := frames.NewTTSAudioFrame(EncodeText(text), s.sampleRate, 1)
+	audio.SetMetadata("codec", "linear16")
+	return s.PushFrame(audio, frames.Downstream)
+}
+
+func (s *MockTTS) stopSpeaking() error {
+	if !s.speaking {
+		return nil
+	}
+	s.speaking = false
+	return s.PushFrame(frames.NewTTSStoppedFrame(), frames.Downstream)
+}
@@ -177,10 +177,7 @@ func TestOllamaLLMServiceInterruptionHandling(t *testing.T) {
 	service.Initialize(ctx)
 	defer service.Cleanup()
 
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx, service.requestCancel = context.WithCancel(ctx)
-	service.streamMu.Unlock()
+	service.stream.Begin(ctx, services.NewLLMContext(""))
 
 	interruptFrame := frames.NewInterruptionFrame()
 	err := service.HandleFrame(ctx, interruptFrame, frames.Downstream)
@@ -188,9 +185,7 @@ func TestOllamaLLMServiceInterruptionHandling(t *testing.T) {
 		t.Errorf("InterruptionFrame handling failed: %v", err)
 	}
 
-	service.streamMu.Lock()
-	wasGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	wasGenerating := service.stream.Generating()
 
 	if wasGenerating {
 		t.Error("Expected isGenerating to be false after interruption")
@@ -205,12 +200,7 @@ func TestOllamaLLMServiceInterruptionCancelsRequest(t *testing.T) {
 	defer service.Cleanup()
 
 	// Set up a request context that we can check cancellation on
-	reqCtx, reqCancel := context.WithCancel(ctx)
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx = reqCtx
-	service.requestCancel = reqCancel
-	service.streamMu.Unlock()
+	reqCtx := service.stream.Begin(ctx, services.NewLLMContext("")).Context()
 
 	// Send interruption
 	interruptFrame := frames.NewInterruptionFrame()
@@ -233,13 +223,9 @@ func TestOllamaLLMServiceInterruptionIgnoredForNewContext(t *testing.T) {
 	defer service.Cleanup()
 
 	// Set up as if we just received a new context
-	reqCtx, reqCancel := context.WithCancel(ctx)
-	service.streamMu.Lock()
-	service.isGenerating = true
-	service.requestCtx = reqCtx
-	service.requestCancel = reqCancel
-	service.lastContextAt = time.Now() // Just received context
-	service.streamMu.Unlock()
+	service.stream.ContextReceived() // Just received context
+	gen := service.stream.Begin(ctx, services.NewLLMContext(""))
+	reqCtx := gen.Context()
 
 	// Send interruption - should be ignored since context was just received
 	interruptFrame := frames.NewInterruptionFrame()
@@ -249,9 +235,7 @@ func TestOllamaLLMServiceInterruptionIgnoredForNewContext(t *testing.T) {
 	}
 
 	// Verify the request context was NOT cancelled (interruption was for old response)
-	service.streamMu.Lock()
-	stillGenerating := service.isGenerating
-	service.streamMu.Unlock()
+	stillGenerating := service.stream.Generating()
 
 	if !stillGenerating {
 		t.Error("Expected isGenerating to still be true - interruption should be ignored for new context")
@@ -262,7 +246,7 @@ func TestOllamaLLMServiceInterruptionIgnoredForNewContext(t *testing.T) {
 	}
 
 	// Clean up
-	reqCancel()
+	gen.End()
 }
 
 func TestOllamaLLMServicePassthroughFrames(t *testing.T) {
@@ -350,7 +334,7 @@ func TestOllamaLLMServiceSSEParsing(t *testing.T) {
 	llmContext := services.NewLLMContext("You are a test assistant")
 	llmContext.AddUserMessage("Say hello")
 
-	err := service.generateResponseFromContext(llmContext)
+	err := service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	if err != nil {
 		t.Fatalf("generateResponseFromContext failed: %v", err)
 	}
@@ -393,7 +377,7 @@ func TestOllamaLLMServiceSSEWithToolCalls(t *testing.T) {
 	llmContext := services.NewLLMContext("You are a test assistant")
 	llmContext.AddUserMessage("What's the weather?")
 
-	err := service.generateResponseFromContext(llmContext)
+	err := service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	if err != nil {
 		t.Fatalf("generateResponseFromContext failed: %v", err)
 	}
@@ -422,7 +406,7 @@ func TestOllamaLLMServiceHTTPError(t *testing.T) {
 	llmContext := services.NewLLMContext("")
 	llmContext.AddUserMessage("Hello")
 
-	err := service.generateResponseFromContext(llmContext)
+	err := service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	if err == nil {
 		t.Fatal("Expected error for HTTP 500 response")
 	}
@@ -470,7 +454,7 @@ func TestOllamaLLMServiceStreamInterruption(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		genErr = service.generateResponseFromContext(llmContext)
+		genErr = service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	}()
 
 	// Wait for request to be received
@@ -521,7 +505,7 @@ func TestOllamaLLMServiceNoAuthHeader(t *testing.T) {
 	llmContext := services.NewLLMContext("")
 	llmContext.AddUserMessage("test")
 
-	err := service.generateResponseFromContext(llmContext)
+	err := service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	if err != nil {
 		t.Fatalf("generateResponseFromContext failed: %v", err)
 	}
@@ -578,7 +562,7 @@ func TestOllamaLLMServiceMalformedSSE(t *testing.T) {
 	llmContext := services.NewLLMContext("")
 	llmContext.AddUserMessage("test")
 
-	err := service.generateResponseFromContext(llmContext)
+	err := service.generateResponseFromContext(service.stream.Begin(ctx, llmContext), llmContext)
 	if err != nil {
 		t.Fatalf("Expected graceful handling of malformed SSE, got error: %v", err)
 	}
@@ -611,10 +595,7 @@ func TestOllamaLLMServiceRaceDetection(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			service.streamMu.Lock()
-			service.isGenerating = true
-			service.requestCtx, service.requestCancel = context.WithCancel(ctx)
-			service.streamMu.Unlock()
+			service.stream.Begin(ctx, services.NewLLMContext(""))
 
 			interruptFrame := frames.NewInterruptionFrame()
 			service.HandleFrame(ctx, interruptFrame, frames.Downstream)
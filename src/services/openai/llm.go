@@ -1,27 +1,30 @@
 package openai
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
 	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/services/openaicompat"
 )
 
+// DefaultBaseURL is the default OpenAI API endpoint
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// DefaultAzureAPIVersion is the Azure OpenAI api-version used when
+// LLMConfig.APIVersion is unset
+const DefaultAzureAPIVersion = "2024-10-21"
+
 // LLMService provides language model capabilities using OpenAI
 type LLMService struct {
 	*processors.BaseProcessor
 	apiKey      string
+	baseURL     string
 	model       string
 	temperature float64
 	context     *services.LLMContext
@@ -29,12 +32,20 @@ type LLMService struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 
-	// Request-scoped context for cancellable streaming (protected by streamMu)
-	requestCtx    context.Context
-	requestCancel context.CancelFunc
-	isGenerating  bool
-	lastContextAt time.Time  // When we last received a new context (for interruption filtering)
-	streamMu      sync.Mutex // Protects requestCancel, isGenerating, and lastContextAt
+	// Azure OpenAI deployment URL, authenticated with the api-key header.
+	// Empty for standard OpenAI.
+	azureURL string
+
+	// The response being streamed, stopped by interruptions
+	stream *services.StreamGuard
+
+	// systemPrompt, once set by an LLMMessagesUpdateFrame or SetSystemPrompt,
+	// overrides the prompt of every request. The context itself is shared
+	// with the aggregators and is never rewritten.
+	systemPrompt string
+
+	// Sampling overrides from an LLMParamsFrame for the next generation only
+	nextParams services.NextLLMParams
 }
 
 // LLMConfig holds configuration for OpenAI
@@ -43,18 +54,42 @@ type LLMConfig struct {
 	Model        string // e.g., "gpt-4-turbo", "gpt-3.5-turbo"
 	SystemPrompt string
 	Temperature  float64
+	BaseURL      string // Optional: override default API URL
+
+	// Azure OpenAI: setting AzureEndpoint (e.g.
+	// "https://myresource.openai.azure.com") sends requests to Deployment
+	// with the api-key header instead of a Bearer token. BaseURL is ignored.
+	AzureEndpoint string
+	Deployment    string
+	APIVersion    string // Optional: defaults to DefaultAzureAPIVersion
 }
 
 // NewLLMService creates a new OpenAI LLM service
 func NewLLMService(config LLMConfig) *LLMService {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	os := &LLMService{
 		apiKey:      config.APIKey,
+		baseURL:     baseURL,
 		model:       config.Model,
 		temperature: config.Temperature,
 		context:     services.NewLLMContext(config.SystemPrompt),
 		log:         logger.WithPrefix("OpenAILLM"),
 	}
+	if config.AzureEndpoint != "" {
+		apiVersion := config.APIVersion
+		if apiVersion == "" {
+			apiVersion = DefaultAzureAPIVersion
+		}
+		os.azureURL = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			strings.TrimRight(config.AzureEndpoint, "/"), url.PathEscape(config.Deployment), url.QueryEscape(apiVersion))
+	}
 	os.BaseProcessor = processors.NewBaseProcessor("OpenAI", os)
+	os.AttachLogger(os.log)
+	os.stream = services.NewStreamGuard(os.BaseProcessor, os.log)
 	return os
 }
 
@@ -62,8 +97,10 @@ func (s *LLMService) SetModel(model string) {
 	s.model = model
 }
 
+// SetSystemPrompt replaces the system prompt sent with every request. An
+// empty prompt clears the override and falls back to the context's own.
 func (s *LLMService) SetSystemPrompt(prompt string) {
-	s.context.SystemPrompt = prompt
+	s.systemPrompt = prompt
 }
 
 func (s *LLMService) SetTemperature(temp float64) {
@@ -95,29 +132,23 @@ func (s *LLMService) Cleanup() error {
 }
 
 func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// Handle InterruptionFrame - CRITICAL: Stop streaming immediately
-	// BUT: If we just received a new context (within 100ms), this interruption is for
-	// the OLD response, not the new one. Don't cancel in that case.
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
-		s.streamMu.Lock()
-		timeSinceContext := time.Since(s.lastContextAt)
-		isNewContext := timeSinceContext < 100*time.Millisecond
-
-		s.log.Warn("Interruption received (isGenerating=%v, timeSinceContext=%v)", s.isGenerating, timeSinceContext)
-
-		if isNewContext {
-			// This interruption is for the OLD response, not the new one we just started
-			s.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
-			s.streamMu.Unlock()
-			return s.PushFrame(frame, direction)
-		}
+	// Handle LLMParamsFrame - override sampling for the next generation
+	if params, ok := frame.(*frames.LLMParamsFrame); ok {
+		s.nextParams.Set(params)
+		return nil
+	}
 
-		if s.isGenerating && s.requestCancel != nil {
-			s.log.Warn("Cancelling ongoing stream")
-			s.requestCancel()
-			s.isGenerating = false
+	// Handle LLMCancelGenerationFrame and InterruptionFrame - stop streaming
+	if handled, err := s.stream.HandleFrame(frame, direction); handled {
+		return err
+	}
+
+	// Handle LLMMessagesUpdateFrame - swap the system prompt for later turns
+	if updateFrame, ok := frame.(*frames.LLMMessagesUpdateFrame); ok {
+		if updateFrame.SystemPrompt != "" {
+			s.log.Info("Updating system prompt")
+			s.systemPrompt = updateFrame.SystemPrompt
 		}
-		s.streamMu.Unlock()
 		return s.PushFrame(frame, direction)
 	}
 
@@ -128,9 +159,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.log.Debug("Received LLMContextFrame with %d messages", len(llmContext.Messages))
 
 			// Record when we received this context (for interruption filtering)
-			s.streamMu.Lock()
-			s.lastContextAt = time.Now()
-			s.streamMu.Unlock()
+			s.stream.ContextReceived()
 
 			// Update our context reference
 			s.context = llmContext
@@ -139,9 +168,10 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			s.PushFrame(frames.NewLLMFullResponseStartFrame(), frames.Downstream)
 
 			// Generate response using the provided context
-			if err := s.generateResponseFromContext(llmContext); err != nil {
+			gen := s.stream.Begin(s.ctx, llmContext)
+			if err := gen.Run(func() error { return s.generateResponseFromContext(gen, llmContext) }); err != nil {
 				// Only log error if not cancelled
-				if s.requestCtx != nil && s.requestCtx.Err() == context.Canceled {
+				if gen.Cancelled() {
 					s.log.Debug("Stream cancelled by interruption")
 				} else {
 					s.log.Error("Error generating response: %v", err)
@@ -150,7 +180,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 			}
 
 			// Send LLM response end marker
-			s.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+			gen.PushResponseEnd()
 		}
 		return nil
 	}
@@ -161,294 +191,35 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 
 // generateResponseFromContext generates a response using the provided context
 // Supports full message format including tool calls
-func (s *LLMService) generateResponseFromContext(llmCtx *services.LLMContext) error {
-	// Create cancellable context for this request
-	// Use background context if s.ctx is nil (Initialize not called yet)
-	parentCtx := s.ctx
-	if parentCtx == nil {
-		parentCtx = context.Background()
-	}
-
-	// Lock to safely set stream state (read by InterruptionFrame handler)
-	s.streamMu.Lock()
-	s.requestCtx, s.requestCancel = context.WithCancel(parentCtx)
-	s.isGenerating = true
-	s.streamMu.Unlock()
-
-	defer func() {
-		s.streamMu.Lock()
-		s.isGenerating = false
-		if s.requestCancel != nil {
-			s.requestCancel()
-		}
-		s.requestCancel = nil
-		s.streamMu.Unlock()
-	}()
-
-	// Build messages array from context
-	messages := []map[string]interface{}{}
-
-	// Add system prompt if present
-	if llmCtx.SystemPrompt != "" {
-		messages = append(messages, map[string]interface{}{
-			"role":    "system",
-			"content": llmCtx.SystemPrompt,
-		})
-	}
-
-	// Add all messages from context
-	for _, msg := range llmCtx.Messages {
-		message := map[string]interface{}{
-			"role": msg.Role,
-		}
-
-		// Add content if present
-		if msg.Content != "" {
-			message["content"] = msg.Content
-		}
-
-		// Add tool calls if present (assistant messages)
-		if len(msg.ToolCalls) > 0 {
-			toolCalls := []map[string]interface{}{}
-			for _, tc := range msg.ToolCalls {
-				toolCalls = append(toolCalls, map[string]interface{}{
-					"id":   tc.ID,
-					"type": tc.Type,
-					"function": map[string]interface{}{
-						"name":      tc.Function.Name,
-						"arguments": tc.Function.Arguments,
-					},
-				})
-			}
-			message["tool_calls"] = toolCalls
-		}
-
-		// Add tool_call_id if present (tool messages)
-		if msg.ToolCallID != "" {
-			message["tool_call_id"] = msg.ToolCallID
-		}
-
-		messages = append(messages, message)
-	}
-
-	// Prepare request
-	requestBody := map[string]interface{}{
-		"model":       s.model,
-		"messages":    messages,
-		"temperature": s.temperature,
-		"stream":      true,
-	}
-
-	// Add tools if present in context
-	if len(llmCtx.Tools) > 0 {
-		tools := []map[string]interface{}{}
-		for _, tool := range llmCtx.Tools {
-			tools = append(tools, map[string]interface{}{
-				"type": tool.Type,
-				"function": map[string]interface{}{
-					"name":        tool.Function.Name,
-					"description": tool.Function.Description,
-					"parameters":  tool.Function.Parameters,
-				},
-			})
-		}
-		requestBody["tools"] = tools
-
-		// Add tool_choice if specified
-		if llmCtx.ToolChoice != nil {
-			requestBody["tool_choice"] = llmCtx.ToolChoice
-		}
-	}
-
-	bodyBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return err
+func (s *LLMService) generateResponseFromContext(gen *services.Generation, llmCtx *services.LLMContext) error {
+	if s.systemPrompt != "" {
+		// Swap the prompt on a copy so the shared context keeps its own
+		override := *llmCtx
+		override.SystemPrompt = s.systemPrompt
+		llmCtx = &override
 	}
+	requestBody := openaicompat.BuildRequest(s.model, s.temperature, llmCtx, nil)
+	s.nextParams.Take().ApplyTo(requestBody)
 
 	// Use cancellable context so interruption can stop the request
-	req, err := http.NewRequestWithContext(s.requestCtx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(bodyBytes))
+	result, err := s.endpoint().Complete(gen.Context(), requestBody, gen.AddReply, gen.Push)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		// Check if cancelled by interruption
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
-		}
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("OpenAI API error: %s", string(body))
-	}
-
-	// Stream response
-	var fullResponse strings.Builder
-
-	// partialToolCall accumulates streamed fragments of a single function call.
-	type partialToolCall struct {
-		id        string
-		callType  string
-		name      string
-		arguments strings.Builder
-	}
-	partialCalls := map[int]*partialToolCall{}
-	maxIdx := -1
-
-	scanner := bufio.NewScanner(resp.Body)
-
-	for scanner.Scan() {
-		// Check if interrupted
-		select {
-		case <-s.requestCtx.Done():
-			s.log.Debug("Stream interrupted, stopping generation")
-			return nil
-		default:
-		}
-
-		line := scanner.Text()
-		if !strings.HasPrefix(line, "data: ") {
-			continue
-		}
-
-		data := strings.TrimPrefix(line, "data: ")
-		if data == "[DONE]" {
-			break
-		}
-
-		var streamResp struct {
-			Choices []struct {
-				Delta struct {
-					Content   string `json:"content"`
-					ToolCalls []struct {
-						Index    int    `json:"index"`
-						ID       string `json:"id"`
-						Type     string `json:"type"`
-						Function struct {
-							Name      string `json:"name"`
-							Arguments string `json:"arguments"`
-						} `json:"function"`
-					} `json:"tool_calls"`
-				} `json:"delta"`
-			} `json:"choices"`
-		}
-
-		if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
-			continue
-		}
-
-		if len(streamResp.Choices) == 0 {
-			continue
-		}
-
-		delta := streamResp.Choices[0].Delta
-
-		if delta.Content != "" {
-			fullResponse.WriteString(delta.Content)
-			// Emit raw LLMTextFrame — sentence splitting handled by SentenceAggregator
-			s.PushFrame(frames.NewLLMTextFrame(delta.Content), frames.Downstream)
-		}
-
-		// Accumulate streaming tool-call deltas (function.arguments arrive in fragments).
-		for _, tcDelta := range delta.ToolCalls {
-			idx := tcDelta.Index
-			if _, ok := partialCalls[idx]; !ok {
-				partialCalls[idx] = &partialToolCall{}
-			}
-			if idx > maxIdx {
-				maxIdx = idx
-			}
-			pt := partialCalls[idx]
-			if tcDelta.ID != "" {
-				pt.id = tcDelta.ID
-			}
-			if tcDelta.Type != "" {
-				pt.callType = tcDelta.Type
-			}
-			if tcDelta.Function.Name != "" {
-				pt.name = tcDelta.Function.Name
-			}
-			pt.arguments.WriteString(tcDelta.Function.Arguments)
-		}
-	}
-
-	// Check if scanner error was due to cancellation
-	if err := scanner.Err(); err != nil {
-		if s.requestCtx.Err() == context.Canceled {
-			return nil // Not an error, just interrupted
-		}
-		return err
-	}
-
-	// Emit accumulated tool calls as frames and record in context.
-	if len(partialCalls) > 0 {
-		callInfos := make([]frames.FunctionCallInfo, 0, len(partialCalls))
-		completedCalls := make([]services.ToolCall, 0, len(partialCalls))
-
-		for i := 0; i <= maxIdx; i++ {
-			pt, ok := partialCalls[i]
-			if !ok {
-				continue
-			}
-			callType := pt.callType
-			if callType == "" {
-				callType = "function"
-			}
-			argStr := pt.arguments.String()
-			callInfos = append(callInfos, frames.FunctionCallInfo{
-				ToolCallID:   pt.id,
-				FunctionName: pt.name,
-			})
-			completedCalls = append(completedCalls, services.ToolCall{
-				ID:   pt.id,
-				Type: callType,
-				Function: services.FunctionCall{
-					Name:      pt.name,
-					Arguments: argStr,
-				},
-			})
-		}
-
-		s.PushFrame(frames.NewFunctionCallsStartedFrame(callInfos), frames.Downstream)
-
-		// Emit FunctionCallInProgressFrame in index order (map iteration is unordered).
-		for i := 0; i <= maxIdx; i++ {
-			pt, ok := partialCalls[i]
-			if !ok {
-				continue
-			}
-			var args map[string]interface{}
-			argStr := pt.arguments.String()
-			if argStr != "" {
-				if err := json.Unmarshal([]byte(argStr), &args); err != nil {
-					args = map[string]interface{}{}
-				}
-			} else {
-				args = map[string]interface{}{}
-			}
-			s.PushFrame(frames.NewFunctionCallInProgressFrame(pt.id, pt.name, args, true), frames.Downstream)
-			s.log.Debug("Tool call: %s(%s)", pt.name, argStr)
-		}
-
-		llmCtx.AddMessageWithToolCalls(completedCalls)
-		s.log.Debug("Emitted %d tool call(s)", len(completedCalls))
-		return nil
+	if len(result.ToolCalls) > 0 {
+		s.log.Debug("Emitted %d tool call(s)", len(result.ToolCalls))
+	} else if result.Text != "" {
+		s.log.Debug("Assistant: %s", result.Text)
 	}
+	return nil
+}
 
-	// Add text assistant response to context
-	response := fullResponse.String()
-	if response != "" {
-		llmCtx.AddAssistantMessage(response)
-		s.log.Debug("Assistant: %s", response)
+// endpoint returns the chat completions endpoint: the Azure deployment with
+// the api-key header, or baseURL with a Bearer token
+func (s *LLMService) endpoint() openaicompat.Endpoint {
+	if s.azureURL != "" {
+		return openaicompat.Endpoint{Name: "OpenAI", URL: s.azureURL, AuthHeader: "api-key", AuthValue: s.apiKey}
 	}
-
-	return nil
+	return openaicompat.BearerEndpoint("OpenAI", s.baseURL, s.apiKey)
 }
@@ -0,0 +1,431 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// startChatServer returns a mock chat completions server that records the
+// system message of every request
+func startChatServer(t *testing.T, systemPrompts *[]string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("Expected /chat/completions, got %s", r.URL.Path)
+		}
+
+		var body struct {
+			Messages []map[string]interface{} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		system := ""
+		if len(body.Messages) > 0 && body.Messages[0]["role"] == "system" {
+			system, _ = body.Messages[0]["content"].(string)
+		}
+		mu.Lock()
+		*systemPrompts = append(*systemPrompts, system)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestLLMServiceSystemPromptUpdate(t *testing.T) {
+	var mu sync.Mutex
+	var systemPrompts []string
+	server := startChatServer(t, &systemPrompts, &mu)
+	defer server.Close()
+
+	service := NewLLMService(LLMConfig{
+		APIKey:  "test-key",
+		Model:   "gpt-4o-mini",
+		BaseURL: server.URL,
+	})
+	ctx := context.Background()
+
+	// The aggregator owns the context and keeps sending it with its own prompt
+	llmCtx := services.NewLLMContext("You are a sales agent")
+	llmCtx.AddUserMessage("hi")
+	if err := service.HandleFrame(ctx, frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	if err := service.HandleFrame(ctx, frames.NewLLMSystemPromptUpdateFrame("You are a support agent"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMMessagesUpdateFrame) failed: %v", err)
+	}
+
+	next := services.NewLLMContext("You are a sales agent")
+	next.AddUserMessage("I need help")
+	if err := service.HandleFrame(ctx, frames.NewLLMContextFrame(next), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(systemPrompts) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(systemPrompts))
+	}
+	if systemPrompts[0] != "You are a sales agent" {
+		t.Errorf("Expected original prompt on first request, got %q", systemPrompts[0])
+	}
+	if systemPrompts[1] != "You are a support agent" {
+		t.Errorf("Expected updated prompt on next request, got %q", systemPrompts[1])
+	}
+	if next.SystemPrompt != "You are a sales agent" {
+		t.Errorf("Expected the shared context to keep its prompt, got %q", next.SystemPrompt)
+	}
+}
+
+func TestLLMServiceMessagesUpdateWithoutPromptKeepsPrompt(t *testing.T) {
+	service := NewLLMService(LLMConfig{
+		APIKey:       "test-key",
+		SystemPrompt: "original",
+	})
+
+	update := frames.NewLLMMessagesUpdateFrame([]services.LLMMessage{{Role: "user", Content: "hi"}}, false)
+	if err := service.HandleFrame(context.Background(), update, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMMessagesUpdateFrame) failed: %v", err)
+	}
+
+	if service.context.SystemPrompt != "original" || service.systemPrompt != "" {
+		t.Errorf("Expected prompt to be unchanged, got %q (override %q)", service.context.SystemPrompt, service.systemPrompt)
+	}
+}
+
+// frameCapturer records the frames pushed to it
+type frameCapturer struct {
+	mu     sync.Mutex
+	frames []frames.Frame
+}
+
+func (c *frameCapturer) QueueFrame(frame frames.Frame, _ frames.FrameDirection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frames = append(c.frames, frame)
+	return nil
+}
+func (c *frameCapturer) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+func (c *frameCapturer) PushFrame(frames.Frame, frames.FrameDirection) error { return nil }
+func (c *frameCapturer) Link(processors.FrameProcessor)                      {}
+func (c *frameCapturer) SetPrev(processors.FrameProcessor)                   {}
+func (c *frameCapturer) Start(context.Context) error                         { return nil }
+func (c *frameCapturer) Stop() error                                         { return nil }
+func (c *frameCapturer) Name() string                                        { return "TestCapturer" }
+
+func TestLLMServiceStreamsToolCallArgumentDeltas(t *testing.T) {
+	chunks := []string{
+		`{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}`,
+		`{"index":0,"function":{"arguments":"{\"city\":"}}`,
+		`{"index":0,"function":{"arguments":"\"San"}}`,
+		`{"index":0,"function":{"arguments":" Francisco\"}"}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[%s]}}]}\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	service := NewLLMService(LLMConfig{APIKey: "test-key", BaseURL: server.URL})
+	capturer := &frameCapturer{}
+	service.Link(capturer)
+
+	llmCtx := services.NewLLMContext("You are helpful")
+	llmCtx.AddUserMessage("Weather in San Francisco?")
+	if err := service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	capturer.mu.Lock()
+	defer capturer.mu.Unlock()
+	var deltas []*frames.FunctionCallArgsDeltaFrame
+	var calls []*frames.FunctionCallInProgressFrame
+	for _, f := range capturer.frames {
+		switch f := f.(type) {
+		case *frames.FunctionCallArgsDeltaFrame:
+			if len(calls) > 0 {
+				t.Error("expected every delta before the call runs")
+			}
+			deltas = append(deltas, f)
+		case *frames.FunctionCallInProgressFrame:
+			calls = append(calls, f)
+		}
+	}
+
+	want := []struct{ delta, args string }{
+		{`{"city":`, `{"city":`},
+		{`"San`, `{"city":"San`},
+		{` Francisco"}`, `{"city":"San Francisco"}`},
+	}
+	if len(deltas) != len(want) {
+		t.Fatalf("expected %d delta frames, got %d", len(want), len(deltas))
+	}
+	for i, w := range want {
+		d := deltas[i]
+		if d.ToolCallID != "call_1" || d.FunctionName != "get_weather" || d.Delta != w.delta || d.Arguments != w.args {
+			t.Errorf("delta %d = %+v, want delta %q args %q", i, d, w.delta, w.args)
+		}
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("expected one complete invocation, got %d", len(calls))
+	}
+	if city, _ := calls[0].Arguments["city"].(string); city != "San Francisco" {
+		t.Errorf("expected city San Francisco, got %v", calls[0].Arguments)
+	}
+}
+
+func TestLLMServiceAzureDeployment(t *testing.T) {
+	var mu sync.Mutex
+	var path, apiVersion, apiKey, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		path = r.URL.Path
+		apiVersion = r.URL.Query().Get("api-version")
+		apiKey = r.Header.Get("api-key")
+		authorization = r.Header.Get("Authorization")
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	service := NewLLMService(LLMConfig{
+		APIKey:        "azure-key",
+		AzureEndpoint: server.URL + "/",
+		Deployment:    "gpt-4o-prod",
+		APIVersion:    "2024-06-01",
+	})
+
+	llmCtx := services.NewLLMContext("You are helpful")
+	llmCtx.AddUserMessage("hi")
+	if err := service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+		t.Errorf("Expected the deployment path, got %q", path)
+	}
+	if apiVersion != "2024-06-01" {
+		t.Errorf("Expected api-version 2024-06-01, got %q", apiVersion)
+	}
+	if apiKey != "azure-key" {
+		t.Errorf("Expected api-key header, got %q", apiKey)
+	}
+	if authorization != "" {
+		t.Errorf("Expected no Authorization header for Azure, got %q", authorization)
+	}
+}
+
+func TestLLMServiceAzureDefaultAPIVersion(t *testing.T) {
+	service := NewLLMService(LLMConfig{
+		AzureEndpoint: "https://myresource.openai.azure.com",
+		Deployment:    "chat",
+	})
+	want := "https://myresource.openai.azure.com/openai/deployments/chat/chat/completions?api-version=" + DefaultAzureAPIVersion
+	if service.azureURL != want {
+		t.Fatalf("Expected %q, got %q", want, service.azureURL)
+	}
+
+	// Standard OpenAI stays the default
+	if standard := NewLLMService(LLMConfig{APIKey: "k"}); standard.azureURL != "" || standard.baseURL != DefaultBaseURL {
+		t.Fatalf("Expected standard OpenAI by default, got azureURL=%q baseURL=%q", standard.azureURL, standard.baseURL)
+	}
+}
+
+func TestLLMServiceParamsFrameAppliesToNextGenerationOnly(t *testing.T) {
+	var mu sync.Mutex
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, body)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	service := NewLLMService(LLMConfig{APIKey: "test-key", BaseURL: server.URL, Temperature: 0.7})
+	service.Link(&frameCapturer{})
+
+	temperature, maxTokens := 0.0, 20
+	params := frames.NewLLMParamsFrame(&temperature, nil, &maxTokens)
+	if err := service.HandleFrame(context.Background(), params, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMParamsFrame) failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		llmCtx := services.NewLLMContext("You are helpful")
+		llmCtx.AddUserMessage("Hello")
+		if err := service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if requests[0]["temperature"] != 0.0 || requests[0]["max_tokens"] != 20.0 {
+		t.Errorf("expected overrides on first request, got temperature %v max_tokens %v",
+			requests[0]["temperature"], requests[0]["max_tokens"])
+	}
+	if _, ok := requests[0]["top_p"]; ok {
+		t.Errorf("expected no top_p when not overridden, got %v", requests[0]["top_p"])
+	}
+	if requests[1]["temperature"] != 0.7 {
+		t.Errorf("expected default temperature restored, got %v", requests[1]["temperature"])
+	}
+	if _, ok := requests[1]["max_tokens"]; ok {
+		t.Errorf("expected max_tokens cleared, got %v", requests[1]["max_tokens"])
+	}
+}
+
+// TestLLMServiceInterruptionPolicy interrupts a response mid-stream and checks
+// Abort cancels it while CompleteSilently lets it finish into the context,
+// ahead of the interrupting turn, without pushing the rest of it or holding
+// up the next context
+func TestLLMServiceInterruptionPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		policy     frames.InterruptionLLMPolicy
+		wantSilent bool
+	}{
+		{"abort", frames.InterruptionLLMAbort, false},
+		{"complete silently", frames.InterruptionLLMCompleteSilently, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				if requests.Add(1) > 1 {
+					fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Sure\"}}]}\n\n")
+					fmt.Fprint(w, "data: [DONE]\n\n")
+					return
+				}
+				fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+				w.(http.Flusher).Flush()
+				select {
+				case <-release:
+				case <-r.Context().Done():
+					return
+				}
+				fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\" world\"}}]}\n\n")
+				fmt.Fprint(w, "data: [DONE]\n\n")
+			}))
+			defer server.Close()
+
+			service := NewLLMService(LLMConfig{APIKey: "test-key", BaseURL: server.URL})
+			capturer := &frameCapturer{}
+			service.Link(capturer)
+			start := frames.NewStartFrame()
+			start.InterruptionLLMPolicy = tc.policy
+			if err := service.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+				t.Fatalf("ProcessFrame(StartFrame) failed: %v", err)
+			}
+
+			llmCtx := services.NewLLMContext("")
+			llmCtx.AddUserMessage("Hi")
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream)
+			}()
+
+			// Interrupt once the first token is out and the context is no
+			// longer new enough for the interruption to be ignored
+			deadline := time.Now().Add(2 * time.Second)
+			for len(capturedText(capturer)) == 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("No text before the deadline")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			time.Sleep(150 * time.Millisecond)
+			service.HandleFrame(context.Background(), frames.NewInterruptionFrame(), frames.Downstream)
+
+			// Neither policy waits for the rest of the stream
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("Generation did not finish")
+			}
+			close(release)
+
+			if text := capturedText(capturer); len(text) != 1 || text[0] != "Hello" {
+				t.Errorf("Pushed text %q, want only the token before the interruption", text)
+			}
+			capturer.mu.Lock()
+			end, ok := capturer.frames[len(capturer.frames)-1].(*frames.LLMFullResponseEndFrame)
+			capturer.mu.Unlock()
+			if !ok {
+				t.Fatal("Expected the response end last")
+			}
+			if silent, _ := end.Metadata()[frames.SilentResponseKey].(bool); silent != tc.wantSilent {
+				t.Errorf("Response end silent = %v, want %v", silent, tc.wantSilent)
+			}
+
+			// The interrupting turn follows once the silenced reply is complete
+			time.Sleep(50 * time.Millisecond)
+			llmCtx.AddUserMessage("Wait")
+			if err := service.HandleFrame(context.Background(), frames.NewLLMContextFrame(llmCtx), frames.Downstream); err != nil {
+				t.Fatalf("HandleFrame(LLMContextFrame) failed: %v", err)
+			}
+
+			var got []string
+			for _, msg := range llmCtx.Messages {
+				got = append(got, msg.Role+":"+msg.Content)
+			}
+			want := []string{"user:Hi", "user:Wait", "assistant:Sure"}
+			if tc.wantSilent {
+				want = []string{"user:Hi", "assistant:Hello world", "user:Wait", "assistant:Sure"}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("Context = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// capturedText returns the text of the LLMTextFrames captured so far
+func capturedText(c *frameCapturer) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var text []string
+	for _, f := range c.frames {
+		if tf, ok := f.(*frames.LLMTextFrame); ok {
+			text = append(text, tf.Text)
+		}
+	}
+	return text
+}
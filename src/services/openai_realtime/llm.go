@@ -561,11 +561,25 @@ func (s *LLMService) pushFunctionCallFrame(event realtimeLLMEvent, done bool) {
 		b.args.WriteString(event.Arguments)
 	}
 	argsJSON = b.args.String()
+	name = b.name
 	if done {
 		delete(s.functionCallBuilders, toolCallID)
 	}
 	s.stateMu.Unlock()
 
+	fnName := name
+	if fnName == "" {
+		fnName = "function"
+	}
+
+	// Deltas only preview the call; it runs once the arguments are complete
+	if !done {
+		if event.Delta != "" {
+			s.pushFrameSafe(frames.NewFunctionCallArgsDeltaFrame(toolCallID, fnName, event.Delta, argsJSON), frames.Downstream)
+		}
+		return
+	}
+
 	args := map[string]interface{}{}
 	if strings.TrimSpace(argsJSON) != "" {
 		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
@@ -573,11 +587,6 @@ func (s *LLMService) pushFunctionCallFrame(event realtimeLLMEvent, done bool) {
 		}
 	}
 
-	fnName := name
-	if fnName == "" {
-		fnName = "function"
-	}
-
 	s.pushFrameSafe(frames.NewFunctionCallInProgressFrame(toolCallID, fnName, args, true), frames.Downstream)
 }
 
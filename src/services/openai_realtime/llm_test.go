@@ -473,6 +473,25 @@ func TestOpenAIRealtimeLLM_FunctionCall(t *testing.T) {
 		"delta":   " Francisco\"}",
 	})
 
+	// The delta previews the call without running it
+	first, ok := h.downstream.waitForFrame(2*time.Second, func(frame frames.Frame) bool {
+		switch frame.(type) {
+		case *frames.FunctionCallArgsDeltaFrame, *frames.FunctionCallInProgressFrame:
+			return true
+		}
+		return false
+	})
+	if !ok {
+		t.Fatal("missing FunctionCallArgsDeltaFrame")
+	}
+	delta, isDelta := first.(*frames.FunctionCallArgsDeltaFrame)
+	if !isDelta {
+		t.Fatalf("expected a FunctionCallArgsDeltaFrame before the call runs, got %s", first.Name())
+	}
+	if delta.ToolCallID != "call-1" || delta.FunctionName != "lookup_weather" || delta.Arguments != "{\"city\":\"San" {
+		t.Errorf("unexpected delta frame: %+v", delta)
+	}
+
 	frameAny, ok := h.downstream.waitForFrame(2*time.Second, func(frame frames.Frame) bool {
 		fcf, match := frame.(*frames.FunctionCallInProgressFrame)
 		if !match {
@@ -350,13 +350,17 @@ func (s *STTService) handleServerMessage(message []byte) error {
 
 	switch event.Type {
 	case "input_audio_buffer.speech_started":
-		return s.PushFrame(frames.NewUserStartedSpeakingFrame(), frames.Downstream)
+		startedFrame := frames.NewUserStartedSpeakingFrame()
+		startedFrame.Source = "openai_realtime"
+		return s.PushFrame(startedFrame, frames.Downstream)
 
 	case "input_audio_buffer.speech_stopped":
 		if err := s.BroadcastInterruption(s.getContext()); err != nil {
 			return fmt.Errorf("failed to broadcast interruption: %w", err)
 		}
-		return nil
+		stoppedFrame := frames.NewUserStoppedSpeakingFrame()
+		stoppedFrame.Source = "openai_realtime"
+		return s.PushFrame(stoppedFrame, frames.Downstream)
 
 	case "conversation.item.input_audio_transcription.completed":
 		transcript := event.extractTranscript()
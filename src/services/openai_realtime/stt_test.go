@@ -340,6 +340,13 @@ func TestOpenAIRealtimeSTT_ServerVAD(t *testing.T) {
 	}); !ok {
 		t.Fatal("missing downstream InterruptionFrame from server VAD speech_stopped")
 	}
+
+	if _, ok := downstream.waitForFrame(2*time.Second, func(frame frames.Frame) bool {
+		stopped, match := frame.(*frames.UserStoppedSpeakingFrame)
+		return match && stopped.Source == "openai_realtime"
+	}); !ok {
+		t.Fatal("missing downstream UserStoppedSpeakingFrame from server VAD speech_stopped")
+	}
 }
 
 func TestOpenAIRealtimeSTT_LocalVAD(t *testing.T) {
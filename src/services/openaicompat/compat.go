@@ -0,0 +1,162 @@
+// Package openaicompat implements the chat completions wire format shared by
+// OpenAI-compatible APIs (OpenAI, Azure OpenAI, Groq, Ollama and local
+// gateways): translating an LLMContext into a request, sending it and parsing
+// the streamed reply, including tool calls.
+package openaicompat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// Endpoint is an OpenAI-compatible chat completions endpoint
+type Endpoint struct {
+	Name       string // Provider name used in errors (e.g., "OpenAI")
+	URL        string // Full chat completions URL
+	AuthHeader string // Header carrying the credential (e.g., "Authorization", "api-key"); empty sends none
+	AuthValue  string // Header value (e.g., "Bearer sk-...")
+}
+
+// BearerEndpoint returns the endpoint at baseURL + "/chat/completions",
+// authenticated with a Bearer token unless apiKey is empty
+func BearerEndpoint(name, baseURL, apiKey string) Endpoint {
+	e := Endpoint{Name: name, URL: baseURL + "/chat/completions"}
+	if apiKey != "" {
+		e.AuthHeader = "Authorization"
+		e.AuthValue = "Bearer " + apiKey
+	}
+	return e
+}
+
+// RoleMapper renames message roles a provider does not accept
+type RoleMapper func(role string) string
+
+// DeveloperAsUser maps OpenAI's "developer" role to "user" for providers that
+// speak the OpenAI wire format but reject it
+func DeveloperAsUser(role string) string {
+	if role == "developer" {
+		return "user"
+	}
+	return role
+}
+
+// BuildMessages translates the context's system prompt and messages, with
+// their tool calls and tool results, into chat messages
+func BuildMessages(llmCtx *services.LLMContext, mapRole RoleMapper) []map[string]interface{} {
+	messages := []map[string]interface{}{}
+
+	if llmCtx.SystemPrompt != "" {
+		messages = append(messages, map[string]interface{}{
+			"role":    "system",
+			"content": llmCtx.SystemPrompt,
+		})
+	}
+
+	for _, msg := range llmCtx.Messages {
+		role := msg.Role
+		if mapRole != nil {
+			role = mapRole(role)
+		}
+		message := map[string]interface{}{
+			"role": role,
+		}
+
+		if msg.Content != "" {
+			message["content"] = msg.Content
+		}
+
+		// Tool calls made by the assistant
+		if len(msg.ToolCalls) > 0 {
+			toolCalls := []map[string]interface{}{}
+			for _, tc := range msg.ToolCalls {
+				toolCalls = append(toolCalls, map[string]interface{}{
+					"id":   tc.ID,
+					"type": tc.Type,
+					"function": map[string]interface{}{
+						"name":      tc.Function.Name,
+						"arguments": tc.Function.Arguments,
+					},
+				})
+			}
+			message["tool_calls"] = toolCalls
+		}
+
+		// Result of a tool call
+		if msg.ToolCallID != "" {
+			message["tool_call_id"] = msg.ToolCallID
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+// BuildRequest returns a streaming chat completions request body for the
+// context, including its tools and tool choice
+func BuildRequest(model string, temperature float64, llmCtx *services.LLMContext, mapRole RoleMapper) map[string]interface{} {
+	requestBody := map[string]interface{}{
+		"model":       model,
+		"messages":    BuildMessages(llmCtx, mapRole),
+		"temperature": temperature,
+		"stream":      true,
+	}
+
+	if len(llmCtx.Tools) > 0 {
+		tools := []map[string]interface{}{}
+		for _, tool := range llmCtx.Tools {
+			tools = append(tools, map[string]interface{}{
+				"type": tool.Type,
+				"function": map[string]interface{}{
+					"name":        tool.Function.Name,
+					"description": tool.Function.Description,
+					"parameters":  tool.Function.Parameters,
+				},
+			})
+		}
+		requestBody["tools"] = tools
+
+		if llmCtx.ToolChoice != nil {
+			requestBody["tool_choice"] = llmCtx.ToolChoice
+		}
+	}
+
+	return requestBody
+}
+
+// Send posts the request body and returns the streaming response body. The
+// caller must close it. Cancelling ctx aborts the request.
+func (e Endpoint) Send(ctx context.Context, requestBody map[string]interface{}) (io.ReadCloser, error) {
+	bodyBytes, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.URL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	if e.AuthHeader != "" {
+		req.Header.Set(e.AuthHeader, e.AuthValue)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s API error: %s", e.Name, string(body))
+	}
+	return resp.Body, nil
+}
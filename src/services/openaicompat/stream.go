@@ -0,0 +1,217 @@
+package openaicompat
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// StreamResult is the reply a stream produced: text, or the tool calls the
+// model made in index order
+type StreamResult struct {
+	Text      string
+	ToolCalls []services.ToolCall
+}
+
+// streamChunk is one server-sent chat completion chunk
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// partialToolCall accumulates the streamed fragments of one function call
+type partialToolCall struct {
+	id        string
+	callType  string
+	name      string
+	arguments strings.Builder
+}
+
+// PushFunc delivers a frame of the response downstream. It reports false
+// when the frame was withheld because the response has been silenced (see
+// frames.InterruptionLLMCompleteSilently).
+type PushFunc func(frame frames.Frame) bool
+
+// RecordFunc records the completed reply in the conversation, such as
+// services.Generation.AddReply
+type RecordFunc func(msg services.LLMMessage)
+
+// ParseStream reads chat completion chunks from r until [DONE]. Each text
+// delta is pushed as an LLMTextFrame; tool calls are accumulated by index,
+// with each argument fragment pushed as a FunctionCallArgsDeltaFrame. If ctx
+// is cancelled mid-stream it stops and returns ctx.Err().
+func ParseStream(ctx context.Context, r io.Reader, push PushFunc) (StreamResult, error) {
+	var text strings.Builder
+	partialCalls := map[int]*partialToolCall{}
+	maxIdx := -1
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return StreamResult{}, ctx.Err()
+		default:
+		}
+
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil || len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta
+
+		if delta.Content != "" {
+			text.WriteString(delta.Content)
+			// Sentence splitting is handled by the SentenceAggregator
+			push(frames.NewLLMTextFrame(delta.Content))
+		}
+
+		// Only the first fragment of a call carries its id and name;
+		// arguments arrive in pieces
+		for _, tcDelta := range delta.ToolCalls {
+			idx := tcDelta.Index
+			pt, ok := partialCalls[idx]
+			if !ok {
+				pt = &partialToolCall{}
+				partialCalls[idx] = pt
+			}
+			maxIdx = max(maxIdx, idx)
+			if tcDelta.ID != "" {
+				pt.id = tcDelta.ID
+			}
+			if tcDelta.Type != "" {
+				pt.callType = tcDelta.Type
+			}
+			if tcDelta.Function.Name != "" {
+				pt.name = tcDelta.Function.Name
+			}
+			if tcDelta.Function.Arguments != "" {
+				pt.arguments.WriteString(tcDelta.Function.Arguments)
+				push(frames.NewFunctionCallArgsDeltaFrame(pt.id, pt.name, tcDelta.Function.Arguments, pt.arguments.String()))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return StreamResult{}, ctx.Err()
+		}
+		return StreamResult{}, err
+	}
+
+	result := StreamResult{Text: text.String()}
+	for i := 0; i <= maxIdx; i++ {
+		pt, ok := partialCalls[i]
+		if !ok {
+			continue
+		}
+		callType := pt.callType
+		if callType == "" {
+			callType = "function"
+		}
+		result.ToolCalls = append(result.ToolCalls, services.ToolCall{
+			ID:   pt.id,
+			Type: callType,
+			Function: services.FunctionCall{
+				Name:      pt.name,
+				Arguments: pt.arguments.String(),
+			},
+		})
+	}
+	return result, nil
+}
+
+// ToolCallFrames returns the frames that run a set of tool calls: one
+// FunctionCallsStartedFrame, then a FunctionCallInProgressFrame per call
+func ToolCallFrames(calls []services.ToolCall) []frames.Frame {
+	callInfos := make([]frames.FunctionCallInfo, 0, len(calls))
+	for _, call := range calls {
+		callInfos = append(callInfos, frames.FunctionCallInfo{
+			ToolCallID:   call.ID,
+			FunctionName: call.Function.Name,
+		})
+	}
+
+	out := []frames.Frame{frames.NewFunctionCallsStartedFrame(callInfos)}
+	for _, call := range calls {
+		args := map[string]interface{}{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				args = map[string]interface{}{}
+			}
+		}
+		out = append(out, frames.NewFunctionCallInProgressFrame(call.ID, call.Function.Name, args, true))
+	}
+	return out
+}
+
+// Complete sends the request and streams the reply through push, then
+// records it: tool calls are pushed as ToolCallFrames and recorded as an
+// assistant tool-call message, text as an assistant message. Tool calls of a
+// silenced response are not run, so they are not recorded either. Cancelling
+// ctx (an interruption) ends it early without error, recording nothing.
+func (e Endpoint) Complete(ctx context.Context, requestBody map[string]interface{}, record RecordFunc, push PushFunc) (StreamResult, error) {
+	// Hold a stream slot until the reply has been read
+	release, err := services.RateLimiterFor(e.Name, e.AuthValue).Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return StreamResult{}, nil // Interrupted while queued
+		}
+		return StreamResult{}, fmt.Errorf("%s: %w", e.Name, err)
+	}
+	defer release()
+
+	body, err := e.Send(ctx, requestBody)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return StreamResult{}, nil
+		}
+		return StreamResult{}, err
+	}
+	defer body.Close()
+
+	result, err := ParseStream(ctx, body, push)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return StreamResult{}, nil
+		}
+		return StreamResult{}, err
+	}
+
+	if len(result.ToolCalls) > 0 {
+		for _, frame := range ToolCallFrames(result.ToolCalls) {
+			if !push(frame) {
+				return result, nil
+			}
+		}
+		record(services.LLMMessage{Role: "assistant", ToolCalls: result.ToolCalls})
+	} else if result.Text != "" {
+		record(services.LLMMessage{Role: "assistant", Content: result.Text})
+	}
+	return result, nil
+}
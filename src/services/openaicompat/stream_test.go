@@ -0,0 +1,198 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// startStreamServer returns a chat completions server that checks the auth
+// header and streams each chunk as a delta
+func startStreamServer(t *testing.T, authHeader, authValue string, deltas []string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authHeader != "" && r.Header.Get(authHeader) != authValue {
+			t.Errorf("Expected %s %q, got %q", authHeader, authValue, r.Header.Get(authHeader))
+		}
+		if authHeader == "" && r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected no Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["stream"] != true {
+			t.Errorf("Expected a streaming request, got %v (%v)", body, err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range deltas {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":%s}]}\n\n", delta)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestCompleteText(t *testing.T) {
+	deltas := []string{`{"role":"assistant"}`, `{"content":"Hello"}`, `{"content":", world"}`}
+	for _, tc := range []struct {
+		name                  string
+		authHeader, authValue string
+		endpoint              func(url string) Endpoint
+	}{
+		{"bearer", "Authorization", "Bearer sk-test", func(url string) Endpoint {
+			return BearerEndpoint("OpenAI", url, "sk-test")
+		}},
+		{"api-key", "api-key", "azure-key", func(url string) Endpoint {
+			return Endpoint{Name: "Azure", URL: url + "/chat/completions", AuthHeader: "api-key", AuthValue: "azure-key"}
+		}},
+		{"no auth", "", "", func(url string) Endpoint {
+			return BearerEndpoint("Ollama", url, "")
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := startStreamServer(t, tc.authHeader, tc.authValue, deltas)
+			defer server.Close()
+			endpoint := tc.endpoint(server.URL)
+
+			llmCtx := services.NewLLMContext("You are helpful")
+			llmCtx.AddUserMessage("Hi")
+			var pushed []string
+			result, err := endpoint.Complete(context.Background(), BuildRequest("test-model", 0.7, llmCtx, nil), llmCtx.AddMessage, func(frame frames.Frame) bool {
+				if text, ok := frame.(*frames.LLMTextFrame); ok {
+					pushed = append(pushed, text.Text)
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatalf("Complete failed: %v", err)
+			}
+
+			if result.Text != "Hello, world" || len(result.ToolCalls) != 0 {
+				t.Errorf("Result = %+v, want text %q", result, "Hello, world")
+			}
+			if strings.Join(pushed, "|") != "Hello|, world" {
+				t.Errorf("Pushed text %q, want each delta", pushed)
+			}
+			last := llmCtx.Messages[len(llmCtx.Messages)-1]
+			if last.Role != "assistant" || last.Content != "Hello, world" {
+				t.Errorf("Last context message = %+v, want the assistant reply", last)
+			}
+		})
+	}
+}
+
+func TestCompleteToolCalls(t *testing.T) {
+	deltas := []string{
+		`{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}`,
+		`{"tool_calls":[{"index":1,"id":"call_2","function":{"name":"get_time","arguments":"{}"}}]}`,
+		`{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}`,
+		`{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}`,
+	}
+	server := startStreamServer(t, "Authorization", "Bearer sk-test", deltas)
+	defer server.Close()
+
+	llmCtx := services.NewLLMContext("")
+	llmCtx.AddUserMessage("Weather and time in Paris?")
+	var pushed []frames.Frame
+	result, err := BearerEndpoint("Groq", server.URL, "sk-test").Complete(context.Background(), BuildRequest("m", 0, llmCtx, nil), llmCtx.AddMessage, func(frame frames.Frame) bool {
+		pushed = append(pushed, frame)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if len(result.ToolCalls) != 2 {
+		t.Fatalf("Expected 2 tool calls, got %+v", result.ToolCalls)
+	}
+	first, second := result.ToolCalls[0], result.ToolCalls[1]
+	if first.ID != "call_1" || first.Function.Name != "get_weather" || first.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("First call = %+v", first)
+	}
+	if second.ID != "call_2" || second.Type != "function" || second.Function.Arguments != "{}" {
+		t.Errorf("Second call = %+v, want type defaulted to function", second)
+	}
+
+	var started int
+	var inProgress []*frames.FunctionCallInProgressFrame
+	for _, frame := range pushed {
+		switch f := frame.(type) {
+		case *frames.FunctionCallsStartedFrame:
+			started++
+		case *frames.FunctionCallInProgressFrame:
+			inProgress = append(inProgress, f)
+		}
+	}
+	if started != 1 || len(inProgress) != 2 {
+		t.Fatalf("Expected 1 started and 2 in-progress frames, got %d and %d", started, len(inProgress))
+	}
+	if city, _ := inProgress[0].Arguments["city"].(string); city != "Paris" {
+		t.Errorf("Expected city Paris, got %v", inProgress[0].Arguments)
+	}
+
+	last := llmCtx.Messages[len(llmCtx.Messages)-1]
+	if len(last.ToolCalls) != 2 {
+		t.Errorf("Expected the tool calls recorded in the context, got %+v", last)
+	}
+}
+
+func TestBuildMessagesMapsRolesAndToolResults(t *testing.T) {
+	llmCtx := services.NewLLMContext("system prompt")
+	llmCtx.Messages = append(llmCtx.Messages,
+		services.LLMMessage{Role: "developer", Content: "be brief"},
+		services.LLMMessage{Role: "tool", Content: "sunny", ToolCallID: "call_1"},
+	)
+
+	messages := BuildMessages(llmCtx, DeveloperAsUser)
+	if len(messages) != 3 || messages[0]["role"] != "system" {
+		t.Fatalf("Unexpected messages %v", messages)
+	}
+	if messages[1]["role"] != "user" {
+		t.Errorf("Expected developer mapped to user, got %v", messages[1]["role"])
+	}
+	if messages[2]["tool_call_id"] != "call_1" {
+		t.Errorf("Expected tool_call_id on the tool result, got %v", messages[2])
+	}
+	if BuildMessages(llmCtx, nil)[1]["role"] != "developer" {
+		t.Error("Expected roles unchanged without a mapper")
+	}
+}
+
+func TestSendReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid model", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := BearerEndpoint("Groq", server.URL, "key").Send(context.Background(), map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "Groq API error: invalid model") {
+		t.Errorf("Expected a Groq API error, got %v", err)
+	}
+}
+
+func TestCompleteHonoursRateLimit(t *testing.T) {
+	server := startStreamServer(t, "Authorization", "Bearer sk-limited", []string{`{"content":"ok"}`})
+	defer server.Close()
+	services.SetRateLimit("LimitedCompat", services.RateLimitConfig{RequestsPerSecond: 0.1, MaxWait: 10 * time.Millisecond})
+	endpoint := BearerEndpoint("LimitedCompat", server.URL, "sk-limited")
+
+	complete := func() error {
+		llmCtx := services.NewLLMContext("")
+		llmCtx.AddUserMessage("Hi")
+		_, err := endpoint.Complete(context.Background(), BuildRequest("test-model", 0.7, llmCtx, nil), llmCtx.AddMessage, func(frames.Frame) bool { return true })
+		return err
+	}
+	if err := complete(); err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+	if err := complete(); !errors.Is(err, services.ErrRateLimited) {
+		t.Errorf("Expected the second request to be rate limited, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitMaxWait is how long a request queues for the limiter
+// before failing with ErrRateLimited
+const DefaultRateLimitMaxWait = 2 * time.Second
+
+// ErrRateLimited is returned when a request could not get a token or stream
+// slot within MaxWait. Services surface it in an ErrorFrame; check for it
+// with errors.Is.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitConfig configures the shared limiter for one provider
+type RateLimitConfig struct {
+	RequestsPerSecond float64       // Sustained request rate (0 = unlimited)
+	Burst             int           // Requests allowed back to back before throttling (default: 1)
+	MaxConcurrent     int           // Simultaneous streams (0 = unlimited)
+	MaxWait           time.Duration // How long a request queues before failing (default: 2s)
+}
+
+// RateLimiter is a token bucket plus a stream semaphore shared by every
+// service instance using the same provider and API key, so many concurrent
+// calls stay under the provider's limits instead of hitting 429s.
+// A nil limiter allows everything, so services can consult it unconditionally.
+type RateLimiter struct {
+	rps     float64
+	burst   float64
+	maxWait time.Duration
+	slots   chan struct{} // nil when concurrency is unlimited
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter from config
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	burst := config.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	maxWait := config.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRateLimitMaxWait
+	}
+
+	l := &RateLimiter{
+		rps:     config.RequestsPerSecond,
+		burst:   float64(burst),
+		maxWait: maxWait,
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return l
+}
+
+// Wait blocks until a request may be issued. If that would take longer than
+// MaxWait it returns ErrRateLimited immediately, without consuming a token.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rps <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rps)
+	l.last = now
+
+	// Reserve a token; a negative balance is the queue ahead of us
+	delay := time.Duration((1 - l.tokens) / l.rps * float64(time.Second))
+	if delay > l.maxWait {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: next request slot in %v", ErrRateLimited, delay.Round(time.Millisecond))
+	}
+	l.tokens--
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // Return the reservation
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Acquire waits for a request token and a stream slot. The returned release
+// frees the slot and must be called when the stream ends; it is safe to call
+// more than once.
+func (l *RateLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if err := l.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: all %d streams in use", ErrRateLimited, cap(l.slots))
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-l.slots })
+	}, nil
+}
+
+var (
+	rateLimitMu      sync.Mutex
+	rateLimitConfigs = make(map[string]RateLimitConfig)
+	rateLimiters     = make(map[string]*RateLimiter)
+)
+
+// SetRateLimit configures the limit for provider (e.g. "deepgram", "openai").
+// Services look their limiter up per request or stream, so a new config
+// takes effect on their next one. Usually called once at startup.
+//
+// Honoured by the LLMs "openai", "groq", "ollama", "anthropic" and "gemini"
+// (a stream slot per completion), the "deepgram" STT (a slot per connection)
+// and the TTS services "cartesia", "elevenlabs" and "deepgram" (a token per
+// synthesis). Other providers ignore it.
+func SetRateLimit(provider string, config RateLimitConfig) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+
+	provider = normalizeProviderName(provider)
+	rateLimitConfigs[provider] = config
+	for key := range rateLimiters {
+		if strings.HasPrefix(key, provider+"/") {
+			delete(rateLimiters, key)
+		}
+	}
+}
+
+// RateLimiterFor returns the limiter shared by all services using provider
+// with apiKey, or nil if no limit is configured for the provider. Different
+// API keys get independent limiters, as providers limit per key.
+func RateLimiterFor(provider, apiKey string) *RateLimiter {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+
+	provider = normalizeProviderName(provider)
+	config, ok := rateLimitConfigs[provider]
+	if !ok {
+		return nil
+	}
+
+	// Hash the key so the registry never holds credentials
+	sum := sha256.Sum256([]byte(apiKey))
+	key := provider + "/" + hex.EncodeToString(sum[:8])
+	limiter, ok := rateLimiters[key]
+	if !ok {
+		limiter = NewRateLimiter(config)
+		rateLimiters[key] = limiter
+	}
+	return limiter
+}
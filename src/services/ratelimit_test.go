@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterThrottlesToRate(t *testing.T) {
+	l := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 20})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait %d failed: %v", i, err)
+		}
+	}
+	// The first request uses the burst token; the other four are 50ms apart
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("expected 5 requests at 20rps to take ~200ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 1, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait %d failed: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected burst requests to pass immediately, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterFailsPastMaxWait(t *testing.T) {
+	l := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 1, MaxWait: 100 * time.Millisecond})
+	ctx := context.Background()
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("first Wait failed: %v", err)
+	}
+	start := time.Now()
+	err := l.Wait(ctx)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected rate limit to fail fast, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterConcurrencyCap(t *testing.T) {
+	l := NewRateLimiter(RateLimitConfig{MaxConcurrent: 2, MaxWait: 100 * time.Millisecond})
+	ctx := context.Background()
+
+	release1, err := l.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire 1 failed: %v", err)
+	}
+	if _, err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire 2 failed: %v", err)
+	}
+
+	// A third stream waits for a slot, then gives up
+	start := time.Now()
+	if _, err := l.Acquire(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected third stream to be rate limited, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected third stream to queue for MaxWait, gave up after %v", elapsed)
+	}
+
+	// Releasing a stream lets a queued one through
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := l.Acquire(ctx)
+		acquired <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	release1()
+	release1() // Double release must not free a second slot
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("expected queued stream to get the released slot, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued stream never acquired a slot")
+	}
+	if _, err := l.Acquire(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected the cap to still hold after a double release, got %v", err)
+	}
+}
+
+func TestRateLimiterRegistry(t *testing.T) {
+	if RateLimiterFor("unlimited-test", "key") != nil {
+		t.Fatal("expected no limiter for an unconfigured provider")
+	}
+	// A nil limiter allows everything
+	var none *RateLimiter
+	if err := none.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter Wait failed: %v", err)
+	}
+
+	SetRateLimit("Limited-Test", RateLimitConfig{RequestsPerSecond: 5})
+	a := RateLimiterFor("limited-test", "key-a")
+	if a == nil {
+		t.Fatal("expected a limiter for a configured provider")
+	}
+	if RateLimiterFor("LIMITED-TEST", "key-a") != a {
+		t.Error("expected services sharing provider and key to share a limiter")
+	}
+	if RateLimiterFor("limited-test", "key-b") == a {
+		t.Error("expected a different API key to get its own limiter")
+	}
+
+	SetRateLimit("limited-test", RateLimitConfig{RequestsPerSecond: 10})
+	if b := RateLimiterFor("limited-test", "key-a"); b == a || b.rps != 10 {
+		t.Error("expected a new config to replace existing limiters")
+	}
+}
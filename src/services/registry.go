@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Well-known ServiceConfig keys shared by the registered providers
+const (
+	ConfigAPIKey       = "api_key"
+	ConfigModel        = "model"
+	ConfigLanguage     = "language"
+	ConfigVoice        = "voice"
+	ConfigEncoding     = "encoding"
+	ConfigSampleRate   = "sample_rate"
+	ConfigBaseURL      = "base_url"
+	ConfigRegion       = "region"
+	ConfigEndpoint     = "endpoint"
+	ConfigOutputFormat = "output_format"
+)
+
+// ServiceConfig is the provider-agnostic configuration passed to registered
+// factories. It is a plain map so it can be decoded straight from JSON or
+// built from environment variables; providers read the well-known keys above
+// plus any provider-specific ones and ignore the rest.
+type ServiceConfig map[string]interface{}
+
+// Has reports whether key is set
+func (c ServiceConfig) Has(key string) bool {
+	_, ok := c[key]
+	return ok
+}
+
+// String returns the value for key as a string, or "" if unset
+func (c ServiceConfig) String(key string) string {
+	switch v := c[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int returns the value for key as an int, or 0 if unset or not numeric.
+// Accepts ints, JSON numbers (float64) and numeric strings.
+func (c ServiceConfig) Int(key string) int {
+	switch v := c[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	}
+	return 0
+}
+
+// Bool returns the value for key as a bool, or false if unset.
+// Accepts bools and strings such as "true"/"1".
+func (c ServiceConfig) Bool(key string) bool {
+	switch v := c[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	}
+	return false
+}
+
+// Duration returns the value for key as a duration, or 0 if unset.
+// Accepts time.Duration values, strings like "5s" and numbers in seconds.
+func (c ServiceConfig) Duration(key string) time.Duration {
+	switch v := c[key].(type) {
+	case time.Duration:
+		return v
+	case float64:
+		return time.Duration(v * float64(time.Second))
+	case int:
+		return time.Duration(v) * time.Second
+	case string:
+		d, _ := time.ParseDuration(strings.TrimSpace(v))
+		return d
+	}
+	return 0
+}
+
+// StringSlice returns the value for key as a string slice, or nil if unset.
+// Accepts string slices, JSON arrays and comma-separated strings.
+func (c ServiceConfig) StringSlice(key string) []string {
+	switch v := c[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case string:
+		var values []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// STTFactory builds an STT service from a ServiceConfig
+type STTFactory func(config ServiceConfig) (STTService, error)
+
+// TTSFactory builds a TTS service from a ServiceConfig
+type TTSFactory func(config ServiceConfig) (TTSService, error)
+
+var (
+	registryMu   sync.RWMutex
+	sttFactories = make(map[string]STTFactory)
+	ttsFactories = make(map[string]TTSFactory)
+)
+
+// normalizeProviderName makes provider lookups case-insensitive
+func normalizeProviderName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RegisterSTT makes an STT provider available to BuildSTT by name.
+// Providers register themselves in an init function, so importing the
+// provider package (even blank) is enough. It panics if factory is nil or
+// the name is already registered.
+func RegisterSTT(name string, factory STTFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = normalizeProviderName(name)
+	if factory == nil {
+		panic("services: RegisterSTT factory is nil for " + name)
+	}
+	if _, dup := sttFactories[name]; dup {
+		panic("services: RegisterSTT called twice for " + name)
+	}
+	sttFactories[name] = factory
+}
+
+// RegisterTTS makes a TTS provider available to BuildTTS by name.
+// It panics if factory is nil or the name is already registered.
+func RegisterTTS(name string, factory TTSFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = normalizeProviderName(name)
+	if factory == nil {
+		panic("services: RegisterTTS factory is nil for " + name)
+	}
+	if _, dup := ttsFactories[name]; dup {
+		panic("services: RegisterTTS called twice for " + name)
+	}
+	ttsFactories[name] = factory
+}
+
+// BuildSTT creates the STT service registered under name
+func BuildSTT(name string, config ServiceConfig) (STTService, error) {
+	registryMu.RLock()
+	factory, ok := sttFactories[normalizeProviderName(name)]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown STT provider %q (registered: %s)", name, strings.Join(STTProviders(), ", "))
+	}
+	if config == nil {
+		config = ServiceConfig{}
+	}
+
+	service, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build STT provider %q: %w", name, err)
+	}
+	return service, nil
+}
+
+// BuildTTS creates the TTS service registered under name
+func BuildTTS(name string, config ServiceConfig) (TTSService, error) {
+	registryMu.RLock()
+	factory, ok := ttsFactories[normalizeProviderName(name)]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown TTS provider %q (registered: %s)", name, strings.Join(TTSProviders(), ", "))
+	}
+	if config == nil {
+		config = ServiceConfig{}
+	}
+
+	service, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TTS provider %q: %w", name, err)
+	}
+	return service, nil
+}
+
+// STTProviders returns the registered STT provider names, sorted
+func STTProviders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return sortedKeys(sttFactories)
+}
+
+// TTSProviders returns the registered TTS provider names, sorted
+func TTSProviders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return sortedKeys(ttsFactories)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
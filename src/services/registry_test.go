@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+type fakeSTT struct {
+	*processors.BaseProcessor
+	apiKey     string
+	model      string
+	language   string
+	sampleRate int
+}
+
+func (f *fakeSTT) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	return f.PushFrame(frame, direction)
+}
+func (f *fakeSTT) Initialize(ctx context.Context) error { return nil }
+func (f *fakeSTT) Cleanup() error                       { return nil }
+func (f *fakeSTT) SetLanguage(lang string)              { f.language = lang }
+func (f *fakeSTT) SetModel(model string)                { f.model = model }
+
+type fakeTTS struct {
+	*processors.BaseProcessor
+	voice string
+}
+
+func (f *fakeTTS) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	return f.PushFrame(frame, direction)
+}
+func (f *fakeTTS) Initialize(ctx context.Context) error { return nil }
+func (f *fakeTTS) Cleanup() error                       { return nil }
+func (f *fakeTTS) SetVoice(voiceID string)              { f.voice = voiceID }
+func (f *fakeTTS) SetModel(model string)                {}
+
+func TestRegistry_BuildSTTByName(t *testing.T) {
+	RegisterSTT("Fake-STT", func(config ServiceConfig) (STTService, error) {
+		if config.String(ConfigAPIKey) == "" {
+			return nil, errors.New("api_key is required")
+		}
+		s := &fakeSTT{
+			apiKey:     config.String(ConfigAPIKey),
+			model:      config.String(ConfigModel),
+			language:   config.String(ConfigLanguage),
+			sampleRate: config.Int(ConfigSampleRate),
+		}
+		s.BaseProcessor = processors.NewBaseProcessor("FakeSTT", s)
+		return s, nil
+	})
+
+	// Config decoded from JSON, as an app would load it
+	var config ServiceConfig
+	if err := json.Unmarshal([]byte(`{"api_key":"k","model":"m1","language":"en","sample_rate":8000}`), &config); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	service, err := BuildSTT(" fake-stt ", config)
+	if err != nil {
+		t.Fatalf("BuildSTT failed: %v", err)
+	}
+	fake, ok := service.(*fakeSTT)
+	if !ok {
+		t.Fatalf("Expected *fakeSTT, got %T", service)
+	}
+	if fake.apiKey != "k" || fake.model != "m1" || fake.language != "en" || fake.sampleRate != 8000 {
+		t.Errorf("Config not applied: %+v", fake)
+	}
+
+	if _, err := BuildSTT("fake-stt", ServiceConfig{}); err == nil || !strings.Contains(err.Error(), "api_key is required") {
+		t.Errorf("Expected factory error to be surfaced, got %v", err)
+	}
+}
+
+func TestRegistry_BuildTTSByName(t *testing.T) {
+	RegisterTTS("fake-tts", func(config ServiceConfig) (TTSService, error) {
+		s := &fakeTTS{voice: config.String(ConfigVoice)}
+		s.BaseProcessor = processors.NewBaseProcessor("FakeTTS", s)
+		return s, nil
+	})
+
+	service, err := BuildTTS("fake-tts", ServiceConfig{ConfigVoice: "alice"})
+	if err != nil {
+		t.Fatalf("BuildTTS failed: %v", err)
+	}
+	if fake := service.(*fakeTTS); fake.voice != "alice" {
+		t.Errorf("Expected voice alice, got %q", fake.voice)
+	}
+
+	found := false
+	for _, name := range TTSProviders() {
+		found = found || name == "fake-tts"
+	}
+	if !found {
+		t.Errorf("Expected fake-tts in %v", TTSProviders())
+	}
+}
+
+func TestRegistry_UnknownProvider(t *testing.T) {
+	RegisterSTT("known-stt", func(config ServiceConfig) (STTService, error) { return nil, nil })
+
+	_, err := BuildSTT("nope", nil)
+	if err == nil {
+		t.Fatal("Expected error for unknown STT provider")
+	}
+	if msg := err.Error(); !strings.Contains(msg, `unknown STT provider "nope"`) || !strings.Contains(msg, "known-stt") {
+		t.Errorf("Expected error naming the provider and listing registered ones, got %q", msg)
+	}
+
+	if _, err := BuildTTS("nope", nil); err == nil || !strings.Contains(err.Error(), `unknown TTS provider "nope"`) {
+		t.Errorf("Expected error for unknown TTS provider, got %v", err)
+	}
+}
+
+func TestRegistry_DuplicateRegistrationPanics(t *testing.T) {
+	RegisterTTS("dup-tts", func(config ServiceConfig) (TTSService, error) { return nil, nil })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	RegisterTTS("DUP-TTS", func(config ServiceConfig) (TTSService, error) { return nil, nil })
+}
+
+func TestServiceConfig_Getters(t *testing.T) {
+	config := ServiceConfig{
+		"rate":    "16000",
+		"json":    float64(24000),
+		"flag":    "true",
+		"timeout": "5s",
+		"csv":     "a, b,,c",
+		"list":    []interface{}{"x", "y"},
+	}
+	if config.Int("rate") != 16000 || config.Int("json") != 24000 || config.Int("missing") != 0 {
+		t.Errorf("Unexpected Int results")
+	}
+	if !config.Bool("flag") || config.Bool("missing") {
+		t.Errorf("Unexpected Bool results")
+	}
+	if config.Duration("timeout").Seconds() != 5 {
+		t.Errorf("Expected 5s, got %v", config.Duration("timeout"))
+	}
+	if config.String("json") != "24000" || config.String("missing") != "" {
+		t.Errorf("Unexpected String results: %q", config.String("json"))
+	}
+	if got := strings.Join(config.StringSlice("csv"), "|"); got != "a|b|c" {
+		t.Errorf("Expected a|b|c, got %q", got)
+	}
+	if got := strings.Join(config.StringSlice("list"), "|"); got != "x|y" || config.StringSlice("missing") != nil {
+		t.Errorf("Unexpected StringSlice results: %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package sarvam
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("sarvam", func(config services.ServiceConfig) (services.STTService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		return NewSTTService(STTConfig{
+			APIKey:            config.String(services.ConfigAPIKey),
+			Model:             config.String(services.ConfigModel),
+			Language:          config.String(services.ConfigLanguage),
+			Mode:              config.String("mode"),
+			Prompt:            config.String("prompt"),
+			Encoding:          config.String(services.ConfigEncoding),
+			SampleRate:        config.Int(services.ConfigSampleRate),
+			KeepaliveInterval: config.Duration("keepalive_interval"),
+		}), nil
+	})
+}
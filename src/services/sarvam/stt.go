@@ -177,6 +177,7 @@ func NewSTTService(config STTConfig) *STTService {
 		log:                logger.WithPrefix("SarvamSTT"),
 	}
 	s.BaseProcessor = processors.NewBaseProcessor("SarvamSTT", s)
+	s.AttachLogger(s.log)
 	return s
 }
 
@@ -407,6 +408,7 @@ func (s *STTService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 		if err := s.Flush(); err != nil {
 			s.log.Warn("Flush on interruption failed: %v", err)
 		}
+		s.TraceInterruption(f.CorrelationID, frames.InterruptionStageSTTFinalized)
 		return s.PushFrame(f, direction)
 
 	case *frames.UserStoppedSpeakingFrame:
@@ -589,10 +591,14 @@ func (s *STTService) receiveTranscriptions(myConn *websocket.Conn) {
 			switch event.SignalType {
 			case "START_SPEECH":
 				s.log.Debug("VAD: user started speaking")
-				s.PushFrame(frames.NewUserStartedSpeakingFrame(), frames.Upstream)
+				startedFrame := frames.NewUserStartedSpeakingFrame()
+				startedFrame.Source = "sarvam"
+				s.PushFrame(startedFrame, frames.Downstream)
 			case "END_SPEECH":
 				s.log.Debug("VAD: user stopped speaking")
-				s.PushFrame(frames.NewUserStoppedSpeakingFrame(), frames.Upstream)
+				stoppedFrame := frames.NewUserStoppedSpeakingFrame()
+				stoppedFrame.Source = "sarvam"
+				s.PushFrame(stoppedFrame, frames.Downstream)
 			}
 
 		case "data":
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// noSpaceLanguages are written without spaces between sentences
+var noSpaceLanguages = map[string]bool{
+	"zh": true, "ja": true, "th": true, "lo": true, "km": true, "my": true,
+}
+
+// IsNoSpaceLanguage reports whether a language code (e.g. "zh", "ja-JP",
+// "zh_Hant") is written without spaces between sentences
+func IsNoSpaceLanguage(language string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(language), "-")
+	base, _, _ = strings.Cut(base, "_")
+	return noSpaceLanguages[base]
+}
+
+// basicSentenceEnders end a sentence when followed by a space
+var basicSentenceEnders = map[rune]bool{
+	'.': true, '!': true, '?': true, ';': true,
+}
+
+// fullWidthSentenceEnders end a sentence whatever follows: the scripts that
+// use them don't put spaces between sentences
+var fullWidthSentenceEnders = map[rune]bool{
+	// Chinese, Japanese
+	'。': true, '？': true, '！': true, '；': true, '．': true, '｡': true,
+	// Myanmar, Khmer
+	'။': true, '។': true,
+}
+
+// sentenceClosers are closing quotes and brackets kept with the sentence
+// they end, as in 「好。」
+var sentenceClosers = map[rune]bool{
+	'」': true, '』': true, '）': true, '】': true, '》': true,
+	'"': true, '\'': true, '”': true, '’': true, ')': true,
+}
+
+// SentenceEnd reports whether the sentence-ending punctuation at runes[i]
+// ends a sentence, and returns the index of the sentence's last rune, past
+// any closing quotes. Full-width punctuation always ends one; ASCII '!' and
+// '?' do too in no-space languages (see IsNoSpaceLanguage); anything else
+// needs a following space or the end of the text.
+func SentenceEnd(runes []rune, i int, language string) (int, bool) {
+	end := i
+	for end+1 < len(runes) && sentenceClosers[runes[end+1]] {
+		end++
+	}
+	if end == len(runes)-1 || unicode.IsSpace(runes[end+1]) {
+		return end, true
+	}
+	r := runes[i]
+	if fullWidthSentenceEnders[r] {
+		return end, true
+	}
+	if (r == '!' || r == '?') && IsNoSpaceLanguage(language) {
+		return end, true
+	}
+	return i, false
+}
+
+// SplitSentences splits text into complete sentences and the remainder, for
+// TTS services that buffer text by sentence. language selects the
+// punctuation rules (see SentenceEnd); "" uses the space-separated ones.
+func SplitSentences(text, language string) ([]string, string) {
+	var sentences []string
+	var current strings.Builder
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		current.WriteRune(runes[i])
+		if !basicSentenceEnders[runes[i]] && !fullWidthSentenceEnders[runes[i]] {
+			continue
+		}
+		end, ok := SentenceEnd(runes, i, language)
+		if !ok {
+			continue
+		}
+		current.WriteString(string(runes[i+1 : end+1]))
+		i = end
+		sentences = append(sentences, current.String())
+		current.Reset()
+	}
+
+	return sentences, current.String()
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	for _, tc := range []struct {
+		name, text, language string
+		want                 []string
+		remainder            string
+	}{
+		{"chinese", "你好。今天天气很好！我们去公园吧？好", "zh",
+			[]string{"你好。", "今天天气很好！", "我们去公园吧？"}, "好"},
+		{"japanese", "こんにちは。元気ですか？「はい。」と答えた", "ja",
+			[]string{"こんにちは。", "元気ですか？", "「はい。」"}, "と答えた"},
+		{"full width without language", "谢谢。再见", "",
+			[]string{"谢谢。"}, "再见"},
+		{"ascii marks in chinese", "真的吗?是的!好", "zh-CN",
+			[]string{"真的吗?", "是的!"}, "好"},
+		{"ascii marks need a space in english", "Really?Yes! Fine", "en",
+			[]string{"Really?Yes!"}, " Fine"},
+		{"decimal stays whole", "It costs 3.50 now. Ok", "",
+			[]string{"It costs 3.50 now."}, " Ok"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sentences, remainder := SplitSentences(tc.text, tc.language)
+			if !reflect.DeepEqual(sentences, tc.want) || remainder != tc.remainder {
+				t.Errorf("SplitSentences(%q) = %q, %q; want %q, %q", tc.text, sentences, remainder, tc.want, tc.remainder)
+			}
+		})
+	}
+}
+
+func TestIsNoSpaceLanguage(t *testing.T) {
+	for language, want := range map[string]bool{
+		"zh": true, "ja-JP": true, "zh_Hant": true, "TH": true,
+		"en": false, "ko": false, "": false,
+	} {
+		if got := IsNoSpaceLanguage(language); got != want {
+			t.Errorf("IsNoSpaceLanguage(%q) = %v, want %v", language, got, want)
+		}
+	}
+}
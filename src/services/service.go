@@ -103,6 +103,11 @@ func NewLLMContext(systemPrompt string) *LLMContext {
 	}
 }
 
+// AddMessage appends msg to the conversation
+func (c *LLMContext) AddMessage(msg LLMMessage) {
+	c.Messages = append(c.Messages, msg)
+}
+
 func (c *LLMContext) AddUserMessage(content string) {
 	c.Messages = append(c.Messages, LLMMessage{
 		Role:    "user",
@@ -301,6 +306,25 @@ func (m *AudioContextManager) ResetActiveAudioContext() {
 	m.currentTurnContextID = ""
 }
 
+// SetOnAudioContextCompleted installs OnAudioContextCompleted, for callers
+// that hold the service only as an interface (e.g. a failover wrapper).
+func (m *AudioContextManager) SetOnAudioContextCompleted(fn func(contextID string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.OnAudioContextCompleted = fn
+}
+
+// CompleteAudioContext reports that the provider finished generating
+// contextID's audio, calling OnAudioContextCompleted if set.
+func (m *AudioContextManager) CompleteAudioContext(contextID string) {
+	m.mu.Lock()
+	fn := m.OnAudioContextCompleted
+	m.mu.Unlock()
+	if fn != nil {
+		fn(contextID)
+	}
+}
+
 // GetTurnContextID returns the current turn context ID (may be empty).
 func (m *AudioContextManager) GetTurnContextID() string {
 	m.mu.Lock()
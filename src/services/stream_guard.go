@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// newContextGrace is how soon after a new context an InterruptionFrame is
+// taken to be about the previous response rather than the one just started
+const newContextGrace = 100 * time.Millisecond
+
+// StreamGuard tracks the response a streaming LLM service is generating so
+// an LLMCancelGenerationFrame or InterruptionFrame can stop it, or, under
+// frames.InterruptionLLMCompleteSilently, let it finish without being spoken.
+//
+// A silenced response is not waited for: Generation.Run returns so the next
+// context is not held up, and the reply is recorded in the context once it
+// is complete, at the position the request was made from.
+type StreamGuard struct {
+	proc *processors.BaseProcessor
+	log  *logger.Logger
+
+	mu            sync.Mutex
+	current       *Generation
+	lastContextAt time.Time     // When we last received a new context (for interruption filtering)
+	pending       []*Generation // Silenced replies completed in the background, not yet recorded
+}
+
+// Generation is one response a StreamGuard is tracking, from Begin to End
+type Generation struct {
+	guard     *StreamGuard
+	ctx       context.Context
+	cancel    context.CancelFunc
+	silenced  bool // Interrupted under InterruptionLLMCompleteSilently: withhold the rest
+	cancelled bool // Stopped by an interruption or cancel request
+
+	llmCtx     *LLMContext
+	replyAt    int           // len(llmCtx.Messages) when the request was made
+	reply      *LLMMessage   // Held for the service goroutine once detached
+	silencedCh chan struct{} // Closed when silenced
+	detached   bool          // Run returned; the response finishes in the background
+}
+
+// NewStreamGuard creates a guard pushing frames through proc, the service's
+// BaseProcessor
+func NewStreamGuard(proc *processors.BaseProcessor, log *logger.Logger) *StreamGuard {
+	return &StreamGuard{proc: proc, log: log}
+}
+
+// HandleFrame stops the current generation for an LLMCancelGenerationFrame,
+// which it consumes, or an InterruptionFrame, which it pushes on. It reports
+// whether frame was one of the two.
+func (g *StreamGuard) HandleFrame(frame frames.Frame, direction frames.FrameDirection) (bool, error) {
+	switch frame.(type) {
+	case *frames.LLMCancelGenerationFrame:
+		// A downstream processor (e.g. the ResponseLengthProcessor) needs no
+		// more of the current response
+		g.mu.Lock()
+		if g.current != nil {
+			g.log.Info("Cancelling ongoing stream on request")
+			g.stopLocked()
+		}
+		g.mu.Unlock()
+		return true, nil
+
+	case *frames.InterruptionFrame:
+		// If we just received a new context, this interruption is for the
+		// OLD response, not the new one. Don't cancel in that case.
+		g.mu.Lock()
+		timeSinceContext := time.Since(g.lastContextAt)
+		g.log.Warn("Interruption received (isGenerating=%v, timeSinceContext=%v)", g.current != nil, timeSinceContext)
+
+		if timeSinceContext < newContextGrace {
+			g.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
+		} else if g.current != nil {
+			if g.proc.InterruptionLLMPolicy() == frames.InterruptionLLMCompleteSilently {
+				// Let the response finish into the context without speaking it
+				g.log.Info("Completing ongoing stream silently")
+				if !g.current.silenced {
+					g.current.silenced = true
+					close(g.current.silencedCh)
+				}
+			} else {
+				g.log.Warn("Cancelling ongoing stream")
+				g.stopLocked()
+			}
+		}
+		g.mu.Unlock()
+		return true, g.proc.PushFrame(frame, direction)
+	}
+	return false, nil
+}
+
+// stopLocked cancels the current generation. g.mu must be held.
+func (g *StreamGuard) stopLocked() {
+	g.current.cancelled = true
+	g.current.cancel()
+	g.current = nil
+}
+
+// ContextReceived records that a new context arrived, so an interruption
+// right behind it is not taken as one for the response it starts
+func (g *StreamGuard) ContextReceived() {
+	g.mu.Lock()
+	g.lastContextAt = time.Now()
+	g.mu.Unlock()
+}
+
+// Begin starts tracking a new generation from llmCtx whose requests run
+// under a context derived from parent (context.Background if nil). Replies
+// of silenced responses completed since are recorded first.
+func (g *StreamGuard) Begin(parent context.Context, llmCtx *LLMContext) *Generation {
+	if parent == nil {
+		parent = context.Background()
+	}
+	gen := &Generation{guard: g, llmCtx: llmCtx, silencedCh: make(chan struct{})}
+	gen.ctx, gen.cancel = context.WithCancel(parent)
+
+	g.mu.Lock()
+	g.recordPendingLocked()
+	gen.replyAt = len(llmCtx.Messages)
+	g.current = gen
+	g.mu.Unlock()
+	return gen
+}
+
+// recordPendingLocked records the replies held by detached generations.
+// g.mu must be held.
+func (g *StreamGuard) recordPendingLocked() {
+	for _, gen := range g.pending {
+		gen.insertReply(*gen.reply)
+	}
+	g.pending = nil
+}
+
+// Generating reports whether a generation is in progress and has not been
+// cancelled
+func (g *StreamGuard) Generating() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.current != nil
+}
+
+// Context returns the context the generation's requests should run under;
+// it is cancelled when the generation is stopped or ends
+func (gen *Generation) Context() context.Context {
+	return gen.ctx
+}
+
+// Cancelled reports whether an interruption or cancel request stopped the
+// generation, as opposed to it failing or finishing on its own
+func (gen *Generation) Cancelled() bool {
+	gen.guard.mu.Lock()
+	defer gen.guard.mu.Unlock()
+	return gen.cancelled
+}
+
+// Push pushes a frame of the response downstream, unless an interruption has
+// silenced it. It reports whether the frame was pushed.
+func (gen *Generation) Push(frame frames.Frame) bool {
+	gen.guard.mu.Lock()
+	silenced := gen.silenced
+	gen.guard.mu.Unlock()
+	if silenced {
+		return false
+	}
+	gen.guard.proc.PushFrame(frame, frames.Downstream)
+	return true
+}
+
+// Run calls generate, which streams the generation's response, and ends the
+// generation when it returns. Once an interruption silences the response Run
+// returns nil without waiting for it: the rest streams in the background and
+// its reply is recorded when the service next calls Begin or Run.
+//
+// Run must be called from the goroutine that handles the service's data
+// frames, which is then the only one recording replies in the context.
+func (gen *Generation) Run(generate func() error) error {
+	g := gen.guard
+	done := make(chan error, 1)
+	go func() {
+		err := generate()
+		gen.End()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		g.mu.Lock()
+		g.recordPendingLocked()
+		g.mu.Unlock()
+		return err
+	case <-gen.silencedCh:
+	}
+
+	g.mu.Lock()
+	gen.detached = true
+	g.mu.Unlock()
+	go func() {
+		if err := <-done; err != nil && !gen.Cancelled() {
+			g.log.Warn("Silenced response failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// AddReply records the reply in the context at the position the request was
+// made from, ahead of anything added since (such as the user message that
+// interrupted a silenced response)
+func (gen *Generation) AddReply(msg LLMMessage) {
+	g := gen.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if gen.detached {
+		gen.reply = &msg
+		g.pending = append(g.pending, gen)
+		return
+	}
+	gen.insertReply(msg)
+}
+
+// insertReply inserts msg at replyAt, or at the end if the context has
+// since been shortened (e.g. summarized)
+func (gen *Generation) insertReply(msg LLMMessage) {
+	at := min(gen.replyAt, len(gen.llmCtx.Messages))
+	gen.llmCtx.Messages = slices.Insert(gen.llmCtx.Messages, at, msg)
+}
+
+// End stops tracking the generation and releases its context
+func (gen *Generation) End() {
+	g := gen.guard
+	g.mu.Lock()
+	if g.current == gen {
+		g.current = nil
+	}
+	g.mu.Unlock()
+	gen.cancel()
+}
+
+// PushResponseEnd ends the response, marking it silent if an interruption
+// let it complete without being spoken. An end also goes upstream so the user
+// aggregator learns generation is over when nothing will be spoken (a
+// tool-only, empty, failed or SkipTTS reply).
+func (gen *Generation) PushResponseEnd() {
+	end := frames.NewLLMFullResponseEndFrame()
+	gen.guard.mu.Lock()
+	if gen.silenced {
+		end.SetMetadata(frames.SilentResponseKey, true)
+	}
+	gen.guard.mu.Unlock()
+	gen.guard.proc.PushFrame(end, frames.Downstream)
+	gen.guard.proc.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Upstream)
+}
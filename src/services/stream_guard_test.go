@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+type guardCapturer struct {
+	mu     sync.Mutex
+	frames []frames.Frame
+}
+
+func (c *guardCapturer) QueueFrame(frame frames.Frame, _ frames.FrameDirection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frames = append(c.frames, frame)
+	return nil
+}
+func (c *guardCapturer) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+func (c *guardCapturer) PushFrame(frames.Frame, frames.FrameDirection) error { return nil }
+func (c *guardCapturer) Link(processors.FrameProcessor)                      {}
+func (c *guardCapturer) SetPrev(processors.FrameProcessor)                   {}
+func (c *guardCapturer) Start(context.Context) error                         { return nil }
+func (c *guardCapturer) Stop() error                                         { return nil }
+func (c *guardCapturer) Name() string                                        { return "TestCapturer" }
+
+// newTestGuard returns a guard over a processor that adopted policy, and the
+// capturer downstream of it
+func newTestGuard(t *testing.T, policy frames.InterruptionLLMPolicy) (*StreamGuard, *guardCapturer) {
+	t.Helper()
+	proc := processors.NewBaseProcessor("TestLLM", nil)
+	capturer := &guardCapturer{}
+	proc.Link(capturer)
+	start := frames.NewStartFrame()
+	start.InterruptionLLMPolicy = policy
+	if err := proc.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame(StartFrame) failed: %v", err)
+	}
+	capturer.frames = nil
+	return NewStreamGuard(proc, logger.WithPrefix("TestLLM")), capturer
+}
+
+func TestStreamGuardInterruptionCancels(t *testing.T) {
+	g, capturer := newTestGuard(t, frames.InterruptionLLMAbort)
+	gen := g.Begin(context.Background(), NewLLMContext(""))
+
+	handled, err := g.HandleFrame(frames.NewInterruptionFrame(), frames.Downstream)
+	if !handled || err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) = %v, %v", handled, err)
+	}
+	if gen.Context().Err() != context.Canceled || !gen.Cancelled() {
+		t.Error("expected the generation to be cancelled")
+	}
+	if g.Generating() {
+		t.Error("expected no generation in progress after cancelling")
+	}
+	if len(capturer.frames) != 1 {
+		t.Errorf("expected the InterruptionFrame pushed on, got %d frames", len(capturer.frames))
+	}
+}
+
+func TestStreamGuardCancelRequestConsumed(t *testing.T) {
+	g, capturer := newTestGuard(t, frames.InterruptionLLMAbort)
+	gen := g.Begin(context.Background(), NewLLMContext(""))
+
+	handled, _ := g.HandleFrame(frames.NewLLMCancelGenerationFrame(), frames.Upstream)
+	if !handled || !gen.Cancelled() {
+		t.Error("expected the cancel request to stop the generation")
+	}
+	if len(capturer.frames) != 0 {
+		t.Errorf("expected the cancel request consumed, got %d frames", len(capturer.frames))
+	}
+	if handled, _ := g.HandleFrame(frames.NewTextFrame("hi"), frames.Downstream); handled {
+		t.Error("expected other frames left to the service")
+	}
+}
+
+func TestStreamGuardIgnoresInterruptionForNewContext(t *testing.T) {
+	g, _ := newTestGuard(t, frames.InterruptionLLMAbort)
+	g.ContextReceived()
+	gen := g.Begin(context.Background(), NewLLMContext(""))
+	defer gen.End()
+
+	g.HandleFrame(frames.NewInterruptionFrame(), frames.Downstream)
+	if gen.Cancelled() || !g.Generating() {
+		t.Error("expected an interruption right behind a new context to be ignored")
+	}
+}
+
+func TestStreamGuardCompleteSilently(t *testing.T) {
+	g, capturer := newTestGuard(t, frames.InterruptionLLMCompleteSilently)
+	gen := g.Begin(context.Background(), NewLLMContext(""))
+
+	if !gen.Push(frames.NewLLMTextFrame("Hello")) {
+		t.Error("expected text pushed before the interruption")
+	}
+	g.HandleFrame(frames.NewInterruptionFrame(), frames.Downstream)
+	if gen.Context().Err() != nil {
+		t.Error("expected the generation to keep running silently")
+	}
+	if gen.Push(frames.NewLLMTextFrame(" world")) {
+		t.Error("expected text withheld after the interruption")
+	}
+	gen.End()
+	gen.PushResponseEnd()
+
+	capturer.mu.Lock()
+	defer capturer.mu.Unlock()
+	if len(capturer.frames) != 3 {
+		t.Fatalf("expected text, interruption and end, got %d frames", len(capturer.frames))
+	}
+	end, ok := capturer.frames[2].(*frames.LLMFullResponseEndFrame)
+	if !ok {
+		t.Fatalf("expected the response end last, got %T", capturer.frames[2])
+	}
+	if silent, _ := end.Metadata()[frames.SilentResponseKey].(bool); !silent {
+		t.Error("expected the response end marked silent")
+	}
+}
+
+func TestStreamGuardEndKeepsNewerGeneration(t *testing.T) {
+	g, _ := newTestGuard(t, frames.InterruptionLLMAbort)
+	old := g.Begin(context.Background(), NewLLMContext(""))
+	current := g.Begin(context.Background(), NewLLMContext(""))
+	defer current.End()
+
+	old.End()
+	if !g.Generating() {
+		t.Error("expected ending an older generation to leave the current one tracked")
+	}
+}
+
+func TestStreamGuardSilencedReplyKeepsItsPlace(t *testing.T) {
+	g, _ := newTestGuard(t, frames.InterruptionLLMCompleteSilently)
+	llmCtx := NewLLMContext("")
+	llmCtx.AddUserMessage("Hi")
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	gen := g.Begin(context.Background(), llmCtx)
+	ran := make(chan error, 1)
+	go func() {
+		ran <- gen.Run(func() error {
+			defer close(finished)
+			<-release
+			gen.AddReply(LLMMessage{Role: "assistant", Content: "Hello world"})
+			return nil
+		})
+	}()
+
+	g.HandleFrame(frames.NewInterruptionFrame(), frames.Downstream)
+	select {
+	case err := <-ran:
+		if err != nil {
+			t.Fatalf("Run returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to stop waiting for a silenced response")
+	}
+
+	llmCtx.AddUserMessage("Wait")
+	close(release)
+	<-finished
+	if len(llmCtx.Messages) != 2 {
+		t.Fatalf("expected the reply held until the next generation, got %+v", llmCtx.Messages)
+	}
+
+	next := g.Begin(context.Background(), llmCtx)
+	defer next.End()
+	var got []string
+	for _, msg := range llmCtx.Messages {
+		got = append(got, msg.Content)
+	}
+	if len(got) != 3 || got[0] != "Hi" || got[1] != "Hello world" || got[2] != "Wait" {
+		t.Errorf("context = %q, want the reply ahead of the interrupting turn", got)
+	}
+}
+
+func TestStreamGuardStartFrameRestoresAbort(t *testing.T) {
+	g, _ := newTestGuard(t, frames.InterruptionLLMCompleteSilently)
+	proc := g.proc
+
+	// A per-call StartFrame leaves the policy unset and keeps the adopted one
+	if err := proc.ProcessFrame(context.Background(), frames.NewStartFrame(), frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame(StartFrame) failed: %v", err)
+	}
+	if got := proc.InterruptionLLMPolicy(); got != frames.InterruptionLLMCompleteSilently {
+		t.Fatalf("expected the adopted policy to be kept, got %v", got)
+	}
+
+	start := frames.NewStartFrame()
+	start.InterruptionLLMPolicy = frames.InterruptionLLMAbort
+	if err := proc.ProcessFrame(context.Background(), start, frames.Downstream); err != nil {
+		t.Fatalf("ProcessFrame(StartFrame) failed: %v", err)
+	}
+	if got := proc.InterruptionLLMPolicy(); got != frames.InterruptionLLMAbort {
+		t.Errorf("expected an explicit abort to be adopted, got %v", got)
+	}
+}
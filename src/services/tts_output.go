@@ -0,0 +1,94 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// TTSOutputConverter resamples and re-encodes a TTS service's audio to the
+// format the transport expects (e.g. ElevenLabs pcm_24000 to 8kHz mulaw for
+// telephony), so the service can emit it directly instead of going through
+// an AudioConverterProcessor stage. A nil converter leaves frames untouched,
+// so services can leave it unset when no output format is configured.
+type TTSOutputConverter struct {
+	sampleRate int
+	codec      string
+
+	mu        sync.Mutex
+	converter *audio.AudioConverterProcessor
+	input     processors.AudioFormat
+}
+
+// NewTTSOutputConverter creates a converter to sampleRate and codec ("mulaw",
+// "alaw" or "linear16"). A zero sampleRate or empty codec keeps the
+// provider's. Returns nil (disabled) if both are unset.
+func NewTTSOutputConverter(sampleRate int, codec string) *TTSOutputConverter {
+	if sampleRate <= 0 && codec == "" {
+		return nil
+	}
+	return &TTSOutputConverter{sampleRate: sampleRate, codec: audio.NormalizeCodecName(codec)}
+}
+
+// Format returns the format audio a provider produces in native ends up in
+// once converted: the configured sample rate and codec replace native's
+func (c *TTSOutputConverter) Format(native processors.AudioFormat) processors.AudioFormat {
+	if c == nil {
+		return native
+	}
+	if c.sampleRate > 0 {
+		native.SampleRate = c.sampleRate
+	}
+	if c.codec != "" {
+		native.Codec = c.codec
+	}
+	return native
+}
+
+// Convert rewrites frame in place to the output format. The frame's codec
+// is read from its "codec" metadata (default linear16), so set that first.
+// Audio is assumed to be mono.
+func (c *TTSOutputConverter) Convert(frame *frames.TTSAudioFrame) error {
+	if c == nil {
+		return nil
+	}
+
+	codec := "linear16"
+	if v, ok := frame.Metadata()["codec"].(string); ok {
+		codec = audio.NormalizeCodecName(v)
+	}
+	in := processors.AudioFormat{SampleRate: frame.SampleRate, Codec: codec}
+	out := in
+	if c.sampleRate > 0 {
+		out.SampleRate = c.sampleRate
+	}
+	if c.codec != "" {
+		out.Codec = c.codec
+	}
+	if in == out {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.converter == nil || c.input != in {
+		c.converter = audio.NewAudioConverterProcessor(audio.AudioConverterConfig{
+			InputSampleRate:  in.SampleRate,
+			InputCodec:       in.Codec,
+			OutputSampleRate: out.SampleRate,
+			OutputCodec:      out.Codec,
+		})
+		c.input = in
+	}
+	data, err := c.converter.Convert(frame.Data, frame.SampleRate)
+	if err != nil {
+		return err
+	}
+
+	frame.Data = data
+	frame.SampleRate = out.SampleRate
+	frame.SetMetadata("codec", out.Codec)
+	return nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestTTSOutputConverter_24kLinear16To8kMulaw(t *testing.T) {
+	c := NewTTSOutputConverter(8000, "PCMU")
+
+	// 20ms of 24kHz linear16
+	frame := frames.NewTTSAudioFrame(make([]byte, 960), 24000, 1)
+	frame.SetMetadata("codec", "linear16")
+	frame.SetMetadata("context_id", "ctx-1")
+	if err := c.Convert(frame); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	if frame.SampleRate != 8000 || frame.Metadata()["codec"] != "mulaw" {
+		t.Fatalf("got %v %dHz, want mulaw 8000Hz", frame.Metadata()["codec"], frame.SampleRate)
+	}
+	if len(frame.Data) != 160 {
+		t.Errorf("expected 160 bytes (20ms of 8kHz mulaw), got %d", len(frame.Data))
+	}
+	if frame.Metadata()["context_id"] != "ctx-1" {
+		t.Error("expected other metadata to be kept")
+	}
+}
+
+func TestTTSOutputConverter_MatchingOrUnsetLeavesAudio(t *testing.T) {
+	if c := NewTTSOutputConverter(0, ""); c != nil {
+		t.Fatal("expected no converter without an output format")
+	}
+	var disabled *TTSOutputConverter
+	frame := frames.NewTTSAudioFrame([]byte{1, 2, 3, 4}, 24000, 1)
+	if err := disabled.Convert(frame); err != nil || len(frame.Data) != 4 {
+		t.Fatalf("expected a nil converter to leave the frame, got %d bytes, err %v", len(frame.Data), err)
+	}
+
+	// Rate only: the provider's codec is kept
+	c := NewTTSOutputConverter(16000, "")
+	frame = frames.NewTTSAudioFrame(make([]byte, 960), 24000, 1)
+	if err := c.Convert(frame); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if frame.SampleRate != 16000 || frame.Metadata()["codec"] != "linear16" || len(frame.Data) != 640 {
+		t.Errorf("got %v %dHz %d bytes, want linear16 16000Hz 640 bytes", frame.Metadata()["codec"], frame.SampleRate, len(frame.Data))
+	}
+}
@@ -17,8 +17,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
-	"time"
 
 	"cloud.google.com/go/auth/credentials"
 	"google.golang.org/genai"
@@ -41,10 +39,6 @@ const (
 
 	// logPrefix prefixes all log lines from this service.
 	logPrefix = "VertexGemini"
-
-	// staleInterruptWindow suppresses InterruptionFrames that arrive immediately
-	// after a fresh LLMContextFrame — those interruptions belong to the prior turn.
-	staleInterruptWindow = 100 * time.Millisecond
 )
 
 // LLMService is a Gemini LLM service backed by Vertex AI.
@@ -57,13 +51,12 @@ type LLMService struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 
-	// Request-scoped context for cancellable streaming (protected by streamMu)
-	requestCtx    context.Context
-	requestCancel context.CancelFunc
-	isGenerating  bool
-	lastContextAt time.Time
-	streamMu      sync.Mutex
-	log           *logger.Logger
+	// The response being streamed, stopped by interruptions
+	stream *services.StreamGuard
+	log    *logger.Logger
+
+	// Sampling overrides from an LLMParamsFrame for the next generation only
+	nextParams services.NextLLMParams
 }
 
 // LLMConfig configures a Vertex AI Gemini LLM service.
@@ -133,6 +126,8 @@ func NewLLMService(config LLMConfig) (*LLMService, error) {
 		log:         logger.WithPrefix(logPrefix),
 	}
 	s.BaseProcessor = processors.NewBaseProcessor(processorName, s)
+	s.AttachLogger(s.log)
+	s.stream = services.NewStreamGuard(s.BaseProcessor, s.log)
 	return s, nil
 }
 
@@ -163,46 +158,30 @@ func (s *LLMService) Cleanup() error {
 }
 
 func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
-	// InterruptionFrame: cancel in-flight stream unless a new context arrived
-	// <staleInterruptWindow ago (interruption belongs to the prior turn).
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
-		s.streamMu.Lock()
-		isGen := s.isGenerating
-		hasCancel := s.requestCancel != nil
-		timeSinceContext := time.Since(s.lastContextAt)
-		isNewContext := timeSinceContext < staleInterruptWindow
-
-		s.log.Debug("Interruption received (isGenerating=%v, hasCancel=%v, timeSinceContext=%v)", isGen, hasCancel, timeSinceContext)
-
-		if isNewContext {
-			s.log.Debug("Ignoring interruption - new context was just received (%v ago)", timeSinceContext)
-			s.streamMu.Unlock()
-			return s.PushFrame(frame, direction)
-		}
+	// Handle LLMParamsFrame - override sampling for the next generation
+	if params, ok := frame.(*frames.LLMParamsFrame); ok {
+		s.nextParams.Set(params)
+		return nil
+	}
 
-		if isGen && hasCancel {
-			s.log.Info("Cancelling ongoing stream")
-			s.requestCancel()
-			s.isGenerating = false
-		}
-		s.streamMu.Unlock()
-		return s.PushFrame(frame, direction)
+	// LLMCancelGenerationFrame and InterruptionFrame stop the in-flight stream.
+	if handled, err := s.stream.HandleFrame(frame, direction); handled {
+		return err
 	}
 
 	if contextFrame, ok := frame.(*frames.LLMContextFrame); ok {
 		if llmContext, ok := contextFrame.Context.(*services.LLMContext); ok {
 			s.log.Info("Received LLMContextFrame with %d messages", len(llmContext.Messages))
 
-			s.streamMu.Lock()
-			s.lastContextAt = time.Now()
-			s.streamMu.Unlock()
+			s.stream.ContextReceived()
 
 			s.context = llmContext
 
 			s.PushFrame(frames.NewLLMFullResponseStartFrame(), frames.Downstream)
 
-			if err := s.generateResponse(); err != nil {
-				if s.requestCtx != nil && s.requestCtx.Err() == context.Canceled {
+			gen := s.stream.Begin(s.ctx, llmContext)
+			if err := gen.Run(func() error { return s.generateResponse(gen) }); err != nil {
+				if gen.Cancelled() {
 					s.log.Info("Stream cancelled by interruption")
 				} else {
 					s.log.Error("Error generating response: %v", err)
@@ -210,7 +189,7 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 				}
 			}
 
-			s.PushFrame(frames.NewLLMFullResponseEndFrame(), frames.Downstream)
+			gen.PushResponseEnd()
 		}
 		return nil
 	}
@@ -218,27 +197,8 @@ func (s *LLMService) HandleFrame(ctx context.Context, frame frames.Frame, direct
 	return s.PushFrame(frame, direction)
 }
 
-func (s *LLMService) generateResponse() error {
-	parentCtx := s.ctx
-	if parentCtx == nil {
-		parentCtx = context.Background()
-	}
-
-	s.streamMu.Lock()
-	s.requestCtx, s.requestCancel = context.WithCancel(parentCtx)
-	s.isGenerating = true
-	s.streamMu.Unlock()
-
+func (s *LLMService) generateResponse(gen *services.Generation) error {
 	s.log.Info("Starting Vertex stream generation")
-	defer func() {
-		s.streamMu.Lock()
-		s.isGenerating = false
-		if s.requestCancel != nil {
-			s.requestCancel()
-		}
-		s.requestCancel = nil
-		s.streamMu.Unlock()
-	}()
 
 	contents := buildContents(s.context.Messages, s.log)
 
@@ -246,6 +206,17 @@ func (s *LLMService) generateResponse() error {
 	cfg := &genai.GenerateContentConfig{
 		Temperature: &temp,
 	}
+	params := s.nextParams.Take()
+	if params.Temperature != nil {
+		temp = float32(*params.Temperature)
+	}
+	if params.TopP != nil {
+		topP := float32(*params.TopP)
+		cfg.TopP = &topP
+	}
+	if params.MaxTokens != nil {
+		cfg.MaxOutputTokens = int32(*params.MaxTokens)
+	}
 	if s.context.SystemPrompt != "" {
 		// Role is ignored by the API for system instructions; omit for clarity.
 		cfg.SystemInstruction = &genai.Content{
@@ -254,10 +225,10 @@ func (s *LLMService) generateResponse() error {
 	}
 
 	var fullResponse strings.Builder
-	stream := s.client.Models.GenerateContentStream(s.requestCtx, s.model, contents, cfg)
+	stream := s.client.Models.GenerateContentStream(gen.Context(), s.model, contents, cfg)
 
 	for resp, err := range stream {
-		if s.requestCtx.Err() == context.Canceled {
+		if gen.Context().Err() == context.Canceled {
 			s.log.Info("Stream interrupted mid-generation (tokens so far: %d chars)", fullResponse.Len())
 			return nil
 		}
@@ -274,11 +245,11 @@ func (s *LLMService) generateResponse() error {
 		}
 
 		fullResponse.WriteString(text)
-		s.PushFrame(frames.NewLLMTextFrame(text), frames.Downstream)
+		gen.Push(frames.NewLLMTextFrame(text))
 	}
 
 	response := fullResponse.String()
-	s.context.AddAssistantMessage(response)
+	gen.AddReply(services.LLMMessage{Role: "assistant", Content: response})
 	s.log.Debug("Assistant response length: %d", len(response))
 
 	return nil
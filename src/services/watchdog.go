@@ -0,0 +1,104 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTTSStallTimeout is how long a TTS service waits for audio after
+// sending text before treating the provider as stalled
+const DefaultTTSStallTimeout = 5 * time.Second
+
+// StallWatchdog detects a streaming provider that accepted input but stopped
+// answering. Arm it when a request is sent, Feed it when a response chunk
+// arrives while requests are still outstanding, and Stop it once everything
+// sent has been answered; if no response comes for the timeout, onStall is
+// called from the timer goroutine. A nil watchdog is a no-op, so services can
+// leave it unset when stall detection is disabled.
+type StallWatchdog struct {
+	timeout time.Duration
+	onStall func()
+
+	mu    sync.Mutex
+	timer *time.Timer
+	gen   uint64 // Invalidates a timer that fired while being stopped
+}
+
+// NewStallWatchdog creates a watchdog calling onStall after timeout without
+// a response. Returns nil (disabled) if timeout is not positive.
+func NewStallWatchdog(timeout time.Duration, onStall func()) *StallWatchdog {
+	if timeout <= 0 {
+		return nil
+	}
+	return &StallWatchdog{timeout: timeout, onStall: onStall}
+}
+
+// Arm starts the timer unless it is already running, so the deadline is
+// measured from the oldest unanswered request
+func (w *StallWatchdog) Arm() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		return
+	}
+	w.startLocked()
+}
+
+// Feed records a response: the provider is alive, so a running timer starts
+// over. It does nothing when the watchdog isn't armed.
+func (w *StallWatchdog) Feed() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer == nil {
+		return
+	}
+	w.timer.Stop()
+	w.startLocked()
+}
+
+// startLocked starts a fresh timer. Caller must hold mu.
+func (w *StallWatchdog) startLocked() {
+	w.gen++
+	gen := w.gen
+	w.timer = time.AfterFunc(w.timeout, func() {
+		w.mu.Lock()
+		if gen != w.gen {
+			w.mu.Unlock()
+			return
+		}
+		w.timer = nil
+		w.mu.Unlock()
+		w.onStall()
+	})
+}
+
+// Stop disarms the timer, e.g. on interruption or when nothing sent is
+// outstanding any more
+func (w *StallWatchdog) Stop() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.gen++
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// Armed reports whether the watchdog is waiting for a response
+func (w *StallWatchdog) Armed() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.timer != nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStallWatchdogFiresWhenUnanswered(t *testing.T) {
+	stalled := make(chan struct{}, 1)
+	w := NewStallWatchdog(50*time.Millisecond, func() { stalled <- struct{}{} })
+
+	w.Arm()
+	select {
+	case <-stalled:
+	case <-time.After(time.Second):
+		t.Fatal("expected watchdog to fire without a response")
+	}
+	if w.Armed() {
+		t.Error("expected watchdog to disarm after firing")
+	}
+}
+
+func TestStallWatchdogFeedResets(t *testing.T) {
+	stalled := make(chan time.Time, 1)
+	w := NewStallWatchdog(50*time.Millisecond, func() { stalled <- time.Now() })
+
+	w.Arm()
+	time.Sleep(30 * time.Millisecond)
+	// Re-arming keeps the original deadline; feeding restarts it
+	w.Arm()
+	fed := time.Now()
+	w.Feed()
+	if !w.Armed() {
+		t.Fatal("expected watchdog to stay armed after Feed")
+	}
+
+	select {
+	case at := <-stalled:
+		if elapsed := at.Sub(fed); elapsed < 45*time.Millisecond {
+			t.Errorf("expected the deadline to restart on Feed, fired %v after it", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected watchdog to fire when responses stop")
+	}
+}
+
+func TestStallWatchdogStopDisarms(t *testing.T) {
+	var fired atomic.Int32
+	w := NewStallWatchdog(50*time.Millisecond, func() { fired.Add(1) })
+
+	w.Arm()
+	w.Feed()
+	w.Stop()
+	// Feeding a stopped watchdog doesn't arm it
+	w.Feed()
+	time.Sleep(80 * time.Millisecond)
+
+	if fired.Load() != 0 {
+		t.Errorf("expected no stall once everything was answered, fired %d times", fired.Load())
+	}
+	if w.Armed() {
+		t.Error("expected watchdog to be disarmed after Stop")
+	}
+}
+
+func TestStallWatchdogDisabled(t *testing.T) {
+	w := NewStallWatchdog(0, func() { t.Error("disabled watchdog fired") })
+	if w != nil {
+		t.Fatal("expected a nil watchdog for a zero timeout")
+	}
+	// Methods are safe on the nil watchdog
+	w.Arm()
+	w.Feed()
+	w.Stop()
+}
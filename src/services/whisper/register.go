@@ -0,0 +1,25 @@
+package whisper
+
+import (
+	"fmt"
+
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+func init() {
+	services.RegisterSTT("whisper", func(config services.ServiceConfig) (services.STTService, error) {
+		if config.String(services.ConfigAPIKey) == "" {
+			return nil, fmt.Errorf("%s is required", services.ConfigAPIKey)
+		}
+		language := config.String(services.ConfigLanguage)
+		if language == "" {
+			language = "en"
+		}
+		return NewWhisperSTTServiceWithConfig(WhisperSTTConfig{
+			APIKey:     config.String(services.ConfigAPIKey),
+			Model:      config.String(services.ConfigModel),
+			Language:   language,
+			SampleRate: config.Int(services.ConfigSampleRate),
+		}), nil
+	})
+}
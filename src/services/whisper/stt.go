@@ -168,6 +168,7 @@ func (s *WhisperSTTService) HandleFrame(ctx context.Context, frame frames.Frame,
 		// Discard accumulated audio on interruption
 		logger.Info("[WhisperSTT] Received InterruptionFrame, discarding audio buffer")
 		s.resetBuffer()
+		s.TraceInterruption(f.CorrelationID, frames.InterruptionStageSTTFinalized)
 		return s.PushFrame(frame, direction)
 
 	case *frames.AudioFrame:
@@ -36,7 +36,7 @@ func (p *OutputProcessor) HandleFrame(ctx context.Context, frame frames.Frame, d
 
 	if _, ok := frame.(*frames.EndFrame); ok {
 		p.clearTrack()
-		return nil
+		return p.PushFrame(frame, direction)
 	}
 
 	if ttsFrame, ok := frame.(*frames.TTSAudioFrame); ok {
@@ -0,0 +1,251 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/logger"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// LoopbackConfig configures a LoopbackTransport
+type LoopbackConfig struct {
+	// SampleRate and Channels of injected audio (default: 16000 Hz mono)
+	SampleRate int
+	Channels   int
+
+	// ChunkDuration is the length of each inbound AudioFrame (default: 20ms)
+	ChunkDuration time.Duration
+
+	// Realtime paces injected audio at playback speed instead of pushing it
+	// as fast as the pipeline accepts it
+	Realtime bool
+
+	// Echo feeds the bot's audio back into the input, like a speakerphone
+	Echo bool
+}
+
+// LoopbackTransport is an in-process transport for pipeline tests. Audio
+// injected with Speak or SpeakFile enters the pipeline as inbound
+// AudioFrames, and the bot's TTS audio is "played" into a buffer readable
+// with PlayedAudio. Paired with mock.MockSTT and mock.MockTTS it runs a
+// scripted conversation end to end without any provider:
+//
+//	transport := transports.NewLoopbackTransport(transports.LoopbackConfig{})
+//	pipe := pipeline.NewPipeline([]processors.FrameProcessor{
+//	    transport.Input(),
+//	    mock.NewMockSTT(mock.STTConfig{}),
+//	    userAgg,
+//	    llm,
+//	    mock.NewMockTTS(mock.TTSConfig{}),
+//	    transport.Output(),
+//	    assistantAgg,
+//	})
+//	...
+//	transport.Speak(ctx, mock.Speech("hello", mock.DefaultSampleRate))
+type LoopbackTransport struct {
+	config LoopbackConfig
+	input  *LoopbackInputProcessor
+	output *LoopbackOutputProcessor
+	log    *logger.Logger
+
+	mu     sync.Mutex
+	played bytes.Buffer
+}
+
+// NewLoopbackTransport creates a loopback transport
+func NewLoopbackTransport(config LoopbackConfig) *LoopbackTransport {
+	if config.SampleRate <= 0 {
+		config.SampleRate = 16000
+	}
+	if config.Channels <= 0 {
+		config.Channels = 1
+	}
+	if config.ChunkDuration <= 0 {
+		config.ChunkDuration = 20 * time.Millisecond
+	}
+
+	t := &LoopbackTransport{
+		config: config,
+		log:    logger.WithPrefix("LoopbackTransport"),
+	}
+	t.input = newLoopbackInputProcessor(t)
+	t.output = newLoopbackOutputProcessor(t)
+	return t
+}
+
+// Input returns the input processor
+func (t *LoopbackTransport) Input() processors.FrameProcessor {
+	return t.input
+}
+
+// Output returns the output processor
+func (t *LoopbackTransport) Output() processors.FrameProcessor {
+	return t.output
+}
+
+// Speak injects 16-bit PCM as inbound audio, split into ChunkDuration
+// frames. It waits for the pipeline to start and returns once all audio was
+// queued, or when ctx is done.
+func (t *LoopbackTransport) Speak(ctx context.Context, pcm []byte) error {
+	select {
+	case <-t.input.started:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	chunkSize := int(t.config.ChunkDuration.Seconds()*float64(t.config.SampleRate)) * t.config.Channels * 2
+	if chunkSize <= 0 {
+		chunkSize = 2 * t.config.Channels
+	}
+	for start := 0; start < len(pcm); start += chunkSize {
+		end := min(start+chunkSize, len(pcm))
+		chunk := make([]byte, end-start)
+		copy(chunk, pcm[start:end])
+		if err := t.input.pushAudio(chunk); err != nil {
+			return err
+		}
+
+		if t.config.Realtime {
+			select {
+			case <-time.After(t.config.ChunkDuration):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SpeakFile injects the audio of a WAV file, or of a file of raw 16-bit PCM
+// at the configured sample rate
+func (t *LoopbackTransport) SpeakFile(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	pcm, err := wavPCM(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return t.Speak(ctx, pcm)
+}
+
+// PlayedAudio returns the bot audio played so far
+func (t *LoopbackTransport) PlayedAudio() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return bytes.Clone(t.played.Bytes())
+}
+
+// ResetPlayedAudio discards the bot audio played so far
+func (t *LoopbackTransport) ResetPlayedAudio() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.played.Reset()
+}
+
+func (t *LoopbackTransport) play(pcm []byte) {
+	t.mu.Lock()
+	t.played.Write(pcm)
+	t.mu.Unlock()
+
+	if t.config.Echo {
+		if err := t.input.pushAudio(bytes.Clone(pcm)); err != nil {
+			t.log.Warn("Failed to echo audio: %v", err)
+		}
+	}
+}
+
+// wavPCM returns the sample data of a WAV file, or data itself when it has
+// no RIFF header
+func wavPCM(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return data, nil
+	}
+
+	// Walk the chunks after the RIFF header to find "data"
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+		if id == "data" {
+			return data[body:min(body+size, len(data))], nil
+		}
+		offset = body + size + size%2 // Chunks are word aligned
+	}
+	return nil, fmt.Errorf("WAV file has no data chunk")
+}
+
+// LoopbackInputProcessor pushes injected audio into the pipeline
+type LoopbackInputProcessor struct {
+	*processors.BaseProcessor
+	transport *LoopbackTransport
+
+	startOnce sync.Once
+	started   chan struct{}
+}
+
+func newLoopbackInputProcessor(transport *LoopbackTransport) *LoopbackInputProcessor {
+	p := &LoopbackInputProcessor{
+		transport: transport,
+		started:   make(chan struct{}),
+	}
+	p.BaseProcessor = processors.NewBaseProcessor("LoopbackInput", p)
+	return p
+}
+
+func (p *LoopbackInputProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if startFrame, ok := frame.(*frames.StartFrame); ok {
+		p.HandleStartFrame(startFrame)
+		defer p.startOnce.Do(func() { close(p.started) })
+	}
+	return p.PushFrame(frame, direction)
+}
+
+func (p *LoopbackInputProcessor) pushAudio(pcm []byte) error {
+	audio := frames.NewAudioFrame(pcm, p.transport.config.SampleRate, p.transport.config.Channels)
+	audio.SetMetadata("codec", "linear16")
+	return p.PushFrame(audio, frames.Downstream)
+}
+
+// LoopbackOutputProcessor plays bot audio into the transport's buffer and
+// reports bot speech like a real output transport
+type LoopbackOutputProcessor struct {
+	*processors.BaseProcessor
+	transport *LoopbackTransport
+	speaking  atomic.Bool // Set from audio, cleared by interruptions too
+}
+
+func newLoopbackOutputProcessor(transport *LoopbackTransport) *LoopbackOutputProcessor {
+	p := &LoopbackOutputProcessor{transport: transport}
+	p.BaseProcessor = processors.NewBaseProcessor("LoopbackOutput", p)
+	return p
+}
+
+func (p *LoopbackOutputProcessor) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch f := frame.(type) {
+	case *frames.TTSAudioFrame:
+		if p.speaking.CompareAndSwap(false, true) {
+			p.PushFrame(frames.NewBotStartedSpeakingFrame(), frames.Upstream)
+		}
+		p.transport.play(f.Data)
+		return nil
+	case *frames.TransferFrame:
+		p.transport.log.Warn("Loopback transport can't transfer calls, ignoring transfer to %s", f.Destination)
+	case *frames.TTSStoppedFrame, *frames.InterruptionFrame:
+		if p.speaking.CompareAndSwap(true, false) {
+			p.PushFrame(frames.NewBotStoppedSpeakingFrame(), frames.Upstream)
+		}
+	}
+	return p.PushFrame(frame, direction)
+}
@@ -0,0 +1,136 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/services/mock"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// runLoopbackPipeline runs transport -> MockSTT -> user aggregator -> echo
+// LLM -> MockTTS -> transport until the test ends
+func runLoopbackPipeline(t *testing.T, transport *LoopbackTransport, llmCtx *services.LLMContext) context.Context {
+	t.Helper()
+	pipe := pipeline.NewPipeline([]processors.FrameProcessor{
+		transport.Input(),
+		mock.NewMockSTT(mock.STTConfig{}),
+		aggregators.NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{}),
+		newEchoLLM(),
+		mock.NewMockTTS(mock.TTSConfig{}),
+		transport.Output(),
+		aggregators.NewLLMAssistantAggregator(llmCtx, nil),
+	})
+	task := pipeline.NewPipelineTask(pipe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-runDone:
+		case <-time.After(2 * time.Second):
+			t.Error("timed out waiting for the pipeline to stop")
+		}
+	})
+	return ctx
+}
+
+func waitForPlayedText(t *testing.T, transport *LoopbackTransport, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := mock.DecodeAudio(transport.PlayedAudio())
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the bot to say %q, played %q", want, got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoopbackScriptedTurn(t *testing.T) {
+	transport := NewLoopbackTransport(LoopbackConfig{})
+	llmCtx := services.NewLLMContext("You are a helpful assistant.")
+	ctx := runLoopbackPipeline(t, transport, llmCtx)
+
+	if err := transport.Speak(ctx, mock.Speech("hello", mock.DefaultSampleRate)); err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	waitForPlayedText(t, transport, "You said: hello")
+
+	// A second turn continues the conversation
+	transport.ResetPlayedAudio()
+	if err := transport.Speak(ctx, mock.Speech("goodbye", mock.DefaultSampleRate)); err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	waitForPlayedText(t, transport, "You said: goodbye")
+}
+
+func TestLoopbackSpeakFileWAV(t *testing.T) {
+	transport := NewLoopbackTransport(LoopbackConfig{})
+	ctx := runLoopbackPipeline(t, transport, services.NewLLMContext(""))
+
+	pcm := mock.Speech("from a file", mock.DefaultSampleRate)
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(36+len(pcm)))
+	wav.WriteString("WAVEfmt ")
+	for _, field := range []any{
+		uint32(16), uint16(1), uint16(1), uint32(mock.DefaultSampleRate),
+		uint32(mock.DefaultSampleRate * 2), uint16(2), uint16(16),
+	} {
+		binary.Write(&wav, binary.LittleEndian, field)
+	}
+	wav.WriteString("data")
+	binary.Write(&wav, binary.LittleEndian, uint32(len(pcm)))
+	wav.Write(pcm)
+
+	path := filepath.Join(t.TempDir(), "turn.wav")
+	if err := os.WriteFile(path, wav.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := transport.SpeakFile(ctx, path); err != nil {
+		t.Fatalf("SpeakFile failed: %v", err)
+	}
+	waitForPlayedText(t, transport, "You said: from a file")
+}
+
+func TestLoopbackOutputInterruptedWhilePlaying(t *testing.T) {
+	transport := NewLoopbackTransport(LoopbackConfig{})
+	output := transport.output
+	ctx := context.Background()
+
+	// Interruptions arrive on the system frame path while audio plays
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			output.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		output.HandleFrame(ctx, frames.NewTTSAudioFrame(make([]byte, 64), 16000, 1), frames.Downstream)
+	}
+	<-done
+
+	output.HandleFrame(ctx, frames.NewTTSStoppedFrame(), frames.Downstream)
+	if output.speaking.Load() {
+		t.Error("expected the bot to be reported quiet after TTS stopped")
+	}
+}
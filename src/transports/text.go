@@ -0,0 +1,25 @@
+package transports
+
+import (
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+// NewTextTransport creates a WebSocket transport for text-only chat clients.
+// Inbound chat messages become final TranscriptionFrames and LLM replies are
+// sent back as JSON text (see serializers.TextFrameSerializer), so a pipeline
+// without STT or TTS can serve a web chat widget:
+//
+//	transport := transports.NewTextTransport(transports.WebSocketConfig{Port: 8080, Path: "/chat"})
+//	pipe := pipeline.NewPipeline([]processors.FrameProcessor{
+//	    transport.Input(),
+//	    userAgg,
+//	    llm,
+//	    transport.Output(),
+//	    assistantAgg,
+//	})
+//
+// Any Serializer in config is replaced.
+func NewTextTransport(config WebSocketConfig) *WebSocketTransport {
+	config.Serializer = serializers.NewTextFrameSerializer()
+	return NewWebSocketTransport(config)
+}
@@ -0,0 +1,124 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// echoLLM answers each context with a streamed reply quoting the last message
+type echoLLM struct {
+	*processors.BaseProcessor
+}
+
+func newEchoLLM() *echoLLM {
+	l := &echoLLM{}
+	l.BaseProcessor = processors.NewBaseProcessor("EchoLLM", l)
+	return l
+}
+
+func (l *echoLLM) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	contextFrame, ok := frame.(*frames.LLMContextFrame)
+	if !ok {
+		return l.PushFrame(frame, direction)
+	}
+	llmCtx := contextFrame.Context.(*services.LLMContext)
+	last := llmCtx.Messages[len(llmCtx.Messages)-1].Content
+
+	for _, f := range []frames.Frame{
+		frames.NewLLMFullResponseStartFrame(),
+		frames.NewLLMTextFrame("You said: "),
+		frames.NewLLMTextFrame(last),
+		frames.NewLLMFullResponseEndFrame(),
+	} {
+		if err := l.PushFrame(f, frames.Downstream); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTextTransportRoundTrip(t *testing.T) {
+	transport := NewTextTransport(WebSocketConfig{})
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	defer server.Close()
+
+	llmCtx := services.NewLLMContext("You are a helpful assistant.")
+	pipe := pipeline.NewPipeline([]processors.FrameProcessor{
+		transport.Input(),
+		aggregators.NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{}),
+		newEchoLLM(),
+		transport.Output(),
+		aggregators.NewLLMAssistantAggregator(llmCtx, nil),
+	})
+	task := pipeline.NewPipelineTask(pipe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(ctx)
+	}()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	// Give the pipeline a moment to process its StartFrame
+	time.Sleep(50 * time.Millisecond)
+	if err := client.WriteMessage(websocket.TextMessage, []byte(`{"type":"message","text":"hello"}`)); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	want := []string{
+		`{"type":"text","text":"You said: "}`,
+		`{"type":"text","text":"hello"}`,
+		`{"type":"message","role":"assistant","text":"You said: hello"}`,
+	}
+	for _, expected := range want {
+		msgType, msg := readTestMessage(t, client)
+		if msgType != websocket.TextMessage {
+			t.Errorf("Expected TEXT message, got type %d", msgType)
+		}
+		if msg != expected {
+			t.Fatalf("Expected %s, got %s", expected, msg)
+		}
+	}
+
+	// The reply reaches the assistant aggregator after the output
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		messages := llmCtx.GetMessages(false)
+		if n := len(messages); n >= 2 && messages[n-1].Role == "assistant" {
+			// The aggregator joins chunks with spaces; compare the words
+			if got := strings.Join(strings.Fields(messages[n-1].Content), " "); got != "You said: hello" {
+				t.Errorf("Expected assistant reply in context, got %q", messages[n-1].Content)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the assistant reply to be added to the context, got %+v", messages)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-runDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the pipeline to stop")
+	}
+}
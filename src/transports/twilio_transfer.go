@@ -0,0 +1,78 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTwilioAPIBaseURL is the Twilio REST API used for call transfers
+const DefaultTwilioAPIBaseURL = "https://api.twilio.com"
+
+// TwilioTransferConfig configures NewTwilioTransferHandler
+type TwilioTransferConfig struct {
+	AuthToken  string       // Account auth token; the account and call SIDs come from the start event
+	APIBaseURL string       // REST API base URL override, e.g. a regional edge (default: DefaultTwilioAPIBaseURL)
+	HTTPClient *http.Client // Client for the REST call (default: 10s timeout)
+}
+
+// NewTwilioTransferHandler returns a TransferHandler that redirects the call
+// with <Dial> TwiML through Twilio's REST API, which ends the media stream.
+// Register it with WebSocketTransport.OnTransfer.
+func NewTwilioTransferHandler(config TwilioTransferConfig) TransferHandler {
+	baseURL := strings.TrimRight(config.APIBaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultTwilioAPIBaseURL
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return func(ctx context.Context, destination string, meta map[string]interface{}) error {
+		if config.AuthToken == "" {
+			return fmt.Errorf("twilio transfer requires an auth token")
+		}
+		accountSid, _ := meta["accountSid"].(string)
+		callSid, _ := meta["callSid"].(string)
+		if accountSid == "" || callSid == "" {
+			return fmt.Errorf("twilio transfer before the start event: no account or call SID")
+		}
+
+		form := url.Values{"Twiml": {transferTwiML(destination)}}
+		endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Calls/%s.json", baseURL, accountSid, callSid)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to create Twilio transfer request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(accountSid, config.AuthToken)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("twilio transfer request failed: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("twilio transfer failed: %s: %s", resp.Status, body)
+		}
+		return nil
+	}
+}
+
+// transferTwiML dials destination, a phone number or SIP URI
+func transferTwiML(destination string) string {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(destination))
+	if strings.HasPrefix(destination, "sip:") || strings.HasPrefix(destination, "sips:") {
+		return "<Response><Dial><Sip>" + escaped.String() + "</Sip></Dial></Response>"
+	}
+	return "<Response><Dial>" + escaped.String() + "</Dial></Response>"
+}
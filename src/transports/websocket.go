@@ -2,7 +2,9 @@ package transports
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -10,10 +12,12 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/audio"
 	"github.com/square-key-labs/strawgo-ai/src/frames"
 	"github.com/square-key-labs/strawgo-ai/src/logger"
 	"github.com/square-key-labs/strawgo-ai/src/processors"
 	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/services"
 )
 
 // WebSocketTransport is a generic WebSocket transport that uses
@@ -30,10 +34,39 @@ type WebSocketTransport struct {
 	upgrader           websocket.Upgrader
 	conns              map[string]*wsConnection
 	connMu             sync.RWMutex
+	maxConnections     int
+	pendingConns       int // Upgrades admitted but not yet in conns (protected by connMu)
+	burstChunks        int
+	pausedBufferChunks int
+	maxChunkAge        time.Duration
+	framesPerWrite     int
+	writeTimeout       time.Duration
+	pingInterval       time.Duration
+	pongTimeout        time.Duration
+	fadeDuration       time.Duration
+	maxQueuedAudio     time.Duration
+	queueFullTimeout   time.Duration
+	silenceThreshold   float64
+	resumeWindow       time.Duration
+	sessionTokenKey    string
+
+	// Session resumption: the connection owning the session, its token and,
+	// while a dropped connection awaits its reconnect, the timer that resets
+	// the session
+	sessionMu    sync.Mutex
+	sessionConn  *wsConnection
+	sessionToken string
+	resumeTimer  *time.Timer
 
 	// playbackKind: transport-declared playback classification. Defaults to
 	// PlaybackNetworkBlind; set via SetPlaybackKind for local audio sinks.
 	playbackKind atomic.Int32
+
+	// Connection lifecycle callbacks, set via OnConnect/OnDisconnect
+	handlerMu    sync.RWMutex
+	onConnect    func(meta map[string]interface{})
+	onDisconnect func(meta map[string]interface{})
+	onTransfer   TransferHandler
 }
 
 type wsConnection struct {
@@ -42,6 +75,15 @@ type wsConnection struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	writeMu sync.Mutex // Protect concurrent writes to WebSocket
+
+	// Lifecycle metadata, only touched by the connection's read loop
+	remoteAddr  string
+	connectedAt time.Time
+	callIDs     map[string]string // Call identifiers adopted from the StartFrame
+
+	sessionToken string // Token presented for session resumption
+	claimed      bool   // Session token (or its absence) settled
+	resumed      bool   // Connection reattached to a dropped session
 }
 
 // WebSocketConfig holds configuration for the WebSocket transport
@@ -50,8 +92,48 @@ type WebSocketConfig struct {
 	Path               string                      // WebSocket path (e.g., "/ws")
 	Serializer         serializers.FrameSerializer // Protocol serializer (Twilio, Asterisk, etc.)
 	PlaybackAckTimeout time.Duration               // Fallback timeout when playout ack is expected but never arrives
+	MaxConnections     int                         // Reject upgrades with 503 beyond this many active connections (default: 0 = unlimited)
+	BurstChunks        int                         // Send the first N chunks of each utterance unpaced to prime the client's jitter buffer (default: 0)
+	PausedBufferChunks int                         // Max chunks buffered while the client has paused sending (XOFF); newer audio is dropped (default: 500)
+	MaxChunkAge        time.Duration               // Drop chunks that would go out more than this far behind their playout slot (default: 0 = never drop)
+	FramesPerWrite     int                         // Coalesce up to this many paced chunks into one WebSocket write when the serializer supports it (default: 1)
+	WriteTimeout       time.Duration               // Deadline for each write; a peer that stops reading fails the write and the connection is closed (default: 10s)
+	PingInterval       time.Duration               // Send a WebSocket ping this often to detect half-open connections (default: 0 = no pings)
+	PongTimeout        time.Duration               // Close the connection if neither a pong nor any message arrives within this window (default: 2x PingInterval)
+	FadeDuration       time.Duration               // Fade each utterance's first chunk in and end interrupted audio with a fade-out, avoiding clicks (default: 5ms; negative disables)
+	MaxQueuedAudio     time.Duration               // Cap on audio queued ahead of the sender; TTS audio blocks until it drains below (default: 0 = bounded only by the 1000-chunk queue)
+	QueueFullTimeout   time.Duration               // How long TTS audio blocks at MaxQueuedAudio before the oldest queued audio is dropped to make room (default: 2s)
+	SilenceThreshold   float64                     // Drop TTS audio frames whose RMS (0-1) is below this before queuing, e.g. 0.001 for -60 dBFS (default: 0 = keep silent audio)
+
+	// SessionResumeWindow keeps the session of a dropped connection alive this
+	// long; a new connection presenting the same session token within it
+	// resumes the pipeline and its LLM context instead of starting fresh.
+	// Once the window expires, or another connection takes over, the
+	// conversation is cleared and the pipeline serves the next connection as
+	// a new session (default: 0 = end the session on disconnect)
+	SessionResumeWindow time.Duration
+	SessionTokenKey     string // Query parameter or StartFrame custom parameter carrying the token (default: "session_token")
 }
 
+// DefaultSessionTokenKey names the session token in the upgrade query string
+// or the StartFrame's custom parameters
+const DefaultSessionTokenKey = "session_token"
+
+// DefaultWriteTimeout bounds a single WebSocket write
+const DefaultWriteTimeout = 10 * time.Second
+
+// DefaultQueueFullTimeout bounds how long TTS audio waits for room under
+// MaxQueuedAudio before older audio is dropped
+const DefaultQueueFullTimeout = 2 * time.Second
+
+// DefaultPausedBufferChunks is ~10s of 20ms chunks
+const DefaultPausedBufferChunks = 500
+
+// TwilioBusyTwiML tells Twilio to reject the call as busy. Twilio ignores
+// the body of a rejected Stream handshake, so return it from the voice
+// webhook instead of <Connect><Stream> when AtCapacity reports true.
+const TwilioBusyTwiML = `<?xml version="1.0" encoding="UTF-8"?><Response><Reject reason="busy"/></Response>`
+
 // NewWebSocketTransport creates a new generic WebSocket transport
 func NewWebSocketTransport(config WebSocketConfig) *WebSocketTransport {
 	if config.Path == "" {
@@ -63,6 +145,27 @@ func NewWebSocketTransport(config WebSocketConfig) *WebSocketTransport {
 	if config.PlaybackAckTimeout <= 0 {
 		config.PlaybackAckTimeout = 3 * time.Second
 	}
+	if config.PausedBufferChunks <= 0 {
+		config.PausedBufferChunks = DefaultPausedBufferChunks
+	}
+	if config.FramesPerWrite <= 0 {
+		config.FramesPerWrite = 1
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+	if config.PingInterval > 0 && config.PongTimeout <= 0 {
+		config.PongTimeout = 2 * config.PingInterval
+	}
+	if config.FadeDuration == 0 {
+		config.FadeDuration = audio.DefaultFadeDuration
+	}
+	if config.QueueFullTimeout <= 0 {
+		config.QueueFullTimeout = DefaultQueueFullTimeout
+	}
+	if config.SessionTokenKey == "" {
+		config.SessionTokenKey = DefaultSessionTokenKey
+	}
 
 	t := &WebSocketTransport{
 		port:               config.Port,
@@ -71,6 +174,20 @@ func NewWebSocketTransport(config WebSocketConfig) *WebSocketTransport {
 		serializer:         config.Serializer,
 		playbackAckTimeout: config.PlaybackAckTimeout,
 		conns:              make(map[string]*wsConnection),
+		maxConnections:     config.MaxConnections,
+		burstChunks:        config.BurstChunks,
+		pausedBufferChunks: config.PausedBufferChunks,
+		maxChunkAge:        config.MaxChunkAge,
+		framesPerWrite:     config.FramesPerWrite,
+		writeTimeout:       config.WriteTimeout,
+		pingInterval:       config.PingInterval,
+		pongTimeout:        config.PongTimeout,
+		fadeDuration:       config.FadeDuration,
+		maxQueuedAudio:     config.MaxQueuedAudio,
+		queueFullTimeout:   config.QueueFullTimeout,
+		silenceThreshold:   config.SilenceThreshold,
+		resumeWindow:       config.SessionResumeWindow,
+		sessionTokenKey:    config.SessionTokenKey,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins (configure based on security needs)
@@ -131,6 +248,84 @@ func (t *WebSocketTransport) SetDrainPad(d time.Duration) {
 	t.outputProc.SetDrainPad(d)
 }
 
+// AudioStats forwards to the output processor.
+func (t *WebSocketTransport) AudioStats() AudioSendStats {
+	return t.outputProc.AudioStats()
+}
+
+// OnConnect registers a callback fired when a client connection is
+// upgraded, e.g. to start billing or call logging. meta holds "conn_id",
+// "remote_addr" and "connected_at" (time.Time). The callback runs on the
+// connection's goroutine, so it should return quickly.
+func (t *WebSocketTransport) OnConnect(handler func(meta map[string]interface{})) {
+	t.handlerMu.Lock()
+	defer t.handlerMu.Unlock()
+	t.onConnect = handler
+}
+
+// OnDisconnect registers a callback fired when a client connection closes.
+// meta holds the OnConnect fields plus "disconnected_at" (time.Time),
+// "duration" (time.Duration) and any call identifiers seen on the
+// connection ("streamSid", "callSid", "channelID").
+func (t *WebSocketTransport) OnDisconnect(handler func(meta map[string]interface{})) {
+	t.handlerMu.Lock()
+	defer t.handlerMu.Unlock()
+	t.onDisconnect = handler
+}
+
+// TransferHandler carries out a call transfer to destination, e.g. through
+// the telephony provider's REST API or the Asterisk ARI app controlling the
+// channel. meta holds the call identifiers the serializer learned
+// ("streamSid", "callSid", "accountSid", "channelID").
+type TransferHandler func(ctx context.Context, destination string, meta map[string]interface{}) error
+
+// OnTransfer registers the handler that performs transfers requested by a
+// TransferFrame. The output stops the bot's audio, sends the serializer's
+// in-band signaling, then runs the handler on its own goroutine; a failure
+// is reported upstream as an ErrorFrame. Without a handler TransferFrames are
+// ignored with a warning.
+func (t *WebSocketTransport) OnTransfer(handler TransferHandler) {
+	t.handlerMu.Lock()
+	defer t.handlerMu.Unlock()
+	t.onTransfer = handler
+}
+
+// connectionMetadata builds the metadata passed to lifecycle callbacks.
+// Call identifiers come from the StartFrame, or from serializers that learn
+// them from their own control messages (e.g. Asterisk's MEDIA_START).
+func (t *WebSocketTransport) connectionMetadata(wsConn *wsConnection) map[string]interface{} {
+	meta := t.callMetadata()
+	meta["conn_id"] = wsConn.id
+	meta["remote_addr"] = wsConn.remoteAddr
+	meta["connected_at"] = wsConn.connectedAt
+	for key, value := range wsConn.callIDs {
+		meta[key] = value
+	}
+	if wsConn.sessionToken != "" {
+		meta["session_token"] = wsConn.sessionToken
+		meta["resumed"] = wsConn.resumed
+	}
+	return meta
+}
+
+// callMetadata returns the call identifiers the serializer has learned
+func (t *WebSocketTransport) callMetadata() map[string]interface{} {
+	meta := map[string]interface{}{}
+	if s, ok := t.serializer.(interface{ GetStreamSid() string }); ok && s.GetStreamSid() != "" {
+		meta["streamSid"] = s.GetStreamSid()
+	}
+	if s, ok := t.serializer.(interface{ GetCallSid() string }); ok && s.GetCallSid() != "" {
+		meta["callSid"] = s.GetCallSid()
+	}
+	if s, ok := t.serializer.(interface{ GetAccountSid() string }); ok && s.GetAccountSid() != "" {
+		meta["accountSid"] = s.GetAccountSid()
+	}
+	if s, ok := t.serializer.(interface{ GetChannelID() string }); ok && s.GetChannelID() != "" {
+		meta["channelID"] = s.GetChannelID()
+	}
+	return meta
+}
+
 // Start begins listening for WebSocket connections
 func (t *WebSocketTransport) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
@@ -156,10 +351,65 @@ func (t *WebSocketTransport) Start(ctx context.Context) error {
 	return nil
 }
 
+// reserveConnection claims a connection slot, returning false if
+// MaxConnections has been reached
+func (t *WebSocketTransport) reserveConnection() bool {
+	t.connMu.Lock()
+	defer t.connMu.Unlock()
+
+	if t.maxConnections > 0 && len(t.conns)+t.pendingConns >= t.maxConnections {
+		return false
+	}
+	t.pendingConns++
+	return true
+}
+
+// AtCapacity reports whether MaxConnections has been reached, so a call can
+// be turned away before its stream is opened (see TwilioBusyTwiML)
+func (t *WebSocketTransport) AtCapacity() bool {
+	t.connMu.RLock()
+	defer t.connMu.RUnlock()
+	return t.maxConnections > 0 && len(t.conns)+t.pendingConns >= t.maxConnections
+}
+
+// rejectConnection answers an upgrade request with 503 Service Unavailable
+func (t *WebSocketTransport) rejectConnection(w http.ResponseWriter) {
+	t.log.Warn("Rejecting connection: max connections (%d) reached", t.maxConnections)
+	http.Error(w, "too many connections", http.StatusServiceUnavailable)
+}
+
+// inboundData converts a received message for Deserialize as the serializer
+// prefers: by WebSocket frame type (BINARY as []byte, TEXT as string) unless
+// it declares that every message is text or binary
+func (t *WebSocketTransport) inboundData(msgType int, msgBytes []byte) interface{} {
+	preferred := serializers.MessageTypeAuto
+	if s, ok := t.serializer.(serializers.MessageTypeSerializer); ok {
+		preferred = s.PreferredMessageType()
+	}
+	switch preferred {
+	case serializers.MessageTypeText:
+		return string(msgBytes)
+	case serializers.MessageTypeBinary:
+		return msgBytes
+	}
+	if msgType == websocket.BinaryMessage {
+		return msgBytes
+	}
+	return string(msgBytes)
+}
+
 // handleWebSocket upgrades HTTP connections to WebSocket
 func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !t.reserveConnection() {
+		t.rejectConnection(w)
+		return
+	}
+
 	conn, err := t.upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		t.connMu.Lock()
+		t.pendingConns--
+		t.connMu.Unlock()
 		t.log.Warn("WebSocket upgrade error: %v", err)
 		return
 	}
@@ -169,13 +419,20 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 	connID := fmt.Sprintf("ws-%p", conn)
 
 	wsConn := &wsConnection{
-		id:     connID,
-		conn:   conn,
-		ctx:    ctx,
-		cancel: cancel,
+		id:          connID,
+		conn:        conn,
+		ctx:         ctx,
+		cancel:      cancel,
+		remoteAddr:  r.RemoteAddr,
+		connectedAt: time.Now(),
+		callIDs:     make(map[string]string),
+	}
+	if token := r.URL.Query().Get(t.sessionTokenKey); token != "" {
+		t.adoptSessionToken(wsConn, token)
 	}
 
 	t.connMu.Lock()
+	t.pendingConns--
 	t.conns[connID] = wsConn
 	t.connMu.Unlock()
 
@@ -185,13 +442,39 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 		t.connMu.Unlock()
 		cancel()
 		conn.Close()
+
+		t.handlerMu.RLock()
+		onDisconnect := t.onDisconnect
+		t.handlerMu.RUnlock()
+		if onDisconnect != nil {
+			meta := t.connectionMetadata(wsConn)
+			disconnectedAt := time.Now()
+			meta["disconnected_at"] = disconnectedAt
+			meta["duration"] = disconnectedAt.Sub(wsConn.connectedAt)
+			onDisconnect(meta)
+		}
 	}()
 
+	t.setLogContext(map[string]string{"conn": connID})
 	t.log.Info("Connection established: %s", connID)
 
-	// Emit ClientConnectedFrame to notify downstream services
-	if err := t.inputProc.pushFrame(frames.NewClientConnectedFrame()); err != nil {
-		t.log.Error("Error pushing ClientConnectedFrame: %v", err)
+	if t.pingInterval > 0 {
+		t.startKeepalive(wsConn)
+	}
+
+	t.handlerMu.RLock()
+	onConnect := t.onConnect
+	t.handlerMu.RUnlock()
+	if onConnect != nil {
+		onConnect(t.connectionMetadata(wsConn))
+	}
+
+	// Emit ClientConnectedFrame to notify downstream services; a resumed
+	// session is already connected as far as the pipeline is concerned
+	if !wsConn.resumed {
+		if err := t.inputProc.pushFrame(frames.NewClientConnectedFrame()); err != nil {
+			t.log.Error("Error pushing ClientConnectedFrame: %v", err)
+		}
 	}
 
 	// Handle incoming messages
@@ -203,28 +486,33 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 			var data interface{}
 			var err error
 
-			// Read message and check ACTUAL WebSocket frame type (not serializer type)
-			// This supports hybrid protocols like Asterisk (BINARY for audio, TEXT for control)
+			// Read message; the WebSocket frame type is kept for serializers of
+			// hybrid protocols like Asterisk (BINARY for audio, TEXT for control)
 			msgType, msgBytes, readErr := conn.ReadMessage()
 			if readErr != nil {
-				if websocket.IsUnexpectedCloseError(readErr, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				var netErr net.Error
+				if errors.As(readErr, &netErr) && netErr.Timeout() {
+					t.log.Warn("No pong or message within %v, closing half-open connection", t.pongTimeout)
+				} else if websocket.IsUnexpectedCloseError(readErr, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					t.log.Warn("WebSocket read error: %v", readErr)
 				}
-				// Push EndFrame to notify downstream services to cleanup
-				if err := t.inputProc.pushFrame(frames.NewEndFrame()); err != nil {
-					t.log.Error("Error pushing end frame: %v", err)
+				// Push EndFrame to notify downstream services to cleanup,
+				// unless the session is held for the client to reconnect
+				if !t.holdSession(wsConn) {
+					if err := t.inputProc.pushFrame(frames.NewEndFrame()); err != nil {
+						t.log.Error("Error pushing end frame: %v", err)
+					}
 				}
 				return
 			}
 
-			// Convert based on WebSocket message type
-			if msgType == websocket.BinaryMessage {
-				data = msgBytes
-			} else {
-				// TEXT message
-				data = string(msgBytes)
+			// Any message shows the peer is alive
+			if t.pongTimeout > 0 {
+				conn.SetReadDeadline(time.Now().Add(t.pongTimeout))
 			}
 
+			data = t.inboundData(msgType, msgBytes)
+
 			// Deserialize using the protocol-specific serializer
 			frame, err := t.serializer.Deserialize(data)
 			if err != nil {
@@ -237,6 +525,12 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 
+			// Without a token in the query string it may still arrive in the
+			// StartFrame; any other first frame takes over without one
+			if _, ok := frame.(*frames.StartFrame); !ok {
+				t.adoptSessionToken(wsConn, "")
+			}
+
 			// Handle different frame types
 			switch f := frame.(type) {
 			case *frames.AudioFrame:
@@ -246,6 +540,23 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 				}
 
 			case *frames.StartFrame:
+				// Tag logs with the call identifiers; downstream processors
+				// adopt them from the StartFrame metadata
+				fields := map[string]string{"conn": connID}
+				for _, key := range processors.LogContextMetadataKeys {
+					if value, ok := f.Metadata()[key].(string); ok && value != "" {
+						fields[key] = value
+						wsConn.callIDs[key] = value
+					}
+				}
+				t.setLogContext(fields)
+
+				token := f.TemplateVars[t.sessionTokenKey]
+				if token == "" {
+					token, _ = f.Metadata()[t.sessionTokenKey].(string)
+				}
+				t.adoptSessionToken(wsConn, token)
+
 				// Send start frame
 				if err := t.inputProc.pushFrame(f); err != nil {
 					t.log.Error("Error pushing start frame: %v", err)
@@ -268,8 +579,187 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// sendMessage sends a serialized message to all active connections
+// adoptSessionToken settles the session a connection takes over, once. A
+// token matching a dropped session still within its resume window cancels
+// the pending reset and marks the connection as resumed; any other
+// connection cancels it and starts a fresh session straight away.
+func (t *WebSocketTransport) adoptSessionToken(wsConn *wsConnection, token string) {
+	if t.resumeWindow <= 0 || wsConn.claimed {
+		return
+	}
+	wsConn.claimed = true
+	wsConn.sessionToken = token
+
+	t.sessionMu.Lock()
+	held := t.resumeTimer != nil
+	if held {
+		// Once cleared, an expiry already in flight finds itself replaced
+		t.resumeTimer.Stop()
+		t.resumeTimer = nil
+	}
+	heldToken := t.sessionToken
+	t.sessionConn = wsConn
+	t.sessionToken = token
+	t.sessionMu.Unlock()
+
+	if !held {
+		return
+	}
+	if token != "" && token == heldToken {
+		wsConn.resumed = true
+		t.log.Info("Connection %s resumed session %s", wsConn.id, token)
+		return
+	}
+	t.log.Info("Connection %s took over from held session %s, starting fresh", wsConn.id, heldToken)
+	t.resetSession()
+}
+
+// holdSession keeps a dropped connection's session alive for the resume
+// window instead of ending it, reporting false if the session should end
+// now. A connection another has since taken over from leaves the session
+// alone. If no connection resumes the session in time, it is reset.
+func (t *WebSocketTransport) holdSession(wsConn *wsConnection) bool {
+	if t.resumeWindow <= 0 {
+		return false
+	}
+	// A connection dropping before it settled a token still took over
+	t.adoptSessionToken(wsConn, "")
+
+	t.sessionMu.Lock()
+	defer t.sessionMu.Unlock()
+
+	if t.sessionConn != wsConn {
+		t.log.Info("Connection %s dropped after being taken over, keeping the session", wsConn.id)
+		return true
+	}
+	if wsConn.sessionToken == "" {
+		return false
+	}
+
+	token := wsConn.sessionToken
+	var timer *time.Timer
+	timer = time.AfterFunc(t.resumeWindow, func() {
+		t.sessionMu.Lock()
+		if t.resumeTimer != timer {
+			t.sessionMu.Unlock()
+			return
+		}
+		t.resumeTimer = nil
+		t.sessionConn = nil
+		t.sessionToken = ""
+		t.sessionMu.Unlock()
+
+		t.log.Info("Session %s not resumed within %v, starting fresh", token, t.resumeWindow)
+		t.resetSession()
+	})
+	t.resumeTimer = timer
+	t.log.Info("Connection %s dropped, holding session %s for %v", wsConn.id, token, t.resumeWindow)
+	return true
+}
+
+// resetSession ends a held session without ending the pipeline: pending
+// output is interrupted and the conversation cleared, keeping the system
+// prompt, so the next connection starts a new session
+func (t *WebSocketTransport) resetSession() {
+	if err := t.inputProc.pushFrame(frames.NewInterruptionFrame()); err != nil {
+		t.log.Error("Error pushing interruption frame: %v", err)
+	}
+	if err := t.inputProc.pushFrame(frames.NewLLMMessagesUpdateFrame([]services.LLMMessage{}, false)); err != nil {
+		t.log.Error("Error pushing messages update frame: %v", err)
+	}
+}
+
+// startKeepalive pings the connection every pingInterval. A pong, or any
+// message, pushes the read deadline out by pongTimeout; if the deadline
+// passes, the read loop fails, pushes an EndFrame and tears the connection
+// down. This catches half-open connections TCP keepalive misses.
+func (t *WebSocketTransport) startKeepalive(wsConn *wsConnection) {
+	conn := wsConn.conn
+	conn.SetReadDeadline(time.Now().Add(t.pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(t.pongTimeout))
+	})
+
+	go func() {
+		ticker := time.NewTicker(t.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-wsConn.ctx.Done():
+				return
+			case <-ticker.C:
+				wsConn.writeMu.Lock()
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(t.writeTimeout))
+				wsConn.writeMu.Unlock()
+				if err != nil {
+					// The read loop notices the closed connection and tears down
+					t.log.Warn("Ping failed on %s, closing connection: %v", wsConn.id, err)
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// setLogContext tags the transport's own log lines and its input/output
+// processors with per-connection fields
+func (t *WebSocketTransport) setLogContext(fields map[string]string) {
+	t.log.SetContext(fields)
+	t.inputProc.SetLogContext(fields)
+	t.outputProc.SetLogContext(fields)
+}
+
+// sendMessage sends serialized data to all active connections. data is a
+// single message ([]byte or string) or a batch of them, sent in order.
 func (t *WebSocketTransport) sendMessage(data interface{}) error {
+	switch batch := data.(type) {
+	case []interface{}:
+		for _, msg := range batch {
+			if err := t.sendMessage(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []string:
+		for _, msg := range batch {
+			if err := t.writeMessage(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case [][]byte:
+		for _, msg := range batch {
+			if err := t.writeMessage(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return t.writeMessage(data)
+}
+
+// closeConnections ends every active connection with a normal close, e.g.
+// when the pipeline ends the call. Each read loop then tears its connection
+// down.
+func (t *WebSocketTransport) closeConnections(reason string) {
+	t.connMu.RLock()
+	defer t.connMu.RUnlock()
+
+	for _, wsConn := range t.conns {
+		wsConn.writeMu.Lock()
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason)
+		if err := wsConn.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(t.writeTimeout)); err != nil {
+			t.log.Debug("Error sending close to connection %s: %v", wsConn.id, err)
+		}
+		wsConn.writeMu.Unlock()
+		wsConn.conn.Close()
+		t.log.Info("Closed connection %s: %s", wsConn.id, reason)
+	}
+}
+
+// writeMessage writes a single message to all active connections
+func (t *WebSocketTransport) writeMessage(data interface{}) error {
 	t.connMu.RLock()
 	defer t.connMu.RUnlock()
 
@@ -278,6 +768,7 @@ func (t *WebSocketTransport) sendMessage(data interface{}) error {
 
 		// Protect concurrent writes to the same connection
 		wsConn.writeMu.Lock()
+		wsConn.conn.SetWriteDeadline(time.Now().Add(t.writeTimeout))
 
 		// Determine message type based on actual data type
 		// This supports hybrid protocols (e.g., Asterisk: BINARY for audio, TEXT for control)
@@ -297,6 +788,13 @@ func (t *WebSocketTransport) sendMessage(data interface{}) error {
 
 		if err != nil {
 			t.log.Debug("Error sending to connection %s: %v", wsConn.id, err)
+			// A timed-out write leaves the connection unusable; close it so
+			// the read loop emits EndFrame instead of every write stalling
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.log.Warn("Write to %s timed out after %v, closing connection", wsConn.id, t.writeTimeout)
+				wsConn.conn.Close()
+			}
 		}
 	}
 
@@ -344,6 +842,25 @@ type audioChunk struct {
 	chunkSize    int
 	sampleRate   int
 	sendInterval time.Duration
+	enqueuedAt   time.Time // When handleAudioFrame queued the chunk (zero = never considered late)
+	seq          uint64    // Monotonic sequence number (0 = unstamped)
+	audio        []byte    // Unserialized audio in codec, for a fade-out if the chunk is interrupted
+	codec        string
+}
+
+// AudioSendStats counts the outbound audio chunks of a connection for
+// diagnosing glitches. Every chunk is stamped with a sequence number when it
+// is created; chunks that never reach the client (interruption, lateness,
+// the paused buffer cap) show up as gaps in the sent sequence.
+type AudioSendStats struct {
+	Sent        int64  // Chunks written to the client
+	Dropped     int64  // Chunks skipped between sent ones
+	Gaps        int64  // Runs of consecutive skipped chunks
+	LateDropped int64  // Chunks skipped for exceeding MaxChunkAge
+	LastSeq     uint64 // Sequence number of the last chunk sent
+
+	OverflowDropped int64 // Queued chunks dropped to stay under MaxQueuedAudio
+	SilenceDropped  int64 // TTS frames dropped below SilenceThreshold before queuing
 }
 
 // WebSocketOutputProcessor handles outgoing frames to WebSocket
@@ -353,8 +870,57 @@ type WebSocketOutputProcessor struct {
 	log         *logger.Logger
 	audioBuffer []byte
 	chunkSize   int
+	burstChunks int // Unpaced chunks at the start of each utterance
 	mu          sync.Mutex
 
+	// Chunks coalesced into each WebSocket write, when the serializer's
+	// protocol can carry several ptimes per message
+	framesPerWrite int
+
+	// Click-free boundaries: the first chunk after fadeInPending is faded in,
+	// and an interruption sends a fade-out (fadeInPending protected by mu)
+	fadeDuration  time.Duration
+	fadeInPending bool
+
+	// Outbound conversion to the serializer's negotiated audio format,
+	// rebuilt when the source or target format changes (protected by mu)
+	converter       *audio.AudioConverterProcessor
+	converterFormat string
+
+	// Client flow control (XOFF/XON): flowChan hands pause state to the
+	// sender goroutine, flowPaused lets handleAudioFrame enforce the cap
+	flowChan        chan bool
+	flowPaused      atomic.Bool
+	pausedBufferCap int
+	pausedDropped   atomic.Int64 // Chunks dropped over the cap during the current pause
+
+	// Late chunk dropping: chunks further than maxChunkAge behind their
+	// playout slot are skipped rather than sent late
+	maxChunkAge time.Duration
+	lateDropped atomic.Int64 // Total chunks dropped for lateness
+
+	// Chunk sequencing: nextSeq stamps chunks as they are created, the
+	// sender counts what it sends and the gaps between
+	nextSeq     atomic.Uint64
+	lastSentSeq atomic.Uint64
+	sentChunks  atomic.Int64
+	seqDropped  atomic.Int64
+	seqGaps     atomic.Int64
+
+	// Queue bounding: queuedNanos is the audio duration waiting in
+	// chunkQueue; past maxQueuedAudio handleAudioFrame waits on queueRoom,
+	// then drops the oldest chunks once queueFullTimeout has passed
+	maxQueuedAudio   time.Duration
+	queueFullTimeout time.Duration
+	queuedNanos      atomic.Int64
+	queueRoom        chan struct{}
+	queueOverflowing bool // Gave up waiting; drop without waiting until there is room. Only touched by handleAudioFrame.
+	overflowDropped  atomic.Int64
+
+	// Silent TTS frames are dropped before queuing below silenceThreshold
+	silenceThreshold float64
+	silenceDropped   atomic.Int64
+
 	// Rate-limited sender
 	chunkQueue   chan *audioChunk
 	senderCtx    context.Context
@@ -371,6 +937,9 @@ type WebSocketOutputProcessor struct {
 	interrupted       bool
 	currentContextID  string // The context_id we're currently accepting audio from
 	expectedContextID string // The context_id we expect from TTSStartedFrame (set before audio arrives)
+	generation        uint64 // Bumped on every TTSStartedFrame; one generation per utterance
+	interruptedGen    uint64 // Generation whose interruption has already been handled (0 = none)
+	drainingID        string // Correlation ID of the interruption awaiting the client's drain confirmation
 	interruptionMu    sync.Mutex
 
 	// Track if cleanup has been done to prevent send on closed channel
@@ -416,6 +985,18 @@ func newWebSocketOutputProcessor(transport *WebSocketTransport) *WebSocketOutput
 		chunkQueue:        make(chan *audioChunk, 1000), // Larger buffer for streaming TTS
 		playbackDoneChan:  make(chan string, 8),
 		playbackResetChan: make(chan struct{}, 1),
+		burstChunks:       transport.burstChunks,
+		flowChan:          make(chan bool, 8),
+		pausedBufferCap:   transport.pausedBufferChunks,
+		maxChunkAge:       transport.maxChunkAge,
+		framesPerWrite:    transport.framesPerWrite,
+		fadeDuration:      transport.fadeDuration,
+		maxQueuedAudio:    transport.maxQueuedAudio,
+		queueFullTimeout:  transport.queueFullTimeout,
+		silenceThreshold:  transport.silenceThreshold,
+		queueRoom:         make(chan struct{}, 1),
+		fadeInPending:     true,
+		generation:        1,
 	}
 	p.BaseProcessor = processors.NewBaseProcessor("WebSocketOutput", p)
 	p.drainPadNanos.Store(int64(DefaultDrainPad))
@@ -488,6 +1069,59 @@ func (p *WebSocketOutputProcessor) resolvePlaybackStrategy() playbackStrategy {
 	return stratDrainPad
 }
 
+// InputFormat implements processors.AudioFormatConsumer with the format the
+// serializer sends audio in, so the pipeline can flag TTS audio that would
+// otherwise be converted chunk by chunk here
+func (p *WebSocketOutputProcessor) InputFormat() processors.AudioFormat {
+	formatSerializer, ok := p.transport.serializer.(serializers.AudioFormatSerializer)
+	if !ok {
+		return processors.AudioFormat{}
+	}
+	codec, sampleRate := formatSerializer.OutputAudioFormat()
+	return processors.AudioFormat{SampleRate: sampleRate, Codec: codec}
+}
+
+// convertOutboundAudio converts audio in codec at sampleRate to the format
+// the serializer negotiated for the connection, returning the data, codec and
+// sample rate to send. Audio passes through unchanged when the serializer
+// doesn't declare a format or it already matches. Caller must hold mu.
+func (p *WebSocketOutputProcessor) convertOutboundAudio(data []byte, codec string, sampleRate int) ([]byte, string, int, error) {
+	formatSerializer, ok := p.transport.serializer.(serializers.AudioFormatSerializer)
+	if !ok {
+		return data, codec, sampleRate, nil
+	}
+	targetCodec, targetRate := formatSerializer.OutputAudioFormat()
+	targetCodec = audio.NormalizeCodecName(targetCodec)
+	if targetCodec == "" {
+		return data, codec, sampleRate, nil
+	}
+	if targetRate <= 0 {
+		targetRate = sampleRate
+	}
+	codec = audio.NormalizeCodecName(codec)
+	if codec == targetCodec && sampleRate == targetRate {
+		return data, codec, sampleRate, nil
+	}
+
+	format := fmt.Sprintf("%s/%d->%s/%d", codec, sampleRate, targetCodec, targetRate)
+	if p.converter == nil || p.converterFormat != format {
+		p.log.Info("Converting outbound audio %s %dHz to %s %dHz", codec, sampleRate, targetCodec, targetRate)
+		p.converter = audio.NewAudioConverterProcessor(audio.AudioConverterConfig{
+			InputCodec:       codec,
+			InputSampleRate:  sampleRate,
+			OutputCodec:      targetCodec,
+			OutputSampleRate: targetRate,
+		})
+		p.converterFormat = format
+	}
+
+	converted, err := p.converter.Convert(data, sampleRate)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return converted, targetCodec, targetRate, nil
+}
+
 // calculateSendInterval computes the real-time pacing interval for audio chunks.
 // Formula: chunk_duration = chunk_size / (sample_rate * bytes_per_sample)
 // For 160-byte chunks at 8kHz mulaw: 160/8000 = 0.02s = 20ms
@@ -534,6 +1168,21 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 		var nextSendTime time.Time
 		firstChunk := true
 		botSpeaking := false
+		burstRemaining := 0
+		paused := false
+
+		// scheduledAt is when the next chunk should go out if audio were
+		// sent in real time from when it was queued. A chunk queued behind
+		// others waits its turn without counting as late; only time lost to
+		// a slow connection or a stalled sender does.
+		var scheduledAt time.Time
+		lateRun := 0
+		logLateDrops := func() {
+			if lateRun > 0 {
+				p.log.Warn("Dropped %d audio chunks more than %v late (%d total)", lateRun, p.maxChunkAge, p.lateDropped.Load())
+				lateRun = 0
+			}
+		}
 
 		// BOT_VAD_STOP_SECS = 0.35
 		// If no audio chunks for this duration, the server has finished sending audio.
@@ -558,12 +1207,39 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 		}()
 
 		for {
+			// While the client has paused us, leave chunks in the queue
+			queue := p.chunkQueue
+			if paused {
+				queue = nil
+			}
+
 			select {
 			case <-p.senderCtx.Done():
 				p.log.Info("Sender goroutine stopped")
 				return
 
-			case chunk := <-p.chunkQueue:
+			case pause := <-p.flowChan:
+				if pause == paused {
+					continue
+				}
+				paused = pause
+				if paused {
+					// Buffered audio is still to be played; don't let the
+					// silence look like the end of the utterance
+					vadTimer.Stop()
+					p.log.Info("Client paused sending (%d chunks queued)", len(p.chunkQueue))
+				} else {
+					if botSpeaking {
+						vadTimer.Reset(vadStopDuration)
+					}
+					// The client held playout, so queued audio is due from now
+					scheduledAt = time.Now()
+					p.log.Info("Client resumed sending (%d chunks queued)", len(p.chunkQueue))
+				}
+
+			case chunk := <-queue:
+				p.dequeued(chunk)
+
 				// CRITICAL: Check if interrupted before sending - discard chunk if so
 				// This prevents sending chunks that were picked up just before/during interruption
 				p.interruptionMu.Lock()
@@ -574,6 +1250,20 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 				}
 				p.interruptionMu.Unlock()
 
+				if p.maxChunkAge > 0 && !chunk.enqueuedAt.IsZero() {
+					if chunk.enqueuedAt.After(scheduledAt) {
+						scheduledAt = chunk.enqueuedAt
+					}
+					due := scheduledAt
+					scheduledAt = scheduledAt.Add(chunk.sendInterval)
+					if time.Since(due) > p.maxChunkAge {
+						lateRun++
+						p.lateDropped.Add(1)
+						continue
+					}
+				}
+				logLateDrops()
+
 				// Rate-limiting algorithm:
 				// current_time = time.monotonic()
 				// sleep_duration = max(0, self._next_send_time - current_time)
@@ -591,12 +1281,29 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 					firstChunk = false
 				}
 
+				// A new utterance may send its first chunks ahead of real time
+				// to prime the client's jitter buffer. The pacing clock starts
+				// at the first chunk, so the client stays exactly burstChunks
+				// ahead afterwards rather than accumulating more.
+				if !botSpeaking {
+					burstRemaining = p.burstChunks
+				}
+				bursting := burstRemaining > 0
+				if bursting {
+					burstRemaining--
+				}
+
 				// Calculate sleep duration
 				sleepDuration := nextSendTime.Sub(now)
+				if bursting {
+					sleepDuration = 0
+				}
 				if sleepDuration > 0 {
 					time.Sleep(sleepDuration)
 				}
 
+				p.recordSequence(chunk.seq)
+
 				// Send the chunk
 				if err := p.transport.sendMessage(chunk.data); err != nil {
 					p.log.Warn("Error sending chunk: %v", err)
@@ -611,13 +1318,17 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 					}
 				}
 
-				// Update next send time
-				if sleepDuration <= 0 {
-					// We're behind schedule - reset to current time + interval
-					nextSendTime = time.Now().Add(chunk.sendInterval)
-				} else {
-					// We're on schedule - add interval to maintain consistent pacing
-					nextSendTime = nextSendTime.Add(chunk.sendInterval)
+				// Update next send time (later burst chunks share the first
+				// chunk's slot, so only the first one advances the clock)
+				firstOfBurst := burstRemaining == p.burstChunks-1
+				if !bursting || firstOfBurst {
+					if sleepDuration <= 0 {
+						// We're behind schedule - reset to current time + interval
+						nextSendTime = time.Now().Add(chunk.sendInterval)
+					} else {
+						// We're on schedule - add interval to maintain consistent pacing
+						nextSendTime = nextSendTime.Add(chunk.sendInterval)
+					}
 				}
 
 				// Reset VAD timer
@@ -645,6 +1356,7 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 				}
 
 			case <-vadTimer.C:
+				logLateDrops()
 				// Server finished sending audio chunks.
 				// IMPORTANT: Only proceed if LLM has finished generating.
 				if !botSpeaking {
@@ -766,6 +1478,121 @@ func (p *WebSocketOutputProcessor) startChunkSender() {
 	}()
 }
 
+// recordSequence counts a chunk about to be sent and any chunks skipped
+// since the previous one, reporting a gap upstream in an
+// AudioSendStatsFrame. Called only from the sender goroutine.
+func (p *WebSocketOutputProcessor) recordSequence(seq uint64) {
+	p.sentChunks.Add(1)
+	if seq == 0 {
+		return
+	}
+	last := p.lastSentSeq.Swap(seq)
+	if last != 0 && seq > last+1 {
+		skipped := int64(seq - last - 1)
+		p.seqDropped.Add(skipped)
+		p.seqGaps.Add(1)
+		p.log.Debug("Audio sequence gap: sent chunk %d after %d (%d skipped, %d total)",
+			seq, last, skipped, p.seqDropped.Load())
+
+		stats := p.AudioStats()
+		report := frames.NewAudioSendStatsFrame()
+		report.Sent = stats.Sent
+		report.Dropped = stats.Dropped
+		report.Gaps = stats.Gaps
+		report.LateDropped = stats.LateDropped
+		report.LastSeq = stats.LastSeq
+		report.Skipped = skipped
+		p.PushFrame(report, frames.Upstream)
+	}
+}
+
+// isSilent reports whether a TTS frame's level is below silenceThreshold
+func (p *WebSocketOutputProcessor) isSilent(audioFrame *frames.TTSAudioFrame) bool {
+	if p.silenceThreshold <= 0 {
+		return false
+	}
+	codec, _ := audioFrame.Metadata()["codec"].(string)
+	if codec == "" {
+		codec = "linear16"
+	}
+	volume, err := audio.CodecVolume(audioFrame.Data, codec)
+	return err == nil && float64(volume) < p.silenceThreshold
+}
+
+// AudioStats returns the outbound audio chunk counters
+func (p *WebSocketOutputProcessor) AudioStats() AudioSendStats {
+	return AudioSendStats{
+		Sent:        p.sentChunks.Load(),
+		Dropped:     p.seqDropped.Load(),
+		Gaps:        p.seqGaps.Load(),
+		LateDropped: p.lateDropped.Load(),
+		LastSeq:     p.lastSentSeq.Load(),
+
+		OverflowDropped: p.overflowDropped.Load(),
+		SilenceDropped:  p.silenceDropped.Load(),
+	}
+}
+
+// dequeued accounts for a chunk leaving the queue and wakes a frame waiting
+// for room under maxQueuedAudio
+func (p *WebSocketOutputProcessor) dequeued(chunk *audioChunk) {
+	p.queuedNanos.Add(-int64(chunk.sendInterval))
+	select {
+	case p.queueRoom <- struct{}{}:
+	default:
+	}
+}
+
+// makeQueueRoom bounds the queued audio at maxQueuedAudio before another
+// chunk is queued. It blocks while the sender drains, applying backpressure
+// to the TTS service; if no room appears within queueFullTimeout, the client
+// has stalled and the oldest queued chunks are dropped instead, without
+// waiting again until the queue recovers.
+func (p *WebSocketOutputProcessor) makeQueueRoom() {
+	full := func() bool {
+		return time.Duration(p.queuedNanos.Load()) >= p.maxQueuedAudio
+	}
+	if p.maxQueuedAudio <= 0 || !full() {
+		if p.queueOverflowing {
+			p.queueOverflowing = false
+			p.log.Info("Audio queue drained below %v (%d chunks dropped in total)", p.maxQueuedAudio, p.overflowDropped.Load())
+		}
+		return
+	}
+
+	if !p.queueOverflowing {
+		timer := time.NewTimer(p.queueFullTimeout)
+		defer timer.Stop()
+		for full() {
+			select {
+			case <-p.queueRoom:
+			case <-timer.C:
+				p.queueOverflowing = true
+				p.log.Warn("Audio queue full (%v) for %v, dropping oldest queued audio", p.maxQueuedAudio, p.queueFullTimeout)
+				p.dropOldest(full)
+				return
+			case <-p.senderCtx.Done():
+				return
+			}
+		}
+		return
+	}
+	p.dropOldest(full)
+}
+
+// dropOldest discards queued chunks from the front while full reports true
+func (p *WebSocketOutputProcessor) dropOldest(full func() bool) {
+	for full() {
+		select {
+		case chunk := <-p.chunkQueue:
+			p.dequeued(chunk)
+			p.overflowDropped.Add(1)
+		default:
+			return
+		}
+	}
+}
+
 // Cleanup stops the sender goroutine and releases resources
 // Safe to call multiple times - only executes once
 func (p *WebSocketOutputProcessor) Cleanup() error {
@@ -782,6 +1609,10 @@ func (p *WebSocketOutputProcessor) Cleanup() error {
 		}
 		p.senderWg.Wait()
 		close(p.chunkQueue)
+		if stats := p.AudioStats(); stats.Sent > 0 {
+			p.log.Info("Sent %d audio chunks; %d dropped in %d gaps (%d late)",
+				stats.Sent, stats.Dropped, stats.Gaps, stats.LateDropped)
+		}
 		p.log.Info("Cleanup complete")
 	})
 	return nil
@@ -793,26 +1624,53 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		p.HandleStartFrame(startFrame)
 		p.log.Info("Interruptions configured: allowed=%v, strategies=%d",
 			p.InterruptionsAllowed(), len(p.InterruptionStrategies()))
+		// Let the serializer pick up call identifiers from the metadata, e.g.
+		// a streamSid supplied by the application rather than the start event
+		if err := p.transport.serializer.Setup(startFrame); err != nil {
+			p.log.Warn("Serializer setup failed: %v", err)
+		}
 		// Pass frame downstream
 		return p.PushFrame(frame, direction)
 	}
 
-	// Handle EndFrame - cleanup sender goroutine and stop processing
+	// Handle EndFrame - cleanup sender goroutine and hang up the call, then
+	// keep propagating so processors placed after the output (e.g. the
+	// assistant aggregator) and the pipeline sink still see the end of the
+	// session
 	if _, ok := frame.(*frames.EndFrame); ok {
 		p.log.Info("Received EndFrame, cleaning up sender goroutine")
 		if err := p.Cleanup(); err != nil {
 			p.log.Warn("Error during cleanup: %v", err)
 		}
-		// Don't process any more frames after EndFrame
+		p.transport.closeConnections("call ended")
+		return p.PushFrame(frame, direction)
+	}
+
+	// Handle client flow control - pause/resume the sender, keeping queued audio
+	if _, ok := frame.(*frames.FlowControlPauseFrame); ok {
+		p.setFlowPaused(true)
+		return nil
+	}
+	if _, ok := frame.(*frames.FlowControlResumeFrame); ok {
+		p.setFlowPaused(false)
 		return nil
 	}
 
 	// Handle LLMFullResponseEndFrame - mark that LLM has finished generating
 	if _, ok := frame.(*frames.LLMFullResponseEndFrame); ok {
+		// A response completed silently after an interruption was never
+		// spoken, so it neither ends the bot's turn nor closes a text turn
+		if silent, _ := frame.Metadata()[frames.SilentResponseKey].(bool); silent {
+			return p.PushFrame(frame, direction)
+		}
 		p.llmMu.Lock()
 		p.llmResponseEnded = true
 		p.llmMu.Unlock()
 		p.log.Info("LLM response ended - bot will stop speaking after final audio")
+		// Text protocols close the assistant turn here
+		if err := p.sendSerialized(frame); err != nil {
+			return err
+		}
 		// Pass frame downstream
 		return p.PushFrame(frame, direction)
 	}
@@ -821,7 +1679,15 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 	if playbackComplete, ok := frame.(*frames.PlaybackCompleteFrame); ok {
 		p.interruptionMu.Lock()
 		isInterrupted := p.interrupted
+		drainingID := ""
+		if playbackComplete.Metadata()["correlation_id"] == "queue-drained" {
+			drainingID = p.drainingID
+			p.drainingID = ""
+		}
 		p.interruptionMu.Unlock()
+		if drainingID != "" {
+			p.TraceInterruption(drainingID, frames.InterruptionStageDrainConfirmed)
+		}
 		if isInterrupted {
 			p.log.Debug("Ignoring playback completion signal while interrupted")
 			return nil
@@ -854,6 +1720,10 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		p.llmResponseEnded = false
 		p.llmMu.Unlock()
 
+		p.mu.Lock()
+		p.fadeInPending = true
+		p.mu.Unlock()
+
 		p.interruptionMu.Lock()
 		wasInterrupted := p.interrupted
 		oldContextID := p.currentContextID
@@ -862,6 +1732,8 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		// Store expected context ID from the TTS service
 		// Only accept audio frames with this exact context ID
 		p.expectedContextID = ttsFrame.ContextID
+		// New utterance - the next interruption must drain and flush again
+		p.generation++
 		// Log summary of blocked stale audio before resetting counters
 		if p.staleAudioBlockedCount > 0 {
 			p.log.Debug("Blocked %d stale audio frames from context %s",
@@ -881,13 +1753,26 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 	}
 
 	// Handle InterruptionFrame - clear local buffer, drain queue, and send flush command to server
-	if _, ok := frame.(*frames.InterruptionFrame); ok {
+	if interruption, ok := frame.(*frames.InterruptionFrame); ok {
 		// Check if interruptions are allowed
 		if !p.InterruptionsAllowed() {
 			p.log.Debug("Interruptions not allowed, ignoring InterruptionFrame")
 			return nil
 		}
 
+		// Only the first interruption per generation drains and flushes.
+		// Rapid back-to-back barge-ins would otherwise double-drain and send
+		// duplicate flush commands to the client.
+		p.interruptionMu.Lock()
+		if p.interruptedGen == p.generation {
+			gen := p.generation
+			p.interruptionMu.Unlock()
+			p.log.Debug("Interruption already handled for generation %d, ignoring", gen)
+			return nil
+		}
+		p.interruptedGen = p.generation
+		p.interruptionMu.Unlock()
+
 		p.log.Info("Interruption sequence started")
 
 		// Emit BotStoppedSpeakingFrame if we were speaking
@@ -909,36 +1794,19 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		p.log.Debug("Step 2: Set interrupted=true (was=%v, blocking context: %s)", wasAlreadyInterrupted, oldContextID)
 		p.interruptionMu.Unlock()
 
-		// Clear local audio buffer
-		p.mu.Lock()
-		bufferSize := len(p.audioBuffer)
+		// Clear local audio buffer and drain the chunk queue
+		next, bufferSize, drainedChunks, drainedBytes := p.discardQueuedAudio()
 		if bufferSize > 0 {
-			p.log.Debug("Step 3: Clearing local audio buffer (%d bytes)", bufferSize)
-			p.audioBuffer = make([]byte, 0)
+			p.log.Debug("Step 3: Cleared local audio buffer (%d bytes)", bufferSize)
 		} else {
 			p.log.Debug("Step 3: Local audio buffer already empty")
 		}
-		p.mu.Unlock()
-
-		// Drain the chunk queue (remove all pending chunks)
-		p.log.Debug("Step 4: Draining pending chunk queue...")
-		drainedChunks := 0
-		drainedBytes := 0
-	drainLoop:
-		for {
-			select {
-			case chunk := <-p.chunkQueue:
-				drainedChunks++
-				drainedBytes += chunk.chunkSize
-			default:
-				break drainLoop
-			}
-		}
 		if drainedChunks > 0 {
 			p.log.Debug("Step 4: Drained %d pending chunks (%d bytes) from queue", drainedChunks, drainedBytes)
 		} else {
 			p.log.Debug("Step 4: Chunk queue already empty")
 		}
+		p.TraceInterruption(interruption.CorrelationID, frames.InterruptionStageOutputDrained)
 
 		// Serialize the interruption frame (serializer knows what commands to send)
 		data, err := p.transport.serializer.Serialize(frame)
@@ -947,31 +1815,32 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		}
 
 		if data != nil {
-			// Handle both single message and multiple messages (slice)
-			if commands, ok := data.([]string); ok {
-				// Multiple commands - send each one
-				p.log.Debug("Sending %d server-side flush commands", len(commands))
-				for _, cmd := range commands {
-					p.log.Debug("Sending: %s", cmd)
-					if err := p.transport.sendMessage(cmd); err != nil {
-						return fmt.Errorf("send error: %w", err)
-					}
-				}
-			} else {
-				// Single message - send it
-				p.log.Debug("Sending server-side flush command")
-				if err := p.transport.sendMessage(data); err != nil {
-					return fmt.Errorf("send error: %w", err)
-				}
+			// Armed before sending so a fast confirmation isn't missed
+			p.interruptionMu.Lock()
+			p.drainingID = interruption.CorrelationID
+			p.interruptionMu.Unlock()
+
+			// A single command or a batch, e.g. Asterisk's flush commands
+			p.log.Debug("Sending server-side flush command(s)")
+			if err := p.transport.sendMessage(data); err != nil {
+				return fmt.Errorf("send error: %w", err)
 			}
 		} else {
 			p.log.Debug("No server-side flush command needed")
 		}
 
+		// After the flush, so the client doesn't discard it with the rest
+		p.sendFadeOut(next)
+
 		p.log.Info("Interruption handling complete (cleared %d bytes buffer + %d chunks)", bufferSize, drainedChunks)
 		return nil
 	}
 
+	// Handle TransferFrame - stop the bot's audio and hand the call over
+	if transfer, ok := frame.(*frames.TransferFrame); ok {
+		return p.handleTransfer(ctx, transfer, direction)
+	}
+
 	// Handle TTSAudioFrame with buffering and chunking (TTS output to send to client)
 	if audioFrame, ok := frame.(*frames.TTSAudioFrame); ok {
 		return p.handleAudioFrame(audioFrame)
@@ -984,7 +1853,139 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 		return nil
 	}
 
+	// Upstream frames come from processors after the output, e.g. the
+	// assistant aggregator's LLMContextFrame after a function result; they
+	// are for the pipeline, not the client
+	if direction == frames.Upstream {
+		return p.PushFrame(frame, direction)
+	}
+
 	// For all other frames, serialize and send normally
+	if err := p.sendSerialized(frame); err != nil {
+		return err
+	}
+
+	switch frame.(type) {
+	// LLM text only reaches the output when it bypasses TTS (text chat,
+	// SkipTTS); pass it on so an assistant aggregator after the output still
+	// records the reply
+	case *frames.LLMFullResponseStartFrame, *frames.TextFrame, *frames.LLMTextFrame:
+		return p.PushFrame(frame, direction)
+	// Function calls and their results are recorded in the context by the
+	// assistant aggregator after the output
+	case *frames.FunctionCallsStartedFrame, *frames.FunctionCallInProgressFrame,
+		*frames.FunctionCallResultFrame, *frames.FunctionCallCancelFrame:
+		return p.PushFrame(frame, direction)
+	}
+	return nil
+}
+
+// discardQueuedAudio clears the local audio buffer and drains the chunk
+// queue, returning the buffered bytes and the drained chunks and bytes
+func (p *WebSocketOutputProcessor) discardQueuedAudio() (next *audioChunk, bufferSize, drainedChunks, drainedBytes int) {
+	p.mu.Lock()
+	bufferSize = len(p.audioBuffer)
+	if bufferSize > 0 {
+		p.audioBuffer = make([]byte, 0)
+	}
+	p.fadeInPending = true
+	p.mu.Unlock()
+
+	for {
+		select {
+		case chunk := <-p.chunkQueue:
+			p.dequeued(chunk)
+			if next == nil {
+				next = chunk
+			}
+			drainedChunks++
+			drainedBytes += chunk.chunkSize
+		default:
+			return next, bufferSize, drainedChunks, drainedBytes
+		}
+	}
+}
+
+// sendFadeOut ends audio cut off by an interruption with a short fade to
+// silence instead of a click. The fade is built from next, the chunk that
+// would have played after the last one sent.
+func (p *WebSocketOutputProcessor) sendFadeOut(next *audioChunk) {
+	if next == nil || p.fadeDuration <= 0 || len(next.audio) == 0 {
+		return
+	}
+	faded, err := audio.FadeOutAudio(next.audio, next.codec, audio.FadeSamples(p.fadeDuration, next.sampleRate))
+	if err != nil {
+		p.log.Debug("Skipping fade-out: %v", err)
+		return
+	}
+	frame := frames.NewTTSAudioFrame(faded, next.sampleRate, 1)
+	frame.SetMetadata("codec", next.codec)
+	data, err := p.transport.serializer.Serialize(frame)
+	if err != nil || data == nil {
+		p.log.Debug("Skipping fade-out: serialization error %v", err)
+		return
+	}
+	if err := p.transport.sendMessage(data); err != nil {
+		p.log.Debug("Error sending fade-out: %v", err)
+		return
+	}
+	p.log.Debug("Sent %v fade-out of interrupted audio", p.fadeDuration)
+}
+
+// handleTransfer ends the bot's media, sends the serializer's in-band
+// transfer signaling and runs the transfer handler in the background, so a
+// slow provider API doesn't hold up the output. Audio still in flight is
+// blocked like after an interruption.
+func (p *WebSocketOutputProcessor) handleTransfer(ctx context.Context, transfer *frames.TransferFrame, direction frames.FrameDirection) error {
+	p.transport.handlerMu.RLock()
+	handler := p.transport.onTransfer
+	p.transport.handlerMu.RUnlock()
+	if handler == nil {
+		p.log.Warn("No transfer handler (OnTransfer), ignoring transfer to %s", transfer.Destination)
+		return p.PushFrame(transfer, direction)
+	}
+
+	p.log.Info("Transferring call to %s", transfer.Destination)
+	p.interruptionMu.Lock()
+	p.interrupted = true
+	p.interruptionMu.Unlock()
+
+	p.PushFrame(frames.NewBotStoppedSpeakingFrame(), frames.Upstream)
+	select {
+	case p.playbackResetChan <- struct{}{}:
+	default:
+	}
+	_, bufferSize, drainedChunks, _ := p.discardQueuedAudio()
+	p.log.Debug("Discarded %d buffered bytes and %d queued chunks before transfer", bufferSize, drainedChunks)
+
+	if transferer, ok := p.transport.serializer.(serializers.TransferSerializer); ok {
+		data, err := transferer.SerializeTransfer(transfer.Destination)
+		if err != nil {
+			return fmt.Errorf("transfer error: %w", err)
+		}
+		if data != nil {
+			if err := p.transport.sendMessage(data); err != nil {
+				return fmt.Errorf("send error: %w", err)
+			}
+		}
+	}
+
+	destination := transfer.Destination
+	meta := p.transport.callMetadata()
+	go func() {
+		if err := handler(ctx, destination, meta); err != nil {
+			p.log.Error("Transfer to %s failed: %v", destination, err)
+			p.PushFrame(frames.NewErrorFrame(fmt.Errorf("transfer to %s failed: %w", destination, err)), frames.Upstream)
+			return
+		}
+		p.log.Info("Transferred call to %s", destination)
+	}()
+	return p.PushFrame(transfer, direction)
+}
+
+// sendSerialized serializes a frame and sends it to the client. Frames the
+// serializer doesn't support for output are skipped.
+func (p *WebSocketOutputProcessor) sendSerialized(frame frames.Frame) error {
 	data, err := p.transport.serializer.Serialize(frame)
 	if err != nil {
 		return fmt.Errorf("serialization error: %w", err)
@@ -999,10 +2000,24 @@ func (p *WebSocketOutputProcessor) HandleFrame(ctx context.Context, frame frames
 	if err := p.transport.sendMessage(data); err != nil {
 		return fmt.Errorf("send error: %w", err)
 	}
-
 	return nil
 }
 
+// setFlowPaused records the client's flow control state and hands it to the
+// sender goroutine
+func (p *WebSocketOutputProcessor) setFlowPaused(paused bool) {
+	if p.flowPaused.Swap(paused) == paused {
+		return
+	}
+	select {
+	case p.flowChan <- paused:
+	case <-p.senderCtx.Done():
+	}
+	if dropped := p.pausedDropped.Swap(0); !paused && dropped > 0 {
+		p.log.Warn("Dropped %d chunks over the paused buffer cap (%d)", dropped, p.pausedBufferCap)
+	}
+}
+
 func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioFrame) error {
 	// CRITICAL: Check if cleanup has been done - prevent send on closed channel
 	p.mu.Lock()
@@ -1017,6 +2032,16 @@ func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioF
 	}
 	p.mu.Unlock()
 
+	// Providers occasionally send empty or pure-silence chunks; queuing them
+	// would only take pacing slots and hold off the VAD-stop timer
+	if len(audioFrame.Data) == 0 {
+		return nil
+	}
+	if p.isSilent(audioFrame) {
+		p.silenceDropped.Add(1)
+		return nil
+	}
+
 	// Get context_id from frame metadata (set by TTS service like Cartesia)
 	frameContextID := ""
 	if ctxIDRaw, exists := audioFrame.Metadata()["context_id"]; exists {
@@ -1116,24 +2141,28 @@ func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioF
 		}
 	}
 
-	// Set chunk size based on codec
-	// For telephony codecs (mulaw/alaw): 160 bytes = 20ms at 8kHz
-	// For PCM: 320 bytes = 10ms at 16kHz
-	chunkSize := 320
-	if codec == "mulaw" || codec == "alaw" {
-		chunkSize = 160
+	// Convert to the connection's codec and rate (e.g. linear16 TTS or an
+	// alaw voice on a mulaw Twilio stream)
+	frameData, codec, sampleRate, err := p.convertOutboundAudio(audioFrame.Data, codec, audioFrame.SampleRate)
+	if err != nil {
+		p.log.Warn("Audio conversion error: %v", err)
+		return nil
 	}
 
-	// Calculate send interval for rate limiting
-	sendInterval := calculateSendInterval(chunkSize, audioFrame.SampleRate, codec)
+	chunkSize := p.chunkSizeFor(codec)
+
+	// Coalesce whole chunks into fewer, larger writes when the protocol
+	// allows; each write is paced by the audio it carries
+	writeSize := chunkSize * p.coalescedChunks()
 
 	// IMMEDIATE STREAMING MODE:
 	// Process THIS frame's data immediately, combining with any small remainder from previous frame
 	// This ensures each TTS chunk is sent as soon as it arrives, not accumulated
-	currentData := append(p.audioBuffer, audioFrame.Data...)
+	currentData := append(p.audioBuffer, frameData...)
 	p.audioBuffer = make([]byte, 0) // Clear old buffer
 
 	numChunks := 0
+	streamedBytes := 0
 
 	// Chunk and send immediately from current frame
 	for len(currentData) >= chunkSize {
@@ -1148,16 +2177,33 @@ func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioF
 		}
 		p.interruptionMu.Unlock()
 
-		chunk := currentData[:chunkSize]
-		currentData = currentData[chunkSize:]
+		n := min(len(currentData)/chunkSize*chunkSize, writeSize)
+		chunk := currentData[:n]
+		currentData = currentData[n:]
 		numChunks++
+		streamedBytes += n
+
+		// Start each utterance with a fade-in rather than a click
+		if p.fadeInPending {
+			p.fadeInPending = false
+			if p.fadeDuration > 0 {
+				if faded, err := audio.FadeInAudio(chunk, codec, audio.FadeSamples(p.fadeDuration, sampleRate)); err == nil {
+					chunk = faded
+				} else {
+					p.log.Debug("Skipping fade-in: %v", err)
+				}
+			}
+		}
 
 		// Create a new audio frame for this chunk
-		chunkFrame := frames.NewTTSAudioFrame(chunk, audioFrame.SampleRate, audioFrame.Channels)
+		chunkFrame := frames.NewTTSAudioFrame(chunk, sampleRate, audioFrame.Channels)
 		// Copy metadata
 		for k, v := range audioFrame.Metadata() {
 			chunkFrame.SetMetadata(k, v)
 		}
+		chunkFrame.SetMetadata("codec", codec)
+		seq := p.nextSeq.Add(1)
+		chunkFrame.SetMetadata("sequence", seq)
 
 		// Pre-serialize the chunk
 		data, err := p.transport.serializer.Serialize(chunkFrame)
@@ -1170,13 +2216,28 @@ func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioF
 			continue
 		}
 
+		// While paused the queue is our buffer; past the cap drop new audio
+		// rather than block the pipeline until the client resumes
+		if p.flowPaused.Load() && len(p.chunkQueue) >= p.pausedBufferCap {
+			p.pausedDropped.Add(1)
+			continue
+		}
+
+		p.makeQueueRoom()
+		sendInterval := calculateSendInterval(n, sampleRate, codec)
+		p.queuedNanos.Add(int64(sendInterval))
+
 		// BLOCKING send to queue for immediate transmission
 		select {
 		case p.chunkQueue <- &audioChunk{
 			data:         data,
-			chunkSize:    chunkSize,
-			sampleRate:   audioFrame.SampleRate,
+			chunkSize:    n,
+			sampleRate:   sampleRate,
 			sendInterval: sendInterval,
+			enqueuedAt:   time.Now(),
+			seq:          seq,
+			audio:        chunk,
+			codec:        codec,
 		}:
 			// Chunk queued successfully
 		case <-p.senderCtx.Done():
@@ -1193,8 +2254,40 @@ func (p *WebSocketOutputProcessor) handleAudioFrame(audioFrame *frames.TTSAudioF
 	// Only log for significant chunks (reduces noise)
 	if numChunks > 0 {
 		p.log.Debug("Streamed %d chunks (%d bytes) immediately (buffer_remainder=%d bytes)",
-			numChunks, numChunks*chunkSize, len(p.audioBuffer))
+			numChunks, streamedBytes, len(p.audioBuffer))
 	}
 
 	return nil
 }
+
+// chunkSizeFor returns the outbound chunk size in bytes: the serializer's
+// optimal frame size when it reports one (e.g. 240 for a 30ms ptime),
+// otherwise 160 bytes (20ms at 8kHz) for mulaw/alaw and 320 bytes (10ms at
+// 16kHz) for PCM
+func (p *WebSocketOutputProcessor) chunkSizeFor(codec string) int {
+	if s, ok := p.transport.serializer.(serializers.FrameSizeSerializer); ok {
+		if size := s.OptimalFrameSize(); size > 0 {
+			if codec != "mulaw" && codec != "alaw" && size%2 != 0 {
+				size++ // Keep 16-bit PCM samples whole
+			}
+			return size
+		}
+	}
+	if codec == "mulaw" || codec == "alaw" {
+		return 160
+	}
+	return 320
+}
+
+// coalescedChunks returns how many chunks to put in each WebSocket write:
+// FramesPerWrite if the serializer can carry several ptimes per message,
+// otherwise one
+func (p *WebSocketOutputProcessor) coalescedChunks() int {
+	if p.framesPerWrite <= 1 {
+		return 1
+	}
+	if c, ok := p.transport.serializer.(serializers.CoalescingAudioSerializer); ok && c.SupportsCoalescedAudio() {
+		return p.framesPerWrite
+	}
+	return 1
+}
@@ -0,0 +1,97 @@
+package transports
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLateChunksDropped(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:  &mockSerializer{},
+		MaxChunkAge: 100 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+	processor := transport.outputProc
+
+	// Chunks queued a second ago are long past their playout slot
+	stale := time.Now().Add(-time.Second)
+	for i := 0; i < 3; i++ {
+		processor.chunkQueue <- &audioChunk{
+			data:         []byte(fmt.Sprintf("stale-%d", i)),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: 20 * time.Millisecond,
+			enqueuedAt:   stale,
+		}
+	}
+	for i := 0; i < 2; i++ {
+		processor.chunkQueue <- &audioChunk{
+			data:         []byte(fmt.Sprintf("fresh-%d", i)),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: 20 * time.Millisecond,
+			enqueuedAt:   time.Now(),
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, msg := readTestMessage(t, client); msg != fmt.Sprintf("fresh-%d", i) {
+			t.Fatalf("Message %d = %q, want fresh-%d", i, msg, i)
+		}
+	}
+	if got := processor.lateDropped.Load(); got != 3 {
+		t.Errorf("Late chunks dropped = %d, want 3", got)
+	}
+}
+
+func TestQueuedChunksNotLate(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:  &mockSerializer{},
+		MaxChunkAge: 50 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+	processor := transport.outputProc
+
+	// A burst of TTS audio queued at once waits its turn behind earlier
+	// chunks; that wait is playout, not lateness
+	const n = 10
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		processor.chunkQueue <- &audioChunk{
+			data:         []byte(fmt.Sprintf("chunk-%d", i)),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: 20 * time.Millisecond,
+			enqueuedAt:   now,
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if _, msg := readTestMessage(t, client); msg != fmt.Sprintf("chunk-%d", i) {
+			t.Fatalf("Message %d = %q, want chunk-%d", i, msg, i)
+		}
+	}
+	if got := processor.lateDropped.Load(); got != 0 {
+		t.Errorf("Late chunks dropped = %d, want 0", got)
+	}
+}
+
+func TestStaleChunksSentWithoutMaxAge(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	transport.outputProc.chunkQueue <- &audioChunk{
+		data:         []byte("stale"),
+		chunkSize:    160,
+		sampleRate:   8000,
+		sendInterval: 20 * time.Millisecond,
+		enqueuedAt:   time.Now().Add(-time.Minute),
+	}
+	if _, msg := readTestMessage(t, client); msg != "stale" {
+		t.Fatalf("Message = %q, want stale (dropping is disabled by default)", msg)
+	}
+}
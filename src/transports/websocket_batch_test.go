@@ -0,0 +1,73 @@
+package transports
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// batchSerializer answers TextFrames with a mixed control/audio batch and
+// interruptions with a batch of binary messages
+type batchSerializer struct {
+	mockSerializer
+}
+
+func (s *batchSerializer) Serialize(frame frames.Frame) (interface{}, error) {
+	switch frame.(type) {
+	case *frames.TextFrame:
+		return []interface{}{`{"event":"start"}`, []byte{1, 2}, `{"event":"end"}`}, nil
+	case *frames.InterruptionFrame:
+		return [][]byte{{0xA}, {0xB}}, nil
+	}
+	return s.mockSerializer.Serialize(frame)
+}
+
+func TestOutputSendsMixedBatchInOrder(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: &batchSerializer{},
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewTextFrame("hi"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TextFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+
+	want := []struct {
+		msgType int
+		data    string
+	}{
+		{websocket.TextMessage, `{"event":"start"}`},
+		{websocket.BinaryMessage, "\x01\x02"},
+		{websocket.TextMessage, `{"event":"end"}`},
+		{websocket.BinaryMessage, "\x0A"},
+		{websocket.BinaryMessage, "\x0B"},
+	}
+	for i, w := range want {
+		msgType, msg := readTestMessage(t, client)
+		if msgType != w.msgType || msg != w.data {
+			t.Errorf("message %d = (%d, %q), want (%d, %q)", i, msgType, msg, w.msgType, w.data)
+		}
+	}
+}
+
+func TestSendMessageRejectsUnsupportedBatchItem(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Port: 8080, Path: "/ws", Serializer: &batchSerializer{}})
+	attachTestClient(t, transport)
+
+	if err := transport.sendMessage([]interface{}{"ok", 42}); err == nil {
+		t.Fatal("expected an error for a non-string, non-bytes batch item")
+	}
+}
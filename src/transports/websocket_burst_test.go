@@ -0,0 +1,64 @@
+package transports
+
+import (
+	"testing"
+	"time"
+)
+
+// sendTimedChunks queues n chunks on the sender and returns the time each
+// one reached the client
+func sendTimedChunks(t *testing.T, config WebSocketConfig, n int, interval time.Duration) []time.Time {
+	t.Helper()
+	config.Serializer = &mockSerializer{}
+	transport := NewWebSocketTransport(config)
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	for i := 0; i < n; i++ {
+		transport.outputProc.chunkQueue <- &audioChunk{
+			data:         []byte("audio"),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: interval,
+		}
+	}
+
+	arrivals := make([]time.Time, n)
+	for i := range arrivals {
+		readTestMessage(t, client)
+		arrivals[i] = time.Now()
+	}
+	return arrivals
+}
+
+func TestBurstChunksSentWithoutPacing(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	arrivals := sendTimedChunks(t, WebSocketConfig{BurstChunks: 3}, 6, interval)
+
+	for i := 1; i < 3; i++ {
+		if gap := arrivals[i].Sub(arrivals[0]); gap > interval/2 {
+			t.Errorf("Burst chunk %d arrived %v after the first, expected no pacing delay", i, gap)
+		}
+	}
+
+	// After the burst, pacing resumes from the first chunk's slot: chunk 3
+	// goes one interval in, keeping the client exactly 3 chunks ahead
+	for i := 3; i < 6; i++ {
+		want := time.Duration(i-2) * interval
+		if got := arrivals[i].Sub(arrivals[0]); got < want-interval/5 {
+			t.Errorf("Chunk %d arrived %v after the first, expected paced at ~%v", i, got, want)
+		}
+	}
+}
+
+func TestNoBurstByDefault(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	arrivals := sendTimedChunks(t, WebSocketConfig{}, 3, interval)
+
+	for i := 1; i < 3; i++ {
+		want := time.Duration(i) * interval
+		if got := arrivals[i].Sub(arrivals[0]); got < want-interval/5 {
+			t.Errorf("Chunk %d arrived %v after the first, expected paced at ~%v", i, got, want)
+		}
+	}
+}
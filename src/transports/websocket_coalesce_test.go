@@ -0,0 +1,89 @@
+package transports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+// passthroughSerializer sends TTS audio as raw bytes and optionally allows
+// coalescing
+type passthroughSerializer struct {
+	mockSerializer
+	coalesce bool
+}
+
+func (s *passthroughSerializer) Serialize(frame frames.Frame) (interface{}, error) {
+	if f, ok := frame.(*frames.TTSAudioFrame); ok {
+		return f.Data, nil
+	}
+	return nil, nil
+}
+
+func (s *passthroughSerializer) SupportsCoalescedAudio() bool {
+	return s.coalesce
+}
+
+// readWrites reads n messages and returns their sizes and arrival times
+func readWrites(t *testing.T, transport *WebSocketTransport, n int, data []byte) ([]int, []time.Time) {
+	t.Helper()
+	client := attachTestClient(t, transport)
+	sendTTSAudio(t, transport, data, 8000, "mulaw")
+
+	sizes := make([]int, n)
+	arrivals := make([]time.Time, n)
+	for i := range sizes {
+		_, msg := readTestMessage(t, client)
+		sizes[i] = len(msg)
+		arrivals[i] = time.Now()
+	}
+
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, extra, err := client.ReadMessage(); err == nil {
+		t.Errorf("Expected %d writes, got another of %d bytes", n, len(extra))
+	}
+	return sizes, arrivals
+}
+
+func TestFramesPerWriteCoalescesChunks(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:     &passthroughSerializer{coalesce: true},
+		FramesPerWrite: 3,
+	})
+	defer transport.outputProc.Cleanup()
+
+	// 7 chunks of 20ms mulaw become ceil(7/3) = 3 writes
+	sizes, arrivals := readWrites(t, transport, 3, make([]byte, 7*160))
+
+	total := 0
+	for i, want := range []int{480, 480, 160} {
+		if sizes[i] != want {
+			t.Errorf("Write %d carried %d bytes, want %d", i, sizes[i], want)
+		}
+		total += sizes[i]
+	}
+	if total != 7*160 {
+		t.Errorf("Expected %d bytes in total, got %d", 7*160, total)
+	}
+
+	// Each write is paced by the 60ms of audio it carries
+	if got := arrivals[2].Sub(arrivals[0]); got < 100*time.Millisecond {
+		t.Errorf("Expected coalesced writes paced ~120ms apart in total, got %v", got)
+	}
+}
+
+func TestFramesPerWriteIgnoredWithoutSerializerSupport(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:     &passthroughSerializer{},
+		FramesPerWrite: 3,
+	})
+	defer transport.outputProc.Cleanup()
+
+	sizes, _ := readWrites(t, transport, 4, make([]byte, 4*160))
+	for i, size := range sizes {
+		if size != 160 {
+			t.Errorf("Write %d carried %d bytes, want one 160-byte chunk", i, size)
+		}
+	}
+}
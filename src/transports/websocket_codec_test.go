@@ -0,0 +1,123 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+// testTone returns n samples of a 440Hz tone
+func testTone(n, sampleRate int) []int16 {
+	pcm := make([]int16, n)
+	for i := range pcm {
+		pcm[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate)))
+	}
+	return pcm
+}
+
+// sendTTSAudio pushes one TTS audio frame in codec through the output processor
+func sendTTSAudio(t *testing.T, transport *WebSocketTransport, data []byte, sampleRate int, codec string) {
+	t.Helper()
+	frame := frames.NewTTSAudioFrame(data, sampleRate, 1)
+	frame.SetMetadata("codec", codec)
+	if err := transport.outputProc.HandleFrame(context.Background(), frame, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSAudioFrame) error: %v", err)
+	}
+}
+
+// readTwilioPayload reads one Twilio media event and returns its audio
+func readTwilioPayload(t *testing.T, msg string) []byte {
+	t.Helper()
+	var event struct {
+		Event string `json:"event"`
+		Media struct {
+			Payload string `json:"payload"`
+		} `json:"media"`
+	}
+	if err := json.Unmarshal([]byte(msg), &event); err != nil {
+		t.Fatalf("Invalid Twilio message %q: %v", msg, err)
+	}
+	if event.Event != "media" {
+		t.Fatalf("Event = %q, want media", event.Event)
+	}
+	payload, err := base64.StdEncoding.DecodeString(event.Media.Payload)
+	if err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+	return payload
+}
+
+func TestOutboundAudioConvertedToTwilioMulaw(t *testing.T) {
+	tone := testTone(320, 16000)
+	tests := []struct {
+		name       string
+		data       []byte
+		sampleRate int
+		codec      string
+		want       []byte
+	}{
+		{
+			name:       "alaw",
+			data:       audio.PCMToAlaw(tone[:160]),
+			sampleRate: 8000,
+			codec:      "alaw",
+			want:       audio.PCMToMulaw(audio.AlawToPCM(audio.PCMToAlaw(tone[:160]))),
+		},
+		{
+			name:       "linear16 16kHz",
+			data:       audio.PCMToBytes(tone),
+			sampleRate: 16000,
+			codec:      "linear16",
+			want:       audio.PCMToMulaw(audio.Resample(tone, 16000, 8000)),
+		},
+		{
+			name:       "mulaw passthrough",
+			data:       audio.PCMToMulaw(tone[:160]),
+			sampleRate: 8000,
+			codec:      "mulaw",
+			want:       audio.PCMToMulaw(tone[:160]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No fade-in, so the first chunk is compared exactly
+			transport := NewWebSocketTransport(WebSocketConfig{
+				Serializer:   serializers.NewTwilioFrameSerializer("MZ123", "CA456"),
+				FadeDuration: -1,
+			})
+			defer transport.outputProc.Cleanup()
+			client := attachTestClient(t, transport)
+
+			sendTTSAudio(t, transport, tt.data, tt.sampleRate, tt.codec)
+
+			_, msg := readTestMessage(t, client)
+			if got := readTwilioPayload(t, msg); !bytes.Equal(got, tt.want) {
+				t.Errorf("Payload differs from %s converted to mulaw (%d bytes, want %d)", tt.codec, len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestOutboundAudioConvertedToAsteriskCodec(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{Codec: "alaw"})
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer, FadeDuration: -1})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	// 24kHz TTS output to an 8kHz alaw channel: 480 samples become one chunk
+	tone := testTone(480, 24000)
+	sendTTSAudio(t, transport, audio.PCMToBytes(tone), 24000, "linear16")
+
+	want := audio.PCMToAlaw(audio.Resample(tone, 24000, 8000))
+	if _, msg := readTestMessage(t, client); !bytes.Equal([]byte(msg), want) {
+		t.Errorf("Asterisk audio differs from linear16 converted to alaw (%d bytes, want %d)", len(msg), len(want))
+	}
+}
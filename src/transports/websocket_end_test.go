@@ -0,0 +1,71 @@
+package transports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// endRecorder signals when an EndFrame reaches it
+type endRecorder struct {
+	*processors.BaseProcessor
+	ended chan struct{}
+}
+
+func newEndRecorder() *endRecorder {
+	r := &endRecorder{ended: make(chan struct{}, 1)}
+	r.BaseProcessor = processors.NewBaseProcessor("EndRecorder", r)
+	return r
+}
+
+func (r *endRecorder) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.EndFrame); ok {
+		select {
+		case r.ended <- struct{}{}:
+		default:
+		}
+	}
+	return r.PushFrame(frame, direction)
+}
+
+// TestEndFramePropagatesPastOutput verifies processors placed after the
+// output (such as the assistant aggregator) receive EndFrame and the task
+// finishes once it reaches the sink.
+func TestEndFramePropagatesPastOutput(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+	recorder := newEndRecorder()
+	task := pipeline.NewPipelineTask(pipeline.NewPipeline([]processors.FrameProcessor{
+		transport.Output(),
+		recorder,
+	}))
+
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(context.Background())
+	}()
+
+	// Give the pipeline a moment to process its StartFrame
+	time.Sleep(50 * time.Millisecond)
+	if err := task.QueueFrame(frames.NewEndFrame()); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+
+	select {
+	case <-recorder.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected EndFrame to reach the processor after the output")
+	}
+
+	select {
+	case err := <-runDone:
+		if err != nil {
+			t.Errorf("Run returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the task to finish when EndFrame reaches the sink")
+	}
+}
@@ -0,0 +1,118 @@
+package transports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// constantChunk is one 20ms chunk of 8kHz linear16 at a constant level
+func constantChunk(level int16) []byte {
+	pcm := make([]int16, 160)
+	for i := range pcm {
+		pcm[i] = level
+	}
+	return audio.PCMToBytes(pcm)
+}
+
+// readBinaryPCM reads messages until a binary one and decodes it as linear16
+func readBinaryPCM(t *testing.T, client *websocket.Conn) (pcm []int16, sawText bool) {
+	t.Helper()
+	for {
+		msgType, msg := readTestMessage(t, client)
+		if msgType != websocket.BinaryMessage {
+			sawText = true
+			continue
+		}
+		pcm, err := audio.BytesToPCM([]byte(msg))
+		if err != nil {
+			t.Fatalf("Invalid PCM message: %v", err)
+		}
+		return pcm, sawText
+	}
+}
+
+func newSlinTransport() *WebSocketTransport {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{Codec: "slin"})
+	return NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+}
+
+func TestOutboundAudioFadesInFirstChunk(t *testing.T) {
+	transport := newSlinTransport()
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	sendTTSAudio(t, transport, constantChunk(8000), 8000, "linear16")
+	sendTTSAudio(t, transport, constantChunk(8000), 8000, "linear16")
+
+	// Default 5ms fade: 40 samples at 8kHz
+	first, _ := readBinaryPCM(t, client)
+	if first[0] != 0 || first[20] != 4000 {
+		t.Errorf("Fade-in samples 0 and 20 = %d and %d, want 0 and 4000", first[0], first[20])
+	}
+	for i := 40; i < len(first); i++ {
+		if first[i] != 8000 {
+			t.Fatalf("Sample %d after the fade-in = %d, want 8000", i, first[i])
+		}
+	}
+
+	second, _ := readBinaryPCM(t, client)
+	for i, v := range second {
+		if v != 8000 {
+			t.Fatalf("Second chunk sample %d = %d, want it unfaded", i, v)
+		}
+	}
+}
+
+func TestInterruptionSendsFadeOut(t *testing.T) {
+	transport := newSlinTransport()
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+	processor := transport.outputProc
+
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		sendTTSAudio(t, transport, constantChunk(8000), 8000, "linear16")
+	}
+	readBinaryPCM(t, client)
+
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+
+	// Skip chunks sent before the flush; the audio after it is the fade
+	var fade []int16
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		pcm, sawFlush := readBinaryPCM(t, client)
+		if sawFlush {
+			fade = pcm
+			break
+		}
+	}
+	if fade == nil {
+		t.Fatal("Expected audio after the flush commands")
+	}
+	if fade[0] != 7800 {
+		t.Errorf("Fade-out starts at %d, want 7800", fade[0])
+	}
+	for i := 1; i < 40; i++ {
+		if fade[i] >= fade[i-1] {
+			t.Fatalf("Fade-out not decreasing at sample %d: %d after %d", i, fade[i], fade[i-1])
+		}
+	}
+	for i := 39; i < len(fade); i++ {
+		if fade[i] != 0 {
+			t.Fatalf("Sample %d after the fade-out = %d, want silence", i, fade[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package transports
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+)
+
+func TestFlowControlPausesAndResumesSending(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	ctx := context.Background()
+	processor := transport.outputProc
+	if err := processor.HandleFrame(ctx, frames.NewFlowControlPauseFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(FlowControlPauseFrame) error: %v", err)
+	}
+	// Let the sender pick up the pause before audio is queued
+	time.Sleep(20 * time.Millisecond)
+
+	const n = 5
+	for i := 0; i < n; i++ {
+		processor.chunkQueue <- &audioChunk{
+			data:         []byte(fmt.Sprintf("chunk-%d", i)),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: time.Millisecond,
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if got := len(processor.chunkQueue); got != n {
+		t.Fatalf("Queued chunks while paused = %d, want %d (sending should halt)", got, n)
+	}
+
+	if err := processor.HandleFrame(ctx, frames.NewFlowControlResumeFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(FlowControlResumeFrame) error: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		if _, msg := readTestMessage(t, client); msg != fmt.Sprintf("chunk-%d", i) {
+			t.Fatalf("Message %d = %q, want chunk-%d", i, msg, i)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package transports
+
+import (
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+func TestChunkSizeFollowsAsteriskOptimalFrameSize(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{})
+	if _, err := serializer.Deserialize("MEDIA_START connection_id:abc channel:PJSIP/100 format:ulaw optimal_frame_size:240"); err != nil {
+		t.Fatalf("Deserialize(MEDIA_START) error: %v", err)
+	}
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+	defer transport.outputProc.Cleanup()
+
+	// 30ms ptime: 3 chunks of 240 bytes rather than 4.5 of the default 160
+	sizes, _ := readWrites(t, transport, 3, make([]byte, 3*240))
+	for i, size := range sizes {
+		if size != 240 {
+			t.Errorf("write %d: %d bytes, want 240", i, size)
+		}
+	}
+}
+
+func TestChunkSizeFallsBackToConfiguredFrameSize(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{
+		Codec:            "ulaw",
+		OptimalFrameSize: 240,
+	})
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+	defer transport.outputProc.Cleanup()
+
+	if got := transport.outputProc.chunkSizeFor("mulaw"); got != 240 {
+		t.Fatalf("chunk size before MEDIA_START = %d, want 240", got)
+	}
+	if _, err := serializer.Deserialize("MEDIA_START connection_id:abc channel:PJSIP/100 format:ulaw optimal_frame_size:160"); err != nil {
+		t.Fatalf("Deserialize(MEDIA_START) error: %v", err)
+	}
+	if got := transport.outputProc.chunkSizeFor("mulaw"); got != 160 {
+		t.Fatalf("chunk size after MEDIA_START = %d, want 160", got)
+	}
+}
+
+func TestChunkSizeDefaultsPerCodec(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializers.NewTwilioFrameSerializer("MZ1", "CA1")})
+	defer transport.outputProc.Cleanup()
+
+	if got := transport.outputProc.chunkSizeFor("mulaw"); got != 160 {
+		t.Errorf("mulaw chunk size = %d, want 160", got)
+	}
+	if got := transport.outputProc.chunkSizeFor("linear16"); got != 320 {
+		t.Errorf("linear16 chunk size = %d, want 320", got)
+	}
+}
@@ -0,0 +1,82 @@
+package transports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// contextRecorder passes frames through and reports LLMContextFrames
+// travelling upstream, as the LLM would receive them
+type contextRecorder struct {
+	*processors.BaseProcessor
+	contexts chan *services.LLMContext
+}
+
+func newContextRecorder() *contextRecorder {
+	r := &contextRecorder{contexts: make(chan *services.LLMContext, 4)}
+	r.BaseProcessor = processors.NewBaseProcessor("ContextRecorder", r)
+	return r
+}
+
+func (r *contextRecorder) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if f, ok := frame.(*frames.LLMContextFrame); ok && direction == frames.Upstream {
+		if llmCtx, ok := f.Context.(*services.LLMContext); ok {
+			r.contexts <- llmCtx
+		}
+	}
+	return r.PushFrame(frame, direction)
+}
+
+// TestFunctionCallResultReachesAssistantAggregator runs a function call
+// through the recommended llm → functionCalls → tts → output → assistant
+// topology and checks the result reaches the context and the LLM is re-run.
+func TestFunctionCallResultReachesAssistantAggregator(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+	functionCalls := processors.NewFunctionCallProcessor()
+	functionCalls.RegisterFunction("get_weather", func(ctx context.Context, params processors.FunctionCallParams) (interface{}, error) {
+		return map[string]string{"forecast": "sunny"}, nil
+	})
+	llmCtx := services.NewLLMContext("You are helpful.")
+	recorder := newContextRecorder()
+
+	task := pipeline.NewPipelineTask(pipeline.NewPipeline([]processors.FrameProcessor{
+		recorder, // Stands in for the LLM
+		functionCalls,
+		transport.Output(),
+		aggregators.NewLLMAssistantAggregator(llmCtx, nil),
+	}))
+	runDone := make(chan error, 1)
+	go func() { runDone <- task.Run(context.Background()) }()
+	defer func() {
+		task.Cancel()
+		<-runDone
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	call := frames.NewFunctionCallInProgressFrame("call_1", "get_weather", map[string]interface{}{"city": "Paris"}, false)
+	if err := task.QueueFrame(call); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+
+	var got *services.LLMContext
+	select {
+	case got = <-recorder.contexts:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the assistant aggregator to re-run the LLM with the function result")
+	}
+
+	messages := got.GetMessages(false)
+	if len(messages) != 2 {
+		t.Fatalf("Expected tool call and tool result messages, got %+v", messages)
+	}
+	if messages[1].Role != "tool" || messages[1].ToolCallID != "call_1" || messages[1].Content != `{"forecast":"sunny"}` {
+		t.Errorf("Tool message = %+v, want the function result", messages[1])
+	}
+}
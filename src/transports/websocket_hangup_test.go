@@ -0,0 +1,111 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// fakeTTS answers each TextFrame with audioFrames 20ms frames of 8kHz
+// linear16 audio
+type fakeTTS struct {
+	*processors.BaseProcessor
+	audioFrames int
+}
+
+func newFakeTTS(audioFrames int) *fakeTTS {
+	s := &fakeTTS{audioFrames: audioFrames}
+	s.BaseProcessor = processors.NewBaseProcessor("FakeTTS", s)
+	return s
+}
+
+func (s *fakeTTS) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if _, ok := frame.(*frames.TextFrame); !ok || direction != frames.Downstream {
+		return s.PushFrame(frame, direction)
+	}
+	contextID := services.GenerateContextID()
+	if err := s.PushFrame(frames.NewTTSStartedFrameWithContext(contextID), frames.Downstream); err != nil {
+		return err
+	}
+	for i := 0; i < s.audioFrames; i++ {
+		audioFrame := frames.NewTTSAudioFrame(make([]byte, 320), 8000, 1)
+		audioFrame.SetMetadata("context_id", contextID)
+		if err := s.PushFrame(audioFrame, frames.Downstream); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestHangupPlaysGoodbyeBeforeClosing verifies Hangup sends every chunk of
+// the goodbye audio before the connection is closed and the task finishes.
+func TestHangupPlaysGoodbyeBeforeClosing(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+	transport.SetDrainPad(20 * time.Millisecond)
+	client := attachTestClient(t, transport)
+
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	task := pipeline.NewPipelineTaskWithConfig(pipeline.NewPipeline([]processors.FrameProcessor{
+		transport.Input(),
+		aggregators.NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{}),
+		newFakeTTS(10),
+		transport.Output(),
+	}), &pipeline.PipelineTaskConfig{AllowInterruptions: true, HangupTimeout: 5 * time.Second})
+
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- task.Run(context.Background())
+	}()
+
+	// Give the pipeline a moment to process its StartFrame
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	if err := task.Hangup("Thanks for calling, goodbye!"); err != nil {
+		t.Fatalf("Hangup failed: %v", err)
+	}
+
+	received := 0
+	for {
+		client.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, data, err := client.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure {
+				t.Fatalf("Expected a normal close after the goodbye, got %v", err)
+			}
+			break
+		}
+		if string(data) == "audio" {
+			received++
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("Call ended by the hangup timeout after %v, not by playback completing", elapsed)
+	}
+
+	stats := transport.AudioStats()
+	if received == 0 || uint64(received) != stats.LastSeq {
+		t.Errorf("Received %d goodbye chunks before close, want all %d", received, stats.LastSeq)
+	}
+	if stats.Dropped != 0 {
+		t.Errorf("Dropped %d goodbye chunks", stats.Dropped)
+	}
+
+	select {
+	case err := <-runDone:
+		if err != nil {
+			t.Errorf("Run returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the task to finish after the hangup")
+	}
+}
@@ -0,0 +1,213 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// attachTestClient registers a server-side connection on the transport and
+// returns the client end so tests can read what the output processor sends.
+func attachTestClient(t *testing.T, transport *WebSocketTransport) *websocket.Conn {
+	t.Helper()
+
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		serverConns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	var serverConn *websocket.Conn
+	select {
+	case serverConn = <-serverConns:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for server connection")
+	}
+	t.Cleanup(func() { serverConn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	transport.connMu.Lock()
+	transport.conns["test-conn"] = &wsConnection{id: "test-conn", conn: serverConn, ctx: ctx, cancel: cancel}
+	transport.connMu.Unlock()
+
+	return client
+}
+
+func readTestMessage(t *testing.T, client *websocket.Conn) (int, string) {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msgType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	return msgType, string(data)
+}
+
+func TestInterruptionSendsTwilioClear(t *testing.T) {
+	serializer := serializers.NewTwilioFrameSerializer("MZ123", "CA456")
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: serializer,
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+
+	msgType, msg := readTestMessage(t, client)
+	if msgType != websocket.TextMessage {
+		t.Errorf("Expected TEXT message, got type %d", msgType)
+	}
+	if want := `{"event":"clear","streamSid":"MZ123"}`; msg != want {
+		t.Errorf("Expected %s, got %s", want, msg)
+	}
+}
+
+func TestInterruptionSendsAsteriskCommandsInOrder(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{ChannelID: "chan-1"})
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: serializer,
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+
+	for _, want := range []string{"REPORT_QUEUE_DRAINED", "FLUSH_MEDIA"} {
+		msgType, msg := readTestMessage(t, client)
+		if msgType != websocket.TextMessage {
+			t.Errorf("Expected TEXT message for %s, got type %d", want, msgType)
+		}
+		if msg != want {
+			t.Errorf("Expected %s, got %s", want, msg)
+		}
+	}
+}
+
+func TestConcurrentInterruptionsFlushOncePerGeneration(t *testing.T) {
+	serializer := serializers.NewTwilioFrameSerializer("MZ123", "CA456")
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: serializer,
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+
+	// Audio buffered after the first drain must survive a duplicate interruption
+	processor.mu.Lock()
+	processor.audioBuffer = append(processor.audioBuffer, make([]byte, 100)...)
+	processor.mu.Unlock()
+
+	if err := processor.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(second InterruptionFrame) error: %v", err)
+	}
+
+	if _, msg := readTestMessage(t, client); msg != `{"event":"clear","streamSid":"MZ123"}` {
+		t.Fatalf("Expected a clear command, got %s", msg)
+	}
+	client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, data, err := client.ReadMessage(); err == nil {
+		t.Errorf("Expected a single flush command, also got %s", data)
+	}
+
+	processor.mu.Lock()
+	buffered := len(processor.audioBuffer)
+	processor.mu.Unlock()
+	if buffered != 100 {
+		t.Errorf("Expected the duplicate interruption not to drain again, buffer has %d bytes", buffered)
+	}
+}
+
+func TestInterruptionFlushesAgainAfterNewUtterance(t *testing.T) {
+	serializer := &countingFlushSerializer{}
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: serializer,
+	})
+	attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+
+	for _, f := range []frames.Frame{
+		frames.NewInterruptionFrame(),
+		frames.NewInterruptionFrame(),
+		frames.NewTTSStartedFrameWithContext("ctx-2"),
+		frames.NewInterruptionFrame(),
+		frames.NewInterruptionFrame(),
+	} {
+		if err := processor.HandleFrame(ctx, f, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame(%s) error: %v", f.Name(), err)
+		}
+	}
+
+	if got := serializer.flushes.Load(); got != 2 {
+		t.Errorf("Expected one flush per utterance (2), got %d", got)
+	}
+}
+
+// countingFlushSerializer counts the flush commands serialized for
+// InterruptionFrames
+type countingFlushSerializer struct {
+	mockSerializer
+	flushes atomic.Int32
+}
+
+func (s *countingFlushSerializer) Serialize(frame frames.Frame) (interface{}, error) {
+	if _, ok := frame.(*frames.InterruptionFrame); ok {
+		s.flushes.Add(1)
+		return "flush", nil
+	}
+	return s.mockSerializer.Serialize(frame)
+}
@@ -0,0 +1,70 @@
+package transports
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// interruptionStageRecorder is a FrameObserver that records interruption stages
+type interruptionStageRecorder struct {
+	mu     sync.Mutex
+	stages []frames.InterruptionStage
+	ids    []string
+}
+
+func (r *interruptionStageRecorder) OnProcessFrame(string, frames.Frame, frames.FrameDirection) {}
+func (r *interruptionStageRecorder) OnPushFrame(string, frames.Frame, frames.FrameDirection)    {}
+
+func (r *interruptionStageRecorder) OnInterruptionStage(_, correlationID string, stage frames.InterruptionStage) {
+	r.mu.Lock()
+	r.stages = append(r.stages, stage)
+	r.ids = append(r.ids, correlationID)
+	r.mu.Unlock()
+}
+
+func TestInterruptionTraceThreadsToDrainConfirmed(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{})
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	recorder := &interruptionStageRecorder{}
+	processor := transport.outputProc
+	processor.SetObserver(recorder)
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+
+	interruption := frames.NewInterruptionFrame()
+	if err := processor.HandleFrame(ctx, interruption, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+	readTestMessage(t, client) // REPORT_QUEUE_DRAINED
+	readTestMessage(t, client) // FLUSH_MEDIA
+
+	drained, err := serializer.Deserialize("QUEUE_DRAINED")
+	if err != nil {
+		t.Fatalf("Deserialize(QUEUE_DRAINED) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, drained, frames.Upstream); err != nil {
+		t.Fatalf("HandleFrame(PlaybackCompleteFrame) error: %v", err)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	want := []frames.InterruptionStage{frames.InterruptionStageOutputDrained, frames.InterruptionStageDrainConfirmed}
+	if len(recorder.stages) != len(want) {
+		t.Fatalf("stages = %v, want %v", recorder.stages, want)
+	}
+	for i, stage := range want {
+		if recorder.stages[i] != stage || recorder.ids[i] != interruption.CorrelationID {
+			t.Errorf("stage %d = %s (%s), want %s (%s)", i, recorder.stages[i], recorder.ids[i], stage, interruption.CorrelationID)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package transports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+)
+
+// endFrameSink signals when an EndFrame reaches it
+type endFrameSink struct {
+	ended chan struct{}
+}
+
+func newEndFrameSink() *endFrameSink {
+	return &endFrameSink{ended: make(chan struct{}, 1)}
+}
+
+func (s *endFrameSink) ProcessFrame(context.Context, frames.Frame, frames.FrameDirection) error {
+	return nil
+}
+func (s *endFrameSink) QueueFrame(f frames.Frame, _ frames.FrameDirection) error {
+	if _, ok := f.(*frames.EndFrame); ok {
+		select {
+		case s.ended <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+func (s *endFrameSink) PushFrame(frames.Frame, frames.FrameDirection) error { return nil }
+func (s *endFrameSink) Link(processors.FrameProcessor)                      {}
+func (s *endFrameSink) SetPrev(processors.FrameProcessor)                   {}
+func (s *endFrameSink) Start(context.Context) error                         { return nil }
+func (s *endFrameSink) Stop() error                                         { return nil }
+func (s *endFrameSink) Name() string                                        { return "end-sink" }
+
+func TestWriteTimeoutClosesStalledConnection(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:   &mockSerializer{},
+		WriteTimeout: 100 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	url, connects, disconnects := lifecycleRecorder(t, transport)
+	sink := newEndFrameSink()
+	transport.inputProc.Link(sink)
+
+	// The client never reads, so writes stall once the socket buffers fill
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer client.Close()
+	waitForMetadata(t, connects, "OnConnect")
+
+	payload := make([]byte, 1<<20)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		select {
+		case <-disconnects:
+			select {
+			case <-sink.ended:
+			case <-time.After(2 * time.Second):
+				t.Fatal("Expected an EndFrame after the stalled connection closed")
+			}
+			return
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Stalled connection was never closed")
+		}
+
+		start := time.Now()
+		transport.sendMessage(payload)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Write blocked for %v despite a 100ms write timeout", elapsed)
+		}
+	}
+}
+
+func TestMissingPongClosesConnection(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:   &mockSerializer{},
+		PingInterval: 50 * time.Millisecond,
+		PongTimeout:  150 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	url, connects, disconnects := lifecycleRecorder(t, transport)
+	sink := newEndFrameSink()
+	transport.inputProc.Link(sink)
+
+	// Without a read loop the client never answers pings
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer client.Close()
+	waitForMetadata(t, connects, "OnConnect")
+
+	meta := waitForMetadata(t, disconnects, "OnDisconnect")
+	if duration, _ := meta["duration"].(time.Duration); duration < 150*time.Millisecond {
+		t.Errorf("Connection closed after %v, before the pong timeout", duration)
+	}
+	select {
+	case <-sink.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an EndFrame after the missing pong")
+	}
+}
+
+func TestPongKeepsConnectionOpen(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:   &mockSerializer{},
+		PingInterval: 50 * time.Millisecond,
+		PongTimeout:  150 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	url, connects, disconnects := lifecycleRecorder(t, transport)
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer client.Close()
+	waitForMetadata(t, connects, "OnConnect")
+
+	// Reading lets gorilla answer each ping with a pong
+	pings := make(chan struct{}, 16)
+	client.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return client.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-disconnects:
+		t.Fatal("Connection closed although the client answered pings")
+	case <-time.After(500 * time.Millisecond):
+	}
+	if len(pings) < 3 {
+		t.Errorf("Expected pings every 50ms, got %d in 500ms", len(pings))
+	}
+}
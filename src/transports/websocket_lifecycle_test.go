@@ -0,0 +1,106 @@
+package transports
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+// lifecycleRecorder starts a server for transport and records the metadata
+// passed to its connect and disconnect callbacks
+func lifecycleRecorder(t *testing.T, transport *WebSocketTransport) (string, chan map[string]interface{}, chan map[string]interface{}) {
+	t.Helper()
+	connects := make(chan map[string]interface{}, 1)
+	disconnects := make(chan map[string]interface{}, 1)
+	transport.OnConnect(func(meta map[string]interface{}) { connects <- meta })
+	transport.OnDisconnect(func(meta map[string]interface{}) { disconnects <- meta })
+
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http"), connects, disconnects
+}
+
+func waitForMetadata(t *testing.T, ch chan map[string]interface{}, event string) map[string]interface{} {
+	t.Helper()
+	select {
+	case meta := <-ch:
+		return meta
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s callback never fired", event)
+		return nil
+	}
+}
+
+func TestConnectionLifecycleCallbacks(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializers.NewTwilioFrameSerializer("", "")})
+	defer transport.outputProc.Cleanup()
+	url, connects, disconnects := lifecycleRecorder(t, transport)
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+
+	connected := waitForMetadata(t, connects, "OnConnect")
+	connID, _ := connected["conn_id"].(string)
+	if connID == "" {
+		t.Errorf("Expected conn_id in connect metadata, got %v", connected)
+	}
+	if addr, _ := connected["remote_addr"].(string); addr != client.LocalAddr().String() {
+		t.Errorf("remote_addr = %q, want %q", addr, client.LocalAddr().String())
+	}
+	if _, ok := connected["connected_at"].(time.Time); !ok {
+		t.Errorf("Expected connected_at in connect metadata, got %v", connected)
+	}
+
+	start := `{"event":"start","start":{"streamSid":"MZ123","callSid":"CA456","mediaFormat":{"encoding":"audio/x-mulaw","sampleRate":8000,"channels":1}}}`
+	if err := client.WriteMessage(websocket.TextMessage, []byte(start)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	client.Close()
+
+	disconnected := waitForMetadata(t, disconnects, "OnDisconnect")
+	if disconnected["conn_id"] != connID {
+		t.Errorf("Disconnect conn_id = %v, want %q", disconnected["conn_id"], connID)
+	}
+	if disconnected["streamSid"] != "MZ123" || disconnected["callSid"] != "CA456" {
+		t.Errorf("Expected call identifiers in disconnect metadata, got %v", disconnected)
+	}
+	if duration, _ := disconnected["duration"].(time.Duration); duration < 50*time.Millisecond {
+		t.Errorf("duration = %v, want at least 50ms", duration)
+	}
+	if _, ok := disconnected["disconnected_at"].(time.Time); !ok {
+		t.Errorf("Expected disconnected_at in disconnect metadata, got %v", disconnected)
+	}
+}
+
+func TestDisconnectMetadataIncludesAsteriskChannel(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{})
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+	defer transport.outputProc.Cleanup()
+	url, connects, disconnects := lifecycleRecorder(t, transport)
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	waitForMetadata(t, connects, "OnConnect")
+
+	start := "MEDIA_START connection_id:abc channel:PJSIP/alice-0001 format:ulaw optimal_frame_size:160"
+	if err := client.WriteMessage(websocket.TextMessage, []byte(start)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	client.Close()
+
+	disconnected := waitForMetadata(t, disconnects, "OnDisconnect")
+	if disconnected["channelID"] != "PJSIP/alice-0001" {
+		t.Errorf("channelID = %v, want PJSIP/alice-0001", disconnected["channelID"])
+	}
+}
@@ -0,0 +1,108 @@
+package transports
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func activeConnections(transport *WebSocketTransport) int {
+	transport.connMu.RLock()
+	defer transport.connMu.RUnlock()
+	return len(transport.conns)
+}
+
+func waitForConnections(t *testing.T, transport *WebSocketTransport, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for activeConnections(transport) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d active connections, got %d", want, activeConnections(transport))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func startLimitedServer(t *testing.T, config WebSocketConfig) (*WebSocketTransport, string) {
+	t.Helper()
+	config.Serializer = &mockSerializer{}
+	transport := NewWebSocketTransport(config)
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	t.Cleanup(server.Close)
+	return transport, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestMaxConnectionsRejectsOverflow(t *testing.T) {
+	transport, url := startLimitedServer(t, WebSocketConfig{MaxConnections: 2})
+
+	var clients []*websocket.Conn
+	for i := 0; i < 2; i++ {
+		client, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("connection %d rejected under limit: %v", i, err)
+		}
+		defer client.Close()
+		clients = append(clients, client)
+	}
+	waitForConnections(t, transport, 2)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatal("Expected connection past the limit to be rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 for overflow connection, got %v", resp)
+	}
+	if activeConnections(transport) != 2 {
+		t.Errorf("Expected rejected connection not to be tracked, got %d", activeConnections(transport))
+	}
+
+	// Closing a connection frees its slot
+	clients[0].Close()
+	waitForConnections(t, transport, 1)
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Expected connection to be accepted after a slot freed: %v", err)
+	}
+	client.Close()
+}
+
+func TestAtCapacity(t *testing.T) {
+	transport, url := startLimitedServer(t, WebSocketConfig{MaxConnections: 1})
+	if transport.AtCapacity() {
+		t.Fatal("Expected capacity with no connections")
+	}
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("first connection rejected: %v", err)
+	}
+	waitForConnections(t, transport, 1)
+	if !transport.AtCapacity() {
+		t.Error("Expected to be at capacity once the limit is reached")
+	}
+
+	client.Close()
+	waitForConnections(t, transport, 0)
+	if transport.AtCapacity() {
+		t.Error("Expected capacity once the connection closed")
+	}
+}
+
+func TestMaxConnectionsUnlimitedByDefault(t *testing.T) {
+	transport, url := startLimitedServer(t, WebSocketConfig{})
+
+	for i := 0; i < 5; i++ {
+		client, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("connection %d rejected without a limit: %v", i, err)
+		}
+		defer client.Close()
+	}
+	waitForConnections(t, transport, 5)
+}
@@ -0,0 +1,102 @@
+package transports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/services/openai"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+	"github.com/square-key-labs/strawgo-ai/src/turns/user_start"
+	"github.com/square-key-labs/strawgo-ai/src/turns/user_stop"
+)
+
+// responseWatcher passes frames through, counting InterruptionFrames and
+// reporting each response end travelling downstream
+type responseWatcher struct {
+	*processors.BaseProcessor
+	interruptions atomic.Int32
+	ends          chan struct{}
+}
+
+func newResponseWatcher() *responseWatcher {
+	w := &responseWatcher{ends: make(chan struct{}, 4)}
+	w.BaseProcessor = processors.NewBaseProcessor("ResponseWatcher", w)
+	return w
+}
+
+func (w *responseWatcher) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	switch frame.(type) {
+	case *frames.InterruptionFrame:
+		w.interruptions.Add(1)
+	case *frames.LLMFullResponseEndFrame:
+		if direction == frames.Downstream {
+			w.ends <- struct{}{}
+		}
+	}
+	return w.PushFrame(frame, direction)
+}
+
+// TestUnspokenResponseEndsGeneration runs an empty LLM reply through a real
+// user → llm → output pipeline and checks the user speaking afterwards is not
+// taken for a barge-in on a response still being generated.
+func TestUnspokenResponseEndsGeneration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	llmCtx := services.NewLLMContext("You are helpful.")
+	llmCtx.AddUserMessage("Hello")
+	user := aggregators.NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{
+		StartStrategies: []user_start.UserTurnStartStrategy{user_start.NewVADUserTurnStartStrategy(true)},
+		StopStrategies:  []user_stop.UserTurnStopStrategy{user_stop.NewSpeechTimeoutUserTurnStopStrategy(100*time.Millisecond, true)},
+	})
+	llm := openai.NewLLMService(openai.LLMConfig{APIKey: "test-key", BaseURL: server.URL})
+	watcher := newResponseWatcher()
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: &mockSerializer{}})
+
+	task := pipeline.NewPipelineTask(pipeline.NewPipeline([]processors.FrameProcessor{
+		user,
+		llm,
+		watcher,
+		transport.Output(),
+	}))
+	runDone := make(chan error, 1)
+	go func() { runDone <- task.Run(context.Background()) }()
+	defer func() {
+		task.Cancel()
+		<-runDone
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := user.PushContextFrame(frames.Downstream); err != nil {
+		t.Fatalf("PushContextFrame failed: %v", err)
+	}
+	select {
+	case <-watcher.ends:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the LLM to end its response")
+	}
+
+	// Let the upstream end reach the user aggregator, then the user speaks
+	time.Sleep(50 * time.Millisecond)
+	if err := task.QueueFrame(frames.NewUserStartedSpeakingFrame()); err != nil {
+		t.Fatalf("QueueFrame failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if n := watcher.interruptions.Load(); n != 0 {
+		t.Errorf("Expected no interruption once the empty reply ended, got %d", n)
+	}
+}
@@ -0,0 +1,92 @@
+package transports
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+func TestWebSocketTransportSetsLogContext(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer: serializers.NewTwilioFrameSerializer("", ""),
+	})
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	waitForLogContext(t, transport, "conn", "ws-")
+
+	start := `{"event":"start","start":{"streamSid":"MZ456","callSid":"CA123"}}`
+	if err := client.WriteMessage(websocket.TextMessage, []byte(start)); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	waitForLogContext(t, transport, "callSid", "CA123")
+	fields := transport.outputProc.LogContext()
+	if fields["streamSid"] != "MZ456" || !strings.HasPrefix(fields["conn"], "ws-") {
+		t.Errorf("Expected stream and connection IDs in output log context, got %v", fields)
+	}
+}
+
+func waitForLogContext(t *testing.T, transport *WebSocketTransport, key, prefix string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.HasPrefix(transport.inputProc.LogContext()[key], prefix) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s=%s* in log context, got %v", key, prefix, transport.inputProc.LogContext())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketTransportPopulatesStartFrameMedia(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer: serializers.NewTwilioFrameSerializer("", ""),
+	})
+	capture := &queuedFrameCapture{}
+	transport.inputProc.Link(capture)
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	start := `{"event":"start","start":{"streamSid":"MZ456","callSid":"CA123",` +
+		`"mediaFormat":{"encoding":"audio/x-mulaw","sampleRate":8000,"channels":1},` +
+		`"customParameters":{"locale":"es-MX"}}}`
+	if err := client.WriteMessage(websocket.TextMessage, []byte(start)); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+	if !capture.waitForFrame("StartFrame", 2*time.Second) {
+		t.Fatal("Expected StartFrame to be pushed downstream")
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	for _, frame := range capture.frames {
+		startFrame, ok := frame.(*frames.StartFrame)
+		if !ok {
+			continue
+		}
+		if startFrame.Codec != "mulaw" || startFrame.SampleRate != 8000 || startFrame.Channels != 1 {
+			t.Errorf("Expected mulaw 8kHz mono, got %s %dHz x%d", startFrame.Codec, startFrame.SampleRate, startFrame.Channels)
+		}
+		if startFrame.CallID != "CA123" || startFrame.Locale != "es-MX" {
+			t.Errorf("Expected call CA123 in es-MX, got %q in %q", startFrame.CallID, startFrame.Locale)
+		}
+	}
+}
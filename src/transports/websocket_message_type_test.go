@@ -0,0 +1,68 @@
+package transports
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+// routeInbound converts and deserializes one inbound message as the read
+// loop does
+func routeInbound(t *testing.T, transport *WebSocketTransport, msgType int, msg []byte) frames.Frame {
+	t.Helper()
+	frame, err := transport.serializer.Deserialize(transport.inboundData(msgType, msg))
+	if err != nil {
+		t.Fatalf("Deserialize error: %v", err)
+	}
+	return frame
+}
+
+func TestJSONSerializerReadsEitherFrameType(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializers.NewJSONFrameSerializer(serializers.JSONSerializerConfig{})})
+	defer transport.outputProc.Cleanup()
+
+	pcm := []byte{1, 2, 3, 4}
+	msg := []byte(`{"type":"audio","audio":"` + base64.StdEncoding.EncodeToString(pcm) + `"}`)
+
+	// Browsers may send the JSON in either frame type
+	for _, msgType := range []int{websocket.TextMessage, websocket.BinaryMessage} {
+		audioFrame, ok := routeInbound(t, transport, msgType, msg).(*frames.AudioFrame)
+		if !ok {
+			t.Fatalf("message type %d: expected an AudioFrame", msgType)
+		}
+		if string(audioFrame.Data) != string(pcm) {
+			t.Errorf("message type %d: audio = %v, want the decoded payload %v", msgType, audioFrame.Data, pcm)
+		}
+	}
+}
+
+func TestAsteriskSerializerRoutesMixedMessages(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{})
+	transport := NewWebSocketTransport(WebSocketConfig{Serializer: serializer})
+	defer transport.outputProc.Cleanup()
+
+	// TEXT is control: MEDIA_START is consumed and sets the codec
+	if frame := routeInbound(t, transport, websocket.TextMessage, []byte("MEDIA_START connection_id:abc channel:PJSIP/100 format:ulaw optimal_frame_size:160")); frame != nil {
+		t.Fatalf("expected MEDIA_START to be consumed, got %s", frame.Name())
+	}
+	if codec := serializer.GetCodec(); codec != "mulaw" {
+		t.Errorf("codec after MEDIA_START = %q, want mulaw", codec)
+	}
+
+	// BINARY is audio, even when the bytes happen to spell a command
+	audioFrame, ok := routeInbound(t, transport, websocket.BinaryMessage, []byte("HANGUP")).(*frames.AudioFrame)
+	if !ok {
+		t.Fatal("expected a BINARY message to be routed as audio")
+	}
+	if audioFrame.Metadata()["codec"] != "mulaw" || string(audioFrame.Data) != "HANGUP" {
+		t.Errorf("unexpected audio frame: codec %v, data %q", audioFrame.Metadata()["codec"], audioFrame.Data)
+	}
+
+	// The same bytes as TEXT are a hangup
+	if _, ok := routeInbound(t, transport, websocket.TextMessage, []byte("HANGUP")).(*frames.EndFrame); !ok {
+		t.Error("expected a TEXT HANGUP to end the call")
+	}
+}
@@ -0,0 +1,82 @@
+package transports
+
+import (
+	"testing"
+	"time"
+)
+
+// TestQueuedAudioCapDropsOldestWhenStalled floods a paused client's queue
+// past MaxQueuedAudio and verifies the queue stays bounded, the oldest audio
+// is dropped and the TTS service is only held up for one QueueFullTimeout.
+func TestQueuedAudioCapDropsOldestWhenStalled(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:         &mockSerializer{},
+		FadeDuration:       -1,
+		MaxQueuedAudio:     100 * time.Millisecond,
+		QueueFullTimeout:   50 * time.Millisecond,
+		PausedBufferChunks: 1000,
+	})
+	defer transport.outputProc.Cleanup()
+	processor := transport.outputProc
+	processor.setFlowPaused(true)
+	time.Sleep(20 * time.Millisecond) // Let the sender see the pause
+
+	// 50 x 20ms of 8kHz linear16
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		sendTTSAudio(t, transport, make([]byte, 320), 8000, "linear16")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Flooding took %v, want a single QueueFullTimeout wait", elapsed)
+	}
+
+	if queued := len(processor.chunkQueue); queued != 5 {
+		t.Errorf("Queued %d chunks, want 5 (100ms of 20ms chunks)", queued)
+	}
+	if dropped := transport.AudioStats().OverflowDropped; dropped != 45 {
+		t.Errorf("OverflowDropped = %d, want 45", dropped)
+	}
+
+	// The newest audio is what remains
+	if oldest := <-processor.chunkQueue; oldest.seq != 46 {
+		t.Errorf("Oldest queued chunk seq = %d, want 46", oldest.seq)
+	}
+}
+
+// TestQueuedAudioCapAppliesBackpressure verifies a TTS burst larger than
+// MaxQueuedAudio is held back while a live client drains it, with nothing
+// dropped.
+func TestQueuedAudioCapAppliesBackpressure(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:     &mockSerializer{},
+		FadeDuration:   -1,
+		MaxQueuedAudio: 100 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	// 20 x 20ms arrives at once; at most 100ms may sit in the queue, so the
+	// burst is released at the client's real-time pace
+	const n = 20
+	sent := make(chan time.Duration, 1)
+	go func() {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			sendTTSAudio(t, transport, make([]byte, 320), 8000, "linear16")
+			if queued := time.Duration(transport.outputProc.queuedNanos.Load()); queued > 120*time.Millisecond {
+				t.Errorf("Queued %v of audio, want at most 100ms", queued)
+			}
+		}
+		sent <- time.Since(start)
+	}()
+
+	for i := 0; i < n; i++ {
+		readTestMessage(t, client)
+	}
+	if elapsed := <-sent; elapsed < 200*time.Millisecond {
+		t.Errorf("Burst queued in %v, want it held back by the cap", elapsed)
+	}
+	if stats := transport.AudioStats(); stats.OverflowDropped != 0 || stats.Sent != n {
+		t.Errorf("Sent = %d, OverflowDropped = %d, want %d and 0", stats.Sent, stats.OverflowDropped, n)
+	}
+}
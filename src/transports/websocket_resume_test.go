@@ -0,0 +1,203 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/pipeline"
+	"github.com/square-key-labs/strawgo-ai/src/processors"
+	"github.com/square-key-labs/strawgo-ai/src/processors/aggregators"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+// messagesRecorder passes frames through and reports the message contents of
+// each LLMContextFrame travelling downstream, copied as the frame passes so
+// the test never reads the context the pipeline writes
+type messagesRecorder struct {
+	*processors.BaseProcessor
+	contexts chan []string
+}
+
+func newMessagesRecorder() *messagesRecorder {
+	r := &messagesRecorder{contexts: make(chan []string, 4)}
+	r.BaseProcessor = processors.NewBaseProcessor("MessagesRecorder", r)
+	return r
+}
+
+func (r *messagesRecorder) HandleFrame(ctx context.Context, frame frames.Frame, direction frames.FrameDirection) error {
+	if f, ok := frame.(*frames.LLMContextFrame); ok && direction == frames.Downstream {
+		if llmCtx, ok := f.Context.(*services.LLMContext); ok {
+			var contents []string
+			for _, msg := range llmCtx.Messages {
+				contents = append(contents, msg.Content)
+			}
+			r.contexts <- contents
+		}
+	}
+	return r.PushFrame(frame, direction)
+}
+
+// resumeSession runs a pipeline over a transport with the given resume window
+// and returns a function asking the LLM context for the conversation, a dial
+// function presenting a session token, the OnConnect metadata of each
+// connection and the end recorder
+func resumeSession(t *testing.T, window time.Duration) (func(content string) []string, func(token string) *websocket.Conn, chan map[string]interface{}, *endRecorder) {
+	t.Helper()
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:          &mockSerializer{},
+		SessionResumeWindow: window,
+	})
+	connected := make(chan map[string]interface{}, 4)
+	transport.OnConnect(func(meta map[string]interface{}) {
+		connected <- meta
+	})
+
+	llmCtx := &services.LLMContext{Messages: []services.LLMMessage{}}
+	messages := newMessagesRecorder()
+	recorder := newEndRecorder()
+	task := pipeline.NewPipelineTask(pipeline.NewPipeline([]processors.FrameProcessor{
+		transport.Input(),
+		aggregators.NewLLMUserAggregator(llmCtx, turns.UserTurnStrategies{}),
+		messages,
+		transport.Output(),
+		recorder,
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go task.Run(ctx)
+
+	server := httptest.NewServer(http.HandlerFunc(transport.handleWebSocket))
+	t.Cleanup(server.Close)
+
+	dial := func(token string) *websocket.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?session_token=" + token
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	// ask appends a user message, runs the LLM and returns the context it got
+	ask := func(content string) []string {
+		t.Helper()
+		if err := task.QueueFrame(frames.NewLLMMessagesAppendFrame([]services.LLMMessage{
+			{Role: "user", Content: content},
+		}, true)); err != nil {
+			t.Fatalf("QueueFrame failed: %v", err)
+		}
+		select {
+		case contents := <-messages.contexts:
+			return contents
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected an LLMContextFrame")
+			return nil
+		}
+	}
+
+	// Give the pipeline a moment to process its StartFrame
+	time.Sleep(50 * time.Millisecond)
+	ask("My order number is 42")
+	return ask, dial, connected, recorder
+}
+
+func waitConnected(t *testing.T, connected chan map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	select {
+	case meta := <-connected:
+		return meta
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected OnConnect to fire")
+		return nil
+	}
+}
+
+// TestReconnectWithinWindowResumesSession verifies a connection presenting
+// the dropped session's token within the window keeps the same pipeline and
+// LLM context instead of ending the session.
+func TestReconnectWithinWindowResumesSession(t *testing.T) {
+	ask, dial, connected, recorder := resumeSession(t, time.Second)
+
+	first := dial("abc")
+	if meta := waitConnected(t, connected); meta["resumed"] != false {
+		t.Fatalf("First connection resumed = %v, want false", meta["resumed"])
+	}
+
+	// Drop the connection without a close handshake
+	first.UnderlyingConn().Close()
+	time.Sleep(100 * time.Millisecond)
+
+	dial("abc")
+	if meta := waitConnected(t, connected); meta["resumed"] != true {
+		t.Fatalf("Reconnect resumed = %v, want true", meta["resumed"])
+	}
+
+	// The session outlives the window once resumed
+	select {
+	case <-recorder.ended:
+		t.Fatal("Resumed session was ended")
+	case <-time.After(1200 * time.Millisecond):
+	}
+	if got := ask("What is its status?"); len(got) != 2 || got[0] != "My order number is 42" {
+		t.Errorf("LLM context messages = %q, want the message from before the drop kept", got)
+	}
+}
+
+// TestReconnectAfterWindowStartsNewSession verifies a dropped session is
+// reset once the window expires, and a later connection starts a new
+// conversation on the same pipeline.
+func TestReconnectAfterWindowStartsNewSession(t *testing.T) {
+	ask, dial, connected, recorder := resumeSession(t, 100*time.Millisecond)
+
+	first := dial("abc")
+	waitConnected(t, connected)
+	first.UnderlyingConn().Close()
+	time.Sleep(300 * time.Millisecond)
+
+	dial("abc")
+	if meta := waitConnected(t, connected); meta["resumed"] != false {
+		t.Errorf("Reconnect after expiry resumed = %v, want false", meta["resumed"])
+	}
+	if got := ask("Hello"); len(got) != 1 || got[0] != "Hello" {
+		t.Errorf("LLM context messages = %q, want only the new session's", got)
+	}
+	select {
+	case <-recorder.ended:
+		t.Error("Expected the pipeline to keep running for the new session")
+	default:
+	}
+}
+
+// TestOtherConnectionCancelsHeldSession verifies a connection with another
+// token starts fresh at once, and the dropped session's window expiring later
+// leaves the new session alone.
+func TestOtherConnectionCancelsHeldSession(t *testing.T) {
+	ask, dial, connected, _ := resumeSession(t, 200*time.Millisecond)
+
+	first := dial("abc")
+	waitConnected(t, connected)
+	first.UnderlyingConn().Close()
+	time.Sleep(50 * time.Millisecond)
+
+	dial("xyz")
+	if meta := waitConnected(t, connected); meta["resumed"] != false {
+		t.Fatalf("Connection with another token resumed = %v, want false", meta["resumed"])
+	}
+	if got := ask("Hello"); len(got) != 1 || got[0] != "Hello" {
+		t.Fatalf("LLM context messages = %q, want only the new session's", got)
+	}
+
+	// Past the dropped session's window
+	time.Sleep(300 * time.Millisecond)
+	if got := ask("Still there?"); len(got) != 2 || got[0] != "Hello" {
+		t.Errorf("LLM context messages = %q, want the new session kept", got)
+	}
+}
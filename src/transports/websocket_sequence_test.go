@@ -0,0 +1,106 @@
+package transports
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+)
+
+func TestOutboundAudioSequenceIncrements(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer: serializers.NewTwilioFrameSerializer("MZ123", "CA456"),
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	// One 20ms mulaw chunk per frame
+	const n = 4
+	for i := 0; i < n; i++ {
+		sendTTSAudio(t, transport, audio.PCMToMulaw(testTone(160, 8000)), 8000, "mulaw")
+	}
+	for i := 0; i < n; i++ {
+		readTestMessage(t, client)
+	}
+
+	stats := transport.AudioStats()
+	if stats.Sent != n {
+		t.Errorf("Sent = %d, want %d", stats.Sent, n)
+	}
+	if stats.LastSeq != n {
+		t.Errorf("LastSeq = %d, want %d", stats.LastSeq, n)
+	}
+	if stats.Dropped != 0 || stats.Gaps != 0 {
+		t.Errorf("Dropped = %d, Gaps = %d, want 0 and 0", stats.Dropped, stats.Gaps)
+	}
+}
+
+func TestOutboundAudioSequenceGapCounted(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:  &mockSerializer{},
+		MaxChunkAge: 100 * time.Millisecond,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+	processor := transport.outputProc
+	capture := &queuedFrameCapture{}
+	processor.SetPrev(capture)
+
+	send := func(seq uint64, enqueuedAt time.Time) {
+		processor.chunkQueue <- &audioChunk{
+			data:         []byte(fmt.Sprintf("chunk-%d", seq)),
+			chunkSize:    160,
+			sampleRate:   8000,
+			sendInterval: 20 * time.Millisecond,
+			enqueuedAt:   enqueuedAt,
+			seq:          seq,
+		}
+	}
+
+	send(1, time.Now())
+	if _, msg := readTestMessage(t, client); msg != "chunk-1" {
+		t.Fatalf("Message = %q, want chunk-1", msg)
+	}
+
+	// After an idle spell longer than MaxChunkAge, chunk 2's playout slot is
+	// long past and the sender drops it
+	time.Sleep(200 * time.Millisecond)
+	send(2, time.Now().Add(-time.Second))
+	send(3, time.Now())
+	if _, msg := readTestMessage(t, client); msg != "chunk-3" {
+		t.Fatalf("Message = %q, want chunk-3", msg)
+	}
+
+	stats := transport.AudioStats()
+	if stats.Sent != 2 {
+		t.Errorf("Sent = %d, want 2", stats.Sent)
+	}
+	if stats.Dropped != 1 || stats.Gaps != 1 {
+		t.Errorf("Dropped = %d, Gaps = %d, want 1 and 1", stats.Dropped, stats.Gaps)
+	}
+	if stats.LateDropped != 1 {
+		t.Errorf("LateDropped = %d, want 1", stats.LateDropped)
+	}
+	if stats.LastSeq != 3 {
+		t.Errorf("LastSeq = %d, want 3", stats.LastSeq)
+	}
+
+	// The gap is reported to the pipeline, not just counted
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	var reports []*frames.AudioSendStatsFrame
+	for _, f := range capture.frames {
+		if report, ok := f.(*frames.AudioSendStatsFrame); ok {
+			reports = append(reports, report)
+		}
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expected one AudioSendStatsFrame for the gap, got %d", len(reports))
+	}
+	if r := reports[0]; r.Skipped != 1 || r.Dropped != 1 || r.Gaps != 1 || r.LateDropped != 1 || r.LastSeq != 3 || r.Sent != 2 {
+		t.Errorf("unexpected stats report %+v", *r)
+	}
+}
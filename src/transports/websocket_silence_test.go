@@ -0,0 +1,110 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/audio"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/services"
+)
+
+// TestEmptyAndSilentTTSAudioDropped verifies empty and silent chunks never
+// reach the queue, so the audio around them is paced back to back
+func TestEmptyAndSilentTTSAudioDropped(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:       &mockSerializer{},
+		FadeDuration:     -1,
+		SilenceThreshold: 0.001,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	// 20ms of 8kHz linear16 each
+	tone := audio.PCMToBytes(testTone(160, 8000))
+	sendTTSAudio(t, transport, tone, 8000, "linear16")
+	sendTTSAudio(t, transport, nil, 8000, "linear16")
+	sendTTSAudio(t, transport, make([]byte, 320), 8000, "linear16")
+	sendTTSAudio(t, transport, bytes.Repeat([]byte{0xFF}, 160), 8000, "mulaw") // Mulaw silence
+	sendTTSAudio(t, transport, tone, 8000, "linear16")
+
+	readTestMessage(t, client)
+	first := time.Now()
+	readTestMessage(t, client)
+	if gap := time.Since(first); gap > 35*time.Millisecond {
+		t.Errorf("Second tone sent %v after the first, want one 20ms slot", gap)
+	}
+
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, msg, err := client.ReadMessage(); err == nil {
+		t.Errorf("Expected only the two tones sent, got %q", msg)
+	}
+	if stats := transport.AudioStats(); stats.Sent != 2 || stats.SilenceDropped != 2 {
+		t.Errorf("Sent = %d, SilenceDropped = %d, want 2 and 2", stats.Sent, stats.SilenceDropped)
+	}
+}
+
+// TestSilentTTSAudioKeptByDefault verifies silence is only dropped when a
+// SilenceThreshold is configured; empty frames always are
+func TestSilentTTSAudioKeptByDefault(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:   &mockSerializer{},
+		FadeDuration: -1,
+	})
+	defer transport.outputProc.Cleanup()
+	client := attachTestClient(t, transport)
+
+	sendTTSAudio(t, transport, nil, 8000, "linear16")
+	sendTTSAudio(t, transport, make([]byte, 320), 8000, "linear16")
+	readTestMessage(t, client)
+
+	if stats := transport.AudioStats(); stats.Sent != 1 || stats.SilenceDropped != 0 {
+		t.Errorf("Sent = %d, SilenceDropped = %d, want the silent chunk sent", stats.Sent, stats.SilenceDropped)
+	}
+}
+
+// TestSilentTTSAudioDoesNotHoldOffBotStopped verifies silent chunks trailing
+// a response don't keep resetting the VAD-stop timer
+func TestSilentTTSAudioDoesNotHoldOffBotStopped(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Serializer:       &mockSerializer{},
+		FadeDuration:     -1,
+		SilenceThreshold: 0.001,
+	})
+	processor := transport.outputProc
+	defer processor.Cleanup()
+	capture := &queuedFrameCapture{}
+	processor.SetPrev(capture)
+
+	ctx := context.Background()
+	contextID := services.GenerateContextID()
+	if err := processor.HandleFrame(ctx, frames.NewTTSStartedFrameWithContext(contextID), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TTSStartedFrame) error: %v", err)
+	}
+	sendChunk := func(data []byte) {
+		frame := frames.NewTTSAudioFrame(data, 8000, 1)
+		frame.SetMetadata("context_id", contextID)
+		if err := processor.HandleFrame(ctx, frame, frames.Downstream); err != nil {
+			t.Fatalf("HandleFrame(TTSAudioFrame) error: %v", err)
+		}
+	}
+	sendChunk(audio.PCMToBytes(testTone(160, 8000)))
+	if !capture.waitForFrame("BotStartedSpeakingFrame", time.Second) {
+		t.Fatal("timed out waiting for BotStartedSpeakingFrame")
+	}
+	if err := processor.HandleFrame(ctx, frames.NewLLMFullResponseEndFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(LLMFullResponseEndFrame) error: %v", err)
+	}
+
+	// Trailing silence for longer than the VAD-stop window
+	start := time.Now()
+	for time.Since(start) < time.Second && capture.count("BotStoppedSpeakingFrame") == 0 {
+		sendChunk(make([]byte, 320))
+		time.Sleep(50 * time.Millisecond)
+	}
+	if capture.count("BotStoppedSpeakingFrame") == 0 {
+		t.Fatal("BotStoppedSpeakingFrame held off by trailing silence")
+	}
+}
@@ -330,3 +330,30 @@ func TestMultipleInterruptions(t *testing.T) {
 		}
 	}
 }
+
+func TestStartFrameMetadataSetsUpSerializer(t *testing.T) {
+	// Stream SID supplied by the application, e.g. from the webhook that
+	// opened the stream, rather than learned from Twilio's start event
+	serializer := serializers.NewTwilioFrameSerializer("", "")
+	transport := NewWebSocketTransport(WebSocketConfig{Port: 8080, Path: "/ws", Serializer: serializer})
+	client := attachTestClient(t, transport)
+
+	start := frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{})
+	start.SetMetadata("streamSid", "MZ999")
+	start.SetMetadata("callSid", "CA999")
+
+	ctx := context.Background()
+	if err := transport.outputProc.HandleFrame(ctx, start, frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if serializer.GetCallSid() != "CA999" {
+		t.Errorf("expected callSid CA999, got %q", serializer.GetCallSid())
+	}
+
+	if err := transport.outputProc.HandleFrame(ctx, frames.NewInterruptionFrame(), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(InterruptionFrame) error: %v", err)
+	}
+	if _, msg := readTestMessage(t, client); msg != `{"event":"clear","streamSid":"MZ999"}` {
+		t.Errorf("expected clear event keyed to MZ999, got %s", msg)
+	}
+}
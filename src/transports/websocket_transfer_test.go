@@ -0,0 +1,172 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/square-key-labs/strawgo-ai/src/frames"
+	"github.com/square-key-labs/strawgo-ai/src/serializers"
+	"github.com/square-key-labs/strawgo-ai/src/turns"
+)
+
+func TestTransferRedirectsTwilioCall(t *testing.T) {
+	type transferRequest struct {
+		path, user, password string
+		form                 url.Values
+	}
+	requests := make(chan transferRequest, 1)
+	release := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+		user, password, _ := r.BasicAuth()
+		requests <- transferRequest{path: r.URL.Path, user: user, password: password, form: r.PostForm}
+		<-release
+		w.Write([]byte(`{"sid":"CA456"}`))
+	}))
+	defer api.Close()
+	defer close(release)
+
+	serializer := serializers.NewTwilioFrameSerializer("", "")
+	if _, err := serializer.Deserialize(`{"event":"start","start":{"streamSid":"MZ123","callSid":"CA456","accountSid":"AC789"}}`); err != nil {
+		t.Fatalf("Deserialize(start) error: %v", err)
+	}
+
+	transport := NewWebSocketTransport(WebSocketConfig{Port: 8080, Path: "/ws", Serializer: serializer})
+	transport.OnTransfer(NewTwilioTransferHandler(TwilioTransferConfig{AuthToken: "secret", APIBaseURL: api.URL}))
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+
+	// The REST call is still in flight when HandleFrame returns
+	if err := processor.HandleFrame(ctx, frames.NewTransferFrame("+15551234567"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TransferFrame) error: %v", err)
+	}
+	if _, msg := readTestMessage(t, client); msg != `{"event":"clear","streamSid":"MZ123"}` {
+		t.Errorf("expected clear event for the transfer, got %s", msg)
+	}
+
+	select {
+	case req := <-requests:
+		if req.path != "/2010-04-01/Accounts/AC789/Calls/CA456.json" {
+			t.Errorf("unexpected transfer endpoint %s", req.path)
+		}
+		if req.user != "AC789" || req.password != "secret" {
+			t.Errorf("unexpected credentials %s:%s", req.user, req.password)
+		}
+		if want := "<Response><Dial>+15551234567</Dial></Response>"; req.form.Get("Twiml") != want {
+			t.Errorf("expected TwiML %s, got %s", want, req.form.Get("Twiml"))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a call update request")
+	}
+}
+
+func TestTransferFailureReportedUpstream(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{Port: 8080, Path: "/ws", Serializer: &mockSerializer{}})
+	transport.OnTransfer(func(ctx context.Context, destination string, meta map[string]interface{}) error {
+		return errors.New("agent queue closed")
+	})
+	attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	capture := &queuedFrameCapture{}
+	processor.SetPrev(capture)
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewTransferFrame("+15551234567"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TransferFrame) error: %v", err)
+	}
+
+	if !capture.waitForFrame("ErrorFrame", 2*time.Second) {
+		t.Fatal("expected the failed transfer reported as an ErrorFrame")
+	}
+}
+
+func TestTransferHandsAsteriskChannelToApplication(t *testing.T) {
+	serializer := serializers.NewAsteriskFrameSerializer(serializers.AsteriskSerializerConfig{ChannelID: "chan-1"})
+	transport := NewWebSocketTransport(WebSocketConfig{Port: 8080, Path: "/ws", Serializer: serializer})
+	type transfer struct {
+		destination string
+		meta        map[string]interface{}
+	}
+	transfers := make(chan transfer, 1)
+	transport.OnTransfer(func(ctx context.Context, destination string, meta map[string]interface{}) error {
+		transfers <- transfer{destination, meta}
+		return nil
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewTransferFrame("sip:agent@pbx.example.com"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TransferFrame) error: %v", err)
+	}
+
+	msgType, msg := readTestMessage(t, client)
+	if msgType != websocket.TextMessage || msg != "FLUSH_MEDIA" {
+		t.Errorf("Expected TEXT FLUSH_MEDIA, got type %d %s", msgType, msg)
+	}
+	select {
+	case got := <-transfers:
+		if got.destination != "sip:agent@pbx.example.com" || got.meta["channelID"] != "chan-1" {
+			t.Errorf("Unexpected transfer %s with meta %v", got.destination, got.meta)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the transfer handed to the application")
+	}
+
+	// chan_websocket has no transfer command
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, msg, err := client.ReadMessage(); err == nil {
+		t.Errorf("expected nothing after FLUSH_MEDIA, got %s", msg)
+	}
+}
+
+func TestTransferIgnoredWithoutHandler(t *testing.T) {
+	transport := NewWebSocketTransport(WebSocketConfig{
+		Port:       8080,
+		Path:       "/ws",
+		Serializer: serializers.NewJSONFrameSerializer(serializers.JSONSerializerConfig{}),
+	})
+	client := attachTestClient(t, transport)
+
+	processor := transport.outputProc
+	ctx := context.Background()
+	if err := processor.HandleFrame(ctx, frames.NewStartFrameWithConfig(true, turns.UserTurnStrategies{}), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(StartFrame) error: %v", err)
+	}
+	if err := processor.HandleFrame(ctx, frames.NewTransferFrame("+15551234567"), frames.Downstream); err != nil {
+		t.Fatalf("HandleFrame(TransferFrame) error: %v", err)
+	}
+
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, msg, err := client.ReadMessage(); err == nil {
+		t.Errorf("expected no transfer signaling, got %s", msg)
+	}
+}
+
+func TestTwilioTransferTwiMLDialsSIPURIs(t *testing.T) {
+	if got, want := transferTwiML("sip:agent@pbx.example.com"), "<Response><Dial><Sip>sip:agent@pbx.example.com</Sip></Dial></Response>"; got != want {
+		t.Fatalf("transferTwiML(sip) = %s, want %s", got, want)
+	}
+	if got, want := transferTwiML("+1555<0>"), "<Response><Dial>+1555&lt;0&gt;</Dial></Response>"; got != want {
+		t.Fatalf("transferTwiML(escaped) = %s, want %s", got, want)
+	}
+}
@@ -159,12 +159,18 @@ func EnsureWorkerBinary(version string) (string, error) {
 	}
 
 	// Write to a temp file then atomically rename — avoids a corrupt binary
-	// if the process is interrupted mid-download.
-	tmpPath := cachePath + ".tmp"
-	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	// if the process is interrupted mid-download. The temp name is unique so
+	// concurrent processes don't write into the same file.
+	f, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".*.tmp")
 	if err != nil {
 		return "", fmt.Errorf("create temp file for onnx-worker: %w", err)
 	}
+	tmpPath := f.Name()
+	if err := f.Chmod(0755); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("create temp file for onnx-worker: %w", err)
+	}
 
 	written, err := io.Copy(f, resp.Body)
 	f.Close()
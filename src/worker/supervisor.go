@@ -5,7 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -13,17 +16,38 @@ import (
 	"github.com/square-key-labs/strawgo-ai/src/models"
 )
 
+// Execution providers accepted in Config.ExecutionProvider. CoreML is built
+// into the macOS release binaries; CUDA needs a worker built with
+// `cargo build --release --features cuda`. A provider the worker can't
+// register falls back to CPU.
+const (
+	ExecutionProviderCPU    = "cpu"
+	ExecutionProviderCoreML = "coreml"
+	ExecutionProviderCUDA   = "cuda"
+)
+
+// Config tunes the ONNX Runtime used by the worker for Silero VAD and Smart
+// Turn inference. The zero value runs on one CPU thread.
+type Config struct {
+	NumThreads        int    // Intra-op threads per session (default: 1)
+	ExecutionProvider string // "cpu", "coreml" or "cuda" (default: cpu)
+}
+
+// sockSeq distinguishes the sockets of supervisors started by one process
+var sockSeq atomic.Uint64
+
 // Supervisor manages the lifecycle of the onnx-worker subprocess.
 type Supervisor struct {
 	binaryPath    string
 	vadModelPath  string
 	turnModelPath string
 	sockPath      string
+	args          []string // Flags the worker is (re)launched with
 	cmd           *exec.Cmd
 	mu            sync.Mutex
 	stopCh        chan struct{} // closed on Stop()
 	stoppedCh     chan struct{} // closed when WatchAndRestart exits
-	watchOnce     sync.Once   // ensures WatchAndRestart goroutine runs at most once
+	watchOnce     sync.Once     // ensures WatchAndRestart goroutine runs at most once
 }
 
 // Start starts the onnx-worker process and waits for it to be ready.
@@ -41,6 +65,13 @@ type Supervisor struct {
 // downloaded automatically to ~/.cache/strawgo/models/ if not already present,
 // regardless of how the binary was resolved.
 func Start(binaryPath string) (*Supervisor, error) {
+	return StartWithConfig(binaryPath, Config{})
+}
+
+// StartWithConfig is like Start but launches the worker with the given ONNX
+// Runtime settings. An unknown ExecutionProvider is logged and ignored,
+// falling back to CPU.
+func StartWithConfig(binaryPath string, config Config) (*Supervisor, error) {
 	// 1. Resolve binary — auto-download if not supplied and not in PATH/cache
 	if binaryPath == "" {
 		var err error
@@ -60,17 +91,22 @@ func Start(binaryPath string) (*Supervisor, error) {
 		return nil, fmt.Errorf("ensure turn model: %w", err)
 	}
 
-	// 3. Build socket path
-	sockPath := filepath.Join(os.TempDir(), fmt.Sprintf("onnx-worker-%d.sock", os.Getpid()))
+	// 3. Build socket path, unique per supervisor within this process
+	sockPath := filepath.Join(os.TempDir(),
+		fmt.Sprintf("onnx-worker-%d-%d.sock", os.Getpid(), sockSeq.Add(1)))
 
 	// 4. Launch the process
-	cmd := exec.Command(binaryPath,
-		"--vad-model", vadModel,
-		"--turn-model", turnModel,
-		"--socket", sockPath,
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	s := &Supervisor{
+		binaryPath:    binaryPath,
+		vadModelPath:  vadModel,
+		turnModelPath: turnModel,
+		sockPath:      sockPath,
+		stopCh:        make(chan struct{}),
+		stoppedCh:     make(chan struct{}),
+	}
+	s.args = runtimeOptions(config)
+
+	cmd := s.command()
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("start onnx-worker: %w", err)
 	}
@@ -90,15 +126,7 @@ func Start(binaryPath string) (*Supervisor, error) {
 	}
 
 	// 6. Return initialised Supervisor
-	s := &Supervisor{
-		binaryPath:    binaryPath,
-		vadModelPath:  vadModel,
-		turnModelPath: turnModel,
-		sockPath:      sockPath,
-		cmd:           cmd,
-		stopCh:        make(chan struct{}),
-		stoppedCh:     make(chan struct{}),
-	}
+	s.cmd = cmd
 
 	// Auto-start the crash-recovery watcher so Stop() can safely wait on stoppedCh.
 	go s.WatchAndRestart()
@@ -106,6 +134,35 @@ func Start(binaryPath string) (*Supervisor, error) {
 	return s, nil
 }
 
+// runtimeOptions converts config to worker flags
+func runtimeOptions(config Config) (args []string) {
+	if config.NumThreads > 0 {
+		args = append(args, "--intra-threads", strconv.Itoa(config.NumThreads))
+	}
+
+	switch provider := strings.ToLower(config.ExecutionProvider); provider {
+	case "", ExecutionProviderCPU:
+	case ExecutionProviderCoreML, ExecutionProviderCUDA:
+		args = append(args, "--execution-provider", provider)
+	default:
+		logger.Warn("[Worker] Unknown execution provider %q, using cpu", config.ExecutionProvider)
+	}
+	return args
+}
+
+// command builds the worker process from the supervisor's settings
+func (s *Supervisor) command() *exec.Cmd {
+	args := append([]string{
+		"--vad-model", s.vadModelPath,
+		"--turn-model", s.turnModelPath,
+		"--socket", s.sockPath,
+	}, s.args...)
+	cmd := exec.Command(s.binaryPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
 // SocketPath returns the Unix socket path that clients should connect to.
 func (s *Supervisor) SocketPath() string {
 	return s.sockPath
@@ -182,13 +239,7 @@ func (s *Supervisor) restart() error {
 	// Clean up stale socket so the readiness check starts fresh
 	os.Remove(s.sockPath)
 
-	cmd := exec.Command(s.binaryPath,
-		"--vad-model", s.vadModelPath,
-		"--turn-model", s.turnModelPath,
-		"--socket", s.sockPath,
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := s.command()
 	if err := cmd.Start(); err != nil {
 		return err
 	}
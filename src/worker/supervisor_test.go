@@ -1,10 +1,14 @@
 package worker
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/square-key-labs/strawgo-ai/src/models"
 )
 
 // localBinaryPath returns the path to the locally-compiled onnx-worker binary
@@ -111,3 +115,118 @@ func TestSupervisor_CrashRecovery(t *testing.T) {
 		t.Errorf("socket did not reappear after crash within 8s: %v", err)
 	}
 }
+
+// fakeWorker writes a stand-in onnx-worker script that records its arguments
+// in the returned directory, creates the socket file, then
+// idles. Model files are planted in a temp HOME so Start never downloads.
+func fakeWorker(t *testing.T) (bin, recordDir string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	modelDir := filepath.Join(home, ".cache", "strawgo", "models")
+	if err := os.MkdirAll(modelDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{models.SileroVADFile, models.SmartTurnFile} {
+		if err := os.WriteFile(filepath.Join(modelDir, name), []byte("model"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recordDir = t.TempDir()
+	bin = filepath.Join(recordDir, "onnx-worker")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$*" > %[1]s/args.tmp && mv %[1]s/args.tmp %[1]s/args
+while [ $# -gt 0 ]; do
+	[ "$1" = "--socket" ] && sock="$2"
+	shift
+done
+touch "$sock"
+exec sleep 30
+`, recordDir)
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return bin, recordDir
+}
+
+// readRecord returns a file written by the fake worker
+func readRecord(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("fake worker did not record %s: %v", name, err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// TestSupervisor_ConfigOverrides verifies that runtime settings reach the
+// worker and survive a crash restart.
+func TestSupervisor_ConfigOverrides(t *testing.T) {
+	bin, record := fakeWorker(t)
+
+	s, err := StartWithConfig(bin, Config{NumThreads: 4, ExecutionProvider: "CUDA"})
+	if err != nil {
+		t.Fatalf("StartWithConfig(): %v", err)
+	}
+	defer s.Stop()
+
+	args := readRecord(t, record, "args")
+	for _, want := range []string{"--intra-threads 4", "--execution-provider cuda", "--socket " + s.SocketPath()} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected worker args to contain %q, got %q", want, args)
+		}
+	}
+
+	// A restarted worker gets the same settings
+	os.Remove(filepath.Join(record, "args"))
+	s.KillWorkerForTesting()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(filepath.Join(record, "args")); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if restarted := readRecord(t, record, "args"); restarted != args {
+		t.Errorf("expected restart to reuse args %q, got %q", args, restarted)
+	}
+}
+
+// TestSupervisor_ConfigFallback verifies that an unknown execution provider
+// falls back to CPU.
+func TestSupervisor_ConfigFallback(t *testing.T) {
+	bin, record := fakeWorker(t)
+
+	s, err := StartWithConfig(bin, Config{ExecutionProvider: "tpu"})
+	if err != nil {
+		t.Fatalf("StartWithConfig(): %v", err)
+	}
+	defer s.Stop()
+
+	args := readRecord(t, record, "args")
+	if strings.Contains(args, "--execution-provider") || strings.Contains(args, "--intra-threads") {
+		t.Errorf("expected default runtime flags, got %q", args)
+	}
+}
+
+// TestSupervisor_UniqueSocketPaths verifies that supervisors in one process
+// don't share a socket.
+func TestSupervisor_UniqueSocketPaths(t *testing.T) {
+	bin, _ := fakeWorker(t)
+
+	a, err := Start(bin)
+	if err != nil {
+		t.Fatalf("Start() a: %v", err)
+	}
+	defer a.Stop()
+	b, err := Start(bin)
+	if err != nil {
+		t.Fatalf("Start() b: %v", err)
+	}
+	defer b.Stop()
+
+	if a.SocketPath() == b.SocketPath() {
+		t.Errorf("expected distinct socket paths, both got %s", a.SocketPath())
+	}
+}